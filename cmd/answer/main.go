@@ -7,13 +7,16 @@ import (
 	"github.com/answerdev/answer/internal/base/conf"
 	"github.com/answerdev/answer/internal/base/constant"
 	"github.com/answerdev/answer/internal/base/cron"
+	answerServer "github.com/answerdev/answer/internal/base/server"
 	"github.com/answerdev/answer/internal/cli"
 	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman"
 	"github.com/segmentfault/pacman/contrib/log/zap"
 	"github.com/segmentfault/pacman/contrib/server/http"
 	"github.com/segmentfault/pacman/log"
+	pacmanServer "github.com/segmentfault/pacman/server"
 )
 
 // go build -ldflags "-X main.Version=x.y.z"
@@ -48,7 +51,7 @@ func runApp() {
 	}
 	conf.GetPathIgnoreList()
 	app, cleanup, err := initApplication(
-		c.Debug, c.Server, c.Data.Database, c.Data.Cache, c.I18n, c.Swaggerui, c.ServiceConfig, log.GetLogger())
+		c.Debug, c.Server, c.Data.Database, c.Data.Cache, c.Data.Search, c.Data.Queue, c.I18n, c.Swaggerui, c.ServiceConfig, log.GetLogger())
 	if err != nil {
 		panic(err)
 	}
@@ -61,11 +64,16 @@ func runApp() {
 	}
 }
 
-func newApplication(serverConf *conf.Server, server *gin.Engine, manager *cron.ScheduledTaskManager) *pacman.Application {
+func newApplication(serverConf *conf.Server, server *gin.Engine, manager *cron.ScheduledTaskManager,
+	mailReplyService *service.MailReplyService) *pacman.Application {
 	manager.Run()
+	servers := []pacmanServer.Server{http.NewServer(server, serverConf.HTTP.Addr)}
+	if serverConf.SMTP != nil && len(serverConf.SMTP.Addr) > 0 {
+		servers = append(servers, answerServer.NewSMTPServer(serverConf.SMTP, mailReplyService))
+	}
 	return pacman.NewApp(
 		pacman.WithName(Name),
 		pacman.WithVersion(Version),
-		pacman.WithServer(http.NewServer(server, serverConf.HTTP.Addr)),
+		pacman.WithServer(servers...),
 	)
 }
@@ -30,11 +30,14 @@ func initApplication(
 	serverConf *conf.Server,
 	dbConf *data.Database,
 	cacheConf *data.CacheConf,
+	searchConf *data.SearchConf,
+	queueConf *data.QueueConf,
 	i18nConf *translator.I18n,
 	swaggerConf *router.SwaggerConfig,
 	serviceConf *service_config.ServiceConfig,
 	logConf log.Logger) (*pacman.Application, func(), error) {
 	panic(wire.Build(
+		wire.FieldsOf(new(*conf.Server), "HTTP"),
 		server.ProviderSetServer,
 		router.ProviderSetRouter,
 		controller.ProviderSetController,
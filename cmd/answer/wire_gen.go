@@ -16,49 +16,73 @@ import (
 	"github.com/answerdev/answer/internal/controller"
 	"github.com/answerdev/answer/internal/controller/template_render"
 	"github.com/answerdev/answer/internal/controller_admin"
+	"github.com/answerdev/answer/internal/repo/access_token"
 	"github.com/answerdev/answer/internal/repo/activity"
 	"github.com/answerdev/answer/internal/repo/activity_common"
 	"github.com/answerdev/answer/internal/repo/answer"
+	"github.com/answerdev/answer/internal/repo/audit_log"
 	"github.com/answerdev/answer/internal/repo/auth"
+	"github.com/answerdev/answer/internal/repo/badge"
+	"github.com/answerdev/answer/internal/repo/bounty"
 	"github.com/answerdev/answer/internal/repo/captcha"
 	"github.com/answerdev/answer/internal/repo/collection"
 	"github.com/answerdev/answer/internal/repo/comment"
 	"github.com/answerdev/answer/internal/repo/common"
 	"github.com/answerdev/answer/internal/repo/config"
+	"github.com/answerdev/answer/internal/repo/email_digest"
 	"github.com/answerdev/answer/internal/repo/export"
+	"github.com/answerdev/answer/internal/repo/job"
 	"github.com/answerdev/answer/internal/repo/meta"
 	"github.com/answerdev/answer/internal/repo/notification"
+	"github.com/answerdev/answer/internal/repo/pending_post"
+	"github.com/answerdev/answer/internal/repo/post_lock"
 	"github.com/answerdev/answer/internal/repo/question"
+	"github.com/answerdev/answer/internal/repo/question_duplicate"
 	"github.com/answerdev/answer/internal/repo/rank"
 	"github.com/answerdev/answer/internal/repo/reason"
 	"github.com/answerdev/answer/internal/repo/report"
 	"github.com/answerdev/answer/internal/repo/revision"
 	"github.com/answerdev/answer/internal/repo/role"
 	"github.com/answerdev/answer/internal/repo/search_common"
+	"github.com/answerdev/answer/internal/repo/serial_vote"
 	"github.com/answerdev/answer/internal/repo/site_info"
+	"github.com/answerdev/answer/internal/repo/spam"
 	"github.com/answerdev/answer/internal/repo/tag"
 	"github.com/answerdev/answer/internal/repo/tag_common"
+	"github.com/answerdev/answer/internal/repo/two_factor"
 	"github.com/answerdev/answer/internal/repo/unique"
 	"github.com/answerdev/answer/internal/repo/user"
+	"github.com/answerdev/answer/internal/repo/user_external_login"
+	"github.com/answerdev/answer/internal/repo/webhook"
 	"github.com/answerdev/answer/internal/router"
 	"github.com/answerdev/answer/internal/service"
+	access_token2 "github.com/answerdev/answer/internal/service/access_token"
 	"github.com/answerdev/answer/internal/service/action"
 	activity2 "github.com/answerdev/answer/internal/service/activity"
 	activity_common2 "github.com/answerdev/answer/internal/service/activity_common"
 	"github.com/answerdev/answer/internal/service/answer_common"
+	audit_log2 "github.com/answerdev/answer/internal/service/audit_log"
 	auth2 "github.com/answerdev/answer/internal/service/auth"
+	badge2 "github.com/answerdev/answer/internal/service/badge"
+	bounty2 "github.com/answerdev/answer/internal/service/bounty"
 	"github.com/answerdev/answer/internal/service/collection_common"
 	comment2 "github.com/answerdev/answer/internal/service/comment"
 	"github.com/answerdev/answer/internal/service/comment_common"
 	"github.com/answerdev/answer/internal/service/dashboard"
+	email_digest2 "github.com/answerdev/answer/internal/service/email_digest"
 	export2 "github.com/answerdev/answer/internal/service/export"
 	"github.com/answerdev/answer/internal/service/follow"
+	"github.com/answerdev/answer/internal/service/job_queue"
 	meta2 "github.com/answerdev/answer/internal/service/meta"
 	notification2 "github.com/answerdev/answer/internal/service/notification"
 	"github.com/answerdev/answer/internal/service/notification_common"
 	"github.com/answerdev/answer/internal/service/object_info"
+	post_lock2 "github.com/answerdev/answer/internal/service/post_lock"
+	"github.com/answerdev/answer/internal/service/pre_moderation"
 	"github.com/answerdev/answer/internal/service/question_common"
+	question_duplicate2 "github.com/answerdev/answer/internal/service/question_duplicate"
 	rank2 "github.com/answerdev/answer/internal/service/rank"
+	"github.com/answerdev/answer/internal/service/rate_limit"
 	reason2 "github.com/answerdev/answer/internal/service/reason"
 	report2 "github.com/answerdev/answer/internal/service/report"
 	"github.com/answerdev/answer/internal/service/report_admin"
@@ -66,14 +90,21 @@ import (
 	"github.com/answerdev/answer/internal/service/revision_common"
 	role2 "github.com/answerdev/answer/internal/service/role"
 	"github.com/answerdev/answer/internal/service/search_parser"
+	"github.com/answerdev/answer/internal/service/search_queue"
+	serial_vote2 "github.com/answerdev/answer/internal/service/serial_vote"
 	"github.com/answerdev/answer/internal/service/service_config"
 	"github.com/answerdev/answer/internal/service/siteinfo"
 	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	spam2 "github.com/answerdev/answer/internal/service/spam"
+	"github.com/answerdev/answer/internal/service/stream"
 	tag2 "github.com/answerdev/answer/internal/service/tag"
 	tag_common2 "github.com/answerdev/answer/internal/service/tag_common"
+	two_factor2 "github.com/answerdev/answer/internal/service/two_factor"
 	"github.com/answerdev/answer/internal/service/uploader"
 	"github.com/answerdev/answer/internal/service/user_admin"
 	"github.com/answerdev/answer/internal/service/user_common"
+	user_external_login2 "github.com/answerdev/answer/internal/service/user_external_login"
+	webhook2 "github.com/answerdev/answer/internal/service/webhook"
 	"github.com/segmentfault/pacman"
 	"github.com/segmentfault/pacman/log"
 )
@@ -81,7 +112,7 @@ import (
 // Injectors from wire.go:
 
 // initApplication init application.
-func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database, cacheConf *data.CacheConf, i18nConf *translator.I18n, swaggerConf *router.SwaggerConfig, serviceConf *service_config.ServiceConfig, logConf log.Logger) (*pacman.Application, func(), error) {
+func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database, cacheConf *data.CacheConf, searchConf *data.SearchConf, queueConf *data.QueueConf, i18nConf *translator.I18n, swaggerConf *router.SwaggerConfig, serviceConf *service_config.ServiceConfig, logConf log.Logger) (*pacman.Application, func(), error) {
 	staticRouter := router.NewStaticRouter(serviceConf)
 	i18nTranslator, err := translator.NewTranslator(i18nConf)
 	if err != nil {
@@ -115,38 +146,65 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	emailService := export2.NewEmailService(configRepo, emailRepo, siteInfoRepo)
 	userRoleRelRepo := role.NewUserRoleRelRepo(dataData)
 	roleRepo := role.NewRoleRepo(dataData)
-	roleService := role2.NewRoleService(roleRepo)
-	userRoleRelService := role2.NewUserRoleRelService(userRoleRelRepo, roleService)
 	userCommon := usercommon.NewUserCommon(userRepo)
-	userService := service.NewUserService(userRepo, userActiveActivityRepo, activityRepo, emailService, authService, serviceConf, siteInfoCommonService, userRoleRelService, userCommon)
-	captchaRepo := captcha.NewCaptchaRepo(dataData)
-	captchaService := action.NewCaptchaService(captchaRepo)
-	uploaderService := uploader.NewUploaderService(serviceConf, siteInfoCommonService)
-	userController := controller.NewUserController(authService, userService, captchaService, emailService, uploaderService, siteInfoCommonService)
-	commentRepo := comment.NewCommentRepo(dataData, uniqueIDRepo)
-	commentCommonRepo := comment.NewCommentCommonRepo(dataData, uniqueIDRepo)
-	answerRepo := answer.NewAnswerRepo(dataData, uniqueIDRepo, userRankRepo, activityRepo)
-	questionRepo := question.NewQuestionRepo(dataData, uniqueIDRepo)
+	auditLogRepo := audit_log.NewAuditLogRepo(dataData)
+	auditLogService := audit_log2.NewAuditLogService(auditLogRepo, userCommon)
+	rolePowerRelRepo := role.NewRolePowerRelRepo(dataData)
+	roleService := role2.NewRoleService(roleRepo, userRoleRelRepo, rolePowerRelRepo, auditLogService)
+	userRoleRelService := role2.NewUserRoleRelService(userRoleRelRepo, roleService)
+	userExternalLoginRepo := user_external_login.NewUserExternalLoginRepo(dataData)
 	tagCommonRepo := tag_common.NewTagCommonRepo(dataData, uniqueIDRepo)
+	powerRepo := role.NewPowerRepo(dataData)
+	rolePowerRelService := role2.NewRolePowerRelService(rolePowerRelRepo, powerRepo, roleRepo, tagCommonRepo, userRoleRelService, auditLogService)
+	twoFactorRepo := two_factor.NewTwoFactorRepo(dataData)
+	twoFactorService := two_factor2.NewTwoFactorService(twoFactorRepo, userRepo, userRoleRelService, rolePowerRelService, siteInfoCommonService, auditLogService)
+	userExternalLoginService := user_external_login2.NewUserExternalLoginService(userRepo, userExternalLoginRepo, userCommon, userActiveActivityRepo, authService, userRoleRelService, siteInfoCommonService, twoFactorService)
+	badgeRepo := badge.NewBadgeRepo(dataData)
+	questionRepo := question.NewQuestionRepo(dataData, uniqueIDRepo)
+	answerRepo := answer.NewAnswerRepo(dataData, uniqueIDRepo, userRankRepo, activityRepo)
 	tagRelRepo := tag.NewTagRelRepo(dataData)
+	jobRepo := job.NewJobRepo(dataData)
+	queue, cleanup3, err := job_queue.NewJobQueue(queueConf, jobRepo)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	searchQueueService := search_queue.NewSearchQueueService(queue, searchConf)
+	badgeService := badge2.NewBadgeService(badgeRepo, userRepo, userCommon, questionRepo, answerRepo, tagRelRepo, queue, auditLogService)
+	commentRepo := comment.NewCommentRepo(dataData, uniqueIDRepo)
+	commentCommonRepo := comment.NewCommentCommonRepo(dataData, uniqueIDRepo)
 	tagRepo := tag.NewTagRepo(dataData, uniqueIDRepo)
 	revisionRepo := revision.NewRevisionRepo(dataData, uniqueIDRepo)
 	revisionService := revision_common.NewRevisionService(revisionRepo, userRepo)
 	tagCommonService := tag_common2.NewTagCommonService(tagCommonRepo, tagRelRepo, tagRepo, revisionService, siteInfoCommonService)
 	objService := object_info.NewObjService(answerRepo, questionRepo, commentCommonRepo, tagCommonRepo, tagCommonService)
 	voteRepo := activity_common.NewVoteRepo(dataData, activityRepo)
-	commentService := comment2.NewCommentService(commentRepo, commentCommonRepo, userCommon, objService, voteRepo, emailService, userRepo)
-	rolePowerRelRepo := role.NewRolePowerRelRepo(dataData)
-	rolePowerRelService := role2.NewRolePowerRelService(rolePowerRelRepo, userRoleRelService)
+	emailDigestRepo := email_digest.NewEmailDigestRepo(dataData)
+	followRepo := activity_common.NewFollowRepo(dataData, uniqueIDRepo, activityRepo)
+	emailDigestService := email_digest2.NewEmailDigestService(emailDigestRepo, userRepo, followRepo, emailService)
+	pendingPostRepo := pending_post.NewPendingPostRepo(dataData)
+	preModerationService := pre_moderation.NewPreModerationService(pendingPostRepo, siteInfoCommonService, userRoleRelService, rolePowerRelService, userRepo, commentCommonRepo)
 	rankService := rank2.NewRankService(userCommon, userRankRepo, objService, userRoleRelService, rolePowerRelService, configRepo)
+	spamRepo := spam.NewSpamRepo(dataData)
+	spamService := spam2.NewSpamService(spamRepo, siteInfoCommonService, objService, userCommon, rankService)
+	postLockRepo := post_lock.NewPostLockRepo(dataData)
+	metaRepo := meta.NewMetaRepo(dataData)
+	metaService := meta2.NewMetaService(metaRepo)
+	postLockService := post_lock2.NewPostLockService(postLockRepo, questionRepo, answerRepo, objService, rankService, auditLogService)
+	userService := service.NewUserService(userRepo, userActiveActivityRepo, activityRepo, emailService, authService, serviceConf, siteInfoCommonService, userRoleRelService, userCommon, userExternalLoginService, twoFactorService, badgeService, spamService)
+	captchaRepo := captcha.NewCaptchaRepo(dataData)
+	captchaService := action.NewCaptchaService(captchaRepo)
+	uploaderService := uploader.NewUploaderService(serviceConf, siteInfoCommonService)
+	userController := controller.NewUserController(authService, userService, captchaService, emailService, uploaderService, siteInfoCommonService)
+	commentService := comment2.NewCommentService(commentRepo, commentCommonRepo, userCommon, objService, voteRepo, emailService, userRepo, emailDigestService, preModerationService, spamService, postLockService)
 	commentController := controller.NewCommentController(commentService, rankService)
 	reportRepo := report.NewReportRepo(dataData, uniqueIDRepo)
 	reportService := report2.NewReportService(reportRepo, objService)
 	reportController := controller.NewReportController(reportService, rankService)
 	serviceVoteRepo := activity.NewVoteRepo(dataData, uniqueIDRepo, configRepo, activityRepo, userRankRepo, voteRepo)
-	voteService := service.NewVoteService(serviceVoteRepo, uniqueIDRepo, configRepo, questionRepo, answerRepo, commentCommonRepo, objService)
+	voteService := service.NewVoteService(serviceVoteRepo, uniqueIDRepo, configRepo, questionRepo, answerRepo, commentCommonRepo, objService, postLockService, searchQueueService)
 	voteController := controller.NewVoteController(voteService, rankService)
-	followRepo := activity_common.NewFollowRepo(dataData, uniqueIDRepo, activityRepo)
 	tagService := tag2.NewTagService(tagRepo, tagCommonService, revisionService, followRepo, siteInfoCommonService)
 	tagController := controller.NewTagController(tagService, tagCommonService, rankService)
 	followFollowRepo := activity.NewFollowRepo(dataData, uniqueIDRepo, activityRepo)
@@ -156,64 +214,105 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	collectionGroupRepo := collection.NewCollectionGroupRepo(dataData)
 	collectionCommon := collectioncommon.NewCollectionCommon(collectionRepo)
 	answerCommon := answercommon.NewAnswerCommon(answerRepo)
-	metaRepo := meta.NewMetaRepo(dataData)
-	metaService := meta2.NewMetaService(metaRepo)
-	questionCommon := questioncommon.NewQuestionCommon(questionRepo, answerRepo, voteRepo, followRepo, tagCommonService, userCommon, collectionCommon, answerCommon, metaService, configRepo)
+	questionCommon := questioncommon.NewQuestionCommon(questionRepo, answerRepo, voteRepo, followRepo, tagCommonService, userCommon, collectionCommon, answerCommon, metaService, configRepo, searchQueueService)
 	collectionService := service.NewCollectionService(collectionRepo, collectionGroupRepo, questionCommon)
 	collectionController := controller.NewCollectionController(collectionService)
 	answerActivityRepo := activity.NewAnswerActivityRepo(dataData, activityRepo, userRankRepo)
 	questionActivityRepo := activity.NewQuestionActivityRepo(dataData, activityRepo, userRankRepo)
 	answerActivityService := activity2.NewAnswerActivityService(answerActivityRepo, questionActivityRepo)
-	questionService := service.NewQuestionService(questionRepo, tagCommonService, questionCommon, userCommon, revisionService, metaService, collectionCommon, answerActivityService, dataData)
+	questionDuplicateRepo := question_duplicate.NewQuestionDuplicateRepo(dataData)
+	bountyRepo := bounty.NewBountyRepo(dataData, activityRepo, userRankRepo)
+	bountyService := bounty2.NewBountyService(bountyRepo, questionRepo, answerRepo, userCommon)
+	questionDuplicateService := question_duplicate2.NewQuestionDuplicateService(questionDuplicateRepo, questionRepo, answerRepo, followFollowRepo, followRepo, tagCommonService, userCommon, configRepo, auditLogService, bountyService, postLockService, searchQueueService)
+	questionService := service.NewQuestionService(questionRepo, tagCommonService, questionCommon, userCommon, revisionService, metaService, collectionCommon, answerActivityService, dataData, auditLogService, preModerationService, spamService, questionDuplicateService, postLockService, bountyService, searchQueueService)
 	questionController := controller.NewQuestionController(questionService, rankService)
-	answerService := service.NewAnswerService(answerRepo, questionRepo, questionCommon, userCommon, collectionCommon, userRepo, revisionService, answerActivityService, answerCommon, voteRepo, emailService)
+	answerService := service.NewAnswerService(answerRepo, questionRepo, questionCommon, userCommon, collectionCommon, userRepo, revisionService, answerActivityService, answerCommon, voteRepo, emailService, emailDigestService, auditLogService, preModerationService, spamService, postLockService, searchQueueService)
 	dashboardService := dashboard.NewDashboardService(questionRepo, answerRepo, commentCommonRepo, voteRepo, userRepo, reportRepo, configRepo, siteInfoCommonService, serviceConf, dataData)
 	answerController := controller.NewAnswerController(answerService, rankService, dashboardService)
 	searchParser := search_parser.NewSearchParser(tagCommonService, userCommon)
-	searchRepo := search_common.NewSearchRepo(dataData, uniqueIDRepo, userCommon)
-	searchService := service.NewSearchService(searchParser, searchRepo)
+	searchRepo, cleanup4, err := search_common.NewSearchRepo(dataData, uniqueIDRepo, userCommon, searchConf)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	searchService := service.NewSearchService(searchParser, searchRepo, queue, searchConf, dataData)
 	searchController := controller.NewSearchController(searchService)
-	serviceRevisionService := service.NewRevisionService(revisionRepo, userCommon, questionCommon, answerService, objService, questionRepo, answerRepo, tagRepo, tagCommonService)
+	serviceRevisionService := service.NewRevisionService(revisionRepo, userCommon, questionCommon, answerService, objService, questionRepo, answerRepo, tagRepo, tagCommonService, auditLogService, postLockService, searchQueueService)
 	revisionController := controller.NewRevisionController(serviceRevisionService, rankService)
+	pendingPostService := service.NewPendingPostService(preModerationService, questionService, answerService, commentService, questionRepo, answerRepo, commentCommonRepo, objService, userCommon, auditLogService, spamService, rankService)
+	pendingPostController := controller.NewPendingPostController(pendingPostService, rankService)
 	rankController := controller.NewRankController(rankService)
 	commonRepo := common.NewCommonRepo(dataData, uniqueIDRepo)
 	reportHandle := report_handle_admin.NewReportHandle(questionCommon, commentRepo, configRepo)
-	reportAdminService := report_admin.NewReportAdminService(reportRepo, userCommon, commonRepo, answerRepo, questionRepo, commentCommonRepo, reportHandle, configRepo)
+	reportAdminService := report_admin.NewReportAdminService(reportRepo, userCommon, commonRepo, answerRepo, questionRepo, commentCommonRepo, reportHandle, configRepo, auditLogService, spamService)
 	controller_adminReportController := controller_admin.NewReportController(reportAdminService)
 	userAdminRepo := user.NewUserAdminRepo(dataData, authRepo)
-	userAdminService := user_admin.NewUserAdminService(userAdminRepo, userRoleRelService, authService, userCommon)
-	userAdminController := controller_admin.NewUserAdminController(userAdminService)
+	userAdminService := user_admin.NewUserAdminService(userAdminRepo, userRoleRelService, authService, userCommon, auditLogService)
+	userAdminController := controller_admin.NewUserAdminController(userAdminService, twoFactorService)
 	reasonRepo := reason.NewReasonRepo(configRepo)
 	reasonService := reason2.NewReasonService(reasonRepo)
 	reasonController := controller.NewReasonController(reasonService)
 	themeController := controller_admin.NewThemeController()
-	siteInfoService := siteinfo.NewSiteInfoService(siteInfoRepo, siteInfoCommonService, emailService, tagCommonService)
+	siteInfoService := siteinfo.NewSiteInfoService(siteInfoRepo, siteInfoCommonService, emailService, tagCommonService, configRepo, auditLogService)
 	siteInfoController := controller_admin.NewSiteInfoController(siteInfoService)
 	siteinfoController := controller.NewSiteinfoController(siteInfoCommonService)
 	notificationRepo := notification.NewNotificationRepo(dataData)
-	notificationCommon := notificationcommon.NewNotificationCommon(dataData, notificationRepo, userCommon, activityRepo, followRepo, objService)
-	notificationService := notification2.NewNotificationService(dataData, notificationRepo, notificationCommon, revisionService)
-	notificationController := controller.NewNotificationController(notificationService, rankService)
+	notificationCommon := notificationcommon.NewNotificationCommon(dataData, notificationRepo, userCommon, activityRepo, followRepo, objService, queue)
+	notificationService := notification2.NewNotificationService(dataData, notificationRepo, notificationCommon, revisionService, preModerationService)
+	streamService := stream.NewStreamService(dataData)
+	notificationController := controller.NewNotificationController(notificationService, rankService, streamService)
 	dashboardController := controller.NewDashboardController(dashboardService)
 	uploadController := controller.NewUploadController(uploaderService)
-	activityCommon := activity_common2.NewActivityCommon(activityRepo)
+	activityCommon := activity_common2.NewActivityCommon(activityRepo, queue)
 	activityActivityRepo := activity.NewActivityRepo(dataData)
 	commentCommonService := comment_common.NewCommentCommonService(commentCommonRepo)
 	activityService := activity2.NewActivityService(activityActivityRepo, userCommon, activityCommon, tagCommonService, objService, commentCommonService, revisionService, metaService)
 	activityController := controller.NewActivityController(activityCommon, activityService)
-	roleController := controller_admin.NewRoleController(roleService)
-	answerAPIRouter := router.NewAnswerAPIRouter(langController, userController, commentController, reportController, voteController, tagController, followController, collectionController, questionController, answerController, searchController, revisionController, rankController, controller_adminReportController, userAdminController, reasonController, themeController, siteInfoController, siteinfoController, notificationController, dashboardController, uploadController, activityController, roleController)
+	roleController := controller_admin.NewRoleController(roleService, rolePowerRelService)
+	accessTokenRepo := access_token.NewAccessTokenRepo(dataData)
+	accessTokenService := access_token2.NewAccessTokenService(accessTokenRepo, userRepo, userRoleRelService)
+	accessTokenController := controller.NewAccessTokenController(accessTokenService)
+	webhookRepo := webhook.NewWebhookRepo(dataData)
+	webhookService := webhook2.NewWebhookService(webhookRepo, objService, tagCommonService, tagCommonRepo, userRepo, siteInfoCommonService, auditLogService, queue)
+	webhookController := controller_admin.NewWebhookController(webhookService)
+	emailDigestController := controller.NewEmailDigestController(emailDigestService)
+	badgeController := controller_admin.NewBadgeController(badgeService)
+	bountyController := controller.NewBountyController(bountyService)
+	questionDuplicateController := controller.NewQuestionDuplicateController(questionDuplicateService, rankService)
+	postLockController := controller.NewPostLockController(postLockService, rankService)
+	auditLogController := controller_admin.NewAuditLogController(auditLogService)
+	spamController := controller_admin.NewSpamController(spamService)
+	serialVoteRepo := serial_vote.NewSerialVoteRepo(dataData, activityRepo, userRankRepo)
+	serialVoteService := serial_vote2.NewSerialVoteService(serialVoteRepo, configRepo, searchQueueService)
+	twoFactorController := controller.NewTwoFactorController(userService, twoFactorService)
+	connectorController := controller.NewConnectorController(userExternalLoginService, siteInfoCommonService)
+	answerAPIRouter := router.NewAnswerAPIRouter(langController, userController, commentController, reportController, voteController, tagController, followController, collectionController, questionController, answerController, searchController, revisionController, rankController, controller_adminReportController, userAdminController, reasonController, themeController, siteInfoController, siteinfoController, notificationController, dashboardController, uploadController, activityController, roleController, connectorController, twoFactorController, accessTokenController, webhookController, emailDigestController, badgeController, bountyController, auditLogController, pendingPostController, spamController, questionDuplicateController, postLockController)
 	swaggerRouter := router.NewSwaggerRouter(swaggerConf)
 	uiRouter := router.NewUIRouter(siteinfoController, siteInfoCommonService)
-	authUserMiddleware := middleware.NewAuthUserMiddleware(authService, siteInfoCommonService)
+	authUserMiddleware := middleware.NewAuthUserMiddleware(authService, siteInfoCommonService, accessTokenService)
+	rateLimitService := rate_limit.NewRateLimitService(dataData, siteInfoCommonService, userRoleRelService, userRepo)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimitService)
 	avatarMiddleware := middleware.NewAvatarMiddleware(serviceConf, uploaderService)
 	templateRenderController := templaterender.NewTemplateRenderController(questionService, userService, tagService, answerService, commentService, dataData, siteInfoCommonService)
 	templateController := controller.NewTemplateController(templateRenderController, siteInfoCommonService)
 	templateRouter := router.NewTemplateRouter(templateController, templateRenderController, siteInfoController)
-	ginEngine := server.NewHTTPServer(debug, staticRouter, answerAPIRouter, swaggerRouter, uiRouter, authUserMiddleware, avatarMiddleware, templateRouter)
-	scheduledTaskManager := cron.NewScheduledTaskManager(siteInfoCommonService, questionService)
-	application := newApplication(serverConf, ginEngine, scheduledTaskManager)
+	http := serverConf.HTTP
+	ginEngine, err := server.NewHTTPServer(debug, http, staticRouter, answerAPIRouter, swaggerRouter, uiRouter, authUserMiddleware, avatarMiddleware, rateLimitMiddleware, templateRouter)
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	scheduledTaskManager := cron.NewScheduledTaskManager(dataData, siteInfoCommonService, questionService, emailDigestService, bountyService, serialVoteService, postLockService)
+	mailReplyService := service.NewMailReplyService(emailService, userRepo, commentCommonRepo, commentService, answerService, rankService)
+	application := newApplication(serverConf, ginEngine, scheduledTaskManager, mailReplyService)
 	return application, func() {
+		cleanup4()
+		cleanup3()
 		cleanup2()
 		cleanup()
 	}, nil
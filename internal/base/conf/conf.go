@@ -35,12 +35,15 @@ type PathIgnore struct {
 // Server server config
 type Server struct {
 	HTTP *server.HTTP `json:"http" mapstructure:"http" yaml:"http"`
+	SMTP *server.SMTP `json:"smtp" mapstructure:"smtp" yaml:"smtp,omitempty"`
 }
 
 // Data data config
 type Data struct {
-	Database *data.Database  `json:"database" mapstructure:"database" yaml:"database"`
-	Cache    *data.CacheConf `json:"cache" mapstructure:"cache" yaml:"cache"`
+	Database *data.Database   `json:"database" mapstructure:"database" yaml:"database"`
+	Cache    *data.CacheConf  `json:"cache" mapstructure:"cache" yaml:"cache"`
+	Search   *data.SearchConf `json:"search" mapstructure:"search" yaml:"search"`
+	Queue    *data.QueueConf  `json:"queue" mapstructure:"queue" yaml:"queue"`
 }
 
 // ReadConfig read config
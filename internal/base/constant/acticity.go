@@ -3,44 +3,56 @@ package constant
 type ActivityTypeKey string
 
 const (
-	ActEdited    = "edited"
-	ActClosed    = "closed"
-	ActVotedDown = "voted_down"
-	ActVotedUp   = "voted_up"
-	ActVoteDown  = "vote_down"
-	ActVoteUp    = "vote_up"
-	ActUpVote    = "upvote"
-	ActDownVote  = "downvote"
-	ActFollow    = "follow"
-	ActAccepted  = "accepted"
-	ActAccept    = "accept"
+	ActEdited        = "edited"
+	ActClosed        = "closed"
+	ActVotedDown     = "voted_down"
+	ActVotedUp       = "voted_up"
+	ActVoteDown      = "vote_down"
+	ActVoteUp        = "vote_up"
+	ActUpVote        = "upvote"
+	ActDownVote      = "downvote"
+	ActFollow        = "follow"
+	ActAccepted      = "accepted"
+	ActAccept        = "accept"
+	ActBounty        = "bounty"
+	ActBountyAwarded = "bounty_awarded"
+	ActLocked        = "locked"
+	ActProtected     = "protected"
 )
 
 const (
-	ActQuestionAsked     ActivityTypeKey = "question.asked"
-	ActQuestionClosed    ActivityTypeKey = "question.closed"
-	ActQuestionReopened  ActivityTypeKey = "question.reopened"
-	ActQuestionAnswered  ActivityTypeKey = "question.answered"
-	ActQuestionCommented ActivityTypeKey = "question.commented"
-	ActQuestionAccept    ActivityTypeKey = "question.accept"
-	ActQuestionUpvote    ActivityTypeKey = "question.upvote"
-	ActQuestionDownVote  ActivityTypeKey = "question.downvote"
-	ActQuestionEdited    ActivityTypeKey = "question.edited"
-	ActQuestionRollback  ActivityTypeKey = "question.rollback"
-	ActQuestionDeleted   ActivityTypeKey = "question.deleted"
-	ActQuestionUndeleted ActivityTypeKey = "question.undeleted"
+	ActQuestionAsked       ActivityTypeKey = "question.asked"
+	ActQuestionClosed      ActivityTypeKey = "question.closed"
+	ActQuestionReopened    ActivityTypeKey = "question.reopened"
+	ActQuestionAnswered    ActivityTypeKey = "question.answered"
+	ActQuestionCommented   ActivityTypeKey = "question.commented"
+	ActQuestionAccept      ActivityTypeKey = "question.accept"
+	ActQuestionUpvote      ActivityTypeKey = "question.upvote"
+	ActQuestionDownVote    ActivityTypeKey = "question.downvote"
+	ActQuestionEdited      ActivityTypeKey = "question.edited"
+	ActQuestionRollback    ActivityTypeKey = "question.rollback"
+	ActQuestionDeleted     ActivityTypeKey = "question.deleted"
+	ActQuestionUndeleted   ActivityTypeKey = "question.undeleted"
+	ActQuestionBounty      ActivityTypeKey = "question.bounty"
+	ActQuestionLocked      ActivityTypeKey = "question.locked"
+	ActQuestionUnlocked    ActivityTypeKey = "question.unlocked"
+	ActQuestionProtected   ActivityTypeKey = "question.protected"
+	ActQuestionUnprotected ActivityTypeKey = "question.unprotected"
 )
 
 const (
-	ActAnswerAnswered  ActivityTypeKey = "answer.answered"
-	ActAnswerCommented ActivityTypeKey = "answer.commented"
-	ActAnswerAccept    ActivityTypeKey = "answer.accept"
-	ActAnswerUpvote    ActivityTypeKey = "answer.upvote"
-	ActAnswerDownVote  ActivityTypeKey = "answer.downvote"
-	ActAnswerEdited    ActivityTypeKey = "answer.edited"
-	ActAnswerRollback  ActivityTypeKey = "answer.rollback"
-	ActAnswerDeleted   ActivityTypeKey = "answer.deleted"
-	ActAnswerUndeleted ActivityTypeKey = "answer.undeleted"
+	ActAnswerAnswered      ActivityTypeKey = "answer.answered"
+	ActAnswerCommented     ActivityTypeKey = "answer.commented"
+	ActAnswerAccept        ActivityTypeKey = "answer.accept"
+	ActAnswerUpvote        ActivityTypeKey = "answer.upvote"
+	ActAnswerDownVote      ActivityTypeKey = "answer.downvote"
+	ActAnswerEdited        ActivityTypeKey = "answer.edited"
+	ActAnswerRollback      ActivityTypeKey = "answer.rollback"
+	ActAnswerDeleted       ActivityTypeKey = "answer.deleted"
+	ActAnswerUndeleted     ActivityTypeKey = "answer.undeleted"
+	ActAnswerBountyAwarded ActivityTypeKey = "answer.bounty_awarded"
+	ActAnswerLocked        ActivityTypeKey = "answer.locked"
+	ActAnswerUnlocked      ActivityTypeKey = "answer.unlocked"
 )
 
 const (
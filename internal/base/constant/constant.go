@@ -11,11 +11,36 @@ const (
 	AdminTokenCacheKey         = "answer:admin:token:"
 	AdminTokenCacheTime        = 7 * 24 * time.Hour
 	AcceptLanguageFlag         = "Accept-Language"
+	ClientIPFlag               = "Client-IP"
+	LoginUserIDFlag            = "Login-User-ID"
 	UserTokenMappingCacheKey   = "answer:user-token:mapping:"
 	SiteInfoCacheKey           = "answer:site-info:"
 	SiteInfoCacheTime          = 1 * time.Hour
 )
 
+const (
+	ExternalLoginStateCacheKey  = "answer:external-login:state:"
+	ExternalLoginStateCacheTime = 10 * time.Minute
+	// ExternalLoginStateCookie the state is also saved in the browser, so that the callback can only be finished
+	// by the browser which started the login
+	ExternalLoginStateCookie = "answer_external_login_state"
+	// SAMLRequestIDCookie the id of AuthnRequest is saved in the browser like the state of OAuth
+	SAMLRequestIDCookie = "answer_saml_request_id"
+	// ExternalLoginCodeCacheKey the one-time code exchanged for the access token, so that the access token
+	// is never put in the url
+	ExternalLoginCodeCacheKey  = "answer:external-login:code:"
+	ExternalLoginCodeCacheTime = 1 * time.Minute
+)
+
+const (
+	TwoFactorLoginCacheKey  = "answer:two-factor:login:"
+	TwoFactorLoginCacheTime = 5 * time.Minute
+	// TwoFactorLoginAttemptsKey the wrong codes of the login, it expires with the login cache
+	TwoFactorLoginAttemptsKey = "answer:two-factor:login-attempts:"
+	// TwoFactorLoginMaxAttempts the login must be restarted after too many wrong codes
+	TwoFactorLoginMaxAttempts = 5
+)
+
 const (
 	QuestionObjectType   = "question"
 	AnswerObjectType     = "answer"
@@ -24,6 +49,8 @@ const (
 	CollectionObjectType = "collection"
 	CommentObjectType    = "comment"
 	ReportObjectType     = "report"
+	// BadgeObjectType the badge is not a unique id object, it is only used in notification
+	BadgeObjectType = "badge"
 )
 
 // ObjectTypeStrMapping key => value
@@ -56,15 +83,21 @@ var (
 )
 
 const (
-	SiteTypeGeneral       = "general"
-	SiteTypeInterface     = "interface"
-	SiteTypeBranding      = "branding"
-	SiteTypeWrite         = "write"
-	SiteTypeLegal         = "legal"
-	SiteTypeSeo           = "seo"
-	SiteTypeLogin         = "login"
-	SiteTypeCustomCssHTML = "css-html"
-	SiteTypeTheme         = "theme"
+	SiteTypeGeneral        = "general"
+	SiteTypeInterface      = "interface"
+	SiteTypeBranding       = "branding"
+	SiteTypeWrite          = "write"
+	SiteTypeLegal          = "legal"
+	SiteTypeSeo            = "seo"
+	SiteTypeLogin          = "login"
+	SiteTypeCustomCssHTML  = "css-html"
+	SiteTypeTheme          = "theme"
+	SiteTypeLoginConnector = "login-connector"
+	SiteTypeLoginSAML      = "login-saml"
+	SiteTypeLoginLDAP      = "login-ldap"
+	SiteTypeRateLimit      = "rate-limit"
+	SiteTypePreModeration  = "pre-moderation"
+	SiteTypeSpam           = "spam"
 )
 
 func ExistInPathIgnore(name string) bool {
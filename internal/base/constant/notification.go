@@ -25,4 +25,10 @@ const (
 	YourAnswerWasDeleted = "notification.action.your_answer_was_deleted"
 	// YourCommentWasDeleted your comment was deleted
 	YourCommentWasDeleted = "notification.action.your_comment_was_deleted"
+	// YourPostWasApproved your post held for review was approved
+	YourPostWasApproved = "notification.action.your_post_was_approved"
+	// YourPostWasRejected your post held for review was rejected
+	YourPostWasRejected = "notification.action.your_post_was_rejected"
+	// BadgeAwarded badge awarded
+	BadgeAwarded = "notification.action.badge_awarded"
 )
@@ -3,8 +3,15 @@ package cron
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/schema"
 	"github.com/answerdev/answer/internal/service"
+	"github.com/answerdev/answer/internal/service/bounty"
+	"github.com/answerdev/answer/internal/service/email_digest"
+	"github.com/answerdev/answer/internal/service/post_lock"
+	"github.com/answerdev/answer/internal/service/serial_vote"
 	"github.com/answerdev/answer/internal/service/siteinfo_common"
 	"github.com/robfig/cron/v3"
 	"github.com/segmentfault/pacman/log"
@@ -12,18 +19,33 @@ import (
 
 // ScheduledTaskManager scheduled task manager
 type ScheduledTaskManager struct {
-	siteInfoService *siteinfo_common.SiteInfoCommonService
-	questionService *service.QuestionService
+	data               *data.Data
+	siteInfoService    *siteinfo_common.SiteInfoCommonService
+	questionService    *service.QuestionService
+	emailDigestService *email_digest.EmailDigestService
+	bountyService      *bounty.BountyService
+	serialVoteService  *serial_vote.SerialVoteService
+	postLockService    *post_lock.PostLockService
 }
 
 // NewScheduledTaskManager new scheduled task manager
 func NewScheduledTaskManager(
+	data *data.Data,
 	siteInfoService *siteinfo_common.SiteInfoCommonService,
 	questionService *service.QuestionService,
+	emailDigestService *email_digest.EmailDigestService,
+	bountyService *bounty.BountyService,
+	serialVoteService *serial_vote.SerialVoteService,
+	postLockService *post_lock.PostLockService,
 ) *ScheduledTaskManager {
 	manager := &ScheduledTaskManager{
-		siteInfoService: siteInfoService,
-		questionService: questionService,
+		data:               data,
+		siteInfoService:    siteInfoService,
+		questionService:    questionService,
+		emailDigestService: emailDigestService,
+		bountyService:      bountyService,
+		serialVoteService:  serialVoteService,
+		postLockService:    postLockService,
 	}
 	return manager
 }
@@ -40,5 +62,60 @@ func (s *ScheduledTaskManager) Run() {
 	if err != nil {
 		log.Error(err)
 	}
+	// daily digest at 00:00 every day, weekly digest at 00:00 every Monday
+	_, err = c.AddFunc("0 0 * * *", func() {
+		s.runOnce("daily email digest", time.Hour, func(ctx context.Context) {
+			s.emailDigestService.SendDigests(ctx, schema.NoticeFrequencyDaily)
+		})
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	_, err = c.AddFunc("0 0 * * 1", func() {
+		s.runOnce("weekly email digest", time.Hour, func(ctx context.Context) {
+			s.emailDigestService.SendDigests(ctx, schema.NoticeFrequencyWeekly)
+		})
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	// award or refund the expired bounties every 10 minutes
+	_, err = c.AddFunc("*/10 * * * *", func() {
+		s.runOnce("expired bounty", 5*time.Minute, s.bountyService.ExpireBounties)
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	// reverse the serial votes of the last day at 02:00 every day
+	_, err = c.AddFunc("0 2 * * *", func() {
+		s.runOnce("serial vote", time.Hour, s.serialVoteService.ReverseSerialVotes)
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	// release the expired locks and protections of the posts every 10 minutes
+	_, err = c.AddFunc("*/10 * * * *", func() {
+		s.runOnce("expired post lock", 5*time.Minute, s.postLockService.ExpirePostLocks)
+	})
+	if err != nil {
+		log.Error(err)
+	}
 	c.Start()
 }
+
+// runOnce run the task on the instance which acquires the lock first, the lock is held until the ttl expires,
+// so the task is not run again by the instances whose clock is a little behind
+func (s *ScheduledTaskManager) runOnce(name string, lockTTL time.Duration, task func(ctx context.Context)) {
+	ctx := context.Background()
+	locked, err := s.data.Locker.TryLock(ctx, "cron:"+name, lockTTL)
+	if err != nil {
+		log.Errorf("lock %s cron failed: %s", name, err)
+		return
+	}
+	if !locked {
+		log.Debugf("%s cron is run by another instance", name)
+		return
+	}
+	fmt.Printf("%s cron execution\n", name)
+	task(ctx)
+}
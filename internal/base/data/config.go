@@ -9,7 +9,59 @@ type Database struct {
 	MaxIdleConn     int    `json:"max_idle_conn" mapstructure:"max_idle_conn" yaml:"max_idle_conn,omitempty"`
 }
 
+const (
+	// CacheTypeMemory memory cache snapshotted to the cache file, it's the default cache type
+	CacheTypeMemory = "memory"
+	// CacheTypeRedis redis cache, which can be shared by multiple instances
+	CacheTypeRedis = "redis"
+)
+
 // CacheConf cache
 type CacheConf struct {
-	FilePath string `json:"file_path" mapstructure:"file_path" yaml:"file_path"`
+	Type     string     `json:"type" mapstructure:"type" yaml:"type"`
+	FilePath string     `json:"file_path" mapstructure:"file_path" yaml:"file_path"`
+	Redis    *RedisConf `json:"redis" mapstructure:"redis" yaml:"redis,omitempty"`
+}
+
+// RedisConf redis
+type RedisConf struct {
+	Address   string `json:"address" mapstructure:"address" yaml:"address"`
+	Password  string `json:"password" mapstructure:"password" yaml:"password"`
+	DB        int    `json:"db" mapstructure:"db" yaml:"db"`
+	KeyPrefix string `json:"key_prefix" mapstructure:"key_prefix" yaml:"key_prefix"`
+}
+
+const (
+	// SearchDriverSQL search by sql like, it's the default search driver
+	SearchDriverSQL = "sql"
+	// SearchDriverIndex search by the embedded inverted index stored in the index path, every replica keeps its
+	// own index and the changes are broadcast by the pub/sub, so the redis cache is required for multiple replicas
+	SearchDriverIndex = "index"
+)
+
+// SearchConf search engine
+type SearchConf struct {
+	Driver    string `json:"driver" mapstructure:"driver" yaml:"driver"`
+	IndexPath string `json:"index_path" mapstructure:"index_path" yaml:"index_path"`
+}
+
+// IsIndexDriver whether to search by the embedded inverted index
+func (c *SearchConf) IsIndexDriver() bool {
+	return c.Driver == SearchDriverIndex
+}
+
+const (
+	// QueueDriverDatabase durable queue stored in database, it's the default queue driver
+	QueueDriverDatabase = "database"
+	// QueueDriverChannel in-process queue, messages are lost on restart, only for development
+	QueueDriverChannel = "channel"
+)
+
+// QueueConf queue, the durations are in seconds
+type QueueConf struct {
+	Driver            string `json:"driver" mapstructure:"driver" yaml:"driver"`
+	Workers           int    `json:"workers" mapstructure:"workers" yaml:"workers,omitempty"`
+	PollInterval      int    `json:"poll_interval" mapstructure:"poll_interval" yaml:"poll_interval,omitempty"`
+	VisibilityTimeout int    `json:"visibility_timeout" mapstructure:"visibility_timeout" yaml:"visibility_timeout,omitempty"`
+	MaxAttempts       int    `json:"max_attempts" mapstructure:"max_attempts" yaml:"max_attempts,omitempty"`
 }
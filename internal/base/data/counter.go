@@ -0,0 +1,86 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/segmentfault/pacman/cache"
+)
+
+// Counter counter shared by instances, the count is increased atomically and expires ttl after the first increase
+type Counter interface {
+	// Increase increase the count of key by one, it returns the count after increased
+	Increase(ctx context.Context, key string, ttl time.Duration) (count int64, err error)
+}
+
+// NewCounter new counter by the cache, the counts are shared by all instances in redis if the redis
+// cache is used, otherwise they are only kept in the current process.
+func NewCounter(c cache.Cache) Counter {
+	if redisCache, ok := c.(*RedisCache); ok {
+		return redisCache
+	}
+	return NewMemoryCounter()
+}
+
+type memoryCount struct {
+	count    int64
+	expireAt time.Time
+}
+
+// MemoryCounter in-process counter
+type MemoryCounter struct {
+	mu        sync.Mutex
+	counts    map[string]*memoryCount
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryCounter new memory counter
+func NewMemoryCounter() *MemoryCounter {
+	return &MemoryCounter{counts: make(map[string]*memoryCount), now: time.Now}
+}
+
+// Increase increase the count of key, the count starts again from one after it expires
+func (m *MemoryCounter) Increase(ctx context.Context, key string, ttl time.Duration) (count int64, err error) {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	c, ok := m.counts[key]
+	if !ok || !now.Before(c.expireAt) {
+		c = &memoryCount{expireAt: now.Add(ttl)}
+		m.counts[key] = c
+	}
+	c.count++
+	return c.count, nil
+}
+
+// sweep remove the expired counts every minute
+func (m *MemoryCounter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+	for key, c := range m.counts {
+		if !now.Before(c.expireAt) {
+			delete(m.counts, key)
+		}
+	}
+}
+
+// increaseScript increase the count and set the expiration on the first increase
+var increaseScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// Increase increase the count of key stored in redis
+func (r *RedisCache) Increase(ctx context.Context, key string, ttl time.Duration) (count int64, err error) {
+	return increaseScript.Run(ctx, r.client, []string{r.keyPrefix + key}, ttl.Milliseconds()).Int64()
+}
@@ -0,0 +1,53 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCounter(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	counter := NewMemoryCounter()
+	counter.now = func() time.Time { return now }
+
+	for i := int64(1); i <= 3; i++ {
+		count, err := counter.Increase(ctx, "login:a", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+	count, err := counter.Increase(ctx, "login:b", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// the ttl is not extended by the later increases
+	now = now.Add(time.Minute)
+	count, err = counter.Increase(ctx, "login:a", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRedisCache_Increase(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.TODO()
+	redisCache, err := NewRedisCache(&RedisConf{Address: server.Addr(), KeyPrefix: "answer:"})
+	assert.NoError(t, err)
+	defer redisCache.Close()
+	counter := NewCounter(redisCache)
+
+	for i := int64(1); i <= 3; i++ {
+		count, err := counter.Increase(ctx, "login:a", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+	assert.Equal(t, time.Minute, server.TTL("answer:login:a"))
+
+	server.FastForward(time.Minute)
+	count, err := counter.Increase(ctx, "login:a", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
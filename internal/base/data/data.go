@@ -1,6 +1,7 @@
 package data
 
 import (
+	"fmt"
 	"path/filepath"
 	"time"
 
@@ -19,8 +20,12 @@ import (
 
 // Data data
 type Data struct {
-	DB    *xorm.Engine
-	Cache cache.Cache
+	DB          *xorm.Engine
+	Cache       cache.Cache
+	PubSub      PubSub
+	RateLimiter RateLimiter
+	Locker      Locker
+	Counter     Counter
 }
 
 // NewData new data instance
@@ -29,7 +34,14 @@ func NewData(db *xorm.Engine, cache cache.Cache) (*Data, func(), error) {
 		log.Info("closing the data resources")
 		db.Close()
 	}
-	return &Data{DB: db, Cache: cache}, cleanup, nil
+	return &Data{
+		DB:          db,
+		Cache:       cache,
+		PubSub:      NewPubSub(cache),
+		RateLimiter: NewRateLimiter(cache),
+		Locker:      NewLocker(cache),
+		Counter:     NewCounter(cache),
+	}, cleanup, nil
 }
 
 // NewDB new database instance
@@ -74,7 +86,24 @@ func NewDB(debug bool, dataConf *Database) (*xorm.Engine, error) {
 
 // NewCache new cache instance
 func NewCache(c *CacheConf) (cache.Cache, func(), error) {
-	// TODO What cache type should be initialized according to the configuration file
+	if c.Type == CacheTypeRedis {
+		if c.Redis == nil {
+			return nil, nil, fmt.Errorf("redis cache config is required")
+		}
+		log.Infof("try to connect redis cache %s", c.Redis.Address)
+		redisCache, err := NewRedisCache(c.Redis)
+		if err != nil {
+			return nil, nil, err
+		}
+		cleanup := func() {
+			log.Info("closing the redis cache")
+			if err := redisCache.Close(); err != nil {
+				log.Warn(err)
+			}
+		}
+		return redisCache, cleanup, nil
+	}
+
 	memCache := memory.NewCache()
 
 	if len(c.FilePath) > 0 {
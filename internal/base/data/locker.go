@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/segmentfault/pacman/cache"
+)
+
+// Locker lock shared by instances, the lock is held until the ttl expires. It is used to make sure the
+// scheduled task only runs on one instance, so the lock is not released after the task is done.
+type Locker interface {
+	// TryLock acquire the lock of key for ttl, it returns false if the lock is held by others
+	TryLock(ctx context.Context, key string, ttl time.Duration) (locked bool, err error)
+}
+
+// NewLocker new locker by the cache, the locks are shared by all instances in redis if the redis
+// cache is used, otherwise they are only held in the current process.
+func NewLocker(c cache.Cache) Locker {
+	if redisCache, ok := c.(*RedisCache); ok {
+		return redisCache
+	}
+	return NewMemoryLocker()
+}
+
+// MemoryLocker in-process locker
+type MemoryLocker struct {
+	mu    sync.Mutex
+	locks map[string]time.Time
+	now   func() time.Time
+}
+
+// NewMemoryLocker new memory locker
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{locks: make(map[string]time.Time), now: time.Now}
+}
+
+// TryLock acquire the lock of key if it is not held or expired
+func (m *MemoryLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (locked bool, err error) {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if expireAt, ok := m.locks[key]; ok && now.Before(expireAt) {
+		return false, nil
+	}
+	m.locks[key] = now.Add(ttl)
+	return true, nil
+}
+
+// TryLock acquire the lock of key stored in redis
+func (r *RedisCache) TryLock(ctx context.Context, key string, ttl time.Duration) (locked bool, err error) {
+	return r.client.SetNX(ctx, r.keyPrefix+key, "1", ttl).Result()
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	locker := NewMemoryLocker()
+	locker.now = func() time.Time { return now }
+
+	locked, err := locker.TryLock(ctx, "cron:digest", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+	locked, err = locker.TryLock(ctx, "cron:digest", time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, locked)
+	locked, err = locker.TryLock(ctx, "cron:bounty", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+
+	// the lock can be acquired again after it expires
+	now = now.Add(time.Hour)
+	locked, err = locker.TryLock(ctx, "cron:digest", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+}
+
+func TestRedisCache_TryLock(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.TODO()
+	redisCache, err := NewRedisCache(&RedisConf{Address: server.Addr(), KeyPrefix: "answer:"})
+	assert.NoError(t, err)
+	defer redisCache.Close()
+	locker := NewLocker(redisCache)
+
+	locked, err := locker.TryLock(ctx, "cron:digest", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+	locked, err = locker.TryLock(ctx, "cron:digest", time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, locked)
+
+	server.FastForward(time.Hour)
+	locked, err = locker.TryLock(ctx, "cron:digest", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+}
@@ -0,0 +1,121 @@
+package data
+
+import (
+	"context"
+	"sync"
+
+	"github.com/segmentfault/pacman/cache"
+	"github.com/segmentfault/pacman/log"
+)
+
+// PubSub broadcast the messages to the subscribers of channel. The messages are not persisted,
+// the subscriber only receives the messages published after it subscribed.
+type PubSub interface {
+	Publish(ctx context.Context, channel string, payload []byte) error
+	// Subscribe call the handler with the messages of channel in a new goroutine until the ctx is done
+	Subscribe(ctx context.Context, channel string, handler func(payload []byte))
+}
+
+// NewPubSub new pub/sub by the cache, the messages are broadcast to all instances by redis if the redis
+// cache is used, otherwise they are only broadcast in the current process.
+func NewPubSub(c cache.Cache) PubSub {
+	if redisCache, ok := c.(*RedisCache); ok {
+		return redisCache
+	}
+	return NewMemoryPubSub()
+}
+
+// memoryPubSubBufferSize the number of messages buffered for each subscriber of the memory pub/sub
+const memoryPubSubBufferSize = 100
+
+// MemoryPubSub in-process pub/sub
+type MemoryPubSub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*memorySubscriber]struct{}
+}
+
+// memorySubscriber the messages are buffered in the channel and delivered to the handler by its own goroutine
+type memorySubscriber struct {
+	messages chan []byte
+}
+
+// NewMemoryPubSub new memory pub/sub
+func NewMemoryPubSub() *MemoryPubSub {
+	return &MemoryPubSub{subscribers: make(map[string]map[*memorySubscriber]struct{})}
+}
+
+// Publish send the message to the subscribers of channel without waiting for the handlers,
+// the message is dropped for the subscriber whose buffer is full
+func (m *MemoryPubSub) Publish(ctx context.Context, channel string, payload []byte) error {
+	m.mu.RLock()
+	subscribers := make([]*memorySubscriber, 0, len(m.subscribers[channel]))
+	for sub := range m.subscribers[channel] {
+		subscribers = append(subscribers, sub)
+	}
+	m.mu.RUnlock()
+
+	for _, sub := range subscribers {
+		select {
+		case sub.messages <- payload:
+		default:
+			log.Warnf("the subscriber of channel %s is too slow, the message is dropped", channel)
+		}
+	}
+	return nil
+}
+
+// Subscribe call the handler with the messages of channel in a new goroutine until the ctx is done
+func (m *MemoryPubSub) Subscribe(ctx context.Context, channel string, handler func(payload []byte)) {
+	sub := &memorySubscriber{messages: make(chan []byte, memoryPubSubBufferSize)}
+	m.mu.Lock()
+	if m.subscribers[channel] == nil {
+		m.subscribers[channel] = make(map[*memorySubscriber]struct{})
+	}
+	m.subscribers[channel][sub] = struct{}{}
+	m.mu.Unlock()
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			delete(m.subscribers[channel], sub)
+			m.mu.Unlock()
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case payload := <-sub.messages:
+				handler(payload)
+			}
+		}
+	}()
+}
+
+// Publish publish the message to the redis channel
+func (r *RedisCache) Publish(ctx context.Context, channel string, payload []byte) error {
+	return r.client.Publish(ctx, r.keyPrefix+channel, payload).Err()
+}
+
+// Subscribe subscribe the redis channel until the ctx is done
+func (r *RedisCache) Subscribe(ctx context.Context, channel string, handler func(payload []byte)) {
+	sub := r.client.Subscribe(ctx, r.keyPrefix+channel)
+	go func() {
+		defer func() {
+			if err := sub.Close(); err != nil {
+				log.Warn(err)
+			}
+		}()
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				handler([]byte(msg.Payload))
+			}
+		}
+	}()
+}
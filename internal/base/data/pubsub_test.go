@@ -0,0 +1,85 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPubSub(t *testing.T, pubSub PubSub) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	received := make(chan string, 1)
+	pubSub.Subscribe(ctx, "stream", func(payload []byte) {
+		received <- string(payload)
+	})
+
+	// wait for the subscription of redis to be ready
+	assert.Eventually(t, func() bool {
+		assert.NoError(t, pubSub.Publish(context.TODO(), "stream", []byte("hello")))
+		select {
+		case msg := <-received:
+			return msg == "hello"
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// the message is not received after unsubscribed
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, pubSub.Publish(context.TODO(), "stream", []byte("bye")))
+	select {
+	case msg := <-received:
+		t.Errorf("unexpected message %s", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestMemoryPubSub(t *testing.T) {
+	testPubSub(t, NewMemoryPubSub())
+}
+
+func TestMemoryPubSub_SlowHandler(t *testing.T) {
+	pubSub := NewMemoryPubSub()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	release := make(chan struct{})
+	received := make(chan string, 2)
+	pubSub.Subscribe(ctx, "stream", func(payload []byte) {
+		// the handler subscribing another channel does not deadlock
+		pubSub.Subscribe(ctx, "other", func(payload []byte) {})
+		<-release
+		received <- string(payload)
+	})
+
+	// the publisher is not blocked by the slow handler
+	published := make(chan struct{})
+	go func() {
+		assert.NoError(t, pubSub.Publish(context.TODO(), "stream", []byte("first")))
+		assert.NoError(t, pubSub.Publish(context.TODO(), "stream", []byte("second")))
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("publish is blocked by the handler")
+	}
+
+	close(release)
+	assert.Equal(t, "first", <-received)
+	assert.Equal(t, "second", <-received)
+}
+
+func TestRedisPubSub(t *testing.T) {
+	server := miniredis.RunT(t)
+	redisCache, err := NewRedisCache(&RedisConf{Address: server.Addr(), KeyPrefix: "answer:"})
+	assert.NoError(t, err)
+	defer redisCache.Close()
+
+	assert.Equal(t, PubSub(redisCache), NewPubSub(redisCache))
+	testPubSub(t, redisCache)
+}
@@ -0,0 +1,158 @@
+package data
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/segmentfault/pacman/cache"
+)
+
+// RateLimiter token bucket rate limiter. The bucket of key holds at most burst tokens and is refilled
+// with limit tokens every period, every request takes one token.
+type RateLimiter interface {
+	Take(ctx context.Context, key string, limit int, period time.Duration, burst int) (result *RateLimitResult, err error)
+}
+
+// RateLimitResult the result of taking a token from the bucket
+type RateLimitResult struct {
+	Allowed bool
+	// Remaining the number of tokens left in the bucket
+	Remaining int
+	// RetryAfter the time to wait for the next token, it is 0 if the request is allowed
+	RetryAfter time.Duration
+	// ResetAfter the time until the bucket is full again
+	ResetAfter time.Duration
+}
+
+// NewRateLimiter new rate limiter by the cache, the buckets are shared by all instances in redis if the
+// redis cache is used, otherwise they are only kept in the current process.
+func NewRateLimiter(c cache.Cache) RateLimiter {
+	if redisCache, ok := c.(*RedisCache); ok {
+		return redisCache
+	}
+	return NewMemoryRateLimiter()
+}
+
+// newRateLimitResult calculate the result by the tokens left, rate is the tokens refilled every millisecond
+func newRateLimitResult(allowed bool, tokens, rate float64, burst int) *RateLimitResult {
+	result := &RateLimitResult{
+		Allowed:    allowed,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: time.Duration(math.Ceil((float64(burst)-tokens)/rate)) * time.Millisecond,
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration(math.Ceil((1-tokens)/rate)) * time.Millisecond
+	}
+	return result
+}
+
+// refillRate the tokens refilled every millisecond
+func refillRate(limit int, period time.Duration) float64 {
+	return float64(limit) / float64(period.Milliseconds())
+}
+
+type memoryBucket struct {
+	tokens   float64
+	last     time.Time
+	expireAt time.Time
+}
+
+// MemoryRateLimiter in-process rate limiter
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRateLimiter new memory rate limiter
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{buckets: make(map[string]*memoryBucket), now: time.Now}
+}
+
+// Take take a token from the bucket of key
+func (m *MemoryRateLimiter) Take(ctx context.Context, key string, limit int, period time.Duration, burst int) (
+	result *RateLimitResult, err error) {
+	rate := refillRate(limit, period)
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(burst), last: now}
+		m.buckets[key] = bucket
+	}
+	if now.After(bucket.last) {
+		bucket.tokens = math.Min(float64(burst), bucket.tokens+float64(now.Sub(bucket.last).Milliseconds())*rate)
+		bucket.last = now
+	}
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+	result = newRateLimitResult(allowed, bucket.tokens, rate, burst)
+	bucket.expireAt = now.Add(result.ResetAfter)
+	return result, nil
+}
+
+// sweep remove the full buckets every minute, they are the same as the new ones
+func (m *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+	for key, bucket := range m.buckets {
+		if !now.Before(bucket.expireAt) {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+// takeTokenScript take a token from the bucket stored in hash, the hash expires when the bucket is full
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate)
+	ts = now
+end
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ts))
+redis.call('PEXPIRE', KEYS[1], math.max(1, math.ceil((burst - tokens) / rate)))
+return {allowed, tostring(tokens)}
+`)
+
+// Take take a token from the bucket of key stored in redis
+func (r *RedisCache) Take(ctx context.Context, key string, limit int, period time.Duration, burst int) (
+	result *RateLimitResult, err error) {
+	rate := refillRate(limit, period)
+	now := time.Now().UnixMilli()
+	values, err := takeTokenScript.Run(ctx, r.client, []string{r.keyPrefix + key},
+		strconv.FormatFloat(rate, 'f', -1, 64), burst, now).Slice()
+	if err != nil {
+		return nil, err
+	}
+	allowed, _ := values[0].(int64)
+	tokensStr, _ := values[1].(string)
+	tokens, _ := strconv.ParseFloat(tokensStr, 64)
+	return newRateLimitResult(allowed == 1, tokens, rate, burst), nil
+}
@@ -0,0 +1,71 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRateLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := NewMemoryRateLimiter()
+	limiter.now = func() time.Time { return now }
+	ctx := context.TODO()
+
+	// 2 tokens every minute, at most 3 tokens
+	for i := 2; i >= 0; i-- {
+		result, err := limiter.Take(ctx, "user:1", 2, time.Minute, 3)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+	result, err := limiter.Take(ctx, "user:1", 2, time.Minute, 3)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 30*time.Second, result.RetryAfter)
+	assert.Equal(t, 90*time.Second, result.ResetAfter)
+
+	// the other keys are not affected
+	result, err = limiter.Take(ctx, "user:2", 2, time.Minute, 3)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	now = now.Add(30 * time.Second)
+	result, err = limiter.Take(ctx, "user:1", 2, time.Minute, 3)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// the full buckets are removed
+	now = now.Add(2 * time.Minute)
+	_, _ = limiter.Take(ctx, "user:3", 2, time.Minute, 3)
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestRedisCache_Take(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.TODO()
+	redisCache, err := NewRedisCache(&RedisConf{Address: server.Addr(), KeyPrefix: "answer:"})
+	assert.NoError(t, err)
+	defer redisCache.Close()
+	limiter := NewRateLimiter(redisCache)
+
+	for i := 1; i >= 0; i-- {
+		result, err := limiter.Take(ctx, "ip:127.0.0.1", 1, time.Hour, 2)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+	result, err := limiter.Take(ctx, "ip:127.0.0.1", 1, time.Hour, 2)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.InDelta(t, time.Hour.Seconds(), result.RetryAfter.Seconds(), 1)
+	assert.True(t, server.Exists("answer:ip:127.0.0.1"))
+
+	// the bucket expires when it is full again
+	server.FastForward(3 * time.Hour)
+	assert.False(t, server.Exists("answer:ip:127.0.0.1"))
+}
@@ -0,0 +1,97 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/segmentfault/pacman/cache"
+)
+
+var _ cache.Cache = (*RedisCache)(nil)
+
+// RedisCache cache stored in redis, it can be shared by multiple instances
+type RedisCache struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisCache new redis cache
+func NewRedisCache(c *RedisConf) (*RedisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     c.Address,
+		Password: c.Password,
+		DB:       c.DB,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("connect to redis %s failed: %w", c.Address, err)
+	}
+	return &RedisCache{client: client, keyPrefix: c.KeyPrefix}, nil
+}
+
+// GetString get string value by key
+func (r *RedisCache) GetString(ctx context.Context, key string) (string, error) {
+	value, err := r.client.Get(ctx, r.keyPrefix+key).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("information does not exist")
+	}
+	return value, err
+}
+
+// SetString set string value with key and ttl
+func (r *RedisCache) SetString(ctx context.Context, key, value string, ttl time.Duration) error {
+	return r.client.Set(ctx, r.keyPrefix+key, value, ttl).Err()
+}
+
+// GetInt64 get int64 value by key
+func (r *RedisCache) GetInt64(ctx context.Context, key string) (int64, error) {
+	value, err := r.GetString(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	num, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse the value of %s failed: %w", key, err)
+	}
+	return num, nil
+}
+
+// SetInt64 set int64 value with key and ttl
+func (r *RedisCache) SetInt64(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	return r.SetString(ctx, key, strconv.FormatInt(value, 10), ttl)
+}
+
+// Del delete key from cache
+func (r *RedisCache) Del(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.keyPrefix+key).Err()
+}
+
+// Flush deletes all items with the key prefix, the other keys in the same redis db are not affected
+func (r *RedisCache) Flush(ctx context.Context) error {
+	iter := r.client.Scan(ctx, 0, r.keyPrefix+"*", 100).Iterator()
+	keys := make([]string, 0, 100)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == cap(keys) {
+			if err := r.client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return r.client.Del(ctx, keys...).Err()
+	}
+	return nil
+}
+
+// Close close the redis connection
+func (r *RedisCache) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisCache(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.TODO()
+
+	redisCache, err := NewRedisCache(&RedisConf{Address: server.Addr(), KeyPrefix: "answer:"})
+	assert.NoError(t, err)
+	defer redisCache.Close()
+
+	assert.NoError(t, redisCache.SetString(ctx, "token", "user", time.Minute))
+	value, err := redisCache.GetString(ctx, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "user", value)
+	assert.True(t, server.Exists("answer:token"))
+
+	assert.NoError(t, redisCache.SetInt64(ctx, "count", 10, 0))
+	num, err := redisCache.GetInt64(ctx, "count")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), num)
+
+	// the corrupt value is not read as 0
+	assert.NoError(t, redisCache.SetString(ctx, "corrupt", "ten", 0))
+	_, err = redisCache.GetInt64(ctx, "corrupt")
+	assert.Error(t, err)
+
+	server.FastForward(2 * time.Minute)
+	_, err = redisCache.GetString(ctx, "token")
+	assert.Error(t, err)
+
+	assert.NoError(t, redisCache.Del(ctx, "count"))
+	_, err = redisCache.GetInt64(ctx, "count")
+	assert.Error(t, err)
+}
+
+func TestRedisCache_Flush(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.TODO()
+
+	redisCache, err := NewRedisCache(&RedisConf{Address: server.Addr(), KeyPrefix: "answer:"})
+	assert.NoError(t, err)
+	defer redisCache.Close()
+
+	assert.NoError(t, server.Set("other", "value"))
+	for _, key := range []string{"a", "b", "c"} {
+		assert.NoError(t, redisCache.SetString(ctx, key, key, 0))
+	}
+	assert.NoError(t, redisCache.Flush(ctx))
+	assert.Equal(t, []string{"other"}, server.Keys())
+}
@@ -6,9 +6,11 @@ import (
 	"github.com/answerdev/answer/internal/schema"
 	"github.com/answerdev/answer/internal/service/siteinfo_common"
 
+	"github.com/answerdev/answer/internal/base/constant"
 	"github.com/answerdev/answer/internal/base/handler"
 	"github.com/answerdev/answer/internal/base/reason"
 	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/access_token"
 	"github.com/answerdev/answer/internal/service/auth"
 	"github.com/answerdev/answer/pkg/converter"
 	"github.com/gin-gonic/gin"
@@ -21,15 +23,18 @@ var ctxUUIDKey = "ctxUuidKey"
 type AuthUserMiddleware struct {
 	authService           *auth.AuthService
 	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
+	accessTokenService    *access_token.AccessTokenService
 }
 
 // NewAuthUserMiddleware new auth user middleware
 func NewAuthUserMiddleware(
 	authService *auth.AuthService,
-	siteInfoCommonService *siteinfo_common.SiteInfoCommonService) *AuthUserMiddleware {
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+	accessTokenService *access_token.AccessTokenService) *AuthUserMiddleware {
 	return &AuthUserMiddleware{
 		authService:           authService,
 		siteInfoCommonService: siteInfoCommonService,
+		accessTokenService:    accessTokenService,
 	}
 }
 
@@ -41,13 +46,13 @@ func (am *AuthUserMiddleware) Auth() gin.HandlerFunc {
 			ctx.Next()
 			return
 		}
-		userInfo, err := am.authService.GetUserCacheInfo(ctx, token)
+		userInfo, err := am.getUserCacheInfo(ctx, token)
 		if err != nil {
 			ctx.Next()
 			return
 		}
 		if userInfo != nil {
-			ctx.Set(ctxUUIDKey, userInfo)
+			setUserInfoToContext(ctx, userInfo)
 		}
 		ctx.Next()
 	}
@@ -85,7 +90,12 @@ func (am *AuthUserMiddleware) MustAuth() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
-		userInfo, err := am.authService.GetUserCacheInfo(ctx, token)
+		userInfo, err := am.getUserCacheInfo(ctx, token)
+		if myErr, ok := err.(*errors.Error); ok && errors.IsForbidden(myErr) {
+			handler.HandleResponse(ctx, err, nil)
+			ctx.Abort()
+			return
+		}
 		if err != nil || userInfo == nil {
 			handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
 			ctx.Abort()
@@ -108,7 +118,7 @@ func (am *AuthUserMiddleware) MustAuth() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
-		ctx.Set(ctxUUIDKey, userInfo)
+		setUserInfoToContext(ctx, userInfo)
 		ctx.Next()
 	}
 }
@@ -121,7 +131,21 @@ func (am *AuthUserMiddleware) AdminAuth() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
-		userInfo, err := am.authService.GetAdminUserCacheInfo(ctx, token)
+		var userInfo *entity.UserCacheInfo
+		var err error
+		if access_token.IsAccessToken(token) {
+			userInfo, err = am.getUserCacheInfo(ctx, token)
+			if err == nil && !userInfo.IsAdmin {
+				err = errors.Unauthorized(reason.UnauthorizedError)
+			}
+		} else {
+			userInfo, err = am.authService.GetAdminUserCacheInfo(ctx, token)
+		}
+		if myErr, ok := err.(*errors.Error); ok && errors.IsForbidden(myErr) {
+			handler.HandleResponse(ctx, err, nil)
+			ctx.Abort()
+			return
+		}
 		if err != nil {
 			handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
 			ctx.Abort()
@@ -133,12 +157,35 @@ func (am *AuthUserMiddleware) AdminAuth() gin.HandlerFunc {
 				ctx.Abort()
 				return
 			}
-			ctx.Set(ctxUUIDKey, userInfo)
+			setUserInfoToContext(ctx, userInfo)
 		}
 		ctx.Next()
 	}
 }
 
+// getUserCacheInfo get the user of session token, or the user of personal access token
+// if the token has the scope for the request
+func (am *AuthUserMiddleware) getUserCacheInfo(ctx *gin.Context, token string) (
+	userInfo *entity.UserCacheInfo, err error) {
+	if !access_token.IsAccessToken(token) {
+		return am.authService.GetUserCacheInfo(ctx, token)
+	}
+	userInfo, scopes, err := am.accessTokenService.Authenticate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !access_token.ScopeAllowed(scopes, ctx.Request.Method, ctx.Request.URL.Path) {
+		return nil, errors.Forbidden(reason.AccessTokenScopeInsufficient)
+	}
+	return userInfo, nil
+}
+
+// setUserInfoToContext set login user to context, the user id is also set for the services
+func setUserInfoToContext(ctx *gin.Context, userInfo *entity.UserCacheInfo) {
+	ctx.Set(ctxUUIDKey, userInfo)
+	ctx.Set(constant.LoginUserIDFlag, userInfo.UserID)
+}
+
 // GetLoginUserIDFromContext get user id from context
 func GetLoginUserIDFromContext(ctx *gin.Context) (userID string) {
 	userInfo := GetUserInfoFromContext(ctx)
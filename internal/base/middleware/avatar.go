@@ -1,9 +1,8 @@
 package middleware
 
 import (
-	"fmt"
+	"net/http"
 	"net/url"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -41,18 +40,16 @@ func (am *AvatarMiddleware) AvatarThumb() gin.HandlerFunc {
 				return
 			}
 			_, urlfileName := filepath.Split(uUrl.Path)
-			uploadPath := am.serviceConfig.UploadPath
-			filePath := fmt.Sprintf("%s/avatar/%s", uploadPath, urlfileName)
-			var avatarfile []byte
-			if size == 0 {
-				avatarfile, err = os.ReadFile(filePath)
-			} else {
-				avatarfile, err = am.uploaderService.AvatarThumbFile(ctx, uploadPath, urlfileName, size)
-			}
+			avatarfile, redirectURL, err := am.uploaderService.GetAvatarFile(ctx, urlfileName, size)
 			if err != nil {
 				ctx.Next()
 				return
 			}
+			if len(redirectURL) > 0 {
+				ctx.Redirect(http.StatusFound, redirectURL)
+				ctx.Abort()
+				return
+			}
 			_, err = ctx.Writer.WriteString(string(avatarfile))
 			if err != nil {
 				log.Error(err)
@@ -0,0 +1,11 @@
+package middleware
+
+import (
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/gin-gonic/gin"
+)
+
+// ExtractAndSetClientIP set client ip to context, the services can get it without gin context
+func ExtractAndSetClientIP(ctx *gin.Context) {
+	ctx.Set(constant.ClientIPFlag, ctx.ClientIP())
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	brotli "github.com/anargu/gin-brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Compress compress the response by brotli except the paths of event stream,
+// because the compressed event can not be flushed to the client immediately.
+func Compress(skipPaths ...string) gin.HandlerFunc {
+	compress := brotli.Brotli(brotli.DefaultCompression)
+	return func(ctx *gin.Context) {
+		for _, path := range skipPaths {
+			if ctx.Request.URL.Path == path {
+				return
+			}
+		}
+		compress(ctx)
+	}
+}
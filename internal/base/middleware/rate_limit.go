@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/rate_limit"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// rateLimitRoutes the route class of the write requests, the key is the method and the full path of route
+var rateLimitRoutes = map[string]string{
+	"POST /answer/api/v1/question":            schema.RateLimitClassPosting,
+	"PUT /answer/api/v1/question":             schema.RateLimitClassPosting,
+	"POST /answer/api/v1/answer":              schema.RateLimitClassPosting,
+	"PUT /answer/api/v1/answer":               schema.RateLimitClassPosting,
+	"POST /answer/api/v1/vote/up":             schema.RateLimitClassVoting,
+	"POST /answer/api/v1/vote/down":           schema.RateLimitClassVoting,
+	"POST /answer/api/v1/comment":             schema.RateLimitClassCommenting,
+	"PUT /answer/api/v1/comment":              schema.RateLimitClassCommenting,
+	"POST /answer/api/v1/report":              schema.RateLimitClassFlagging,
+	"POST /answer/api/v1/user/login/email":    schema.RateLimitClassLogin,
+	"POST /answer/api/v1/user/login/2fa":      schema.RateLimitClassLogin,
+	"POST /answer/api/v1/user/register/email": schema.RateLimitClassLogin,
+	"POST /answer/api/v1/user/password/reset": schema.RateLimitClassLogin,
+}
+
+// RateLimitMiddleware rate limit middleware
+type RateLimitMiddleware struct {
+	rateLimitService *rate_limit.RateLimitService
+}
+
+// NewRateLimitMiddleware new rate limit middleware
+func NewRateLimitMiddleware(rateLimitService *rate_limit.RateLimitService) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		rateLimitService: rateLimitService,
+	}
+}
+
+// RateLimit limit the write requests by the route class, it must be used after the auth middleware.
+// The RateLimit-* headers are set for the limited routes, the request is allowed if the limiter fails.
+func (rm *RateLimitMiddleware) RateLimit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		class, ok := rateLimitRoutes[ctx.Request.Method+" "+ctx.FullPath()]
+		if !ok {
+			ctx.Next()
+			return
+		}
+		status, err := rm.rateLimitService.Take(ctx, class, GetLoginUserIDFromContext(ctx), ctx.ClientIP())
+		if err != nil {
+			log.Errorf("rate limit failed: %s", err)
+			ctx.Next()
+			return
+		}
+		if status == nil {
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("RateLimit-Limit", strconv.Itoa(status.Limit))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(status.Remaining))
+		ctx.Header("RateLimit-Reset", formatSeconds(status.ResetAfter))
+		if !status.Allowed {
+			ctx.Header("Retry-After", formatSeconds(status.RetryAfter))
+			handler.HandleResponse(ctx, errors.New(http.StatusTooManyRequests, reason.RateLimitExceeded), nil)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// formatSeconds format the duration to the whole seconds, it is rounded up
+func formatSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -20,6 +20,9 @@ const (
 	QuestionCannotDeleted            = "error.question.cannot_deleted"
 	QuestionCannotClose              = "error.question.cannot_close"
 	QuestionCannotUpdate             = "error.question.cannot_update"
+	QuestionDuplicateTargetInvalid   = "error.question.duplicate_target_invalid"
+	QuestionCannotMerge              = "error.question.cannot_merge"
+	QuestionProtected                = "error.question.protected"
 	AnswerNotFound                   = "error.answer.not_found"
 	AnswerCannotDeleted              = "error.answer.cannot_deleted"
 	AnswerCannotUpdate               = "error.answer.cannot_update"
@@ -39,6 +42,8 @@ const (
 	EmailNeedToBeVerified            = "error.email.need_to_be_verified"
 	UserSuspended                    = "error.user.suspended"
 	ObjectNotFound                   = "error.object.not_found"
+	ObjectLocked                     = "error.object.locked"
+	ObjectCannotLock                 = "error.object.cannot_lock"
 	TagNotFound                      = "error.tag.not_found"
 	TagNotContainSynonym             = "error.tag.not_contain_synonym_tags"
 	TagCannotUpdate                  = "error.tag.cannot_update"
@@ -61,4 +66,43 @@ const (
 	TagCannotSetSynonymAsItself      = "error.tag.cannot_set_synonym_as_itself"
 	NotAllowedRegistration           = "error.user.not_allowed_registration"
 	SMTPConfigFromNameCannotBeEmail  = "error.smtp.config_from_name_cannot_be_email"
+	PasswordLoginDisabled            = "error.user.password_login_disabled"
+	ExternalLoginFailed              = "error.user.external_login_failed"
+	LoginConnectorInvalid            = "error.login_connector.invalid"
+	LoginSAMLInvalid                 = "error.login_saml.invalid"
+	LoginLDAPInvalid                 = "error.login_ldap.invalid"
+	TwoFactorCodeInvalid             = "error.two_factor.code_invalid"
+	TwoFactorLoginExpired            = "error.two_factor.login_expired"
+	TwoFactorNotEnabled              = "error.two_factor.not_enabled"
+	TwoFactorAlreadyEnabled          = "error.two_factor.already_enabled"
+	TwoFactorRequired                = "error.two_factor.required"
+	AccessTokenNotFound              = "error.access_token.not_found"
+	AccessTokenScopeInvalid          = "error.access_token.scope_invalid"
+	AccessTokenScopeInsufficient     = "error.access_token.scope_insufficient"
+	WebhookNotFound                  = "error.webhook.not_found"
+	WebhookInvalid                   = "error.webhook.invalid"
+	WebhookDeliveryNotFound          = "error.webhook.delivery_not_found"
+	MailReplyAddressInvalid          = "error.mail_reply.address_invalid"
+	MailReplySenderNotMatch          = "error.mail_reply.sender_not_match"
+	MailReplyContentEmpty            = "error.mail_reply.content_empty"
+	RoleNotFound                     = "error.role.not_found"
+	RoleNameDuplicate                = "error.role.name_duplicate"
+	RoleCannotDelete                 = "error.role.cannot_delete"
+	RoleInUse                        = "error.role.in_use"
+	RolePowerInvalid                 = "error.role.power_invalid"
+	RolePowerCannotEdit              = "error.role.power_cannot_edit"
+	RankConfigInvalid                = "error.config.rank_config_invalid"
+	BadgeNotFound                    = "error.badge.not_found"
+	BadgeAlreadyAwarded              = "error.badge.already_awarded"
+	UserBadgeNotFound                = "error.badge.user_badge_not_found"
+	BountyNotFound                   = "error.bounty.not_found"
+	BountyAlreadyExist               = "error.bounty.already_exist"
+	BountyNotAllowed                 = "error.bounty.not_allowed"
+	BountyRankNotEnough              = "error.bounty.rank_not_enough"
+	BountyCannotAward                = "error.bounty.cannot_award"
+	BountyAnswerInvalid              = "error.bounty.answer_invalid"
+	RateLimitExceeded                = "error.rate_limit.exceeded"
+	RateLimitRuleInvalid             = "error.rate_limit.rule_invalid"
+	SpamContentRejected              = "error.spam.content_rejected"
+	SpamPatternInvalid               = "error.spam.pattern_invalid"
 )
@@ -3,4 +3,14 @@ package server
 // HTTP http config
 type HTTP struct {
 	Addr string `json:"addr" mapstructure:"addr"`
+	// TrustedProxies the addresses or CIDRs of the reverse proxies whose forwarded headers are trusted to get
+	// the client ip, no proxy is trusted if it is empty and the remote address is used as the client ip
+	TrustedProxies []string `json:"trusted_proxies" mapstructure:"trusted_proxies" yaml:"trusted_proxies,omitempty"`
+}
+
+// SMTP inbound smtp config, the server receives the replies of notification emails and is disabled if addr is empty
+type SMTP struct {
+	Addr string `json:"addr" mapstructure:"addr"`
+	// Domain the domain name of server used in the greeting
+	Domain string `json:"domain" mapstructure:"domain"`
 }
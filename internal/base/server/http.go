@@ -4,7 +4,6 @@ import (
 	"html/template"
 	"io/fs"
 
-	brotli "github.com/anargu/gin-brotli"
 	"github.com/answerdev/answer/internal/base/middleware"
 	"github.com/answerdev/answer/internal/router"
 	"github.com/answerdev/answer/ui"
@@ -13,14 +12,16 @@ import (
 
 // NewHTTPServer new http server.
 func NewHTTPServer(debug bool,
+	httpConf *HTTP,
 	staticRouter *router.StaticRouter,
 	answerRouter *router.AnswerAPIRouter,
 	swaggerRouter *router.SwaggerRouter,
 	viewRouter *router.UIRouter,
 	authUserMiddleware *middleware.AuthUserMiddleware,
 	avatarMiddleware *middleware.AvatarMiddleware,
+	rateLimitMiddleware *middleware.RateLimitMiddleware,
 	templateRouter *router.TemplateRouter,
-) *gin.Engine {
+) (*gin.Engine, error) {
 
 	if debug {
 		gin.SetMode(gin.DebugMode)
@@ -28,7 +29,13 @@ func NewHTTPServer(debug bool,
 		gin.SetMode(gin.ReleaseMode)
 	}
 	r := gin.New()
-	r.Use(brotli.Brotli(brotli.DefaultCompression), middleware.ExtractAndSetAcceptLanguage)
+	// the client ip is used by the rate limit and the audit log, so the forwarded headers are only trusted
+	// when they are set by the configured proxies
+	if err := r.SetTrustedProxies(httpConf.TrustedProxies); err != nil {
+		return nil, err
+	}
+	r.Use(middleware.Compress("/answer/api/v1/notification/stream"), middleware.ExtractAndSetAcceptLanguage,
+		middleware.ExtractAndSetClientIP)
 	r.GET("/healthz", func(ctx *gin.Context) { ctx.String(200, "OK") })
 
 	html, _ := fs.Sub(ui.Template, "template")
@@ -45,16 +52,17 @@ func NewHTTPServer(debug bool,
 
 	// The route must be available without logging in
 	mustUnAuthV1 := r.Group("/answer/api/v1")
+	mustUnAuthV1.Use(rateLimitMiddleware.RateLimit())
 	answerRouter.RegisterMustUnAuthAnswerAPIRouter(mustUnAuthV1)
 
 	// register api that no need to login
 	unAuthV1 := r.Group("/answer/api/v1")
-	unAuthV1.Use(authUserMiddleware.Auth(), authUserMiddleware.EjectUserBySiteInfo())
+	unAuthV1.Use(authUserMiddleware.Auth(), authUserMiddleware.EjectUserBySiteInfo(), rateLimitMiddleware.RateLimit())
 	answerRouter.RegisterUnAuthAnswerAPIRouter(unAuthV1)
 
 	// register api that must be authenticated
 	authV1 := r.Group("/answer/api/v1")
-	authV1.Use(authUserMiddleware.MustAuth())
+	authV1.Use(authUserMiddleware.MustAuth(), rateLimitMiddleware.RateLimit())
 	answerRouter.RegisterAnswerAPIRouter(authV1)
 
 	adminauthV1 := r.Group("/answer/admin/api")
@@ -62,5 +70,5 @@ func NewHTTPServer(debug bool,
 	answerRouter.RegisterAnswerAdminAPIRouter(adminauthV1)
 
 	templateRouter.RegisterTemplateRouter(rootGroup)
-	return r
+	return r, nil
 }
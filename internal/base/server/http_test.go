@@ -0,0 +1,13 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPServer_InvalidTrustedProxies(t *testing.T) {
+	_, err := NewHTTPServer(false, &HTTP{TrustedProxies: []string{"not-an-ip"}},
+		nil, nil, nil, nil, nil, nil, nil, nil)
+	assert.Error(t, err)
+}
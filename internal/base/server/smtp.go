@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/translator"
+	"github.com/emersion/go-smtp"
+	myErrors "github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/i18n"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	smtpMaxMessageBytes = 10 * 1024 * 1024
+	smtpMaxRecipients   = 10
+	smtpTimeout         = time.Minute
+)
+
+// MailHandler handle the inbound mail
+type MailHandler interface {
+	CheckRecipient(ctx context.Context, address string) (err error)
+	HandleMail(ctx context.Context, recipients []string, r io.Reader) (err error)
+}
+
+// SMTPServer the inbound smtp server
+type SMTPServer struct {
+	server *smtp.Server
+}
+
+// NewSMTPServer new inbound smtp server
+func NewSMTPServer(conf *SMTP, handler MailHandler) *SMTPServer {
+	s := smtp.NewServer(&smtpBackend{handler: handler})
+	s.Addr = conf.Addr
+	s.Domain = conf.Domain
+	s.MaxMessageBytes = smtpMaxMessageBytes
+	s.MaxRecipients = smtpMaxRecipients
+	s.ReadTimeout = smtpTimeout
+	s.WriteTimeout = smtpTimeout
+	s.AuthDisabled = true
+	return &SMTPServer{server: s}
+}
+
+// Start start the server
+func (s *SMTPServer) Start() error {
+	log.Infof("inbound smtp server listening on %s", s.server.Addr)
+	return s.server.ListenAndServe()
+}
+
+// Stop stop the server
+func (s *SMTPServer) Stop() error {
+	return s.server.Close()
+}
+
+type smtpBackend struct {
+	handler MailHandler
+}
+
+func (b *smtpBackend) NewSession(_ *smtp.Conn) (smtp.Session, error) {
+	return &smtpSession{handler: b.handler}, nil
+}
+
+type smtpSession struct {
+	handler    MailHandler
+	recipients []string
+}
+
+func (s *smtpSession) AuthPlain(username, password string) error {
+	return smtp.ErrAuthUnsupported
+}
+
+func (s *smtpSession) Mail(from string, opts *smtp.MailOptions) error {
+	return nil
+}
+
+// Rcpt only the reply addresses are accepted, so the server is never used to relay mails
+func (s *smtpSession) Rcpt(to string) error {
+	if err := s.handler.CheckRecipient(context.Background(), to); err != nil {
+		return toSMTPError(err)
+	}
+	s.recipients = append(s.recipients, to)
+	return nil
+}
+
+func (s *smtpSession) Data(r io.Reader) error {
+	if err := s.handler.HandleMail(context.Background(), s.recipients, r); err != nil {
+		return toSMTPError(err)
+	}
+	return nil
+}
+
+func (s *smtpSession) Reset() {
+	s.recipients = nil
+}
+
+func (s *smtpSession) Logout() error {
+	return nil
+}
+
+// toSMTPError the rejected reply is bounced to the sender with the reason
+func toSMTPError(err error) error {
+	var myErr *myErrors.Error
+	if !errors.As(err, &myErr) || myErrors.IsInternalServer(myErr) {
+		log.Error(err)
+		return &smtp.SMTPError{
+			Code:         451,
+			EnhancedCode: smtp.EnhancedCode{4, 3, 0},
+			Message:      "Temporary server error, please try again later",
+		}
+	}
+	message := myErr.Message
+	if len(message) == 0 && translator.GlobalTrans != nil {
+		message = translator.GlobalTrans.Tr(i18n.LanguageEnglish, myErr.Reason)
+	}
+	return &smtp.SMTPError{
+		Code:         550,
+		EnhancedCode: smtp.EnhancedCode{5, 7, 1},
+		Message:      message,
+	}
+}
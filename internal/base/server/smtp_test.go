@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMailHandler struct {
+	recipients []string
+	body       string
+}
+
+func (f *fakeMailHandler) CheckRecipient(ctx context.Context, address string) error {
+	if !strings.HasPrefix(address, "reply+") {
+		return errors.BadRequest(reason.MailReplyAddressInvalid).WithMsg("The reply address is invalid.")
+	}
+	return nil
+}
+
+func (f *fakeMailHandler) HandleMail(ctx context.Context, recipients []string, r io.Reader) error {
+	body, err := io.ReadAll(r)
+	f.recipients, f.body = recipients, string(body)
+	return err
+}
+
+func TestSMTPServer(t *testing.T) {
+	handler := &fakeMailHandler{}
+	s := NewSMTPServer(&SMTP{Domain: "localhost"}, handler)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() { _ = s.server.Serve(l) }()
+	defer func() { _ = s.Stop() }()
+
+	msg := "From: bob@example.com\r\nSubject: Re: answer\r\n\r\nThanks!\r\n"
+	err = smtp.SendMail(l.Addr().String(), nil, "bob@example.com", []string{"reply+abc@example.com"}, []byte(msg))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"reply+abc@example.com"}, handler.recipients)
+	assert.Contains(t, handler.body, "Thanks!")
+
+	// the server is not an open relay
+	err = smtp.SendMail(l.Addr().String(), nil, "bob@example.com", []string{"alice@example.com"}, []byte(msg))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "The reply address is invalid.")
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/answerdev/answer/pkg/dir"
+	"github.com/answerdev/answer/pkg/s3"
+)
+
+const (
+	// DriverLocal store files in the upload path of local disk, it's the default driver
+	DriverLocal = "local"
+	// DriverS3 store files in S3-compatible object storage
+	DriverS3 = "s3"
+)
+
+// ErrNotExist the file does not exist in storage
+var ErrNotExist = errors.New("file does not exist")
+
+// Config storage config
+type Config struct {
+	Driver string    `json:"driver" mapstructure:"driver" yaml:"driver"`
+	S3     *S3Config `json:"s3" mapstructure:"s3" yaml:"s3,omitempty"`
+}
+
+// S3Config S3-compatible object storage config
+type S3Config struct {
+	Endpoint        string `json:"endpoint" mapstructure:"endpoint" yaml:"endpoint"`
+	Bucket          string `json:"bucket" mapstructure:"bucket" yaml:"bucket"`
+	Region          string `json:"region" mapstructure:"region" yaml:"region"`
+	AccessKeyID     string `json:"access_key_id" mapstructure:"access_key_id" yaml:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key" mapstructure:"secret_access_key" yaml:"secret_access_key"`
+	PathStyle       bool   `json:"path_style" mapstructure:"path_style" yaml:"path_style"`
+	// PublicBaseURL the base url that the objects can be fetched publicly, e.g. the CDN domain,
+	// the object url of endpoint is used if it's empty
+	PublicBaseURL string `json:"public_base_url" mapstructure:"public_base_url" yaml:"public_base_url"`
+}
+
+// Storage the storage of uploaded files, the key is the relative path like avatar/xxx.png
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get returns ErrNotExist if the file does not exist
+	Get(ctx context.Context, key string) ([]byte, error)
+	Exist(ctx context.Context, key string) (bool, error)
+	// PublicURL returns the url that the file can be fetched directly,
+	// empty means the file should be served by answer under /uploads
+	PublicURL(key string) string
+}
+
+// NewStorage new storage by config, the local storage is used by default
+func NewStorage(conf *Config, uploadPath string) (Storage, error) {
+	if conf == nil || conf.Driver != DriverS3 {
+		return NewLocalStorage(uploadPath), nil
+	}
+	if conf.S3 == nil {
+		return nil, fmt.Errorf("s3 storage config is required")
+	}
+	return NewS3Storage(conf.S3)
+}
+
+// LocalStorage store files in local disk
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage new local storage
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{root: root}
+}
+
+func (ls *LocalStorage) filePath(key string) string {
+	return filepath.Join(ls.root, filepath.FromSlash(key))
+}
+
+// Put save file to disk
+func (ls *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	filePath := ls.filePath(key)
+	if err := dir.CreateDirIfNotExist(filepath.Dir(filePath)); err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0o644)
+}
+
+// Get read file from disk
+func (ls *LocalStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(ls.filePath(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotExist
+	}
+	return data, err
+}
+
+// Exist check whether the file exists in disk
+func (ls *LocalStorage) Exist(ctx context.Context, key string) (bool, error) {
+	return dir.CheckFileExist(ls.filePath(key)), nil
+}
+
+// PublicURL local files are served by answer
+func (ls *LocalStorage) PublicURL(key string) string {
+	return ""
+}
+
+// S3Storage store files in S3-compatible object storage
+type S3Storage struct {
+	client        *s3.Client
+	publicBaseURL string
+}
+
+// NewS3Storage new s3 storage
+func NewS3Storage(conf *S3Config) (*S3Storage, error) {
+	client, err := s3.NewClient(s3.Config{
+		Endpoint:        conf.Endpoint,
+		Region:          conf.Region,
+		Bucket:          conf.Bucket,
+		AccessKeyID:     conf.AccessKeyID,
+		SecretAccessKey: conf.SecretAccessKey,
+		PathStyle:       conf.PathStyle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &S3Storage{client: client, publicBaseURL: strings.TrimSuffix(conf.PublicBaseURL, "/")}, nil
+}
+
+// Put upload object
+func (ss *S3Storage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	return ss.client.PutObject(ctx, key, data, contentType)
+}
+
+// Get download object
+func (ss *S3Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := ss.client.GetObject(ctx, key)
+	if errors.Is(err, s3.ErrNotFound) {
+		return nil, ErrNotExist
+	}
+	return data, err
+}
+
+// Exist check whether the object exists
+func (ss *S3Storage) Exist(ctx context.Context, key string) (bool, error) {
+	return ss.client.HasObject(ctx, key)
+}
+
+// PublicURL the url of public base url or the object url of endpoint
+func (ss *S3Storage) PublicURL(key string) string {
+	if len(ss.publicBaseURL) > 0 {
+		return ss.publicBaseURL + "/" + key
+	}
+	return ss.client.ObjectURL(key)
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.TODO()
+	localStorage, err := NewStorage(nil, t.TempDir())
+	assert.NoError(t, err)
+
+	exist, err := localStorage.Exist(ctx, "avatar/a.png")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	_, err = localStorage.Get(ctx, "avatar/a.png")
+	assert.ErrorIs(t, err, ErrNotExist)
+
+	assert.NoError(t, localStorage.Put(ctx, "avatar/a.png", []byte("png"), "image/png"))
+	exist, err = localStorage.Exist(ctx, "avatar/a.png")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	data, err := localStorage.Get(ctx, "avatar/a.png")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("png"), data)
+	assert.Empty(t, localStorage.PublicURL("avatar/a.png"))
+}
+
+func TestS3Storage_PublicURL(t *testing.T) {
+	s3Storage, err := NewStorage(&Config{Driver: DriverS3, S3: &S3Config{
+		Endpoint:  "http://127.0.0.1:9000",
+		Bucket:    "answer",
+		PathStyle: true,
+	}}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:9000/answer/post/a.png", s3Storage.PublicURL("post/a.png"))
+
+	s3Storage, err = NewStorage(&Config{Driver: DriverS3, S3: &S3Config{
+		Endpoint:      "https://s3.amazonaws.com",
+		Bucket:        "answer",
+		PublicBaseURL: "https://cdn.example.com/",
+	}}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/post/a.png", s3Storage.PublicURL("post/a.png"))
+}
@@ -22,6 +22,7 @@ var (
 	UploadFilePath = "/uploads/"
 	I18nPath       = "/i18n/"
 	CacheDir       = "/cache/"
+	SearchIndexDir = "/search/"
 )
 
 // GetConfigFilePath get config file path
@@ -34,6 +35,7 @@ func FormatAllPath(dataDirPath string) {
 	UploadFilePath = filepath.Join(dataDirPath, UploadFilePath)
 	I18nPath = filepath.Join(dataDirPath, I18nPath)
 	CacheDir = filepath.Join(dataDirPath, CacheDir)
+	SearchIndexDir = filepath.Join(dataDirPath, SearchIndexDir)
 }
 
 // InstallAllInitialEnvironment install all initial environment
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/middleware"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/access_token"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessTokenController personal access token controller
+type AccessTokenController struct {
+	accessTokenService *access_token.AccessTokenService
+}
+
+// NewAccessTokenController new controller
+func NewAccessTokenController(accessTokenService *access_token.AccessTokenService) *AccessTokenController {
+	return &AccessTokenController{accessTokenService: accessTokenService}
+}
+
+// AddAccessToken create personal access token
+// @Summary create personal access token
+// @Description create personal access token, the token is only returned once
+// @Security ApiKeyAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.AddAccessTokenReq true "AddAccessTokenReq"
+// @Success 200 {object} handler.RespBody{data=schema.AddAccessTokenResp}
+// @Router /answer/api/v1/user/access-token [post]
+func (ac *AccessTokenController) AddAccessToken(ctx *gin.Context) {
+	req := &schema.AddAccessTokenReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := ac.accessTokenService.AddAccessToken(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetAccessTokenList get personal access tokens of current user
+// @Summary get personal access tokens of current user
+// @Description get personal access tokens of current user
+// @Security ApiKeyAuth
+// @Tags User
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.GetAccessTokenResp}
+// @Router /answer/api/v1/user/access-tokens [get]
+func (ac *AccessTokenController) GetAccessTokenList(ctx *gin.Context) {
+	resp, err := ac.accessTokenService.GetAccessTokenList(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RemoveAccessToken revoke personal access token
+// @Summary revoke personal access token
+// @Description revoke personal access token
+// @Security ApiKeyAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.RemoveAccessTokenReq true "RemoveAccessTokenReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/access-token [delete]
+func (ac *AccessTokenController) RemoveAccessToken(ctx *gin.Context) {
+	req := &schema.RemoveAccessTokenReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := ac.accessTokenService.RemoveAccessToken(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// AdminGetAccessTokenList get personal access tokens of user
+// @Summary get personal access tokens of user
+// @Description get personal access tokens of user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param user_id query string true "user id"
+// @Success 200 {object} handler.RespBody{data=[]schema.GetAccessTokenResp}
+// @Router /answer/admin/api/user/access-tokens [get]
+func (ac *AccessTokenController) AdminGetAccessTokenList(ctx *gin.Context) {
+	req := &schema.GetAccessTokenListReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := ac.accessTokenService.GetAccessTokenList(ctx, req.UserID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AdminRemoveAccessToken revoke personal access token of user
+// @Summary revoke personal access token of user
+// @Description revoke personal access token of user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.RemoveAccessTokenReq true "RemoveAccessTokenReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/access-token [delete]
+func (ac *AccessTokenController) AdminRemoveAccessToken(ctx *gin.Context) {
+	req := &schema.RemoveAccessTokenReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	err := ac.accessTokenService.AdminRemoveAccessToken(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -159,10 +159,10 @@ func (ac *AnswerController) Update(ctx *gin.Context) {
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
-	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := ac.rankService.CheckOperationPermissionsByObject(ctx, req.UserID, []string{
 		permission.AnswerEdit,
 		permission.AnswerEditWithoutReview,
-	})
+	}, req.ID)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
 		return
@@ -210,10 +210,10 @@ func (ac *AnswerController) AnswerList(ctx *gin.Context) {
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
-	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := ac.rankService.CheckOperationPermissionsByObject(ctx, req.UserID, []string{
 		permission.AnswerEdit,
 		permission.AnswerDelete,
-	})
+	}, req.QuestionID)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
 		return
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/middleware"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/bounty"
+	"github.com/gin-gonic/gin"
+)
+
+// BountyController question bounty controller
+type BountyController struct {
+	bountyService *bounty.BountyService
+}
+
+// NewBountyController new controller
+func NewBountyController(bountyService *bounty.BountyService) *BountyController {
+	return &BountyController{bountyService: bountyService}
+}
+
+// GetQuestionBounty get the open bounty of question
+// @Summary get the open bounty of question
+// @Description get the open bounty of question, the data is null if there is no bounty
+// @Tags Question
+// @Produce json
+// @Param question_id query string true "question id"
+// @Success 200 {object} handler.RespBody{data=schema.GetQuestionBountyResp}
+// @Router /answer/api/v1/question/bounty [get]
+func (bc *BountyController) GetQuestionBounty(ctx *gin.Context) {
+	req := &schema.GetQuestionBountyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := bc.bountyService.GetQuestionBounty(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// StartBounty offer reputation as a bounty on the question
+// @Summary offer reputation as a bounty on the question
+// @Description the amount is debited from the user immediately and the bounty is open for 7 days
+// @Security ApiKeyAuth
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Param data body schema.StartBountyReq true "StartBountyReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/question/bounty [post]
+func (bc *BountyController) StartBounty(ctx *gin.Context) {
+	req := &schema.StartBountyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := bc.bountyService.StartBounty(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// AwardBounty award the open bounty of question to the answer
+// @Summary award the open bounty of question to the answer
+// @Description only the user who offered the bounty can award it
+// @Security ApiKeyAuth
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Param data body schema.AwardBountyReq true "AwardBountyReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/question/bounty/award [post]
+func (bc *BountyController) AwardBounty(ctx *gin.Context) {
+	req := &schema.AwardBountyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := bc.bountyService.AwardBounty(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
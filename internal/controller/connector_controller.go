@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/base/translator"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	"github.com/answerdev/answer/internal/service/user_external_login"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// ConnectorController external login connector controller
+type ConnectorController struct {
+	userExternalLoginService *user_external_login.UserExternalLoginService
+	siteInfoCommonService    *siteinfo_common.SiteInfoCommonService
+}
+
+// NewConnectorController new controller
+func NewConnectorController(
+	userExternalLoginService *user_external_login.UserExternalLoginService,
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+) *ConnectorController {
+	return &ConnectorController{
+		userExternalLoginService: userExternalLoginService,
+		siteInfoCommonService:    siteInfoCommonService,
+	}
+}
+
+// ConnectorInfo get the enabled external login connectors
+// @Summary get the enabled external login connectors
+// @Description get the enabled external login connectors
+// @Tags Connector
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.ExternalLoginConnectorResp}
+// @Router /answer/api/v1/connector/info [get]
+func (cc *ConnectorController) ConnectorInfo(ctx *gin.Context) {
+	resp, err := cc.userExternalLoginService.GetExternalLoginConnectors(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// ConnectorLogin redirect the user to the login page of provider
+// @Summary redirect the user to the login page of provider
+// @Description redirect the user to the login page of provider
+// @Tags Connector
+// @Param name path string true "connector name"
+// @Success 302
+// @Router /answer/api/v1/connector/login/{name} [get]
+func (cc *ConnectorController) ConnectorLogin(ctx *gin.Context) {
+	redirectURL, state, err := cc.userExternalLoginService.ExternalLoginRedirectURL(ctx, ctx.Param("name"))
+	if err != nil {
+		cc.redirectLoginFailed(ctx, err)
+		return
+	}
+	cc.setStateCookie(ctx, state, int(constant.ExternalLoginStateCacheTime.Seconds()))
+	ctx.Redirect(http.StatusFound, redirectURL)
+}
+
+// ConnectorRedirect the callback of provider, redirect to the landing page with the one-time login code
+// @Summary the callback of provider
+// @Description the callback of provider, redirect to the landing page with the one-time login code
+// @Tags Connector
+// @Param name path string true "connector name"
+// @Param code query string false "authorization code"
+// @Param state query string false "state"
+// @Success 302
+// @Router /answer/api/v1/connector/redirect/{name} [get]
+func (cc *ConnectorController) ConnectorRedirect(ctx *gin.Context) {
+	req := &schema.ExternalLoginCallbackReq{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		cc.redirectLoginFailed(ctx, errors.BadRequest(reason.RequestFormatError))
+		return
+	}
+	req.StateCookie, _ = ctx.Cookie(constant.ExternalLoginStateCookie)
+	cc.setStateCookie(ctx, "", -1)
+	resp, err := cc.userExternalLoginService.ExternalLogin(ctx, ctx.Param("name"), req)
+	if err != nil {
+		cc.redirectLoginFailed(ctx, err)
+		return
+	}
+	cc.redirectLoginSucceed(ctx, resp)
+}
+
+// ConnectorToken exchange the one-time login code for the access token
+// @Summary exchange the one-time login code for the access token
+// @Description the landing page exchanges the code in the url for the access token after the external login
+// @Tags Connector
+// @Accept json
+// @Produce json
+// @Param data body schema.ExternalLoginTokenReq true "ExternalLoginTokenReq"
+// @Success 200 {object} handler.RespBody{data=schema.ExternalLoginTokenResp}
+// @Router /answer/api/v1/connector/token [post]
+func (cc *ConnectorController) ConnectorToken(ctx *gin.Context) {
+	req := &schema.ExternalLoginTokenReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := cc.userExternalLoginService.ExchangeLoginCode(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// SAMLMetadata get the metadata of SAML service provider
+// @Summary get the metadata of SAML service provider
+// @Description get the metadata of SAML service provider, it is imported by the IdP
+// @Tags Connector
+// @Produce xml
+// @Success 200 {string} string
+// @Router /answer/api/v1/saml/metadata [get]
+func (cc *ConnectorController) SAMLMetadata(ctx *gin.Context) {
+	metadata, err := cc.userExternalLoginService.SAMLMetadata(ctx)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	ctx.Data(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+// SAMLLogin redirect the user to the IdP with the signed AuthnRequest
+// @Summary redirect the user to the IdP with the signed AuthnRequest
+// @Description redirect the user to the IdP with the signed AuthnRequest
+// @Tags Connector
+// @Success 302
+// @Router /answer/api/v1/saml/login [get]
+func (cc *ConnectorController) SAMLLogin(ctx *gin.Context) {
+	redirectURL, requestID, err := cc.userExternalLoginService.SAMLLoginRedirectURL(ctx)
+	if err != nil {
+		cc.redirectLoginFailed(ctx, err)
+		return
+	}
+	cc.setSAMLRequestIDCookie(ctx, requestID, int(constant.ExternalLoginStateCacheTime.Seconds()))
+	ctx.Redirect(http.StatusFound, redirectURL)
+}
+
+// SAMLACS the assertion consumer service, redirect to the landing page with the one-time login code
+// @Summary the assertion consumer service
+// @Description the assertion consumer service, redirect to the landing page with the one-time login code
+// @Tags Connector
+// @Accept x-www-form-urlencoded
+// @Param SAMLResponse formData string true "base64 encoded saml response"
+// @Param RelayState formData string false "relay state"
+// @Success 302
+// @Router /answer/api/v1/saml/acs [post]
+func (cc *ConnectorController) SAMLACS(ctx *gin.Context) {
+	req := &schema.SAMLACSReq{}
+	if err := ctx.ShouldBind(req); err != nil {
+		cc.redirectLoginFailed(ctx, errors.BadRequest(reason.RequestFormatError))
+		return
+	}
+	req.RequestIDCookie, _ = ctx.Cookie(constant.SAMLRequestIDCookie)
+	cc.setSAMLRequestIDCookie(ctx, "", -1)
+	resp, err := cc.userExternalLoginService.SAMLLogin(ctx, req)
+	if err != nil {
+		cc.redirectLoginFailed(ctx, err)
+		return
+	}
+	cc.redirectLoginSucceed(ctx, resp)
+}
+
+// redirectLoginSucceed redirect to the landing page with the one-time login code, or to the login page
+// if the two-factor code should be verified
+func (cc *ConnectorController) redirectLoginSucceed(ctx *gin.Context, resp *schema.GetUserResp) {
+	if len(resp.TwoFactorToken) > 0 {
+		ctx.Redirect(http.StatusFound, fmt.Sprintf("%s/users/login?two_factor_token=%s&two_factor_enroll_required=%t",
+			cc.getSiteUrl(ctx), url.QueryEscape(resp.TwoFactorToken), resp.TwoFactorEnrollRequired))
+		return
+	}
+	code, err := cc.userExternalLoginService.NewLoginCode(ctx, resp.AccessToken)
+	if err != nil {
+		cc.redirectLoginFailed(ctx, err)
+		return
+	}
+	ctx.Redirect(http.StatusFound, cc.getSiteUrl(ctx)+"/users/auth-landing?code="+url.QueryEscape(code))
+}
+
+// setStateCookie save the state in the browser, the cookie is sent back with the redirect of provider
+// because it is a top-level navigation, and it is removed if the max age is negative
+func (cc *ConnectorController) setStateCookie(ctx *gin.Context, state string, maxAge int) {
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(constant.ExternalLoginStateCookie, state, maxAge, "/answer/api/v1/connector/", "",
+		strings.HasPrefix(cc.getSiteUrl(ctx), "https://"), true)
+}
+
+// setSAMLRequestIDCookie save the id of AuthnRequest in the browser, the IdP posts the response back
+// from another site, so the cookie must be SameSite=None which is only accepted with Secure
+func (cc *ConnectorController) setSAMLRequestIDCookie(ctx *gin.Context, requestID string, maxAge int) {
+	ctx.SetSameSite(http.SameSiteNoneMode)
+	ctx.SetCookie(constant.SAMLRequestIDCookie, requestID, maxAge, "/answer/api/v1/saml/", "", true, true)
+}
+
+// redirectLoginFailed redirect to the login page with the error message
+func (cc *ConnectorController) redirectLoginFailed(ctx *gin.Context, err error) {
+	msgReason := reason.ExternalLoginFailed
+	if myErr, ok := err.(*errors.Error); ok {
+		if errors.IsInternalServer(myErr) {
+			log.Error(myErr)
+		} else {
+			log.Warnf("external login failed: %v", myErr)
+			msgReason = myErr.Reason
+		}
+	} else {
+		log.Error(err)
+	}
+	msg := translator.GlobalTrans.Tr(handler.GetLang(ctx), msgReason)
+	ctx.Redirect(http.StatusFound, cc.getSiteUrl(ctx)+"/users/login?external_login_error="+url.QueryEscape(msg))
+}
+
+func (cc *ConnectorController) getSiteUrl(ctx *gin.Context) string {
+	siteGeneral, err := cc.siteInfoCommonService.GetSiteGeneral(ctx)
+	if err != nil {
+		log.Errorf("get site general failed: %s", err)
+		return ""
+	}
+	return strings.TrimSuffix(siteGeneral.SiteUrl, "/")
+}
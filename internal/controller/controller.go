@@ -24,4 +24,12 @@ var ProviderSetController = wire.NewSet(
 	NewUploadController,
 	NewActivityController,
 	NewTemplateController,
+	NewConnectorController,
+	NewTwoFactorController,
+	NewAccessTokenController,
+	NewEmailDigestController,
+	NewBountyController,
+	NewPendingPostController,
+	NewQuestionDuplicateController,
+	NewPostLockController,
 )
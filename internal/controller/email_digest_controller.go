@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/middleware"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/email_digest"
+	"github.com/gin-gonic/gin"
+)
+
+// EmailDigestController email delivery preferences controller
+type EmailDigestController struct {
+	emailDigestService *email_digest.EmailDigestService
+}
+
+// NewEmailDigestController new controller
+func NewEmailDigestController(emailDigestService *email_digest.EmailDigestService) *EmailDigestController {
+	return &EmailDigestController{emailDigestService: emailDigestService}
+}
+
+// GetUserNoticeConfig get the email delivery preferences of current user
+// @Summary get the email delivery preferences of current user
+// @Description get the email delivery frequency of each notification type
+// @Security ApiKeyAuth
+// @Tags User
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.GetUserNoticeConfigResp}
+// @Router /answer/api/v1/user/notice/config [get]
+func (ec *EmailDigestController) GetUserNoticeConfig(ctx *gin.Context) {
+	resp, err := ec.emailDigestService.GetUserNoticeConfig(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateUserNoticeConfig update the email delivery preferences of current user
+// @Summary update the email delivery preferences of current user
+// @Description set each notification type to immediate, daily digest, weekly digest or off
+// @Security ApiKeyAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateUserNoticeConfigReq true "UpdateUserNoticeConfigReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/notice/config [put]
+func (ec *EmailDigestController) UpdateUserNoticeConfig(ctx *gin.Context) {
+	req := &schema.UpdateUserNoticeConfigReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := ec.emailDigestService.UpdateUserNoticeConfig(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -1,29 +1,40 @@
 package controller
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/answerdev/answer/internal/base/handler"
 	"github.com/answerdev/answer/internal/base/middleware"
 	"github.com/answerdev/answer/internal/schema"
 	"github.com/answerdev/answer/internal/service/notification"
 	"github.com/answerdev/answer/internal/service/permission"
 	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/answerdev/answer/internal/service/stream"
 	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/log"
 )
 
+// streamHeartbeatInterval the interval of heartbeat of notification stream
+const streamHeartbeatInterval = 30 * time.Second
+
 // NotificationController notification controller
 type NotificationController struct {
 	notificationService *notification.NotificationService
 	rankService         *rank.RankService
+	streamService       *stream.StreamService
 }
 
 // NewNotificationController new controller
 func NewNotificationController(
 	notificationService *notification.NotificationService,
 	rankService *rank.RankService,
+	streamService *stream.StreamService,
 ) *NotificationController {
 	return &NotificationController{
 		notificationService: notificationService,
 		rankService:         rankService,
+		streamService:       streamService,
 	}
 }
 
@@ -37,27 +48,100 @@ func NewNotificationController(
 // @Success 200 {object} handler.RespBody
 // @Router /answer/api/v1/notification/status [get]
 func (nc *NotificationController) GetRedDot(ctx *gin.Context) {
+	req, err := nc.getRedDotReq(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
 
-	req := &schema.GetRedDot{}
+	RedDot, err := nc.notificationService.GetRedDot(ctx, req)
+	handler.HandleResponse(ctx, err, RedDot)
+}
 
-	userID := middleware.GetLoginUserIDFromContext(ctx)
-	req.UserID = userID
-	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+func (nc *NotificationController) getRedDotReq(ctx *gin.Context, userID string) (*schema.GetRedDot, error) {
+	req := &schema.GetRedDot{UserID: userID}
 	canList, err := nc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
 		permission.QuestionAudit,
 		permission.AnswerAudit,
 		permission.TagAudit,
 	})
 	if err != nil {
-		handler.HandleResponse(ctx, err, nil)
-		return
+		return nil, err
 	}
 	req.CanReviewQuestion = canList[0]
 	req.CanReviewAnswer = canList[1]
 	req.CanReviewTag = canList[2]
+	return req, nil
+}
 
-	RedDot, err := nc.notificationService.GetRedDot(ctx, req)
-	handler.HandleResponse(ctx, err, RedDot)
+// GetStream push the notification events
+// @Summary push the notification events
+// @Description push the red dot counts, new notifications and the vote and answer counts of the question being viewed by Server-Sent Events.
+// @Description The token can be passed by the Authorization query parameter, because EventSource can not set the header.
+// @Tags Notification
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Param question_id query string false "the question being viewed"
+// @Success 200 {string} string "event stream"
+// @Router /answer/api/v1/notification/stream [get]
+func (nc *NotificationController) GetStream(ctx *gin.Context) {
+	req := &schema.GetNotificationStreamReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	redDotReq, err := nc.getRedDotReq(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+
+	keys := []string{stream.UserKey(redDotReq.UserID)}
+	if redDotReq.CanReviewQuestion || redDotReq.CanReviewAnswer || redDotReq.CanReviewTag {
+		keys = append(keys, stream.ReviewKey)
+	}
+	if len(req.QuestionID) > 0 {
+		keys = append(keys, stream.QuestionKey(req.QuestionID))
+	}
+	client := nc.streamService.Subscribe(keys...)
+	defer nc.streamService.Unsubscribe(client)
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// disable the buffering of nginx
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	sendRedDot := func() {
+		redDot, err := nc.notificationService.GetRedDot(ctx, redDotReq)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		ctx.SSEvent(stream.EventRedDot, redDot)
+		ctx.Writer.Flush()
+	}
+	sendRedDot()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			// the comment line keeps the connection alive through the proxies
+			_, _ = ctx.Writer.WriteString(": ping\n\n")
+			ctx.Writer.Flush()
+		case event := <-client.Events():
+			if event.Event == stream.EventRedDot {
+				sendRedDot()
+				continue
+			}
+			ctx.SSEvent(event.Event, event.Data)
+			ctx.Writer.Flush()
+		}
+	}
 }
 
 // ClearRedDot
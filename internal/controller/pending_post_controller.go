@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/middleware"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/gin-gonic/gin"
+)
+
+// PendingPostController pending post controller
+type PendingPostController struct {
+	pendingPostService *service.PendingPostService
+	rankService        *rank.RankService
+}
+
+// NewPendingPostController new controller
+func NewPendingPostController(
+	pendingPostService *service.PendingPostService,
+	rankService *rank.RankService,
+) *PendingPostController {
+	return &PendingPostController{
+		pendingPostService: pendingPostService,
+		rankService:        rankService,
+	}
+}
+
+// GetPendingPostPage godoc
+// @Summary get the page of the posts waiting for review
+// @Description get the page of the posts held by the pre-moderation policy
+// @Tags Revision
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetPendingPostResp}}
+// @Router /answer/api/v1/pending-posts [get]
+func (pc *PendingPostController) GetPendingPostPage(ctx *gin.Context) {
+	req := &schema.GetPendingPostPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	actions := []string{
+		permission.QuestionAudit,
+		permission.AnswerAudit,
+	}
+	canList, err := pc.rankService.CheckOperationPermissions(ctx, req.UserID, actions)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.CanReviewQuestion = canList[0]
+	req.CanReviewAnswer = canList[1]
+	canList, err = pc.rankService.CheckOperationPermissionsInAnyTag(ctx, req.UserID, actions)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.CanReviewQuestionInTags = canList[0]
+	req.CanReviewAnswerInTags = canList[1]
+
+	resp, err := pc.pendingPostService.GetPendingPostPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// ReviewPendingPost godoc
+// @Summary review pending post
+// @Description review pending post operation: approve or reject, the author is notified of the result
+// @Tags Revision
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.ReviewPendingPostReq true "review"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/api/v1/pending-posts/review [put]
+func (pc *PendingPostController) ReviewPendingPost(ctx *gin.Context) {
+	req := &schema.ReviewPendingPostReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	objectID, err := pc.pendingPostService.GetPendingPostObjectID(ctx, req.ID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	canList, err := pc.rankService.CheckOperationPermissionsByObject(ctx, req.UserID, []string{
+		permission.QuestionAudit,
+		permission.AnswerAudit,
+	}, objectID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.CanReviewQuestion = canList[0]
+	req.CanReviewAnswer = canList[1]
+
+	err = pc.pendingPostService.ReviewPendingPost(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/middleware"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/post_lock"
+	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/answerdev/answer/pkg/obj"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// PostLockController post lock controller
+type PostLockController struct {
+	postLockService *post_lock.PostLockService
+	rankService     *rank.RankService
+}
+
+// NewPostLockController new controller
+func NewPostLockController(
+	postLockService *post_lock.PostLockService,
+	rankService *rank.RankService,
+) *PostLockController {
+	return &PostLockController{
+		postLockService: postLockService,
+		rankService:     rankService,
+	}
+}
+
+// LockPost lock the question or answer
+// @Summary lock the question or answer
+// @Description the locked post can not be edited, voted, answered or commented, the lock is released after the duration in hours if it is set
+// @Security ApiKeyAuth
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Param data body schema.LockPostReq true "LockPostReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/post/lock [put]
+func (pc *PostLockController) LockPost(ctx *gin.Context) {
+	req := &schema.LockPostReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	if !pc.checkLockPermission(ctx, req.UserID, req.ObjectID) {
+		return
+	}
+	err := pc.postLockService.LockPost(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UnlockPost unlock the question or answer
+// @Summary unlock the question or answer
+// @Description unlock the question or answer
+// @Security ApiKeyAuth
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Param data body schema.UnlockPostReq true "UnlockPostReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/post/unlock [put]
+func (pc *PostLockController) UnlockPost(ctx *gin.Context) {
+	req := &schema.UnlockPostReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	if !pc.checkLockPermission(ctx, req.UserID, req.ObjectID) {
+		return
+	}
+	err := pc.postLockService.UnlockPost(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// ProtectQuestion protect the question
+// @Summary protect the question
+// @Description only the users with enough reputation can answer the protected question, the protection is released after the duration in hours if it is set
+// @Security ApiKeyAuth
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Param data body schema.ProtectQuestionReq true "ProtectQuestionReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/question/protect [put]
+func (pc *PostLockController) ProtectQuestion(ctx *gin.Context) {
+	req := &schema.ProtectQuestionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	if !pc.checkPermission(ctx, req.UserID, permission.QuestionProtect, req.QuestionID) {
+		return
+	}
+	err := pc.postLockService.ProtectQuestion(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UnprotectQuestion unprotect the question
+// @Summary unprotect the question
+// @Description unprotect the question
+// @Security ApiKeyAuth
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Param data body schema.UnprotectQuestionReq true "UnprotectQuestionReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/question/unprotect [put]
+func (pc *PostLockController) UnprotectQuestion(ctx *gin.Context) {
+	req := &schema.UnprotectQuestionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	if !pc.checkPermission(ctx, req.UserID, permission.QuestionProtect, req.QuestionID) {
+		return
+	}
+	err := pc.postLockService.UnprotectQuestion(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// checkLockPermission check the user can lock the post, the permission depends on the type of the post
+func (pc *PostLockController) checkLockPermission(ctx *gin.Context, userID, objectID string) bool {
+	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		handler.HandleResponse(ctx, errors.BadRequest(reason.ObjectCannotLock), nil)
+		return false
+	}
+	switch objectType {
+	case constant.QuestionObjectType:
+		return pc.checkPermission(ctx, userID, permission.QuestionLock, objectID)
+	case constant.AnswerObjectType:
+		return pc.checkPermission(ctx, userID, permission.AnswerLock, objectID)
+	}
+	handler.HandleResponse(ctx, errors.BadRequest(reason.ObjectCannotLock), nil)
+	return false
+}
+
+// checkPermission check the user has the permission on the object including the powers scoped to its tags,
+// the response is written if the user does not have it
+func (pc *PostLockController) checkPermission(ctx *gin.Context, userID, action, objectID string) bool {
+	can, err := pc.rankService.CheckOperationPermissionByObject(ctx, userID, action, objectID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return false
+	}
+	if !can {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return false
+	}
+	return true
+}
@@ -74,12 +74,14 @@ func (qc *QuestionController) CloseQuestion(ctx *gin.Context) {
 		return
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	can, err := qc.rankService.CheckOperationPermission(ctx, req.UserID, permission.QuestionClose, "")
+	can, err := qc.rankService.CheckOperationPermissionsByObject(ctx, req.UserID, []string{
+		permission.QuestionClose,
+	}, req.ID)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
 		return
 	}
-	if !can {
+	if !can[0] {
 		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
 		return
 	}
@@ -104,12 +106,14 @@ func (qc *QuestionController) ReopenQuestion(ctx *gin.Context) {
 		return
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	can, err := qc.rankService.CheckOperationPermission(ctx, req.UserID, permission.QuestionReopen, "")
+	can, err := qc.rankService.CheckOperationPermissionsByObject(ctx, req.UserID, []string{
+		permission.QuestionReopen,
+	}, req.QuestionID)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
 		return
 	}
-	if !can {
+	if !can[0] {
 		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
 		return
 	}
@@ -132,12 +136,13 @@ func (qc *QuestionController) GetQuestion(ctx *gin.Context) {
 	id := ctx.Query("id")
 	userID := middleware.GetLoginUserIDFromContext(ctx)
 	req := schema.QuestionPermission{}
-	canList, err := qc.rankService.CheckOperationPermissions(ctx, userID, []string{
+	canList, err := qc.rankService.CheckOperationPermissionsByObject(ctx, userID, []string{
 		permission.QuestionEdit,
 		permission.QuestionDelete,
 		permission.QuestionClose,
 		permission.QuestionReopen,
-	})
+		permission.QuestionAudit,
+	}, id)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
 		return
@@ -148,6 +153,7 @@ func (qc *QuestionController) GetQuestion(ctx *gin.Context) {
 	req.CanDelete = canList[1]
 	req.CanClose = canList[2]
 	req.CanReopen = canList[3]
+	req.CanReview = canList[4]
 
 	info, err := qc.questionService.GetQuestionAndAddPV(ctx, id, userID, req)
 	if err != nil {
@@ -292,12 +298,12 @@ func (qc *QuestionController) UpdateQuestion(ctx *gin.Context) {
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
-	canList, err := qc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := qc.rankService.CheckOperationPermissionsByObject(ctx, req.UserID, []string{
 		permission.QuestionEdit,
 		permission.QuestionDelete,
 		permission.QuestionEditWithoutReview,
 		permission.TagUseReservedTag,
-	})
+	}, req.ID)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
 		return
@@ -530,6 +536,7 @@ func (qc *QuestionController) AdminSetQuestionStatus(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
-	err := qc.questionService.AdminSetQuestionStatus(ctx, req.QuestionID, req.StatusStr)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := qc.questionService.AdminSetQuestionStatus(ctx, req)
 	handler.HandleResponse(ctx, err, gin.H{})
 }
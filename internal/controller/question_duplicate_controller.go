@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/middleware"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/question_duplicate"
+	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// QuestionDuplicateController question duplicate controller
+type QuestionDuplicateController struct {
+	questionDuplicateService *question_duplicate.QuestionDuplicateService
+	rankService              *rank.RankService
+}
+
+// NewQuestionDuplicateController new controller
+func NewQuestionDuplicateController(
+	questionDuplicateService *question_duplicate.QuestionDuplicateService,
+	rankService *rank.RankService,
+) *QuestionDuplicateController {
+	return &QuestionDuplicateController{
+		questionDuplicateService: questionDuplicateService,
+		rankService:              rankService,
+	}
+}
+
+// MergeQuestion merge the duplicate question into the original question
+// @Summary merge the duplicate question into the original question
+// @Description the answers, comments and followers are moved to the original question, the duplicate question is deleted and redirects to the original one
+// @Security ApiKeyAuth
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Param data body schema.MergeQuestionReq true "MergeQuestionReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/question/merge [put]
+func (qc *QuestionDuplicateController) MergeQuestion(ctx *gin.Context) {
+	req := &schema.MergeQuestionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	// the power scoped to tags must cover both questions
+	for _, questionID := range []string{req.QuestionID, req.DuplicateOfID} {
+		can, err := qc.rankService.CheckOperationPermissionByObject(ctx, req.UserID, permission.QuestionMerge, questionID)
+		if err != nil {
+			handler.HandleResponse(ctx, err, nil)
+			return
+		}
+		if !can {
+			handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+			return
+		}
+	}
+
+	err := qc.questionDuplicateService.MergeQuestion(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -102,11 +102,16 @@ func (rc *RevisionController) RevisionAudit(ctx *gin.Context) {
 		return
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, err := rc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	objectID, err := rc.revisionListService.GetRevisionObjectID(ctx, req.ID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	canList, err := rc.rankService.CheckOperationPermissionsByObject(ctx, req.UserID, []string{
 		permission.QuestionAudit,
 		permission.AnswerAudit,
 		permission.TagAudit,
-	})
+	}, objectID)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
 		return
@@ -220,6 +220,15 @@ func (tc *TemplateController) QuestionInfo(ctx *gin.Context) {
 	}
 
 	siteInfo := tc.SiteInfo(ctx)
+	// the merged question redirects to the question it is merged into
+	if detail.Merged && detail.DuplicateOf != nil {
+		url := fmt.Sprintf("%s/questions/%s", siteInfo.General.SiteUrl, detail.DuplicateOf.ID)
+		if siteInfo.SiteSeo.PermaLink != schema.PermaLinkQuestionID {
+			url = fmt.Sprintf("%s/%s", url, detail.DuplicateOf.UrlTitle)
+		}
+		ctx.Redirect(http.StatusMovedPermanently, url)
+		return
+	}
 	jump, jumpurl := tc.QuestionInfoeRdirect(ctx, siteInfo, correctTitle)
 	if jump {
 		ctx.Redirect(http.StatusFound, jumpurl)
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/middleware"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service"
+	"github.com/answerdev/answer/internal/service/two_factor"
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorController two-factor authentication controller
+type TwoFactorController struct {
+	userService      *service.UserService
+	twoFactorService *two_factor.TwoFactorService
+}
+
+// NewTwoFactorController new controller
+func NewTwoFactorController(
+	userService *service.UserService,
+	twoFactorService *two_factor.TwoFactorService,
+) *TwoFactorController {
+	return &TwoFactorController{
+		userService:      userService,
+		twoFactorService: twoFactorService,
+	}
+}
+
+// TwoFactorLogin verify the two-factor code to finish login
+// @Summary verify the two-factor code to finish login
+// @Description verify the TOTP code or recovery code with the two_factor_token returned by login
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.TwoFactorLoginReq true "TwoFactorLoginReq"
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorLoginResp}
+// @Router /answer/api/v1/user/login/2fa [post]
+func (tc *TwoFactorController) TwoFactorLogin(ctx *gin.Context) {
+	req := &schema.TwoFactorLoginReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := tc.userService.TwoFactorLogin(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// TwoFactorLoginEnroll enroll two-factor authentication during login
+// @Summary enroll two-factor authentication during login
+// @Description the user whose role requires two-factor authentication enrolls with the two_factor_token returned by login
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.TwoFactorLoginEnrollReq true "TwoFactorLoginEnrollReq"
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorEnrollResp}
+// @Router /answer/api/v1/user/login/2fa/enroll [post]
+func (tc *TwoFactorController) TwoFactorLoginEnroll(ctx *gin.Context) {
+	req := &schema.TwoFactorLoginEnrollReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := tc.twoFactorService.LoginEnroll(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetTwoFactorStatus get the two-factor authentication status of current user
+// @Summary get the two-factor authentication status of current user
+// @Description get the two-factor authentication status of current user
+// @Security ApiKeyAuth
+// @Tags User
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorStatusResp}
+// @Router /answer/api/v1/user/2fa [get]
+func (tc *TwoFactorController) GetTwoFactorStatus(ctx *gin.Context) {
+	resp, err := tc.twoFactorService.GetStatus(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// TwoFactorEnroll generate a new secret for the authenticator app
+// @Summary generate a new secret for the authenticator app
+// @Description generate a new secret, it is enabled after the code is verified
+// @Security ApiKeyAuth
+// @Tags User
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorEnrollResp}
+// @Router /answer/api/v1/user/2fa/enroll [post]
+func (tc *TwoFactorController) TwoFactorEnroll(ctx *gin.Context) {
+	resp, err := tc.twoFactorService.Enroll(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// TwoFactorEnable verify the code of new secret and enable two-factor authentication
+// @Summary enable two-factor authentication
+// @Description verify the code of new secret and enable two-factor authentication, the recovery codes are only returned once
+// @Security ApiKeyAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.TwoFactorCodeReq true "TwoFactorCodeReq"
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorRecoveryCodesResp}
+// @Router /answer/api/v1/user/2fa/enable [post]
+func (tc *TwoFactorController) TwoFactorEnable(ctx *gin.Context) {
+	req := &schema.TwoFactorCodeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.twoFactorService.Enable(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// TwoFactorDisable disable two-factor authentication
+// @Summary disable two-factor authentication
+// @Description disable two-factor authentication, it can not be disabled if it is required by the role of user
+// @Security ApiKeyAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.TwoFactorCodeReq true "TwoFactorCodeReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/2fa [delete]
+func (tc *TwoFactorController) TwoFactorDisable(ctx *gin.Context) {
+	req := &schema.TwoFactorCodeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := tc.twoFactorService.Disable(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RegenerateRecoveryCodes regenerate the recovery codes
+// @Summary regenerate the recovery codes
+// @Description regenerate the recovery codes, the old codes can not be used anymore
+// @Security ApiKeyAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.TwoFactorCodeReq true "TwoFactorCodeReq"
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorRecoveryCodesResp}
+// @Router /answer/api/v1/user/2fa/recovery-codes [post]
+func (tc *TwoFactorController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	req := &schema.TwoFactorCodeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.twoFactorService.RegenerateRecoveryCodes(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
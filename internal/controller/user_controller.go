@@ -120,6 +120,10 @@ func (uc *UserController) UserEmailLogin(ctx *gin.Context) {
 	}
 
 	resp, err := uc.userService.EmailLogin(ctx, req)
+	if myErr, ok := err.(*errors.Error); ok && errors.IsForbidden(myErr) {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
 	if err != nil {
 		_, _ = uc.actionService.ActionRecordAdd(ctx, schema.ActionRecordTypeLogin, ctx.ClientIP())
 		errFields := append([]*validator.FormErrorField{}, &validator.FormErrorField{
@@ -226,6 +230,10 @@ func (uc *UserController) UserRegisterByEmail(ctx *gin.Context) {
 		handler.HandleResponse(ctx, errors.BadRequest(reason.NotAllowedRegistration), nil)
 		return
 	}
+	if siteInfo.DisallowPasswordLogin {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.PasswordLoginDisabled), nil)
+		return
+	}
 
 	req := &schema.UserRegisterReq{}
 	if handler.BindAndCheck(ctx, req) {
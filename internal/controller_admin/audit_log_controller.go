@@ -0,0 +1,85 @@
+package controller_admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHasMoreHeader the header of export response, it is true if there are older logs in the next page
+const ExportHasMoreHeader = "X-Answer-Export-Has-More"
+
+// AuditLogController audit log controller
+type AuditLogController struct {
+	auditLogService *audit_log.AuditLogService
+}
+
+// NewAuditLogController new controller
+func NewAuditLogController(auditLogService *audit_log.AuditLogService) *AuditLogController {
+	return &AuditLogController{auditLogService: auditLogService}
+}
+
+// GetAuditLogPage get audit log page
+// @Summary get audit log page
+// @Description get audit log page, the latest first
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Param username query string false "username of the user who did the action"
+// @Param action query string false "action" Enums(question.status, question.merge, question.lock, question.protect, answer.status, answer.lock, user.status, user.role, report.handle, revision.audit, post.review, siteinfo.update, config.update, badge.award, badge.revoke, user.two_factor_reset, role.add, role.update, role.power, webhook.add, webhook.update, webhook.remove)
+// @Param object_type query string false "object type"
+// @Param object_id query string false "object id"
+// @Param start_time query int false "start time, unix timestamp"
+// @Param end_time query int false "end time, unix timestamp"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetAuditLogResp}}
+// @Router /answer/admin/api/audit-logs/page [get]
+func (ac *AuditLogController) GetAuditLogPage(ctx *gin.Context) {
+	req := &schema.GetAuditLogPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := ac.auditLogService.GetAuditLogPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// ExportAuditLog export audit log
+// @Summary export audit log
+// @Description export the audit logs matching the filters as csv, the latest first, 10000 logs per page.
+// @Description The header X-Answer-Export-Has-More is true if there are older logs in the next page.
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce text/csv
+// @Param username query string false "username of the user who did the action"
+// @Param action query string false "action"
+// @Param object_type query string false "object type"
+// @Param object_id query string false "object id"
+// @Param start_time query int false "start time, unix timestamp"
+// @Param end_time query int false "end time, unix timestamp"
+// @Param page query int false "page"
+// @Success 200 {file} file
+// @Router /answer/admin/api/audit-logs/export [get]
+func (ac *AuditLogController) ExportAuditLog(ctx *gin.Context) {
+	req := &schema.GetAuditLogPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	content, hasMore, err := ac.auditLogService.ExportAuditLog(ctx, req)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	ctx.Header(ExportHasMoreHeader, strconv.FormatBool(hasMore))
+	filename := fmt.Sprintf("audit-log-%s.csv", time.Now().Format("20060102150405"))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+}
@@ -0,0 +1,136 @@
+package controller_admin
+
+import (
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/middleware"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/badge"
+	"github.com/gin-gonic/gin"
+)
+
+// BadgeController badge controller
+type BadgeController struct {
+	badgeService *badge.BadgeService
+}
+
+// NewBadgeController new controller
+func NewBadgeController(badgeService *badge.BadgeService) *BadgeController {
+	return &BadgeController{badgeService: badgeService}
+}
+
+// GetBadgeList get badge list
+// @Summary get badge list
+// @Description get badge list with the number of times each badge has been awarded
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.GetBadgeResp}
+// @Router /answer/admin/api/badges [get]
+func (bc *BadgeController) GetBadgeList(ctx *gin.Context) {
+	resp, err := bc.badgeService.GetBadgeList(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddBadge add badge
+// @Summary add badge
+// @Description add badge
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AddBadgeReq true "AddBadgeReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/badge [post]
+func (bc *BadgeController) AddBadge(ctx *gin.Context) {
+	req := &schema.AddBadgeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	err := bc.badgeService.AddBadge(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateBadge update badge
+// @Summary update badge
+// @Description update badge, the badges awarded before are kept
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateBadgeReq true "UpdateBadgeReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/badge [put]
+func (bc *BadgeController) UpdateBadge(ctx *gin.Context) {
+	req := &schema.UpdateBadgeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	err := bc.badgeService.UpdateBadge(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetUserBadgePage get the awarded badges
+// @Summary get the awarded badges
+// @Description get the awarded badges, the newest first
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param badge_id query string false "badge id"
+// @Param username query string false "username"
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetUserBadgeResp}}
+// @Router /answer/admin/api/user-badges/page [get]
+func (bc *BadgeController) GetUserBadgePage(ctx *gin.Context) {
+	req := &schema.GetUserBadgePageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := bc.badgeService.GetUserBadgePage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AwardBadge award badge to user
+// @Summary award badge to user
+// @Description award badge to user manually
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AwardBadgeReq true "AwardBadgeReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user-badge [post]
+func (bc *BadgeController) AwardBadge(ctx *gin.Context) {
+	req := &schema.AwardBadgeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := bc.badgeService.AwardBadge(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RevokeBadge revoke the badge awarded to user
+// @Summary revoke the badge awarded to user
+// @Description revoke the badge awarded to user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.RevokeBadgeReq true "RevokeBadgeReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user-badge [delete]
+func (bc *BadgeController) RevokeBadge(ctx *gin.Context) {
+	req := &schema.RevokeBadgeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := bc.badgeService.RevokeBadge(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -9,4 +9,8 @@ var ProviderSetController = wire.NewSet(
 	NewThemeController,
 	NewSiteInfoController,
 	NewRoleController,
+	NewWebhookController,
+	NewBadgeController,
+	NewAuditLogController,
+	NewSpamController,
 )
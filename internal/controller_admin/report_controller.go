@@ -2,6 +2,7 @@ package controller_admin
 
 import (
 	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/middleware"
 	"github.com/answerdev/answer/internal/schema"
 	"github.com/answerdev/answer/internal/service/report_admin"
 	"github.com/answerdev/answer/pkg/converter"
@@ -72,6 +73,7 @@ func (rc *ReportController) Handle(ctx *gin.Context) {
 		return
 	}
 
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 	err := rc.reportService.HandleReported(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
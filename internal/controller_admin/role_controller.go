@@ -9,12 +9,19 @@ import (
 
 // RoleController role controller
 type RoleController struct {
-	roleService *service.RoleService
+	roleService      *service.RoleService
+	rolePowerService *service.RolePowerRelService
 }
 
 // NewRoleController new controller
-func NewRoleController(roleService *service.RoleService) *RoleController {
-	return &RoleController{roleService: roleService}
+func NewRoleController(
+	roleService *service.RoleService,
+	rolePowerService *service.RolePowerRelService,
+) *RoleController {
+	return &RoleController{
+		roleService:      roleService,
+		rolePowerService: rolePowerService,
+	}
 }
 
 // GetRoleList get role list
@@ -32,3 +39,97 @@ func (rc *RoleController) GetRoleList(ctx *gin.Context) {
 	resp, err := rc.roleService.GetRoleList(ctx)
 	handler.HandleResponse(ctx, err, resp)
 }
+
+// AddRole add role
+// @Summary add role
+// @Description add a custom role without any power
+// @Tags admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.AddRoleReq true "role"
+// @Success 200 {object} handler.RespBody{data=schema.GetRoleResp}
+// @Router /answer/admin/api/role [post]
+func (rc *RoleController) AddRole(ctx *gin.Context) {
+	req := &schema.AddRoleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := rc.roleService.AddRole(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateRole update role
+// @Summary update role
+// @Description rename role and update its description
+// @Tags admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateRoleReq true "role"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/role [put]
+func (rc *RoleController) UpdateRole(ctx *gin.Context) {
+	req := &schema.UpdateRoleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := rc.roleService.UpdateRole(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveRole remove role
+// @Summary remove role
+// @Description remove the custom role which is not assigned to any user
+// @Tags admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.RemoveRoleReq true "role"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/role [delete]
+func (rc *RoleController) RemoveRole(ctx *gin.Context) {
+	req := &schema.RemoveRoleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := rc.roleService.RemoveRole(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetRolePowerList get role power list
+// @Summary get role power list
+// @Description get all powers and whether the role has them, everywhere or in some tags
+// @Tags admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param role_id query int true "role id"
+// @Success 200 {object} handler.RespBody{data=[]schema.GetRolePowerResp}
+// @Router /answer/admin/api/role/powers [get]
+func (rc *RoleController) GetRolePowerList(ctx *gin.Context) {
+	req := &schema.GetRolePowerListReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := rc.rolePowerService.GetRolePowerMatrix(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateRolePower update role power
+// @Summary update role power
+// @Description replace all powers of role, the power without tags works everywhere
+// @Tags admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateRolePowerReq true "powers"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/role/powers [put]
+func (rc *RoleController) UpdateRolePower(ctx *gin.Context) {
+	req := &schema.UpdateRolePowerReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := rc.rolePowerService.UpdateRolePower(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
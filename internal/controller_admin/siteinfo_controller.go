@@ -113,6 +113,84 @@ func (sc *SiteInfoController) GetSiteLogin(ctx *gin.Context) {
 	handler.HandleResponse(ctx, err, resp)
 }
 
+// GetSiteLoginConnector get site info login connector config
+// @Summary get site info login connector config
+// @Description get site info login connector config
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteLoginConnectorResp}
+// @Router /answer/admin/api/siteinfo/login-connector [get]
+func (sc *SiteInfoController) GetSiteLoginConnector(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteLoginConnector(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteLoginSAML get site info login SAML config
+// @Summary get site info login SAML config
+// @Description get site info login SAML config, the private key of SP is not returned
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteLoginSAMLResp}
+// @Router /answer/admin/api/siteinfo/login-saml [get]
+func (sc *SiteInfoController) GetSiteLoginSAML(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteLoginSAML(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteLoginLDAP get site info login LDAP config
+// @Summary get site info login LDAP config
+// @Description get site info login LDAP config, the bind password is not returned
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteLoginLDAPResp}
+// @Router /answer/admin/api/siteinfo/login-ldap [get]
+func (sc *SiteInfoController) GetSiteLoginLDAP(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteLoginLDAP(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteRateLimit get site info rate limit config
+// @Summary get site info rate limit config
+// @Description get site info rate limit config, the default rules are returned if it is not configured
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteRateLimitResp}
+// @Router /answer/admin/api/siteinfo/rate-limit [get]
+func (sc *SiteInfoController) GetSiteRateLimit(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteRateLimit(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSitePreModeration get site info pre-moderation config
+// @Summary get site info pre-moderation config
+// @Description get site info pre-moderation config
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SitePreModerationResp}
+// @Router /answer/admin/api/siteinfo/pre-moderation [get]
+func (sc *SiteInfoController) GetSitePreModeration(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSitePreModeration(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteSpam get site info spam detection config
+// @Summary get site info spam detection config
+// @Description get site info spam detection config
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteSpamResp}
+// @Router /answer/admin/api/siteinfo/spam [get]
+func (sc *SiteInfoController) GetSiteSpam(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteSpam(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
 // GetSiteCustomCssHTML get site info custom html css config
 // @Summary get site info custom html css config
 // @Description get site info custom html css config
@@ -300,6 +378,114 @@ func (sc *SiteInfoController) UpdateSiteLogin(ctx *gin.Context) {
 	handler.HandleResponse(ctx, err, nil)
 }
 
+// UpdateSiteLoginConnector update site login connector
+// @Summary update site login connector
+// @Description update site login connector
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteLoginConnectorReq true "login connector info"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/login-connector [put]
+func (sc *SiteInfoController) UpdateSiteLoginConnector(ctx *gin.Context) {
+	req := &schema.SiteLoginConnectorReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteLoginConnector(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteLoginSAML update site login SAML config
+// @Summary update site login SAML config
+// @Description update site login SAML config, the key pair of SP is kept or generated if the private key is empty
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteLoginSAMLReq true "login SAML info"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/login-saml [put]
+func (sc *SiteInfoController) UpdateSiteLoginSAML(ctx *gin.Context) {
+	req := &schema.SiteLoginSAMLReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteLoginSAML(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteLoginLDAP update site login LDAP config
+// @Summary update site login LDAP config
+// @Description update site login LDAP config, the bind password is kept if it is empty
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteLoginLDAPReq true "login LDAP info"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/login-ldap [put]
+func (sc *SiteInfoController) UpdateSiteLoginLDAP(ctx *gin.Context) {
+	req := &schema.SiteLoginLDAPReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteLoginLDAP(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteRateLimit update site rate limit config
+// @Summary update site rate limit config
+// @Description update site rate limit config, the rules of ip scope can not have the role or min rank
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteRateLimitReq true "rate limit info"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/rate-limit [put]
+func (sc *SiteInfoController) UpdateSiteRateLimit(ctx *gin.Context) {
+	req := &schema.SiteRateLimitReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteRateLimit(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSitePreModeration update site pre-moderation config
+// @Summary update site pre-moderation config
+// @Description update site pre-moderation config, the first posts of the new users or the users with low reputation are held for review
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SitePreModerationReq true "pre-moderation info"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/pre-moderation [put]
+func (sc *SiteInfoController) UpdateSitePreModeration(ctx *gin.Context) {
+	req := &schema.SitePreModerationReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSitePreModeration(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteSpam update site spam detection config
+// @Summary update site spam detection config
+// @Description update site spam detection config, the blocked patterns must be valid regular expressions
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteSpamReq true "spam detection info"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/spam [put]
+func (sc *SiteInfoController) UpdateSiteSpam(ctx *gin.Context) {
+	req := &schema.SiteSpamReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteSpam(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
 // UpdateSiteCustomCssHTML update site custom css html config
 // @Summary update site custom css html config
 // @Description update site custom css html config
@@ -366,3 +552,35 @@ func (sc *SiteInfoController) UpdateSMTPConfig(ctx *gin.Context) {
 	err := sc.siteInfoService.UpdateSMTPConfig(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
+
+// GetPrivilegeConfig get privileges and reputation config
+// @Summary get privileges and reputation config
+// @Description get the reputation required by every action and earned by every activity
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.GetPrivilegeConfigResp}
+// @Router /answer/admin/api/setting/privileges [get]
+func (sc *SiteInfoController) GetPrivilegeConfig(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetPrivilegeConfig(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdatePrivilegeConfig update privileges and reputation config
+// @Summary update privileges and reputation config
+// @Description update the reputation required by actions and earned by activities
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.UpdatePrivilegeConfigReq true "privileges and reputation config"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/setting/privileges [put]
+func (sc *SiteInfoController) UpdatePrivilegeConfig(ctx *gin.Context) {
+	req := &schema.UpdatePrivilegeConfigReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := sc.siteInfoService.UpdatePrivilegeConfig(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -0,0 +1,40 @@
+package controller_admin
+
+import (
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/spam"
+	"github.com/gin-gonic/gin"
+)
+
+// SpamController spam controller
+type SpamController struct {
+	spamService *spam.SpamService
+}
+
+// NewSpamController new controller
+func NewSpamController(spamService *spam.SpamService) *SpamController {
+	return &SpamController{spamService: spamService}
+}
+
+// GetSpamLogPage get spam log page
+// @Summary get spam log page
+// @Description get the content caught by the spam detection, the latest first
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Param action query string false "action" Enums(review, reject)
+// @Param object_type query string false "object type" Enums(question, answer, comment, user)
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetSpamLogResp}}
+// @Router /answer/admin/api/spam-logs/page [get]
+func (sc *SpamController) GetSpamLogPage(ctx *gin.Context) {
+	req := &schema.GetSpamLogPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := sc.spamService.GetSpamLogPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
@@ -4,18 +4,23 @@ import (
 	"github.com/answerdev/answer/internal/base/handler"
 	"github.com/answerdev/answer/internal/base/middleware"
 	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/two_factor"
 	"github.com/answerdev/answer/internal/service/user_admin"
 	"github.com/gin-gonic/gin"
 )
 
 // UserAdminController user controller
 type UserAdminController struct {
-	userService *user_admin.UserAdminService
+	userService      *user_admin.UserAdminService
+	twoFactorService *two_factor.TwoFactorService
 }
 
 // NewUserAdminController new controller
-func NewUserAdminController(userService *user_admin.UserAdminService) *UserAdminController {
-	return &UserAdminController{userService: userService}
+func NewUserAdminController(
+	userService *user_admin.UserAdminService,
+	twoFactorService *two_factor.TwoFactorService,
+) *UserAdminController {
+	return &UserAdminController{userService: userService, twoFactorService: twoFactorService}
 }
 
 // UpdateUserStatus update user
@@ -104,6 +109,26 @@ func (uc *UserAdminController) UpdateUserPassword(ctx *gin.Context) {
 	handler.HandleResponse(ctx, err, nil)
 }
 
+// ResetUserTwoFactor reset the two-factor authentication of user
+// @Summary reset the two-factor authentication of user
+// @Description reset the two-factor authentication of user who lost the authenticator and recovery codes
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AdminResetTwoFactorReq true "user"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/2fa/reset [put]
+func (uc *UserAdminController) ResetUserTwoFactor(ctx *gin.Context) {
+	req := &schema.AdminResetTwoFactorReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	err := uc.twoFactorService.AdminReset(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
 // GetUserPage get user page
 // @Summary get user page
 // @Description get user page
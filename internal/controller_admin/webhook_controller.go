@@ -0,0 +1,144 @@
+package controller_admin
+
+import (
+	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookController webhook controller
+type WebhookController struct {
+	webhookService *webhook.WebhookService
+}
+
+// NewWebhookController new controller
+func NewWebhookController(webhookService *webhook.WebhookService) *WebhookController {
+	return &WebhookController{webhookService: webhookService}
+}
+
+// GetWebhookEvents get the events which can trigger webhooks
+// @Summary get the events which can trigger webhooks
+// @Description get the events which can trigger webhooks
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]string}
+// @Router /answer/admin/api/webhook/events [get]
+func (wc *WebhookController) GetWebhookEvents(ctx *gin.Context) {
+	handler.HandleResponse(ctx, nil, wc.webhookService.GetWebhookEvents(ctx))
+}
+
+// GetWebhookList get webhook list
+// @Summary get webhook list
+// @Description get webhook list
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.GetWebhookResp}
+// @Router /answer/admin/api/webhooks [get]
+func (wc *WebhookController) GetWebhookList(ctx *gin.Context) {
+	resp, err := wc.webhookService.GetWebhookList(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddWebhook add webhook
+// @Summary add webhook
+// @Description add webhook, a random secret is generated if it is empty, the secret is only returned here
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AddWebhookReq true "AddWebhookReq"
+// @Success 200 {object} handler.RespBody{data=schema.AddWebhookResp}
+// @Router /answer/admin/api/webhook [post]
+func (wc *WebhookController) AddWebhook(ctx *gin.Context) {
+	req := &schema.AddWebhookReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := wc.webhookService.AddWebhook(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateWebhook update webhook
+// @Summary update webhook
+// @Description update webhook
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateWebhookReq true "UpdateWebhookReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/webhook [put]
+func (wc *WebhookController) UpdateWebhook(ctx *gin.Context) {
+	req := &schema.UpdateWebhookReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	err := wc.webhookService.UpdateWebhook(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveWebhook remove webhook
+// @Summary remove webhook
+// @Description remove webhook and its delivery log
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.RemoveWebhookReq true "RemoveWebhookReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/webhook [delete]
+func (wc *WebhookController) RemoveWebhook(ctx *gin.Context) {
+	req := &schema.RemoveWebhookReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	err := wc.webhookService.RemoveWebhook(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetDeliveryPage get the delivery log of webhook
+// @Summary get the delivery log of webhook
+// @Description get the delivery log of webhook, the newest first
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param webhook_id query string true "webhook id"
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetWebhookDeliveryResp}}
+// @Router /answer/admin/api/webhook/deliveries/page [get]
+func (wc *WebhookController) GetDeliveryPage(ctx *gin.Context) {
+	req := &schema.GetWebhookDeliveryPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := wc.webhookService.GetDeliveryPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// Redeliver redeliver the payload of webhook delivery
+// @Summary redeliver the payload of webhook delivery
+// @Description the payload is sent again as a new delivery
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.RedeliverWebhookReq true "RedeliverWebhookReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/webhook/delivery/redeliver [post]
+func (wc *WebhookController) Redeliver(ctx *gin.Context) {
+	req := &schema.RedeliverWebhookReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	err := wc.webhookService.Redeliver(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
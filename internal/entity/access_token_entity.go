@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// AccessToken the personal access token of user for the REST API
+type AccessToken struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	Name      string    `xorm:"not null default '' VARCHAR(100) name"`
+	// TokenHash the sha256 hash of token, the token is only shown once when it is created
+	TokenHash string `xorm:"not null default '' VARCHAR(64) UNIQUE token_hash"`
+	// TokenPrefix the beginning of token to help the user to recognize it
+	TokenPrefix string `xorm:"not null default '' VARCHAR(32) token_prefix"`
+	// Scopes the comma separated scopes
+	Scopes string `xorm:"not null default '' VARCHAR(255) scopes"`
+	// ExpiredAt the token never expires if it is zero
+	ExpiredAt  time.Time `xorm:"TIMESTAMP expired_at"`
+	LastUsedAt time.Time `xorm:"TIMESTAMP last_used_at"`
+}
+
+// TableName access token table name
+func (AccessToken) TableName() string {
+	return "user_access_token"
+}
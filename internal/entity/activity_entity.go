@@ -22,6 +22,11 @@ type Activity struct {
 	Rank             int       `xorm:"not null default 0 INT(11) rank"`
 	HasRank          int       `xorm:"not null default 0 TINYINT(4) has_rank"`
 	RevisionID       int64     `xorm:"not null default 0 BIGINT(20) revision_id"`
+	// ExpiredAt the expiry of the lock or protection added by the activity, it is zero if it never expires
+	ExpiredAt time.Time `xorm:"TIMESTAMP expired_at"`
+	// DedupKey the key of the queue message which added the activity, so that the redelivered message is ignored.
+	// It is null for the activities not added by the queue.
+	DedupKey *string `xorm:"null VARCHAR(64) UNIQUE dedup_key"`
 }
 
 type ActivityRankSum struct {
@@ -38,6 +43,13 @@ type ActivityUserVoteStat struct {
 	VoteCount int    `xorm:"vote_count"`
 }
 
+// ActivityUserVotePairStat the number of upvotes the user received from the trigger user
+type ActivityUserVotePairStat struct {
+	UserID        string `xorm:"user_id"`
+	TriggerUserID string `xorm:"trigger_user_id"`
+	VoteCount     int    `xorm:"vote_count"`
+}
+
 // TableName activity table name
 func (Activity) TableName() string {
 	return "activity"
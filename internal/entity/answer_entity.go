@@ -9,6 +9,8 @@ const (
 
 	AnswerStatusAvailable = 1
 	AnswerStatusDeleted   = 10
+	// AnswerStatusPending the answer is held for review, it is only visible to the author and reviewers
+	AnswerStatusPending = 11
 )
 
 var AdminAnswerSearchStatus = map[string]int{
@@ -16,6 +18,12 @@ var AdminAnswerSearchStatus = map[string]int{
 	"deleted":   AnswerStatusDeleted,
 }
 
+var AdminAnswerSearchStatusIntToString = map[int]string{
+	AnswerStatusAvailable: "available",
+	AnswerStatusDeleted:   "deleted",
+	AnswerStatusPending:   "pending",
+}
+
 // Answer answer
 type Answer struct {
 	ID             string    `xorm:"not null pk autoincr BIGINT(20) id"`
@@ -31,6 +39,9 @@ type Answer struct {
 	CommentCount   int       `xorm:"not null default 0 INT(11) comment_count"`
 	VoteCount      int       `xorm:"not null default 0 INT(11) vote_count"`
 	RevisionID     string    `xorm:"not null default 0 BIGINT(20) revision_id"`
+	// Locked the locked answer can not be edited, voted or commented
+	Locked        bool      `xorm:"not null default false BOOL locked"`
+	LockExpiredAt time.Time `xorm:"TIMESTAMP lock_expired_at"`
 }
 
 type AnswerSearch struct {
@@ -53,3 +64,8 @@ type AdminAnswerSearch struct {
 func (Answer) TableName() string {
 	return "answer"
 }
+
+// IsLocked whether the lock is in effect, the expired lock is released by the cron job later
+func (a *Answer) IsLocked() bool {
+	return a.Locked && (a.LockExpiredAt.Unix() <= 0 || a.LockExpiredAt.After(time.Now()))
+}
@@ -0,0 +1,61 @@
+package entity
+
+import "time"
+
+const (
+	AuditActionQuestionStatus  = "question.status"
+	AuditActionQuestionMerge   = "question.merge"
+	AuditActionQuestionLock    = "question.lock"
+	AuditActionQuestionProtect = "question.protect"
+	AuditActionAnswerStatus    = "answer.status"
+	AuditActionAnswerLock      = "answer.lock"
+	AuditActionUserStatus      = "user.status"
+	AuditActionUserRole        = "user.role"
+	AuditActionReportHandle    = "report.handle"
+	AuditActionRevisionAudit   = "revision.audit"
+	AuditActionPostReview      = "post.review"
+	AuditActionSiteInfoUpdate  = "siteinfo.update"
+	AuditActionConfigUpdate    = "config.update"
+	AuditActionBadgeAward      = "badge.award"
+	AuditActionBadgeRevoke     = "badge.revoke"
+	AuditActionTwoFactorReset  = "user.two_factor_reset"
+	AuditActionRoleAdd         = "role.add"
+	AuditActionRoleUpdate      = "role.update"
+	AuditActionRoleRemove      = "role.remove"
+	AuditActionRolePower       = "role.power"
+	AuditActionWebhookAdd      = "webhook.add"
+	AuditActionWebhookUpdate   = "webhook.update"
+	AuditActionWebhookRemove   = "webhook.remove"
+)
+
+const (
+	// AuditObjectTypeSiteInfo the object id is the site info type
+	AuditObjectTypeSiteInfo = "site_info"
+	// AuditObjectTypeConfig the object id is the config key
+	AuditObjectTypeConfig = "config"
+	// AuditObjectTypeRole the object id is the role id
+	AuditObjectTypeRole = "role"
+	// AuditObjectTypeWebhook the object id is the webhook id
+	AuditObjectTypeWebhook = "webhook"
+)
+
+// AuditLog the privileged action done by the user, it is append only
+type AuditLog struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP INDEX created_at"`
+	// UserID the user who does the action
+	UserID     string `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	Action     string `xorm:"not null default '' VARCHAR(100) INDEX action"`
+	ObjectType string `xorm:"not null default '' VARCHAR(100) object_type"`
+	ObjectID   string `xorm:"not null default '' VARCHAR(100) INDEX object_id"`
+	// Before After the json snapshots of the object before and after the action
+	Before string `xorm:"MEDIUMTEXT before"`
+	After  string `xorm:"MEDIUMTEXT after"`
+	IP     string `xorm:"not null default '' VARCHAR(100) ip"`
+	Reason string `xorm:"TEXT reason"`
+}
+
+// TableName audit log table name
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,78 @@
+package entity
+
+import "time"
+
+const (
+	BadgeLevelBronze = 1
+	BadgeLevelSilver = 2
+	BadgeLevelGold   = 3
+)
+
+const (
+	// BadgeRuleManual the badge is only awarded by admin
+	BadgeRuleManual = "manual"
+	// BadgeRuleAcceptedAnswers the number of accepted answers of user reaches the threshold
+	BadgeRuleAcceptedAnswers = "accepted_answers"
+	// BadgeRuleTagUpvotedAnswers the number of upvoted answers of user in a tag reaches the threshold
+	BadgeRuleTagUpvotedAnswers = "tag_upvoted_answers"
+	// BadgeRuleVisitDays the number of days user visited reaches the threshold
+	BadgeRuleVisitDays = "visit_days"
+	// BadgeRuleQuestionVotes the votes of question reaches the threshold
+	BadgeRuleQuestionVotes = "question_votes"
+)
+
+// Badge the badge definition
+type Badge struct {
+	ID          string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt   time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt   time.Time `xorm:"updated TIMESTAMP updated_at"`
+	Name        string    `xorm:"not null default '' VARCHAR(100) name"`
+	Description string    `xorm:"not null default '' VARCHAR(500) description"`
+	Level       int       `xorm:"not null default 1 TINYINT(4) level"`
+	// Repeatable the repeatable badge is awarded once for each question or tag meeting the rule,
+	// or each time the count of the rule reaches another multiple of the threshold,
+	// otherwise it is awarded only once to the user
+	Repeatable bool   `xorm:"not null default false BOOL repeatable"`
+	Rule       string `xorm:"not null default '' VARCHAR(32) rule"`
+	Threshold  int    `xorm:"not null default 0 INT(11) threshold"`
+	Enabled    bool   `xorm:"not null default false BOOL enabled"`
+}
+
+// TableName badge table name
+func (Badge) TableName() string {
+	return "badge"
+}
+
+// UserBadge the badge awarded to user
+type UserBadge struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE(s) user_id"`
+	BadgeID   string    `xorm:"not null default 0 BIGINT(20) UNIQUE(s) INDEX badge_id"`
+	// ObjectID the question or tag the repeatable badge is awarded for, or the multiple of the threshold
+	// for the count rules, it is 0 for the one-time badge
+	ObjectID string `xorm:"not null default 0 BIGINT(20) UNIQUE(s) object_id"`
+	// AwardUserID the admin who awarded the badge manually, it is 0 if awarded by rule
+	AwardUserID string `xorm:"not null default 0 BIGINT(20) award_user_id"`
+	// Revoked the revoked badge is kept so that the rule does not award it again
+	Revoked bool `xorm:"not null default false BOOL revoked"`
+}
+
+// TableName user badge table name
+func (UserBadge) TableName() string {
+	return "user_badge"
+}
+
+// UserVisit the days user visited
+type UserVisit struct {
+	ID        int64     `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE(s) user_id"`
+	// VisitDate the date in format 2006-01-02
+	VisitDate string `xorm:"not null default '' VARCHAR(10) UNIQUE(s) visit_date"`
+}
+
+// TableName user visit table name
+func (UserVisit) TableName() string {
+	return "user_visit"
+}
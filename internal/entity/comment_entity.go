@@ -11,6 +11,8 @@ import (
 const (
 	CommentStatusAvailable = 1
 	CommentStatusDeleted   = 10
+	// CommentStatusPending the comment is held for review
+	CommentStatusPending = 11
 )
 
 // Comment comment
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// UserNoticeConfig the email delivery preference of user for each notification type
+type UserNoticeConfig struct {
+	ID         int       `xorm:"not null pk autoincr INT(11) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt  time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID     string    `xorm:"not null default 0 BIGINT(20) UNIQUE(s) user_id"`
+	NoticeType string    `xorm:"not null default '' VARCHAR(32) UNIQUE(s) notice_type"`
+	Frequency  string    `xorm:"not null default '' VARCHAR(16) frequency"`
+}
+
+// TableName user notice config table name
+func (UserNoticeConfig) TableName() string {
+	return "user_notice_config"
+}
+
+// EmailDigestItem the pending notification waiting for the digest email
+type EmailDigestItem struct {
+	ID                 int64     `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt          time.Time `xorm:"created TIMESTAMP created_at"`
+	UserID             string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	NoticeType         string    `xorm:"not null default '' VARCHAR(32) notice_type"`
+	Frequency          string    `xorm:"not null default '' VARCHAR(16) INDEX frequency"`
+	QuestionID         string    `xorm:"not null default 0 BIGINT(20) question_id"`
+	AnswerID           string    `xorm:"not null default 0 BIGINT(20) answer_id"`
+	CommentID          string    `xorm:"not null default 0 BIGINT(20) comment_id"`
+	QuestionTitle      string    `xorm:"not null default '' VARCHAR(150) question_title"`
+	TriggerDisplayName string    `xorm:"not null default '' VARCHAR(255) trigger_display_name"`
+	Summary            string    `xorm:"TEXT summary"`
+}
+
+// TableName email digest item table name
+func (EmailDigestItem) TableName() string {
+	return "email_digest_item"
+}
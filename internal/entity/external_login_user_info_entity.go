@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// ExternalLoginUserInfo the user of external login provider linked to the user
+type ExternalLoginUserInfo struct {
+	ID         int       `xorm:"not null pk autoincr INT(11) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt  time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID     string    `xorm:"not null default 0 BIGINT(20) index user_id"`
+	Provider   string    `xorm:"not null default '' VARCHAR(100) unique(provider_external_id) provider"`
+	ExternalID string    `xorm:"not null default '' VARCHAR(128) unique(provider_external_id) external_id"`
+	Name       string    `xorm:"not null default '' VARCHAR(100) name"`
+	Email      string    `xorm:"not null default '' VARCHAR(100) email"`
+	MetaInfo   string    `xorm:"TEXT meta_info"`
+	// MappedRoleID the role granted by the group mapping of provider, it is taken back when the user
+	// is removed from the mapped groups
+	MappedRoleID int `xorm:"not null default 0 INT(11) mapped_role_id"`
+}
+
+// TableName external login user info table name
+func (ExternalLoginUserInfo) TableName() string {
+	return "external_login_user_info"
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	JobStatusPending = 1
+	JobStatusRunning = 2
+	JobStatusDead    = 10
+)
+
+// Job the message of queue waiting to be handled
+type Job struct {
+	ID          string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt   time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt   time.Time `xorm:"updated TIMESTAMP updated_at"`
+	Topic       string    `xorm:"not null default '' VARCHAR(64) topic"`
+	Payload     string    `xorm:"not null MEDIUMTEXT payload"`
+	Status      int       `xorm:"not null default 1 INT(11) index(status_available_at) status"`
+	Attempts    int       `xorm:"not null default 0 INT(11) attempts"`
+	AvailableAt time.Time `xorm:"not null default CURRENT_TIMESTAMP TIMESTAMP index(status_available_at) available_at"`
+	LockedBy    string    `xorm:"not null default '' VARCHAR(64) locked_by"`
+	LastError   string    `xorm:"TEXT last_error"`
+}
+
+// TableName job table name
+func (Job) TableName() string {
+	return "job"
+}
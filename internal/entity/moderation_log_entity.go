@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	// ModerationActionSerialVoteReversed the serial votes between two users are reversed
+	ModerationActionSerialVoteReversed = "serial_vote_reversed"
+)
+
+// ModerationLog the moderation done by the system or the moderators
+type ModerationLog struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	Action    string    `xorm:"not null default '' VARCHAR(100) INDEX action"`
+	// UserID the user who is moderated, it is the voter for the serial votes
+	UserID string `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	// OperatorUserID the moderator, it is 0 if it is done by the system
+	OperatorUserID string `xorm:"not null default 0 BIGINT(20) operator_user_id"`
+	// TargetUserID the user affected by the moderation, it is the user who received the votes
+	TargetUserID string `xorm:"not null default 0 BIGINT(20) target_user_id"`
+	// Content the json detail of the moderation
+	Content string `xorm:"not null MEDIUMTEXT content"`
+}
+
+// TableName moderation log table name
+func (ModerationLog) TableName() string {
+	return "moderation_log"
+}
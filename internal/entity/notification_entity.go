@@ -13,6 +13,8 @@ type Notification struct {
 	Type      int       `xorm:"not null default 0 INT(11) type"`
 	IsRead    int       `xorm:"not null default 1 INT(11) is_read"`
 	Status    int       `xorm:"not null default 1 INT(11) status"`
+	// DedupKey the key of the queue message which added the notification, so that the redelivered message is ignored
+	DedupKey *string `xorm:"null VARCHAR(64) UNIQUE dedup_key"`
 }
 
 // TableName notification table name
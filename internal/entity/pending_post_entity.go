@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+const (
+	PendingPostStatusPending  = 1
+	PendingPostStatusApproved = 2
+	PendingPostStatusRejected = 3
+)
+
+// PendingPost the new post held for review by the pre-moderation policy
+type PendingPost struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	// UserID the author of the post
+	UserID     string `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	ObjectID   string `xorm:"not null default 0 BIGINT(20) UNIQUE object_id"`
+	ObjectType int    `xorm:"not null default 0 INT(11) INDEX(status_object_type) object_type"`
+	Status     int    `xorm:"not null default 1 INT(11) INDEX(status_object_type) status"`
+	// Extra the json data needed to publish the post, e.g. the mentioned users of the comment
+	Extra        string `xorm:"TEXT extra"`
+	ReviewUserID string `xorm:"not null default 0 BIGINT(20) review_user_id"`
+}
+
+// TableName pending post table name
+func (PendingPost) TableName() string {
+	return "pending_post"
+}
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+const (
+	QuestionBountyStatusActive   = 1
+	QuestionBountyStatusAwarded  = 2
+	QuestionBountyStatusRefunded = 3
+)
+
+// QuestionBounty the reputation offered by user on a question
+type QuestionBounty struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt  time.Time `xorm:"updated TIMESTAMP updated_at"`
+	QuestionID string    `xorm:"not null default 0 BIGINT(20) INDEX question_id"`
+	UserID     string    `xorm:"not null default 0 BIGINT(20) user_id"`
+	Amount     int       `xorm:"not null default 0 INT(11) amount"`
+	Status     int       `xorm:"not null default 1 TINYINT(4) INDEX status"`
+	// AnswerID the answer the bounty is awarded to, it is 0 before awarded or if refunded
+	AnswerID  string    `xorm:"not null default 0 BIGINT(20) answer_id"`
+	ExpiredAt time.Time `xorm:"TIMESTAMP expired_at"`
+	// ActiveQuestionID the question id while the bounty is active, it is null after closed,
+	// so that the unique index allows only one active bounty on a question
+	ActiveQuestionID string `xorm:"null BIGINT(20) UNIQUE active_question_id"`
+}
+
+// TableName question bounty table name
+func (QuestionBounty) TableName() string {
+	return "question_bounty"
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// QuestionDuplicate the question closed as a duplicate of the canonical question
+type QuestionDuplicate struct {
+	ID            string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt     time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt     time.Time `xorm:"updated TIMESTAMP updated_at"`
+	QuestionID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE question_id"`
+	DuplicateOfID string    `xorm:"not null default 0 BIGINT(20) INDEX duplicate_of_id"`
+	UserID        string    `xorm:"not null default 0 BIGINT(20) user_id"`
+	// Merged the answers, comments and followers are moved to the canonical question, the question only redirects
+	Merged bool `xorm:"not null default false BOOL merged"`
+}
+
+// TableName question duplicate table name
+func (QuestionDuplicate) TableName() string {
+	return "question_duplicate"
+}
@@ -8,6 +8,8 @@ const (
 	QuestionStatusAvailable = 1
 	QuestionStatusClosed    = 2
 	QuestionStatusDeleted   = 10
+	// QuestionStatusPending the question is held for review, it is only visible to the author and reviewers
+	QuestionStatusPending = 11
 )
 
 var AdminQuestionSearchStatus = map[string]int{
@@ -20,6 +22,7 @@ var AdminQuestionSearchStatusIntToString = map[int]string{
 	QuestionStatusAvailable: "available",
 	QuestionStatusClosed:    "closed",
 	QuestionStatusDeleted:   "deleted",
+	QuestionStatusPending:   "pending",
 }
 
 // Question question
@@ -43,6 +46,12 @@ type Question struct {
 	LastAnswerID     string    `xorm:"not null default 0 BIGINT(20) last_answer_id"`
 	PostUpdateTime   time.Time `xorm:"post_update_time TIMESTAMP"`
 	RevisionID       string    `xorm:"not null default 0 BIGINT(20) revision_id"`
+	// Locked the locked question and its answers can not be edited, voted, answered or commented
+	Locked        bool      `xorm:"not null default false BOOL locked"`
+	LockExpiredAt time.Time `xorm:"TIMESTAMP lock_expired_at"`
+	// Protected only the users with enough reputation can answer the protected question
+	Protected        bool      `xorm:"not null default false BOOL protected"`
+	ProtectExpiredAt time.Time `xorm:"TIMESTAMP protect_expired_at"`
 }
 
 // TableName question table name
@@ -50,6 +59,16 @@ func (Question) TableName() string {
 	return "question"
 }
 
+// IsLocked whether the lock is in effect, the expired lock is released by the cron job later
+func (q *Question) IsLocked() bool {
+	return q.Locked && (q.LockExpiredAt.Unix() <= 0 || q.LockExpiredAt.After(time.Now()))
+}
+
+// IsProtected whether the protection is in effect, the expired protection is released by the cron job later
+func (q *Question) IsProtected() bool {
+	return q.Protected && (q.ProtectExpiredAt.Unix() <= 0 || q.ProtectExpiredAt.After(time.Now()))
+}
+
 // QuestionWithTagsRevision question
 type QuestionWithTagsRevision struct {
 	Question
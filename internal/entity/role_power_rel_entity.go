@@ -9,6 +9,8 @@ type RolePowerRel struct {
 	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
 	RoleID    int       `xorm:"not null default 0 INT(11) role_id"`
 	PowerType string    `xorm:"not null default '' VARCHAR(200) power_type"`
+	// TagID the power only works on the questions with this tag, 0 means the power works everywhere
+	TagID int64 `xorm:"not null default 0 BIGINT(20) tag_id"`
 }
 
 // TableName role power rel table name
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+const (
+	SpamActionReview = "review"
+	SpamActionReject = "reject"
+)
+
+// SpamTokenDocuments the reserved token keeping the count of the trained documents,
+// it can not be produced by the tokenizer
+const SpamTokenDocuments = "#documents"
+
+// SpamLog the content held for review or rejected by the spam detection
+type SpamLog struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP INDEX created_at"`
+	// UserID the author of the content, it is 0 for the registration
+	UserID     string `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	ObjectType string `xorm:"not null default '' VARCHAR(100) object_type"`
+	Action     string `xorm:"not null default '' VARCHAR(100) INDEX action"`
+	Checker    string `xorm:"not null default '' VARCHAR(100) checker"`
+	Reason     string `xorm:"TEXT reason"`
+	Content    string `xorm:"MEDIUMTEXT content"`
+	IP         string `xorm:"not null default '' VARCHAR(100) ip"`
+}
+
+// TableName spam log table name
+func (SpamLog) TableName() string {
+	return "spam_log"
+}
+
+// SpamToken the documents containing the token of the naive Bayes spam classifier
+type SpamToken struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	Token     string    `xorm:"not null default '' VARCHAR(100) UNIQUE token"`
+	SpamCount int64     `xorm:"not null default 0 BIGINT(20) spam_count"`
+	HamCount  int64     `xorm:"not null default 0 BIGINT(20) ham_count"`
+}
+
+// TableName spam token table name
+func (SpamToken) TableName() string {
+	return "spam_token"
+}
@@ -5,6 +5,8 @@ import "time"
 const (
 	TagRelStatusAvailable = 1
 	TagRelStatusDeleted   = 10
+	// TagRelStatusPending the relation of the question held for review, it is not counted until published
+	TagRelStatusPending = 11
 )
 
 // TagRel tag relation
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// UserTwoFactor the TOTP two-factor authentication of user
+type UserTwoFactor struct {
+	ID        int       `xorm:"not null pk autoincr INT(11) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE user_id"`
+	Secret    string    `xorm:"not null default '' VARCHAR(128) secret"`
+	// Enabled the secret is pending until the first code is verified
+	Enabled bool `xorm:"not null default false BOOL enabled"`
+	// RecoveryCodes the json array of sha256 hashed recovery codes, the used code is removed
+	RecoveryCodes string `xorm:"TEXT recovery_codes"`
+	// LastUsedStep the time step of last verified code, the code can not be used again
+	LastUsedStep int64 `xorm:"not null default 0 BIGINT(20) last_used_step"`
+}
+
+// TableName user two factor table name
+func (UserTwoFactor) TableName() string {
+	return "user_two_factor"
+}
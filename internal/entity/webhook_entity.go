@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+const (
+	WebhookDeliveryStatusPending   = 1
+	WebhookDeliveryStatusSucceeded = 2
+	WebhookDeliveryStatusFailed    = 3
+)
+
+// Webhook the admin managed webhook subscription
+type Webhook struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	Name      string    `xorm:"not null default '' VARCHAR(100) name"`
+	URL       string    `xorm:"not null default '' VARCHAR(512) url"`
+	// Secret the key of HMAC signature of payload
+	Secret string `xorm:"not null default '' VARCHAR(128) secret"`
+	// Events the comma separated activity type keys
+	Events string `xorm:"not null TEXT events"`
+	// Tags the comma separated slug names of tags, the events of all tags are sent if it is empty
+	Tags    string `xorm:"not null TEXT tags"`
+	Enabled bool   `xorm:"not null default false BOOL enabled"`
+}
+
+// TableName webhook table name
+func (Webhook) TableName() string {
+	return "webhook"
+}
+
+// WebhookDelivery the delivery log of webhook
+type WebhookDelivery struct {
+	ID             string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt      time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt      time.Time `xorm:"updated TIMESTAMP updated_at"`
+	WebhookID      string    `xorm:"not null default 0 BIGINT(20) INDEX webhook_id"`
+	Event          string    `xorm:"not null default '' VARCHAR(64) event"`
+	Payload        string    `xorm:"not null MEDIUMTEXT payload"`
+	Status         int       `xorm:"not null default 1 TINYINT(4) status"`
+	Attempts       int       `xorm:"not null default 0 INT(11) attempts"`
+	ResponseStatus int       `xorm:"not null default 0 INT(11) response_status"`
+	ResponseBody   string    `xorm:"TEXT response_body"`
+	Error          string    `xorm:"TEXT error"`
+	// Duration the milliseconds of last attempt
+	Duration    int       `xorm:"not null default 0 INT(11) duration"`
+	DeliveredAt time.Time `xorm:"TIMESTAMP delivered_at"`
+}
+
+// TableName webhook delivery table name
+func (WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
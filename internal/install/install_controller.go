@@ -144,6 +144,9 @@ func InitEnvironment(ctx *gin.Context) {
 	c.Data.Database.Driver = req.DbType
 	c.Data.Database.Connection = req.GetConnection()
 	c.Data.Cache.FilePath = filepath.Join(cli.CacheDir, cli.DefaultCacheFileName)
+	if c.Data.Search != nil {
+		c.Data.Search.IndexPath = cli.SearchIndexDir
+	}
 	c.I18n.BundleDir = cli.I18nPath
 	c.ServiceConfig.UploadPath = cli.UploadFilePath
 
@@ -50,6 +50,24 @@ var tables = []interface{}{
 	&entity.RolePowerRel{},
 	&entity.Power{},
 	&entity.UserRoleRel{},
+	&entity.Job{},
+	&entity.ExternalLoginUserInfo{},
+	&entity.UserTwoFactor{},
+	&entity.AccessToken{},
+	&entity.Webhook{},
+	&entity.WebhookDelivery{},
+	&entity.UserNoticeConfig{},
+	&entity.EmailDigestItem{},
+	&entity.Badge{},
+	&entity.UserBadge{},
+	&entity.UserVisit{},
+	&entity.QuestionBounty{},
+	&entity.ModerationLog{},
+	&entity.AuditLog{},
+	&entity.PendingPost{},
+	&entity.SpamLog{},
+	&entity.SpamToken{},
+	&entity.QuestionDuplicate{},
 }
 
 // InitDB init db
@@ -73,6 +91,10 @@ func InitDB(dataConf *data.Database) (err error) {
 	if err != nil {
 		return fmt.Errorf("sync table failed: %s", err)
 	}
+	err = addSearchFullTextIndex(engine)
+	if err != nil {
+		return fmt.Errorf("init search full-text index failed: %s", err)
+	}
 	_, err = engine.InsertOne(&entity.Version{ID: 1, VersionNumber: ExpectedVersion()})
 	if err != nil {
 		return fmt.Errorf("init version table failed: %s", err)
@@ -92,6 +114,11 @@ func InitDB(dataConf *data.Database) (err error) {
 	if err != nil {
 		return fmt.Errorf("init role and power failed: %s", err)
 	}
+
+	err = initBadge(engine)
+	if err != nil {
+		return fmt.Errorf("init badge failed: %s", err)
+	}
 	return nil
 }
 
@@ -250,7 +277,7 @@ func initConfigTable(engine *xorm.Engine) error {
 		{ID: 20, Key: "object.reported", Value: `-100`},
 		{ID: 21, Key: "edit.rejected", Value: `-2`},
 		{ID: 22, Key: "daily_rank_limit", Value: `200`},
-		{ID: 23, Key: "daily_rank_limit.exclude", Value: `["answer.accepted"]`},
+		{ID: 23, Key: "daily_rank_limit.exclude", Value: `["answer.accepted","question.bounty","answer.bounty_awarded"]`},
 		{ID: 24, Key: "user.follow", Value: `0`},
 		{ID: 25, Key: "comment.vote_up", Value: `0`},
 		{ID: 26, Key: "comment.vote_up_cancel", Value: `0`},
@@ -260,7 +287,7 @@ func initConfigTable(engine *xorm.Engine) error {
 		{ID: 30, Key: "answer.vote_up", Value: `0`},
 		{ID: 31, Key: "answer.vote_up_cancel", Value: `0`},
 		{ID: 32, Key: "question.follow", Value: `0`},
-		{ID: 33, Key: "email.config", Value: `{"from_name":"","from_email":"","smtp_host":"","smtp_port":465,"smtp_password":"","smtp_username":"","smtp_authentication":true,"encryption":"","register_title":"[{{.SiteName}}] Confirm your new account","register_body":"Welcome to {{.SiteName}}<br><br>\n\nClick the following link to confirm and activate your new account:<br>\n<a href='{{.RegisterUrl}}' target='_blank'>{{.RegisterUrl}}</a><br><br>\n\nIf the above link is not clickable, try copying and pasting it into the address bar of your web browser.\n","pass_reset_title":"[{{.SiteName }}] Password reset","pass_reset_body":"Somebody asked to reset your password on [{{.SiteName}}].<br><br>\n\nIf it was not you, you can safely ignore this email.<br><br>\n\nClick the following link to choose a new password:<br>\n<a href='{{.PassResetUrl}}' target='_blank'>{{.PassResetUrl}}</a>\n","change_title":"[{{.SiteName}}] Confirm your new email address","change_body":"Confirm your new email address for {{.SiteName}}  by clicking on the following link:<br><br>\n\n<a href='{{.ChangeEmailUrl}}' target='_blank'>{{.ChangeEmailUrl}}</a><br><br>\n\nIf you did not request this change, please ignore this email.\n","test_title":"[{{.SiteName}}] Test Email","test_body":"This is a test email.","new_answer_title":"[{{.SiteName}}] {{.DisplayName}} answered your question","new_answer_body":"<strong><a href='{{.AnswerUrl}}'>{{.QuestionTitle}}</a></strong><br><br>\n\n<small>{{.DisplayName}}:</small><br>\n<blockquote>{{.AnswerSummary}}</blockquote><br>\n<a href='{{.AnswerUrl}}'>View it on {{.SiteName}}</a><br><br>\n\n<small>You are receiving this because you authored the thread. <a href='{{.UnsubscribeUrl}}'>Unsubscribe</a></small>","new_comment_title":"[{{.SiteName}}] {{.DisplayName}} commented on your post","new_comment_body":"<strong><a href='{{.CommentUrl}}'>{{.QuestionTitle}}</a></strong><br><br>\n\n<small>{{.DisplayName}}:</small><br>\n<blockquote>{{.CommentSummary}}</blockquote><br>\n<a href='{{.CommentUrl}}'>View it on {{.SiteName}}</a><br><br>\n\n<small>You are receiving this because you authored the thread. <a href='{{.UnsubscribeUrl}}'>Unsubscribe</a></small>"}`},
+		{ID: 33, Key: "email.config", Value: `{"from_name":"","from_email":"","smtp_host":"","smtp_port":465,"smtp_password":"","smtp_username":"","smtp_authentication":true,"encryption":"","register_title":"[{{.SiteName}}] Confirm your new account","register_body":"Welcome to {{.SiteName}}<br><br>\n\nClick the following link to confirm and activate your new account:<br>\n<a href='{{.RegisterUrl}}' target='_blank'>{{.RegisterUrl}}</a><br><br>\n\nIf the above link is not clickable, try copying and pasting it into the address bar of your web browser.\n","pass_reset_title":"[{{.SiteName }}] Password reset","pass_reset_body":"Somebody asked to reset your password on [{{.SiteName}}].<br><br>\n\nIf it was not you, you can safely ignore this email.<br><br>\n\nClick the following link to choose a new password:<br>\n<a href='{{.PassResetUrl}}' target='_blank'>{{.PassResetUrl}}</a>\n","change_title":"[{{.SiteName}}] Confirm your new email address","change_body":"Confirm your new email address for {{.SiteName}}  by clicking on the following link:<br><br>\n\n<a href='{{.ChangeEmailUrl}}' target='_blank'>{{.ChangeEmailUrl}}</a><br><br>\n\nIf you did not request this change, please ignore this email.\n","test_title":"[{{.SiteName}}] Test Email","test_body":"This is a test email.","new_answer_title":"[{{.SiteName}}] {{.DisplayName}} answered your question","new_answer_body":"<strong><a href='{{.AnswerUrl}}'>{{.QuestionTitle}}</a></strong><br><br>\n\n<small>{{.DisplayName}}:</small><br>\n<blockquote>{{.AnswerSummary}}</blockquote><br>\n<a href='{{.AnswerUrl}}'>View it on {{.SiteName}}</a><br><br>\n\n<small>You are receiving this because you authored the thread. <a href='{{.UnsubscribeUrl}}'>Unsubscribe</a></small>","new_comment_title":"[{{.SiteName}}] {{.DisplayName}} commented on your post","new_comment_body":"<strong><a href='{{.CommentUrl}}'>{{.QuestionTitle}}</a></strong><br><br>\n\n<small>{{.DisplayName}}:</small><br>\n<blockquote>{{.CommentSummary}}</blockquote><br>\n<a href='{{.CommentUrl}}'>View it on {{.SiteName}}</a><br><br>\n\n<small>You are receiving this because you authored the thread. <a href='{{.UnsubscribeUrl}}'>Unsubscribe</a></small>","digest_title":"[{{.SiteName}}] Your {{.Period}} digest","digest_body":"{{if .Items}}<strong>Activity on your posts</strong><br><br>\n\n{{range .Items}}<small>{{.DisplayName}} {{if eq .NoticeType \"new_answer\"}}answered{{else}}commented on{{end}}</small> <strong><a href='{{.Url}}'>{{.QuestionTitle}}</a></strong><br>\n<blockquote>{{.Summary}}</blockquote><br>\n{{end}}{{end}}{{if .NewQuestions}}<strong>New questions in tags you follow</strong><br><br>\n\n{{range .NewQuestions}}<a href='{{.Url}}'>{{.Title}}</a><br>\n{{end}}<br>{{end}}\n<small>You are receiving this {{.Period}} digest because of your <a href='{{.SettingsUrl}}'>notification settings</a>. <a href='{{.UnsubscribeUrl}}'>Unsubscribe</a></small>"}`},
 		{ID: 35, Key: "tag.follow", Value: `0`},
 		{ID: 36, Key: "rank.question.add", Value: `1`},
 		{ID: 37, Key: "rank.question.edit", Value: `200`},
@@ -344,6 +371,16 @@ func initConfigTable(engine *xorm.Engine) error {
 		{ID: 115, Key: "rank.question.close", Value: `-1`},
 		{ID: 116, Key: "rank.question.reopen", Value: `-1`},
 		{ID: 117, Key: "rank.tag.use_reserved_tag", Value: `-1`},
+		{ID: 118, Key: "question.bounty", Value: `0`},
+		{ID: 119, Key: "answer.bounty_awarded", Value: `0`},
+		{ID: 120, Key: "serial_vote.limit", Value: `5`},
+		{ID: 121, Key: "rank.answer.add_protected", Value: `10`},
+		{ID: 122, Key: "question.locked", Value: `0`},
+		{ID: 123, Key: "question.unlocked", Value: `0`},
+		{ID: 124, Key: "question.protected", Value: `0`},
+		{ID: 125, Key: "question.unprotected", Value: `0`},
+		{ID: 126, Key: "answer.locked", Value: `0`},
+		{ID: 127, Key: "answer.unlocked", Value: `0`},
 	}
 	_, err := engine.Insert(defaultConfigTable)
 	return err
@@ -394,6 +431,11 @@ func initRolePower(engine *xorm.Engine) (err error) {
 		{ID: 31, Name: "answer audit", PowerType: permission.AnswerAudit, Description: "answer audit"},
 		{ID: 32, Name: "question audit", PowerType: permission.QuestionAudit, Description: "question audit"},
 		{ID: 33, Name: "tag audit", PowerType: permission.TagAudit, Description: "tag audit"},
+		{ID: 34, Name: "question merge", PowerType: permission.QuestionMerge, Description: "question merge"},
+		{ID: 35, Name: "question lock", PowerType: permission.QuestionLock, Description: "question lock"},
+		{ID: 36, Name: "question protect", PowerType: permission.QuestionProtect, Description: "question protect"},
+		{ID: 37, Name: "answer lock", PowerType: permission.AnswerLock, Description: "answer lock"},
+		{ID: 38, Name: "answer add protected", PowerType: permission.AnswerAddProtected, Description: "answer add protected"},
 	}
 	_, err = engine.Insert(powers)
 	if err != nil {
@@ -407,14 +449,19 @@ func initRolePower(engine *xorm.Engine) (err error) {
 		{RoleID: 2, PowerType: permission.QuestionEditWithoutReview},
 		{RoleID: 2, PowerType: permission.QuestionDelete},
 		{RoleID: 2, PowerType: permission.QuestionClose},
+		{RoleID: 2, PowerType: permission.QuestionMerge},
+		{RoleID: 2, PowerType: permission.QuestionLock},
+		{RoleID: 2, PowerType: permission.QuestionProtect},
 		{RoleID: 2, PowerType: permission.QuestionReopen},
 		{RoleID: 2, PowerType: permission.QuestionVoteUp},
 		{RoleID: 2, PowerType: permission.QuestionVoteDown},
 		{RoleID: 2, PowerType: permission.AnswerAdd},
+		{RoleID: 2, PowerType: permission.AnswerAddProtected},
 		{RoleID: 2, PowerType: permission.AnswerEdit},
 		{RoleID: 2, PowerType: permission.AnswerEditWithoutReview},
 		{RoleID: 2, PowerType: permission.AnswerDelete},
 		{RoleID: 2, PowerType: permission.AnswerAccept},
+		{RoleID: 2, PowerType: permission.AnswerLock},
 		{RoleID: 2, PowerType: permission.AnswerVoteUp},
 		{RoleID: 2, PowerType: permission.AnswerVoteDown},
 		{RoleID: 2, PowerType: permission.CommentAdd},
@@ -441,14 +488,19 @@ func initRolePower(engine *xorm.Engine) (err error) {
 		{RoleID: 3, PowerType: permission.QuestionEditWithoutReview},
 		{RoleID: 3, PowerType: permission.QuestionDelete},
 		{RoleID: 3, PowerType: permission.QuestionClose},
+		{RoleID: 3, PowerType: permission.QuestionMerge},
+		{RoleID: 3, PowerType: permission.QuestionLock},
+		{RoleID: 3, PowerType: permission.QuestionProtect},
 		{RoleID: 3, PowerType: permission.QuestionReopen},
 		{RoleID: 3, PowerType: permission.QuestionVoteUp},
 		{RoleID: 3, PowerType: permission.QuestionVoteDown},
 		{RoleID: 3, PowerType: permission.AnswerAdd},
+		{RoleID: 3, PowerType: permission.AnswerAddProtected},
 		{RoleID: 3, PowerType: permission.AnswerEdit},
 		{RoleID: 3, PowerType: permission.AnswerEditWithoutReview},
 		{RoleID: 3, PowerType: permission.AnswerDelete},
 		{RoleID: 3, PowerType: permission.AnswerAccept},
+		{RoleID: 3, PowerType: permission.AnswerLock},
 		{RoleID: 3, PowerType: permission.AnswerVoteUp},
 		{RoleID: 3, PowerType: permission.AnswerVoteDown},
 		{RoleID: 3, PowerType: permission.CommentAdd},
@@ -56,6 +56,27 @@ var migrations = []Migration{
 	NewMigration("add user role", addRoleFeatures, false),
 	NewMigration("add theme and private mode", addThemeAndPrivateMode, true),
 	NewMigration("add new answer notification", addNewAnswerNotification, true),
+	NewMigration("add search full-text index", addSearchFullTextIndex, false),
+	NewMigration("add job queue", addJobQueue, false),
+	NewMigration("add external login", addExternalLogin, false),
+	NewMigration("add user two factor", addUserTwoFactor, false),
+	NewMigration("add access token", addAccessToken, false),
+	NewMigration("add webhook", addWebhook, false),
+	NewMigration("add email digest", addEmailDigest, false),
+	NewMigration("add role power tag scope", addRolePowerTagScope, false),
+	NewMigration("add badge", addBadge, false),
+	NewMigration("add question bounty", addQuestionBounty, false),
+	NewMigration("add moderation log", addModerationLog, false),
+	NewMigration("add audit log", addAuditLog, false),
+	NewMigration("add pending post", addPendingPost, false),
+	NewMigration("add spam detection", addSpamDetection, false),
+	NewMigration("add question duplicate", addQuestionDuplicate, false),
+	NewMigration("add post lock", addPostLock, false),
+	NewMigration("add question bounty active guard", addQuestionBountyActiveGuard, false),
+	NewMigration("add message dedup key", addMessageDedupKey, false),
+	NewMigration("add user badge revoked", addUserBadgeRevoked, false),
+	NewMigration("add activity expired at", addActivityExpiredAt, false),
+	NewMigration("add external login mapped role", addExternalLoginMappedRole, false),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -111,7 +132,7 @@ func Migrate(dbConf *data.Database, cacheConf *data.CacheConf) error {
 			fmt.Printf("[migrate] migrate to db version %d failed: %s\n", currentDBVersion+1, err.Error())
 			return err
 		}
-		if migrationFunc.ShouldCleanCache() {
+		if migrationFunc.ShouldCleanCache() && cache != nil {
 			if err := cache.Flush(context.Background()); err != nil {
 				fmt.Printf("[migrate] flush cache failed: %s\n", err.Error())
 			}
@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addUserTwoFactor(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.UserTwoFactor)); err != nil {
+		return fmt.Errorf("sync user two factor table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addAccessToken(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.AccessToken)); err != nil {
+		return fmt.Errorf("sync access token table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addWebhook(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.Webhook), new(entity.WebhookDelivery)); err != nil {
+		return fmt.Errorf("sync webhook table failed: %w", err)
+	}
+	return nil
+}
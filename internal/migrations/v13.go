@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addEmailDigest(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.UserNoticeConfig), new(entity.EmailDigestItem)); err != nil {
+		return fmt.Errorf("sync email digest table failed: %w", err)
+	}
+
+	cond := &entity.Config{Key: "email.config"}
+	exist, err := x.Get(cond)
+	if err != nil {
+		return fmt.Errorf("get email config failed: %w", err)
+	}
+	if !exist {
+		return nil
+	}
+
+	m := make(map[string]interface{})
+	_ = json.Unmarshal([]byte(cond.Value), &m)
+	m["digest_title"] = "[{{.SiteName}}] Your {{.Period}} digest"
+	m["digest_body"] = "{{if .Items}}<strong>Activity on your posts</strong><br><br>\n\n{{range .Items}}<small>{{.DisplayName}} {{if eq .NoticeType \"new_answer\"}}answered{{else}}commented on{{end}}</small> <strong><a href='{{.Url}}'>{{.QuestionTitle}}</a></strong><br>\n<blockquote>{{.Summary}}</blockquote><br>\n{{end}}{{end}}{{if .NewQuestions}}<strong>New questions in tags you follow</strong><br><br>\n\n{{range .NewQuestions}}<a href='{{.Url}}'>{{.Title}}</a><br>\n{{end}}<br>{{end}}\n<small>You are receiving this {{.Period}} digest because of your <a href='{{.SettingsUrl}}'>notification settings</a>. <a href='{{.UnsubscribeUrl}}'>Unsubscribe</a></small>"
+
+	val, _ := json.Marshal(m)
+	_, err = x.ID(cond.ID).Update(&entity.Config{Value: string(val)})
+	if err != nil {
+		return fmt.Errorf("update email config failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addRolePowerTagScope(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.RolePowerRel)); err != nil {
+		return fmt.Errorf("sync role power rel table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addBadge(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.Badge), new(entity.UserBadge), new(entity.UserVisit)); err != nil {
+		return fmt.Errorf("sync badge table failed: %w", err)
+	}
+	count, err := x.Count(&entity.Badge{})
+	if err != nil {
+		return fmt.Errorf("count badge failed: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	if err = initBadge(x); err != nil {
+		return fmt.Errorf("init badge failed: %w", err)
+	}
+	return nil
+}
+
+// initBadge add the built-in badges
+func initBadge(engine *xorm.Engine) error {
+	badges := []*entity.Badge{
+		{Name: "Solver", Description: "Answer accepted for the first time.", Level: entity.BadgeLevelBronze,
+			Rule: entity.BadgeRuleAcceptedAnswers, Threshold: 1, Enabled: true},
+		{Name: "Tag Expert", Description: "10 upvoted answers in a tag.", Level: entity.BadgeLevelSilver,
+			Repeatable: true, Rule: entity.BadgeRuleTagUpvotedAnswers, Threshold: 10, Enabled: true},
+		{Name: "Good Question", Description: "Question with 25 votes.", Level: entity.BadgeLevelSilver,
+			Repeatable: true, Rule: entity.BadgeRuleQuestionVotes, Threshold: 25, Enabled: true},
+		{Name: "Fanatic", Description: "Visited the site on 100 days.", Level: entity.BadgeLevelGold,
+			Rule: entity.BadgeRuleVisitDays, Threshold: 100, Enabled: true},
+	}
+	_, err := engine.Insert(badges)
+	return err
+}
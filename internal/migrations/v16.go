@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addQuestionBounty(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.QuestionBounty)); err != nil {
+		return fmt.Errorf("sync question bounty table failed: %w", err)
+	}
+
+	defaultConfigTable := []*entity.Config{
+		{ID: 118, Key: "question.bounty", Value: `0`},
+		{ID: 119, Key: "answer.bounty_awarded", Value: `0`},
+	}
+	for _, c := range defaultConfigTable {
+		exist, err := x.Get(&entity.Config{ID: c.ID, Key: c.Key})
+		if err != nil {
+			return fmt.Errorf("get config failed: %w", err)
+		}
+		if exist {
+			continue
+		}
+		if _, err = x.Insert(&entity.Config{ID: c.ID, Key: c.Key, Value: c.Value}); err != nil {
+			return fmt.Errorf("add config failed: %w", err)
+		}
+	}
+
+	// the bounty is paid by the reputation the user already has, so it is not limited by the daily limit
+	cond := &entity.Config{Key: "daily_rank_limit.exclude"}
+	exist, err := x.Get(cond)
+	if err != nil {
+		return fmt.Errorf("get config failed: %w", err)
+	}
+	if !exist {
+		return nil
+	}
+	exclude := make([]string, 0)
+	_ = json.Unmarshal([]byte(cond.Value), &exclude)
+	for _, key := range []string{"question.bounty", "answer.bounty_awarded"} {
+		if !containsString(exclude, key) {
+			exclude = append(exclude, key)
+		}
+	}
+	val, _ := json.Marshal(exclude)
+	if _, err = x.ID(cond.ID).Update(&entity.Config{Value: string(val)}); err != nil {
+		return fmt.Errorf("update config failed: %w", err)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
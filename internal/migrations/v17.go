@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addModerationLog(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.ModerationLog)); err != nil {
+		return fmt.Errorf("sync moderation log table failed: %w", err)
+	}
+
+	defaultConfigTable := []*entity.Config{
+		{ID: 120, Key: "serial_vote.limit", Value: `5`},
+	}
+	for _, c := range defaultConfigTable {
+		exist, err := x.Get(&entity.Config{ID: c.ID, Key: c.Key})
+		if err != nil {
+			return fmt.Errorf("get config failed: %w", err)
+		}
+		if exist {
+			continue
+		}
+		if _, err = x.Insert(&entity.Config{ID: c.ID, Key: c.Key, Value: c.Value}); err != nil {
+			return fmt.Errorf("add config failed: %w", err)
+		}
+	}
+	return nil
+}
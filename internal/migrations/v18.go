@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addAuditLog(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.AuditLog)); err != nil {
+		return fmt.Errorf("sync audit log table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addPendingPost(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.PendingPost)); err != nil {
+		return fmt.Errorf("sync pending post table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addSpamDetection(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.SpamLog), new(entity.SpamToken)); err != nil {
+		return fmt.Errorf("sync spam table failed: %w", err)
+	}
+	return nil
+}
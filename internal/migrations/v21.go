@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/permission"
+	"xorm.io/xorm"
+)
+
+func addQuestionDuplicate(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.QuestionDuplicate)); err != nil {
+		return fmt.Errorf("sync question duplicate table failed: %w", err)
+	}
+
+	power := &entity.Power{ID: 34, Name: "question merge", PowerType: permission.QuestionMerge, Description: "question merge"}
+	exist, err := x.Get(&entity.Power{ID: power.ID})
+	if err != nil {
+		return fmt.Errorf("get power failed: %w", err)
+	}
+	if !exist {
+		if _, err = x.Insert(power); err != nil {
+			return fmt.Errorf("add power failed: %w", err)
+		}
+	}
+
+	rolePowerRels := []*entity.RolePowerRel{
+		{RoleID: 2, PowerType: permission.QuestionMerge},
+		{RoleID: 3, PowerType: permission.QuestionMerge},
+	}
+	for _, rel := range rolePowerRels {
+		exist, err := x.Get(&entity.RolePowerRel{RoleID: rel.RoleID, PowerType: rel.PowerType})
+		if err != nil {
+			return fmt.Errorf("get role power rel failed: %w", err)
+		}
+		if exist {
+			continue
+		}
+		if _, err = x.Insert(rel); err != nil {
+			return fmt.Errorf("add role power rel failed: %w", err)
+		}
+	}
+	return nil
+}
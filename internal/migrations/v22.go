@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/permission"
+	"xorm.io/xorm"
+)
+
+func addPostLock(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.Question), new(entity.Answer)); err != nil {
+		return fmt.Errorf("sync question and answer table failed: %w", err)
+	}
+
+	defaultConfigTable := []*entity.Config{
+		{ID: 121, Key: "rank.answer.add_protected", Value: `10`},
+		{ID: 122, Key: "question.locked", Value: `0`},
+		{ID: 123, Key: "question.unlocked", Value: `0`},
+		{ID: 124, Key: "question.protected", Value: `0`},
+		{ID: 125, Key: "question.unprotected", Value: `0`},
+		{ID: 126, Key: "answer.locked", Value: `0`},
+		{ID: 127, Key: "answer.unlocked", Value: `0`},
+	}
+	for _, c := range defaultConfigTable {
+		exist, err := x.Get(&entity.Config{ID: c.ID, Key: c.Key})
+		if err != nil {
+			return fmt.Errorf("get config failed: %w", err)
+		}
+		if exist {
+			continue
+		}
+		if _, err = x.Insert(&entity.Config{ID: c.ID, Key: c.Key, Value: c.Value}); err != nil {
+			return fmt.Errorf("add config failed: %w", err)
+		}
+	}
+
+	powers := []*entity.Power{
+		{ID: 35, Name: "question lock", PowerType: permission.QuestionLock, Description: "question lock"},
+		{ID: 36, Name: "question protect", PowerType: permission.QuestionProtect, Description: "question protect"},
+		{ID: 37, Name: "answer lock", PowerType: permission.AnswerLock, Description: "answer lock"},
+		{ID: 38, Name: "answer add protected", PowerType: permission.AnswerAddProtected, Description: "answer add protected"},
+	}
+	for _, power := range powers {
+		exist, err := x.Get(&entity.Power{ID: power.ID})
+		if err != nil {
+			return fmt.Errorf("get power failed: %w", err)
+		}
+		if exist {
+			continue
+		}
+		if _, err = x.Insert(power); err != nil {
+			return fmt.Errorf("add power failed: %w", err)
+		}
+	}
+
+	rolePowerRels := make([]*entity.RolePowerRel, 0)
+	for _, roleID := range []int{2, 3} {
+		for _, power := range powers {
+			rolePowerRels = append(rolePowerRels, &entity.RolePowerRel{RoleID: roleID, PowerType: power.PowerType})
+		}
+	}
+	for _, rel := range rolePowerRels {
+		exist, err := x.Get(&entity.RolePowerRel{RoleID: rel.RoleID, PowerType: rel.PowerType})
+		if err != nil {
+			return fmt.Errorf("get role power rel failed: %w", err)
+		}
+		if exist {
+			continue
+		}
+		if _, err = x.Insert(rel); err != nil {
+			return fmt.Errorf("add role power rel failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addQuestionBountyActiveGuard(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.QuestionBounty)); err != nil {
+		return fmt.Errorf("sync question bounty table failed: %w", err)
+	}
+
+	bounties := make([]*entity.QuestionBounty, 0)
+	err := x.Where("status = ?", entity.QuestionBountyStatusActive).Asc("id").Find(&bounties)
+	if err != nil {
+		return fmt.Errorf("get active bounties failed: %w", err)
+	}
+	// only the first active bounty of a question is guarded, the others are closed by the expiry as before
+	guarded := make(map[string]bool)
+	for _, bounty := range bounties {
+		if guarded[bounty.QuestionID] {
+			continue
+		}
+		guarded[bounty.QuestionID] = true
+		_, err = x.ID(bounty.ID).Cols("active_question_id").
+			Update(&entity.QuestionBounty{ActiveQuestionID: bounty.QuestionID})
+		if err != nil {
+			return fmt.Errorf("update active bounty failed: %w", err)
+		}
+	}
+	return nil
+}
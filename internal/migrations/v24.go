@@ -0,0 +1,18 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addMessageDedupKey(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.Activity)); err != nil {
+		return fmt.Errorf("sync activity table failed: %w", err)
+	}
+	if err := x.Sync(new(entity.Notification)); err != nil {
+		return fmt.Errorf("sync notification table failed: %w", err)
+	}
+	return nil
+}
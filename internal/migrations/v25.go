@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addUserBadgeRevoked(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.UserBadge)); err != nil {
+		return fmt.Errorf("sync user badge table failed: %w", err)
+	}
+	return nil
+}
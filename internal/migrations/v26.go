@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addActivityExpiredAt(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.Activity)); err != nil {
+		return fmt.Errorf("sync activity table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/segmentfault/pacman/log"
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+// addSearchFullTextIndex create the native full-text index used by search.
+// The index columns and expressions must be the same as the ones used in search repo,
+// otherwise the database can not use the index.
+// The default parsers do not split the CJK text and skip the short words, they are searched by LIKE matching.
+func addSearchFullTextIndex(x *xorm.Engine) (err error) {
+	switch x.Dialect().URI().DBType {
+	case schemas.MYSQL:
+		return addMySQLFullTextIndex(x)
+	case schemas.POSTGRES:
+		return addPostgresFullTextIndex(x)
+	case schemas.SQLITE:
+		return addSQLiteFullTextTable(x)
+	}
+	return nil
+}
+
+func addMySQLFullTextIndex(x *xorm.Engine) (err error) {
+	indexes := []struct {
+		table, name, columns string
+	}{
+		{table: "question", name: "FT_question_title_text", columns: "`title`, `original_text`"},
+		{table: "answer", name: "FT_answer_text", columns: "`original_text`"},
+	}
+	for _, idx := range indexes {
+		var count int64
+		_, err = x.SQL("SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?",
+			idx.table, idx.name).Get(&count)
+		if err != nil {
+			return fmt.Errorf("check full-text index %s failed: %w", idx.name, err)
+		}
+		if count > 0 {
+			continue
+		}
+		_, err = x.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD FULLTEXT INDEX `%s` (%s)", idx.table, idx.name, idx.columns))
+		if err != nil {
+			return fmt.Errorf("create full-text index %s failed: %w", idx.name, err)
+		}
+	}
+	return nil
+}
+
+func addPostgresFullTextIndex(x *xorm.Engine) (err error) {
+	_, err = x.Exec(`CREATE INDEX IF NOT EXISTS "FT_question_title_text" ON "question" ` +
+		`USING GIN (to_tsvector('english', "title" || ' ' || "original_text"))`)
+	if err != nil {
+		return fmt.Errorf("create question full-text index failed: %w", err)
+	}
+	_, err = x.Exec(`CREATE INDEX IF NOT EXISTS "FT_answer_text" ON "answer" ` +
+		`USING GIN (to_tsvector('english', "original_text"))`)
+	if err != nil {
+		return fmt.Errorf("create answer full-text index failed: %w", err)
+	}
+	return nil
+}
+
+// addSQLiteFullTextTable create the FTS5 shadow table of question and answer, which is kept in sync by triggers.
+// The FTS5 module is only available when the binary is built with the sqlite_fts5 tag,
+// search falls back to LIKE matching if the shadow table can not be created.
+func addSQLiteFullTextTable(x *xorm.Engine) (err error) {
+	_, err = x.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS "question_fts" USING fts5("title", "original_text", tokenize = 'porter unicode61')`)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			log.Warnf("sqlite fts5 module is not available, search will not use the full-text index: %s", err)
+			return nil
+		}
+		return fmt.Errorf("create question fts table failed: %w", err)
+	}
+	_, err = x.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS "answer_fts" USING fts5("original_text", tokenize = 'porter unicode61')`)
+	if err != nil {
+		return fmt.Errorf("create answer fts table failed: %w", err)
+	}
+
+	sqlList := []string{
+		`CREATE TRIGGER IF NOT EXISTS "question_fts_insert" AFTER INSERT ON "question" BEGIN
+  INSERT INTO "question_fts" ("rowid", "title", "original_text") VALUES (new."id", new."title", new."original_text");
+END`,
+		`CREATE TRIGGER IF NOT EXISTS "question_fts_update" AFTER UPDATE OF "title", "original_text" ON "question" BEGIN
+  DELETE FROM "question_fts" WHERE "rowid" = old."id";
+  INSERT INTO "question_fts" ("rowid", "title", "original_text") VALUES (new."id", new."title", new."original_text");
+END`,
+		`CREATE TRIGGER IF NOT EXISTS "question_fts_delete" AFTER DELETE ON "question" BEGIN
+  DELETE FROM "question_fts" WHERE "rowid" = old."id";
+END`,
+		`CREATE TRIGGER IF NOT EXISTS "answer_fts_insert" AFTER INSERT ON "answer" BEGIN
+  INSERT INTO "answer_fts" ("rowid", "original_text") VALUES (new."id", new."original_text");
+END`,
+		`CREATE TRIGGER IF NOT EXISTS "answer_fts_update" AFTER UPDATE OF "original_text" ON "answer" BEGIN
+  DELETE FROM "answer_fts" WHERE "rowid" = old."id";
+  INSERT INTO "answer_fts" ("rowid", "original_text") VALUES (new."id", new."original_text");
+END`,
+		`CREATE TRIGGER IF NOT EXISTS "answer_fts_delete" AFTER DELETE ON "answer" BEGIN
+  DELETE FROM "answer_fts" WHERE "rowid" = old."id";
+END`,
+		`DELETE FROM "question_fts"`,
+		`INSERT INTO "question_fts" ("rowid", "title", "original_text") SELECT "id", "title", "original_text" FROM "question"`,
+		`DELETE FROM "answer_fts"`,
+		`INSERT INTO "answer_fts" ("rowid", "original_text") SELECT "id", "original_text" FROM "answer"`,
+	}
+	for _, sql := range sqlList {
+		if _, err = x.Exec(sql); err != nil {
+			return fmt.Errorf("init fts table failed: %w", err)
+		}
+	}
+	return nil
+}
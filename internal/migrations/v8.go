@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addJobQueue(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.Job)); err != nil {
+		return fmt.Errorf("sync job table failed: %w", err)
+	}
+	return nil
+}
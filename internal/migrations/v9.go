@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/answerdev/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+func addExternalLogin(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.ExternalLoginUserInfo)); err != nil {
+		return fmt.Errorf("sync external login user info table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package access_token
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/access_token"
+	"github.com/segmentfault/pacman/errors"
+)
+
+type accessTokenRepo struct {
+	data *data.Data
+}
+
+// NewAccessTokenRepo new repository
+func NewAccessTokenRepo(data *data.Data) access_token.AccessTokenRepo {
+	return &accessTokenRepo{
+		data: data,
+	}
+}
+
+// AddAccessToken add access token
+func (ar *accessTokenRepo) AddAccessToken(ctx context.Context, token *entity.AccessToken) (err error) {
+	_, err = ar.data.DB.Insert(token)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetAccessToken get access token by id
+func (ar *accessTokenRepo) GetAccessToken(ctx context.Context, id string) (
+	token *entity.AccessToken, exist bool, err error) {
+	token = &entity.AccessToken{}
+	exist, err = ar.data.DB.ID(id).Get(token)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetByTokenHash get access token by the hash of token
+func (ar *accessTokenRepo) GetByTokenHash(ctx context.Context, tokenHash string) (
+	token *entity.AccessToken, exist bool, err error) {
+	token = &entity.AccessToken{}
+	exist, err = ar.data.DB.Where("token_hash = ?", tokenHash).Get(token)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserAccessTokens get all access tokens of user, the newest first
+func (ar *accessTokenRepo) GetUserAccessTokens(ctx context.Context, userID string) (
+	tokens []*entity.AccessToken, err error) {
+	tokens = make([]*entity.AccessToken, 0)
+	err = ar.data.DB.Where("user_id = ?", userID).Desc("id").Find(&tokens)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateLastUsedAt update the last used time of access token
+func (ar *accessTokenRepo) UpdateLastUsedAt(ctx context.Context, id string, lastUsedAt time.Time) (err error) {
+	_, err = ar.data.DB.ID(id).Cols("last_used_at").NoAutoTime().
+		Update(&entity.AccessToken{LastUsedAt: lastUsedAt})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveAccessToken remove access token
+func (ar *accessTokenRepo) RemoveAccessToken(ctx context.Context, id string) (err error) {
+	_, err = ar.data.DB.ID(id).Delete(&entity.AccessToken{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
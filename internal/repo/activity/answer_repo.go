@@ -164,14 +164,12 @@ func (ar *AnswerActivityRepo) AcceptAnswer(ctx context.Context,
 				continue
 			}
 
-			reachStandard, e := ar.userRankRepo.TriggerUserRank(
+			appliedRank, e := ar.userRankRepo.TriggerUserRank(
 				ctx, session, addActivity.UserID, addActivity.Rank, addActivity.ActivityType)
 			if e != nil {
 				return nil, errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
 			}
-			if reachStandard {
-				addActivity.Rank = 0
-			}
+			addActivity.Rank = appliedRank
 
 			if exists {
 				if _, e = session.Where("id = ?", existsActivity.ID).Cols("`cancelled`").
@@ -70,7 +70,7 @@ func (ar *UserActiveActivityRepo) UserActive(ctx context.Context, userID string)
 			return nil, nil
 		}
 
-		_, err = ar.userRankRepo.TriggerUserRank(ctx, session, addActivity.UserID, addActivity.Rank, activityType)
+		addActivity.Rank, err = ar.userRankRepo.TriggerUserRank(ctx, session, addActivity.UserID, addActivity.Rank, activityType)
 		if err != nil {
 			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
@@ -119,21 +119,21 @@ func (vr *VoteRepo) vote(ctx context.Context, objectID string, userID, objectUse
 
 			// trigger user rank and send notification
 			if hasRank != 0 {
-				var isReachStandard bool
-				isReachStandard, err = vr.userRankRepo.TriggerUserRank(ctx, session, activityUserID, deltaRank, activityType)
+				var appliedRank int
+				appliedRank, err = vr.userRankRepo.TriggerUserRank(ctx, session, activityUserID, deltaRank, activityType)
 				if err != nil {
 					return nil, err
 				}
-				if isReachStandard {
-					insertActivity.Rank = 0
-				}
+				insertActivity.Rank = appliedRank
 				notificationUserIDs = append(notificationUserIDs, activityUserID)
 			}
 
 			if has {
-				if _, err = session.Where("id = ?", existsActivity.ID).Cols("`cancelled`").
+				// the rank may be limited this time, so it is updated with the cancelled status
+				if _, err = session.Where("id = ?", existsActivity.ID).Cols("`cancelled`", "`rank`").
 					Update(&entity.Activity{
 						Cancelled: entity.ActivityAvailable,
+						Rank:      insertActivity.Rank,
 					}); err != nil {
 					return
 				}
@@ -181,12 +181,11 @@ func (vr *VoteRepo) voteCancel(ctx context.Context, objectID string, userID, obj
 				has            bool
 				triggerUserID,
 				activityUserID string
-				activityType,
-				deltaRank, hasRank int
+				activityType, hasRank int
 			)
 			result = nil
 
-			activityUserID, activityType, deltaRank, hasRank, err = vr.CheckRank(ctx, objectID, objectUserID, userID, action)
+			activityUserID, activityType, _, hasRank, err = vr.CheckRank(ctx, objectID, objectUserID, userID, action)
 			if err != nil {
 				return
 			}
@@ -221,7 +220,7 @@ func (vr *VoteRepo) voteCancel(ctx context.Context, objectID string, userID, obj
 
 			// trigger user rank and send notification
 			if hasRank != 0 && existsActivity.Rank != 0 {
-				_, err = vr.userRankRepo.TriggerUserRank(ctx, session, activityUserID, -deltaRank, activityType)
+				_, err = vr.userRankRepo.TriggerUserRank(ctx, session, activityUserID, -existsActivity.Rank, activityType)
 				if err != nil {
 					return
 				}
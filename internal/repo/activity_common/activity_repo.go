@@ -102,7 +102,27 @@ func (ar *ActivityRepo) GetUserIDObjectIDActivitySum(ctx context.Context, userID
 
 // AddActivity add activity
 func (ar *ActivityRepo) AddActivity(ctx context.Context, activity *entity.Activity) (err error) {
+	if activity.DedupKey != nil {
+		exist, err := ar.existDedupKey(*activity.DedupKey)
+		if err != nil || exist {
+			return err
+		}
+	}
 	_, err = ar.data.DB.Insert(activity)
+	if err == nil {
+		return nil
+	}
+	// the same message handled at the same time is rejected by the unique dedup key
+	if activity.DedupKey != nil {
+		if exist, _ := ar.existDedupKey(*activity.DedupKey); exist {
+			return nil
+		}
+	}
+	return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+}
+
+func (ar *ActivityRepo) existDedupKey(dedupKey string) (exist bool, err error) {
+	exist, err = ar.data.DB.Where("dedup_key = ?", dedupKey).Exist(&entity.Activity{})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -0,0 +1,60 @@
+package audit_log
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/pager"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+// auditLogRepo audit log repository, the logs are never updated or deleted
+type auditLogRepo struct {
+	data *data.Data
+}
+
+// NewAuditLogRepo new repository
+func NewAuditLogRepo(data *data.Data) audit_log.AuditLogRepo {
+	return &auditLogRepo{
+		data: data,
+	}
+}
+
+// AddAuditLog add audit log
+func (ar *auditLogRepo) AddAuditLog(ctx context.Context, auditLog *entity.AuditLog) (err error) {
+	_, err = ar.data.DB.Insert(auditLog)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetAuditLogPage get audit log page, the latest first
+func (ar *auditLogRepo) GetAuditLogPage(ctx context.Context, page, pageSize int, cond *entity.AuditLog,
+	startTime, endTime time.Time) (logs []*entity.AuditLog, total int64, err error) {
+	logs = make([]*entity.AuditLog, 0)
+	session := ar.timeRangeSession(startTime, endTime)
+	session.Desc("id")
+	total, err = pager.Help(page, pageSize, &logs, cond, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// timeRangeSession the zero time means no limit
+func (ar *auditLogRepo) timeRangeSession(startTime, endTime time.Time) *xorm.Session {
+	session := ar.data.DB.NewSession()
+	if !startTime.IsZero() {
+		session.Where("created_at >= ?", startTime)
+	}
+	if !endTime.IsZero() {
+		session.Where("created_at <= ?", endTime)
+	}
+	return session
+}
@@ -0,0 +1,232 @@
+package badge
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/pager"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/badge"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	userVisitCacheKey  = "answer:user:visit:"
+	userVisitCacheTime = 24 * time.Hour
+)
+
+type badgeRepo struct {
+	data *data.Data
+}
+
+// NewBadgeRepo new repository
+func NewBadgeRepo(data *data.Data) badge.BadgeRepo {
+	return &badgeRepo{
+		data: data,
+	}
+}
+
+// AddBadge add badge
+func (br *badgeRepo) AddBadge(ctx context.Context, badge *entity.Badge) (err error) {
+	_, err = br.data.DB.Insert(badge)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateBadge update badge
+func (br *badgeRepo) UpdateBadge(ctx context.Context, badge *entity.Badge) (err error) {
+	_, err = br.data.DB.ID(badge.ID).
+		Cols("name", "description", "level", "repeatable", "rule", "threshold", "enabled").Update(badge)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetBadge get badge by id
+func (br *badgeRepo) GetBadge(ctx context.Context, id string) (badge *entity.Badge, exist bool, err error) {
+	badge = &entity.Badge{}
+	exist, err = br.data.DB.ID(id).Get(badge)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetBadgeList get all badges
+func (br *badgeRepo) GetBadgeList(ctx context.Context) (badges []*entity.Badge, err error) {
+	badges = make([]*entity.Badge, 0)
+	err = br.data.DB.Asc("id").Find(&badges)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetEnabledBadges get the enabled badges which are awarded by rule
+func (br *badgeRepo) GetEnabledBadges(ctx context.Context) (badges []*entity.Badge, err error) {
+	badges = make([]*entity.Badge, 0)
+	err = br.data.DB.Where("enabled = ? AND rule <> ?", true, entity.BadgeRuleManual).Asc("id").Find(&badges)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetBadgeAwardCounts get the number of times each badge has been awarded
+func (br *badgeRepo) GetBadgeAwardCounts(ctx context.Context) (counts map[string]int64, err error) {
+	rows := make([]*struct {
+		BadgeID string `xorm:"badge_id"`
+		Count   int64  `xorm:"award_count"`
+	}, 0)
+	err = br.data.DB.Table(entity.UserBadge{}.TableName()).Select("badge_id, COUNT(*) AS award_count").
+		Where("revoked = ?", false).GroupBy("badge_id").Find(&rows)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	counts = make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.BadgeID] = row.Count
+	}
+	return counts, nil
+}
+
+// AddUserBadge award badge to user
+func (br *badgeRepo) AddUserBadge(ctx context.Context, userBadge *entity.UserBadge) (err error) {
+	_, err = br.data.DB.Insert(userBadge)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RevokeUserBadge revoke the badge awarded to user, the record is kept as revoked
+func (br *badgeRepo) RevokeUserBadge(ctx context.Context, id string) (err error) {
+	_, err = br.data.DB.ID(id).Cols("revoked").Update(&entity.UserBadge{Revoked: true})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RestoreUserBadge award the revoked badge to user again
+func (br *badgeRepo) RestoreUserBadge(ctx context.Context, id, awardUserID string) (err error) {
+	_, err = br.data.DB.ID(id).Cols("revoked", "award_user_id").
+		Update(&entity.UserBadge{Revoked: false, AwardUserID: awardUserID})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserBadge get the badge awarded to user by id, the revoked badge is not included
+func (br *badgeRepo) GetUserBadge(ctx context.Context, id string) (userBadge *entity.UserBadge, exist bool, err error) {
+	userBadge = &entity.UserBadge{}
+	exist, err = br.data.DB.ID(id).Where("revoked = ?", false).Get(userBadge)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserBadgeByObject get the badge awarded to user for the object, the revoked badge is included
+func (br *badgeRepo) GetUserBadgeByObject(ctx context.Context, userID, badgeID, objectID string) (
+	userBadge *entity.UserBadge, exist bool, err error) {
+	userBadge = &entity.UserBadge{}
+	exist, err = br.data.DB.Where("user_id = ? AND badge_id = ? AND object_id = ?", userID, badgeID, objectID).
+		Get(userBadge)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserBadgeList get all badges awarded to user
+func (br *badgeRepo) GetUserBadgeList(ctx context.Context, userID string) (userBadges []*entity.UserBadge, err error) {
+	userBadges = make([]*entity.UserBadge, 0)
+	err = br.data.DB.Where("user_id = ? AND revoked = ?", userID, false).Asc("id").Find(&userBadges)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserBadgePage get the awarded badges, the newest first
+func (br *badgeRepo) GetUserBadgePage(ctx context.Context, page, pageSize int, cond *entity.UserBadge) (
+	userBadges []*entity.UserBadge, total int64, err error) {
+	userBadges = make([]*entity.UserBadge, 0)
+	session := br.data.DB.Where("revoked = ?", false).Desc("id")
+	if len(cond.BadgeID) > 0 {
+		session.And("badge_id = ?", cond.BadgeID)
+	}
+	if len(cond.UserID) > 0 {
+		session.And("user_id = ?", cond.UserID)
+	}
+	total, err = pager.Help(page, pageSize, &userBadges, &entity.UserBadge{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CountAcceptedAnswers count the available accepted answers of user
+func (br *badgeRepo) CountAcceptedAnswers(ctx context.Context, userID string) (count int64, err error) {
+	count, err = br.data.DB.Where("user_id = ? AND adopted = ? AND status = ?",
+		userID, schema.AnswerAcceptedEnable, entity.AnswerStatusAvailable).Count(&entity.Answer{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CountUpvotedAnswersInTag count the available answers of user with positive votes in the questions of tag
+func (br *badgeRepo) CountUpvotedAnswersInTag(ctx context.Context, userID, tagID string) (count int64, err error) {
+	count, err = br.data.DB.Table(entity.Answer{}.TableName()).
+		Join("INNER", "tag_rel", "answer.question_id = tag_rel.object_id").
+		Where("answer.user_id = ? AND answer.status = ? AND answer.vote_count > 0",
+			userID, entity.AnswerStatusAvailable).
+		And("tag_rel.tag_id = ? AND tag_rel.status = ?", tagID, entity.TagRelStatusAvailable).
+		Count()
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AddUserVisit record user visited on the date, it returns false if the visit has been recorded
+func (br *badgeRepo) AddUserVisit(ctx context.Context, userID, visitDate string) (added bool, err error) {
+	cacheKey := userVisitCacheKey + userID
+	if cached, cacheErr := br.data.Cache.GetString(ctx, cacheKey); cacheErr == nil && cached == visitDate {
+		return false, nil
+	}
+
+	exist, err := br.data.DB.Where("user_id = ? AND visit_date = ?", userID, visitDate).Exist(&entity.UserVisit{})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !exist {
+		_, err = br.data.DB.Insert(&entity.UserVisit{UserID: userID, VisitDate: visitDate})
+		if err != nil {
+			return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+	}
+	if err = br.data.Cache.SetString(ctx, cacheKey, visitDate, userVisitCacheTime); err != nil {
+		log.Error(err)
+	}
+	return !exist, nil
+}
+
+// CountUserVisitDays count the days user visited
+func (br *badgeRepo) CountUserVisitDays(ctx context.Context, userID string) (count int64, err error) {
+	count, err = br.data.DB.Where("user_id = ?", userID).Count(&entity.UserVisit{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,213 @@
+package bounty
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/activity_common"
+	"github.com/answerdev/answer/internal/service/bounty"
+	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/answerdev/answer/pkg/converter"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+type bountyRepo struct {
+	data         *data.Data
+	activityRepo activity_common.ActivityRepo
+	userRankRepo rank.UserRankRepo
+}
+
+// NewBountyRepo new repository
+func NewBountyRepo(
+	data *data.Data,
+	activityRepo activity_common.ActivityRepo,
+	userRankRepo rank.UserRankRepo,
+) bounty.BountyRepo {
+	return &bountyRepo{
+		data:         data,
+		activityRepo: activityRepo,
+		userRankRepo: userRankRepo,
+	}
+}
+
+// AddBounty add bounty and debit the amount from the user who offers it
+func (br *bountyRepo) AddBounty(ctx context.Context, bounty *entity.QuestionBounty) (err error) {
+	activityType, err := br.activityRepo.GetActivityTypeByConfigKey(ctx, string(constant.ActQuestionBounty))
+	if err != nil {
+		return err
+	}
+
+	_, err = br.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		exist, err := session.Where("question_id = ? AND status = ?",
+			bounty.QuestionID, entity.QuestionBountyStatusActive).Exist(&entity.QuestionBounty{})
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if exist {
+			return nil, errors.BadRequest(reason.BountyAlreadyExist)
+		}
+
+		// the unique active question id rejects the concurrent bounty on the same question
+		bounty.ActiveQuestionID = bounty.QuestionID
+		if _, err = session.Insert(bounty); err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		// the amount is debited only if the user keeps at least 1 reputation after paying the bounty
+		debited, err := br.userRankRepo.DebitUserRank(ctx, session, bounty.UserID, bounty.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if !debited {
+			exist, err = session.ID(bounty.UserID).Exist(&entity.User{})
+			if err != nil {
+				return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+			}
+			if !exist {
+				return nil, errors.BadRequest(reason.UserNotFound)
+			}
+			return nil, errors.BadRequest(reason.BountyRankNotEnough)
+		}
+		_, err = session.Insert(&entity.Activity{
+			UserID:           bounty.UserID,
+			ObjectID:         bounty.QuestionID,
+			OriginalObjectID: bounty.QuestionID,
+			ActivityType:     activityType,
+			Rank:             -bounty.Amount,
+			HasRank:          1,
+		})
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		return nil, nil
+	})
+	if myErr, ok := err.(*errors.Error); ok && errors.IsInternalServer(myErr) {
+		// the bounty added by the concurrent request is committed first
+		if _, exist, _ := br.GetActiveBounty(ctx, bounty.QuestionID); exist {
+			return errors.BadRequest(reason.BountyAlreadyExist)
+		}
+	}
+	return err
+}
+
+// AwardBounty award the bounty to the answer and credit the amount to the answerer
+func (br *bountyRepo) AwardBounty(ctx context.Context, bounty *entity.QuestionBounty, answer *entity.Answer) (err error) {
+	activityType, err := br.activityRepo.GetActivityTypeByConfigKey(ctx, string(constant.ActAnswerBountyAwarded))
+	if err != nil {
+		return err
+	}
+
+	_, err = br.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		affected, err := session.Table(&entity.QuestionBounty{}).
+			Where("id = ? AND status = ?", bounty.ID, entity.QuestionBountyStatusActive).
+			Update(map[string]any{
+				"status":             entity.QuestionBountyStatusAwarded,
+				"answer_id":          answer.ID,
+				"active_question_id": nil,
+			})
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if affected == 0 {
+			return nil, errors.BadRequest(reason.BountyNotFound)
+		}
+
+		appliedRank, err := br.userRankRepo.TriggerUserRank(ctx, session, answer.UserID, bounty.Amount, activityType)
+		if err != nil {
+			return nil, err
+		}
+		_, err = session.Insert(&entity.Activity{
+			UserID:           answer.UserID,
+			TriggerUserID:    converter.StringToInt64(bounty.UserID),
+			ObjectID:         answer.ID,
+			OriginalObjectID: bounty.QuestionID,
+			ActivityType:     activityType,
+			Rank:             appliedRank,
+			HasRank:          1,
+		})
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// RefundBounty return the amount to the user who offered the bounty by cancelling the activity of it
+func (br *bountyRepo) RefundBounty(ctx context.Context, bounty *entity.QuestionBounty) (err error) {
+	activityType, err := br.activityRepo.GetActivityTypeByConfigKey(ctx, string(constant.ActQuestionBounty))
+	if err != nil {
+		return err
+	}
+
+	_, err = br.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		affected, err := session.Table(&entity.QuestionBounty{}).
+			Where("id = ? AND status = ?", bounty.ID, entity.QuestionBountyStatusActive).
+			Update(map[string]any{
+				"status":             entity.QuestionBountyStatusRefunded,
+				"active_question_id": nil,
+			})
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if affected == 0 {
+			return nil, errors.BadRequest(reason.BountyNotFound)
+		}
+
+		// only one bounty is open on a question at the same time, so the latest one is the activity of it
+		act := &entity.Activity{}
+		exist, err := session.Where(builder.Eq{"object_id": bounty.QuestionID}).
+			And(builder.Eq{"user_id": bounty.UserID}).
+			And(builder.Eq{"activity_type": activityType}).
+			Desc("id").Get(act)
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		// the activity has been rolled back if the question was deleted
+		if !exist || act.Cancelled == entity.ActivityCancelled {
+			log.Infof("bounty %s activity has been cancelled, skip refund", bounty.ID)
+			return nil, nil
+		}
+
+		if _, err = br.userRankRepo.TriggerUserRank(ctx, session, bounty.UserID, -act.Rank, activityType); err != nil {
+			return nil, err
+		}
+		_, err = session.ID(act.ID).Cols("cancelled", "cancelled_at").
+			Update(&entity.Activity{Cancelled: entity.ActivityCancelled, CancelledAt: time.Now()})
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// GetActiveBounty get the open bounty of question
+func (br *bountyRepo) GetActiveBounty(ctx context.Context, questionID string) (
+	bounty *entity.QuestionBounty, exist bool, err error) {
+	bounty = &entity.QuestionBounty{}
+	exist, err = br.data.DB.Where("question_id = ? AND status = ?", questionID, entity.QuestionBountyStatusActive).
+		Get(bounty)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetExpiredBounties get the open bounties which are expired before the time
+func (br *bountyRepo) GetExpiredBounties(ctx context.Context, before time.Time) (
+	bounties []*entity.QuestionBounty, err error) {
+	bounties = make([]*entity.QuestionBounty, 0)
+	err = br.data.DB.Where("status = ? AND expired_at <= ?", entity.QuestionBountyStatusActive, before).
+		Asc("expired_at").Find(&bounties)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -67,6 +67,15 @@ func (cr *commentRepo) UpdateComment(ctx context.Context, comment *entity.Commen
 	return
 }
 
+// UpdateCommentStatus update comment status
+func (cr *commentRepo) UpdateCommentStatus(ctx context.Context, commentID string, status int) (err error) {
+	_, err = cr.data.DB.ID(commentID).Cols("status").Update(&entity.Comment{Status: status})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
 // GetComment get comment one
 func (cr *commentRepo) GetComment(ctx context.Context, commentID string) (
 	comment *entity.Comment, exist bool, err error,
@@ -88,6 +97,16 @@ func (cr *commentRepo) GetCommentCount(ctx context.Context) (count int64, err er
 	return
 }
 
+// GetUserCommentCount get the count of the available comments of the user
+func (cr *commentRepo) GetUserCommentCount(ctx context.Context, userID string) (count int64, err error) {
+	count, err = cr.data.DB.Where("user_id = ? AND status = ?", userID, entity.CommentStatusAvailable).
+		Count(&entity.Comment{})
+	if err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return count, nil
+}
+
 // GetCommentPage get comment page
 func (cr *commentRepo) GetCommentPage(ctx context.Context, commentQuery *comment.CommentQuery) (
 	commentList []*entity.Comment, total int64, err error,
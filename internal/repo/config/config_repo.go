@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -12,12 +13,19 @@ import (
 	"github.com/answerdev/answer/internal/base/reason"
 	"github.com/answerdev/answer/internal/entity"
 	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
 )
 
+// configChangedChannel the pub/sub channel of the changed config key,
+// so that every replica reloads the value changed by any of them
+const configChangedChannel = "config_changed"
+
 var (
 	Key2ValueMapping = make(map[string]interface{})
 	Key2IDMapping    = make(map[string]int)
 	ID2KeyMapping    = make(map[int]string)
+	// valueLock guard Key2ValueMapping, which is changed by SetConfig while being read
+	valueLock sync.RWMutex
 )
 
 // configRepo config repository
@@ -32,6 +40,9 @@ func NewConfigRepo(data *data.Data) config.ConfigRepo {
 		data: data,
 	}
 	repo.init()
+	if data.PubSub != nil {
+		data.PubSub.Subscribe(context.Background(), configChangedChannel, repo.reload)
+	}
 	return repo
 }
 
@@ -42,6 +53,8 @@ func (cr *configRepo) init() {
 	rows := &[]entity.Config{}
 	err := cr.data.DB.Find(rows)
 	if err == nil {
+		valueLock.Lock()
+		defer valueLock.Unlock()
 		for _, row := range *rows {
 			Key2ValueMapping[row.Key] = row.Value
 			Key2IDMapping[row.Key] = row.ID
@@ -50,10 +63,33 @@ func (cr *configRepo) init() {
 	}
 }
 
+// reload load the value of the changed key from database
+func (cr *configRepo) reload(payload []byte) {
+	key := string(payload)
+	id, ok := Key2IDMapping[key]
+	if !ok {
+		return
+	}
+	row := &entity.Config{}
+	exist, err := cr.data.DB.ID(id).Get(row)
+	if err != nil {
+		log.Errorf("reload config %s failed: %s", key, err)
+		return
+	}
+	if !exist {
+		return
+	}
+	valueLock.Lock()
+	defer valueLock.Unlock()
+	Key2ValueMapping[row.Key] = row.Value
+}
+
 // Get Base method for getting the config value
 // Key string
 func (cr *configRepo) Get(key string) (interface{}, error) {
+	valueLock.RLock()
 	value, ok := Key2ValueMapping[key]
+	valueLock.RUnlock()
 	if ok {
 		return value, nil
 	} else {
@@ -132,9 +168,15 @@ func (cr *configRepo) SetConfig(key, value string) (err error) {
 	id := Key2IDMapping[key]
 	_, err = cr.data.DB.ID(id).Update(&entity.Config{Value: value})
 	if err != nil {
-		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
-	} else {
-		Key2ValueMapping[key] = value
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
-	return
+	valueLock.Lock()
+	Key2ValueMapping[key] = value
+	valueLock.Unlock()
+	if cr.data.PubSub != nil {
+		if err := cr.data.PubSub.Publish(context.Background(), configChangedChannel, []byte(key)); err != nil {
+			log.Errorf("publish config %s changed failed: %s", key, err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,121 @@
+package email_digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/email_digest"
+	"github.com/segmentfault/pacman/errors"
+)
+
+type emailDigestRepo struct {
+	data *data.Data
+}
+
+// NewEmailDigestRepo new repository
+func NewEmailDigestRepo(data *data.Data) email_digest.EmailDigestRepo {
+	return &emailDigestRepo{
+		data: data,
+	}
+}
+
+// GetUserNoticeConfigList get the notice config list of user
+func (er *emailDigestRepo) GetUserNoticeConfigList(ctx context.Context, userID string) (
+	configs []*entity.UserNoticeConfig, err error) {
+	configs = make([]*entity.UserNoticeConfig, 0)
+	err = er.data.DB.Where("user_id = ?", userID).Find(&configs)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SaveUserNoticeConfig add or update the frequency of the notice type
+func (er *emailDigestRepo) SaveUserNoticeConfig(ctx context.Context, userID, noticeType, frequency string) (err error) {
+	config := &entity.UserNoticeConfig{}
+	exist, err := er.data.DB.Where("user_id = ? AND notice_type = ?", userID, noticeType).Get(config)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		_, err = er.data.DB.ID(config.ID).Cols("frequency").Update(&entity.UserNoticeConfig{Frequency: frequency})
+	} else {
+		_, err = er.data.DB.Insert(&entity.UserNoticeConfig{UserID: userID, NoticeType: noticeType, Frequency: frequency})
+	}
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserIDsByNoticeFrequency get the users who set the frequency of the notice type
+func (er *emailDigestRepo) GetUserIDsByNoticeFrequency(ctx context.Context, noticeType, frequency string) (
+	userIDs []string, err error) {
+	userIDs = make([]string, 0)
+	err = er.data.DB.Table(entity.UserNoticeConfig{}.TableName()).Select("user_id").
+		Where("notice_type = ? AND frequency = ?", noticeType, frequency).Find(&userIDs)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AddDigestItem add the pending digest item
+func (er *emailDigestRepo) AddDigestItem(ctx context.Context, item *entity.EmailDigestItem) (err error) {
+	_, err = er.data.DB.Insert(item)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetDigestItemUserIDs get the users who have pending digest items of the frequency
+func (er *emailDigestRepo) GetDigestItemUserIDs(ctx context.Context, frequency string) (userIDs []string, err error) {
+	userIDs = make([]string, 0)
+	err = er.data.DB.Table(entity.EmailDigestItem{}.TableName()).Distinct("user_id").
+		Where("frequency = ?", frequency).Find(&userIDs)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetDigestItemList get the pending digest items of user
+func (er *emailDigestRepo) GetDigestItemList(ctx context.Context, userID, frequency string) (
+	items []*entity.EmailDigestItem, err error) {
+	items = make([]*entity.EmailDigestItem, 0)
+	err = er.data.DB.Where("user_id = ? AND frequency = ?", userID, frequency).Asc("id").Find(&items)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveDigestItems remove the digest items which have been sent
+func (er *emailDigestRepo) RemoveDigestItems(ctx context.Context, ids []int64) (err error) {
+	_, err = er.data.DB.In("id", ids).Delete(&entity.EmailDigestItem{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetNewQuestionsByTagIDs get the available questions created after the time with any of the tags
+func (er *emailDigestRepo) GetNewQuestionsByTagIDs(ctx context.Context, tagIDs []string, since time.Time, limit int) (
+	questions []*entity.Question, err error) {
+	questions = make([]*entity.Question, 0)
+	err = er.data.DB.Distinct("question.id", "question.user_id", "question.title", "question.created_at").
+		Join("INNER", "tag_rel", "question.id = tag_rel.object_id").
+		In("tag_rel.tag_id", tagIDs).
+		And("tag_rel.status = ?", entity.TagRelStatusAvailable).
+		And("question.status = ?", entity.QuestionStatusAvailable).
+		And("question.created_at >= ?", since).
+		Desc("question.created_at").Limit(limit).Find(&questions)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,119 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/job_queue"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// jobRepo job repository
+type jobRepo struct {
+	data *data.Data
+}
+
+// NewJobRepo new repository
+func NewJobRepo(data *data.Data) job_queue.JobRepo {
+	return &jobRepo{
+		data: data,
+	}
+}
+
+// AddJob add job
+func (jr *jobRepo) AddJob(ctx context.Context, job *entity.Job) (err error) {
+	_, err = jr.data.DB.Insert(job)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetAvailableJobs get the pending jobs and the running jobs exceeded the visibility timeout
+func (jr *jobRepo) GetAvailableJobs(ctx context.Context, topics []string, now time.Time, limit int) (
+	jobs []*entity.Job, err error) {
+	jobs = make([]*entity.Job, 0)
+	err = jr.data.DB.In("status", entity.JobStatusPending, entity.JobStatusRunning).
+		And(builder.Lte{"available_at": now}).
+		In("topic", topics).
+		OrderBy("id ASC").Limit(limit).Find(&jobs)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ClaimJob mark the job running and invisible until visibleAt. The attempts is used as the version of job,
+// so only one worker can claim the job even though it is got by several workers at the same time.
+func (jr *jobRepo) ClaimJob(ctx context.Context, job *entity.Job, workerID string, visibleAt time.Time) (
+	claimed bool, err error) {
+	update := &entity.Job{
+		Status:      entity.JobStatusRunning,
+		Attempts:    job.Attempts + 1,
+		AvailableAt: visibleAt,
+		LockedBy:    workerID,
+	}
+	affected, err := jr.data.DB.Where(builder.Eq{"id": job.ID, "attempts": job.Attempts}).
+		In("status", entity.JobStatusPending, entity.JobStatusRunning).
+		Cols("status", "attempts", "available_at", "locked_by").Update(update)
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	job.Status, job.Attempts, job.AvailableAt, job.LockedBy = update.Status, update.Attempts, update.AvailableAt, update.LockedBy
+	return true, nil
+}
+
+// RemoveJob remove the completed job if it is still claimed by the worker,
+// it returns false if the job has been claimed by another worker after the visibility timeout
+func (jr *jobRepo) RemoveJob(ctx context.Context, job *entity.Job) (owned bool, err error) {
+	affected, err := jr.data.DB.Where(claimedBy(job)).Delete(&entity.Job{})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// RetryJob make the failed job available again at availableAt if it is still claimed by the worker
+func (jr *jobRepo) RetryJob(ctx context.Context, job *entity.Job, availableAt time.Time, lastError string) (
+	owned bool, err error) {
+	affected, err := jr.data.DB.Where(claimedBy(job)).Cols("status", "available_at", "locked_by", "last_error").
+		Update(&entity.Job{
+			Status:      entity.JobStatusPending,
+			AvailableAt: availableAt,
+			LastError:   lastError,
+		})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// DeadJob mark the job dead if it is still claimed by the worker, it will never be retried and is kept for checking
+func (jr *jobRepo) DeadJob(ctx context.Context, job *entity.Job, lastError string) (owned bool, err error) {
+	affected, err := jr.data.DB.Where(claimedBy(job)).Cols("status", "locked_by", "last_error").
+		Update(&entity.Job{
+			Status:    entity.JobStatusDead,
+			LastError: lastError,
+		})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// claimedBy the condition of the job claimed by the worker, the attempts tells the claims of the same worker apart
+func claimedBy(job *entity.Job) builder.Cond {
+	return builder.Eq{
+		"id":        job.ID,
+		"locked_by": job.LockedBy,
+		"attempts":  job.Attempts,
+		"status":    entity.JobStatusRunning,
+	}
+}
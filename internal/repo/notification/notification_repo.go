@@ -25,11 +25,26 @@ func NewNotificationRepo(data *data.Data) notficationcommon.NotificationRepo {
 	}
 }
 
-// AddNotification add notification
+// AddNotification add notification, the notification with the same dedup key is added only once
 func (nr *notificationRepo) AddNotification(ctx context.Context, notification *entity.Notification) (err error) {
 	_, err = nr.data.DB.Insert(notification)
+	if err == nil {
+		return nil
+	}
+	// the same message handled at the same time is rejected by the unique dedup key
+	if notification.DedupKey != nil {
+		if exist, _ := nr.ExistDedupKey(ctx, *notification.DedupKey); exist {
+			return nil
+		}
+	}
+	return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+}
+
+// ExistDedupKey whether the notification of the message with the dedup key has been added
+func (nr *notificationRepo) ExistDedupKey(ctx context.Context, dedupKey string) (exist bool, err error) {
+	exist, err = nr.data.DB.Where("dedup_key = ?", dedupKey).Exist(&entity.Notification{})
 	if err != nil {
-		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 	return
 }
@@ -0,0 +1,121 @@
+package pending_post
+
+import (
+	"context"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/pager"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/pre_moderation"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// pendingPostRepo pending post repository
+type pendingPostRepo struct {
+	data *data.Data
+}
+
+// NewPendingPostRepo new repository
+func NewPendingPostRepo(data *data.Data) pre_moderation.PendingPostRepo {
+	return &pendingPostRepo{
+		data: data,
+	}
+}
+
+// AddPendingPost add pending post, the post reviewed before such as the edited comment is queued again
+func (pr *pendingPostRepo) AddPendingPost(ctx context.Context, pendingPost *entity.PendingPost) (err error) {
+	reviewed := &entity.PendingPost{}
+	exist, err := pr.data.DB.Where("object_id = ?", pendingPost.ObjectID).Cols("id").Get(reviewed)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		pendingPost.ID = reviewed.ID
+		pendingPost.ReviewUserID = "0"
+		_, err = pr.data.DB.ID(reviewed.ID).Cols("user_id", "object_type", "status", "extra", "review_user_id").
+			Update(pendingPost)
+	} else {
+		_, err = pr.data.DB.Insert(pendingPost)
+	}
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetPendingPost get pending post by id
+func (pr *pendingPostRepo) GetPendingPost(ctx context.Context, id string) (
+	pendingPost *entity.PendingPost, exist bool, err error) {
+	pendingPost = &entity.PendingPost{}
+	exist, err = pr.data.DB.ID(id).Get(pendingPost)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetPendingPostByObjectID get pending post by the object id of the post
+func (pr *pendingPostRepo) GetPendingPostByObjectID(ctx context.Context, objectID string) (
+	pendingPost *entity.PendingPost, exist bool, err error) {
+	pendingPost = &entity.PendingPost{}
+	exist, err = pr.data.DB.Where("object_id = ?", objectID).Get(pendingPost)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetPendingPostPage get the page of the posts waiting for review, the oldest first
+func (pr *pendingPostRepo) GetPendingPostPage(ctx context.Context, page, pageSize int, objectTypes []int) (
+	pendingPosts []*entity.PendingPost, total int64, err error) {
+	pendingPosts = make([]*entity.PendingPost, 0)
+	if len(objectTypes) == 0 {
+		return pendingPosts, 0, nil
+	}
+	session := pr.data.DB.NewSession()
+	session.Where("status = ?", entity.PendingPostStatusPending)
+	session.In("object_type", objectTypes)
+	session.Asc("id")
+	total, err = pager.Help(page, pageSize, &pendingPosts, &entity.PendingPost{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetPendingPostCount get the count of the posts waiting for review
+func (pr *pendingPostRepo) GetPendingPostCount(ctx context.Context, objectTypes []int) (count int64, err error) {
+	if len(objectTypes) == 0 {
+		return 0, nil
+	}
+	count, err = pr.data.DB.Where("status = ?", entity.PendingPostStatusPending).
+		In("object_type", objectTypes).Count(&entity.PendingPost{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdatePendingPostStatus update the status of the pending post, it returns false if the post has been reviewed
+func (pr *pendingPostRepo) UpdatePendingPostStatus(ctx context.Context, id string, status int, reviewUserID string) (
+	updated bool, err error) {
+	affected, err := pr.data.DB.ID(id).Where("status = ?", entity.PendingPostStatusPending).
+		Cols("status", "review_user_id").
+		Update(&entity.PendingPost{Status: status, ReviewUserID: reviewUserID})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// ReleasePendingPost put the reviewed post with the status back to the queue
+func (pr *pendingPostRepo) ReleasePendingPost(ctx context.Context, id string, status int) (err error) {
+	_, err = pr.data.DB.ID(id).Where("status = ?", status).
+		Cols("status", "review_user_id").
+		Update(&entity.PendingPost{Status: entity.PendingPostStatusPending, ReviewUserID: "0"})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
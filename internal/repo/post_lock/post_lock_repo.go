@@ -0,0 +1,106 @@
+package post_lock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/post_lock"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+type postLockRepo struct {
+	data *data.Data
+}
+
+// NewPostLockRepo new repository
+func NewPostLockRepo(data *data.Data) post_lock.PostLockRepo {
+	return &postLockRepo{
+		data: data,
+	}
+}
+
+// UpdateQuestionLock lock or unlock the question, the zero expiry means the lock never expires
+func (pr *postLockRepo) UpdateQuestionLock(ctx context.Context, questionID string, locked bool,
+	expiredAt time.Time) (err error) {
+	_, err = pr.data.DB.ID(questionID).Cols("locked", "lock_expired_at").
+		Update(&entity.Question{Locked: locked, LockExpiredAt: expiredAt})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// UpdateQuestionProtect protect or unprotect the question, the zero expiry means the protection never expires
+func (pr *postLockRepo) UpdateQuestionProtect(ctx context.Context, questionID string, protected bool,
+	expiredAt time.Time) (err error) {
+	_, err = pr.data.DB.ID(questionID).Cols("protected", "protect_expired_at").
+		Update(&entity.Question{Protected: protected, ProtectExpiredAt: expiredAt})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// UpdateAnswerLock lock or unlock the answer, the zero expiry means the lock never expires
+func (pr *postLockRepo) UpdateAnswerLock(ctx context.Context, answerID string, locked bool,
+	expiredAt time.Time) (err error) {
+	_, err = pr.data.DB.ID(answerID).Cols("locked", "lock_expired_at").
+		Update(&entity.Answer{Locked: locked, LockExpiredAt: expiredAt})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// ExpirePostLocks unlock the questions and answers and unprotect the questions which are expired before the time,
+// the posts which are released are returned
+func (pr *postLockRepo) ExpirePostLocks(ctx context.Context, before time.Time) (
+	expired *schema.ExpiredPostLocks, err error) {
+	expired = &schema.ExpiredPostLocks{}
+	_, err = pr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		expired.LockedQuestionIDs, err = releaseExpired(session, &entity.Question{}, "locked", "lock_expired_at", before)
+		if err != nil {
+			return nil, err
+		}
+		expired.ProtectedQuestionIDs, err = releaseExpired(session, &entity.Question{},
+			"protected", "protect_expired_at", before)
+		if err != nil {
+			return nil, err
+		}
+		expired.LockedAnswerIDs, err = releaseExpired(session, &entity.Answer{}, "locked", "lock_expired_at", before)
+		return nil, err
+	})
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return expired, nil
+}
+
+// releaseExpired clear the flag of the rows which are expired before the time. Each row is released with the
+// expiry condition again, so the row locked again in the meantime is not released.
+func releaseExpired(session *xorm.Session, bean any, flagCol, expiredCol string, before time.Time) (
+	ids []string, err error) {
+	cond := fmt.Sprintf("%s = ? AND %s IS NOT NULL AND %s <= ?", flagCol, expiredCol, expiredCol)
+	candidates := make([]string, 0)
+	err = session.Table(bean).Where(cond, true, before).Cols("id").Find(&candidates)
+	if err != nil {
+		return nil, err
+	}
+	ids = make([]string, 0, len(candidates))
+	for _, id := range candidates {
+		count, err := session.ID(id).Where(cond, true, before).Cols(flagCol, expiredCol).Update(bean)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
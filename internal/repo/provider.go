@@ -2,30 +2,44 @@ package repo
 
 import (
 	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/repo/access_token"
 	"github.com/answerdev/answer/internal/repo/activity"
 	"github.com/answerdev/answer/internal/repo/activity_common"
 	"github.com/answerdev/answer/internal/repo/answer"
+	"github.com/answerdev/answer/internal/repo/audit_log"
 	"github.com/answerdev/answer/internal/repo/auth"
+	"github.com/answerdev/answer/internal/repo/badge"
+	"github.com/answerdev/answer/internal/repo/bounty"
 	"github.com/answerdev/answer/internal/repo/captcha"
 	"github.com/answerdev/answer/internal/repo/collection"
 	"github.com/answerdev/answer/internal/repo/comment"
 	"github.com/answerdev/answer/internal/repo/common"
 	"github.com/answerdev/answer/internal/repo/config"
+	"github.com/answerdev/answer/internal/repo/email_digest"
 	"github.com/answerdev/answer/internal/repo/export"
+	"github.com/answerdev/answer/internal/repo/job"
 	"github.com/answerdev/answer/internal/repo/meta"
 	"github.com/answerdev/answer/internal/repo/notification"
+	"github.com/answerdev/answer/internal/repo/pending_post"
+	"github.com/answerdev/answer/internal/repo/post_lock"
 	"github.com/answerdev/answer/internal/repo/question"
+	"github.com/answerdev/answer/internal/repo/question_duplicate"
 	"github.com/answerdev/answer/internal/repo/rank"
 	"github.com/answerdev/answer/internal/repo/reason"
 	"github.com/answerdev/answer/internal/repo/report"
 	"github.com/answerdev/answer/internal/repo/revision"
 	"github.com/answerdev/answer/internal/repo/role"
 	"github.com/answerdev/answer/internal/repo/search_common"
+	"github.com/answerdev/answer/internal/repo/serial_vote"
 	"github.com/answerdev/answer/internal/repo/site_info"
+	"github.com/answerdev/answer/internal/repo/spam"
 	"github.com/answerdev/answer/internal/repo/tag"
 	"github.com/answerdev/answer/internal/repo/tag_common"
+	"github.com/answerdev/answer/internal/repo/two_factor"
 	"github.com/answerdev/answer/internal/repo/unique"
 	"github.com/answerdev/answer/internal/repo/user"
+	"github.com/answerdev/answer/internal/repo/user_external_login"
+	"github.com/answerdev/answer/internal/repo/webhook"
 	"github.com/google/wire"
 )
 
@@ -72,4 +86,18 @@ var ProviderSetRepo = wire.NewSet(
 	role.NewUserRoleRelRepo,
 	role.NewRolePowerRelRepo,
 	role.NewPowerRepo,
+	job.NewJobRepo,
+	user_external_login.NewUserExternalLoginRepo,
+	two_factor.NewTwoFactorRepo,
+	access_token.NewAccessTokenRepo,
+	webhook.NewWebhookRepo,
+	email_digest.NewEmailDigestRepo,
+	badge.NewBadgeRepo,
+	bounty.NewBountyRepo,
+	serial_vote.NewSerialVoteRepo,
+	audit_log.NewAuditLogRepo,
+	pending_post.NewPendingPostRepo,
+	spam.NewSpamRepo,
+	question_duplicate.NewQuestionDuplicateRepo,
+	post_lock.NewPostLockRepo,
 )
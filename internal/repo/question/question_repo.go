@@ -232,6 +232,11 @@ func (qr *questionRepo) GetQuestionPage(ctx context.Context, page, pageSize int,
 	case "unanswered":
 		session.Where("question.last_answer_id = 0")
 		session.OrderBy("question.created_at DESC")
+	case "featured":
+		// the questions with open bounty
+		session.And(builder.In("question.id", builder.Select("question_id").From("question_bounty").
+			Where(builder.Eq{"status": entity.QuestionBountyStatusActive})))
+		session.OrderBy("question.post_update_time DESC, question.updated_at DESC")
 	}
 
 	total, err = pager.Help(page, pageSize, &questionList, &entity.Question{}, session)
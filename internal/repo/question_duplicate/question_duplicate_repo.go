@@ -0,0 +1,149 @@
+package question_duplicate
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/question_duplicate"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+type questionDuplicateRepo struct {
+	data *data.Data
+}
+
+// NewQuestionDuplicateRepo new repository
+func NewQuestionDuplicateRepo(data *data.Data) question_duplicate.QuestionDuplicateRepo {
+	return &questionDuplicateRepo{
+		data: data,
+	}
+}
+
+// SaveQuestionDuplicate link the question to the canonical question, the old link is replaced
+func (qr *questionDuplicateRepo) SaveQuestionDuplicate(ctx context.Context, duplicate *entity.QuestionDuplicate) (
+	err error) {
+	_, err = qr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		old := &entity.QuestionDuplicate{}
+		exist, err := session.Where("question_id = ?", duplicate.QuestionID).Get(old)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			_, err = session.ID(old.ID).Cols("duplicate_of_id", "user_id", "merged").Update(duplicate)
+		} else {
+			_, err = session.Insert(duplicate)
+		}
+		return nil, err
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveQuestionDuplicate remove the link of the question
+func (qr *questionDuplicateRepo) RemoveQuestionDuplicate(ctx context.Context, questionID string) (err error) {
+	_, err = qr.data.DB.Where("question_id = ?", questionID).Delete(&entity.QuestionDuplicate{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetQuestionDuplicate get the link of the question
+func (qr *questionDuplicateRepo) GetQuestionDuplicate(ctx context.Context, questionID string) (
+	duplicate *entity.QuestionDuplicate, exist bool, err error) {
+	duplicate = &entity.QuestionDuplicate{}
+	exist, err = qr.data.DB.Where("question_id = ?", questionID).Get(duplicate)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetDuplicateQuestionIDs get the ids of the questions linked to the canonical question and not merged
+func (qr *questionDuplicateRepo) GetDuplicateQuestionIDs(ctx context.Context, duplicateOfID string) (
+	questionIDs []string, err error) {
+	duplicates := make([]*entity.QuestionDuplicate, 0)
+	err = qr.data.DB.Where("duplicate_of_id = ? AND merged = ?", duplicateOfID, false).
+		Asc("id").Find(&duplicates)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	questionIDs = make([]string, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		questionIDs = append(questionIDs, duplicate.QuestionID)
+	}
+	return questionIDs, nil
+}
+
+// MergeQuestion move the answers and comments of the question to the canonical question and delete it,
+// the answer count of the canonical question is recomputed
+func (qr *questionDuplicateRepo) MergeQuestion(ctx context.Context, duplicate *entity.QuestionDuplicate) (err error) {
+	fromID, toID := duplicate.QuestionID, duplicate.DuplicateOfID
+	_, err = qr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		// the canonical question keeps its own accepted answer
+		_, err = session.Where("question_id = ?", fromID).Cols("question_id", "adopted").
+			Update(&entity.Answer{QuestionID: toID, Accepted: schema.AnswerAcceptedFailed})
+		if err != nil {
+			return nil, err
+		}
+		_, err = session.Where("question_id = ?", fromID).Cols("question_id").
+			Update(&entity.Comment{QuestionID: toID})
+		if err != nil {
+			return nil, err
+		}
+		_, err = session.Where("object_id = ?", fromID).Cols("object_id").
+			Update(&entity.Comment{ObjectID: toID})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = session.ID(fromID).Cols("status", "answer_count", "accepted_answer_id", "updated_at").
+			Update(&entity.Question{
+				Status:           entity.QuestionStatusDeleted,
+				AcceptedAnswerID: "0",
+				UpdatedAt:        time.Now(),
+			})
+		if err != nil {
+			return nil, err
+		}
+		answerCount, err := session.Where("question_id = ? AND status = ?", toID, entity.AnswerStatusAvailable).
+			Count(&entity.Answer{})
+		if err != nil {
+			return nil, err
+		}
+		_, err = session.ID(toID).Cols("answer_count").Update(&entity.Question{AnswerCount: int(answerCount)})
+		if err != nil {
+			return nil, err
+		}
+
+		// the questions linked to the merged one are linked to the canonical question directly
+		_, err = session.Where("duplicate_of_id = ?", fromID).Cols("duplicate_of_id").
+			Update(&entity.QuestionDuplicate{DuplicateOfID: toID})
+		if err != nil {
+			return nil, err
+		}
+		duplicate.Merged = true
+		exist, err := session.Where("question_id = ?", fromID).Exist(&entity.QuestionDuplicate{})
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			_, err = session.Where("question_id = ?", fromID).Cols("duplicate_of_id", "user_id", "merged").
+				Update(duplicate)
+		} else {
+			_, err = session.Insert(duplicate)
+		}
+		return nil, err
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -33,99 +33,135 @@ func NewUserRankRepo(data *data.Data, configRepo config.ConfigRepo) rank.UserRan
 // TriggerUserRank trigger user rank change
 // session is need provider, it means this action must be success or failure
 // if outer action is failed then this action is need rollback
+// appliedRank is the rank really changed, the positive delta is clamped to the rest of the daily limit
+// and the negative one is clamped to keep the user at least 1 rank
 func (ur *UserRankRepo) TriggerUserRank(ctx context.Context,
 	session *xorm.Session, userID string, deltaRank int, activityType int,
-) (isReachStandard bool, err error) {
+) (appliedRank int, err error) {
 	if deltaRank == 0 {
-		return false, nil
+		return 0, nil
 	}
 
 	if deltaRank < 0 {
 		// if user rank is lower than 1 after this action, then user rank will be set to 1 only.
+		var userRank int
 		var isReachMin bool
-		isReachMin, err = ur.checkUserMinRank(ctx, session, userID, deltaRank)
+		userRank, isReachMin, err = ur.checkUserMinRank(ctx, session, userID, deltaRank)
 		if err != nil {
-			return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+			return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
 		if isReachMin {
 			_, err = session.Where(builder.Eq{"id": userID}).Update(&entity.User{Rank: 1})
 			if err != nil {
-				return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+				return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 			}
-			return true, nil
+			return 1 - userRank, nil
 		}
 	} else {
-		isReachStandard, err = ur.checkUserTodayRank(ctx, session, userID, activityType)
+		var headroom int
+		var limited bool
+		headroom, limited, err = ur.checkUserTodayRank(ctx, session, userID, activityType)
 		if err != nil {
-			return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+			return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
-		if isReachStandard {
-			return isReachStandard, nil
+		if limited && deltaRank > headroom {
+			deltaRank = headroom
+		}
+		if deltaRank <= 0 {
+			return 0, nil
 		}
 	}
 	_, err = session.Where(builder.Eq{"id": userID}).Incr("`rank`", deltaRank).Update(&entity.User{})
+	if err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return deltaRank, nil
+}
+
+// DebitUserRank debit the rank only if the user keeps at least 1 rank after that. Unlike TriggerUserRank,
+// the rank is never lowered to 1 instead, the condition is checked by the update, so the concurrent debits
+// can not overdraw it. The debited is false if the rank is not enough.
+func (ur *UserRankRepo) DebitUserRank(ctx context.Context, session *xorm.Session, userID string, amount int) (
+	debited bool, err error) {
+	if amount <= 0 {
+		return true, nil
+	}
+	affected, err := session.Where(builder.Eq{"id": userID}).And(builder.Gte{"`rank`": amount + 1}).
+		Decr("`rank`", amount).Update(&entity.User{})
 	if err != nil {
 		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
-	return false, nil
+	return affected > 0, nil
 }
 
 func (ur *UserRankRepo) checkUserMinRank(ctx context.Context, session *xorm.Session, userID string, deltaRank int) (
-	isReachStandard bool, err error,
+	userRank int, isReachStandard bool, err error,
 ) {
 	bean := &entity.User{ID: userID}
 	_, err = session.Select("rank").Get(bean)
 	if err != nil {
-		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		return 0, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 	if bean.Rank+deltaRank < 1 {
 		log.Infof("user %s is rank %d out of range before rank operation", userID, deltaRank)
-		return true, nil
+		return bean.Rank, true, nil
 	}
-	return
+	return bean.Rank, false, nil
 }
 
+// checkUserTodayRank get the reputation the user can still earn today before the daily limit,
+// the activities in daily_rank_limit.exclude are never limited and not counted, 0 means no limit
 func (ur *UserRankRepo) checkUserTodayRank(ctx context.Context,
 	session *xorm.Session, userID string, activityType int,
-) (isReachStandard bool, err error) {
+) (headroom int, limited bool, err error) {
+	// max rank
+	maxDailyRank, err := ur.configRepo.GetInt("daily_rank_limit")
+	if err != nil {
+		return 0, false, err
+	}
+	if maxDailyRank <= 0 {
+		return 0, false, nil
+	}
+
 	// exclude daily rank
 	exclude, _ := ur.configRepo.GetArrayString("daily_rank_limit.exclude")
+	excludeActivityTypes := make([]int, 0, len(exclude))
 	for _, item := range exclude {
 		var excludeActivityType int
-		excludeActivityType, err = ur.configRepo.GetInt(item)
+		excludeActivityType, err = ur.configRepo.GetConfigType(item)
 		if err != nil {
-			return false, err
+			return 0, false, err
 		}
 		if activityType == excludeActivityType {
-			return false, nil
+			return 0, false, nil
 		}
+		excludeActivityTypes = append(excludeActivityTypes, excludeActivityType)
 	}
 
-	// get user
+	// only the reputation earned today is counted, the lost one does not give more chance to earn
 	start, end := now.BeginningOfDay(), now.EndOfDay()
 	session.Where(builder.Eq{"user_id": userID})
 	session.Where(builder.Eq{"cancelled": 0})
+	session.Where(builder.Gt{"`rank`": 0})
 	session.Where(builder.Between{
 		Col:     "updated_at",
 		LessVal: start,
 		MoreVal: end,
 	})
-	earned, err := session.Sum(&entity.Activity{}, "rank")
-	if err != nil {
-		return false, err
+	if len(excludeActivityTypes) > 0 {
+		session.NotIn("activity_type", excludeActivityTypes)
 	}
-
-	// max rank
-	maxDailyRank, err := ur.configRepo.GetInt("daily_rank_limit")
+	earned, err := session.Sum(&entity.Activity{}, "rank")
 	if err != nil {
-		return false, err
+		return 0, false, err
 	}
 
-	if int(earned) < maxDailyRank {
-		return false, nil
+	headroom = maxDailyRank - int(earned)
+	if headroom <= 0 {
+		log.Infof("user %s today has rank %d is reach stand %d", userID, int(earned), maxDailyRank)
+		return 0, true, nil
 	}
-	log.Infof("user %s today has rank %d is reach stand %d", userID, earned, maxDailyRank)
-	return true, nil
+	return headroom, true, nil
 }
 
 func (ur *UserRankRepo) UserRankPage(ctx context.Context, userID string, page, pageSize int) (
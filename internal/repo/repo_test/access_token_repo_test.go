@@ -0,0 +1,52 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/access_token"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_accessTokenRepo_AddAccessToken(t *testing.T) {
+	accessTokenRepo := access_token.NewAccessTokenRepo(testDataSource)
+	token := &entity.AccessToken{
+		UserID:      "1",
+		Name:        "ci",
+		TokenHash:   "hash1",
+		TokenPrefix: "answer_pat_abcdef",
+		Scopes:      "read,write:question",
+	}
+	err := accessTokenRepo.AddAccessToken(context.TODO(), token)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.ID)
+
+	got, exist, err := accessTokenRepo.GetByTokenHash(context.TODO(), "hash1")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, token.ID, got.ID)
+	assert.True(t, got.LastUsedAt.IsZero())
+
+	// the hash of token is unique
+	err = accessTokenRepo.AddAccessToken(context.TODO(), &entity.AccessToken{UserID: "2", TokenHash: "hash1"})
+	assert.Error(t, err)
+
+	now := time.Now()
+	err = accessTokenRepo.UpdateLastUsedAt(context.TODO(), token.ID, now)
+	assert.NoError(t, err)
+	got, _, err = accessTokenRepo.GetAccessToken(context.TODO(), token.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Unix(), got.LastUsedAt.Unix())
+
+	tokens, err := accessTokenRepo.GetUserAccessTokens(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	err = accessTokenRepo.RemoveAccessToken(context.TODO(), token.ID)
+	assert.NoError(t, err)
+	_, exist, err = accessTokenRepo.GetByTokenHash(context.TODO(), "hash1")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
@@ -0,0 +1,47 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/audit_log"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_auditLogRepo_GetAuditLogPage(t *testing.T) {
+	auditLogRepo := audit_log.NewAuditLogRepo(testDataSource)
+	for _, objectID := range []string{"10010000000000101", "10010000000000102", "10010000000000101"} {
+		err := auditLogRepo.AddAuditLog(context.TODO(), &entity.AuditLog{
+			UserID:     "1",
+			Action:     entity.AuditActionQuestionStatus,
+			ObjectType: "question",
+			ObjectID:   objectID,
+			Before:     `{"status":"available"}`,
+			After:      `{"status":"closed"}`,
+		})
+		assert.NoError(t, err)
+	}
+
+	// the latest first
+	logs, total, err := auditLogRepo.GetAuditLogPage(context.TODO(), 1, 1,
+		&entity.AuditLog{Action: entity.AuditActionQuestionStatus, ObjectID: "10010000000000101"}, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "10010000000000101", logs[0].ObjectID)
+	assert.Equal(t, `{"status":"closed"}`, logs[0].After)
+
+	// the time range
+	_, total, err = auditLogRepo.GetAuditLogPage(context.TODO(), 1, 10,
+		&entity.AuditLog{Action: entity.AuditActionQuestionStatus}, time.Now().Add(time.Hour), time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+
+	logs, _, err = auditLogRepo.GetAuditLogPage(context.TODO(), 1, 2, &entity.AuditLog{UserID: "1"},
+		time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Greater(t, logs[0].ID, logs[1].ID)
+}
@@ -0,0 +1,118 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/badge"
+	"github.com/answerdev/answer/internal/repo/tag"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_badgeRepo_UserBadge(t *testing.T) {
+	badgeRepo := badge.NewBadgeRepo(testDataSource)
+	badges, err := badgeRepo.GetEnabledBadges(context.TODO())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, badges)
+
+	newBadge := &entity.Badge{Name: "Helper", Level: entity.BadgeLevelBronze, Rule: entity.BadgeRuleManual, Enabled: true}
+	err = badgeRepo.AddBadge(context.TODO(), newBadge)
+	assert.NoError(t, err)
+	// the manual badge is not checked by rule
+	badges, err = badgeRepo.GetEnabledBadges(context.TODO())
+	assert.NoError(t, err)
+	for _, b := range badges {
+		assert.NotEqual(t, newBadge.ID, b.ID)
+	}
+
+	userBadge := &entity.UserBadge{UserID: "1", BadgeID: newBadge.ID, ObjectID: "0", AwardUserID: "1"}
+	err = badgeRepo.AddUserBadge(context.TODO(), userBadge)
+	assert.NoError(t, err)
+	_, exist, err := badgeRepo.GetUserBadgeByObject(context.TODO(), "1", newBadge.ID, "0")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	// the same badge can not be awarded twice for the same object
+	err = badgeRepo.AddUserBadge(context.TODO(), &entity.UserBadge{UserID: "1", BadgeID: newBadge.ID, ObjectID: "0"})
+	assert.Error(t, err)
+
+	counts, err := badgeRepo.GetBadgeAwardCounts(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), counts[newBadge.ID])
+	list, total, err := badgeRepo.GetUserBadgePage(context.TODO(), 1, 10, &entity.UserBadge{BadgeID: newBadge.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, userBadge.ID, list[0].ID)
+
+	err = badgeRepo.RevokeUserBadge(context.TODO(), userBadge.ID)
+	assert.NoError(t, err)
+	_, exist, err = badgeRepo.GetUserBadge(context.TODO(), userBadge.ID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	counts, err = badgeRepo.GetBadgeAwardCounts(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), counts[newBadge.ID])
+	_, total, err = badgeRepo.GetUserBadgePage(context.TODO(), 1, 10, &entity.UserBadge{BadgeID: newBadge.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	// the revoked badge is kept so that the rule does not award it again
+	revoked, exist, err := badgeRepo.GetUserBadgeByObject(context.TODO(), "1", newBadge.ID, "0")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.True(t, revoked.Revoked)
+
+	err = badgeRepo.RestoreUserBadge(context.TODO(), userBadge.ID, "2")
+	assert.NoError(t, err)
+	restored, exist, err := badgeRepo.GetUserBadge(context.TODO(), userBadge.ID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "2", restored.AwardUserID)
+}
+
+func Test_badgeRepo_CountUpvotedAnswersInTag(t *testing.T) {
+	const (
+		userID     = "1002"
+		questionID = "10010000000002001"
+		tagID      = "10030000000002001"
+	)
+	tagRelRepo := tag.NewTagRelRepo(testDataSource)
+	err := tagRelRepo.AddTagRelList(context.TODO(), []*entity.TagRel{
+		{ObjectID: questionID, TagID: tagID, Status: entity.TagRelStatusAvailable},
+	})
+	assert.NoError(t, err)
+	_, err = testDataSource.DB.Insert([]*entity.Answer{
+		{ID: "10020000000002001", QuestionID: questionID, UserID: userID, VoteCount: 2,
+			Status: entity.AnswerStatusAvailable, Accepted: schema.AnswerAcceptedEnable},
+		{ID: "10020000000002002", QuestionID: questionID, UserID: userID, VoteCount: 0,
+			Status: entity.AnswerStatusAvailable, Accepted: schema.AnswerAcceptedFailed},
+		{ID: "10020000000002003", QuestionID: questionID, UserID: userID, VoteCount: 3,
+			Status: entity.AnswerStatusDeleted, Accepted: schema.AnswerAcceptedEnable},
+	})
+	assert.NoError(t, err)
+
+	badgeRepo := badge.NewBadgeRepo(testDataSource)
+	count, err := badgeRepo.CountUpvotedAnswersInTag(context.TODO(), userID, tagID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = badgeRepo.CountAcceptedAnswers(context.TODO(), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func Test_badgeRepo_AddUserVisit(t *testing.T) {
+	badgeRepo := badge.NewBadgeRepo(testDataSource)
+	added, err := badgeRepo.AddUserVisit(context.TODO(), "1003", "2023-01-01")
+	assert.NoError(t, err)
+	assert.True(t, added)
+	added, err = badgeRepo.AddUserVisit(context.TODO(), "1003", "2023-01-01")
+	assert.NoError(t, err)
+	assert.False(t, added)
+	added, err = badgeRepo.AddUserVisit(context.TODO(), "1003", "2023-01-02")
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	count, err := badgeRepo.CountUserVisitDays(context.TODO(), "1003")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
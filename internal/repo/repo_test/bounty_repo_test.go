@@ -0,0 +1,98 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/activity_common"
+	"github.com/answerdev/answer/internal/repo/bounty"
+	"github.com/answerdev/answer/internal/repo/config"
+	"github.com/answerdev/answer/internal/repo/question"
+	"github.com/answerdev/answer/internal/repo/rank"
+	"github.com/answerdev/answer/internal/repo/unique"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_bountyRepo_AwardAndRefund(t *testing.T) {
+	const questionID = "10010000000003001"
+	configRepo := config.NewConfigRepo(testDataSource)
+	activityRepo := activity_common.NewActivityRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource), configRepo)
+	bountyRepo := bounty.NewBountyRepo(testDataSource, activityRepo, rank.NewUserRankRepo(testDataSource, configRepo))
+
+	owner := &entity.User{Username: "bounty_owner", EMail: "bounty_owner@example.com", Rank: 1000,
+		Status: entity.UserStatusAvailable}
+	answerer := &entity.User{Username: "bounty_answerer", EMail: "bounty_answerer@example.com", Rank: 1,
+		Status: entity.UserStatusAvailable}
+	_, err := testDataSource.DB.Insert(owner, answerer)
+	assert.NoError(t, err)
+	getRank := func(userID string) int {
+		userInfo := &entity.User{}
+		_, err := testDataSource.DB.ID(userID).Get(userInfo)
+		assert.NoError(t, err)
+		return userInfo.Rank
+	}
+
+	newBounty := func() *entity.QuestionBounty {
+		return &entity.QuestionBounty{QuestionID: questionID, UserID: owner.ID, Amount: 100,
+			Status: entity.QuestionBountyStatusActive, ExpiredAt: time.Now().Add(-time.Minute)}
+	}
+	first := newBounty()
+	err = bountyRepo.AddBounty(context.TODO(), first)
+	assert.NoError(t, err)
+	assert.Equal(t, 900, getRank(owner.ID))
+	// only one bounty is open on a question
+	err = bountyRepo.AddBounty(context.TODO(), newBounty())
+	assert.Error(t, err)
+	// the user must keep at least 1 reputation, nothing is debited if the bounty is rejected
+	err = bountyRepo.AddBounty(context.TODO(), &entity.QuestionBounty{QuestionID: "10010000000003002",
+		UserID: answerer.ID, Amount: 50, Status: entity.QuestionBountyStatusActive})
+	assert.Error(t, err)
+	assert.Equal(t, 1, getRank(answerer.ID))
+	_, exist, err := bountyRepo.GetActiveBounty(context.TODO(), "10010000000003002")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	expired, err := bountyRepo.GetExpiredBounties(context.TODO(), time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+	err = bountyRepo.RefundBounty(context.TODO(), first)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, getRank(owner.ID))
+	// the closed bounty can not be refunded again
+	err = bountyRepo.RefundBounty(context.TODO(), first)
+	assert.Error(t, err)
+	assert.Equal(t, 1000, getRank(owner.ID))
+
+	var active *entity.QuestionBounty
+	second := newBounty()
+	err = bountyRepo.AddBounty(context.TODO(), second)
+	assert.NoError(t, err)
+	active, exist, err = bountyRepo.GetActiveBounty(context.TODO(), questionID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, second.ID, active.ID)
+	// the concurrent bounty passed the existence check is rejected by the unique index
+	concurrent := newBounty()
+	concurrent.ActiveQuestionID = questionID
+	_, err = testDataSource.DB.Insert(concurrent)
+	assert.Error(t, err)
+
+	// the question with open bounty is featured
+	_, err = testDataSource.DB.Insert(&entity.Question{ID: questionID, UserID: owner.ID, Title: "bounty",
+		Status: entity.QuestionStatusAvailable})
+	assert.NoError(t, err)
+	questionRepo := question.NewQuestionRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource))
+	featured, total, err := questionRepo.GetQuestionPage(context.TODO(), 1, 10, "", "", "featured")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, questionID, featured[0].ID)
+	err = bountyRepo.AwardBounty(context.TODO(), second, &entity.Answer{ID: "10020000000003001", UserID: answerer.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, 900, getRank(owner.ID))
+	assert.Equal(t, 101, getRank(answerer.ID))
+	_, exist, err = bountyRepo.GetActiveBounty(context.TODO(), questionID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
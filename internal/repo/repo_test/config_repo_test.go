@@ -1,8 +1,11 @@
 package repo_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/answerdev/answer/internal/entity"
 	"github.com/answerdev/answer/internal/repo/config"
 	"github.com/answerdev/answer/internal/schema"
 	"github.com/stretchr/testify/assert"
@@ -18,8 +21,9 @@ func Test_configRepo_GetArrayString(t *testing.T) {
 	configRepo := config.NewConfigRepo(testDataSource)
 	got, err := configRepo.GetArrayString("daily_rank_limit.exclude")
 	assert.NoError(t, err)
-	assert.Equal(t, 1, len(got))
+	assert.Equal(t, 3, len(got))
 	assert.Equal(t, "answer.accepted", got[0])
+	assert.Equal(t, []string{"question.bounty", "answer.bounty_awarded"}, got[1:])
 }
 
 func Test_configRepo_GetConfigById(t *testing.T) {
@@ -60,3 +64,25 @@ func Test_configRepo_SetConfig(t *testing.T) {
 	err = configRepo.SetConfig("email.config", got)
 	assert.NoError(t, err)
 }
+
+func Test_configRepo_ReloadChangedConfig(t *testing.T) {
+	configRepo := config.NewConfigRepo(testDataSource)
+	before, err := configRepo.GetString("serial_vote.limit")
+	assert.NoError(t, err)
+
+	// the config changed by another replica is reloaded after it is published
+	id, err := configRepo.GetConfigType("serial_vote.limit")
+	assert.NoError(t, err)
+	_, err = testDataSource.DB.ID(id).Update(&entity.Config{Value: "99"})
+	assert.NoError(t, err)
+	err = testDataSource.PubSub.Publish(context.TODO(), "config_changed", []byte("serial_vote.limit"))
+	assert.NoError(t, err)
+	// the message is delivered to the subscribers asynchronously
+	assert.Eventually(t, func() bool {
+		got, err := configRepo.GetInt("serial_vote.limit")
+		return err == nil && got == 99
+	}, time.Second, 10*time.Millisecond)
+
+	err = configRepo.SetConfig("serial_vote.limit", before)
+	assert.NoError(t, err)
+}
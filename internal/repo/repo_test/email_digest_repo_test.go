@@ -0,0 +1,86 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/email_digest"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_emailDigestRepo_UserNoticeConfig(t *testing.T) {
+	emailDigestRepo := email_digest.NewEmailDigestRepo(testDataSource)
+	err := emailDigestRepo.SaveUserNoticeConfig(context.TODO(), "1", schema.NoticeTypeNewAnswer, schema.NoticeFrequencyDaily)
+	assert.NoError(t, err)
+	// the frequency of the same notice type is updated
+	err = emailDigestRepo.SaveUserNoticeConfig(context.TODO(), "1", schema.NoticeTypeNewAnswer, schema.NoticeFrequencyWeekly)
+	assert.NoError(t, err)
+
+	configs, err := emailDigestRepo.GetUserNoticeConfigList(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(configs))
+	assert.Equal(t, schema.NoticeFrequencyWeekly, configs[0].Frequency)
+
+	userIDs, err := emailDigestRepo.GetUserIDsByNoticeFrequency(context.TODO(),
+		schema.NoticeTypeNewAnswer, schema.NoticeFrequencyWeekly)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, userIDs)
+}
+
+func Test_emailDigestRepo_DigestItem(t *testing.T) {
+	emailDigestRepo := email_digest.NewEmailDigestRepo(testDataSource)
+	for _, frequency := range []string{schema.NoticeFrequencyDaily, schema.NoticeFrequencyDaily, schema.NoticeFrequencyWeekly} {
+		err := emailDigestRepo.AddDigestItem(context.TODO(), &entity.EmailDigestItem{
+			UserID:     "2",
+			NoticeType: schema.NoticeTypeNewComment,
+			Frequency:  frequency,
+			QuestionID: "1",
+			AnswerID:   "0",
+			CommentID:  "1",
+		})
+		assert.NoError(t, err)
+	}
+
+	userIDs, err := emailDigestRepo.GetDigestItemUserIDs(context.TODO(), schema.NoticeFrequencyDaily)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2"}, userIDs)
+
+	items, err := emailDigestRepo.GetDigestItemList(context.TODO(), "2", schema.NoticeFrequencyDaily)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(items))
+
+	err = emailDigestRepo.RemoveDigestItems(context.TODO(), []int64{items[0].ID, items[1].ID})
+	assert.NoError(t, err)
+	items, err = emailDigestRepo.GetDigestItemList(context.TODO(), "2", schema.NoticeFrequencyDaily)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(items))
+	items, err = emailDigestRepo.GetDigestItemList(context.TODO(), "2", schema.NoticeFrequencyWeekly)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(items))
+}
+
+func Test_emailDigestRepo_GetNewQuestionsByTagIDs(t *testing.T) {
+	now := time.Now()
+	questions := []*entity.Question{
+		{ID: "10010000000009001", UserID: "1", Title: "new", Status: entity.QuestionStatusAvailable, CreatedAt: now},
+		{ID: "10010000000009002", UserID: "1", Title: "old", Status: entity.QuestionStatusAvailable,
+			CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "10010000000009003", UserID: "1", Title: "deleted", Status: entity.QuestionStatusDeleted, CreatedAt: now},
+	}
+	_, err := testDataSource.DB.Insert(questions)
+	assert.NoError(t, err)
+	for _, question := range questions {
+		_, err = testDataSource.DB.Insert(&entity.TagRel{
+			ObjectID: question.ID, TagID: "9001", Status: entity.TagRelStatusAvailable})
+		assert.NoError(t, err)
+	}
+
+	emailDigestRepo := email_digest.NewEmailDigestRepo(testDataSource)
+	got, err := emailDigestRepo.GetNewQuestionsByTagIDs(context.TODO(), []string{"9001"}, now.Add(-24*time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(got))
+	assert.Equal(t, "new", got[0].Title)
+}
@@ -0,0 +1,109 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/job"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_jobRepo_ClaimJob(t *testing.T) {
+	jobRepo := job.NewJobRepo(testDataSource)
+	ctx := context.TODO()
+
+	testJob := &entity.Job{
+		Topic:       "test_claim",
+		Payload:     `"1"`,
+		Status:      entity.JobStatusPending,
+		AvailableAt: time.Now().Add(-time.Second),
+	}
+	err := jobRepo.AddJob(ctx, testJob)
+	assert.NoError(t, err)
+
+	jobs, err := jobRepo.GetAvailableJobs(ctx, []string{"test_claim"}, time.Now(), 10)
+	assert.NoError(t, err)
+	if !assert.Equal(t, 1, len(jobs)) {
+		return
+	}
+	another := *jobs[0]
+
+	claimed, err := jobRepo.ClaimJob(ctx, jobs[0], "worker-1", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, 1, jobs[0].Attempts)
+
+	// the job got by another worker at the same time can not be claimed again
+	claimed, err = jobRepo.ClaimJob(ctx, &another, "worker-2", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	// the claimed job is invisible until the visibility timeout
+	jobs, err = jobRepo.GetAvailableJobs(ctx, []string{"test_claim"}, time.Now(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(jobs))
+	jobs, err = jobRepo.GetAvailableJobs(ctx, []string{"test_claim"}, time.Now().Add(2*time.Minute), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(jobs))
+
+	// the job claimed again after the visibility timeout can not be removed by the first worker
+	claimed, err = jobRepo.ClaimJob(ctx, jobs[0], "worker-2", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	owned, err := jobRepo.RemoveJob(ctx, testJob)
+	assert.NoError(t, err)
+	assert.False(t, owned)
+
+	owned, err = jobRepo.RemoveJob(ctx, jobs[0])
+	assert.NoError(t, err)
+	assert.True(t, owned)
+	jobs, err = jobRepo.GetAvailableJobs(ctx, []string{"test_claim"}, time.Now().Add(2*time.Minute), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(jobs))
+}
+
+func Test_jobRepo_RetryAndDeadJob(t *testing.T) {
+	jobRepo := job.NewJobRepo(testDataSource)
+	ctx := context.TODO()
+
+	testJob := &entity.Job{
+		Topic:       "test_retry",
+		Payload:     `"1"`,
+		Status:      entity.JobStatusPending,
+		AvailableAt: time.Now().Add(-time.Second),
+	}
+	err := jobRepo.AddJob(ctx, testJob)
+	assert.NoError(t, err)
+
+	claimed, err := jobRepo.ClaimJob(ctx, testJob, "worker-1", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	owned, err := jobRepo.RetryJob(ctx, testJob, time.Now().Add(-time.Second), "failed")
+	assert.NoError(t, err)
+	assert.True(t, owned)
+	jobs, err := jobRepo.GetAvailableJobs(ctx, []string{"test_retry"}, time.Now(), 10)
+	assert.NoError(t, err)
+	if !assert.Equal(t, 1, len(jobs)) {
+		return
+	}
+	assert.Equal(t, entity.JobStatusPending, jobs[0].Status)
+	assert.Equal(t, "failed", jobs[0].LastError)
+
+	// the job is not claimed after retried
+	owned, err = jobRepo.DeadJob(ctx, testJob, "failed again")
+	assert.NoError(t, err)
+	assert.False(t, owned)
+
+	claimed, err = jobRepo.ClaimJob(ctx, jobs[0], "worker-1", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	owned, err = jobRepo.DeadJob(ctx, jobs[0], "failed again")
+	assert.NoError(t, err)
+	assert.True(t, owned)
+	jobs, err = jobRepo.GetAvailableJobs(ctx, []string{"test_retry"}, time.Now().Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(jobs))
+}
@@ -102,3 +102,29 @@ func Test_notificationRepo_UpdateNotificationContent(t *testing.T) {
 	assert.True(t, exists)
 	assert.Equal(t, got.Content, ent.Content)
 }
+
+func Test_notificationRepo_AddNotificationDedupKey(t *testing.T) {
+	notificationRepo := notification.NewNotificationRepo(testDataSource)
+	dedupKey := "test-notification-dedup-key"
+	ent := buildNotificationEntity()
+	ent.DedupKey = &dedupKey
+	err := notificationRepo.AddNotification(context.TODO(), ent)
+	assert.NoError(t, err)
+
+	exist, err := notificationRepo.ExistDedupKey(context.TODO(), dedupKey)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+
+	// the redelivered message is ignored
+	duplicate := buildNotificationEntity()
+	duplicate.DedupKey = &dedupKey
+	err = notificationRepo.AddNotification(context.TODO(), duplicate)
+	assert.NoError(t, err)
+	assert.Empty(t, duplicate.ID)
+
+	// the notifications without dedup key are not limited
+	err = notificationRepo.AddNotification(context.TODO(), buildNotificationEntity())
+	assert.NoError(t, err)
+	err = notificationRepo.AddNotification(context.TODO(), buildNotificationEntity())
+	assert.NoError(t, err)
+}
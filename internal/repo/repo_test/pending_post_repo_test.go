@@ -0,0 +1,86 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/pending_post"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pendingPostRepo_ReviewPendingPost(t *testing.T) {
+	pendingPostRepo := pending_post.NewPendingPostRepo(testDataSource)
+	questionPost := &entity.PendingPost{
+		UserID:     "1",
+		ObjectID:   "10010000000000201",
+		ObjectType: 1,
+		Status:     entity.PendingPostStatusPending,
+	}
+	commentPost := &entity.PendingPost{
+		UserID:     "1",
+		ObjectID:   "10070000000000201",
+		ObjectType: 7,
+		Status:     entity.PendingPostStatusPending,
+		Extra:      `{"mention_username_list":["admin"]}`,
+	}
+	assert.NoError(t, pendingPostRepo.AddPendingPost(context.TODO(), questionPost))
+	assert.NoError(t, pendingPostRepo.AddPendingPost(context.TODO(), commentPost))
+
+	posts, total, err := pendingPostRepo.GetPendingPostPage(context.TODO(), 1, 10, []int{1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, questionPost.ObjectID, posts[0].ObjectID)
+
+	count, err := pendingPostRepo.GetPendingPostCount(context.TODO(), []int{1, 7})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// the post can only be reviewed once
+	updated, err := pendingPostRepo.UpdatePendingPostStatus(context.TODO(), questionPost.ID,
+		entity.PendingPostStatusApproved, "2")
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = pendingPostRepo.UpdatePendingPostStatus(context.TODO(), questionPost.ID,
+		entity.PendingPostStatusRejected, "3")
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	got, exist, err := pendingPostRepo.GetPendingPostByObjectID(context.TODO(), questionPost.ObjectID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, entity.PendingPostStatusApproved, got.Status)
+	assert.Equal(t, "2", got.ReviewUserID)
+
+	// the post which can not be published is put back to the queue and can be reviewed again
+	assert.NoError(t, pendingPostRepo.ReleasePendingPost(context.TODO(), questionPost.ID,
+		entity.PendingPostStatusApproved))
+	got, _, err = pendingPostRepo.GetPendingPost(context.TODO(), questionPost.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PendingPostStatusPending, got.Status)
+	assert.Equal(t, "0", got.ReviewUserID)
+	updated, err = pendingPostRepo.UpdatePendingPostStatus(context.TODO(), questionPost.ID,
+		entity.PendingPostStatusApproved, "2")
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	got, exist, err = pendingPostRepo.GetPendingPost(context.TODO(), commentPost.ID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, commentPost.Extra, got.Extra)
+
+	count, err = pendingPostRepo.GetPendingPostCount(context.TODO(), []int{1, 7})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// the reviewed post is queued again
+	requeued := &entity.PendingPost{UserID: "1", ObjectID: questionPost.ObjectID, ObjectType: 1,
+		Status: entity.PendingPostStatusPending, Extra: `{"spam_reason":"link"}`}
+	assert.NoError(t, pendingPostRepo.AddPendingPost(context.TODO(), requeued))
+	assert.Equal(t, questionPost.ID, requeued.ID)
+	got, _, err = pendingPostRepo.GetPendingPost(context.TODO(), questionPost.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PendingPostStatusPending, got.Status)
+	assert.Equal(t, "0", got.ReviewUserID)
+	assert.Equal(t, requeued.Extra, got.Extra)
+}
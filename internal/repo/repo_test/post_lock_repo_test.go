@@ -0,0 +1,75 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/post_lock"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_postLockRepo_ExpirePostLocks(t *testing.T) {
+	const (
+		expiredID   = "10010000000006001"
+		permanentID = "10010000000006002"
+		answerID    = "10020000000006001"
+	)
+	postLockRepo := post_lock.NewPostLockRepo(testDataSource)
+	_, err := testDataSource.DB.Insert(
+		&entity.Question{ID: expiredID, UserID: "1", Title: "expired", Status: entity.QuestionStatusAvailable},
+		&entity.Question{ID: permanentID, UserID: "1", Title: "permanent", Status: entity.QuestionStatusAvailable},
+	)
+	assert.NoError(t, err)
+	_, err = testDataSource.DB.Insert(&entity.Answer{ID: answerID, QuestionID: expiredID, UserID: "1",
+		Status: entity.AnswerStatusAvailable, Accepted: schema.AnswerAcceptedFailed})
+	assert.NoError(t, err)
+
+	expiredAt := time.Now().Add(time.Hour)
+	assert.NoError(t, postLockRepo.UpdateQuestionLock(context.TODO(), expiredID, true, expiredAt))
+	assert.NoError(t, postLockRepo.UpdateQuestionProtect(context.TODO(), expiredID, true, expiredAt))
+	assert.NoError(t, postLockRepo.UpdateAnswerLock(context.TODO(), answerID, true, expiredAt))
+	// the lock without expiry is never released by the cron job
+	assert.NoError(t, postLockRepo.UpdateQuestionLock(context.TODO(), permanentID, true, time.Time{}))
+	assert.NoError(t, postLockRepo.UpdateQuestionProtect(context.TODO(), permanentID, true, time.Time{}))
+
+	expired, err := postLockRepo.ExpirePostLocks(context.TODO(), time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, expired.LockedQuestionIDs)
+	assert.Empty(t, expired.ProtectedQuestionIDs)
+	assert.Empty(t, expired.LockedAnswerIDs)
+
+	expired, err = postLockRepo.ExpirePostLocks(context.TODO(), expiredAt.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{expiredID}, expired.LockedQuestionIDs)
+	assert.Equal(t, []string{expiredID}, expired.ProtectedQuestionIDs)
+	assert.Equal(t, []string{answerID}, expired.LockedAnswerIDs)
+
+	// the released posts are not released again
+	expired, err = postLockRepo.ExpirePostLocks(context.TODO(), expiredAt.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Empty(t, expired.LockedQuestionIDs)
+	assert.Empty(t, expired.ProtectedQuestionIDs)
+	assert.Empty(t, expired.LockedAnswerIDs)
+
+	released, permanent, answer := &entity.Question{}, &entity.Question{}, &entity.Answer{}
+	_, err = testDataSource.DB.ID(expiredID).Get(released)
+	assert.NoError(t, err)
+	_, err = testDataSource.DB.ID(permanentID).Get(permanent)
+	assert.NoError(t, err)
+	_, err = testDataSource.DB.ID(answerID).Get(answer)
+	assert.NoError(t, err)
+	assert.False(t, released.Locked)
+	assert.False(t, released.Protected)
+	assert.False(t, answer.Locked)
+	assert.True(t, permanent.IsLocked())
+	assert.True(t, permanent.IsProtected())
+
+	assert.NoError(t, postLockRepo.UpdateQuestionLock(context.TODO(), permanentID, false, time.Time{}))
+	permanent = &entity.Question{}
+	_, err = testDataSource.DB.ID(permanentID).Get(permanent)
+	assert.NoError(t, err)
+	assert.False(t, permanent.IsLocked())
+}
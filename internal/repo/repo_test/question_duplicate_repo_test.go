@@ -0,0 +1,91 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/question_duplicate"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_questionDuplicateRepo_MergeQuestion(t *testing.T) {
+	const (
+		canonicalID = "10010000000005001"
+		duplicateID = "10010000000005002"
+		linkedID    = "10010000000005003"
+	)
+	questionDuplicateRepo := question_duplicate.NewQuestionDuplicateRepo(testDataSource)
+	_, err := testDataSource.DB.Insert(
+		&entity.Question{ID: canonicalID, UserID: "1", Title: "canonical", Status: entity.QuestionStatusAvailable,
+			AnswerCount: 1},
+		&entity.Question{ID: duplicateID, UserID: "1", Title: "duplicate", Status: entity.QuestionStatusClosed,
+			AnswerCount: 2, AcceptedAnswerID: "10020000000005003"},
+		&entity.Question{ID: linkedID, UserID: "1", Title: "linked", Status: entity.QuestionStatusClosed},
+	)
+	assert.NoError(t, err)
+	_, err = testDataSource.DB.Insert(
+		&entity.Answer{ID: "10020000000005001", QuestionID: canonicalID, UserID: "1",
+			Status: entity.AnswerStatusAvailable, Accepted: schema.AnswerAcceptedFailed},
+		&entity.Answer{ID: "10020000000005002", QuestionID: duplicateID, UserID: "1",
+			Status: entity.AnswerStatusAvailable, Accepted: schema.AnswerAcceptedFailed},
+		&entity.Answer{ID: "10020000000005003", QuestionID: duplicateID, UserID: "1",
+			Status: entity.AnswerStatusAvailable, Accepted: schema.AnswerAcceptedEnable},
+	)
+	assert.NoError(t, err)
+	_, err = testDataSource.DB.Insert(
+		&entity.Comment{ID: "10070000000005001", ObjectID: duplicateID, QuestionID: duplicateID, UserID: "1",
+			Status: entity.CommentStatusAvailable},
+		&entity.Comment{ID: "10070000000005002", ObjectID: "10020000000005002", QuestionID: duplicateID, UserID: "1",
+			Status: entity.CommentStatusAvailable},
+	)
+	assert.NoError(t, err)
+
+	// the question linked to the duplicate is linked to the canonical question after merged
+	assert.NoError(t, questionDuplicateRepo.SaveQuestionDuplicate(context.TODO(),
+		&entity.QuestionDuplicate{QuestionID: linkedID, DuplicateOfID: duplicateID, UserID: "1"}))
+	assert.NoError(t, questionDuplicateRepo.SaveQuestionDuplicate(context.TODO(),
+		&entity.QuestionDuplicate{QuestionID: duplicateID, DuplicateOfID: canonicalID, UserID: "1"}))
+	questionIDs, err := questionDuplicateRepo.GetDuplicateQuestionIDs(context.TODO(), canonicalID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{duplicateID}, questionIDs)
+
+	err = questionDuplicateRepo.MergeQuestion(context.TODO(),
+		&entity.QuestionDuplicate{QuestionID: duplicateID, DuplicateOfID: canonicalID, UserID: "2"})
+	assert.NoError(t, err)
+
+	canonical, duplicate := &entity.Question{}, &entity.Question{}
+	_, err = testDataSource.DB.ID(canonicalID).Get(canonical)
+	assert.NoError(t, err)
+	_, err = testDataSource.DB.ID(duplicateID).Get(duplicate)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, canonical.AnswerCount)
+	assert.Equal(t, entity.QuestionStatusDeleted, duplicate.Status)
+	assert.Equal(t, "0", duplicate.AcceptedAnswerID)
+
+	answers := make([]*entity.Answer, 0)
+	assert.NoError(t, testDataSource.DB.Where("question_id = ?", canonicalID).Find(&answers))
+	assert.Len(t, answers, 3)
+	for _, answer := range answers {
+		assert.Equal(t, schema.AnswerAcceptedFailed, answer.Accepted)
+	}
+	comments := make([]*entity.Comment, 0)
+	assert.NoError(t, testDataSource.DB.Where("question_id = ?", canonicalID).Asc("id").Find(&comments))
+	assert.Len(t, comments, 2)
+	assert.Equal(t, canonicalID, comments[0].ObjectID)
+
+	merged, exist, err := questionDuplicateRepo.GetQuestionDuplicate(context.TODO(), duplicateID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.True(t, merged.Merged)
+	assert.Equal(t, "2", merged.UserID)
+	questionIDs, err = questionDuplicateRepo.GetDuplicateQuestionIDs(context.TODO(), canonicalID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{linkedID}, questionIDs)
+
+	assert.NoError(t, questionDuplicateRepo.RemoveQuestionDuplicate(context.TODO(), linkedID))
+	_, exist, err = questionDuplicateRepo.GetQuestionDuplicate(context.TODO(), linkedID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
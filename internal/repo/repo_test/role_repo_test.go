@@ -0,0 +1,83 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/role"
+	"github.com/answerdev/answer/internal/repo/tag"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_roleRepo_Role(t *testing.T) {
+	roleRepo := role.NewRoleRepo(testDataSource)
+	newRole := &entity.Role{Name: "Reviewer", Description: "review posts"}
+	err := roleRepo.AddRole(context.TODO(), newRole)
+	assert.NoError(t, err)
+	assert.NotZero(t, newRole.ID)
+
+	newRole.Name = "Kubernetes moderator"
+	err = roleRepo.UpdateRole(context.TODO(), newRole)
+	assert.NoError(t, err)
+	got, exist, err := roleRepo.GetRoleByName(context.TODO(), "Kubernetes moderator")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, newRole.ID, got.ID)
+
+	// the powers are removed with the role
+	rolePowerRelRepo := role.NewRolePowerRelRepo(testDataSource)
+	err = rolePowerRelRepo.ReplaceRolePowerRelList(context.TODO(), newRole.ID, []*entity.RolePowerRel{
+		{RoleID: newRole.ID, PowerType: "question.close"},
+	})
+	assert.NoError(t, err)
+	err = roleRepo.RemoveRole(context.TODO(), newRole.ID)
+	assert.NoError(t, err)
+	_, exist, err = roleRepo.GetRole(context.TODO(), newRole.ID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	rels, err := rolePowerRelRepo.GetRolePowerRelList(context.TODO(), newRole.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, rels)
+}
+
+func Test_rolePowerRelRepo_TagScope(t *testing.T) {
+	const (
+		roleID     = 1001
+		questionID = "10010000000001001"
+		tagID      = "10030000000001001"
+	)
+	tagRelRepo := tag.NewTagRelRepo(testDataSource)
+	err := tagRelRepo.AddTagRelList(context.TODO(), []*entity.TagRel{
+		{ObjectID: questionID, TagID: tagID, Status: entity.TagRelStatusAvailable},
+	})
+	assert.NoError(t, err)
+
+	rolePowerRelRepo := role.NewRolePowerRelRepo(testDataSource)
+	err = rolePowerRelRepo.ReplaceRolePowerRelList(context.TODO(), roleID, []*entity.RolePowerRel{
+		{RoleID: roleID, PowerType: "question.close", TagID: 10030000000001001},
+		{RoleID: roleID, PowerType: "tag.synonym"},
+	})
+	assert.NoError(t, err)
+
+	// the power scoped to tag does not work everywhere
+	powers, err := rolePowerRelRepo.GetRolePowerTypeList(context.TODO(), roleID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tag.synonym"}, powers)
+
+	has, err := rolePowerRelRepo.CheckRolePowerInObjectTags(context.TODO(), roleID, "question.close", questionID)
+	assert.NoError(t, err)
+	assert.True(t, has)
+	has, err = rolePowerRelRepo.CheckRolePowerInObjectTags(context.TODO(), roleID, "question.reopen", questionID)
+	assert.NoError(t, err)
+	assert.False(t, has)
+	has, err = rolePowerRelRepo.CheckRolePowerInObjectTags(context.TODO(), roleID, "question.close", "10010000000001002")
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	err = rolePowerRelRepo.ReplaceRolePowerRelList(context.TODO(), roleID, nil)
+	assert.NoError(t, err)
+	rels, err := rolePowerRelRepo.GetRolePowerRelList(context.TODO(), roleID)
+	assert.NoError(t, err)
+	assert.Empty(t, rels)
+}
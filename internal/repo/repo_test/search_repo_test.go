@@ -0,0 +1,115 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/config"
+	"github.com/answerdev/answer/internal/repo/search_common"
+	"github.com/answerdev/answer/internal/repo/unique"
+	"github.com/answerdev/answer/internal/repo/user"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_searchRepo_SearchQuestions(t *testing.T) {
+	questionList := []*entity.Question{
+		{
+			ID:           "10010000000000901",
+			UserID:       "1",
+			Title:        "How to configure nginx as a reverse proxy",
+			OriginalText: "I want to put nginx in front of answer.",
+			ParsedText:   "<p>I want to put nginx in front of answer.</p>",
+			Status:       entity.QuestionStatusAvailable,
+		},
+		{
+			ID:           "10010000000000902",
+			UserID:       "1",
+			Title:        "Which database is supported",
+			OriginalText: "Can I use postgres or sqlite?",
+			ParsedText:   "<p>Can I use postgres or sqlite?</p>",
+			Status:       entity.QuestionStatusAvailable,
+		},
+	}
+	_, err := testDataSource.DB.Insert(questionList)
+	assert.NoError(t, err)
+
+	userCommon := usercommon.NewUserCommon(user.NewUserRepo(testDataSource, config.NewConfigRepo(testDataSource)))
+	searchRepo, cleanup, err := search_common.NewSearchRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource), userCommon, nil)
+	assert.NoError(t, err)
+	defer cleanup()
+
+	resp, total, err := searchRepo.SearchQuestions(context.TODO(), []string{"nginx"}, nil, false, -1, -1, 1, 10, "relevance")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	if assert.Equal(t, 1, len(resp)) {
+		assert.Equal(t, questionList[0].ID, resp[0].Object.ID)
+	}
+
+	resp, total, err = searchRepo.SearchQuestions(context.TODO(), []string{"sqlite"}, nil, false, -1, -1, 1, 10, "newest")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	if assert.Equal(t, 1, len(resp)) {
+		assert.Equal(t, questionList[1].ID, resp[0].Object.ID)
+	}
+}
+
+func Test_searchIndexRepo_SearchContentsNegativeVotes(t *testing.T) {
+	question := &entity.Question{
+		ID:           "10010000000000903",
+		UserID:       "1",
+		Title:        "Why is downvoteditem rejected",
+		OriginalText: "The downvoteditem has negative votes.",
+		ParsedText:   "<p>The downvoteditem has negative votes.</p>",
+		Status:       entity.QuestionStatusAvailable,
+		VoteCount:    -2,
+	}
+	_, err := testDataSource.DB.Insert(question)
+	assert.NoError(t, err)
+
+	userCommon := usercommon.NewUserCommon(user.NewUserRepo(testDataSource, config.NewConfigRepo(testDataSource)))
+	searchRepo, cleanup, err := search_common.NewSearchRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource),
+		userCommon, &data.SearchConf{Driver: data.SearchDriverIndex, IndexPath: t.TempDir()})
+	assert.NoError(t, err)
+	defer cleanup()
+	assert.NoError(t, searchRepo.SyncObject(context.TODO(), question.ID))
+
+	// the negative votes means no filter, same as the sql search
+	resp, total, err := searchRepo.SearchContents(context.TODO(), []string{"downvoteditem"}, nil, "", -1, 1, 10, "newest")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	if assert.Equal(t, 1, len(resp)) {
+		assert.Equal(t, question.ID, resp[0].Object.ID)
+	}
+
+	_, total, err = searchRepo.SearchContents(context.TODO(), []string{"downvoteditem"}, nil, "", 0, 1, 10, "newest")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
+
+func Test_searchIndexRepo_SearchPendingQuestion(t *testing.T) {
+	question := &entity.Question{
+		ID:           "10010000000000904",
+		UserID:       "1",
+		Title:        "Where is the heldforreview post",
+		OriginalText: "The heldforreview post is waiting for review.",
+		ParsedText:   "<p>The heldforreview post is waiting for review.</p>",
+		Status:       entity.QuestionStatusPending,
+	}
+	_, err := testDataSource.DB.Insert(question)
+	assert.NoError(t, err)
+
+	userCommon := usercommon.NewUserCommon(user.NewUserRepo(testDataSource, config.NewConfigRepo(testDataSource)))
+	searchRepo, cleanup, err := search_common.NewSearchRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource),
+		userCommon, &data.SearchConf{Driver: data.SearchDriverIndex, IndexPath: t.TempDir()})
+	assert.NoError(t, err)
+	defer cleanup()
+
+	// the pending question is not indexed until it is published
+	assert.NoError(t, searchRepo.SyncObject(context.TODO(), question.ID))
+	_, total, err := searchRepo.SearchContents(context.TODO(), []string{"heldforreview"}, nil, "", -1, 1, 10, "newest")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
@@ -0,0 +1,77 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/activity"
+	"github.com/answerdev/answer/internal/repo/activity_common"
+	"github.com/answerdev/answer/internal/repo/config"
+	"github.com/answerdev/answer/internal/repo/rank"
+	"github.com/answerdev/answer/internal/repo/serial_vote"
+	"github.com/answerdev/answer/internal/repo/unique"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_serialVoteRepo_ReverseVotes(t *testing.T) {
+	configRepo := config.NewConfigRepo(testDataSource)
+	uniqueIDRepo := unique.NewUniqueIDRepo(testDataSource)
+	activityRepo := activity_common.NewActivityRepo(testDataSource, uniqueIDRepo, configRepo)
+	userRankRepo := rank.NewUserRankRepo(testDataSource, configRepo)
+	voteRepo := activity.NewVoteRepo(testDataSource, uniqueIDRepo, configRepo, activityRepo, userRankRepo,
+		activity_common.NewVoteRepo(testDataSource, activityRepo))
+	serialVoteRepo := serial_vote.NewSerialVoteRepo(testDataSource, activityRepo, userRankRepo)
+
+	voter := &entity.User{Username: "serial_voter", EMail: "serial_voter@example.com", Rank: 100,
+		Status: entity.UserStatusAvailable}
+	receiver := &entity.User{Username: "serial_receiver", EMail: "serial_receiver@example.com", Rank: 1,
+		Status: entity.UserStatusAvailable}
+	_, err := testDataSource.DB.Insert(voter, receiver)
+	assert.NoError(t, err)
+	getRank := func(userID string) int {
+		userInfo := &entity.User{}
+		_, err := testDataSource.DB.ID(userID).Get(userInfo)
+		assert.NoError(t, err)
+		return userInfo.Rank
+	}
+
+	// the reputation earned by votes is limited every day
+	assert.NoError(t, configRepo.SetConfig("daily_rank_limit", "20"))
+	defer func() {
+		assert.NoError(t, configRepo.SetConfig("daily_rank_limit", "200"))
+	}()
+	questionIDs := []string{"10010000000004001", "10010000000004002", "10010000000004003"}
+	for _, questionID := range questionIDs {
+		_, err = testDataSource.DB.Insert(&entity.Question{ID: questionID, UserID: receiver.ID, Title: "serial vote",
+			Status: entity.QuestionStatusAvailable})
+		assert.NoError(t, err)
+		_, err = voteRepo.VoteUp(context.TODO(), questionID, voter.ID, receiver.ID)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 21, getRank(receiver.ID))
+
+	since := time.Now().Add(-time.Hour)
+	pairs, err := serialVoteRepo.GetVotePairs(context.TODO(), since)
+	assert.NoError(t, err)
+	assert.Contains(t, pairs, &entity.ActivityUserVotePairStat{UserID: receiver.ID, TriggerUserID: voter.ID, VoteCount: 3})
+
+	objectIDs, reversedRank, err := serialVoteRepo.ReverseVotes(context.TODO(), voter.ID, receiver.ID, since)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, questionIDs, objectIDs)
+	assert.Equal(t, 20, reversedRank)
+	assert.Equal(t, 1, getRank(receiver.ID))
+	questionInfo := &entity.Question{}
+	_, err = testDataSource.DB.ID(questionIDs[0]).Get(questionInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, questionInfo.VoteCount)
+	resp, err := voteRepo.GetVoteResultByObjectId(context.TODO(), questionIDs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.UpVotes)
+
+	// the reversed votes are not found again
+	objectIDs, _, err = serialVoteRepo.ReverseVotes(context.TODO(), voter.ID, receiver.ID, since)
+	assert.NoError(t, err)
+	assert.Empty(t, objectIDs)
+}
@@ -0,0 +1,55 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/spam"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_spamRepo_TrainSpamTokens(t *testing.T) {
+	spamRepo := spam.NewSpamRepo(testDataSource)
+	assert.NoError(t, spamRepo.TrainSpamTokens(context.TODO(), []string{"casino", "bonus"}, true))
+	assert.NoError(t, spamRepo.TrainSpamTokens(context.TODO(), []string{"casino", "golang"}, false))
+	assert.NoError(t, spamRepo.TrainSpamTokens(context.TODO(), []string{"casino"}, true))
+
+	spamTokens, err := spamRepo.GetSpamTokens(context.TODO(), []string{"casino", "golang", "unknown"})
+	assert.NoError(t, err)
+	assert.Len(t, spamTokens, 2)
+	for _, spamToken := range spamTokens {
+		switch spamToken.Token {
+		case "casino":
+			assert.Equal(t, int64(2), spamToken.SpamCount)
+			assert.Equal(t, int64(1), spamToken.HamCount)
+		case "golang":
+			assert.Equal(t, int64(0), spamToken.SpamCount)
+			assert.Equal(t, int64(1), spamToken.HamCount)
+		}
+	}
+}
+
+func Test_spamRepo_GetSpamLogPage(t *testing.T) {
+	spamRepo := spam.NewSpamRepo(testDataSource)
+	assert.NoError(t, spamRepo.AddSpamLog(context.TODO(), &entity.SpamLog{
+		UserID:     "1",
+		ObjectType: "question",
+		Action:     entity.SpamActionReview,
+		Checker:    "link_limit",
+		Content:    "spam question",
+	}))
+	assert.NoError(t, spamRepo.AddSpamLog(context.TODO(), &entity.SpamLog{
+		UserID:     "0",
+		ObjectType: "user",
+		Action:     entity.SpamActionReject,
+		Checker:    "blocklist",
+		Content:    "spam@example.com",
+	}))
+
+	spamLogs, total, err := spamRepo.GetSpamLogPage(context.TODO(), 1, 10,
+		&entity.SpamLog{Action: entity.SpamActionReject})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "blocklist", spamLogs[0].Checker)
+}
@@ -89,3 +89,35 @@ func Test_tagListRepo_GetObjectTagRelWithoutStatus(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), count)
 }
+
+func Test_tagListRepo_PendingTagRel(t *testing.T) {
+	tagRelRepo := tag.NewTagRelRepo(testDataSource)
+	err := tagRelRepo.AddTagRelList(context.TODO(), []*entity.TagRel{
+		{ObjectID: "3", TagID: "3", Status: entity.TagRelStatusPending},
+	})
+	assert.NoError(t, err)
+
+	// the pending relation is neither listed nor counted
+	relList, err := tagRelRepo.GetObjectTagRelList(context.TODO(), "3")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(relList))
+	count, err := tagRelRepo.CountTagRelByTagID(context.TODO(), "3")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	relList, err = tagRelRepo.GetObjectPendingTagRelList(context.TODO(), "3")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(relList))
+
+	err = tagRelRepo.EnableTagRelByIDs(context.TODO(), []int64{relList[0].ID})
+	assert.NoError(t, err)
+	count, err = tagRelRepo.CountTagRelByTagID(context.TODO(), "3")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	err = tagRelRepo.HoldTagRelByIDs(context.TODO(), []int64{relList[0].ID})
+	assert.NoError(t, err)
+	count, err = tagRelRepo.CountTagRelByTagID(context.TODO(), "3")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,77 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/repo/two_factor"
+	two_factor_service "github.com/answerdev/answer/internal/service/two_factor"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_twoFactorRepo_Enable(t *testing.T) {
+	twoFactorRepo := two_factor.NewTwoFactorRepo(testDataSource)
+	err := twoFactorRepo.SavePending(context.TODO(), "1", "secret1")
+	assert.NoError(t, err)
+	// the pending secret is replaced by the new one
+	err = twoFactorRepo.SavePending(context.TODO(), "1", "secret2")
+	assert.NoError(t, err)
+
+	got, exist, err := twoFactorRepo.GetByUserID(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "secret2", got.Secret)
+	assert.False(t, got.Enabled)
+
+	err = twoFactorRepo.Enable(context.TODO(), "1", `["a","b"]`)
+	assert.NoError(t, err)
+	got, _, err = twoFactorRepo.GetByUserID(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.True(t, got.Enabled)
+	assert.Equal(t, `["a","b"]`, got.RecoveryCodes)
+
+	// the code can be only used once
+	updated, err := twoFactorRepo.UpdateLastUsedStep(context.TODO(), "1", 100)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = twoFactorRepo.UpdateLastUsedStep(context.TODO(), "1", 100)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	// the recovery code can be only used once
+	updated, err = twoFactorRepo.UpdateRecoveryCodes(context.TODO(), "1", `["a","b"]`, `["b"]`)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = twoFactorRepo.UpdateRecoveryCodes(context.TODO(), "1", `["a","b"]`, `["a"]`)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	err = twoFactorRepo.RemoveByUserID(context.TODO(), "1")
+	assert.NoError(t, err)
+	_, exist, err = twoFactorRepo.GetByUserID(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_twoFactorRepo_LoginCache(t *testing.T) {
+	twoFactorRepo := two_factor.NewTwoFactorRepo(testDataSource)
+	err := twoFactorRepo.SetLoginCache(context.TODO(), "token", &two_factor_service.LoginCache{UserID: "1"})
+	assert.NoError(t, err)
+
+	got, exist, err := twoFactorRepo.GetLoginCache(context.TODO(), "token")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "1", got.UserID)
+
+	for i := int64(1); i <= 2; i++ {
+		attempts, err := twoFactorRepo.IncreaseLoginAttempts(context.TODO(), "token")
+		assert.NoError(t, err)
+		assert.Equal(t, i, attempts)
+	}
+
+	err = twoFactorRepo.RemoveLoginCache(context.TODO(), "token")
+	assert.NoError(t, err)
+	_, exist, err = twoFactorRepo.GetLoginCache(context.TODO(), "token")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
@@ -0,0 +1,73 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/user_external_login"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_userExternalLoginRepo_AddUserExternalLogin(t *testing.T) {
+	userExternalLoginRepo := user_external_login.NewUserExternalLoginRepo(testDataSource)
+	info := &entity.ExternalLoginUserInfo{
+		UserID:     "1",
+		Provider:   "github",
+		ExternalID: "10001",
+		Name:       "admin",
+		Email:      "admin@admin.com",
+		MetaInfo:   "{}",
+	}
+	err := userExternalLoginRepo.AddUserExternalLogin(context.TODO(), info)
+	assert.NoError(t, err)
+
+	got, exist, err := userExternalLoginRepo.GetByExternalID(context.TODO(), "github", "10001")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "1", got.UserID)
+
+	_, exist, err = userExternalLoginRepo.GetByExternalID(context.TODO(), "google", "10001")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	// the same external user of provider can only be linked once
+	err = userExternalLoginRepo.AddUserExternalLogin(context.TODO(), &entity.ExternalLoginUserInfo{
+		UserID: "2", Provider: "github", ExternalID: "10001"})
+	assert.Error(t, err)
+
+	got.Name = "new name"
+	err = userExternalLoginRepo.UpdateInfo(context.TODO(), got)
+	assert.NoError(t, err)
+	got, _, err = userExternalLoginRepo.GetByExternalID(context.TODO(), "github", "10001")
+	assert.NoError(t, err)
+	assert.Equal(t, "new name", got.Name)
+}
+
+func Test_userExternalLoginRepo_CacheState(t *testing.T) {
+	userExternalLoginRepo := user_external_login.NewUserExternalLoginRepo(testDataSource)
+	err := userExternalLoginRepo.SetCacheState(context.TODO(), "state", "github")
+	assert.NoError(t, err)
+
+	provider, err := userExternalLoginRepo.GetCacheState(context.TODO(), "state")
+	assert.NoError(t, err)
+	assert.Equal(t, "github", provider)
+
+	// the state can only be used once
+	_, err = userExternalLoginRepo.GetCacheState(context.TODO(), "state")
+	assert.Error(t, err)
+}
+
+func Test_userExternalLoginRepo_CacheLoginCode(t *testing.T) {
+	userExternalLoginRepo := user_external_login.NewUserExternalLoginRepo(testDataSource)
+	err := userExternalLoginRepo.SetCacheLoginCode(context.TODO(), "code", "token")
+	assert.NoError(t, err)
+
+	accessToken, err := userExternalLoginRepo.GetCacheLoginCode(context.TODO(), "code")
+	assert.NoError(t, err)
+	assert.Equal(t, "token", accessToken)
+
+	// the code can only be used once
+	_, err = userExternalLoginRepo.GetCacheLoginCode(context.TODO(), "code")
+	assert.Error(t, err)
+}
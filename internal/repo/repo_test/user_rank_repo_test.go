@@ -0,0 +1,55 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/config"
+	"github.com/answerdev/answer/internal/repo/rank"
+	"github.com/stretchr/testify/assert"
+	"xorm.io/xorm"
+)
+
+func Test_userRankRepo_TriggerUserRankDailyLimit(t *testing.T) {
+	configRepo := config.NewConfigRepo(testDataSource)
+	userRankRepo := rank.NewUserRankRepo(testDataSource, configRepo)
+	activityType, err := configRepo.GetConfigType("question.voted_up")
+	assert.NoError(t, err)
+
+	user := &entity.User{Username: "daily_rank_user", EMail: "daily_rank_user@example.com", Rank: 1,
+		Status: entity.UserStatusAvailable}
+	_, err = testDataSource.DB.Insert(user)
+	assert.NoError(t, err)
+	// 195 of the daily limit 200 is earned today
+	_, err = testDataSource.DB.Insert(&entity.Activity{UserID: user.ID, ObjectID: "10010000000004001",
+		OriginalObjectID: "10010000000004001", ActivityType: activityType, Rank: 195, HasRank: 1})
+	assert.NoError(t, err)
+
+	trigger := func(deltaRank int) int {
+		appliedRank, err := testDataSource.DB.Transaction(func(session *xorm.Session) (any, error) {
+			return userRankRepo.TriggerUserRank(context.TODO(), session, user.ID, deltaRank, activityType)
+		})
+		assert.NoError(t, err)
+		return appliedRank.(int)
+	}
+	getRank := func() int {
+		userInfo := &entity.User{}
+		_, err := testDataSource.DB.ID(user.ID).Get(userInfo)
+		assert.NoError(t, err)
+		return userInfo.Rank
+	}
+
+	// only the rest of the daily limit is earned
+	assert.Equal(t, 5, trigger(15))
+	assert.Equal(t, 6, getRank())
+	_, err = testDataSource.DB.Insert(&entity.Activity{UserID: user.ID, ObjectID: "10010000000004002",
+		OriginalObjectID: "10010000000004002", ActivityType: activityType, Rank: 5, HasRank: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, trigger(10))
+	assert.Equal(t, 6, getRank())
+
+	// the lost rank is not limited but never lower than 1
+	assert.Equal(t, -5, trigger(-10))
+	assert.Equal(t, 1, getRank())
+}
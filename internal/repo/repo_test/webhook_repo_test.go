@@ -0,0 +1,60 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/repo/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_webhookRepo_Webhook(t *testing.T) {
+	webhookRepo := webhook.NewWebhookRepo(testDataSource)
+	hook := &entity.Webhook{Name: "chat", URL: "https://example.com/hook", Secret: "secret",
+		Events: "question.asked", Enabled: false}
+	err := webhookRepo.AddWebhook(context.TODO(), hook)
+	assert.NoError(t, err)
+
+	enabled, err := webhookRepo.GetEnabledWebhooks(context.TODO())
+	assert.NoError(t, err)
+	for _, w := range enabled {
+		assert.NotEqual(t, hook.ID, w.ID)
+	}
+
+	hook.Enabled = true
+	hook.Tags = "go"
+	err = webhookRepo.UpdateWebhook(context.TODO(), hook)
+	assert.NoError(t, err)
+	got, exist, err := webhookRepo.GetWebhook(context.TODO(), hook.ID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.True(t, got.Enabled)
+	assert.Equal(t, "go", got.Tags)
+
+	delivery := &entity.WebhookDelivery{WebhookID: hook.ID, Event: "question.asked", Payload: "{}",
+		Status: entity.WebhookDeliveryStatusPending}
+	err = webhookRepo.AddDelivery(context.TODO(), delivery)
+	assert.NoError(t, err)
+	delivery.Status = entity.WebhookDeliveryStatusSucceeded
+	delivery.Attempts = 1
+	delivery.ResponseStatus = 200
+	err = webhookRepo.UpdateDelivery(context.TODO(), delivery)
+	assert.NoError(t, err)
+
+	deliveries, total, err := webhookRepo.GetDeliveryPage(context.TODO(), hook.ID, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, entity.WebhookDeliveryStatusSucceeded, deliveries[0].Status)
+	assert.Equal(t, 200, deliveries[0].ResponseStatus)
+
+	// the deliveries are removed with the webhook
+	err = webhookRepo.RemoveWebhook(context.TODO(), hook.ID)
+	assert.NoError(t, err)
+	_, exist, err = webhookRepo.GetWebhook(context.TODO(), hook.ID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	_, exist, err = webhookRepo.GetDelivery(context.TODO(), delivery.ID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
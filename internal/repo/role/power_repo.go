@@ -25,7 +25,7 @@ func NewPowerRepo(data *data.Data) role.PowerRepo {
 // GetPowerList get  list all
 func (pr *powerRepo) GetPowerList(ctx context.Context, power *entity.Power) (powerList []*entity.Power, err error) {
 	powerList = make([]*entity.Power, 0)
-	err = pr.data.DB.Find(powerList, power)
+	err = pr.data.DB.Asc("id").Find(&powerList, power)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
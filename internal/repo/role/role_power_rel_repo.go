@@ -5,9 +5,11 @@ import (
 
 	"github.com/answerdev/answer/internal/base/data"
 	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
 	"github.com/answerdev/answer/internal/service/role"
 	"github.com/segmentfault/pacman/errors"
 	"xorm.io/builder"
+	"xorm.io/xorm"
 )
 
 // rolePowerRelRepo rolePowerRel repository
@@ -22,11 +24,60 @@ func NewRolePowerRelRepo(data *data.Data) role.RolePowerRelRepo {
 	}
 }
 
-// GetRolePowerTypeList get role power type list
+// GetRolePowerTypeList get role power type list, the powers scoped to tags are excluded
 func (rr *rolePowerRelRepo) GetRolePowerTypeList(ctx context.Context, roleID int) (powers []string, err error) {
 	powers = make([]string, 0)
 	err = rr.data.DB.Table("role_power_rel").
-		Cols("power_type").Where(builder.Eq{"role_id": roleID}).Find(&powers)
+		Cols("power_type").Where(builder.Eq{"role_id": roleID, "tag_id": 0}).Find(&powers)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetRolePowerRelList get all powers of role including the ones scoped to tags
+func (rr *rolePowerRelRepo) GetRolePowerRelList(ctx context.Context, roleID int) (
+	rels []*entity.RolePowerRel, err error) {
+	rels = make([]*entity.RolePowerRel, 0)
+	err = rr.data.DB.Where(builder.Eq{"role_id": roleID}).Asc("id").Find(&rels)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ReplaceRolePowerRelList replace all powers of role
+func (rr *rolePowerRelRepo) ReplaceRolePowerRelList(ctx context.Context, roleID int, rels []*entity.RolePowerRel) (
+	err error) {
+	_, err = rr.data.DB.Transaction(func(session *xorm.Session) (interface{}, error) {
+		if _, err := session.Where(builder.Eq{"role_id": roleID}).Delete(&entity.RolePowerRel{}); err != nil {
+			return nil, err
+		}
+		if len(rels) == 0 {
+			return nil, nil
+		}
+		if _, err := session.Insert(rels); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CheckRolePowerInObjectTags check whether the role has the power scoped to any tag of the object
+func (rr *rolePowerRelRepo) CheckRolePowerInObjectTags(ctx context.Context, roleID int, powerType, objectID string) (
+	has bool, err error) {
+	has, err = rr.data.DB.Table("role_power_rel").
+		Join("INNER", "tag_rel", "role_power_rel.tag_id = tag_rel.tag_id").
+		Where(builder.Eq{
+			"role_power_rel.role_id":    roleID,
+			"role_power_rel.power_type": powerType,
+			"tag_rel.object_id":         objectID,
+			"tag_rel.status":            entity.TagRelStatusAvailable,
+		}).Exist()
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/answerdev/answer/internal/entity"
 	service "github.com/answerdev/answer/internal/service/role"
 	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
 )
 
 // roleRepo role repository
@@ -44,3 +45,58 @@ func (rr *roleRepo) GetRoleAllMapping(ctx context.Context) (roleMapping map[int]
 	}
 	return roleMapping, nil
 }
+
+// GetRole get role by id
+func (rr *roleRepo) GetRole(ctx context.Context, roleID int) (role *entity.Role, exist bool, err error) {
+	role = &entity.Role{}
+	exist, err = rr.data.DB.ID(roleID).Get(role)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetRoleByName get role by name
+func (rr *roleRepo) GetRoleByName(ctx context.Context, name string) (role *entity.Role, exist bool, err error) {
+	role = &entity.Role{}
+	exist, err = rr.data.DB.Where("name = ?", name).Get(role)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AddRole add role
+func (rr *roleRepo) AddRole(ctx context.Context, role *entity.Role) (err error) {
+	_, err = rr.data.DB.Insert(role)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateRole update the name and description of role
+func (rr *roleRepo) UpdateRole(ctx context.Context, role *entity.Role) (err error) {
+	_, err = rr.data.DB.ID(role.ID).Cols("name", "description").Update(role)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveRole remove role and its powers
+func (rr *roleRepo) RemoveRole(ctx context.Context, roleID int) (err error) {
+	_, err = rr.data.DB.Transaction(func(session *xorm.Session) (interface{}, error) {
+		if _, err := session.Where("role_id = ?", roleID).Delete(&entity.RolePowerRel{}); err != nil {
+			return nil, err
+		}
+		if _, err := session.ID(roleID).Delete(&entity.Role{}); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,156 @@
+package search_common
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/segmentfault/pacman/log"
+	"xorm.io/xorm/schemas"
+)
+
+// fullTextMinWordLen the words shorter than it are not indexed by MySQL in default,
+// which is innodb_ft_min_token_size, and most of them are the stop words of Postgres.
+const fullTextMinWordLen = 3
+
+// fullTextTarget which table is searched by the full-text index
+type fullTextTarget int
+
+const (
+	fullTextQuestion fullTextTarget = iota
+	fullTextAnswer
+)
+
+// fullTextSearch build the search condition and relevance by the native full-text index of database.
+// The columns and expressions must be the same as the index created in migrations.
+type fullTextSearch struct {
+	dbType schemas.DBType
+}
+
+// loadFullTextSearch check whether the database supports full-text search,
+// nil will be returned if not, and search will fall back to LIKE matching.
+func (sr *searchRepo) loadFullTextSearch() *fullTextSearch {
+	sr.fullTextOnce.Do(func() {
+		dbType := sr.data.DB.Dialect().URI().DBType
+		switch dbType {
+		case schemas.MYSQL, schemas.POSTGRES:
+			sr.fullText = &fullTextSearch{dbType: dbType}
+		case schemas.SQLITE:
+			exist, err := sr.data.DB.IsTableExist("question_fts")
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if !exist {
+				log.Warn("sqlite fts table not found, search will use LIKE matching")
+				return
+			}
+			sr.fullText = &fullTextSearch{dbType: dbType}
+		}
+	})
+	return sr.fullText
+}
+
+// fullTextFor returns the full-text search if all the words can be matched by the full-text index,
+// otherwise nil is returned and the words are searched by LIKE matching.
+func (sr *searchRepo) fullTextFor(words []string) *fullTextSearch {
+	ft := sr.loadFullTextSearch()
+	if ft == nil || !canMatchFullText(words) {
+		return nil
+	}
+	return ft
+}
+
+// canMatchFullText the CJK text is not split into words by the default parsers, and the short words are not
+// indexed, so the full-text index yields nothing for them.
+func canMatchFullText(words []string) bool {
+	for _, word := range words {
+		if utf8.RuneCountInString(word) < fullTextMinWordLen {
+			return false
+		}
+		for _, r := range word {
+			if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// condition returns the sql condition that matches any of the words
+func (fs *fullTextSearch) condition(target fullTextTarget, words []string) (cond string, args []interface{}) {
+	switch fs.dbType {
+	case schemas.MYSQL:
+		return fs.mysqlMatch(target), []interface{}{strings.Join(words, " ")}
+	case schemas.POSTGRES:
+		query, args := fs.postgresQuery(words)
+		return fs.postgresVector(target) + " @@ " + query, args
+	case schemas.SQLITE:
+		table, idField := fs.sqliteTable(target)
+		return idField + " IN (SELECT `rowid` FROM `" + table + "` WHERE `" + table + "` MATCH ?)",
+			[]interface{}{sqliteMatchQuery(words)}
+	}
+	return "", nil
+}
+
+// addRelevanceField add the relevance field ranked by database to fields
+func (fs *fullTextSearch) addRelevanceField(target fullTextTarget, words, fields []string) (res []string, args []interface{}) {
+	var relevance string
+	switch fs.dbType {
+	case schemas.MYSQL:
+		relevance = fs.mysqlMatch(target)
+		args = []interface{}{strings.Join(words, " ")}
+	case schemas.POSTGRES:
+		var query string
+		query, args = fs.postgresQuery(words)
+		relevance = "ts_rank(" + fs.postgresVector(target) + ", " + query + ")"
+	case schemas.SQLITE:
+		// bm25 returns negative value, the better match the smaller value
+		table, idField := fs.sqliteTable(target)
+		relevance = "(SELECT -bm25(`" + table + "`) FROM `" + table + "` WHERE `" + table + "` MATCH ? AND `rowid` = " + idField + ")"
+		args = []interface{}{sqliteMatchQuery(words)}
+	}
+	res = append(append([]string{}, fields...), relevance+" as relevance")
+	return res, args
+}
+
+func (fs *fullTextSearch) mysqlMatch(target fullTextTarget) string {
+	if target == fullTextAnswer {
+		return "MATCH(`answer`.`original_text`) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	}
+	return "MATCH(`question`.`title`, `question`.`original_text`) AGAINST (? IN NATURAL LANGUAGE MODE)"
+}
+
+func (fs *fullTextSearch) postgresVector(target fullTextTarget) string {
+	if target == fullTextAnswer {
+		return "to_tsvector('english', `answer`.`original_text`)"
+	}
+	return "to_tsvector('english', `question`.`title` || ' ' || `question`.`original_text`)"
+}
+
+// postgresQuery combine the query of every word with OR
+func (fs *fullTextSearch) postgresQuery(words []string) (query string, args []interface{}) {
+	queries := make([]string, 0, len(words))
+	for _, word := range words {
+		queries = append(queries, "plainto_tsquery('english', ?)")
+		args = append(args, word)
+	}
+	return "(" + strings.Join(queries, " || ") + ")", args
+}
+
+func (fs *fullTextSearch) sqliteTable(target fullTextTarget) (table, idField string) {
+	if target == fullTextAnswer {
+		return "answer_fts", "`answer`.`id`"
+	}
+	return "question_fts", "`question`.`id`"
+}
+
+// sqliteMatchQuery quote every word as a fts5 string and combine them with OR,
+// so that the special characters in words will not be parsed as query syntax.
+func sqliteMatchQuery(words []string) string {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		quoted = append(quoted, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	return strings.Join(quoted, " OR ")
+}
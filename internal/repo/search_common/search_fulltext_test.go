@@ -0,0 +1,19 @@
+package search_common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanMatchFullText(t *testing.T) {
+	assert.True(t, canMatchFullText([]string{"golang", "database"}))
+	assert.True(t, canMatchFullText(nil))
+	// short words are not indexed
+	assert.False(t, canMatchFullText([]string{"golang", "go"}))
+	// CJK text is not split into words
+	assert.False(t, canMatchFullText([]string{"数据库连接"}))
+	assert.False(t, canMatchFullText([]string{"データベース"}))
+	assert.False(t, canMatchFullText([]string{"데이터베이스"}))
+	assert.False(t, canMatchFullText([]string{"golang语言"}))
+}
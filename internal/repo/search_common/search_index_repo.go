@@ -0,0 +1,559 @@
+package search_common
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/pkg/obj"
+	"github.com/answerdev/answer/pkg/tokenizer"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"xorm.io/builder"
+)
+
+const (
+	indexDocPrefix  = "doc:"
+	indexTermPrefix = "term:"
+
+	// titleWeight the terms in the title are more important than in the content
+	titleWeight = 3
+	// prefixMatchMinLen the shortest term which can be used to match other terms by prefix
+	prefixMatchMinLen = 3
+	// bm25K1 term frequency saturation of the bm25 ranking
+	bm25K1 = 1.2
+	// rebuildBatchSize the number of questions read from the database per batch when rebuilding
+	rebuildBatchSize = 100
+)
+
+// indexDoc the document stored in the inverted index, it contains the fields used by the search filters
+type indexDoc struct {
+	ID             string   `json:"id"`
+	ObjectType     string   `json:"object_type"`
+	QuestionID     string   `json:"question_id"`
+	UserID         string   `json:"user_id"`
+	TagIDs         []string `json:"tag_ids"`
+	VoteCount      int      `json:"vote_count"`
+	AnswerCount    int      `json:"answer_count"`
+	ViewCount      int      `json:"view_count"`
+	Accepted       bool     `json:"accepted"`
+	CreatedAt      int64    `json:"created_at"`
+	PostUpdateTime int64    `json:"post_update_time"`
+	Terms          []string `json:"terms"`
+}
+
+// searchIndexRepo search repository with the embedded inverted index
+type searchIndexRepo struct {
+	*searchRepo
+	db       *leveldb.DB
+	lock     sync.Mutex
+	docCount int64
+}
+
+// newSearchIndexRepo open or create the inverted index in the index path, the index is synced from the database
+// in the background on every start, because the changes made while the replica is stopped are not delivered to it.
+func newSearchIndexRepo(sqlRepo *searchRepo, indexPath string) (*searchIndexRepo, func(), error) {
+	db, err := leveldb.OpenFile(indexPath, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	sr := &searchIndexRepo{searchRepo: sqlRepo, db: db}
+
+	iter := db.NewIterator(util.BytesPrefix([]byte(indexDocPrefix)), nil)
+	for iter.Next() {
+		sr.docCount++
+	}
+	if err = iter.Error(); err != nil {
+		iter.Release()
+		_ = db.Close()
+		return nil, nil, err
+	}
+	iter.Release()
+
+	go sr.rebuild(context.Background())
+	cleanup := func() {
+		log.Info("closing the search index")
+		if err := db.Close(); err != nil {
+			log.Error(err)
+		}
+	}
+	return sr, cleanup, nil
+}
+
+// SearchContents search question and answer data
+func (sr *searchIndexRepo) SearchContents(ctx context.Context, words []string, tagIDs []string, userID string, votes int, page, size int, order string) (resp []schema.SearchResp, total int64, err error) {
+	words = filterWords(words)
+	if len(words) == 0 {
+		return sr.searchRepo.SearchContents(ctx, words, tagIDs, userID, votes, page, size, order)
+	}
+	return sr.search(ctx, words, page, size, order, func(doc *indexDoc) bool {
+		if !hasAllTags(doc, tagIDs) {
+			return false
+		}
+		if userID != "" && doc.UserID != userID {
+			return false
+		}
+		return matchCount(doc.VoteCount, votes)
+	})
+}
+
+// SearchQuestions search question data
+func (sr *searchIndexRepo) SearchQuestions(ctx context.Context, words []string, tagIDs []string, notAccepted bool, views, answers int, page, size int, order string) (resp []schema.SearchResp, total int64, err error) {
+	words = filterWords(words)
+	if len(words) == 0 {
+		return sr.searchRepo.SearchQuestions(ctx, words, tagIDs, notAccepted, views, answers, page, size, order)
+	}
+	return sr.search(ctx, words, page, size, order, func(doc *indexDoc) bool {
+		if doc.ObjectType != constant.QuestionObjectType || !hasAllTags(doc, tagIDs) {
+			return false
+		}
+		if notAccepted && doc.Accepted {
+			return false
+		}
+		if views > -1 && doc.ViewCount < views {
+			return false
+		}
+		return matchCount(doc.AnswerCount, answers)
+	})
+}
+
+// SearchAnswers search answer data
+func (sr *searchIndexRepo) SearchAnswers(ctx context.Context, words []string, tagIDs []string, accepted bool, questionID string, page, size int, order string) (resp []schema.SearchResp, total int64, err error) {
+	words = filterWords(words)
+	if len(words) == 0 {
+		return sr.searchRepo.SearchAnswers(ctx, words, tagIDs, accepted, questionID, page, size, order)
+	}
+	return sr.search(ctx, words, page, size, order, func(doc *indexDoc) bool {
+		if doc.ObjectType != constant.AnswerObjectType || !hasAllTags(doc, tagIDs) {
+			return false
+		}
+		if accepted && !doc.Accepted {
+			return false
+		}
+		return questionID == "" || doc.QuestionID == questionID
+	})
+}
+
+// SyncObject sync the question or answer to the inverted index
+func (sr *searchIndexRepo) SyncObject(ctx context.Context, objectID string) (err error) {
+	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return err
+	}
+	switch objectType {
+	case constant.QuestionObjectType:
+		question := &entity.Question{}
+		exist, err := sr.data.DB.Where("id = ?", objectID).Get(question)
+		if err != nil {
+			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if !exist {
+			return sr.removeDoc(objectID)
+		}
+		return sr.syncQuestion(ctx, question)
+	case constant.AnswerObjectType:
+		answer := &entity.Answer{}
+		exist, err := sr.data.DB.Where("id = ?", objectID).Get(answer)
+		if err != nil {
+			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if !exist {
+			return sr.removeDoc(objectID)
+		}
+		question := &entity.Question{}
+		exist, err = sr.data.DB.Where("id = ?", answer.QuestionID).Get(question)
+		if err != nil {
+			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if !exist {
+			return sr.removeDoc(objectID)
+		}
+		tagIDs, err := sr.getQuestionTagIDs(question.ID)
+		if err != nil {
+			return err
+		}
+		return sr.syncAnswer(answer, question, tagIDs)
+	}
+	return nil
+}
+
+// syncQuestion index the question and all its answers, because the answers are searched with the question's tags and status
+func (sr *searchIndexRepo) syncQuestion(ctx context.Context, question *entity.Question) (err error) {
+	tagIDs, err := sr.getQuestionTagIDs(question.ID)
+	if err != nil {
+		return err
+	}
+	if !isSearchableQuestion(question) {
+		err = sr.removeDoc(question.ID)
+	} else {
+		err = sr.putDoc(&indexDoc{
+			ID:             question.ID,
+			ObjectType:     constant.QuestionObjectType,
+			QuestionID:     question.ID,
+			UserID:         question.UserID,
+			TagIDs:         tagIDs,
+			VoteCount:      question.VoteCount,
+			AnswerCount:    question.AnswerCount,
+			ViewCount:      question.ViewCount,
+			Accepted:       question.AcceptedAnswerID != "" && question.AcceptedAnswerID != "0",
+			CreatedAt:      question.CreatedAt.Unix(),
+			PostUpdateTime: question.PostUpdateTime.Unix(),
+		}, question.Title, question.OriginalText)
+	}
+	if err != nil {
+		return err
+	}
+
+	answers := make([]*entity.Answer, 0)
+	err = sr.data.DB.Where("question_id = ?", question.ID).Find(&answers)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	for _, answer := range answers {
+		if err = sr.syncAnswer(answer, question, tagIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sr *searchIndexRepo) syncAnswer(answer *entity.Answer, question *entity.Question, tagIDs []string) (err error) {
+	if answer.Status != entity.AnswerStatusAvailable || !isSearchableQuestion(question) {
+		return sr.removeDoc(answer.ID)
+	}
+	return sr.putDoc(&indexDoc{
+		ID:             answer.ID,
+		ObjectType:     constant.AnswerObjectType,
+		QuestionID:     answer.QuestionID,
+		UserID:         answer.UserID,
+		TagIDs:         tagIDs,
+		VoteCount:      answer.VoteCount,
+		Accepted:       answer.Accepted == schema.AnswerAcceptedEnable,
+		CreatedAt:      answer.CreatedAt.Unix(),
+		PostUpdateTime: answer.CreatedAt.Unix(),
+	}, "", answer.OriginalText)
+}
+
+func (sr *searchIndexRepo) getQuestionTagIDs(questionID string) (tagIDs []string, err error) {
+	tagRelList := make([]*entity.TagRel, 0)
+	err = sr.data.DB.Where("object_id = ?", questionID).And("status = ?", entity.TagRelStatusAvailable).Find(&tagRelList)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	for _, tagRel := range tagRelList {
+		tagIDs = append(tagIDs, tagRel.TagID)
+	}
+	return tagIDs, nil
+}
+
+// putDoc replace the document and its terms in the index
+func (sr *searchIndexRepo) putDoc(doc *indexDoc, title, content string) (err error) {
+	termFreq := make(map[string]int)
+	for _, term := range tokenizer.Tokenize(title) {
+		termFreq[term] += titleWeight
+	}
+	for _, term := range tokenizer.Tokenize(content) {
+		termFreq[term]++
+	}
+	for term := range termFreq {
+		doc.Terms = append(doc.Terms, term)
+	}
+
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+	oldDoc, exist, err := sr.getDoc(doc.ID)
+	if err != nil {
+		return err
+	}
+
+	batch := new(leveldb.Batch)
+	if exist {
+		for _, term := range oldDoc.Terms {
+			batch.Delete(termKey(term, doc.ID))
+		}
+	}
+	for term, freq := range termFreq {
+		batch.Put(termKey(term, doc.ID), []byte(strconv.Itoa(freq)))
+	}
+	docBytes, _ := json.Marshal(doc)
+	batch.Put([]byte(indexDocPrefix+doc.ID), docBytes)
+	if err = sr.db.Write(batch, nil); err != nil {
+		return errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	if !exist {
+		atomic.AddInt64(&sr.docCount, 1)
+	}
+	return nil
+}
+
+// removeDoc remove the document and its terms from the index
+func (sr *searchIndexRepo) removeDoc(id string) (err error) {
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+	oldDoc, exist, err := sr.getDoc(id)
+	if err != nil || !exist {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	for _, term := range oldDoc.Terms {
+		batch.Delete(termKey(term, id))
+	}
+	batch.Delete([]byte(indexDocPrefix + id))
+	if err = sr.db.Write(batch, nil); err != nil {
+		return errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	atomic.AddInt64(&sr.docCount, -1)
+	return nil
+}
+
+func (sr *searchIndexRepo) getDoc(id string) (doc *indexDoc, exist bool, err error) {
+	docBytes, err := sr.db.Get([]byte(indexDocPrefix+id), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	doc = &indexDoc{}
+	if err = json.Unmarshal(docBytes, doc); err != nil {
+		return nil, false, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	return doc, true, nil
+}
+
+// search match the words in the index, filter the matched documents and return the page sorted by order.
+// Only the documents ranked before the end of page are kept, so the memory does not grow with the matches.
+func (sr *searchIndexRepo) search(ctx context.Context, words []string, page, size int, order string,
+	filter func(doc *indexDoc) bool) (resp []schema.SearchResp, total int64, err error) {
+	if page < 1 {
+		page = 1
+	}
+	relevance := sr.match(words)
+
+	top := newSearchTopK(order, page*size)
+	for id, score := range relevance {
+		doc, exist, err := sr.getDoc(id)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !exist || !filter(doc) {
+			continue
+		}
+		total++
+		top.add(&searchHit{
+			ID:             doc.ID,
+			ObjectType:     doc.ObjectType,
+			Relevance:      score,
+			VoteCount:      doc.VoteCount,
+			CreatedAt:      doc.CreatedAt,
+			PostUpdateTime: doc.PostUpdateTime,
+		})
+	}
+
+	hits := top.sorted()
+	start := (page - 1) * size
+	if start >= len(hits) {
+		return nil, total, nil
+	}
+	resp, err = sr.fetchResult(ctx, hits[start:])
+	return resp, total, err
+}
+
+// match the documents which contain any of the words, and all the terms of the word,
+// returns the relevance of each matched document.
+func (sr *searchIndexRepo) match(words []string) (relevance map[string]float64) {
+	relevance = make(map[string]float64)
+	for _, word := range words {
+		var wordScores map[string]float64
+		for _, term := range tokenizer.Tokenize(word) {
+			termScores := sr.matchTerm(term)
+			if wordScores == nil {
+				wordScores = termScores
+				continue
+			}
+			for id, score := range wordScores {
+				if _, ok := termScores[id]; ok {
+					wordScores[id] = score + termScores[id]
+				} else {
+					delete(wordScores, id)
+				}
+			}
+		}
+		for id, score := range wordScores {
+			relevance[id] += score
+		}
+	}
+	return relevance
+}
+
+// matchTerm returns the bm25 score of documents which contain the term, or contain a term which starts with it.
+func (sr *searchIndexRepo) matchTerm(term string) (scores map[string]float64) {
+	scores = make(map[string]float64)
+	prefix := indexTermPrefix + term
+	if len([]rune(term)) < prefixMatchMinLen {
+		prefix += "\x00"
+	}
+
+	var (
+		currentTerm string
+		postings    = make(map[string]int)
+	)
+	flush := func() {
+		if len(postings) == 0 {
+			return
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (float64(atomic.LoadInt64(&sr.docCount))-df+0.5)/(df+0.5))
+		weight := 1.0
+		if currentTerm != term {
+			weight = 0.5
+		}
+		for id, tf := range postings {
+			scores[id] += weight * idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + bm25K1)
+		}
+		postings = make(map[string]int)
+	}
+
+	iter := sr.db.NewIterator(util.BytesPrefix([]byte(prefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		key := strings.TrimPrefix(string(iter.Key()), indexTermPrefix)
+		idx := strings.LastIndexByte(key, 0)
+		if idx < 0 {
+			continue
+		}
+		if key[:idx] != currentTerm {
+			flush()
+			currentTerm = key[:idx]
+		}
+		postings[key[idx+1:]], _ = strconv.Atoi(string(iter.Value()))
+	}
+	flush()
+	return scores
+}
+
+// fetchResult get the latest question and answer data of the matched documents from database
+func (sr *searchIndexRepo) fetchResult(ctx context.Context, hits []*searchHit) (resp []schema.SearchResp, err error) {
+	var questionIDs, answerIDs []string
+	for _, hit := range hits {
+		if hit.ObjectType == constant.QuestionObjectType {
+			questionIDs = append(questionIDs, hit.ID)
+		} else {
+			answerIDs = append(answerIDs, hit.ID)
+		}
+	}
+
+	rows := make(map[string]map[string][]byte)
+	queries := []*builder.Builder{}
+	if len(questionIDs) > 0 {
+		queries = append(queries, builder.MySQL().Select(qFields...).From("`question`").
+			Where(builder.In("`question`.`id`", questionIDs)).
+			And(builder.In("`question`.`status`", searchableQuestionStatus)))
+	}
+	if len(answerIDs) > 0 {
+		queries = append(queries, builder.MySQL().Select(aFields...).From("`answer`").
+			LeftJoin("`question`", "`question`.id = `answer`.question_id").
+			Where(builder.In("`answer`.`id`", answerIDs)).
+			And(builder.Eq{"`answer`.`status`": entity.AnswerStatusAvailable}).
+			And(builder.In("`question`.`status`", searchableQuestionStatus)))
+	}
+	for _, query := range queries {
+		querySQL, args, err := query.ToSQL()
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		res, err := sr.data.DB.Query(append([]interface{}{querySQL}, args...)...)
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		for _, r := range res {
+			rows[string(r["id"])] = r
+		}
+	}
+
+	res := make([]map[string][]byte, 0, len(hits))
+	for _, hit := range hits {
+		if r, ok := rows[hit.ID]; ok {
+			res = append(res, r)
+		}
+	}
+	return sr.parseResult(ctx, res)
+}
+
+// rebuild index all the questions and answers in the database
+func (sr *searchIndexRepo) rebuild(ctx context.Context) {
+	log.Info("start to build the search index")
+	for page := 0; ; page++ {
+		questions := make([]*entity.Question, 0)
+		err := sr.data.DB.Asc("id").Limit(rebuildBatchSize, page*rebuildBatchSize).Find(&questions)
+		if err != nil {
+			log.Errorf("build the search index failed: %s", err)
+			return
+		}
+		for _, question := range questions {
+			if err = sr.syncQuestion(ctx, question); err != nil {
+				log.Errorf("build the search index failed: %s", err)
+				return
+			}
+		}
+		if len(questions) < rebuildBatchSize {
+			break
+		}
+	}
+	log.Infof("build the search index success, %d documents indexed", atomic.LoadInt64(&sr.docCount))
+}
+
+// searchableQuestionStatus only the available and closed questions can be searched,
+// the deleted and pending ones are removed from the index
+var searchableQuestionStatus = []int{entity.QuestionStatusAvailable, entity.QuestionStatusClosed}
+
+func isSearchableQuestion(question *entity.Question) bool {
+	for _, status := range searchableQuestionStatus {
+		if question.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+func termKey(term, id string) []byte {
+	return []byte(indexTermPrefix + term + "\x00" + id)
+}
+
+func hasAllTags(doc *indexDoc, tagIDs []string) bool {
+	for _, tagID := range tagIDs {
+		found := false
+		for _, docTagID := range doc.TagIDs {
+			if docTagID == tagID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchCount check the count same as the sql search, negative means no filter, 0 means equal to 0,
+// positive means greater or equal
+func matchCount(count, expected int) bool {
+	if expected < 0 {
+		return true
+	}
+	if expected == 0 {
+		return count == 0
+	}
+	return count >= expected
+}
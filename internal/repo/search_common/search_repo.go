@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/answerdev/answer/pkg/htmltext"
@@ -17,9 +18,11 @@ import (
 	"github.com/answerdev/answer/internal/service/unique"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
 	"github.com/answerdev/answer/pkg/converter"
+	"github.com/answerdev/answer/pkg/dir"
 	"github.com/answerdev/answer/pkg/obj"
 	"github.com/jinzhu/copier"
 	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
 	"xorm.io/builder"
 )
 
@@ -57,15 +60,32 @@ type searchRepo struct {
 	data         *data.Data
 	userCommon   *usercommon.UserCommon
 	uniqueIDRepo unique.UniqueIDRepo
+
+	fullTextOnce sync.Once
+	fullText     *fullTextSearch
 }
 
-// NewSearchRepo new repository
-func NewSearchRepo(data *data.Data, uniqueIDRepo unique.UniqueIDRepo, userCommon *usercommon.UserCommon) search_common.SearchRepo {
-	return &searchRepo{
+// NewSearchRepo new repository, the search driver is selected by config, sql search is the default driver
+func NewSearchRepo(
+	data *data.Data,
+	uniqueIDRepo unique.UniqueIDRepo,
+	userCommon *usercommon.UserCommon,
+	searchConf *data.SearchConf,
+) (search_common.SearchRepo, func(), error) {
+	sqlRepo := &searchRepo{
 		data:         data,
 		uniqueIDRepo: uniqueIDRepo,
 		userCommon:   userCommon,
 	}
+	if searchConf == nil || !searchConf.IsIndexDriver() {
+		return sqlRepo, func() {}, nil
+	}
+
+	log.Infof("try to open search index from %s", searchConf.IndexPath)
+	if err := dir.CreateDirIfNotExist(searchConf.IndexPath); err != nil {
+		return nil, nil, err
+	}
+	return newSearchIndexRepo(sqlRepo, searchConf.IndexPath)
 }
 
 // SearchContents search question and answer data
@@ -79,10 +99,14 @@ func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs
 		afs   = aFields
 		argsQ = []interface{}{}
 		argsA = []interface{}{}
+		ft    = sr.fullTextFor(words)
 	)
 
 	if order == "relevance" {
-		if len(words) > 0 {
+		if len(words) > 0 && ft != nil {
+			qfs, argsQ = ft.addRelevanceField(fullTextQuestion, words, qfs)
+			afs, argsA = ft.addRelevanceField(fullTextAnswer, words, afs)
+		} else if len(words) > 0 {
 			qfs, argsQ = addRelevanceField([]string{"title", "original_text"}, words, qfs)
 			afs, argsA = addRelevanceField([]string{"`answer`.`original_text`"}, words, afs)
 		} else {
@@ -101,6 +125,16 @@ func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs
 	argsQ = append(argsQ, entity.QuestionStatusDeleted)
 	argsA = append(argsA, entity.QuestionStatusDeleted, entity.AnswerStatusDeleted)
 
+	if len(words) > 0 && ft != nil {
+		cond, args := ft.condition(fullTextQuestion, words)
+		b.Where(builder.Expr(cond, args...))
+		argsQ = append(argsQ, args...)
+
+		cond, args = ft.condition(fullTextAnswer, words)
+		ub.Where(builder.Expr(cond, args...))
+		argsA = append(argsA, args...)
+		words = nil
+	}
 	for i, word := range words {
 		if i == 0 {
 			b.Where(builder.Like{"title", word}).
@@ -217,9 +251,12 @@ func (sr *searchRepo) SearchQuestions(ctx context.Context, words []string, tagID
 	var (
 		qfs  = qFields
 		args = []interface{}{}
+		ft   = sr.fullTextFor(words)
 	)
 	if order == "relevance" {
-		if len(words) > 0 {
+		if len(words) > 0 && ft != nil {
+			qfs, args = ft.addRelevanceField(fullTextQuestion, words, qfs)
+		} else if len(words) > 0 {
 			qfs, args = addRelevanceField([]string{"title", "original_text"}, words, qfs)
 		} else {
 			order = "newest"
@@ -231,6 +268,12 @@ func (sr *searchRepo) SearchQuestions(ctx context.Context, words []string, tagID
 	b.Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted})
 	args = append(args, entity.QuestionStatusDeleted)
 
+	if len(words) > 0 && ft != nil {
+		cond, condArgs := ft.condition(fullTextQuestion, words)
+		b.Where(builder.Expr(cond, condArgs...))
+		args = append(args, condArgs...)
+		words = nil
+	}
 	for i, word := range words {
 		if i == 0 {
 			b.Where(builder.Like{"title", word}).
@@ -330,9 +373,12 @@ func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs
 	var (
 		afs  = aFields
 		args = []interface{}{}
+		ft   = sr.fullTextFor(words)
 	)
 	if order == "relevance" {
-		if len(words) > 0 {
+		if len(words) > 0 && ft != nil {
+			afs, args = ft.addRelevanceField(fullTextAnswer, words, afs)
+		} else if len(words) > 0 {
 			afs, args = addRelevanceField([]string{"`answer`.`original_text`"}, words, afs)
 		} else {
 			order = "newest"
@@ -346,6 +392,12 @@ func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs
 		And(builder.Lt{"`answer`.`status`": entity.AnswerStatusDeleted})
 	args = append(args, entity.QuestionStatusDeleted, entity.AnswerStatusDeleted)
 
+	if len(words) > 0 && ft != nil {
+		cond, condArgs := ft.condition(fullTextAnswer, words)
+		b.Where(builder.Expr(cond, condArgs...))
+		args = append(args, condArgs...)
+		words = nil
+	}
 	for i, word := range words {
 		if i == 0 {
 			b.Where(builder.Like{"`answer`.original_text", word})
@@ -418,6 +470,11 @@ func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs
 	return
 }
 
+// SyncObject the sql search reads the question and answer tables directly, there is nothing to sync
+func (sr *searchRepo) SyncObject(ctx context.Context, objectID string) (err error) {
+	return nil
+}
+
 func (sr *searchRepo) parseOrder(ctx context.Context, order string) (res string) {
 	switch order {
 	case "newest":
@@ -0,0 +1,89 @@
+package search_common
+
+import (
+	"container/heap"
+)
+
+// searchHit the fields of matched document used to sort the search result
+type searchHit struct {
+	ID             string
+	ObjectType     string
+	Relevance      float64
+	VoteCount      int
+	CreatedAt      int64
+	PostUpdateTime int64
+}
+
+// searchTopK keep the k hits ranked first by the order. The hits are kept in a heap whose root is
+// the last ranked one, so it is replaced when a hit ranked before it is added.
+type searchTopK struct {
+	order string
+	k     int
+	hits  []*searchHit
+}
+
+func newSearchTopK(order string, k int) *searchTopK {
+	return &searchTopK{order: order, k: k}
+}
+
+// add the hit if it is ranked in the first k
+func (t *searchTopK) add(hit *searchHit) {
+	if t.k <= 0 {
+		return
+	}
+	if len(t.hits) < t.k {
+		heap.Push(t, hit)
+		return
+	}
+	if rankBefore(t.order, hit, t.hits[0]) {
+		t.hits[0] = hit
+		heap.Fix(t, 0)
+	}
+}
+
+// sorted returns the kept hits sorted by the order, the top k can not be used after that
+func (t *searchTopK) sorted() []*searchHit {
+	hits := make([]*searchHit, len(t.hits))
+	for i := len(hits) - 1; i >= 0; i-- {
+		hits[i] = heap.Pop(t).(*searchHit)
+	}
+	return hits
+}
+
+func (t *searchTopK) Len() int { return len(t.hits) }
+
+// Less the last ranked hit is the root of heap
+func (t *searchTopK) Less(i, j int) bool { return rankBefore(t.order, t.hits[j], t.hits[i]) }
+
+func (t *searchTopK) Swap(i, j int) { t.hits[i], t.hits[j] = t.hits[j], t.hits[i] }
+
+func (t *searchTopK) Push(x interface{}) { t.hits = append(t.hits, x.(*searchHit)) }
+
+func (t *searchTopK) Pop() interface{} {
+	hit := t.hits[len(t.hits)-1]
+	t.hits = t.hits[:len(t.hits)-1]
+	return hit
+}
+
+// rankBefore whether the hit a is ranked before b by the order, the newer one is ranked first
+// if they are equal by the order
+func rankBefore(order string, a, b *searchHit) bool {
+	switch order {
+	case "active":
+		if a.PostUpdateTime != b.PostUpdateTime {
+			return a.PostUpdateTime > b.PostUpdateTime
+		}
+	case "score":
+		if a.VoteCount != b.VoteCount {
+			return a.VoteCount > b.VoteCount
+		}
+	case "relevance":
+		if a.Relevance != b.Relevance {
+			return a.Relevance > b.Relevance
+		}
+	}
+	if a.CreatedAt != b.CreatedAt {
+		return a.CreatedAt > b.CreatedAt
+	}
+	return a.ID > b.ID
+}
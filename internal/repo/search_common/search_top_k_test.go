@@ -0,0 +1,30 @@
+package search_common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTopK(t *testing.T) {
+	top := newSearchTopK("score", 3)
+	for _, votes := range []int{5, 1, 9, 3, 7, 9, 2} {
+		top.add(&searchHit{ID: fmt.Sprintf("%d-%d", votes, top.Len()), VoteCount: votes, CreatedAt: int64(votes)})
+	}
+	hits := top.sorted()
+	assert.Len(t, hits, 3)
+	assert.Equal(t, []int{9, 9, 7}, []int{hits[0].VoteCount, hits[1].VoteCount, hits[2].VoteCount})
+
+	// the newer one is ranked first if they are equal by the order
+	top = newSearchTopK("relevance", 2)
+	top.add(&searchHit{ID: "old", Relevance: 1, CreatedAt: 1})
+	top.add(&searchHit{ID: "new", Relevance: 1, CreatedAt: 2})
+	top.add(&searchHit{ID: "best", Relevance: 2, CreatedAt: 0})
+	hits = top.sorted()
+	assert.Equal(t, []string{"best", "new"}, []string{hits[0].ID, hits[1].ID})
+
+	top = newSearchTopK("newest", 0)
+	top.add(&searchHit{ID: "1"})
+	assert.Empty(t, top.sorted())
+}
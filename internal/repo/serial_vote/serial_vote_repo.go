@@ -0,0 +1,184 @@
+package serial_vote
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/activity_common"
+	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/answerdev/answer/internal/service/serial_vote"
+	"github.com/answerdev/answer/pkg/obj"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// votedObjectTypes the object types whose upvotes give reputation to the author
+var votedObjectTypes = []string{constant.QuestionObjectType, constant.AnswerObjectType}
+
+type serialVoteRepo struct {
+	data         *data.Data
+	activityRepo activity_common.ActivityRepo
+	userRankRepo rank.UserRankRepo
+}
+
+// NewSerialVoteRepo new repository
+func NewSerialVoteRepo(
+	data *data.Data,
+	activityRepo activity_common.ActivityRepo,
+	userRankRepo rank.UserRankRepo,
+) serial_vote.SerialVoteRepo {
+	return &serialVoteRepo{
+		data:         data,
+		activityRepo: activityRepo,
+		userRankRepo: userRankRepo,
+	}
+}
+
+// GetVotePairs get the number of upvotes every user received from every other user since the time
+func (sr *serialVoteRepo) GetVotePairs(ctx context.Context, since time.Time) (
+	pairs []*entity.ActivityUserVotePairStat, err error) {
+	votedUpTypes, err := sr.getActivityTypes(ctx, "voted_up")
+	if err != nil {
+		return nil, err
+	}
+	pairs = make([]*entity.ActivityUserVotePairStat, 0)
+	session := sr.data.DB.Select("user_id, trigger_user_id, COUNT(*) AS vote_count").Table("activity")
+	session.Where("cancelled = 0 AND trigger_user_id <> 0")
+	session.In("activity_type", votedUpTypes)
+	session.Where("updated_at >= ?", since)
+	session.GroupBy("user_id, trigger_user_id")
+	err = session.Find(&pairs)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ReverseVotes cancel the upvotes from the voter to the receiver since the time,
+// the reputation earned by them and the vote count of the objects are rolled back
+func (sr *serialVoteRepo) ReverseVotes(ctx context.Context, voterID, receiverID string, since time.Time) (
+	objectIDs []string, reversedRank int, err error) {
+	votedUpTypes, err := sr.getActivityTypes(ctx, "voted_up")
+	if err != nil {
+		return nil, 0, err
+	}
+	voteUpTypes, err := sr.getActivityTypes(ctx, "vote_up")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	objectIDs = make([]string, 0)
+	_, err = sr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		votedActs := make([]*entity.Activity, 0)
+		err = session.Where(builder.Eq{"user_id": receiverID}).
+			And(builder.Eq{"trigger_user_id": voterID}).
+			And(builder.Eq{"cancelled": entity.ActivityAvailable}).
+			And(builder.In("activity_type", votedUpTypes)).
+			And(builder.Gte{"updated_at": since}).
+			Asc("id").Find(&votedActs)
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+
+		for _, votedAct := range votedActs {
+			cancelled, err := sr.cancelActivity(ctx, session, votedAct)
+			if err != nil {
+				return nil, err
+			}
+			// the vote has been cancelled by the voter or another reversal in the meantime
+			if !cancelled {
+				continue
+			}
+			reversedRank += votedAct.Rank
+
+			// the activity of the voter on the same object
+			voteAct := &entity.Activity{}
+			exist, err := session.Where(builder.Eq{"object_id": votedAct.ObjectID}).
+				And(builder.Eq{"user_id": voterID}).
+				And(builder.Eq{"cancelled": entity.ActivityAvailable}).
+				And(builder.In("activity_type", voteUpTypes)).
+				Get(voteAct)
+			if err != nil {
+				return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+			}
+			if exist {
+				if _, err = sr.cancelActivity(ctx, session, voteAct); err != nil {
+					return nil, err
+				}
+			}
+
+			if err = sr.decrVoteCount(session, votedAct.ObjectID); err != nil {
+				return nil, err
+			}
+			objectIDs = append(objectIDs, votedAct.ObjectID)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return objectIDs, reversedRank, nil
+}
+
+// AddModerationLog add moderation log
+func (sr *serialVoteRepo) AddModerationLog(ctx context.Context, moderationLog *entity.ModerationLog) (err error) {
+	_, err = sr.data.DB.Insert(moderationLog)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// cancelActivity cancel the activity and roll back the reputation of it,
+// nothing is rolled back if the activity is already cancelled
+func (sr *serialVoteRepo) cancelActivity(ctx context.Context, session *xorm.Session, act *entity.Activity) (
+	cancelled bool, err error) {
+	affected, err := session.ID(act.ID).Where("cancelled = ?", entity.ActivityAvailable).
+		Cols("cancelled", "cancelled_at").
+		Update(&entity.Activity{Cancelled: entity.ActivityCancelled, CancelledAt: time.Now()})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if affected != 1 {
+		return false, nil
+	}
+	if act.Rank == 0 {
+		return true, nil
+	}
+	_, err = sr.userRankRepo.TriggerUserRank(ctx, session, act.UserID, -act.Rank, act.ActivityType)
+	return err == nil, err
+}
+
+func (sr *serialVoteRepo) decrVoteCount(session *xorm.Session, objectID string) (err error) {
+	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return err
+	}
+	switch objectType {
+	case constant.QuestionObjectType:
+		_, err = session.ID(objectID).Decr("vote_count").Update(&entity.Question{})
+	case constant.AnswerObjectType:
+		_, err = session.ID(objectID).Decr("vote_count").Update(&entity.Answer{})
+	}
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *serialVoteRepo) getActivityTypes(ctx context.Context, action string) (activityTypes []int, err error) {
+	activityTypes = make([]int, 0, len(votedObjectTypes))
+	for _, objectType := range votedObjectTypes {
+		activityType, err := sr.activityRepo.GetActivityTypeByObjKey(ctx, objectType, action)
+		if err != nil {
+			return nil, err
+		}
+		activityTypes = append(activityTypes, activityType)
+	}
+	return activityTypes, nil
+}
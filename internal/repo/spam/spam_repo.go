@@ -0,0 +1,112 @@
+package spam
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/pager"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/spam"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+const spamFingerprintCacheKey = "answer:spam:fingerprint:"
+
+// spamRepo spam repository
+type spamRepo struct {
+	data *data.Data
+}
+
+// NewSpamRepo new repository
+func NewSpamRepo(data *data.Data) spam.SpamRepo {
+	return &spamRepo{
+		data: data,
+	}
+}
+
+// AddSpamLog add spam log
+func (sr *spamRepo) AddSpamLog(ctx context.Context, spamLog *entity.SpamLog) (err error) {
+	_, err = sr.data.DB.Insert(spamLog)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetSpamLogPage get spam log page, the latest first
+func (sr *spamRepo) GetSpamLogPage(ctx context.Context, page, pageSize int, cond *entity.SpamLog) (
+	spamLogs []*entity.SpamLog, total int64, err error) {
+	spamLogs = make([]*entity.SpamLog, 0)
+	session := sr.data.DB.NewSession()
+	session.Desc("id")
+	total, err = pager.Help(page, pageSize, &spamLogs, cond, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// IncreaseFingerprintCount increase the times the content of the fingerprint is posted in the window,
+// the count is increased atomically so the concurrent posts are all counted
+func (sr *spamRepo) IncreaseFingerprintCount(ctx context.Context, fingerprint string, window time.Duration) (
+	count int64, err error) {
+	count, err = sr.data.Counter.Increase(ctx, spamFingerprintCacheKey+fingerprint, window)
+	if err != nil {
+		return 0, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	return count, nil
+}
+
+// GetSpamTokens get the trained tokens in the token list
+func (sr *spamRepo) GetSpamTokens(ctx context.Context, tokens []string) (spamTokens []*entity.SpamToken, err error) {
+	spamTokens = make([]*entity.SpamToken, 0)
+	if len(tokens) == 0 {
+		return spamTokens, nil
+	}
+	err = sr.data.DB.In("token", tokens).Find(&spamTokens)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// TrainSpamTokens increase the spam or ham count of the tokens, the token is added if it is not trained.
+// The tokens are upserted, so the same token trained concurrently does not fail on the unique index.
+func (sr *spamRepo) TrainSpamTokens(ctx context.Context, tokens []string, isSpam bool) (err error) {
+	column := "ham_count"
+	spamCount, hamCount := 0, 1
+	if isSpam {
+		column = "spam_count"
+		spamCount, hamCount = 1, 0
+	}
+	upsertSQL := sr.upsertSpamTokenSQL(column)
+	_, err = sr.data.DB.Transaction(func(session *xorm.Session) (interface{}, error) {
+		now := time.Now()
+		for _, token := range tokens {
+			if _, err := session.Exec(upsertSQL, token, spamCount, hamCount, now, now); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// upsertSpamTokenSQL the sql to insert the token or increase the count column of the existing one
+func (sr *spamRepo) upsertSpamTokenSQL(column string) string {
+	insertSQL := "INSERT INTO spam_token (token, spam_count, ham_count, created_at, updated_at) VALUES (?, ?, ?, ?, ?)"
+	if sr.data.DB.Dialect().URI().DBType == schemas.MYSQL {
+		return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s = %s + 1, updated_at = VALUES(updated_at)",
+			insertSQL, column, column)
+	}
+	return fmt.Sprintf("%s ON CONFLICT (token) DO UPDATE SET %s = spam_token.%s + 1, updated_at = excluded.updated_at",
+		insertSQL, column, column)
+}
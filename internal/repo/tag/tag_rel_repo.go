@@ -62,6 +62,15 @@ func (tr *tagRelRepo) GetObjectTagRelWithoutStatus(ctx context.Context, objectID
 	return
 }
 
+// HoldTagRelByIDs update tag status to pending
+func (tr *tagRelRepo) HoldTagRelByIDs(ctx context.Context, ids []int64) (err error) {
+	_, err = tr.data.DB.In("id", ids).Update(&entity.TagRel{Status: entity.TagRelStatusPending})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
 // EnableTagRelByIDs update tag status to available
 func (tr *tagRelRepo) EnableTagRelByIDs(ctx context.Context, ids []int64) (err error) {
 	_, err = tr.data.DB.In("id", ids).Update(&entity.TagRel{Status: entity.TagRelStatusAvailable})
@@ -83,6 +92,19 @@ func (tr *tagRelRepo) GetObjectTagRelList(ctx context.Context, objectID string)
 	return
 }
 
+// GetObjectPendingTagRelList get the pending tag relation list of object
+func (tr *tagRelRepo) GetObjectPendingTagRelList(ctx context.Context, objectID string) (
+	tagListList []*entity.TagRel, err error) {
+	tagListList = make([]*entity.TagRel, 0)
+	session := tr.data.DB.Where("object_id = ?", objectID)
+	session.Where("status = ?", entity.TagRelStatusPending)
+	err = session.Find(&tagListList)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
 // BatchGetObjectTagRelList get object tag relation list all
 func (tr *tagRelRepo) BatchGetObjectTagRelList(ctx context.Context, objectIds []string) (tagListList []*entity.TagRel, err error) {
 	tagListList = make([]*entity.TagRel, 0)
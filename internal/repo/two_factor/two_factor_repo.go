@@ -0,0 +1,132 @@
+package two_factor
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/two_factor"
+	"github.com/segmentfault/pacman/errors"
+)
+
+type twoFactorRepo struct {
+	data *data.Data
+}
+
+// NewTwoFactorRepo new repository
+func NewTwoFactorRepo(data *data.Data) two_factor.TwoFactorRepo {
+	return &twoFactorRepo{
+		data: data,
+	}
+}
+
+// GetByUserID get two-factor info by user id
+func (tr *twoFactorRepo) GetByUserID(ctx context.Context, userID string) (
+	info *entity.UserTwoFactor, exist bool, err error) {
+	info = &entity.UserTwoFactor{}
+	exist, err = tr.data.DB.Where("user_id = ?", userID).Get(info)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SavePending save the pending secret, the old pending secret is replaced
+func (tr *twoFactorRepo) SavePending(ctx context.Context, userID, secret string) (err error) {
+	_, err = tr.data.DB.Where("user_id = ? AND enabled = ?", userID, false).Delete(&entity.UserTwoFactor{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	_, err = tr.data.DB.Insert(&entity.UserTwoFactor{UserID: userID, Secret: secret})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// Enable enable the pending secret with the recovery codes
+func (tr *twoFactorRepo) Enable(ctx context.Context, userID, recoveryCodes string) (err error) {
+	_, err = tr.data.DB.Where("user_id = ?", userID).Cols("enabled", "recovery_codes").
+		Update(&entity.UserTwoFactor{Enabled: true, RecoveryCodes: recoveryCodes})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateLastUsedStep update the last used step if it is after the saved one
+func (tr *twoFactorRepo) UpdateLastUsedStep(ctx context.Context, userID string, step int64) (updated bool, err error) {
+	affected, err := tr.data.DB.Where("user_id = ? AND last_used_step < ?", userID, step).Cols("last_used_step").
+		Update(&entity.UserTwoFactor{LastUsedStep: step})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// UpdateRecoveryCodes update the recovery codes if they are not changed by others
+func (tr *twoFactorRepo) UpdateRecoveryCodes(ctx context.Context, userID, oldRecoveryCodes, recoveryCodes string) (
+	updated bool, err error) {
+	affected, err := tr.data.DB.Where("user_id = ? AND recovery_codes = ?", userID, oldRecoveryCodes).
+		Cols("recovery_codes").Update(&entity.UserTwoFactor{RecoveryCodes: recoveryCodes})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// RemoveByUserID remove the two-factor info of user
+func (tr *twoFactorRepo) RemoveByUserID(ctx context.Context, userID string) (err error) {
+	_, err = tr.data.DB.Where("user_id = ?", userID).Delete(&entity.UserTwoFactor{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SetLoginCache set the login waiting for two-factor verification
+func (tr *twoFactorRepo) SetLoginCache(ctx context.Context, token string, cache *two_factor.LoginCache) (err error) {
+	content, _ := json.Marshal(cache)
+	err = tr.data.Cache.SetString(ctx, constant.TwoFactorLoginCacheKey+token, string(content),
+		constant.TwoFactorLoginCacheTime)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetLoginCache get the login waiting for two-factor verification
+func (tr *twoFactorRepo) GetLoginCache(ctx context.Context, token string) (
+	cache *two_factor.LoginCache, exist bool, err error) {
+	content, err := tr.data.Cache.GetString(ctx, constant.TwoFactorLoginCacheKey+token)
+	if err != nil || len(content) == 0 {
+		return nil, false, nil
+	}
+	cache = &two_factor.LoginCache{}
+	if err = json.Unmarshal([]byte(content), cache); err != nil {
+		return nil, false, nil
+	}
+	return cache, true, nil
+}
+
+// IncreaseLoginAttempts increase the wrong codes of the login atomically, it returns the attempts after increased
+func (tr *twoFactorRepo) IncreaseLoginAttempts(ctx context.Context, token string) (attempts int64, err error) {
+	attempts, err = tr.data.Counter.Increase(ctx, constant.TwoFactorLoginAttemptsKey+token,
+		constant.TwoFactorLoginCacheTime)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveLoginCache remove the login waiting for two-factor verification
+func (tr *twoFactorRepo) RemoveLoginCache(ctx context.Context, token string) (err error) {
+	err = tr.data.Cache.Del(ctx, constant.TwoFactorLoginCacheKey+token)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,105 @@
+package user_external_login
+
+import (
+	"context"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/user_external_login"
+	"github.com/segmentfault/pacman/errors"
+)
+
+type userExternalLoginRepo struct {
+	data *data.Data
+}
+
+// NewUserExternalLoginRepo new repository
+func NewUserExternalLoginRepo(data *data.Data) user_external_login.UserExternalLoginRepo {
+	return &userExternalLoginRepo{
+		data: data,
+	}
+}
+
+// AddUserExternalLogin add external login information
+func (ur *userExternalLoginRepo) AddUserExternalLogin(ctx context.Context, user *entity.ExternalLoginUserInfo) (err error) {
+	_, err = ur.data.DB.Insert(user)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateInfo update user info
+func (ur *userExternalLoginRepo) UpdateInfo(ctx context.Context, userInfo *entity.ExternalLoginUserInfo) (err error) {
+	_, err = ur.data.DB.ID(userInfo.ID).Cols("name", "email", "meta_info").Update(userInfo)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateMappedRole update the role granted by the group mapping
+func (ur *userExternalLoginRepo) UpdateMappedRole(ctx context.Context, id, mappedRoleID int) (err error) {
+	_, err = ur.data.DB.ID(id).Cols("mapped_role_id").Update(&entity.ExternalLoginUserInfo{MappedRoleID: mappedRoleID})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetByExternalID get by external ID
+func (ur *userExternalLoginRepo) GetByExternalID(ctx context.Context, provider, externalID string) (
+	userInfo *entity.ExternalLoginUserInfo, exist bool, err error) {
+	userInfo = &entity.ExternalLoginUserInfo{}
+	exist, err = ur.data.DB.Where("provider = ? AND external_id = ?", provider, externalID).Get(userInfo)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SetCacheState set the state of login with the provider
+func (ur *userExternalLoginRepo) SetCacheState(ctx context.Context, state, provider string) (err error) {
+	err = ur.data.Cache.SetString(ctx, constant.ExternalLoginStateCacheKey+state, provider,
+		constant.ExternalLoginStateCacheTime)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetCacheState get the provider of state and remove the state, the expired state is treated as invalid
+func (ur *userExternalLoginRepo) GetCacheState(ctx context.Context, state string) (provider string, err error) {
+	provider, err = ur.data.Cache.GetString(ctx, constant.ExternalLoginStateCacheKey+state)
+	if err != nil || len(provider) == 0 {
+		return "", errors.BadRequest(reason.ExternalLoginFailed).WithMsg("invalid state")
+	}
+	if err = ur.data.Cache.Del(ctx, constant.ExternalLoginStateCacheKey+state); err != nil {
+		return "", errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return provider, nil
+}
+
+// SetCacheLoginCode set the access token of the one-time login code
+func (ur *userExternalLoginRepo) SetCacheLoginCode(ctx context.Context, code, accessToken string) (err error) {
+	err = ur.data.Cache.SetString(ctx, constant.ExternalLoginCodeCacheKey+code, accessToken,
+		constant.ExternalLoginCodeCacheTime)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetCacheLoginCode get the access token of the login code and remove the code, the code can only be used once
+func (ur *userExternalLoginRepo) GetCacheLoginCode(ctx context.Context, code string) (accessToken string, err error) {
+	accessToken, err = ur.data.Cache.GetString(ctx, constant.ExternalLoginCodeCacheKey+code)
+	if err != nil || len(accessToken) == 0 {
+		return "", errors.BadRequest(reason.ExternalLoginFailed).WithMsg("invalid code")
+	}
+	if err = ur.data.Cache.Del(ctx, constant.ExternalLoginCodeCacheKey+code); err != nil {
+		return "", errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return accessToken, nil
+}
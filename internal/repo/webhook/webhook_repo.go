@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/base/pager"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/webhook"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+type webhookRepo struct {
+	data *data.Data
+}
+
+// NewWebhookRepo new repository
+func NewWebhookRepo(data *data.Data) webhook.WebhookRepo {
+	return &webhookRepo{
+		data: data,
+	}
+}
+
+// AddWebhook add webhook
+func (wr *webhookRepo) AddWebhook(ctx context.Context, webhook *entity.Webhook) (err error) {
+	_, err = wr.data.DB.Insert(webhook)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateWebhook update webhook
+func (wr *webhookRepo) UpdateWebhook(ctx context.Context, webhook *entity.Webhook) (err error) {
+	_, err = wr.data.DB.ID(webhook.ID).AllCols().Update(webhook)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveWebhook remove webhook and its deliveries
+func (wr *webhookRepo) RemoveWebhook(ctx context.Context, id string) (err error) {
+	_, err = wr.data.DB.Transaction(func(session *xorm.Session) (interface{}, error) {
+		if _, err := session.Where("webhook_id = ?", id).Delete(&entity.WebhookDelivery{}); err != nil {
+			return nil, err
+		}
+		return session.ID(id).Delete(&entity.Webhook{})
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetWebhook get webhook by id
+func (wr *webhookRepo) GetWebhook(ctx context.Context, id string) (
+	webhook *entity.Webhook, exist bool, err error) {
+	webhook = &entity.Webhook{}
+	exist, err = wr.data.DB.ID(id).Get(webhook)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetWebhookList get all webhooks
+func (wr *webhookRepo) GetWebhookList(ctx context.Context) (webhooks []*entity.Webhook, err error) {
+	webhooks = make([]*entity.Webhook, 0)
+	err = wr.data.DB.Asc("id").Find(&webhooks)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetEnabledWebhooks get all enabled webhooks
+func (wr *webhookRepo) GetEnabledWebhooks(ctx context.Context) (webhooks []*entity.Webhook, err error) {
+	webhooks = make([]*entity.Webhook, 0)
+	err = wr.data.DB.Where("enabled = ?", true).Asc("id").Find(&webhooks)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AddDelivery add webhook delivery
+func (wr *webhookRepo) AddDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (err error) {
+	_, err = wr.data.DB.Insert(delivery)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateDelivery update the result of webhook delivery
+func (wr *webhookRepo) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (err error) {
+	_, err = wr.data.DB.ID(delivery.ID).
+		Cols("status", "attempts", "response_status", "response_body", "error", "duration", "delivered_at").
+		Update(delivery)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetDelivery get webhook delivery by id
+func (wr *webhookRepo) GetDelivery(ctx context.Context, id string) (
+	delivery *entity.WebhookDelivery, exist bool, err error) {
+	delivery = &entity.WebhookDelivery{}
+	exist, err = wr.data.DB.ID(id).Get(delivery)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetDeliveryPage get the deliveries of webhook, the newest first
+func (wr *webhookRepo) GetDeliveryPage(ctx context.Context, webhookID string, page, pageSize int) (
+	deliveries []*entity.WebhookDelivery, total int64, err error) {
+	deliveries = make([]*entity.WebhookDelivery, 0)
+	session := wr.data.DB.Where("webhook_id = ?", webhookID).Desc("id")
+	total, err = pager.Help(page, pageSize, &deliveries, &entity.WebhookDelivery{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -7,30 +7,42 @@ import (
 )
 
 type AnswerAPIRouter struct {
-	langController         *controller.LangController
-	userController         *controller.UserController
-	commentController      *controller.CommentController
-	reportController       *controller.ReportController
-	voteController         *controller.VoteController
-	tagController          *controller.TagController
-	followController       *controller.FollowController
-	collectionController   *controller.CollectionController
-	questionController     *controller.QuestionController
-	answerController       *controller.AnswerController
-	searchController       *controller.SearchController
-	revisionController     *controller.RevisionController
-	rankController         *controller.RankController
-	adminReportController  *controller_admin.ReportController
-	adminUserController    *controller_admin.UserAdminController
-	reasonController       *controller.ReasonController
-	themeController        *controller_admin.ThemeController
-	siteInfoController     *controller_admin.SiteInfoController
-	siteinfoController     *controller.SiteinfoController
-	notificationController *controller.NotificationController
-	dashboardController    *controller.DashboardController
-	uploadController       *controller.UploadController
-	activityController     *controller.ActivityController
-	roleController         *controller_admin.RoleController
+	langController              *controller.LangController
+	userController              *controller.UserController
+	commentController           *controller.CommentController
+	reportController            *controller.ReportController
+	voteController              *controller.VoteController
+	tagController               *controller.TagController
+	followController            *controller.FollowController
+	collectionController        *controller.CollectionController
+	questionController          *controller.QuestionController
+	answerController            *controller.AnswerController
+	searchController            *controller.SearchController
+	revisionController          *controller.RevisionController
+	rankController              *controller.RankController
+	adminReportController       *controller_admin.ReportController
+	adminUserController         *controller_admin.UserAdminController
+	reasonController            *controller.ReasonController
+	themeController             *controller_admin.ThemeController
+	siteInfoController          *controller_admin.SiteInfoController
+	siteinfoController          *controller.SiteinfoController
+	notificationController      *controller.NotificationController
+	dashboardController         *controller.DashboardController
+	uploadController            *controller.UploadController
+	activityController          *controller.ActivityController
+	roleController              *controller_admin.RoleController
+	connectorController         *controller.ConnectorController
+	twoFactorController         *controller.TwoFactorController
+	accessTokenController       *controller.AccessTokenController
+	webhookController           *controller_admin.WebhookController
+	emailDigestController       *controller.EmailDigestController
+	badgeController             *controller_admin.BadgeController
+	bountyController            *controller.BountyController
+	auditLogController          *controller_admin.AuditLogController
+	pendingPostController       *controller.PendingPostController
+	spamController              *controller_admin.SpamController
+	questionDuplicateController *controller.QuestionDuplicateController
+	postLockController          *controller.PostLockController
 }
 
 func NewAnswerAPIRouter(
@@ -58,32 +70,56 @@ func NewAnswerAPIRouter(
 	uploadController *controller.UploadController,
 	activityController *controller.ActivityController,
 	roleController *controller_admin.RoleController,
+	connectorController *controller.ConnectorController,
+	twoFactorController *controller.TwoFactorController,
+	accessTokenController *controller.AccessTokenController,
+	webhookController *controller_admin.WebhookController,
+	emailDigestController *controller.EmailDigestController,
+	badgeController *controller_admin.BadgeController,
+	bountyController *controller.BountyController,
+	auditLogController *controller_admin.AuditLogController,
+	pendingPostController *controller.PendingPostController,
+	spamController *controller_admin.SpamController,
+	questionDuplicateController *controller.QuestionDuplicateController,
+	postLockController *controller.PostLockController,
 ) *AnswerAPIRouter {
 	return &AnswerAPIRouter{
-		langController:         langController,
-		userController:         userController,
-		commentController:      commentController,
-		reportController:       reportController,
-		voteController:         voteController,
-		tagController:          tagController,
-		followController:       followController,
-		collectionController:   collectionController,
-		questionController:     questionController,
-		answerController:       answerController,
-		searchController:       searchController,
-		revisionController:     revisionController,
-		rankController:         rankController,
-		adminReportController:  adminReportController,
-		adminUserController:    adminUserController,
-		reasonController:       reasonController,
-		themeController:        themeController,
-		siteInfoController:     siteInfoController,
-		notificationController: notificationController,
-		siteinfoController:     siteinfoController,
-		dashboardController:    dashboardController,
-		uploadController:       uploadController,
-		activityController:     activityController,
-		roleController:         roleController,
+		langController:              langController,
+		userController:              userController,
+		commentController:           commentController,
+		reportController:            reportController,
+		voteController:              voteController,
+		tagController:               tagController,
+		followController:            followController,
+		collectionController:        collectionController,
+		questionController:          questionController,
+		answerController:            answerController,
+		searchController:            searchController,
+		revisionController:          revisionController,
+		rankController:              rankController,
+		adminReportController:       adminReportController,
+		adminUserController:         adminUserController,
+		reasonController:            reasonController,
+		themeController:             themeController,
+		siteInfoController:          siteInfoController,
+		notificationController:      notificationController,
+		siteinfoController:          siteinfoController,
+		dashboardController:         dashboardController,
+		uploadController:            uploadController,
+		activityController:          activityController,
+		roleController:              roleController,
+		connectorController:         connectorController,
+		twoFactorController:         twoFactorController,
+		accessTokenController:       accessTokenController,
+		webhookController:           webhookController,
+		emailDigestController:       emailDigestController,
+		badgeController:             badgeController,
+		bountyController:            bountyController,
+		auditLogController:          auditLogController,
+		pendingPostController:       pendingPostController,
+		spamController:              spamController,
+		questionDuplicateController: questionDuplicateController,
+		postLockController:          postLockController,
 	}
 }
 
@@ -98,6 +134,8 @@ func (a *AnswerAPIRouter) RegisterMustUnAuthAnswerAPIRouter(r *gin.RouterGroup)
 
 	// user
 	r.POST("/user/login/email", a.userController.UserEmailLogin)
+	r.POST("/user/login/2fa", a.twoFactorController.TwoFactorLogin)
+	r.POST("/user/login/2fa/enroll", a.twoFactorController.TwoFactorLoginEnroll)
 	r.POST("/user/register/email", a.userController.UserRegisterByEmail)
 	r.GET("/user/register/captcha", a.userController.UserRegisterCaptcha)
 	r.POST("/user/email/verification", a.userController.UserVerifyEmail)
@@ -107,6 +145,15 @@ func (a *AnswerAPIRouter) RegisterMustUnAuthAnswerAPIRouter(r *gin.RouterGroup)
 	r.POST("/user/password/replacement", a.userController.UseRePassWord)
 	r.GET("/user/info", a.userController.GetUserInfoByUserID)
 	r.PUT("/user/email/notification", a.userController.UserUnsubscribeEmailNotification)
+
+	// external login connector
+	r.GET("/connector/info", a.connectorController.ConnectorInfo)
+	r.GET("/connector/login/:name", a.connectorController.ConnectorLogin)
+	r.GET("/connector/redirect/:name", a.connectorController.ConnectorRedirect)
+	r.POST("/connector/token", a.connectorController.ConnectorToken)
+	r.GET("/saml/metadata", a.connectorController.SAMLMetadata)
+	r.GET("/saml/login", a.connectorController.SAMLLogin)
+	r.POST("/saml/acs", a.connectorController.SAMLACS)
 }
 
 func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
@@ -126,6 +173,7 @@ func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
 	r.GET("/question/info", a.questionController.GetQuestion)
 	r.GET("/question/page", a.questionController.QuestionPage)
 	r.GET("/question/similar/tag", a.questionController.SimilarQuestion)
+	r.GET("/question/bounty", a.bountyController.GetQuestionBounty)
 	r.GET("/personal/qa/top", a.questionController.UserTop)
 	r.GET("/personal/question/page", a.questionController.UserList)
 
@@ -156,6 +204,10 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/revisions/audit", a.revisionController.RevisionAudit)
 	r.GET("/revisions/edit/check", a.revisionController.CheckCanUpdateRevision)
 
+	// pending posts
+	r.GET("/pending-posts", a.pendingPostController.GetPendingPostPage)
+	r.PUT("/pending-posts/review", a.pendingPostController.ReviewPendingPost)
+
 	// comment
 	r.POST("/comment", a.commentController.AddComment)
 	r.DELETE("/comment", a.commentController.RemoveComment)
@@ -164,6 +216,9 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	// report
 	r.POST("/report", a.reportController.AddReport)
 
+	// notification
+	r.GET("/notification/stream", a.notificationController.GetStream)
+
 	// vote
 	r.POST("/vote/up", a.voteController.VoteUp)
 	r.POST("/vote/down", a.voteController.VoteDown)
@@ -188,7 +243,14 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.DELETE("/question", a.questionController.RemoveQuestion)
 	r.PUT("/question/status", a.questionController.CloseQuestion)
 	r.PUT("/question/reopen", a.questionController.ReopenQuestion)
+	r.PUT("/question/merge", a.questionDuplicateController.MergeQuestion)
+	r.PUT("/question/protect", a.postLockController.ProtectQuestion)
+	r.PUT("/question/unprotect", a.postLockController.UnprotectQuestion)
+	r.PUT("/post/lock", a.postLockController.LockPost)
+	r.PUT("/post/unlock", a.postLockController.UnlockPost)
 	r.GET("/question/similar", a.questionController.SearchByTitleLike)
+	r.POST("/question/bounty", a.bountyController.StartBounty)
+	r.POST("/question/bounty/award", a.bountyController.AwardBounty)
 
 	// answer
 	r.POST("/answer", a.answerController.Add)
@@ -201,6 +263,20 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/user/info", a.userController.UserUpdateInfo)
 	r.PUT("/user/interface", a.userController.UserUpdateInterface)
 	r.POST("/user/notice/set", a.userController.UserNoticeSet)
+	r.GET("/user/notice/config", a.emailDigestController.GetUserNoticeConfig)
+	r.PUT("/user/notice/config", a.emailDigestController.UpdateUserNoticeConfig)
+
+	// two-factor authentication
+	r.GET("/user/2fa", a.twoFactorController.GetTwoFactorStatus)
+	r.POST("/user/2fa/enroll", a.twoFactorController.TwoFactorEnroll)
+	r.POST("/user/2fa/enable", a.twoFactorController.TwoFactorEnable)
+	r.DELETE("/user/2fa", a.twoFactorController.TwoFactorDisable)
+	r.POST("/user/2fa/recovery-codes", a.twoFactorController.RegenerateRecoveryCodes)
+
+	// personal access token
+	r.GET("/user/access-tokens", a.accessTokenController.GetAccessTokenList)
+	r.POST("/user/access-token", a.accessTokenController.AddAccessToken)
+	r.DELETE("/user/access-token", a.accessTokenController.RemoveAccessToken)
 
 	// vote
 	r.GET("/personal/vote/page", a.voteController.UserVotes)
@@ -240,6 +316,9 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/user/role", a.adminUserController.UpdateUserRole)
 	r.POST("/user", a.adminUserController.AddUser)
 	r.PUT("/user/password", a.adminUserController.UpdateUserPassword)
+	r.PUT("/user/2fa/reset", a.adminUserController.ResetUserTwoFactor)
+	r.GET("/user/access-tokens", a.accessTokenController.AdminGetAccessTokenList)
+	r.DELETE("/user/access-token", a.accessTokenController.AdminRemoveAccessToken)
 
 	// reason
 	r.GET("/reasons", a.reasonController.Reasons)
@@ -258,6 +337,12 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 	r.GET("/siteinfo/legal", a.siteInfoController.GetSiteLegal)
 	r.GET("/siteinfo/seo", a.siteInfoController.GetSeo)
 	r.GET("/siteinfo/login", a.siteInfoController.GetSiteLogin)
+	r.GET("/siteinfo/login-connector", a.siteInfoController.GetSiteLoginConnector)
+	r.GET("/siteinfo/login-saml", a.siteInfoController.GetSiteLoginSAML)
+	r.GET("/siteinfo/login-ldap", a.siteInfoController.GetSiteLoginLDAP)
+	r.GET("/siteinfo/rate-limit", a.siteInfoController.GetSiteRateLimit)
+	r.GET("/siteinfo/pre-moderation", a.siteInfoController.GetSitePreModeration)
+	r.GET("/siteinfo/spam", a.siteInfoController.GetSiteSpam)
 	r.GET("/siteinfo/custom-css-html", a.siteInfoController.GetSiteCustomCssHTML)
 	r.GET("/siteinfo/theme", a.siteInfoController.GetSiteTheme)
 	r.PUT("/siteinfo/general", a.siteInfoController.UpdateGeneral)
@@ -266,15 +351,52 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/siteinfo/write", a.siteInfoController.UpdateSiteWrite)
 	r.PUT("/siteinfo/legal", a.siteInfoController.UpdateSiteLegal)
 	r.PUT("/siteinfo/login", a.siteInfoController.UpdateSiteLogin)
+	r.PUT("/siteinfo/login-connector", a.siteInfoController.UpdateSiteLoginConnector)
+	r.PUT("/siteinfo/login-saml", a.siteInfoController.UpdateSiteLoginSAML)
+	r.PUT("/siteinfo/login-ldap", a.siteInfoController.UpdateSiteLoginLDAP)
+	r.PUT("/siteinfo/rate-limit", a.siteInfoController.UpdateSiteRateLimit)
+	r.PUT("/siteinfo/pre-moderation", a.siteInfoController.UpdateSitePreModeration)
+	r.PUT("/siteinfo/spam", a.siteInfoController.UpdateSiteSpam)
 	r.PUT("/siteinfo/custom-css-html", a.siteInfoController.UpdateSiteCustomCssHTML)
 	r.PUT("/siteinfo/theme", a.siteInfoController.SaveSiteTheme)
 	r.PUT("/siteinfo/seo", a.siteInfoController.UpdateSeo)
 	r.GET("/setting/smtp", a.siteInfoController.GetSMTPConfig)
 	r.PUT("/setting/smtp", a.siteInfoController.UpdateSMTPConfig)
+	r.GET("/setting/privileges", a.siteInfoController.GetPrivilegeConfig)
+	r.PUT("/setting/privileges", a.siteInfoController.UpdatePrivilegeConfig)
 
 	// dashboard
 	r.GET("/dashboard", a.dashboardController.DashboardInfo)
 
 	// roles
 	r.GET("/roles", a.roleController.GetRoleList)
+	r.POST("/role", a.roleController.AddRole)
+	r.PUT("/role", a.roleController.UpdateRole)
+	r.DELETE("/role", a.roleController.RemoveRole)
+	r.GET("/role/powers", a.roleController.GetRolePowerList)
+	r.PUT("/role/powers", a.roleController.UpdateRolePower)
+
+	// webhook
+	r.GET("/webhooks", a.webhookController.GetWebhookList)
+	r.GET("/webhook/events", a.webhookController.GetWebhookEvents)
+	r.POST("/webhook", a.webhookController.AddWebhook)
+	r.PUT("/webhook", a.webhookController.UpdateWebhook)
+	r.DELETE("/webhook", a.webhookController.RemoveWebhook)
+	r.GET("/webhook/deliveries/page", a.webhookController.GetDeliveryPage)
+	r.POST("/webhook/delivery/redeliver", a.webhookController.Redeliver)
+
+	// badge
+	r.GET("/badges", a.badgeController.GetBadgeList)
+	r.POST("/badge", a.badgeController.AddBadge)
+	r.PUT("/badge", a.badgeController.UpdateBadge)
+	r.GET("/user-badges/page", a.badgeController.GetUserBadgePage)
+	r.POST("/user-badge", a.badgeController.AwardBadge)
+	r.DELETE("/user-badge", a.badgeController.RevokeBadge)
+
+	// audit log
+	r.GET("/audit-logs/page", a.auditLogController.GetAuditLogPage)
+	r.GET("/audit-logs/export", a.auditLogController.ExportAuditLog)
+
+	// spam log
+	r.GET("/spam-logs/page", a.spamController.GetSpamLogPage)
 }
@@ -0,0 +1,50 @@
+package schema
+
+const (
+	// AccessTokenScopeRead read the content and the information of user
+	AccessTokenScopeRead = "read"
+	// AccessTokenScopeWriteQuestion add, update and close questions, also comment and vote
+	AccessTokenScopeWriteQuestion = "write:question"
+	// AccessTokenScopeWriteAnswer add, update and accept answers, also comment and vote
+	AccessTokenScopeWriteAnswer = "write:answer"
+	// AccessTokenScopeAdmin use the admin API if the user is an admin
+	AccessTokenScopeAdmin = "admin"
+)
+
+// AddAccessTokenReq add personal access token request
+type AddAccessTokenReq struct {
+	Name   string   `validate:"required,gt=0,lte=100" json:"name"`
+	Scopes []string `validate:"required,gt=0,dive,oneof=read write:question write:answer admin" json:"scopes"`
+	// ExpiresInDays the token never expires if it is zero
+	ExpiresInDays int    `validate:"omitempty,gte=0,lte=3650" json:"expires_in_days"`
+	UserID        string `json:"-"`
+}
+
+// AddAccessTokenResp the token is only returned once
+type AddAccessTokenResp struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+// GetAccessTokenListReq admin get the access tokens of user
+type GetAccessTokenListReq struct {
+	UserID string `validate:"required" form:"user_id"`
+}
+
+// GetAccessTokenResp personal access token
+type GetAccessTokenResp struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	TokenPrefix string   `json:"token_prefix"`
+	Scopes      []string `json:"scopes"`
+	CreatedAt   int64    `json:"created_at"`
+	// ExpiredAt LastUsedAt are zero if the token never expires or is never used
+	ExpiredAt  int64 `json:"expired_at"`
+	LastUsedAt int64 `json:"last_used_at"`
+}
+
+// RemoveAccessTokenReq revoke personal access token request
+type RemoveAccessTokenReq struct {
+	ID     string `validate:"required" json:"id"`
+	UserID string `json:"-"`
+}
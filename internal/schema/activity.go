@@ -10,6 +10,10 @@ type ActivityMsg struct {
 	OriginalObjectID string                   `json:"original_object_id"`
 	ActivityTypeKey  constant.ActivityTypeKey `json:"activity_type_key"`
 	RevisionID       string                   `json:"revision_id"`
+	// ExpiredAt the unix time when the lock or protection expires, it is 0 if it never expires
+	ExpiredAt int64 `json:"expired_at,omitempty"`
+	// DedupKey the unique key of the message, the activity is added only once even if the message is redelivered
+	DedupKey string `json:"dedup_key"`
 }
 
 // GetObjectTimelineReq get object timeline request
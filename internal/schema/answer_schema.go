@@ -75,6 +75,7 @@ type AnswerInfo struct {
 	CreateTime     int64          `json:"create_time" xorm:"created"`     // create_time
 	UpdateTime     int64          `json:"update_time" xorm:"updated"`     // update_time
 	Accepted       int            `json:"accepted"`                       // 1 Failed 2 accepted
+	Status         int            `json:"status"`                         // 1 available 10 deleted 11 pending
 	UserID         string         `json:"-" `
 	UpdateUserID   string         `json:"-" `
 	UserInfo       *UserBasicInfo `json:"user_info,omitempty"`
@@ -83,6 +84,8 @@ type AnswerInfo struct {
 	VoteStatus     string         `json:"vote_status"`
 	VoteCount      int            `json:"vote_count"`
 	QuestionInfo   *QuestionInfo  `json:"question_info,omitempty"`
+	Locked         bool           `json:"locked"`
+	LockExpiredAt  int64          `json:"lock_expired_at"`
 
 	// MemberActions
 	MemberActions []*PermissionMemberAction `json:"member_actions"`
@@ -113,5 +116,7 @@ type AnswerAcceptedReq struct {
 type AdminSetAnswerStatusRequest struct {
 	StatusStr string `json:"status"`
 	AnswerID  string `json:"answer_id"`
-	UserID    string `json:"-" `
+	// Reason why the status is changed, it is recorded in the audit log
+	Reason string `validate:"omitempty,lte=500" json:"reason"`
+	UserID string `json:"-" `
 }
@@ -0,0 +1,100 @@
+package schema
+
+// AddAuditLogReq add audit log request
+type AddAuditLogReq struct {
+	// UserID the user who does the action, it is the login user in the context if it is empty
+	UserID     string
+	Action     string
+	ObjectType string
+	ObjectID   string
+	// Before After the object before and after the action, they are saved as json
+	Before interface{}
+	After  interface{}
+	Reason string
+}
+
+// AuditLogStatusSnapshot the status of the object in the audit log
+type AuditLogStatusSnapshot struct {
+	Status string `json:"status"`
+}
+
+// AuditLogRoleSnapshot the role of the user in the audit log
+type AuditLogRoleSnapshot struct {
+	RoleID int `json:"role_id"`
+}
+
+// AuditLogReviewSnapshot the report or revision reviewed in the audit log
+type AuditLogReviewSnapshot struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	FlaggedType int    `json:"flagged_type,omitempty"`
+}
+
+// AuditLogMergeSnapshot the question merged into the canonical question in the audit log
+type AuditLogMergeSnapshot struct {
+	Status        string `json:"status"`
+	DuplicateOfID string `json:"duplicate_of_id"`
+}
+
+// AuditLogLockSnapshot the lock or protection of the post in the audit log
+type AuditLogLockSnapshot struct {
+	Status    string `json:"status"`
+	ExpiredAt int64  `json:"expired_at,omitempty"`
+}
+
+// AuditLogBadgeSnapshot the badge awarded to the user in the audit log
+type AuditLogBadgeSnapshot struct {
+	BadgeID  string `json:"badge_id"`
+	ObjectID string `json:"object_id"`
+}
+
+// AuditLogRoleDetailSnapshot the role with its powers in the audit log
+type AuditLogRoleDetailSnapshot struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Powers      []string `json:"powers"`
+}
+
+// AuditLogRolePowerSnapshot the powers of the role in the audit log, the tag scoped power is power_type@tag_id
+type AuditLogRolePowerSnapshot struct {
+	Powers []string `json:"powers"`
+}
+
+// AuditLogWebhookSnapshot the webhook in the audit log, the secret is never recorded
+type AuditLogWebhookSnapshot struct {
+	Name    string   `json:"name"`
+	URL     string   `json:"url"`
+	Events  []string `json:"events"`
+	Tags    []string `json:"tags"`
+	Enabled bool     `json:"enabled"`
+}
+
+// GetAuditLogPageReq get audit log page request
+type GetAuditLogPageReq struct {
+	Page     int `validate:"omitempty,min=1" form:"page"`
+	PageSize int `validate:"omitempty,min=1" form:"page_size"`
+	// Username the username of the user who does the action
+	Username   string `validate:"omitempty,gt=0,lte=100" form:"username"`
+	Action     string `validate:"omitempty,gt=0,lte=100" form:"action"`
+	ObjectType string `validate:"omitempty,gt=0,lte=100" form:"object_type"`
+	ObjectID   string `validate:"omitempty,gt=0,lte=100" form:"object_id"`
+	// StartTime EndTime unix timestamp of the created time range
+	StartTime int64 `validate:"omitempty,min=0" form:"start_time"`
+	EndTime   int64 `validate:"omitempty,min=0" form:"end_time"`
+
+	UserID string `json:"-"`
+}
+
+// GetAuditLogResp get audit log response
+type GetAuditLogResp struct {
+	ID         string         `json:"id"`
+	CreatedAt  int64          `json:"created_at"`
+	UserInfo   *UserBasicInfo `json:"user_info"`
+	Action     string         `json:"action"`
+	ObjectType string         `json:"object_type"`
+	ObjectID   string         `json:"object_id"`
+	Before     string         `json:"before"`
+	After      string         `json:"after"`
+	IP         string         `json:"ip"`
+	Reason     string         `json:"reason"`
+}
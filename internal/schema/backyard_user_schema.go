@@ -6,6 +6,8 @@ type UpdateUserStatusReq struct {
 	UserID string `validate:"required" json:"user_id"`
 	// user status
 	Status string `validate:"required,oneof=normal suspended deleted inactive" json:"status" enums:"normal,suspended,deleted,inactive"`
+	// reason why the status is changed, it is recorded in the audit log
+	Reason string `validate:"omitempty,lte=500" json:"reason"`
 }
 
 const (
@@ -81,6 +83,8 @@ type UpdateUserRoleReq struct {
 	UserID string `validate:"required" json:"user_id"`
 	// role id
 	RoleID int `validate:"required" json:"role_id"`
+	// reason why the role is changed, it is recorded in the audit log
+	Reason string `validate:"omitempty,lte=500" json:"reason"`
 	// login user id
 	LoginUserID string `json:"-"`
 }
@@ -0,0 +1,98 @@
+package schema
+
+// BadgeEvent the event which may make user earn badges
+type BadgeEvent string
+
+const (
+	// BadgeEventAnswerAccepted the answer of user is accepted
+	BadgeEventAnswerAccepted BadgeEvent = "answer.accepted"
+	// BadgeEventVotedUp the question or answer of user is voted up
+	BadgeEventVotedUp BadgeEvent = "voted_up"
+	// BadgeEventVisited user visited the site
+	BadgeEventVisited BadgeEvent = "user.visited"
+)
+
+// BadgeEventMsg badge event message
+type BadgeEventMsg struct {
+	Event BadgeEvent
+	// UserID the user who may earn badges
+	UserID string
+	// ObjectID the question or answer of event
+	ObjectID string
+}
+
+// AddBadgeReq add badge request
+type AddBadgeReq struct {
+	Name        string `validate:"required,gt=0,lte=100" json:"name"`
+	Description string `validate:"omitempty,lte=500" json:"description"`
+	// Level 1 bronze 2 silver 3 gold
+	Level      int    `validate:"required,oneof=1 2 3" json:"level"`
+	Repeatable bool   `json:"repeatable"`
+	Rule       string `validate:"required,oneof=manual accepted_answers tag_upvoted_answers visit_days question_votes" json:"rule"`
+	Threshold  int    `validate:"omitempty,min=0,max=1000000" json:"threshold"`
+	Enabled    bool   `json:"enabled"`
+}
+
+// UpdateBadgeReq update badge request
+type UpdateBadgeReq struct {
+	ID string `validate:"required" json:"id"`
+	AddBadgeReq
+}
+
+// GetBadgeResp badge
+type GetBadgeResp struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Level       int    `json:"level"`
+	Repeatable  bool   `json:"repeatable"`
+	Rule        string `json:"rule"`
+	Threshold   int    `json:"threshold"`
+	Enabled     bool   `json:"enabled"`
+	// AwardCount the number of times the badge has been awarded
+	AwardCount int64 `json:"award_count"`
+}
+
+// AwardBadgeReq award badge to user manually request
+type AwardBadgeReq struct {
+	BadgeID  string `validate:"required" json:"badge_id"`
+	Username string `validate:"required,gt=0,lte=30" json:"username"`
+	UserID   string `json:"-"`
+}
+
+// RevokeBadgeReq revoke the badge awarded to user request
+type RevokeBadgeReq struct {
+	ID     string `validate:"required" json:"id"`
+	UserID string `json:"-"`
+}
+
+// GetUserBadgePageReq get the awarded badges page request
+type GetUserBadgePageReq struct {
+	BadgeID  string `validate:"omitempty" form:"badge_id"`
+	Username string `validate:"omitempty,lte=30" form:"username"`
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1" form:"page_size"`
+}
+
+// GetUserBadgeResp the badge awarded to user
+type GetUserBadgeResp struct {
+	ID         string         `json:"id"`
+	BadgeID    string         `json:"badge_id"`
+	BadgeName  string         `json:"badge_name"`
+	BadgeLevel int            `json:"badge_level"`
+	ObjectID   string         `json:"object_id"`
+	UserInfo   *UserBasicInfo `json:"user_info"`
+	// Manual the badge is awarded by admin
+	Manual    bool  `json:"manual"`
+	CreatedAt int64 `json:"created_at"`
+}
+
+// UserBadgeSummary the badge of user shown on the personal page
+type UserBadgeSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Level       int    `json:"level"`
+	// Count the times user earned the badge
+	Count int `json:"count"`
+}
@@ -0,0 +1,29 @@
+package schema
+
+// StartBountyReq start bounty request
+type StartBountyReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	// Amount the reputation offered, it is debited from user immediately
+	Amount int    `validate:"required,min=50,max=500" json:"amount"`
+	UserID string `json:"-"`
+}
+
+// AwardBountyReq award bounty request
+type AwardBountyReq struct {
+	AnswerID string `validate:"required" json:"answer_id"`
+	UserID   string `json:"-"`
+}
+
+// GetQuestionBountyReq get question bounty request
+type GetQuestionBountyReq struct {
+	QuestionID string `validate:"required" form:"question_id"`
+}
+
+// GetQuestionBountyResp the open bounty of question
+type GetQuestionBountyResp struct {
+	ID        string         `json:"id"`
+	Amount    int            `json:"amount"`
+	CreatedAt int64          `json:"created_at"`
+	ExpiredAt int64          `json:"expired_at"`
+	UserInfo  *UserBasicInfo `json:"user_info"`
+}
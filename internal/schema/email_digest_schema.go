@@ -0,0 +1,39 @@
+package schema
+
+const (
+	NoticeTypeNewAnswer    = "new_answer"
+	NoticeTypeNewComment   = "new_comment"
+	NoticeTypeFollowedTags = "followed_tags"
+)
+
+const (
+	NoticeFrequencyImmediate = "immediate"
+	NoticeFrequencyDaily     = "daily"
+	NoticeFrequencyWeekly    = "weekly"
+	NoticeFrequencyOff       = "off"
+)
+
+// DefaultNoticeFrequency the frequency used when user has not set the preference
+var DefaultNoticeFrequency = map[string]string{
+	NoticeTypeNewAnswer:    NoticeFrequencyImmediate,
+	NoticeTypeNewComment:   NoticeFrequencyImmediate,
+	NoticeTypeFollowedTags: NoticeFrequencyOff,
+}
+
+// GetUserNoticeConfigResp get user notice config response
+type GetUserNoticeConfigResp struct {
+	// new answer to my question: immediate/daily/weekly/off
+	NewAnswer string `json:"new_answer"`
+	// new comment on my post: immediate/daily/weekly/off
+	NewComment string `json:"new_comment"`
+	// new questions in tags I follow: daily/weekly/off
+	FollowedTags string `json:"followed_tags"`
+}
+
+// UpdateUserNoticeConfigReq update user notice config request
+type UpdateUserNoticeConfigReq struct {
+	NewAnswer    string `validate:"required,oneof=immediate daily weekly off" json:"new_answer"`
+	NewComment   string `validate:"required,oneof=immediate daily weekly off" json:"new_comment"`
+	FollowedTags string `validate:"required,oneof=daily weekly off" json:"followed_tags"`
+	UserID       string `json:"-"`
+}
@@ -62,3 +62,36 @@ type NewCommentTemplateData struct {
 	CommentSummary string
 	UnsubscribeUrl string
 }
+
+type DigestTemplateRawData struct {
+	Frequency       string
+	Items           []*DigestTemplateItem
+	NewQuestions    []*DigestTemplateQuestion
+	UnsubscribeCode string
+}
+
+type DigestTemplateItem struct {
+	NoticeType    string
+	DisplayName   string
+	QuestionTitle string
+	QuestionID    string
+	AnswerID      string
+	CommentID     string
+	Summary       string
+	Url           string
+}
+
+type DigestTemplateQuestion struct {
+	ID    string
+	Title string
+	Url   string
+}
+
+type DigestTemplateData struct {
+	SiteName       string
+	Period         string
+	Items          []*DigestTemplateItem
+	NewQuestions   []*DigestTemplateQuestion
+	SettingsUrl    string
+	UnsubscribeUrl string
+}
@@ -52,6 +52,8 @@ type NotificationMsg struct {
 	NotificationAction string
 	// if true no need to send notification to all followers
 	NoNeedPushAllFollow bool
+	// the unique key of the message, the notification is added only once even if the message is redelivered
+	DedupKey string
 }
 
 type ObjectInfo struct {
@@ -66,6 +68,7 @@ type RedDot struct {
 	Achievement int64 `json:"achievement"`
 	Revision    int64 `json:"revision"`
 	CanRevision bool  `json:"can_revision"`
+	PendingPost int64 `json:"pending_post"`
 }
 
 type NotificationSearch struct {
@@ -88,3 +91,23 @@ type NotificationClearIDRequest struct {
 	UserID string `json:"-"`
 	ID     string `json:"id" form:"id"`
 }
+
+// GetNotificationStreamReq get notification stream request
+type GetNotificationStreamReq struct {
+	// QuestionID the question being viewed, the vote and answer counts of it are pushed
+	QuestionID string `validate:"omitempty" form:"question_id"`
+}
+
+// NotificationStreamVote the vote count of question or answer changed
+type NotificationStreamVote struct {
+	ObjectID  string `json:"object_id"`
+	UpVotes   int    `json:"up_votes"`
+	DownVotes int    `json:"down_votes"`
+	Votes     int    `json:"votes"`
+}
+
+// NotificationStreamAnswerCount the answer count of question changed
+type NotificationStreamAnswerCount struct {
+	QuestionID  string `json:"question_id"`
+	AnswerCount int    `json:"answer_count"`
+}
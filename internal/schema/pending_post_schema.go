@@ -0,0 +1,92 @@
+package schema
+
+import "github.com/answerdev/answer/internal/base/constant"
+
+const (
+	PendingPostApprove = "approve"
+	PendingPostReject  = "reject"
+)
+
+// PendingPostExtra the data needed to publish the pending post
+type PendingPostExtra struct {
+	MentionUsernameList []string `json:"mention_username_list,omitempty"`
+	// SpamReason the reason of the spam detection verdict if the post is held by it
+	SpamReason string `json:"spam_reason,omitempty"`
+}
+
+// GetPendingPostPageReq get pending post page request
+type GetPendingPostPageReq struct {
+	Page              int    `validate:"omitempty,min=1" form:"page"`
+	PageSize          int    `validate:"omitempty,min=1" form:"page_size"`
+	UserID            string `json:"-"`
+	CanReviewQuestion bool   `json:"-"`
+	CanReviewAnswer   bool   `json:"-"`
+	// CanReviewQuestionInTags CanReviewAnswerInTags the user can review the posts of some tags only,
+	// each post of these types is checked by the tags of it
+	CanReviewQuestionInTags bool `json:"-"`
+	CanReviewAnswerInTags   bool `json:"-"`
+}
+
+// GetCanReviewObjectTypes the comments can be reviewed by the users who can review questions or answers
+func (r *GetPendingPostPageReq) GetCanReviewObjectTypes() []int {
+	return pendingPostObjectTypes(r.CanReviewQuestion || r.CanReviewQuestionInTags,
+		r.CanReviewAnswer || r.CanReviewAnswerInTags)
+}
+
+// CanReviewAll whether the user can review all the posts of the object type regardless of the tags
+func (r *GetPendingPostPageReq) CanReviewAll(objectType int) bool {
+	return containsObjectType(pendingPostObjectTypes(r.CanReviewQuestion, r.CanReviewAnswer), objectType)
+}
+
+// GetPendingPostResp get pending post response
+type GetPendingPostResp struct {
+	ID         string         `json:"id"`
+	CreatedAt  int64          `json:"created_at"`
+	ObjectID   string         `json:"object_id"`
+	ObjectType string         `json:"object_type"`
+	QuestionID string         `json:"question_id"`
+	AnswerID   string         `json:"answer_id"`
+	Title      string         `json:"title"`
+	Content    string         `json:"content"`
+	SpamReason string         `json:"spam_reason"`
+	UserInfo   *UserBasicInfo `json:"user_info"`
+}
+
+// ReviewPendingPostReq review pending post request
+type ReviewPendingPostReq struct {
+	ID        string `validate:"required" json:"id"`
+	Operation string `validate:"required,oneof=approve reject" json:"operation"`
+	// Spam whether the rejected post is spam, it is used to train the spam classifier
+	Spam              bool   `json:"spam"`
+	UserID            string `json:"-"`
+	CanReviewQuestion bool   `json:"-"`
+	CanReviewAnswer   bool   `json:"-"`
+}
+
+// CanReview whether the user can review the post of the object type
+func (r *ReviewPendingPostReq) CanReview(objectType int) bool {
+	return containsObjectType(pendingPostObjectTypes(r.CanReviewQuestion, r.CanReviewAnswer), objectType)
+}
+
+func containsObjectType(objectTypes []int, objectType int) bool {
+	for _, t := range objectTypes {
+		if t == objectType {
+			return true
+		}
+	}
+	return false
+}
+
+func pendingPostObjectTypes(canReviewQuestion, canReviewAnswer bool) []int {
+	objectTypes := make([]int, 0)
+	if canReviewQuestion {
+		objectTypes = append(objectTypes, constant.ObjectTypeStrMapping[constant.QuestionObjectType])
+	}
+	if canReviewAnswer {
+		objectTypes = append(objectTypes, constant.ObjectTypeStrMapping[constant.AnswerObjectType])
+	}
+	if canReviewQuestion || canReviewAnswer {
+		objectTypes = append(objectTypes, constant.ObjectTypeStrMapping[constant.CommentObjectType])
+	}
+	return objectTypes
+}
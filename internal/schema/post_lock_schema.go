@@ -0,0 +1,37 @@
+package schema
+
+// LockPostReq lock post request
+type LockPostReq struct {
+	// ObjectID the question or answer id
+	ObjectID string `validate:"required" json:"object_id"`
+	// Duration the hours the post is locked for, 0 means it is locked until it is unlocked
+	Duration int    `validate:"omitempty,min=0,max=8760" json:"duration"`
+	UserID   string `json:"-"`
+}
+
+// UnlockPostReq unlock post request
+type UnlockPostReq struct {
+	ObjectID string `validate:"required" json:"object_id"`
+	UserID   string `json:"-"`
+}
+
+// ProtectQuestionReq protect question request
+type ProtectQuestionReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	// Duration the hours the question is protected for, 0 means it is protected until it is unprotected
+	Duration int    `validate:"omitempty,min=0,max=8760" json:"duration"`
+	UserID   string `json:"-"`
+}
+
+// UnprotectQuestionReq unprotect question request
+type UnprotectQuestionReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	UserID     string `json:"-"`
+}
+
+// ExpiredPostLocks the posts whose lock or protection is released because it is expired
+type ExpiredPostLocks struct {
+	LockedQuestionIDs    []string
+	ProtectedQuestionIDs []string
+	LockedAnswerIDs      []string
+}
@@ -0,0 +1,18 @@
+package schema
+
+// MergeQuestionReq merge question request
+type MergeQuestionReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	// DuplicateOfID the canonical question which the answers, comments and followers are moved to
+	DuplicateOfID string `validate:"required" json:"duplicate_of_id"`
+	UserID        string `json:"-"`
+}
+
+// QuestionLinkInfo the question linked as the canonical question or a duplicate
+type QuestionLinkInfo struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	UrlTitle    string `json:"url_title"`
+	AnswerCount int    `json:"answer_count"`
+	Status      int    `json:"status"`
+}
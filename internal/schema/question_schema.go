@@ -26,6 +26,8 @@ type CloseQuestionReq struct {
 	CloseType int    `json:"close_type"` // close_type
 	CloseMsg  string `json:"close_msg"`  // close_type
 	UserID    string `json:"-"`          // user_id
+	// DuplicateOfID the original question if the question is closed as a duplicate
+	DuplicateOfID string `json:"duplicate_of_id"`
 }
 
 type CloseQuestionMeta struct {
@@ -76,6 +78,8 @@ type QuestionPermission struct {
 	CanReopen bool `json:"-"`
 	// whether user can use reserved it
 	CanUseReservedTag bool `json:"-"`
+	// whether user can review it, the question waiting for review is visible to the reviewer
+	CanReview bool `json:"-"`
 }
 
 type CheckCanQuestionUpdate struct {
@@ -153,6 +157,17 @@ type QuestionInfo struct {
 	Collected            bool           `json:"collected"`
 	VoteStatus           string         `json:"vote_status"`
 	IsFollowed           bool           `json:"is_followed"`
+	// DuplicateOf the canonical question if the question is closed as a duplicate
+	DuplicateOf *QuestionLinkInfo `json:"duplicate_of,omitempty"`
+	// Merged the question is merged into the canonical question, the client should redirect to it
+	Merged     bool                `json:"merged"`
+	Duplicates []*QuestionLinkInfo `json:"duplicates"`
+	// Locked the question and its answers can not be edited, voted, answered or commented
+	Locked        bool  `json:"locked"`
+	LockExpiredAt int64 `json:"lock_expired_at"`
+	// Protected only the users with enough reputation can answer the question
+	Protected        bool  `json:"protected"`
+	ProtectExpiredAt int64 `json:"protect_expired_at"`
 
 	// MemberActions
 	MemberActions []*PermissionMemberAction `json:"member_actions"`
@@ -230,13 +245,14 @@ const (
 	QuestionOrderCondFrequent   = "frequent"
 	QuestionOrderCondScore      = "score"
 	QuestionOrderCondUnanswered = "unanswered"
+	QuestionOrderCondFeatured   = "featured"
 )
 
 // QuestionPageReq query questions page
 type QuestionPageReq struct {
 	Page      int    `validate:"omitempty,min=1" form:"page"`
 	PageSize  int    `validate:"omitempty,min=1" form:"page_size"`
-	OrderCond string `validate:"omitempty,oneof=newest active frequent score unanswered" form:"order"`
+	OrderCond string `validate:"omitempty,oneof=newest active frequent score unanswered featured" form:"order"`
 	Tag       string `validate:"omitempty,gt=0,lte=100" form:"tag"`
 	Username  string `validate:"omitempty,gt=0,lte=100" form:"username"`
 
@@ -297,6 +313,9 @@ type AdminQuestionSearch struct {
 type AdminSetQuestionStatusRequest struct {
 	StatusStr  string `json:"status" form:"status"`
 	QuestionID string `json:"question_id" form:"question_id"`
+	// Reason why the status is changed, it is recorded in the audit log
+	Reason string `validate:"omitempty,lte=500" json:"reason" form:"reason"`
+	UserID string `json:"-"`
 }
 
 type SiteMapList struct {
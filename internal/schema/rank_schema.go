@@ -33,3 +33,27 @@ type GetRankPersonalWithPageResp struct {
 	// rank type
 	RankType string `json:"rank_type"`
 }
+
+// RankConfigItem the rank config, the key is the action of privilege or the activity type of reputation
+type RankConfigItem struct {
+	Key   string `validate:"required" json:"key"`
+	Value int    `json:"value"`
+}
+
+// GetPrivilegeConfigResp get the privileges and reputation config response
+type GetPrivilegeConfigResp struct {
+	// Privileges the reputation required for the action, -1 means only the roles with the power can do it
+	Privileges []*RankConfigItem `json:"privileges"`
+	// Reputations the reputation earned or lost by the activity
+	Reputations []*RankConfigItem `json:"reputations"`
+	// Limits the daily reputation limit and the votes from one user to another that are treated as serial votes
+	Limits []*RankConfigItem `json:"limits"`
+}
+
+// UpdatePrivilegeConfigReq update the privileges and reputation config request, the missing keys are unchanged
+type UpdatePrivilegeConfigReq struct {
+	Privileges  []*RankConfigItem `validate:"omitempty,dive" json:"privileges"`
+	Reputations []*RankConfigItem `validate:"omitempty,dive" json:"reputations"`
+	Limits      []*RankConfigItem `validate:"omitempty,dive" json:"limits"`
+	UserID      string            `json:"-"`
+}
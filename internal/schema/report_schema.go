@@ -45,6 +45,7 @@ type ReportHandleReq struct {
 	ID             string `validate:"required" comment:"report id" form:"id" json:"id"`
 	FlaggedType    int    `validate:"required" comment:"flagged type" form:"flagged_type" json:"flagged_type"`
 	FlaggedContent string `validate:"omitempty" comment:"flagged content" form:"flagged_content" json:"flagged_content"`
+	UserID         string `json:"-"`
 }
 
 // GetReportListPageDTO report list data transfer object
@@ -6,3 +6,54 @@ type GetRoleResp struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 }
+
+// AddRoleReq add role request
+type AddRoleReq struct {
+	Name        string `validate:"required,gt=0,lte=50" json:"name"`
+	Description string `validate:"omitempty,lte=200" json:"description"`
+}
+
+// UpdateRoleReq update role request
+type UpdateRoleReq struct {
+	ID int `validate:"required" json:"id"`
+	AddRoleReq
+}
+
+// RemoveRoleReq remove role request
+type RemoveRoleReq struct {
+	ID int `validate:"required" json:"id"`
+}
+
+// GetRolePowerListReq get the powers of role request
+type GetRolePowerListReq struct {
+	RoleID int `validate:"required" form:"role_id"`
+}
+
+// GetRolePowerResp the power and whether the role has it
+type GetRolePowerResp struct {
+	PowerType   string `json:"power_type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Granted the role has the power everywhere
+	Granted bool `json:"granted"`
+	// Tags the role only has the power on the questions with these tags
+	Tags []*RolePowerTag `json:"tags"`
+}
+
+// RolePowerTag the tag which the power of role is scoped to
+type RolePowerTag struct {
+	SlugName    string `json:"slug_name"`
+	DisplayName string `json:"display_name"`
+}
+
+// UpdateRolePowerReq replace all powers of role request
+type UpdateRolePowerReq struct {
+	RoleID int                `validate:"required" json:"role_id"`
+	Powers []*RolePowerConfig `validate:"omitempty,dive" json:"powers"`
+}
+
+// RolePowerConfig the power of role, it works everywhere if no tag is set
+type RolePowerConfig struct {
+	PowerType string   `validate:"required" json:"power_type"`
+	Tags      []string `validate:"omitempty,dive,gt=0" json:"tags"`
+}
@@ -93,6 +93,148 @@ type GetSiteLegalInfoResp struct {
 type SiteLoginReq struct {
 	AllowNewRegistrations bool `json:"allow_new_registrations"`
 	LoginRequired         bool `json:"login_required"`
+	// DisallowPasswordLogin only the external login connectors can be used, except for the admin
+	DisallowPasswordLogin bool `json:"disallow_password_login"`
+	// RequireTwoFactorAdmin RequireTwoFactorModerator the users of role must enroll two-factor authentication on login
+	RequireTwoFactorAdmin     bool `json:"require_two_factor_admin"`
+	RequireTwoFactorModerator bool `json:"require_two_factor_moderator"`
+}
+
+const (
+	LoginConnectorTypeOIDC   = "oidc"
+	LoginConnectorTypeGithub = "github"
+	LoginConnectorTypeGoogle = "google"
+	// LoginConnectorTypeSAML the SAML login is configured separately, so it can not be used as connector name
+	LoginConnectorTypeSAML = "saml"
+	// LoginConnectorTypeLDAP the provider of users verified by the directory
+	LoginConnectorTypeLDAP = "ldap"
+)
+
+// SiteLoginConnectorReq site login connector request
+type SiteLoginConnectorReq struct {
+	Connectors []*LoginConnector `validate:"omitempty,dive" json:"connectors"`
+}
+
+// LoginConnector OAuth2 or OpenID Connect login provider
+type LoginConnector struct {
+	// Name unique name of connector, it is used in the callback url and identifies the linked users,
+	// so it should not be changed after users have logged in.
+	Name         string `validate:"required,gt=0,lte=30,alphanum,lowercase" json:"name"`
+	DisplayName  string `validate:"required,gt=0,lte=30" json:"display_name"`
+	Type         string `validate:"required,oneof=oidc github google" json:"type"`
+	Enabled      bool   `json:"enabled"`
+	ClientID     string `validate:"required,gt=0,lte=512" json:"client_id"`
+	ClientSecret string `validate:"required,gt=0,lte=512" json:"client_secret"`
+	// Issuer the issuer url for OpenID Connect discovery, only required by the oidc type
+	Issuer string   `validate:"omitempty,url,lte=512" json:"issuer"`
+	Scopes []string `validate:"omitempty,dive,gt=0,lte=100" json:"scopes"`
+}
+
+// SiteLoginSAMLReq site login SAML 2.0 service provider request
+type SiteLoginSAMLReq struct {
+	Enabled     bool   `json:"enabled"`
+	DisplayName string `validate:"omitempty,lte=30" json:"display_name"`
+	// IDPEntityID IDPSSOURL IDPCertificate are copied from the metadata of IdP
+	IDPEntityID    string `validate:"omitempty,lte=512" json:"idp_entity_id"`
+	IDPSSOURL      string `validate:"omitempty,url,lte=512" json:"idp_sso_url"`
+	IDPCertificate string `validate:"omitempty,lte=65536" json:"idp_certificate"`
+	// SPCertificate SPPrivateKey sign the AuthnRequest, a new key pair is generated if they are empty
+	SPCertificate string `validate:"omitempty,lte=65536" json:"sp_certificate"`
+	SPPrivateKey  string `validate:"omitempty,lte=65536" json:"sp_private_key,omitempty"`
+	// the attribute names of IdP, the NameID is used if the email attribute is empty
+	UsernameAttribute    string `validate:"omitempty,lte=255" json:"username_attribute"`
+	DisplayNameAttribute string `validate:"omitempty,lte=255" json:"display_name_attribute"`
+	EmailAttribute       string `validate:"omitempty,lte=255" json:"email_attribute"`
+	// GroupAttribute the role of user is updated by the groups on each login if it is set
+	GroupAttribute  string   `validate:"omitempty,lte=255" json:"group_attribute"`
+	AdminGroups     []string `validate:"omitempty,dive,gt=0,lte=255" json:"admin_groups"`
+	ModeratorGroups []string `validate:"omitempty,dive,gt=0,lte=255" json:"moderator_groups"`
+}
+
+// SiteLoginLDAPReq site login LDAP request, the password of email login is verified by the directory
+type SiteLoginLDAPReq struct {
+	Enabled bool `json:"enabled"`
+	// URL ldap://host:389 or ldaps://host:636
+	URL                string `validate:"omitempty,url,lte=512" json:"url"`
+	StartTLS           bool   `json:"start_tls"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+	// BindDN BindPassword the service account to search the user, the old password is kept if it is empty
+	BindDN       string `validate:"omitempty,lte=512" json:"bind_dn"`
+	BindPassword string `validate:"omitempty,lte=512" json:"bind_password,omitempty"`
+	BaseDN       string `validate:"omitempty,lte=512" json:"base_dn"`
+	// UserFilter the filter to find the user by the login email, e.g. (&(objectClass=person)(mail={login}))
+	UserFilter           string `validate:"omitempty,lte=512" json:"user_filter"`
+	UsernameAttribute    string `validate:"omitempty,lte=255" json:"username_attribute"`
+	DisplayNameAttribute string `validate:"omitempty,lte=255" json:"display_name_attribute"`
+	EmailAttribute       string `validate:"omitempty,lte=255" json:"email_attribute"`
+	// GroupAttribute the role of user is updated by the groups on each login if it is set, e.g. memberOf
+	GroupAttribute  string   `validate:"omitempty,lte=255" json:"group_attribute"`
+	AdminGroups     []string `validate:"omitempty,dive,gt=0,lte=512" json:"admin_groups"`
+	ModeratorGroups []string `validate:"omitempty,dive,gt=0,lte=512" json:"moderator_groups"`
+}
+
+const (
+	RateLimitClassPosting    = "posting"
+	RateLimitClassVoting     = "voting"
+	RateLimitClassCommenting = "commenting"
+	RateLimitClassFlagging   = "flagging"
+	RateLimitClassLogin      = "login"
+
+	RateLimitScopeIP   = "ip"
+	RateLimitScopeUser = "user"
+)
+
+// SiteRateLimitReq site rate limit request, the write requests are limited by the token buckets of the rules
+type SiteRateLimitReq struct {
+	Enabled bool             `json:"enabled"`
+	Rules   []*RateLimitRule `validate:"omitempty,lte=100,dive" json:"rules"`
+}
+
+// RateLimitRule the limit of the route class. The rule of ip scope limits all the requests from one IP.
+// The rule of user scope limits the logged-in user, the one of the role with the highest min rank
+// not above the reputation of user is used, the rules of all roles are used if no rule of the role matches.
+type RateLimitRule struct {
+	Class string `validate:"required,oneof=posting voting commenting flagging login" json:"class"`
+	Scope string `validate:"required,oneof=ip user" json:"scope"`
+	// RoleID the role of user, 0 means all roles
+	RoleID  int `validate:"omitempty,gte=0" json:"role_id"`
+	MinRank int `validate:"omitempty,gte=0" json:"min_rank"`
+	// Limit the requests allowed every period of seconds, 0 means no limit
+	Limit  int `validate:"omitempty,gte=0" json:"limit"`
+	Period int `validate:"required,gt=0,lte=86400" json:"period"`
+	// Burst the most requests allowed at once, it is the limit if it is 0
+	Burst int `validate:"omitempty,gte=0" json:"burst"`
+}
+
+// SitePreModerationReq site pre-moderation request, the new questions, answers and comments of the user
+// are held for review if the user has fewer published posts than first posts or less reputation than min rank
+type SitePreModerationReq struct {
+	Enabled bool `json:"enabled"`
+	// FirstPosts the number of the first questions and answers of the user held for review
+	FirstPosts int `validate:"omitempty,gte=0,lte=1000" json:"first_posts"`
+	MinRank    int `validate:"omitempty,gte=0" json:"min_rank"`
+}
+
+// SiteSpamReq site spam detection request, the checkers run in order and the most severe verdict is used
+type SiteSpamReq struct {
+	Enabled bool `json:"enabled"`
+	// BlockedKeywords the content containing any of the keywords is matched, it is case-insensitive
+	BlockedKeywords []string `validate:"omitempty,dive,gt=0,lte=100" json:"blocked_keywords"`
+	// BlockedPatterns the regular expressions matched against the content
+	BlockedPatterns []string `validate:"omitempty,dive,gt=0,lte=500" json:"blocked_patterns"`
+	// BlocklistAction review or reject the matched content, it is reject by default
+	BlocklistAction string `validate:"omitempty,oneof=review reject" json:"blocklist_action"`
+	// MaxLinks the max links of the post of the user without the link url limit power, 0 means no limit
+	MaxLinks int `validate:"omitempty,gte=0" json:"max_links"`
+	// DuplicateLimit the times the same content can be posted in the duplicate window, 0 means no limit
+	DuplicateLimit int `validate:"omitempty,gte=0" json:"duplicate_limit"`
+	// DuplicateWindow the seconds the content fingerprint is kept
+	DuplicateWindow   int  `validate:"omitempty,gte=0" json:"duplicate_window"`
+	ClassifierEnabled bool `json:"classifier_enabled"`
+	// ClassifierReviewScore the content with a spam probability not below it is held for review
+	ClassifierReviewScore float64 `validate:"omitempty,gte=0,lte=1" json:"classifier_review_score"`
+	// ClassifierRejectScore the content with a spam probability not below it is rejected, 0 means never
+	ClassifierRejectScore float64 `validate:"omitempty,gte=0,lte=1" json:"classifier_reject_score"`
 }
 
 // SiteCustomCssHTMLReq site custom css html
@@ -121,6 +263,35 @@ type SiteBrandingResp SiteBrandingReq
 // SiteLoginResp site login response
 type SiteLoginResp SiteLoginReq
 
+// SiteLoginConnectorResp site login connector response
+type SiteLoginConnectorResp SiteLoginConnectorReq
+
+// SiteLoginSAMLResp site login SAML response
+type SiteLoginSAMLResp SiteLoginSAMLReq
+
+// SiteLoginLDAPResp site login LDAP response
+type SiteLoginLDAPResp SiteLoginLDAPReq
+
+// SiteRateLimitResp site rate limit response
+type SiteRateLimitResp SiteRateLimitReq
+
+// SitePreModerationResp site pre-moderation response
+type SitePreModerationResp SitePreModerationReq
+
+// SiteSpamResp site spam detection response
+type SiteSpamResp SiteSpamReq
+
+// DefaultSiteRateLimitRules the rules suggested before the rate limit is configured, it is disabled by default
+var DefaultSiteRateLimitRules = []*RateLimitRule{
+	{Class: RateLimitClassPosting, Scope: RateLimitScopeIP, Limit: 60, Period: 3600},
+	{Class: RateLimitClassPosting, Scope: RateLimitScopeUser, Limit: 10, Period: 3600, Burst: 3},
+	{Class: RateLimitClassPosting, Scope: RateLimitScopeUser, MinRank: 1000, Limit: 60, Period: 3600, Burst: 10},
+	{Class: RateLimitClassVoting, Scope: RateLimitScopeUser, Limit: 60, Period: 3600, Burst: 20},
+	{Class: RateLimitClassCommenting, Scope: RateLimitScopeUser, Limit: 30, Period: 3600, Burst: 5},
+	{Class: RateLimitClassFlagging, Scope: RateLimitScopeUser, Limit: 10, Period: 3600, Burst: 5},
+	{Class: RateLimitClassLogin, Scope: RateLimitScopeIP, Limit: 10, Period: 300},
+}
+
 // SiteCustomCssHTMLResp site custom css html response
 type SiteCustomCssHTMLResp SiteCustomCssHTMLReq
 
@@ -192,6 +363,8 @@ type UpdateSMTPConfigReq struct {
 	SMTPPassword       string `validate:"omitempty,gt=0,lte=256" json:"smtp_password"`
 	SMTPAuthentication bool   `validate:"omitempty" json:"smtp_authentication"`
 	TestEmailRecipient string `validate:"omitempty,email" json:"test_email_recipient"`
+	// the base address of reply-by-email, the replies of notification emails are sent to it
+	ReplyAddress string `validate:"omitempty,email,lte=256" json:"reply_address"`
 }
 
 func (r *UpdateSMTPConfigReq) Check() (errField []*validator.FormErrorField, err error) {
@@ -215,6 +388,7 @@ type GetSMTPConfigResp struct {
 	SMTPUsername       string `json:"smtp_username"`
 	SMTPPassword       string `json:"smtp_password"`
 	SMTPAuthentication bool   `json:"smtp_authentication"`
+	ReplyAddress       string `json:"reply_address"`
 }
 
 // GetManifestJsonResp get manifest json response
@@ -0,0 +1,22 @@
+package schema
+
+// GetSpamLogPageReq get spam log page request
+type GetSpamLogPageReq struct {
+	Page       int    `validate:"omitempty,min=1" form:"page"`
+	PageSize   int    `validate:"omitempty,min=1" form:"page_size"`
+	Action     string `validate:"omitempty,oneof=review reject" form:"action"`
+	ObjectType string `validate:"omitempty,oneof=question answer comment user" form:"object_type"`
+}
+
+// GetSpamLogResp get spam log response
+type GetSpamLogResp struct {
+	ID         string         `json:"id"`
+	CreatedAt  int64          `json:"created_at"`
+	UserInfo   *UserBasicInfo `json:"user_info"`
+	ObjectType string         `json:"object_type"`
+	Action     string         `json:"action"`
+	Checker    string         `json:"checker"`
+	Reason     string         `json:"reason"`
+	Content    string         `json:"content"`
+	IP         string         `json:"ip"`
+}
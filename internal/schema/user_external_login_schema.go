@@ -0,0 +1,37 @@
+package schema
+
+// ExternalLoginConnectorResp the enabled external login connector
+type ExternalLoginConnectorResp struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+	// Link the url to start login with the connector
+	Link string `json:"link"`
+}
+
+// ExternalLoginCallbackReq the callback request from the provider
+type ExternalLoginCallbackReq struct {
+	Code  string `form:"code"`
+	State string `form:"state"`
+	Error string `form:"error"`
+	// StateCookie the state saved in the cookie of the browser which started the login
+	StateCookie string `form:"-"`
+}
+
+// ExternalLoginTokenReq exchange the one-time login code for the access token
+type ExternalLoginTokenReq struct {
+	Code string `validate:"required,gt=0,lte=100" json:"code"`
+}
+
+// ExternalLoginTokenResp the access token of the login code
+type ExternalLoginTokenResp struct {
+	AccessToken string `json:"access_token"`
+}
+
+// SAMLACSReq the response posted by the IdP to the assertion consumer service
+type SAMLACSReq struct {
+	SAMLResponse string `form:"SAMLResponse"`
+	RelayState   string `form:"RelayState"`
+	// RequestIDCookie the id of AuthnRequest saved in the cookie of the browser which started the login
+	RequestIDCookie string `form:"-"`
+}
@@ -70,6 +70,10 @@ type GetUserResp struct {
 	IsAdmin bool `json:"is_admin"`
 	// user status
 	Status string `json:"status"`
+	// TwoFactorToken the login is not finished until the two-factor code is verified with this token
+	TwoFactorToken string `json:"two_factor_token,omitempty"`
+	// TwoFactorEnrollRequired the user must enroll two-factor authentication before login
+	TwoFactorEnrollRequired bool `json:"two_factor_enroll_required,omitempty"`
 }
 
 func (r *GetUserResp) GetFromUserEntity(userInfo *entity.User) {
@@ -166,6 +170,8 @@ type GetOtherUserInfoByUsernameResp struct {
 	IsAdmin   bool   `json:"is_admin"`
 	Status    string `json:"status"`
 	StatusMsg string `json:"status_msg,omitempty"`
+	// badges
+	Badges []*UserBadgeSummary `json:"badges"`
 }
 
 func (r *GetOtherUserInfoByUsernameResp) GetFromUserEntity(userInfo *entity.User) {
@@ -0,0 +1,51 @@
+package schema
+
+// TwoFactorStatusResp two-factor authentication status of user
+type TwoFactorStatusResp struct {
+	Enabled bool `json:"enabled"`
+	// Required the role of user requires two-factor authentication, so it can not be disabled
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorEnrollResp the secret to add to the authenticator app
+type TwoFactorEnrollResp struct {
+	Secret string `json:"secret"`
+	// ProvisioningURI the otpauth uri
+	ProvisioningURI string `json:"provisioning_uri"`
+	// QRCode the data uri of png image of provisioning uri
+	QRCode string `json:"qr_code"`
+}
+
+// TwoFactorCodeReq verify the TOTP code or recovery code
+type TwoFactorCodeReq struct {
+	Code   string `validate:"required,gt=0,lte=32" json:"code"`
+	UserID string `json:"-"`
+}
+
+// TwoFactorRecoveryCodesResp the recovery codes which are only shown once
+type TwoFactorRecoveryCodesResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorLoginEnrollReq enroll two-factor authentication during login
+type TwoFactorLoginEnrollReq struct {
+	TwoFactorToken string `validate:"required,gt=0,lte=64" json:"two_factor_token"`
+}
+
+// TwoFactorLoginReq verify the code to finish login
+type TwoFactorLoginReq struct {
+	TwoFactorToken string `validate:"required,gt=0,lte=64" json:"two_factor_token"`
+	Code           string `validate:"required,gt=0,lte=32" json:"code"`
+}
+
+// TwoFactorLoginResp the logged in user, the recovery codes are returned if the enrollment is finished in login
+type TwoFactorLoginResp struct {
+	*GetUserResp
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// AdminResetTwoFactorReq admin reset the two-factor authentication of user
+type AdminResetTwoFactorReq struct {
+	UserID string `validate:"required" json:"user_id"`
+}
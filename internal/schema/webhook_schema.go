@@ -0,0 +1,120 @@
+package schema
+
+import "github.com/answerdev/answer/internal/base/constant"
+
+// WebhookEvents the activity type keys which can trigger webhooks
+var WebhookEvents = []constant.ActivityTypeKey{
+	constant.ActQuestionAsked,
+	constant.ActQuestionEdited,
+	constant.ActQuestionClosed,
+	constant.ActQuestionReopened,
+	constant.ActQuestionDeleted,
+	constant.ActQuestionCommented,
+	constant.ActQuestionAnswered,
+	constant.ActAnswerAnswered,
+	constant.ActAnswerEdited,
+	constant.ActAnswerAccept,
+	constant.ActAnswerDeleted,
+	constant.ActAnswerCommented,
+	constant.ActTagCreated,
+	constant.ActTagEdited,
+	constant.ActTagDeleted,
+}
+
+// AddWebhookReq add webhook request
+type AddWebhookReq struct {
+	Name string `validate:"required,gt=0,lte=100" json:"name"`
+	URL  string `validate:"required,url,lte=512" json:"url"`
+	// Secret a random secret is generated if it is empty when adding, the secret is kept if it is empty when updating
+	Secret string   `validate:"omitempty,lte=128" json:"secret"`
+	Events []string `validate:"required,gt=0" json:"events"`
+	// Tags the slug names of tags, the events of all tags are sent if it is empty
+	Tags    []string `validate:"omitempty" json:"tags"`
+	Enabled bool     `json:"enabled"`
+}
+
+// AddWebhookResp the added webhook, the secret is only returned here so that the generated one can be saved
+type AddWebhookResp struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+// UpdateWebhookReq update webhook request
+type UpdateWebhookReq struct {
+	ID string `validate:"required" json:"id"`
+	AddWebhookReq
+}
+
+// RemoveWebhookReq remove webhook request
+type RemoveWebhookReq struct {
+	ID string `validate:"required" json:"id"`
+}
+
+// GetWebhookResp webhook
+type GetWebhookResp struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// SecretHint the last characters of secret, the secret itself is never returned
+	SecretHint string   `json:"secret_hint"`
+	Events     []string `json:"events"`
+	Tags       []string `json:"tags"`
+	Enabled    bool     `json:"enabled"`
+	CreatedAt  int64    `json:"created_at"`
+}
+
+// GetWebhookDeliveryPageReq get webhook delivery page request
+type GetWebhookDeliveryPageReq struct {
+	WebhookID string `validate:"required" form:"webhook_id"`
+	Page      int    `validate:"omitempty,min=1" form:"page"`
+	PageSize  int    `validate:"omitempty,min=1" form:"page_size"`
+}
+
+// GetWebhookDeliveryResp webhook delivery
+type GetWebhookDeliveryResp struct {
+	ID             string `json:"id"`
+	WebhookID      string `json:"webhook_id"`
+	Event          string `json:"event"`
+	Payload        string `json:"payload"`
+	Status         int    `json:"status"`
+	Attempts       int    `json:"attempts"`
+	ResponseStatus int    `json:"response_status"`
+	ResponseBody   string `json:"response_body"`
+	Error          string `json:"error"`
+	Duration       int    `json:"duration"`
+	CreatedAt      int64  `json:"created_at"`
+	DeliveredAt    int64  `json:"delivered_at"`
+}
+
+// RedeliverWebhookReq redeliver webhook request
+type RedeliverWebhookReq struct {
+	ID string `validate:"required" json:"id"`
+}
+
+// WebhookPayload the json body posted to the webhook url
+type WebhookPayload struct {
+	Event     string               `json:"event"`
+	CreatedAt int64                `json:"created_at"`
+	Object    WebhookPayloadObject `json:"object"`
+	User      *WebhookPayloadUser  `json:"user,omitempty"`
+}
+
+// WebhookPayloadObject the object of the event
+type WebhookPayloadObject struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	QuestionID string   `json:"question_id,omitempty"`
+	AnswerID   string   `json:"answer_id,omitempty"`
+	CommentID  string   `json:"comment_id,omitempty"`
+	TagID      string   `json:"tag_id,omitempty"`
+	Title      string   `json:"title"`
+	URL        string   `json:"url"`
+	Tags       []string `json:"tags"`
+}
+
+// WebhookPayloadUser the user who triggered the event
+type WebhookPayloadUser struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+}
@@ -0,0 +1,226 @@
+package access_token
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/role"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// TokenPrefix the personal access tokens are distinguished from the session tokens by the prefix
+	TokenPrefix = "answer_pat_"
+	// tokenPrefixShownLength the length of beginning of token which is shown in the list
+	tokenPrefixShownLength = len(TokenPrefix) + 6
+	// lastUsedUpdateInterval the last used time is not updated on every request
+	lastUsedUpdateInterval = time.Minute
+)
+
+// AccessTokenRepo personal access token repository
+type AccessTokenRepo interface {
+	AddAccessToken(ctx context.Context, token *entity.AccessToken) (err error)
+	GetAccessToken(ctx context.Context, id string) (token *entity.AccessToken, exist bool, err error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (token *entity.AccessToken, exist bool, err error)
+	GetUserAccessTokens(ctx context.Context, userID string) (tokens []*entity.AccessToken, err error)
+	UpdateLastUsedAt(ctx context.Context, id string, lastUsedAt time.Time) (err error)
+	RemoveAccessToken(ctx context.Context, id string) (err error)
+}
+
+// AccessTokenService personal access token service
+type AccessTokenService struct {
+	accessTokenRepo AccessTokenRepo
+	userRepo        usercommon.UserRepo
+	userRoleService *role.UserRoleRelService
+}
+
+// NewAccessTokenService new access token service
+func NewAccessTokenService(
+	accessTokenRepo AccessTokenRepo,
+	userRepo usercommon.UserRepo,
+	userRoleService *role.UserRoleRelService,
+) *AccessTokenService {
+	return &AccessTokenService{
+		accessTokenRepo: accessTokenRepo,
+		userRepo:        userRepo,
+		userRoleService: userRoleService,
+	}
+}
+
+// AddAccessToken create a new personal access token, the admin scope is only allowed for the admin
+func (as *AccessTokenService) AddAccessToken(ctx context.Context, req *schema.AddAccessTokenReq) (
+	resp *schema.AddAccessTokenResp, err error) {
+	scopes := uniqueScopes(req.Scopes)
+	for _, scope := range scopes {
+		if scope != schema.AccessTokenScopeAdmin {
+			continue
+		}
+		roleID, err := as.userRoleService.GetUserRole(ctx, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if roleID != role.RoleAdminID {
+			return nil, errors.BadRequest(reason.AccessTokenScopeInvalid)
+		}
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	info := &entity.AccessToken{
+		UserID:      req.UserID,
+		Name:        req.Name,
+		TokenHash:   hashToken(token),
+		TokenPrefix: token[:tokenPrefixShownLength],
+		Scopes:      strings.Join(scopes, ","),
+	}
+	if req.ExpiresInDays > 0 {
+		info.ExpiredAt = time.Now().AddDate(0, 0, req.ExpiresInDays)
+	}
+	if err = as.accessTokenRepo.AddAccessToken(ctx, info); err != nil {
+		return nil, err
+	}
+	return &schema.AddAccessTokenResp{ID: info.ID, Token: token}, nil
+}
+
+// GetAccessTokenList get the personal access tokens of user
+func (as *AccessTokenService) GetAccessTokenList(ctx context.Context, userID string) (
+	resp []*schema.GetAccessTokenResp, err error) {
+	tokens, err := as.accessTokenRepo.GetUserAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.GetAccessTokenResp, 0, len(tokens))
+	for _, token := range tokens {
+		resp = append(resp, &schema.GetAccessTokenResp{
+			ID:          token.ID,
+			Name:        token.Name,
+			TokenPrefix: token.TokenPrefix,
+			Scopes:      parseScopes(token.Scopes),
+			CreatedAt:   token.CreatedAt.Unix(),
+			ExpiredAt:   unixOrZero(token.ExpiredAt),
+			LastUsedAt:  unixOrZero(token.LastUsedAt),
+		})
+	}
+	return resp, nil
+}
+
+// RemoveAccessToken revoke the personal access token of user
+func (as *AccessTokenService) RemoveAccessToken(ctx context.Context, req *schema.RemoveAccessTokenReq) (err error) {
+	token, exist, err := as.accessTokenRepo.GetAccessToken(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist || token.UserID != req.UserID {
+		return errors.BadRequest(reason.AccessTokenNotFound)
+	}
+	return as.accessTokenRepo.RemoveAccessToken(ctx, req.ID)
+}
+
+// AdminRemoveAccessToken admin revoke the personal access token of any user
+func (as *AccessTokenService) AdminRemoveAccessToken(ctx context.Context, req *schema.RemoveAccessTokenReq) (err error) {
+	_, exist, err := as.accessTokenRepo.GetAccessToken(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.AccessTokenNotFound)
+	}
+	return as.accessTokenRepo.RemoveAccessToken(ctx, req.ID)
+}
+
+// Authenticate get the user of personal access token and the scopes of token.
+// The user info is loaded from the database, so the change of role or status takes effect immediately.
+func (as *AccessTokenService) Authenticate(ctx context.Context, token string) (
+	userInfo *entity.UserCacheInfo, scopes []string, err error) {
+	info, exist, err := as.accessTokenRepo.GetByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if !exist || (!info.ExpiredAt.IsZero() && info.ExpiredAt.Before(now)) {
+		return nil, nil, errors.Unauthorized(reason.UnauthorizedError)
+	}
+	user, exist, err := as.userRepo.GetByUserID(ctx, info.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the tokens of the suspended or deleted user stop working, they work again if the user is recovered
+	if !exist || user.Status == entity.UserStatusDeleted {
+		return nil, nil, errors.Unauthorized(reason.UnauthorizedError)
+	}
+	if user.Status != entity.UserStatusAvailable {
+		return nil, nil, errors.Forbidden(reason.UserSuspended)
+	}
+	roleID, err := as.userRoleService.GetUserRole(ctx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if now.Sub(info.LastUsedAt) > lastUsedUpdateInterval {
+		if err = as.accessTokenRepo.UpdateLastUsedAt(ctx, info.ID, now); err != nil {
+			log.Error(err)
+		}
+	}
+	userInfo = &entity.UserCacheInfo{
+		UserID:      user.ID,
+		UserStatus:  user.Status,
+		EmailStatus: user.MailStatus,
+		IsAdmin:     roleID == role.RoleAdminID,
+	}
+	return userInfo, parseScopes(info.Scopes), nil
+}
+
+// IsAccessToken whether the token is a personal access token
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, TokenPrefix)
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return TokenPrefix + hex.EncodeToString(b), nil
+}
+
+// hashToken the token is random enough to be hashed without salt, so it can be found by the hash
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func uniqueScopes(scopes []string) (unique []string) {
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	return unique
+}
+
+func parseScopes(scopes string) []string {
+	if len(scopes) == 0 {
+		return make([]string, 0)
+	}
+	return strings.Split(scopes, ",")
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,74 @@
+package access_token
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/role"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAccessTokenRepo struct {
+	AccessTokenRepo
+	tokens map[string]*entity.AccessToken
+}
+
+func (f *fakeAccessTokenRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*entity.AccessToken, bool, error) {
+	token, ok := f.tokens[tokenHash]
+	return token, ok, nil
+}
+
+func (f *fakeAccessTokenRepo) UpdateLastUsedAt(ctx context.Context, id string, lastUsedAt time.Time) error {
+	return nil
+}
+
+type fakeUserRepo struct {
+	usercommon.UserRepo
+	users map[string]*entity.User
+}
+
+func (f *fakeUserRepo) GetByUserID(ctx context.Context, userID string) (*entity.User, bool, error) {
+	user, ok := f.users[userID]
+	return user, ok, nil
+}
+
+type fakeUserRoleRelRepo struct {
+	role.UserRoleRelRepo
+}
+
+func (f *fakeUserRoleRelRepo) GetUserRoleRel(ctx context.Context, userID string) (*entity.UserRoleRel, bool, error) {
+	return nil, false, nil
+}
+
+func TestAccessTokenService_Authenticate(t *testing.T) {
+	userRepo := &fakeUserRepo{users: map[string]*entity.User{
+		"1": {ID: "1", Status: entity.UserStatusAvailable, MailStatus: entity.EmailStatusAvailable},
+	}}
+	tokenRepo := &fakeAccessTokenRepo{tokens: map[string]*entity.AccessToken{
+		hashToken(TokenPrefix + "active"): {ID: "1", UserID: "1", Scopes: schema.AccessTokenScopeRead},
+	}}
+	as := NewAccessTokenService(tokenRepo, userRepo, role.NewUserRoleRelService(&fakeUserRoleRelRepo{}, nil))
+
+	userInfo, scopes, err := as.Authenticate(context.TODO(), TokenPrefix+"active")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", userInfo.UserID)
+	assert.Equal(t, []string{schema.AccessTokenScopeRead}, scopes)
+
+	_, _, err = as.Authenticate(context.TODO(), TokenPrefix+"unknown")
+	assert.Equal(t, errors.Unauthorized(reason.UnauthorizedError), err)
+
+	// the token stops working once the user is suspended or deleted
+	userRepo.users["1"].Status = entity.UserStatusSuspended
+	_, _, err = as.Authenticate(context.TODO(), TokenPrefix+"active")
+	assert.Equal(t, errors.Forbidden(reason.UserSuspended), err)
+
+	userRepo.users["1"].Status = entity.UserStatusDeleted
+	_, _, err = as.Authenticate(context.TODO(), TokenPrefix+"active")
+	assert.Equal(t, errors.Unauthorized(reason.UnauthorizedError), err)
+}
@@ -0,0 +1,55 @@
+package access_token
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/answerdev/answer/internal/schema"
+)
+
+const (
+	apiPathPrefix      = "/answer/api/v1"
+	adminAPIPathPrefix = "/answer/admin/api"
+)
+
+// writeScopeRules the scopes which allow to change the resources under the path of API,
+// the changes which are not listed here can not be made by the personal access tokens.
+var writeScopeRules = []struct {
+	path   string
+	scopes []string
+}{
+	{path: "/question", scopes: []string{schema.AccessTokenScopeWriteQuestion}},
+	{path: "/answer", scopes: []string{schema.AccessTokenScopeWriteAnswer}},
+	{path: "/comment", scopes: []string{schema.AccessTokenScopeWriteQuestion, schema.AccessTokenScopeWriteAnswer}},
+	{path: "/vote", scopes: []string{schema.AccessTokenScopeWriteQuestion, schema.AccessTokenScopeWriteAnswer}},
+	{path: "/file", scopes: []string{schema.AccessTokenScopeWriteQuestion, schema.AccessTokenScopeWriteAnswer}},
+}
+
+// ScopeAllowed whether the request can be made with the scopes of token. The admin API requires the admin scope,
+// the other read requests require the read scope and the write requests require the scope of resource.
+func ScopeAllowed(scopes []string, method, path string) bool {
+	if strings.HasPrefix(path, adminAPIPathPrefix+"/") {
+		return hasAnyScope(scopes, schema.AccessTokenScopeAdmin)
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return hasAnyScope(scopes, schema.AccessTokenScopeRead)
+	}
+	path = strings.TrimPrefix(path, apiPathPrefix)
+	for _, rule := range writeScopeRules {
+		if path == rule.path || strings.HasPrefix(path, rule.path+"/") {
+			return hasAnyScope(scopes, rule.scopes...)
+		}
+	}
+	return false
+}
+
+func hasAnyScope(scopes []string, expected ...string) bool {
+	for _, scope := range scopes {
+		for _, e := range expected {
+			if scope == e {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package access_token
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeAllowed(t *testing.T) {
+	read := []string{schema.AccessTokenScopeRead}
+	question := []string{schema.AccessTokenScopeWriteQuestion}
+	answer := []string{schema.AccessTokenScopeWriteAnswer}
+	admin := []string{schema.AccessTokenScopeAdmin}
+
+	assert.True(t, ScopeAllowed(read, http.MethodGet, "/answer/api/v1/question/page"))
+	assert.False(t, ScopeAllowed(question, http.MethodGet, "/answer/api/v1/question/page"))
+
+	assert.True(t, ScopeAllowed(question, http.MethodPost, "/answer/api/v1/question"))
+	assert.True(t, ScopeAllowed(question, http.MethodPut, "/answer/api/v1/question/status"))
+	assert.False(t, ScopeAllowed(answer, http.MethodPost, "/answer/api/v1/question"))
+	assert.False(t, ScopeAllowed(read, http.MethodPost, "/answer/api/v1/question"))
+	assert.True(t, ScopeAllowed(answer, http.MethodPost, "/answer/api/v1/answer/acceptance"))
+	assert.True(t, ScopeAllowed(answer, http.MethodPost, "/answer/api/v1/comment"))
+	assert.True(t, ScopeAllowed(question, http.MethodPost, "/answer/api/v1/vote/up"))
+	// the path must match the whole segment
+	assert.False(t, ScopeAllowed(question, http.MethodPost, "/answer/api/v1/questions"))
+
+	// the changes of account can not be made by tokens
+	all := []string{schema.AccessTokenScopeRead, schema.AccessTokenScopeWriteQuestion,
+		schema.AccessTokenScopeWriteAnswer, schema.AccessTokenScopeAdmin}
+	assert.False(t, ScopeAllowed(all, http.MethodPut, "/answer/api/v1/user/password"))
+	assert.False(t, ScopeAllowed(all, http.MethodPost, "/answer/api/v1/user/access-token"))
+
+	assert.True(t, ScopeAllowed(admin, http.MethodGet, "/answer/admin/api/users/page"))
+	assert.True(t, ScopeAllowed(admin, http.MethodPut, "/answer/admin/api/user/status"))
+	assert.False(t, ScopeAllowed(read, http.MethodGet, "/answer/admin/api/users/page"))
+}
+
+func TestHashToken(t *testing.T) {
+	token, err := generateToken()
+	assert.NoError(t, err)
+	assert.True(t, IsAccessToken(token))
+	assert.Len(t, hashToken(token), 64)
+	assert.Equal(t, hashToken(token), hashToken(token))
+	assert.False(t, IsAccessToken("2a4bd6a0-6dd5-11ed-a1eb-0242ac120002"))
+}
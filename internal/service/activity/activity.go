@@ -110,6 +110,19 @@ func (as *ActivityService) GetObjectTimeline(ctx context.Context, req *schema.Ge
 		}
 
 		item.Comment = as.getTimelineActivityComment(ctx, item.ObjectID, item.ObjectType, item.ActivityType, item.RevisionID)
+		// show the expiry of the lock or protection, it is empty if it never expires
+		if (item.ActivityType == constant.ActLocked || item.ActivityType == constant.ActProtected) &&
+			act.ExpiredAt.Unix() > 0 {
+			item.Comment = converter.IntToString(act.ExpiredAt.Unix())
+		}
+		// show the amount of bounty, the offered amount is saved as the negative rank
+		if item.ActivityType == constant.ActBounty || item.ActivityType == constant.ActBountyAwarded {
+			amount := act.Rank
+			if amount < 0 {
+				amount = -amount
+			}
+			item.Comment = converter.IntToString(int64(amount))
+		}
 		resp.Timeline = append(resp.Timeline, item)
 	}
 	as.formatTimelineUserInfo(ctx, resp.Timeline)
@@ -2,10 +2,13 @@ package activity_common
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
 	"github.com/answerdev/answer/internal/service/activity_queue"
+	"github.com/answerdev/answer/internal/service/job_queue"
 	"github.com/answerdev/answer/pkg/converter"
 	"github.com/segmentfault/pacman/log"
 	"xorm.io/xorm"
@@ -32,45 +35,45 @@ type ActivityCommon struct {
 // NewActivityCommon new activity common
 func NewActivityCommon(
 	activityRepo ActivityRepo,
+	queue job_queue.Queue,
 ) *ActivityCommon {
 	activity := &ActivityCommon{
 		activityRepo: activityRepo,
 	}
-	activity.HandleActivity()
+	queue.Subscribe(activity_queue.Topic, activity.HandleActivity)
 	return activity
 }
 
 // HandleActivity handle activity message
-func (ac *ActivityCommon) HandleActivity() {
-	go func() {
-		defer func() {
-			if err := recover(); err != nil {
-				log.Error(err)
-			}
-		}()
-
-		for msg := range activity_queue.ActivityQueue {
-			log.Debugf("received activity %+v", msg)
+func (ac *ActivityCommon) HandleActivity(ctx context.Context, payload []byte) error {
+	msg := &schema.ActivityMsg{}
+	if err := json.Unmarshal(payload, msg); err != nil {
+		log.Errorf("decode activity message failed: %s", err)
+		return nil
+	}
+	log.Debugf("received activity %+v", msg)
 
-			activityType, err := ac.activityRepo.GetActivityTypeByConfigKey(context.Background(), string(msg.ActivityTypeKey))
-			if err != nil {
-				log.Errorf("error getting activity type %s, activity type is %d", err, activityType)
-			}
+	activityType, err := ac.activityRepo.GetActivityTypeByConfigKey(ctx, string(msg.ActivityTypeKey))
+	if err != nil {
+		log.Errorf("error getting activity type %s, activity type is %d", err, activityType)
+	}
 
-			act := &entity.Activity{
-				UserID:           msg.UserID,
-				TriggerUserID:    msg.TriggerUserID,
-				ObjectID:         msg.ObjectID,
-				OriginalObjectID: msg.OriginalObjectID,
-				ActivityType:     activityType,
-				Cancelled:        entity.ActivityAvailable,
-			}
-			if len(msg.RevisionID) > 0 {
-				act.RevisionID = converter.StringToInt64(msg.RevisionID)
-			}
-			if err := ac.activityRepo.AddActivity(context.TODO(), act); err != nil {
-				log.Error(err)
-			}
-		}
-	}()
+	act := &entity.Activity{
+		UserID:           msg.UserID,
+		TriggerUserID:    msg.TriggerUserID,
+		ObjectID:         msg.ObjectID,
+		OriginalObjectID: msg.OriginalObjectID,
+		ActivityType:     activityType,
+		Cancelled:        entity.ActivityAvailable,
+	}
+	if len(msg.RevisionID) > 0 {
+		act.RevisionID = converter.StringToInt64(msg.RevisionID)
+	}
+	if msg.ExpiredAt > 0 {
+		act.ExpiredAt = time.Unix(msg.ExpiredAt, 0)
+	}
+	if len(msg.DedupKey) > 0 {
+		act.DedupKey = &msg.DedupKey
+	}
+	return ac.activityRepo.AddActivity(ctx, act)
 }
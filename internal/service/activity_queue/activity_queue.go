@@ -2,13 +2,19 @@ package activity_queue
 
 import (
 	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/job_queue"
+	"github.com/answerdev/answer/internal/service/webhook_queue"
+	"github.com/answerdev/answer/pkg/token"
 )
 
-var (
-	ActivityQueue = make(chan *schema.ActivityMsg, 128)
-)
+// Topic the topic of activity message
+const Topic = "activity"
 
-// AddActivity add new activity
+// AddActivity add new activity, the activity is also the event of webhooks
 func AddActivity(msg *schema.ActivityMsg) {
-	ActivityQueue <- msg
+	if len(msg.DedupKey) == 0 {
+		msg.DedupKey = token.GenerateToken()
+	}
+	job_queue.Publish(Topic, msg)
+	webhook_queue.AddEvent(msg)
 }
@@ -66,6 +66,7 @@ func (as *AnswerCommon) ShowFormat(ctx context.Context, data *entity.Answer) *sc
 	info.HTML = data.ParsedText
 	info.Accepted = data.Accepted
 	info.VoteCount = data.VoteCount
+	info.Status = data.Status
 	info.CreateTime = data.CreatedAt.Unix()
 	info.UpdateTime = data.UpdatedAt.Unix()
 	if data.UpdatedAt.Unix() < 1 {
@@ -73,6 +74,10 @@ func (as *AnswerCommon) ShowFormat(ctx context.Context, data *entity.Answer) *sc
 	}
 	info.UserID = data.UserID
 	info.UpdateUserID = data.LastEditUserID
+	info.Locked = data.IsLocked()
+	if info.Locked && data.LockExpiredAt.Unix() > 0 {
+		info.LockExpiredAt = data.LockExpiredAt.Unix()
+	}
 	return &info
 }
 
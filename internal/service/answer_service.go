@@ -14,13 +14,22 @@ import (
 	"github.com/answerdev/answer/internal/service/activity_common"
 	"github.com/answerdev/answer/internal/service/activity_queue"
 	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/answerdev/answer/internal/service/badge_queue"
 	collectioncommon "github.com/answerdev/answer/internal/service/collection_common"
+	"github.com/answerdev/answer/internal/service/email_digest"
 	"github.com/answerdev/answer/internal/service/export"
 	"github.com/answerdev/answer/internal/service/notice_queue"
 	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/post_lock"
+	"github.com/answerdev/answer/internal/service/pre_moderation"
 	questioncommon "github.com/answerdev/answer/internal/service/question_common"
 	"github.com/answerdev/answer/internal/service/revision_common"
+	"github.com/answerdev/answer/internal/service/search_queue"
+	"github.com/answerdev/answer/internal/service/spam"
+	"github.com/answerdev/answer/internal/service/stream"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/internal/service/webhook_queue"
 	"github.com/answerdev/answer/pkg/encryption"
 	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
@@ -39,6 +48,12 @@ type AnswerService struct {
 	AnswerCommon          *answercommon.AnswerCommon
 	voteRepo              activity_common.VoteRepo
 	emailService          *export.EmailService
+	emailDigestService    *email_digest.EmailDigestService
+	auditLogService       *audit_log.AuditLogService
+	preModerationService  *pre_moderation.PreModerationService
+	spamService           *spam.SpamService
+	postLockService       *post_lock.PostLockService
+	searchQueueService    *search_queue.SearchQueueService
 }
 
 func NewAnswerService(
@@ -53,6 +68,12 @@ func NewAnswerService(
 	answerCommon *answercommon.AnswerCommon,
 	voteRepo activity_common.VoteRepo,
 	emailService *export.EmailService,
+	emailDigestService *email_digest.EmailDigestService,
+	auditLogService *audit_log.AuditLogService,
+	preModerationService *pre_moderation.PreModerationService,
+	spamService *spam.SpamService,
+	postLockService *post_lock.PostLockService,
+	searchQueueService *search_queue.SearchQueueService,
 ) *AnswerService {
 	return &AnswerService{
 		answerRepo:            answerRepo,
@@ -66,6 +87,12 @@ func NewAnswerService(
 		AnswerCommon:          answerCommon,
 		voteRepo:              voteRepo,
 		emailService:          emailService,
+		emailDigestService:    emailDigestService,
+		auditLogService:       auditLogService,
+		preModerationService:  preModerationService,
+		spamService:           spamService,
+		postLockService:       postLockService,
+		searchQueueService:    searchQueueService,
 	}
 }
 
@@ -78,6 +105,10 @@ func (as *AnswerService) RemoveAnswer(ctx context.Context, req *schema.RemoveAns
 	if !exist {
 		return nil
 	}
+	// the answer waiting for review has not been published
+	if answerInfo.Status == entity.AnswerStatusPending && (req.IsAdmin || answerInfo.UserID == req.UserID) {
+		return as.answerRepo.RemoveAnswer(ctx, req.ID)
+	}
 	if !req.IsAdmin {
 		if answerInfo.UserID != req.UserID {
 			return errors.BadRequest(reason.AnswerCannotDeleted)
@@ -122,12 +153,14 @@ func (as *AnswerService) RemoveAnswer(ctx context.Context, req *schema.RemoveAns
 	if err != nil {
 		log.Errorf("delete answer activity change failed: %s", err.Error())
 	}
+	as.publishAnswerCount(ctx, answerInfo.QuestionID)
 	activity_queue.AddActivity(&schema.ActivityMsg{
 		UserID:           req.UserID,
 		ObjectID:         answerInfo.ID,
 		OriginalObjectID: answerInfo.ID,
 		ActivityTypeKey:  constant.ActAnswerDeleted,
 	})
+	as.searchQueueService.AddSearchSync(answerInfo.ID)
 	return
 }
 
@@ -136,9 +169,29 @@ func (as *AnswerService) Insert(ctx context.Context, req *schema.AnswerAddReq) (
 	if err != nil {
 		return "", err
 	}
-	if !exist {
+	// the question waiting for review can not be answered
+	if !exist || questionInfo.Status == entity.QuestionStatusPending {
 		return "", errors.BadRequest(reason.QuestionNotFound)
 	}
+	if err = as.postLockService.CheckCanAnswer(ctx, questionInfo, req.UserID); err != nil {
+		return "", err
+	}
+	verdict, err := as.spamService.Check(ctx, &spam.Content{
+		UserID:     req.UserID,
+		ObjectType: constant.AnswerObjectType,
+		HTML:       req.HTML,
+	})
+	if err != nil {
+		return "", err
+	}
+	if verdict.Rejected() {
+		return "", errors.BadRequest(reason.SpamContentRejected)
+	}
+	needReview, err := as.preModerationService.NeedReview(ctx, req.UserID)
+	if err != nil {
+		return "", err
+	}
+	needReview = needReview || verdict.NeedReview()
 	insertData := new(entity.Answer)
 	insertData.UserID = req.UserID
 	insertData.OriginalText = req.Content
@@ -148,61 +201,141 @@ func (as *AnswerService) Insert(ctx context.Context, req *schema.AnswerAddReq) (
 	insertData.RevisionID = "0"
 	insertData.LastEditUserID = "0"
 	insertData.Status = entity.AnswerStatusAvailable
+	if needReview {
+		insertData.Status = entity.AnswerStatusPending
+	}
 	//insertData.UpdatedAt = now
 	if err = as.answerRepo.AddAnswer(ctx, insertData); err != nil {
 		return "", err
 	}
-	err = as.questionCommon.UpdateAnswerCount(ctx, req.QuestionID, 1)
+
+	revisionDTO := &schema.AddRevisionDTO{
+		UserID:   insertData.UserID,
+		ObjectID: insertData.ID,
+		Title:    "",
+	}
+	infoJSON, _ := json.Marshal(insertData)
+	revisionDTO.Content = string(infoJSON)
+	revisionID, err := as.revisionService.AddRevision(ctx, revisionDTO, true)
+	if err != nil {
+		return insertData.ID, err
+	}
+	if needReview {
+		err = as.preModerationService.AddPendingPost(ctx, insertData.UserID, insertData.ID,
+			&schema.PendingPostExtra{SpamReason: verdict.String()})
+		if err != nil {
+			return insertData.ID, err
+		}
+		return insertData.ID, nil
+	}
+	if err = as.publishAnswer(ctx, insertData, questionInfo, revisionID); err != nil {
+		return insertData.ID, err
+	}
+	return insertData.ID, nil
+}
+
+// publishAnswer update the counts of the question and the user, send the notifications and add the activities
+// of the new answer
+func (as *AnswerService) publishAnswer(ctx context.Context, answerInfo *entity.Answer, questionInfo *entity.Question,
+	revisionID string) (err error) {
+	err = as.questionCommon.UpdateAnswerCount(ctx, questionInfo.ID, 1)
 	if err != nil {
 		log.Error("IncreaseAnswerCount error", err.Error())
 	}
-	err = as.questionCommon.UpdateLastAnswer(ctx, req.QuestionID, insertData.ID)
+	err = as.questionCommon.UpdateLastAnswer(ctx, questionInfo.ID, answerInfo.ID)
 	if err != nil {
 		log.Error("UpdateLastAnswer error", err.Error())
 	}
-	err = as.questionCommon.UpdataPostTime(ctx, req.QuestionID)
+	err = as.questionCommon.UpdataPostTime(ctx, questionInfo.ID)
 	if err != nil {
-		return insertData.ID, err
+		return err
 	}
 
-	err = as.userCommon.UpdateAnswerCount(ctx, req.UserID, 1)
+	err = as.userCommon.UpdateAnswerCount(ctx, answerInfo.UserID, 1)
 	if err != nil {
 		log.Error("user IncreaseAnswerCount error", err.Error())
 	}
 
-	revisionDTO := &schema.AddRevisionDTO{
-		UserID:   insertData.UserID,
-		ObjectID: insertData.ID,
-		Title:    "",
-	}
-	infoJSON, _ := json.Marshal(insertData)
-	revisionDTO.Content = string(infoJSON)
-	revisionID, err := as.revisionService.AddRevision(ctx, revisionDTO, true)
-	if err != nil {
-		return insertData.ID, err
-	}
-	as.notificationAnswerTheQuestion(ctx, questionInfo.UserID, questionInfo.ID, insertData.ID, req.UserID, questionInfo.Title,
-		insertData.OriginalText)
+	as.notificationAnswerTheQuestion(ctx, questionInfo.UserID, questionInfo.ID, answerInfo.ID, answerInfo.UserID,
+		questionInfo.Title, answerInfo.OriginalText)
 
 	activity_queue.AddActivity(&schema.ActivityMsg{
-		UserID:           insertData.UserID,
-		ObjectID:         insertData.ID,
-		OriginalObjectID: insertData.ID,
+		UserID:           answerInfo.UserID,
+		ObjectID:         answerInfo.ID,
+		OriginalObjectID: answerInfo.ID,
 		ActivityTypeKey:  constant.ActAnswerAnswered,
 		RevisionID:       revisionID,
 	})
 	activity_queue.AddActivity(&schema.ActivityMsg{
-		UserID:           insertData.UserID,
-		ObjectID:         insertData.ID,
+		UserID:           answerInfo.UserID,
+		ObjectID:         answerInfo.ID,
 		OriginalObjectID: questionInfo.ID,
 		ActivityTypeKey:  constant.ActQuestionAnswered,
 	})
-	return insertData.ID, nil
+	as.searchQueueService.AddSearchSync(answerInfo.ID)
+	as.publishAnswerCount(ctx, questionInfo.ID)
+	return nil
+}
+
+// PublishPendingAnswer publish the answer approved by the reviewer
+func (as *AnswerService) PublishPendingAnswer(ctx context.Context, answerID string) (err error) {
+	answerInfo, exist, err := as.answerRepo.GetByID(ctx, answerID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.AnswerNotFound)
+	}
+	questionInfo, exist, err := as.questionRepo.GetQuestion(ctx, answerInfo.QuestionID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.QuestionNotFound)
+	}
+	answerInfo.Status = entity.AnswerStatusAvailable
+	if err = as.answerRepo.UpdateAnswerStatus(ctx, answerInfo); err != nil {
+		return err
+	}
+	return as.publishAnswer(ctx, answerInfo, questionInfo, answerInfo.RevisionID)
+}
+
+// RejectPendingAnswer discard the answer rejected by the reviewer
+func (as *AnswerService) RejectPendingAnswer(ctx context.Context, answerID string) (err error) {
+	answerInfo, exist, err := as.answerRepo.GetByID(ctx, answerID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.AnswerNotFound)
+	}
+	answerInfo.Status = entity.AnswerStatusDeleted
+	return as.answerRepo.UpdateAnswerStatus(ctx, answerInfo)
+}
+
+// publishAnswerCount push the answer count to the users viewing the question and resync it to the search engine
+func (as *AnswerService) publishAnswerCount(ctx context.Context, questionID string) {
+	as.searchQueueService.AddSearchSync(questionID)
+	questionInfo, exist, err := as.questionRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if !exist {
+		return
+	}
+	stream.Publish(ctx, stream.QuestionKey(questionID), stream.EventAnswerCount, &schema.NotificationStreamAnswerCount{
+		QuestionID:  questionID,
+		AnswerCount: questionInfo.AnswerCount,
+	})
 }
 
 func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq) (string, error) {
 	//req.NoNeedReview //true 不需要审核
 	var canUpdate bool
+	if err := as.postLockService.CheckPostLocked(ctx, req.ID, req.UserID); err != nil {
+		return "", err
+	}
 	_, existUnreviewed, err := as.revisionService.ExistUnreviewedByObjectID(ctx, req.ID)
 	if err != nil {
 		return "", err
@@ -232,6 +365,17 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 	if answerInfo.OriginalText == req.Content {
 		return "", nil
 	}
+	verdict, err := as.spamService.Check(ctx, &spam.Content{
+		UserID:     req.UserID,
+		ObjectType: constant.AnswerObjectType,
+		HTML:       req.HTML,
+	})
+	if err != nil {
+		return "", err
+	}
+	if verdict.Rejected() {
+		return "", errors.BadRequest(reason.SpamContentRejected)
+	}
 
 	now := time.Now()
 	insertData := new(entity.Answer)
@@ -254,7 +398,8 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 		Log:      req.EditSummary,
 	}
 
-	if req.NoNeedReview || answerInfo.UserID == req.UserID {
+	// the edit that looks like spam is kept as an unreviewed revision
+	if (req.NoNeedReview || answerInfo.UserID == req.UserID) && !verdict.NeedReview() {
 		canUpdate = true
 	}
 
@@ -286,6 +431,7 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 			ActivityTypeKey:  constant.ActAnswerEdited,
 			RevisionID:       revisionID,
 		})
+		as.searchQueueService.AddSearchSync(insertData.ID)
 	}
 
 	return insertData.ID, nil
@@ -309,7 +455,9 @@ func (as *AnswerService) UpdateAccepted(ctx context.Context, req *schema.AnswerA
 		if err != nil {
 			return err
 		}
-		if !newAnswerInfoexist {
+		// only the published answer of this question can be accepted
+		if !newAnswerInfoexist || newAnswerInfo.Status != entity.AnswerStatusAvailable ||
+			newAnswerInfo.QuestionID != req.QuestionID {
 			return errors.BadRequest(reason.AnswerNotFound)
 		}
 	}
@@ -324,6 +472,14 @@ func (as *AnswerService) UpdateAccepted(ctx context.Context, req *schema.AnswerA
 	if questionInfo.UserID != req.UserID {
 		return fmt.Errorf("no permission to set answer")
 	}
+	// the locked answer can not be accepted, and the accepted answer of the locked question can not be changed
+	lockObjectID := questionInfo.ID
+	if newAnswerInfoexist {
+		lockObjectID = newAnswerInfo.ID
+	}
+	if err = as.postLockService.CheckPostLocked(ctx, lockObjectID, req.UserID); err != nil {
+		return err
+	}
 	if questionInfo.AcceptedAnswerID == req.AnswerID {
 		return nil
 	}
@@ -347,6 +503,22 @@ func (as *AnswerService) UpdateAccepted(ctx context.Context, req *schema.AnswerA
 	}
 
 	as.updateAnswerRank(ctx, req.UserID, questionInfo, newAnswerInfo, oldAnswerInfo)
+	// question and all its answers accepted status changed
+	as.searchQueueService.AddSearchSync(questionInfo.ID)
+	// the accept activity is recorded by updateAnswerRank, so only the webhook event is sent here
+	if newAnswerInfo.ID != "" {
+		webhook_queue.AddEvent(&schema.ActivityMsg{
+			UserID:           req.UserID,
+			ObjectID:         newAnswerInfo.ID,
+			OriginalObjectID: questionInfo.ID,
+			ActivityTypeKey:  constant.ActAnswerAccept,
+		})
+		badge_queue.AddEvent(&schema.BadgeEventMsg{
+			Event:    schema.BadgeEventAnswerAccepted,
+			UserID:   newAnswerInfo.UserID,
+			ObjectID: newAnswerInfo.ID,
+		})
+	}
 	return nil
 }
 
@@ -375,9 +547,13 @@ func (as *AnswerService) Get(ctx context.Context, answerID, loginUserID string)
 	if err != nil {
 		return nil, nil, has, err
 	}
+	// the answer waiting for review is only visible to its author
+	if answerInfo.Status == entity.AnswerStatusPending && answerInfo.UserID != loginUserID {
+		return nil, nil, false, nil
+	}
 	info := as.ShowFormat(ctx, answerInfo)
 	// todo questionFunc
-	questionInfo, err := as.questionCommon.Info(ctx, answerInfo.QuestionID, loginUserID)
+	questionInfo, err := as.questionCommon.Info(ctx, answerInfo.QuestionID, loginUserID, false)
 	if err != nil {
 		return nil, nil, has, err
 	}
@@ -430,11 +606,22 @@ func (as *AnswerService) AdminSetAnswerStatus(ctx context.Context, req *schema.A
 	if !exist {
 		return fmt.Errorf("answer does not exist")
 	}
+	oldStatus := answerInfo.Status
 	answerInfo.Status = setStatus
 	err = as.answerRepo.UpdateAnswerStatus(ctx, answerInfo)
 	if err != nil {
 		return err
 	}
+	as.searchQueueService.AddSearchSync(answerInfo.ID)
+	as.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     req.UserID,
+		Action:     entity.AuditActionAnswerStatus,
+		ObjectType: constant.AnswerObjectType,
+		ObjectID:   answerInfo.ID,
+		Before:     &schema.AuditLogStatusSnapshot{Status: entity.AdminAnswerSearchStatusIntToString[oldStatus]},
+		After:      &schema.AuditLogStatusSnapshot{Status: req.StatusStr},
+		Reason:     req.Reason,
+	})
 
 	if setStatus == entity.AnswerStatusDeleted {
 		err = as.answerActivityService.DeleteAnswer(ctx, answerInfo.ID, answerInfo.CreatedAt, answerInfo.VoteCount)
@@ -588,6 +775,18 @@ func (as *AnswerService) notificationAnswerTheQuestion(ctx context.Context,
 	if answerUser != nil {
 		rawData.AnswerUserDisplayName = answerUser.DisplayName
 	}
+	sendNow := as.emailDigestService.Schedule(ctx, &entity.EmailDigestItem{
+		UserID:             userInfo.ID,
+		NoticeType:         schema.NoticeTypeNewAnswer,
+		QuestionID:         questionID,
+		AnswerID:           answerID,
+		QuestionTitle:      questionTitle,
+		TriggerDisplayName: rawData.AnswerUserDisplayName,
+		Summary:            answerSummary,
+	})
+	if !sendNow {
+		return
+	}
 	codeContent := &schema.EmailCodeContent{
 		SourceType: schema.UnsubscribeSourceType,
 		Email:      userInfo.EMail,
@@ -600,6 +799,8 @@ func (as *AnswerService) notificationAnswerTheQuestion(ctx context.Context,
 		return
 	}
 
-	go as.emailService.SendAndSaveCodeWithTime(
-		ctx, userInfo.EMail, title, body, rawData.UnsubscribeCode, codeContent.ToJSONString(), 7*24*time.Hour)
+	// the reply of email becomes a comment on the answer
+	replyTo := as.emailService.ReplyAddress(userInfo.ID, answerID)
+	go as.emailService.SendAndSaveCodeWithReplyTo(ctx, userInfo.EMail, title, body, replyTo,
+		rawData.UnsubscribeCode, codeContent.ToJSONString(), 7*24*time.Hour)
 }
@@ -0,0 +1,248 @@
+package audit_log
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/pager"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/log"
+)
+
+// ExportLimit the max number of audit logs in one export, the rest are exported by the next pages
+const ExportLimit = 10000
+
+// maskedValue replace the sensitive value in the snapshots
+const maskedValue = "******"
+
+// sensitiveKeys the snapshot field whose name contains any of them is masked
+var sensitiveKeys = []string{"password", "secret", "private_key", "token"}
+
+// AuditLogRepo audit log repository
+type AuditLogRepo interface {
+	AddAuditLog(ctx context.Context, auditLog *entity.AuditLog) (err error)
+	GetAuditLogPage(ctx context.Context, page, pageSize int, cond *entity.AuditLog,
+		startTime, endTime time.Time) (logs []*entity.AuditLog, total int64, err error)
+}
+
+// AuditLogService audit log service
+type AuditLogService struct {
+	auditLogRepo      AuditLogRepo
+	userCommonService *usercommon.UserCommon
+}
+
+// NewAuditLogService new audit log service
+func NewAuditLogService(
+	auditLogRepo AuditLogRepo,
+	userCommonService *usercommon.UserCommon,
+) *AuditLogService {
+	return &AuditLogService{
+		auditLogRepo:      auditLogRepo,
+		userCommonService: userCommonService,
+	}
+}
+
+// AddAuditLog record the privileged action. The action is already done, so the error is only logged.
+func (as *AuditLogService) AddAuditLog(ctx context.Context, req *schema.AddAuditLogReq) {
+	auditLog := &entity.AuditLog{
+		UserID:     req.UserID,
+		Action:     req.Action,
+		ObjectType: req.ObjectType,
+		ObjectID:   req.ObjectID,
+		Before:     snapshot(req.Before),
+		After:      snapshot(req.After),
+		Reason:     req.Reason,
+	}
+	if len(auditLog.UserID) == 0 {
+		auditLog.UserID, _ = ctx.Value(constant.LoginUserIDFlag).(string)
+	}
+	auditLog.IP, _ = ctx.Value(constant.ClientIPFlag).(string)
+	if err := as.auditLogRepo.AddAuditLog(ctx, auditLog); err != nil {
+		log.Errorf("add audit log of %s %s failed: %v", req.Action, req.ObjectID, err)
+	}
+}
+
+// GetAuditLogPage get audit log page
+func (as *AuditLogService) GetAuditLogPage(ctx context.Context, req *schema.GetAuditLogPageReq) (
+	pageModel *pager.PageModel, err error) {
+	cond, ok, err := as.formatCond(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]*schema.GetAuditLogResp, 0)
+	if !ok {
+		return pager.NewPageModel(0, resp), nil
+	}
+
+	logs, total, err := as.auditLogRepo.GetAuditLogPage(ctx, req.Page, req.PageSize, cond,
+		unixTime(req.StartTime), unixTime(req.EndTime))
+	if err != nil {
+		return nil, err
+	}
+	resp, err = as.formatAuditLogs(ctx, logs)
+	if err != nil {
+		return nil, err
+	}
+	return pager.NewPageModel(total, resp), nil
+}
+
+// ExportAuditLog export the page of audit logs as csv, the latest first. Each page has at most ExportLimit logs,
+// hasMore is true if there are older logs in the next page.
+func (as *AuditLogService) ExportAuditLog(ctx context.Context, req *schema.GetAuditLogPageReq) (
+	content []byte, hasMore bool, err error) {
+	logs := make([]*entity.AuditLog, 0)
+	cond, ok, err := as.formatCond(ctx, req)
+	if err != nil {
+		return nil, false, err
+	}
+	if ok {
+		page, _ := pager.ValPageAndPageSize(req.Page, ExportLimit)
+		var total int64
+		logs, total, err = as.auditLogRepo.GetAuditLogPage(ctx, page, ExportLimit, cond,
+			unixTime(req.StartTime), unixTime(req.EndTime))
+		if err != nil {
+			return nil, false, err
+		}
+		hasMore = total > int64(page*ExportLimit)
+	}
+	resp, err := as.formatAuditLogs(ctx, logs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	_ = w.Write([]string{"id", "created_at", "user_id", "username", "action", "object_type", "object_id",
+		"ip", "reason", "before", "after"})
+	for _, item := range resp {
+		var userID, username string
+		if item.UserInfo != nil {
+			userID, username = item.UserInfo.ID, item.UserInfo.Username
+		}
+		record := []string{item.ID, time.Unix(item.CreatedAt, 0).UTC().Format(time.RFC3339), userID, username,
+			item.Action, item.ObjectType, item.ObjectID, item.IP, item.Reason, item.Before, item.After}
+		for i := range record {
+			record[i] = escapeCSVFormula(record[i])
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	return buf.Bytes(), hasMore, w.Error()
+}
+
+// formatCond convert the request to the condition, ok is false if the user of username does not exist
+func (as *AuditLogService) formatCond(ctx context.Context, req *schema.GetAuditLogPageReq) (
+	cond *entity.AuditLog, ok bool, err error) {
+	cond = &entity.AuditLog{
+		Action:     req.Action,
+		ObjectType: req.ObjectType,
+		ObjectID:   req.ObjectID,
+	}
+	if len(req.Username) > 0 {
+		userInfo, exist, err := as.userCommonService.GetUserBasicInfoByUserName(ctx, req.Username)
+		if err != nil || !exist {
+			return nil, false, err
+		}
+		cond.UserID = userInfo.ID
+	}
+	return cond, true, nil
+}
+
+func (as *AuditLogService) formatAuditLogs(ctx context.Context, logs []*entity.AuditLog) (
+	resp []*schema.GetAuditLogResp, err error) {
+	userIDs := make([]string, 0)
+	for _, item := range logs {
+		userIDs = append(userIDs, item.UserID)
+	}
+	users, err := as.userCommonService.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.GetAuditLogResp, 0, len(logs))
+	for _, item := range logs {
+		resp = append(resp, &schema.GetAuditLogResp{
+			ID:         item.ID,
+			CreatedAt:  item.CreatedAt.Unix(),
+			UserInfo:   users[item.UserID],
+			Action:     item.Action,
+			ObjectType: item.ObjectType,
+			ObjectID:   item.ObjectID,
+			Before:     item.Before,
+			After:      item.After,
+			IP:         item.IP,
+			Reason:     item.Reason,
+		})
+	}
+	return resp, nil
+}
+
+// snapshot marshal the object to json with the sensitive fields masked
+func snapshot(obj interface{}) string {
+	if obj == nil {
+		return ""
+	}
+	content, err := json.Marshal(obj)
+	if err != nil {
+		log.Errorf("marshal audit log snapshot failed: %v", err)
+		return ""
+	}
+	var value interface{}
+	if err = json.Unmarshal(content, &value); err != nil {
+		return string(content)
+	}
+	content, _ = json.Marshal(maskSensitive(value))
+	return string(content)
+}
+
+func maskSensitive(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				if s, ok := item.(string); !ok || len(s) > 0 {
+					v[key] = maskedValue
+				}
+				continue
+			}
+			v[key] = maskSensitive(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = maskSensitive(item)
+		}
+	}
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitiveKey := range sensitiveKeys {
+		if strings.Contains(key, sensitiveKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeCSVFormula prevent the cell being executed as formula when the csv is opened by spreadsheet
+func escapeCSVFormula(cell string) string {
+	if len(cell) > 0 && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// unixTime the zero timestamp means the zero time
+func unixTime(timestamp int64) time.Time {
+	if timestamp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(timestamp, 0)
+}
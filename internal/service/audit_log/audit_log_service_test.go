@@ -0,0 +1,101 @@
+package audit_log
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAuditLogRepo struct {
+	AuditLogRepo
+	logs  []*entity.AuditLog
+	total int64
+}
+
+func (f *fakeAuditLogRepo) AddAuditLog(ctx context.Context, auditLog *entity.AuditLog) error {
+	f.logs = append(f.logs, auditLog)
+	return nil
+}
+
+func (f *fakeAuditLogRepo) GetAuditLogPage(ctx context.Context, page, pageSize int, cond *entity.AuditLog,
+	startTime, endTime time.Time) ([]*entity.AuditLog, int64, error) {
+	return f.logs, f.total, nil
+}
+
+type fakeUserRepo struct {
+	usercommon.UserRepo
+}
+
+func (f *fakeUserRepo) BatchGetByID(ctx context.Context, ids []string) ([]*entity.User, error) {
+	return []*entity.User{{ID: "1", Username: "admin", Status: entity.UserStatusAvailable}}, nil
+}
+
+func Test_snapshot(t *testing.T) {
+	assert.Equal(t, "", snapshot(nil))
+	assert.Equal(t, `{"status":"closed"}`, snapshot(&schema.AuditLogStatusSnapshot{Status: "closed"}))
+	// the sensitive fields are masked in the nested objects, the empty ones are kept
+	assert.Equal(t,
+		`{"bind_password":"","connectors":[{"client_secret":"******","name":"github"}],"smtp_password":"******"}`,
+		snapshot(map[string]interface{}{
+			"smtp_password": "pass",
+			"bind_password": "",
+			"connectors":    []map[string]string{{"name": "github", "client_secret": "secret"}},
+		}))
+}
+
+func TestAuditLogService_AddAuditLog(t *testing.T) {
+	repo := &fakeAuditLogRepo{}
+	as := NewAuditLogService(repo, nil)
+	ctx := context.WithValue(context.TODO(), constant.LoginUserIDFlag, "1")
+	ctx = context.WithValue(ctx, constant.ClientIPFlag, "127.0.0.1")
+
+	// the user in the context is used if the request has no user
+	as.AddAuditLog(ctx, &schema.AddAuditLogReq{Action: entity.AuditActionUserStatus, ObjectID: "2", Reason: "spam"})
+	as.AddAuditLog(ctx, &schema.AddAuditLogReq{UserID: "3", Action: entity.AuditActionUserRole, ObjectID: "2"})
+	assert.Len(t, repo.logs, 2)
+	assert.Equal(t, "1", repo.logs[0].UserID)
+	assert.Equal(t, "127.0.0.1", repo.logs[0].IP)
+	assert.Equal(t, "spam", repo.logs[0].Reason)
+	assert.Equal(t, "3", repo.logs[1].UserID)
+}
+
+func TestAuditLogService_ExportAuditLog(t *testing.T) {
+	repo := &fakeAuditLogRepo{logs: []*entity.AuditLog{{
+		ID:        "1",
+		CreatedAt: time.Unix(0, 0),
+		UserID:    "1",
+		Action:    entity.AuditActionQuestionStatus,
+		ObjectID:  "10010000000000001",
+		Reason:    "=HYPERLINK(\"http://example.com\")",
+	}}, total: 1}
+	as := NewAuditLogService(repo, usercommon.NewUserCommon(&fakeUserRepo{}))
+
+	content, hasMore, err := as.ExportAuditLog(context.TODO(), &schema.GetAuditLogPageReq{})
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+	records, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, []string{"1", "1970-01-01T00:00:00Z", "1", "admin", "question.status", "", "10010000000000001",
+		"", `'=HYPERLINK("http://example.com")`, "", ""}, records[1])
+}
+
+func TestAuditLogService_ExportAuditLogHasMore(t *testing.T) {
+	repo := &fakeAuditLogRepo{total: ExportLimit*2 + 1}
+	as := NewAuditLogService(repo, usercommon.NewUserCommon(&fakeUserRepo{}))
+
+	_, hasMore, err := as.ExportAuditLog(context.TODO(), &schema.GetAuditLogPageReq{Page: 2})
+	assert.NoError(t, err)
+	assert.True(t, hasMore)
+	_, hasMore, err = as.ExportAuditLog(context.TODO(), &schema.GetAuditLogPageReq{Page: 3})
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+}
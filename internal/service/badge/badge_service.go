@@ -0,0 +1,461 @@
+package badge
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/pager"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/answerdev/answer/internal/service/badge_queue"
+	"github.com/answerdev/answer/internal/service/job_queue"
+	"github.com/answerdev/answer/internal/service/notice_queue"
+	questioncommon "github.com/answerdev/answer/internal/service/question_common"
+	tagcommon "github.com/answerdev/answer/internal/service/tag_common"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/pkg/obj"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// BadgeRepo badge repository
+type BadgeRepo interface {
+	AddBadge(ctx context.Context, badge *entity.Badge) (err error)
+	UpdateBadge(ctx context.Context, badge *entity.Badge) (err error)
+	GetBadge(ctx context.Context, id string) (badge *entity.Badge, exist bool, err error)
+	GetBadgeList(ctx context.Context) (badges []*entity.Badge, err error)
+	// GetEnabledBadges get the enabled badges which are awarded by rule
+	GetEnabledBadges(ctx context.Context) (badges []*entity.Badge, err error)
+	GetBadgeAwardCounts(ctx context.Context) (counts map[string]int64, err error)
+	AddUserBadge(ctx context.Context, userBadge *entity.UserBadge) (err error)
+	// RevokeUserBadge revoke the badge awarded to user, the record is kept so the rule does not award it again
+	RevokeUserBadge(ctx context.Context, id string) (err error)
+	RestoreUserBadge(ctx context.Context, id, awardUserID string) (err error)
+	GetUserBadge(ctx context.Context, id string) (userBadge *entity.UserBadge, exist bool, err error)
+	// GetUserBadgeByObject get the badge awarded to user for the object, the revoked badge is included
+	GetUserBadgeByObject(ctx context.Context, userID, badgeID, objectID string) (
+		userBadge *entity.UserBadge, exist bool, err error)
+	GetUserBadgeList(ctx context.Context, userID string) (userBadges []*entity.UserBadge, err error)
+	GetUserBadgePage(ctx context.Context, page, pageSize int, cond *entity.UserBadge) (
+		userBadges []*entity.UserBadge, total int64, err error)
+	CountAcceptedAnswers(ctx context.Context, userID string) (count int64, err error)
+	CountUpvotedAnswersInTag(ctx context.Context, userID, tagID string) (count int64, err error)
+	// AddUserVisit record user visited on the date, it returns false if the visit has been recorded
+	AddUserVisit(ctx context.Context, userID, visitDate string) (added bool, err error)
+	CountUserVisitDays(ctx context.Context, userID string) (count int64, err error)
+}
+
+// BadgeService badge service
+type BadgeService struct {
+	badgeRepo    BadgeRepo
+	userRepo     usercommon.UserRepo
+	userCommon   *usercommon.UserCommon
+	questionRepo questioncommon.QuestionRepo
+	answerRepo   answercommon.AnswerRepo
+	tagRelRepo   tagcommon.TagRelRepo
+	auditLog     *audit_log.AuditLogService
+}
+
+// NewBadgeService new badge service
+func NewBadgeService(
+	badgeRepo BadgeRepo,
+	userRepo usercommon.UserRepo,
+	userCommon *usercommon.UserCommon,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	tagRelRepo tagcommon.TagRelRepo,
+	queue job_queue.Queue,
+	auditLog *audit_log.AuditLogService,
+) *BadgeService {
+	bs := &BadgeService{
+		badgeRepo:    badgeRepo,
+		userRepo:     userRepo,
+		userCommon:   userCommon,
+		questionRepo: questionRepo,
+		answerRepo:   answerRepo,
+		tagRelRepo:   tagRelRepo,
+		auditLog:     auditLog,
+	}
+	queue.Subscribe(badge_queue.Topic, bs.HandleEvent)
+	return bs
+}
+
+// GetBadgeList get all badges
+func (bs *BadgeService) GetBadgeList(ctx context.Context) (resp []*schema.GetBadgeResp, err error) {
+	badges, err := bs.badgeRepo.GetBadgeList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := bs.badgeRepo.GetBadgeAwardCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.GetBadgeResp, 0, len(badges))
+	for _, badge := range badges {
+		resp = append(resp, &schema.GetBadgeResp{
+			ID:          badge.ID,
+			Name:        badge.Name,
+			Description: badge.Description,
+			Level:       badge.Level,
+			Repeatable:  badge.Repeatable,
+			Rule:        badge.Rule,
+			Threshold:   badge.Threshold,
+			Enabled:     badge.Enabled,
+			AwardCount:  counts[badge.ID],
+		})
+	}
+	return resp, nil
+}
+
+// AddBadge add badge
+func (bs *BadgeService) AddBadge(ctx context.Context, req *schema.AddBadgeReq) (err error) {
+	return bs.badgeRepo.AddBadge(ctx, &entity.Badge{
+		Name:        req.Name,
+		Description: req.Description,
+		Level:       req.Level,
+		Repeatable:  req.Repeatable,
+		Rule:        req.Rule,
+		Threshold:   req.Threshold,
+		Enabled:     req.Enabled,
+	})
+}
+
+// UpdateBadge update badge, the badges awarded before are kept
+func (bs *BadgeService) UpdateBadge(ctx context.Context, req *schema.UpdateBadgeReq) (err error) {
+	badge, exist, err := bs.badgeRepo.GetBadge(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.BadgeNotFound)
+	}
+	badge.Name = req.Name
+	badge.Description = req.Description
+	badge.Level = req.Level
+	badge.Repeatable = req.Repeatable
+	badge.Rule = req.Rule
+	badge.Threshold = req.Threshold
+	badge.Enabled = req.Enabled
+	return bs.badgeRepo.UpdateBadge(ctx, badge)
+}
+
+// AwardBadge award the badge to user manually, the badge can only be awarded once by admin,
+// the badge revoked before is awarded again
+func (bs *BadgeService) AwardBadge(ctx context.Context, req *schema.AwardBadgeReq) (err error) {
+	badge, exist, err := bs.badgeRepo.GetBadge(ctx, req.BadgeID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.BadgeNotFound)
+	}
+	userInfo, exist, err := bs.userRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		return err
+	}
+	if !exist || userInfo.Status == entity.UserStatusDeleted {
+		return errors.BadRequest(reason.UserNotFound)
+	}
+
+	awarded, err := bs.award(ctx, badge, userInfo.ID, "0", req.UserID)
+	if err != nil {
+		return err
+	}
+	if !awarded {
+		return errors.BadRequest(reason.BadgeAlreadyAwarded)
+	}
+	log.Infof("admin %s awarded badge %s to user %s", req.UserID, badge.ID, userInfo.ID)
+	bs.auditLog.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     req.UserID,
+		Action:     entity.AuditActionBadgeAward,
+		ObjectType: constant.UserObjectType,
+		ObjectID:   userInfo.ID,
+		After:      &schema.AuditLogBadgeSnapshot{BadgeID: badge.ID, ObjectID: "0"},
+	})
+	return nil
+}
+
+// RevokeBadge revoke the badge awarded to user
+func (bs *BadgeService) RevokeBadge(ctx context.Context, req *schema.RevokeBadgeReq) (err error) {
+	userBadge, exist, err := bs.badgeRepo.GetUserBadge(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.UserBadgeNotFound)
+	}
+	if err = bs.badgeRepo.RevokeUserBadge(ctx, userBadge.ID); err != nil {
+		return err
+	}
+	log.Infof("admin %s revoked badge %s from user %s", req.UserID, userBadge.BadgeID, userBadge.UserID)
+	bs.auditLog.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     req.UserID,
+		Action:     entity.AuditActionBadgeRevoke,
+		ObjectType: constant.UserObjectType,
+		ObjectID:   userBadge.UserID,
+		Before:     &schema.AuditLogBadgeSnapshot{BadgeID: userBadge.BadgeID, ObjectID: userBadge.ObjectID},
+	})
+	return nil
+}
+
+// GetUserBadgePage get the awarded badges page
+func (bs *BadgeService) GetUserBadgePage(ctx context.Context, req *schema.GetUserBadgePageReq) (
+	pageModel *pager.PageModel, err error) {
+	cond := &entity.UserBadge{BadgeID: req.BadgeID}
+	if len(req.Username) > 0 {
+		userInfo, exist, err := bs.userRepo.GetByUsername(ctx, req.Username)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			return pager.NewPageModel(0, []*schema.GetUserBadgeResp{}), nil
+		}
+		cond.UserID = userInfo.ID
+	}
+
+	userBadges, total, err := bs.badgeRepo.GetUserBadgePage(ctx, req.Page, req.PageSize, cond)
+	if err != nil {
+		return nil, err
+	}
+	badges, err := bs.getBadgeMapping(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(userBadges))
+	for _, userBadge := range userBadges {
+		userIDs = append(userIDs, userBadge.UserID)
+	}
+	users, err := bs.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*schema.GetUserBadgeResp, 0, len(userBadges))
+	for _, userBadge := range userBadges {
+		item := &schema.GetUserBadgeResp{
+			ID:        userBadge.ID,
+			BadgeID:   userBadge.BadgeID,
+			ObjectID:  userBadge.ObjectID,
+			UserInfo:  users[userBadge.UserID],
+			Manual:    userBadge.AwardUserID != "0",
+			CreatedAt: userBadge.CreatedAt.Unix(),
+		}
+		if badge, ok := badges[userBadge.BadgeID]; ok {
+			item.BadgeName = badge.Name
+			item.BadgeLevel = badge.Level
+		}
+		resp = append(resp, item)
+	}
+	return pager.NewPageModel(total, resp), nil
+}
+
+// GetUserBadgeSummary get the badges of user grouped by badge, the higher level first
+func (bs *BadgeService) GetUserBadgeSummary(ctx context.Context, userID string) (
+	resp []*schema.UserBadgeSummary, err error) {
+	userBadges, err := bs.badgeRepo.GetUserBadgeList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.UserBadgeSummary, 0)
+	if len(userBadges) == 0 {
+		return resp, nil
+	}
+	badges, err := bs.getBadgeMapping(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[string]*schema.UserBadgeSummary)
+	for _, userBadge := range userBadges {
+		badge, ok := badges[userBadge.BadgeID]
+		if !ok {
+			continue
+		}
+		summary, ok := summaries[badge.ID]
+		if !ok {
+			summary = &schema.UserBadgeSummary{
+				ID:          badge.ID,
+				Name:        badge.Name,
+				Description: badge.Description,
+				Level:       badge.Level,
+			}
+			summaries[badge.ID] = summary
+			resp = append(resp, summary)
+		}
+		summary.Count++
+	}
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].Level > resp[j].Level
+	})
+	return resp, nil
+}
+
+// RecordVisit record user visited today, the badges of visit days are checked on the first visit of the day
+func (bs *BadgeService) RecordVisit(ctx context.Context, userID string) {
+	added, err := bs.badgeRepo.AddUserVisit(ctx, userID, time.Now().Format("2006-01-02"))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if added {
+		badge_queue.AddEvent(&schema.BadgeEventMsg{Event: schema.BadgeEventVisited, UserID: userID})
+	}
+}
+
+// HandleEvent check the badge rules related to the event and award the badges user earned
+func (bs *BadgeService) HandleEvent(ctx context.Context, payload []byte) error {
+	msg := &schema.BadgeEventMsg{}
+	if err := json.Unmarshal(payload, msg); err != nil {
+		log.Errorf("decode badge event message failed: %s", err)
+		return nil
+	}
+	log.Debugf("received badge event %+v", msg)
+
+	badges, err := bs.badgeRepo.GetEnabledBadges(ctx)
+	if err != nil {
+		return err
+	}
+	for _, badge := range badges {
+		objectIDs, err := bs.matchRule(ctx, badge, msg)
+		if err != nil {
+			return err
+		}
+		if !badge.Repeatable && len(objectIDs) > 0 {
+			objectIDs = []string{"0"}
+		}
+		for _, objectID := range objectIDs {
+			if _, err = bs.award(ctx, badge, msg.UserID, objectID, "0"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// matchRule get the objects which meet the rule of badge, it is empty if the rule is not met
+func (bs *BadgeService) matchRule(ctx context.Context, badge *entity.Badge, msg *schema.BadgeEventMsg) (
+	objectIDs []string, err error) {
+	switch badge.Rule {
+	case entity.BadgeRuleAcceptedAnswers:
+		if msg.Event != schema.BadgeEventAnswerAccepted {
+			return nil, nil
+		}
+		count, err := bs.badgeRepo.CountAcceptedAnswers(ctx, msg.UserID)
+		if err != nil || count < int64(badge.Threshold) {
+			return nil, err
+		}
+		return []string{countMilestone(count, badge.Threshold)}, nil
+	case entity.BadgeRuleVisitDays:
+		if msg.Event != schema.BadgeEventVisited {
+			return nil, nil
+		}
+		count, err := bs.badgeRepo.CountUserVisitDays(ctx, msg.UserID)
+		if err != nil || count < int64(badge.Threshold) {
+			return nil, err
+		}
+		return []string{countMilestone(count, badge.Threshold)}, nil
+	case entity.BadgeRuleQuestionVotes:
+		if msg.Event != schema.BadgeEventVotedUp || !isObjectType(msg.ObjectID, constant.QuestionObjectType) {
+			return nil, nil
+		}
+		question, exist, err := bs.questionRepo.GetQuestion(ctx, msg.ObjectID)
+		if err != nil || !exist || question.UserID != msg.UserID || question.VoteCount < badge.Threshold {
+			return nil, err
+		}
+		return []string{question.ID}, nil
+	case entity.BadgeRuleTagUpvotedAnswers:
+		if msg.Event != schema.BadgeEventVotedUp || !isObjectType(msg.ObjectID, constant.AnswerObjectType) {
+			return nil, nil
+		}
+		answer, exist, err := bs.answerRepo.GetAnswer(ctx, msg.ObjectID)
+		if err != nil || !exist || answer.UserID != msg.UserID {
+			return nil, err
+		}
+		tagRelList, err := bs.tagRelRepo.GetObjectTagRelList(ctx, answer.QuestionID)
+		if err != nil {
+			return nil, err
+		}
+		for _, tagRel := range tagRelList {
+			count, err := bs.badgeRepo.CountUpvotedAnswersInTag(ctx, msg.UserID, tagRel.TagID)
+			if err != nil {
+				return nil, err
+			}
+			if count >= int64(badge.Threshold) {
+				objectIDs = append(objectIDs, tagRel.TagID)
+			}
+		}
+		return objectIDs, nil
+	}
+	return nil, nil
+}
+
+// countMilestone get the object id of the count rules, it is the multiple of the threshold the count reaches,
+// so the repeatable badge is awarded again each time the count reaches the next multiple
+func countMilestone(count int64, threshold int) string {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return strconv.FormatInt(count/int64(threshold), 10)
+}
+
+// award the badge to user if user has not earned it for the object, it returns false if user has earned it.
+// The revoked badge is only awarded again by admin, the rule skips it.
+func (bs *BadgeService) award(ctx context.Context, badge *entity.Badge, userID, objectID, awardUserID string) (
+	awarded bool, err error) {
+	userBadge, exist, err := bs.badgeRepo.GetUserBadgeByObject(ctx, userID, badge.ID, objectID)
+	if err != nil {
+		return false, err
+	}
+	if exist {
+		if !userBadge.Revoked || awardUserID == "0" {
+			return false, nil
+		}
+		err = bs.badgeRepo.RestoreUserBadge(ctx, userBadge.ID, awardUserID)
+	} else {
+		err = bs.badgeRepo.AddUserBadge(ctx, &entity.UserBadge{
+			UserID:      userID,
+			BadgeID:     badge.ID,
+			ObjectID:    objectID,
+			AwardUserID: awardUserID,
+		})
+	}
+	if err != nil {
+		return false, err
+	}
+
+	notice_queue.AddNotification(&schema.NotificationMsg{
+		TriggerUserID:       userID,
+		ReceiverUserID:      userID,
+		Type:                schema.NotificationTypeAchievement,
+		Title:               badge.Name,
+		ObjectID:            badge.ID,
+		ObjectType:          constant.BadgeObjectType,
+		NotificationAction:  constant.BadgeAwarded,
+		NoNeedPushAllFollow: true,
+	})
+	return true, nil
+}
+
+func (bs *BadgeService) getBadgeMapping(ctx context.Context) (badges map[string]*entity.Badge, err error) {
+	badgeList, err := bs.badgeRepo.GetBadgeList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	badges = make(map[string]*entity.Badge, len(badgeList))
+	for _, badge := range badgeList {
+		badges[badge.ID] = badge
+	}
+	return badges, nil
+}
+
+func isObjectType(objectID, objectType string) bool {
+	t, err := obj.GetObjectTypeStrByObjectID(objectID)
+	return err == nil && t == objectType
+}
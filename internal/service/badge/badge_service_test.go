@@ -0,0 +1,252 @@
+package badge
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	questioncommon "github.com/answerdev/answer/internal/service/question_common"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBadgeRepo struct {
+	BadgeRepo
+	badges          []*entity.Badge
+	userBadges      []*entity.UserBadge
+	acceptedAnswers int64
+}
+
+func (f *fakeBadgeRepo) GetBadge(ctx context.Context, id string) (*entity.Badge, bool, error) {
+	for _, badge := range f.badges {
+		if badge.ID == id {
+			return badge, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (f *fakeBadgeRepo) GetBadgeList(ctx context.Context) ([]*entity.Badge, error) {
+	return f.badges, nil
+}
+
+func (f *fakeBadgeRepo) GetEnabledBadges(ctx context.Context) ([]*entity.Badge, error) {
+	badges := make([]*entity.Badge, 0)
+	for _, badge := range f.badges {
+		if badge.Enabled && badge.Rule != entity.BadgeRuleManual {
+			badges = append(badges, badge)
+		}
+	}
+	return badges, nil
+}
+
+func (f *fakeBadgeRepo) GetUserBadgeByObject(ctx context.Context, userID, badgeID, objectID string) (
+	*entity.UserBadge, bool, error) {
+	for _, userBadge := range f.userBadges {
+		if userBadge.UserID == userID && userBadge.BadgeID == badgeID && userBadge.ObjectID == objectID {
+			return userBadge, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (f *fakeBadgeRepo) GetUserBadge(ctx context.Context, id string) (*entity.UserBadge, bool, error) {
+	for _, userBadge := range f.userBadges {
+		if userBadge.ID == id && !userBadge.Revoked {
+			return userBadge, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (f *fakeBadgeRepo) AddUserBadge(ctx context.Context, userBadge *entity.UserBadge) error {
+	userBadge.ID = strconv.Itoa(len(f.userBadges) + 1)
+	f.userBadges = append(f.userBadges, userBadge)
+	return nil
+}
+
+func (f *fakeBadgeRepo) RevokeUserBadge(ctx context.Context, id string) error {
+	userBadge, _, _ := f.GetUserBadge(ctx, id)
+	userBadge.Revoked = true
+	return nil
+}
+
+func (f *fakeBadgeRepo) RestoreUserBadge(ctx context.Context, id, awardUserID string) error {
+	for _, userBadge := range f.userBadges {
+		if userBadge.ID == id {
+			userBadge.Revoked = false
+			userBadge.AwardUserID = awardUserID
+		}
+	}
+	return nil
+}
+
+func (f *fakeBadgeRepo) GetUserBadgeList(ctx context.Context, userID string) ([]*entity.UserBadge, error) {
+	userBadges := make([]*entity.UserBadge, 0)
+	for _, userBadge := range f.userBadges {
+		if userBadge.UserID == userID && !userBadge.Revoked {
+			userBadges = append(userBadges, userBadge)
+		}
+	}
+	return userBadges, nil
+}
+
+func (f *fakeBadgeRepo) CountAcceptedAnswers(ctx context.Context, userID string) (int64, error) {
+	return f.acceptedAnswers, nil
+}
+
+type fakeAuditLogRepo struct {
+	audit_log.AuditLogRepo
+	logs []*entity.AuditLog
+}
+
+func (f *fakeAuditLogRepo) AddAuditLog(ctx context.Context, auditLog *entity.AuditLog) error {
+	f.logs = append(f.logs, auditLog)
+	return nil
+}
+
+type fakeQuestionRepo struct {
+	questioncommon.QuestionRepo
+	questions map[string]*entity.Question
+}
+
+func (f *fakeQuestionRepo) GetQuestion(ctx context.Context, id string) (*entity.Question, bool, error) {
+	question, ok := f.questions[id]
+	return question, ok, nil
+}
+
+type fakeUserRepo struct {
+	usercommon.UserRepo
+	users map[string]*entity.User
+}
+
+func (f *fakeUserRepo) GetByUsername(ctx context.Context, username string) (*entity.User, bool, error) {
+	user, ok := f.users[username]
+	return user, ok, nil
+}
+
+func newTestBadgeService() (*BadgeService, *fakeBadgeRepo, *fakeAuditLogRepo) {
+	badgeRepo := &fakeBadgeRepo{acceptedAnswers: 1, badges: []*entity.Badge{
+		{ID: "1", Name: "Solver", Level: entity.BadgeLevelBronze, Rule: entity.BadgeRuleAcceptedAnswers,
+			Threshold: 1, Enabled: true},
+		{ID: "2", Name: "Good Question", Level: entity.BadgeLevelSilver, Repeatable: true,
+			Rule: entity.BadgeRuleQuestionVotes, Threshold: 25, Enabled: true},
+		{ID: "3", Name: "Helper", Level: entity.BadgeLevelGold, Rule: entity.BadgeRuleManual, Enabled: true},
+	}}
+	auditLogRepo := &fakeAuditLogRepo{}
+	return &BadgeService{
+		badgeRepo: badgeRepo,
+		userRepo: &fakeUserRepo{users: map[string]*entity.User{
+			"alice": {ID: "100", Username: "alice", Status: entity.UserStatusAvailable},
+		}},
+		questionRepo: &fakeQuestionRepo{questions: map[string]*entity.Question{
+			"10010000000000001": {ID: "10010000000000001", UserID: "100", VoteCount: 25},
+			"10010000000000002": {ID: "10010000000000002", UserID: "100", VoteCount: 24},
+		}},
+		auditLog: audit_log.NewAuditLogService(auditLogRepo, nil),
+	}, badgeRepo, auditLogRepo
+}
+
+func handleEvent(t *testing.T, bs *BadgeService, msg *schema.BadgeEventMsg) {
+	payload, _ := json.Marshal(msg)
+	assert.NoError(t, bs.HandleEvent(context.TODO(), payload))
+}
+
+func TestBadgeService_HandleEvent(t *testing.T) {
+	bs, badgeRepo, _ := newTestBadgeService()
+
+	handleEvent(t, bs, &schema.BadgeEventMsg{
+		Event: schema.BadgeEventVotedUp, UserID: "100", ObjectID: "10010000000000002"})
+	assert.Empty(t, badgeRepo.userBadges)
+
+	// the repeatable badge is awarded once for each question
+	for i := 0; i < 2; i++ {
+		handleEvent(t, bs, &schema.BadgeEventMsg{
+			Event: schema.BadgeEventVotedUp, UserID: "100", ObjectID: "10010000000000001"})
+	}
+	assert.Len(t, badgeRepo.userBadges, 1)
+	assert.Equal(t, "2", badgeRepo.userBadges[0].BadgeID)
+	assert.Equal(t, "10010000000000001", badgeRepo.userBadges[0].ObjectID)
+
+	// the one-time badge is awarded without object
+	handleEvent(t, bs, &schema.BadgeEventMsg{
+		Event: schema.BadgeEventAnswerAccepted, UserID: "100", ObjectID: "10020000000000001"})
+	assert.Len(t, badgeRepo.userBadges, 2)
+	assert.Equal(t, "0", badgeRepo.userBadges[1].ObjectID)
+
+	summary, err := bs.GetUserBadgeSummary(context.TODO(), "100")
+	assert.NoError(t, err)
+	assert.Len(t, summary, 2)
+	assert.Equal(t, "Good Question", summary[0].Name)
+	assert.Equal(t, 1, summary[0].Count)
+}
+
+func TestBadgeService_AwardBadge(t *testing.T) {
+	bs, badgeRepo, auditLogRepo := newTestBadgeService()
+
+	err := bs.AwardBadge(context.TODO(), &schema.AwardBadgeReq{BadgeID: "3", Username: "alice", UserID: "1"})
+	assert.NoError(t, err)
+	assert.Len(t, badgeRepo.userBadges, 1)
+	assert.Equal(t, "1", badgeRepo.userBadges[0].AwardUserID)
+
+	err = bs.AwardBadge(context.TODO(), &schema.AwardBadgeReq{BadgeID: "3", Username: "alice", UserID: "1"})
+	assert.Equal(t, errors.BadRequest(reason.BadgeAlreadyAwarded), err)
+	err = bs.AwardBadge(context.TODO(), &schema.AwardBadgeReq{BadgeID: "4", Username: "alice", UserID: "1"})
+	assert.Equal(t, errors.BadRequest(reason.BadgeNotFound), err)
+	err = bs.AwardBadge(context.TODO(), &schema.AwardBadgeReq{BadgeID: "3", Username: "bob", UserID: "1"})
+	assert.Equal(t, errors.BadRequest(reason.UserNotFound), err)
+
+	err = bs.RevokeBadge(context.TODO(), &schema.RevokeBadgeReq{ID: badgeRepo.userBadges[0].ID, UserID: "2"})
+	assert.NoError(t, err)
+	err = bs.RevokeBadge(context.TODO(), &schema.RevokeBadgeReq{ID: badgeRepo.userBadges[0].ID, UserID: "2"})
+	assert.Equal(t, errors.BadRequest(reason.UserBadgeNotFound), err)
+	// the revoked badge is awarded again by admin
+	err = bs.AwardBadge(context.TODO(), &schema.AwardBadgeReq{BadgeID: "3", Username: "alice", UserID: "2"})
+	assert.NoError(t, err)
+	assert.Len(t, badgeRepo.userBadges, 1)
+	assert.False(t, badgeRepo.userBadges[0].Revoked)
+	assert.Equal(t, "2", badgeRepo.userBadges[0].AwardUserID)
+
+	actions := make([]string, 0)
+	for _, item := range auditLogRepo.logs {
+		assert.Equal(t, "100", item.ObjectID)
+		actions = append(actions, item.Action)
+	}
+	assert.Equal(t, []string{entity.AuditActionBadgeAward, entity.AuditActionBadgeRevoke,
+		entity.AuditActionBadgeAward}, actions)
+	assert.Equal(t, `{"badge_id":"3","object_id":"0"}`, auditLogRepo.logs[1].Before)
+}
+
+func TestBadgeService_RevokedBadgeNotAwardedByRule(t *testing.T) {
+	bs, badgeRepo, _ := newTestBadgeService()
+
+	handleEvent(t, bs, &schema.BadgeEventMsg{Event: schema.BadgeEventAnswerAccepted, UserID: "100"})
+	assert.Len(t, badgeRepo.userBadges, 1)
+	err := bs.RevokeBadge(context.TODO(), &schema.RevokeBadgeReq{ID: badgeRepo.userBadges[0].ID, UserID: "1"})
+	assert.NoError(t, err)
+
+	handleEvent(t, bs, &schema.BadgeEventMsg{Event: schema.BadgeEventAnswerAccepted, UserID: "100"})
+	assert.Len(t, badgeRepo.userBadges, 1)
+	assert.True(t, badgeRepo.userBadges[0].Revoked)
+}
+
+func TestBadgeService_RepeatableCountBadge(t *testing.T) {
+	bs, badgeRepo, _ := newTestBadgeService()
+	badgeRepo.badges[0].Repeatable = true
+	badgeRepo.badges[0].Threshold = 10
+
+	// the repeatable badge is awarded once for each multiple of the threshold
+	for _, count := range []int64{9, 10, 11, 19, 20, 20} {
+		badgeRepo.acceptedAnswers = count
+		handleEvent(t, bs, &schema.BadgeEventMsg{Event: schema.BadgeEventAnswerAccepted, UserID: "100"})
+	}
+	assert.Len(t, badgeRepo.userBadges, 2)
+	assert.Equal(t, "1", badgeRepo.userBadges[0].ObjectID)
+	assert.Equal(t, "2", badgeRepo.userBadges[1].ObjectID)
+}
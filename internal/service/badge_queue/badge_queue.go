@@ -0,0 +1,14 @@
+package badge_queue
+
+import (
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/job_queue"
+)
+
+// Topic the topic of badge event message
+const Topic = "badge"
+
+// AddEvent add the event which may make user earn badges
+func AddEvent(msg *schema.BadgeEventMsg) {
+	job_queue.Publish(Topic, msg)
+}
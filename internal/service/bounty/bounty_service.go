@@ -0,0 +1,217 @@
+package bounty
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	"github.com/answerdev/answer/internal/service/notice_queue"
+	questioncommon "github.com/answerdev/answer/internal/service/question_common"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// Duration the period a bounty is open for
+const Duration = 7 * 24 * time.Hour
+
+// BountyRepo bounty repository
+type BountyRepo interface {
+	// AddBounty add bounty and debit the amount from the user who offers it
+	AddBounty(ctx context.Context, bounty *entity.QuestionBounty) (err error)
+	// AwardBounty award the bounty to the answer and credit the amount to the answerer
+	AwardBounty(ctx context.Context, bounty *entity.QuestionBounty, answer *entity.Answer) (err error)
+	// RefundBounty return the amount to the user who offered the bounty
+	RefundBounty(ctx context.Context, bounty *entity.QuestionBounty) (err error)
+	GetActiveBounty(ctx context.Context, questionID string) (bounty *entity.QuestionBounty, exist bool, err error)
+	GetExpiredBounties(ctx context.Context, before time.Time) (bounties []*entity.QuestionBounty, err error)
+}
+
+// BountyService bounty service
+type BountyService struct {
+	bountyRepo   BountyRepo
+	questionRepo questioncommon.QuestionRepo
+	answerRepo   answercommon.AnswerRepo
+	userCommon   *usercommon.UserCommon
+}
+
+// NewBountyService new bounty service
+func NewBountyService(
+	bountyRepo BountyRepo,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	userCommon *usercommon.UserCommon,
+) *BountyService {
+	return &BountyService{
+		bountyRepo:   bountyRepo,
+		questionRepo: questionRepo,
+		answerRepo:   answerRepo,
+		userCommon:   userCommon,
+	}
+}
+
+// GetQuestionBounty get the open bounty of question, it returns nil if there is no bounty
+func (bs *BountyService) GetQuestionBounty(ctx context.Context, req *schema.GetQuestionBountyReq) (
+	resp *schema.GetQuestionBountyResp, err error) {
+	bounty, exist, err := bs.bountyRepo.GetActiveBounty(ctx, req.QuestionID)
+	if err != nil || !exist {
+		return nil, err
+	}
+	resp = &schema.GetQuestionBountyResp{
+		ID:        bounty.ID,
+		Amount:    bounty.Amount,
+		CreatedAt: bounty.CreatedAt.Unix(),
+		ExpiredAt: bounty.ExpiredAt.Unix(),
+	}
+	resp.UserInfo, _, err = bs.userCommon.GetUserBasicInfoByID(ctx, bounty.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// StartBounty offer the reputation of user as a bounty on the question
+func (bs *BountyService) StartBounty(ctx context.Context, req *schema.StartBountyReq) (err error) {
+	questionInfo, exist, err := bs.questionRepo.GetQuestion(ctx, req.QuestionID)
+	if err != nil {
+		return err
+	}
+	if !exist || questionInfo.Status == entity.QuestionStatusDeleted {
+		return errors.BadRequest(reason.QuestionNotFound)
+	}
+	// the bounty can only be offered on the unanswered question
+	if questionInfo.Status != entity.QuestionStatusAvailable || questionInfo.AnswerCount > 0 ||
+		hasAcceptedAnswer(questionInfo) {
+		return errors.BadRequest(reason.BountyNotAllowed)
+	}
+
+	return bs.bountyRepo.AddBounty(ctx, &entity.QuestionBounty{
+		QuestionID: questionInfo.ID,
+		UserID:     req.UserID,
+		Amount:     req.Amount,
+		Status:     entity.QuestionBountyStatusActive,
+		ExpiredAt:  time.Now().Add(Duration),
+	})
+}
+
+// AwardBounty award the open bounty of the question to the answer
+func (bs *BountyService) AwardBounty(ctx context.Context, req *schema.AwardBountyReq) (err error) {
+	answerInfo, exist, err := bs.answerRepo.GetAnswer(ctx, req.AnswerID)
+	if err != nil {
+		return err
+	}
+	if !exist || answerInfo.Status != entity.AnswerStatusAvailable {
+		return errors.BadRequest(reason.AnswerNotFound)
+	}
+	bounty, exist, err := bs.bountyRepo.GetActiveBounty(ctx, answerInfo.QuestionID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.BountyNotFound)
+	}
+	if bounty.UserID != req.UserID {
+		return errors.Forbidden(reason.BountyCannotAward)
+	}
+	if answerInfo.UserID == bounty.UserID {
+		return errors.BadRequest(reason.BountyAnswerInvalid)
+	}
+	return bs.award(ctx, bounty, answerInfo)
+}
+
+// ExpireBounties close the expired bounties. The bounty is awarded to the accepted answer or the answer
+// with the most votes posted after the bounty started, it is refunded if there is no such answer.
+func (bs *BountyService) ExpireBounties(ctx context.Context) {
+	bounties, err := bs.bountyRepo.GetExpiredBounties(ctx, time.Now())
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, bounty := range bounties {
+		questionInfo, exist, err := bs.questionRepo.GetQuestion(ctx, bounty.QuestionID)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		// the bounty of the deleted or merged question is never awarded
+		var answerInfo *entity.Answer
+		if exist && isOpenQuestion(questionInfo) {
+			answerInfo, err = bs.pickAnswer(ctx, bounty)
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+		}
+		if answerInfo != nil {
+			log.Infof("bounty %s expired, award to answer %s", bounty.ID, answerInfo.ID)
+			err = bs.award(ctx, bounty, answerInfo)
+		} else {
+			log.Infof("bounty %s expired, refund to user %s", bounty.ID, bounty.UserID)
+			err = bs.bountyRepo.RefundBounty(ctx, bounty)
+		}
+		if err != nil {
+			log.Errorf("close expired bounty %s failed: %s", bounty.ID, err)
+		}
+	}
+}
+
+// RefundQuestionBounty refund the open bounty of the question, it is called when the question is deleted or merged
+func (bs *BountyService) RefundQuestionBounty(ctx context.Context, questionID string) (err error) {
+	bounty, exist, err := bs.bountyRepo.GetActiveBounty(ctx, questionID)
+	if err != nil || !exist {
+		return err
+	}
+	log.Infof("question %s is removed, refund bounty %s to user %s", questionID, bounty.ID, bounty.UserID)
+	return bs.bountyRepo.RefundBounty(ctx, bounty)
+}
+
+func (bs *BountyService) pickAnswer(ctx context.Context, bounty *entity.QuestionBounty) (
+	answerInfo *entity.Answer, err error) {
+	answers, err := bs.answerRepo.GetAnswerList(ctx, &entity.Answer{
+		QuestionID: bounty.QuestionID,
+		Status:     entity.AnswerStatusAvailable,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, answer := range answers {
+		if answer.UserID == bounty.UserID || answer.CreatedAt.Before(bounty.CreatedAt) {
+			continue
+		}
+		if answer.Accepted == schema.AnswerAcceptedEnable {
+			return answer, nil
+		}
+		if answer.VoteCount > 0 && (answerInfo == nil || answer.VoteCount > answerInfo.VoteCount) {
+			answerInfo = answer
+		}
+	}
+	return answerInfo, nil
+}
+
+func (bs *BountyService) award(ctx context.Context, bounty *entity.QuestionBounty, answerInfo *entity.Answer) (
+	err error) {
+	if err = bs.bountyRepo.AwardBounty(ctx, bounty, answerInfo); err != nil {
+		return err
+	}
+	notice_queue.AddNotification(&schema.NotificationMsg{
+		TriggerUserID:  bounty.UserID,
+		ReceiverUserID: answerInfo.UserID,
+		Type:           schema.NotificationTypeAchievement,
+		ObjectID:       answerInfo.ID,
+		ObjectType:     constant.AnswerObjectType,
+	})
+	return nil
+}
+
+// isOpenQuestion whether the bounty of question can be awarded, the question is visible to users
+func isOpenQuestion(questionInfo *entity.Question) bool {
+	return questionInfo.Status == entity.QuestionStatusAvailable || questionInfo.Status == entity.QuestionStatusClosed
+}
+
+func hasAcceptedAnswer(questionInfo *entity.Question) bool {
+	return len(questionInfo.AcceptedAnswerID) > 0 && questionInfo.AcceptedAnswerID != "0"
+}
@@ -0,0 +1,197 @@
+package bounty
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	questioncommon "github.com/answerdev/answer/internal/service/question_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBountyRepo struct {
+	BountyRepo
+	bounties []*entity.QuestionBounty
+}
+
+func (f *fakeBountyRepo) AddBounty(ctx context.Context, bounty *entity.QuestionBounty) error {
+	f.bounties = append(f.bounties, bounty)
+	return nil
+}
+
+func (f *fakeBountyRepo) AwardBounty(ctx context.Context, bounty *entity.QuestionBounty, answer *entity.Answer) error {
+	bounty.Status = entity.QuestionBountyStatusAwarded
+	bounty.AnswerID = answer.ID
+	return nil
+}
+
+func (f *fakeBountyRepo) RefundBounty(ctx context.Context, bounty *entity.QuestionBounty) error {
+	bounty.Status = entity.QuestionBountyStatusRefunded
+	return nil
+}
+
+func (f *fakeBountyRepo) GetActiveBounty(ctx context.Context, questionID string) (*entity.QuestionBounty, bool, error) {
+	for _, bounty := range f.bounties {
+		if bounty.QuestionID == questionID && bounty.Status == entity.QuestionBountyStatusActive {
+			return bounty, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (f *fakeBountyRepo) GetExpiredBounties(ctx context.Context, before time.Time) ([]*entity.QuestionBounty, error) {
+	bounties := make([]*entity.QuestionBounty, 0)
+	for _, bounty := range f.bounties {
+		if bounty.Status == entity.QuestionBountyStatusActive && !bounty.ExpiredAt.After(before) {
+			bounties = append(bounties, bounty)
+		}
+	}
+	return bounties, nil
+}
+
+type fakeQuestionRepo struct {
+	questioncommon.QuestionRepo
+	questions []*entity.Question
+}
+
+func (f *fakeQuestionRepo) GetQuestion(ctx context.Context, id string) (*entity.Question, bool, error) {
+	for _, question := range f.questions {
+		if question.ID == id {
+			return question, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+type fakeAnswerRepo struct {
+	answercommon.AnswerRepo
+	answers []*entity.Answer
+}
+
+func (f *fakeAnswerRepo) GetAnswer(ctx context.Context, id string) (*entity.Answer, bool, error) {
+	for _, answer := range f.answers {
+		if answer.ID == id {
+			return answer, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (f *fakeAnswerRepo) GetAnswerList(ctx context.Context, cond *entity.Answer) ([]*entity.Answer, error) {
+	answers := make([]*entity.Answer, 0)
+	for _, answer := range f.answers {
+		if answer.QuestionID == cond.QuestionID && answer.Status == cond.Status {
+			answers = append(answers, answer)
+		}
+	}
+	return answers, nil
+}
+
+func TestBountyService_ExpireBounties(t *testing.T) {
+	start := time.Now().Add(-Duration)
+	bountyRepo := &fakeBountyRepo{bounties: []*entity.QuestionBounty{
+		{ID: "1", QuestionID: "q1", UserID: "u1", Amount: 100, Status: entity.QuestionBountyStatusActive,
+			CreatedAt: start, ExpiredAt: start.Add(Duration)},
+		{ID: "2", QuestionID: "q2", UserID: "u1", Amount: 100, Status: entity.QuestionBountyStatusActive,
+			CreatedAt: start, ExpiredAt: start.Add(Duration)},
+		{ID: "3", QuestionID: "q3", UserID: "u1", Amount: 100, Status: entity.QuestionBountyStatusActive,
+			CreatedAt: start, ExpiredAt: time.Now().Add(time.Hour)},
+		{ID: "4", QuestionID: "q4", UserID: "u1", Amount: 100, Status: entity.QuestionBountyStatusActive,
+			CreatedAt: start, ExpiredAt: start.Add(Duration)},
+	}}
+	questionRepo := &fakeQuestionRepo{questions: []*entity.Question{
+		{ID: "q1", Status: entity.QuestionStatusAvailable},
+		{ID: "q2", Status: entity.QuestionStatusClosed},
+		{ID: "q3", Status: entity.QuestionStatusAvailable},
+		{ID: "q4", Status: entity.QuestionStatusDeleted},
+	}}
+	answerRepo := &fakeAnswerRepo{answers: []*entity.Answer{
+		// posted before the bounty started
+		{ID: "a1", QuestionID: "q1", UserID: "u2", Status: entity.AnswerStatusAvailable, VoteCount: 10,
+			CreatedAt: start.Add(-time.Hour)},
+		{ID: "a2", QuestionID: "q1", UserID: "u2", Status: entity.AnswerStatusAvailable, VoteCount: 1,
+			CreatedAt: start.Add(time.Hour)},
+		{ID: "a3", QuestionID: "q1", UserID: "u3", Status: entity.AnswerStatusAvailable, VoteCount: 3,
+			CreatedAt: start.Add(time.Hour)},
+		// posted by the user who offered the bounty
+		{ID: "a4", QuestionID: "q1", UserID: "u1", Status: entity.AnswerStatusAvailable, VoteCount: 5,
+			CreatedAt: start.Add(time.Hour)},
+		{ID: "a5", QuestionID: "q2", UserID: "u2", Status: entity.AnswerStatusAvailable,
+			CreatedAt: start.Add(time.Hour)},
+		{ID: "a6", QuestionID: "q4", UserID: "u2", Status: entity.AnswerStatusAvailable, VoteCount: 3,
+			CreatedAt: start.Add(time.Hour)},
+	}}
+	bs := NewBountyService(bountyRepo, questionRepo, answerRepo, nil)
+
+	bs.ExpireBounties(context.TODO())
+	assert.Equal(t, entity.QuestionBountyStatusAwarded, bountyRepo.bounties[0].Status)
+	assert.Equal(t, "a3", bountyRepo.bounties[0].AnswerID)
+	// no answer has votes
+	assert.Equal(t, entity.QuestionBountyStatusRefunded, bountyRepo.bounties[1].Status)
+	// not expired yet
+	assert.Equal(t, entity.QuestionBountyStatusActive, bountyRepo.bounties[2].Status)
+	// the question is deleted
+	assert.Equal(t, entity.QuestionBountyStatusRefunded, bountyRepo.bounties[3].Status)
+}
+
+func TestBountyService_RefundQuestionBounty(t *testing.T) {
+	bountyRepo := &fakeBountyRepo{bounties: []*entity.QuestionBounty{
+		{ID: "1", QuestionID: "q1", UserID: "u1", Amount: 100, Status: entity.QuestionBountyStatusActive},
+	}}
+	bs := NewBountyService(bountyRepo, nil, nil, nil)
+
+	assert.NoError(t, bs.RefundQuestionBounty(context.TODO(), "q2"))
+	assert.Equal(t, entity.QuestionBountyStatusActive, bountyRepo.bounties[0].Status)
+	assert.NoError(t, bs.RefundQuestionBounty(context.TODO(), "q1"))
+	assert.Equal(t, entity.QuestionBountyStatusRefunded, bountyRepo.bounties[0].Status)
+}
+
+func TestBountyService_AwardBounty(t *testing.T) {
+	bountyRepo := &fakeBountyRepo{bounties: []*entity.QuestionBounty{
+		{ID: "1", QuestionID: "q1", UserID: "u1", Amount: 100, Status: entity.QuestionBountyStatusActive},
+	}}
+	answerRepo := &fakeAnswerRepo{answers: []*entity.Answer{
+		{ID: "a1", QuestionID: "q1", UserID: "u1", Status: entity.AnswerStatusAvailable},
+		{ID: "a2", QuestionID: "q1", UserID: "u2", Status: entity.AnswerStatusAvailable},
+		{ID: "a3", QuestionID: "q2", UserID: "u2", Status: entity.AnswerStatusAvailable},
+	}}
+	bs := NewBountyService(bountyRepo, nil, answerRepo, nil)
+
+	err := bs.AwardBounty(context.TODO(), &schema.AwardBountyReq{AnswerID: "a2", UserID: "u2"})
+	assert.Equal(t, errors.Forbidden(reason.BountyCannotAward), err)
+	err = bs.AwardBounty(context.TODO(), &schema.AwardBountyReq{AnswerID: "a1", UserID: "u1"})
+	assert.Equal(t, errors.BadRequest(reason.BountyAnswerInvalid), err)
+	err = bs.AwardBounty(context.TODO(), &schema.AwardBountyReq{AnswerID: "a3", UserID: "u1"})
+	assert.Equal(t, errors.BadRequest(reason.BountyNotFound), err)
+
+	err = bs.AwardBounty(context.TODO(), &schema.AwardBountyReq{AnswerID: "a2", UserID: "u1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a2", bountyRepo.bounties[0].AnswerID)
+}
+
+func TestBountyService_StartBounty(t *testing.T) {
+	bountyRepo := &fakeBountyRepo{}
+	questionRepo := &fakeQuestionRepo{questions: []*entity.Question{
+		{ID: "q1", Status: entity.QuestionStatusAvailable, AcceptedAnswerID: "0"},
+		{ID: "q2", Status: entity.QuestionStatusAvailable, AcceptedAnswerID: "0", AnswerCount: 1},
+		{ID: "q3", Status: entity.QuestionStatusAvailable, AcceptedAnswerID: "a1", AnswerCount: 1},
+		{ID: "q4", Status: entity.QuestionStatusClosed, AcceptedAnswerID: "0"},
+	}}
+	bs := NewBountyService(bountyRepo, questionRepo, nil, nil)
+
+	// the answered question can not have a bounty even if no answer is accepted
+	for _, questionID := range []string{"q2", "q3", "q4"} {
+		err := bs.StartBounty(context.TODO(), &schema.StartBountyReq{QuestionID: questionID, UserID: "u1", Amount: 50})
+		assert.Equal(t, errors.BadRequest(reason.BountyNotAllowed), err)
+	}
+	assert.Empty(t, bountyRepo.bounties)
+
+	err := bs.StartBounty(context.TODO(), &schema.StartBountyReq{QuestionID: "q1", UserID: "u1", Amount: 50})
+	assert.NoError(t, err)
+	assert.Len(t, bountyRepo.bounties, 1)
+}
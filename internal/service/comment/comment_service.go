@@ -2,6 +2,7 @@ package comment
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/answerdev/answer/internal/base/constant"
@@ -12,10 +13,14 @@ import (
 	"github.com/answerdev/answer/internal/service/activity_common"
 	"github.com/answerdev/answer/internal/service/activity_queue"
 	"github.com/answerdev/answer/internal/service/comment_common"
+	"github.com/answerdev/answer/internal/service/email_digest"
 	"github.com/answerdev/answer/internal/service/export"
 	"github.com/answerdev/answer/internal/service/notice_queue"
 	"github.com/answerdev/answer/internal/service/object_info"
 	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/post_lock"
+	"github.com/answerdev/answer/internal/service/pre_moderation"
+	"github.com/answerdev/answer/internal/service/spam"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
 	"github.com/answerdev/answer/pkg/encryption"
 	"github.com/jinzhu/copier"
@@ -28,6 +33,7 @@ type CommentRepo interface {
 	AddComment(ctx context.Context, comment *entity.Comment) (err error)
 	RemoveComment(ctx context.Context, commentID string) (err error)
 	UpdateComment(ctx context.Context, comment *entity.Comment) (err error)
+	UpdateCommentStatus(ctx context.Context, commentID string, status int) (err error)
 	GetComment(ctx context.Context, commentID string) (comment *entity.Comment, exist bool, err error)
 	GetCommentPage(ctx context.Context, commentQuery *CommentQuery) (
 		comments []*entity.Comment, total int64, err error)
@@ -55,13 +61,17 @@ func (c *CommentQuery) GetOrderBy() string {
 
 // CommentService user service
 type CommentService struct {
-	commentRepo       CommentRepo
-	commentCommonRepo comment_common.CommentCommonRepo
-	userCommon        *usercommon.UserCommon
-	voteCommon        activity_common.VoteRepo
-	objectInfoService *object_info.ObjService
-	emailService      *export.EmailService
-	userRepo          usercommon.UserRepo
+	commentRepo          CommentRepo
+	commentCommonRepo    comment_common.CommentCommonRepo
+	userCommon           *usercommon.UserCommon
+	voteCommon           activity_common.VoteRepo
+	objectInfoService    *object_info.ObjService
+	emailService         *export.EmailService
+	userRepo             usercommon.UserRepo
+	emailDigestService   *email_digest.EmailDigestService
+	preModerationService *pre_moderation.PreModerationService
+	spamService          *spam.SpamService
+	postLockService      *post_lock.PostLockService
 }
 
 // NewCommentService new comment service
@@ -73,15 +83,23 @@ func NewCommentService(
 	voteCommon activity_common.VoteRepo,
 	emailService *export.EmailService,
 	userRepo usercommon.UserRepo,
+	emailDigestService *email_digest.EmailDigestService,
+	preModerationService *pre_moderation.PreModerationService,
+	spamService *spam.SpamService,
+	postLockService *post_lock.PostLockService,
 ) *CommentService {
 	return &CommentService{
-		commentRepo:       commentRepo,
-		commentCommonRepo: commentCommonRepo,
-		userCommon:        userCommon,
-		voteCommon:        voteCommon,
-		objectInfoService: objectInfoService,
-		emailService:      emailService,
-		userRepo:          userRepo,
+		commentRepo:          commentRepo,
+		commentCommonRepo:    commentCommonRepo,
+		userCommon:           userCommon,
+		voteCommon:           voteCommon,
+		objectInfoService:    objectInfoService,
+		emailService:         emailService,
+		userRepo:             userRepo,
+		emailDigestService:   emailDigestService,
+		preModerationService: preModerationService,
+		spamService:          spamService,
+		postLockService:      postLockService,
 	}
 }
 
@@ -100,6 +118,36 @@ func (cs *CommentService) AddComment(ctx context.Context, req *schema.AddComment
 	if objInfo.ObjectType == constant.QuestionObjectType || objInfo.ObjectType == constant.AnswerObjectType {
 		comment.QuestionID = objInfo.QuestionID
 	}
+	// the posts waiting for review can not be commented
+	pending, err := cs.preModerationService.IsPending(ctx, req.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.BadRequest(reason.ObjectNotFound)
+	}
+	if err = cs.postLockService.CheckPostLocked(ctx, req.ObjectID, req.UserID); err != nil {
+		return nil, err
+	}
+	verdict, err := cs.spamService.Check(ctx, &spam.Content{
+		UserID:     req.UserID,
+		ObjectType: constant.CommentObjectType,
+		HTML:       req.ParsedText,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verdict.Rejected() {
+		return nil, errors.BadRequest(reason.SpamContentRejected)
+	}
+	needReview, err := cs.preModerationService.NeedReview(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	needReview = needReview || verdict.NeedReview()
+	if needReview {
+		comment.Status = entity.CommentStatusPending
+	}
 
 	if len(req.ReplyCommentID) > 0 {
 		replyComment, exist, err := cs.commentCommonRepo.GetComment(ctx, req.ReplyCommentID)
@@ -121,15 +169,16 @@ func (cs *CommentService) AddComment(ctx context.Context, req *schema.AddComment
 		return nil, err
 	}
 
-	if objInfo.ObjectType == constant.QuestionObjectType {
-		cs.notificationQuestionComment(ctx, objInfo.ObjectCreatorUserID,
-			objInfo.QuestionID, objInfo.Title, comment.ID, req.UserID, comment.OriginalText)
-	} else if objInfo.ObjectType == constant.AnswerObjectType {
-		cs.notificationAnswerComment(ctx, objInfo.QuestionID, objInfo.Title, objInfo.AnswerID,
-			objInfo.ObjectCreatorUserID, comment.ID, req.UserID, comment.OriginalText)
-	}
-	if len(req.MentionUsernameList) > 0 {
-		cs.notificationMention(ctx, req.MentionUsernameList, comment.ID, req.UserID)
+	if needReview {
+		err = cs.preModerationService.AddPendingPost(ctx, comment.UserID, comment.ID, &schema.PendingPostExtra{
+			MentionUsernameList: req.MentionUsernameList,
+			SpamReason:          verdict.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		cs.publishComment(ctx, comment, objInfo, req.MentionUsernameList)
 	}
 
 	resp = &schema.GetCommentResp{}
@@ -147,7 +196,6 @@ func (cs *CommentService) AddComment(ctx context.Context, req *schema.AddComment
 			resp.ReplyUserDisplayName = replyUser.DisplayName
 			resp.ReplyUserStatus = replyUser.Status
 		}
-		cs.notificationCommentReply(ctx, replyUser.ID, objInfo.QuestionID, req.UserID)
 	}
 
 	// get user info
@@ -161,11 +209,30 @@ func (cs *CommentService) AddComment(ctx context.Context, req *schema.AddComment
 		resp.UserAvatar = userInfo.Avatar
 		resp.UserStatus = userInfo.Status
 	}
+	return resp, nil
+}
+
+// publishComment send the notifications and add the activity of the new comment
+func (cs *CommentService) publishComment(ctx context.Context, comment *entity.Comment,
+	objInfo *schema.SimpleObjectInfo, mentionUsernameList []string) {
+	if objInfo.ObjectType == constant.QuestionObjectType {
+		cs.notificationQuestionComment(ctx, objInfo.ObjectCreatorUserID,
+			objInfo.QuestionID, objInfo.Title, comment.ID, comment.UserID, comment.OriginalText)
+	} else if objInfo.ObjectType == constant.AnswerObjectType {
+		cs.notificationAnswerComment(ctx, objInfo.QuestionID, objInfo.Title, objInfo.AnswerID,
+			objInfo.ObjectCreatorUserID, comment.ID, comment.UserID, comment.OriginalText)
+	}
+	if len(mentionUsernameList) > 0 {
+		cs.notificationMention(ctx, mentionUsernameList, comment.ID, comment.UserID)
+	}
+	if replyUserID := comment.GetReplyUserID(); len(replyUserID) > 0 {
+		cs.notificationCommentReply(ctx, replyUserID, objInfo.QuestionID, comment.UserID)
+	}
 
 	activityMsg := &schema.ActivityMsg{
 		UserID:           comment.UserID,
 		ObjectID:         comment.ID,
-		OriginalObjectID: req.ObjectID,
+		OriginalObjectID: comment.ObjectID,
 		ActivityTypeKey:  constant.ActQuestionCommented,
 	}
 	switch objInfo.ObjectType {
@@ -175,7 +242,33 @@ func (cs *CommentService) AddComment(ctx context.Context, req *schema.AddComment
 		activityMsg.ActivityTypeKey = constant.ActAnswerCommented
 	}
 	activity_queue.AddActivity(activityMsg)
-	return resp, nil
+}
+
+// PublishPendingComment publish the comment approved by the reviewer, extra is saved when it is held
+func (cs *CommentService) PublishPendingComment(ctx context.Context, commentID, extra string) (err error) {
+	comment, exist, err := cs.commentCommonRepo.GetComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.CommentNotFound)
+	}
+	if err = cs.commentRepo.UpdateCommentStatus(ctx, comment.ID, entity.CommentStatusAvailable); err != nil {
+		return err
+	}
+	objInfo, err := cs.objectInfoService.GetInfo(ctx, comment.ObjectID)
+	if err != nil {
+		return err
+	}
+	pendingExtra := &schema.PendingPostExtra{}
+	_ = json.Unmarshal([]byte(extra), pendingExtra)
+	cs.publishComment(ctx, comment, objInfo, pendingExtra.MentionUsernameList)
+	return nil
+}
+
+// RejectPendingComment discard the comment rejected by the reviewer
+func (cs *CommentService) RejectPendingComment(ctx context.Context, commentID string) (err error) {
+	return cs.commentRepo.RemoveComment(ctx, commentID)
 }
 
 // RemoveComment delete comment
@@ -185,10 +278,36 @@ func (cs *CommentService) RemoveComment(ctx context.Context, req *schema.RemoveC
 
 // UpdateComment update comment
 func (cs *CommentService) UpdateComment(ctx context.Context, req *schema.UpdateCommentReq) (err error) {
+	if err = cs.postLockService.CheckPostLocked(ctx, req.CommentID, req.UserID); err != nil {
+		return err
+	}
+	verdict, err := cs.spamService.Check(ctx, &spam.Content{
+		UserID:     req.UserID,
+		ObjectType: constant.CommentObjectType,
+		HTML:       req.ParsedText,
+	})
+	if err != nil {
+		return err
+	}
+	if verdict.Rejected() {
+		return errors.BadRequest(reason.SpamContentRejected)
+	}
 	comment := &entity.Comment{}
 	_ = copier.Copy(comment, req)
 	comment.ID = req.CommentID
-	return cs.commentRepo.UpdateComment(ctx, comment)
+	// the edited comment is held for review again if it looks like spam
+	if verdict.NeedReview() {
+		comment.Status = entity.CommentStatusPending
+	}
+	if err = cs.commentRepo.UpdateComment(ctx, comment); err != nil {
+		return err
+	}
+	if verdict.NeedReview() {
+		return cs.preModerationService.AddPendingPost(ctx, req.UserID, req.CommentID, &schema.PendingPostExtra{
+			SpamReason: verdict.String(),
+		})
+	}
+	return nil
 }
 
 // GetComment get comment one
@@ -200,6 +319,10 @@ func (cs *CommentService) GetComment(ctx context.Context, req *schema.GetComment
 	if !exist {
 		return nil, errors.BadRequest(reason.UnknownError)
 	}
+	// the comment waiting for review is only visible to its author
+	if comment.Status == entity.CommentStatusPending && comment.UserID != req.UserID {
+		return nil, errors.BadRequest(reason.CommentNotFound)
+	}
 
 	resp = &schema.GetCommentResp{
 		CommentID:      comment.ID,
@@ -437,6 +560,19 @@ func (cs *CommentService) notificationQuestionComment(ctx context.Context, quest
 	if commentUser != nil {
 		rawData.CommentUserDisplayName = commentUser.DisplayName
 	}
+	sendNow := cs.emailDigestService.Schedule(ctx, &entity.EmailDigestItem{
+		UserID:             receiverUserInfo.ID,
+		NoticeType:         schema.NoticeTypeNewComment,
+		QuestionID:         rawData.QuestionID,
+		AnswerID:           rawData.AnswerID,
+		CommentID:          rawData.CommentID,
+		QuestionTitle:      rawData.QuestionTitle,
+		TriggerDisplayName: rawData.CommentUserDisplayName,
+		Summary:            rawData.CommentSummary,
+	})
+	if !sendNow {
+		return
+	}
 	codeContent := &schema.EmailCodeContent{
 		SourceType: schema.UnsubscribeSourceType,
 		Email:      receiverUserInfo.EMail,
@@ -449,8 +585,10 @@ func (cs *CommentService) notificationQuestionComment(ctx context.Context, quest
 		return
 	}
 
-	go cs.emailService.SendAndSaveCodeWithTime(
-		ctx, receiverUserInfo.EMail, title, body, rawData.UnsubscribeCode, codeContent.ToJSONString(), 7*24*time.Hour)
+	// the reply of email becomes a reply to the comment
+	replyTo := cs.emailService.ReplyAddress(receiverUserInfo.ID, commentID)
+	go cs.emailService.SendAndSaveCodeWithReplyTo(ctx, receiverUserInfo.EMail, title, body, replyTo,
+		rawData.UnsubscribeCode, codeContent.ToJSONString(), 7*24*time.Hour)
 }
 
 func (cs *CommentService) notificationAnswerComment(ctx context.Context,
@@ -493,6 +631,19 @@ func (cs *CommentService) notificationAnswerComment(ctx context.Context,
 	if commentUser != nil {
 		rawData.CommentUserDisplayName = commentUser.DisplayName
 	}
+	sendNow := cs.emailDigestService.Schedule(ctx, &entity.EmailDigestItem{
+		UserID:             receiverUserInfo.ID,
+		NoticeType:         schema.NoticeTypeNewComment,
+		QuestionID:         rawData.QuestionID,
+		AnswerID:           rawData.AnswerID,
+		CommentID:          rawData.CommentID,
+		QuestionTitle:      rawData.QuestionTitle,
+		TriggerDisplayName: rawData.CommentUserDisplayName,
+		Summary:            rawData.CommentSummary,
+	})
+	if !sendNow {
+		return
+	}
 	codeContent := &schema.EmailCodeContent{
 		SourceType: schema.UnsubscribeSourceType,
 		Email:      receiverUserInfo.EMail,
@@ -505,8 +656,10 @@ func (cs *CommentService) notificationAnswerComment(ctx context.Context,
 		return
 	}
 
-	go cs.emailService.SendAndSaveCodeWithTime(
-		ctx, receiverUserInfo.EMail, title, body, rawData.UnsubscribeCode, codeContent.ToJSONString(), 7*24*time.Hour)
+	// the reply of email becomes a reply to the comment
+	replyTo := cs.emailService.ReplyAddress(receiverUserInfo.ID, commentID)
+	go cs.emailService.SendAndSaveCodeWithReplyTo(ctx, receiverUserInfo.EMail, title, body, replyTo,
+		rawData.UnsubscribeCode, codeContent.ToJSONString(), 7*24*time.Hour)
 }
 
 func (cs *CommentService) notificationCommentReply(ctx context.Context, replyUserID, commentID, commentUserID string) {
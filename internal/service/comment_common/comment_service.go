@@ -13,6 +13,8 @@ import (
 type CommentCommonRepo interface {
 	GetComment(ctx context.Context, commentID string) (comment *entity.Comment, exist bool, err error)
 	GetCommentCount(ctx context.Context) (count int64, err error)
+	// GetUserCommentCount get the count of the available comments of the user
+	GetUserCommentCount(ctx context.Context, userID string) (count int64, err error)
 }
 
 // CommentCommonService user service
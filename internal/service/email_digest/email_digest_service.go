@@ -0,0 +1,266 @@
+package email_digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/activity_common"
+	"github.com/answerdev/answer/internal/service/export"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/pkg/encryption"
+	"github.com/segmentfault/pacman/log"
+)
+
+// newQuestionsLimit the max number of new questions in followed tags in one digest
+const newQuestionsLimit = 20
+
+// EmailDigestRepo email digest repository
+type EmailDigestRepo interface {
+	GetUserNoticeConfigList(ctx context.Context, userID string) (configs []*entity.UserNoticeConfig, err error)
+	SaveUserNoticeConfig(ctx context.Context, userID, noticeType, frequency string) (err error)
+	// GetUserIDsByNoticeFrequency get the users who set the frequency of the notice type
+	GetUserIDsByNoticeFrequency(ctx context.Context, noticeType, frequency string) (userIDs []string, err error)
+	AddDigestItem(ctx context.Context, item *entity.EmailDigestItem) (err error)
+	// GetDigestItemUserIDs get the users who have pending digest items of the frequency
+	GetDigestItemUserIDs(ctx context.Context, frequency string) (userIDs []string, err error)
+	GetDigestItemList(ctx context.Context, userID, frequency string) (items []*entity.EmailDigestItem, err error)
+	RemoveDigestItems(ctx context.Context, ids []int64) (err error)
+	// GetNewQuestionsByTagIDs get the available questions created after the time with any of the tags
+	GetNewQuestionsByTagIDs(ctx context.Context, tagIDs []string, since time.Time, limit int) (
+		questions []*entity.Question, err error)
+}
+
+// EmailDigestService email digest service
+type EmailDigestService struct {
+	emailDigestRepo EmailDigestRepo
+	userRepo        usercommon.UserRepo
+	followRepo      activity_common.FollowRepo
+	emailService    *export.EmailService
+}
+
+// NewEmailDigestService new email digest service
+func NewEmailDigestService(
+	emailDigestRepo EmailDigestRepo,
+	userRepo usercommon.UserRepo,
+	followRepo activity_common.FollowRepo,
+	emailService *export.EmailService,
+) *EmailDigestService {
+	return &EmailDigestService{
+		emailDigestRepo: emailDigestRepo,
+		userRepo:        userRepo,
+		followRepo:      followRepo,
+		emailService:    emailService,
+	}
+}
+
+// GetUserNoticeConfig get the email delivery preferences of user
+func (es *EmailDigestService) GetUserNoticeConfig(ctx context.Context, userID string) (
+	resp *schema.GetUserNoticeConfigResp, err error) {
+	frequencies, err := es.getUserFrequencies(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.GetUserNoticeConfigResp{
+		NewAnswer:    frequencies[schema.NoticeTypeNewAnswer],
+		NewComment:   frequencies[schema.NoticeTypeNewComment],
+		FollowedTags: frequencies[schema.NoticeTypeFollowedTags],
+	}, nil
+}
+
+// UpdateUserNoticeConfig update the email delivery preferences of user
+func (es *EmailDigestService) UpdateUserNoticeConfig(ctx context.Context, req *schema.UpdateUserNoticeConfigReq) (err error) {
+	frequencies := map[string]string{
+		schema.NoticeTypeNewAnswer:    req.NewAnswer,
+		schema.NoticeTypeNewComment:   req.NewComment,
+		schema.NoticeTypeFollowedTags: req.FollowedTags,
+	}
+	for noticeType, frequency := range frequencies {
+		err = es.emailDigestRepo.SaveUserNoticeConfig(ctx, req.UserID, noticeType, frequency)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Schedule decide how to deliver the email notification by the preference of receiver.
+// It returns true if the email should be sent immediately, otherwise the item is saved for the digest or dropped.
+func (es *EmailDigestService) Schedule(ctx context.Context, item *entity.EmailDigestItem) (sendNow bool) {
+	frequencies, err := es.getUserFrequencies(ctx, item.UserID)
+	if err != nil {
+		log.Error(err)
+		return true
+	}
+	switch frequency := frequencies[item.NoticeType]; frequency {
+	case schema.NoticeFrequencyDaily, schema.NoticeFrequencyWeekly:
+		item.Frequency = frequency
+		if len(item.AnswerID) == 0 {
+			item.AnswerID = "0"
+		}
+		if len(item.CommentID) == 0 {
+			item.CommentID = "0"
+		}
+		if err = es.emailDigestRepo.AddDigestItem(ctx, item); err != nil {
+			log.Error(err)
+		}
+		return false
+	case schema.NoticeFrequencyOff:
+		return false
+	default:
+		return true
+	}
+}
+
+// SendDigests send the digest email of the frequency to every user who has pending items or follows tags
+func (es *EmailDigestService) SendDigests(ctx context.Context, frequency string) {
+	itemUserIDs, err := es.emailDigestRepo.GetDigestItemUserIDs(ctx, frequency)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	tagUserIDs, err := es.emailDigestRepo.GetUserIDsByNoticeFrequency(ctx, schema.NoticeTypeFollowedTags, frequency)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	sent := make(map[string]bool)
+	for _, userID := range append(itemUserIDs, tagUserIDs...) {
+		if sent[userID] {
+			continue
+		}
+		sent[userID] = true
+		if err = es.sendDigest(ctx, userID, frequency); err != nil {
+			log.Errorf("send %s digest to user %s failed: %s", frequency, userID, err)
+		}
+	}
+}
+
+func (es *EmailDigestService) sendDigest(ctx context.Context, userID, frequency string) (err error) {
+	items, err := es.emailDigestRepo.GetDigestItemList(ctx, userID, frequency)
+	if err != nil {
+		return err
+	}
+	itemIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		itemIDs = append(itemIDs, item.ID)
+	}
+	// the pending items are removed even if the user can not receive the email anymore,
+	// but they are kept for the next digest if the email is failed to send
+	defer func() {
+		if err != nil || len(itemIDs) == 0 {
+			return
+		}
+		if removeErr := es.emailDigestRepo.RemoveDigestItems(ctx, itemIDs); removeErr != nil {
+			log.Error(removeErr)
+		}
+	}()
+
+	userInfo, exist, err := es.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exist || userInfo.NoticeStatus == schema.NoticeStatusOff || len(userInfo.EMail) == 0 {
+		return nil
+	}
+
+	raw := &schema.DigestTemplateRawData{
+		Frequency:       frequency,
+		UnsubscribeCode: encryption.MD5(userInfo.Pass),
+	}
+	for _, item := range items {
+		raw.Items = append(raw.Items, &schema.DigestTemplateItem{
+			NoticeType:    item.NoticeType,
+			DisplayName:   item.TriggerDisplayName,
+			QuestionTitle: item.QuestionTitle,
+			QuestionID:    item.QuestionID,
+			AnswerID:      trimZeroID(item.AnswerID),
+			CommentID:     trimZeroID(item.CommentID),
+			Summary:       item.Summary,
+		})
+	}
+	raw.NewQuestions, err = es.getNewQuestionsInFollowedTags(ctx, userID, frequency)
+	if err != nil {
+		return err
+	}
+	if len(raw.Items) == 0 && len(raw.NewQuestions) == 0 {
+		return nil
+	}
+
+	title, body, err := es.emailService.DigestTemplate(ctx, raw)
+	if err != nil {
+		return err
+	}
+	codeContent := &schema.EmailCodeContent{
+		SourceType: schema.UnsubscribeSourceType,
+		Email:      userInfo.EMail,
+		UserID:     userInfo.ID,
+	}
+	return es.emailService.SendAndSaveCodeWithTime(
+		ctx, userInfo.EMail, title, body, raw.UnsubscribeCode, codeContent.ToJSONString(), 7*24*time.Hour)
+}
+
+// getNewQuestionsInFollowedTags get the new questions in the tags followed by user during the digest period
+func (es *EmailDigestService) getNewQuestionsInFollowedTags(ctx context.Context, userID, frequency string) (
+	questions []*schema.DigestTemplateQuestion, err error) {
+	frequencies, err := es.getUserFrequencies(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if frequencies[schema.NoticeTypeFollowedTags] != frequency {
+		return nil, nil
+	}
+	tagIDs, err := es.followRepo.GetFollowIDs(ctx, userID, entity.Tag{}.TableName())
+	if err != nil {
+		return nil, err
+	}
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+
+	since := time.Now().Add(-digestPeriod(frequency))
+	questionList, err := es.emailDigestRepo.GetNewQuestionsByTagIDs(ctx, tagIDs, since, newQuestionsLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, question := range questionList {
+		if question.UserID == userID {
+			continue
+		}
+		questions = append(questions, &schema.DigestTemplateQuestion{ID: question.ID, Title: question.Title})
+	}
+	return questions, nil
+}
+
+// getUserFrequencies get the frequency of every notice type, the default frequency is used if not set
+func (es *EmailDigestService) getUserFrequencies(ctx context.Context, userID string) (
+	frequencies map[string]string, err error) {
+	configs, err := es.emailDigestRepo.GetUserNoticeConfigList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	frequencies = make(map[string]string, len(schema.DefaultNoticeFrequency))
+	for noticeType, frequency := range schema.DefaultNoticeFrequency {
+		frequencies[noticeType] = frequency
+	}
+	for _, config := range configs {
+		frequencies[config.NoticeType] = config.Frequency
+	}
+	return frequencies, nil
+}
+
+func digestPeriod(frequency string) time.Duration {
+	if frequency == schema.NoticeFrequencyWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+func trimZeroID(id string) string {
+	if id == "0" {
+		return ""
+	}
+	return id
+}
@@ -0,0 +1,57 @@
+package email_digest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEmailDigestRepo struct {
+	EmailDigestRepo
+	configs []*entity.UserNoticeConfig
+	items   []*entity.EmailDigestItem
+}
+
+func (f *fakeEmailDigestRepo) GetUserNoticeConfigList(ctx context.Context, userID string) (
+	[]*entity.UserNoticeConfig, error) {
+	return f.configs, nil
+}
+
+func (f *fakeEmailDigestRepo) AddDigestItem(ctx context.Context, item *entity.EmailDigestItem) error {
+	f.items = append(f.items, item)
+	return nil
+}
+
+func TestEmailDigestService_Schedule(t *testing.T) {
+	repo := &fakeEmailDigestRepo{configs: []*entity.UserNoticeConfig{
+		{UserID: "1", NoticeType: schema.NoticeTypeNewAnswer, Frequency: schema.NoticeFrequencyDaily},
+		{UserID: "1", NoticeType: schema.NoticeTypeNewComment, Frequency: schema.NoticeFrequencyOff},
+	}}
+	es := NewEmailDigestService(repo, nil, nil, nil)
+
+	sendNow := es.Schedule(context.TODO(), &entity.EmailDigestItem{
+		UserID: "1", NoticeType: schema.NoticeTypeNewAnswer, QuestionID: "1", AnswerID: "2"})
+	assert.False(t, sendNow)
+	assert.Equal(t, 1, len(repo.items))
+	assert.Equal(t, schema.NoticeFrequencyDaily, repo.items[0].Frequency)
+	assert.Equal(t, "0", repo.items[0].CommentID)
+
+	sendNow = es.Schedule(context.TODO(), &entity.EmailDigestItem{UserID: "1", NoticeType: schema.NoticeTypeNewComment})
+	assert.False(t, sendNow)
+	assert.Equal(t, 1, len(repo.items))
+
+	// the default frequency is immediate
+	repo.configs = nil
+	sendNow = es.Schedule(context.TODO(), &entity.EmailDigestItem{UserID: "1", NoticeType: schema.NoticeTypeNewComment})
+	assert.True(t, sendNow)
+	assert.Equal(t, 1, len(repo.items))
+}
+
+func TestDigestPeriod(t *testing.T) {
+	assert.Equal(t, 24*time.Hour, digestPeriod(schema.NoticeFrequencyDaily))
+	assert.Equal(t, 7*24*time.Hour, digestPeriod(schema.NoticeFrequencyWeekly))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/answerdev/answer/internal/schema"
 	"github.com/answerdev/answer/internal/service/config"
 	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	"github.com/answerdev/answer/pkg/mailreply"
 	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
 	"golang.org/x/net/context"
@@ -64,6 +65,13 @@ type EmailConfig struct {
 	NewAnswerBody   string `json:"new_answer_body"`
 	NewCommentTitle string `json:"new_comment_title"`
 	NewCommentBody  string `json:"new_comment_body"`
+	DigestTitle     string `json:"digest_title"`
+	DigestBody      string `json:"digest_body"`
+
+	// ReplyAddress the base address of reply-by-email, e.g. reply@example.com, it is disabled if empty
+	ReplyAddress string `json:"reply_address"`
+	// ReplySecret the secret to sign the reply address
+	ReplySecret string `json:"reply_secret"`
 }
 
 func (e *EmailConfig) IsSSL() bool {
@@ -98,10 +106,22 @@ func (es *EmailService) SendAndSaveCode(ctx context.Context, toEmailAddr, subjec
 	}
 }
 
-// SendAndSaveCodeWithTime send email and save code
+// SendAndSaveCodeWithTime send email and save code, the code is not saved if the email is not sent
 func (es *EmailService) SendAndSaveCodeWithTime(
-	ctx context.Context, toEmailAddr, subject, body, code, codeContent string, duration time.Duration) {
-	es.Send(ctx, toEmailAddr, subject, body)
+	ctx context.Context, toEmailAddr, subject, body, code, codeContent string, duration time.Duration) (err error) {
+	if err = es.send(ctx, toEmailAddr, subject, body, ""); err != nil {
+		return err
+	}
+	if err = es.emailRepo.SetCode(ctx, code, codeContent, duration); err != nil {
+		log.Error(err)
+	}
+	return nil
+}
+
+// SendAndSaveCodeWithReplyTo send email with the reply-to address and save code
+func (es *EmailService) SendAndSaveCodeWithReplyTo(ctx context.Context,
+	toEmailAddr, subject, body, replyTo, code, codeContent string, duration time.Duration) {
+	_ = es.send(ctx, toEmailAddr, subject, body, replyTo)
 	err := es.emailRepo.SetCode(ctx, code, codeContent, duration)
 	if err != nil {
 		log.Error(err)
@@ -110,11 +130,15 @@ func (es *EmailService) SendAndSaveCodeWithTime(
 
 // Send email send
 func (es *EmailService) Send(ctx context.Context, toEmailAddr, subject, body string) {
+	_ = es.send(ctx, toEmailAddr, subject, body, "")
+}
+
+func (es *EmailService) send(ctx context.Context, toEmailAddr, subject, body, replyTo string) (err error) {
 	log.Infof("try to send email to %s", toEmailAddr)
 	ec, err := es.GetEmailConfig()
 	if err != nil {
 		log.Errorf("get email config failed: %s", err)
-		return
+		return err
 	}
 
 	m := gomail.NewMessage()
@@ -122,17 +146,21 @@ func (es *EmailService) Send(ctx context.Context, toEmailAddr, subject, body str
 	m.SetHeader("From", fmt.Sprintf("%s <%s>", fromName, ec.FromEmail))
 	m.SetHeader("To", toEmailAddr)
 	m.SetHeader("Subject", subject)
+	if len(replyTo) > 0 {
+		m.SetHeader("Reply-To", replyTo)
+	}
 	m.SetBody("text/html", body)
 
 	d := gomail.NewDialer(ec.SMTPHost, ec.SMTPPort, ec.SMTPUsername, ec.SMTPPassword)
 	if ec.IsSSL() {
 		d.SSL = true
 	}
-	if err := d.DialAndSend(m); err != nil {
+	if err = d.DialAndSend(m); err != nil {
 		log.Errorf("send email to %s failed: %s", toEmailAddr, err)
-	} else {
-		log.Infof("send email to %s success", toEmailAddr)
+		return err
 	}
+	log.Infof("send email to %s success", toEmailAddr)
+	return nil
 }
 
 // VerifyUrlExpired email send
@@ -368,6 +396,53 @@ func (es *EmailService) NewCommentTemplate(ctx context.Context, raw *schema.NewC
 	return title, body, nil
 }
 
+// DigestTemplate digest template
+func (es *EmailService) DigestTemplate(ctx context.Context, raw *schema.DigestTemplateRawData) (
+	title, body string, err error) {
+	emailConfig, err := es.GetEmailConfig()
+	if err != nil {
+		return
+	}
+
+	siteInfo, err := es.GetSiteGeneral(ctx)
+	if err != nil {
+		return
+	}
+	templateData := &schema.DigestTemplateData{
+		SiteName:       siteInfo.Name,
+		Period:         raw.Frequency,
+		Items:          raw.Items,
+		NewQuestions:   raw.NewQuestions,
+		SettingsUrl:    fmt.Sprintf("%s/users/settings/notify", siteInfo.SiteUrl),
+		UnsubscribeUrl: fmt.Sprintf("%s/users/unsubscribe?code=%s", siteInfo.SiteUrl, raw.UnsubscribeCode),
+	}
+	for _, item := range templateData.Items {
+		switch {
+		case len(item.CommentID) > 0 && len(item.AnswerID) > 0:
+			item.Url = fmt.Sprintf("%s/questions/%s/%s?commentId=%s", siteInfo.SiteUrl, item.QuestionID,
+				item.AnswerID, item.CommentID)
+		case len(item.CommentID) > 0:
+			item.Url = fmt.Sprintf("%s/questions/%s?commentId=%s", siteInfo.SiteUrl, item.QuestionID, item.CommentID)
+		default:
+			item.Url = fmt.Sprintf("%s/questions/%s/%s", siteInfo.SiteUrl, item.QuestionID, item.AnswerID)
+		}
+	}
+	for _, question := range templateData.NewQuestions {
+		question.Url = fmt.Sprintf("%s/questions/%s", siteInfo.SiteUrl, question.ID)
+	}
+
+	title, err = es.parseTemplateData(emailConfig.DigestTitle, templateData)
+	if err != nil {
+		return "", "", fmt.Errorf("email template parse error: %s", err)
+	}
+
+	body, err = es.parseTemplateData(emailConfig.DigestBody, templateData)
+	if err != nil {
+		return "", "", fmt.Errorf("email template parse error: %s", err)
+	}
+	return title, body, nil
+}
+
 func (es *EmailService) parseTemplateData(templateContent string, templateData interface{}) (parsedData string, err error) {
 	parsedDataBuf := &bytes.Buffer{}
 	tmpl, err := template.New("").Parse(templateContent)
@@ -381,6 +456,25 @@ func (es *EmailService) parseTemplateData(templateContent string, templateData i
 	return parsedDataBuf.String(), nil
 }
 
+// ReplyAddress get the signed reply address for the user to reply the object by email,
+// it returns empty if reply-by-email is disabled
+func (es *EmailService) ReplyAddress(userID, objectID string) string {
+	ec, err := es.GetEmailConfig()
+	if err != nil {
+		log.Error(err)
+		return ""
+	}
+	if len(ec.ReplyAddress) == 0 || len(ec.ReplySecret) == 0 {
+		return ""
+	}
+	address, err := mailreply.Address(ec.ReplyAddress, ec.ReplySecret, userID, objectID)
+	if err != nil {
+		log.Error(err)
+		return ""
+	}
+	return address
+}
+
 func (es *EmailService) GetEmailConfig() (ec *EmailConfig, err error) {
 	emailConf, err := es.configRepo.GetString("email.config")
 	if err != nil {
@@ -0,0 +1,52 @@
+package job_queue
+
+import (
+	"context"
+	"sync"
+
+	"github.com/segmentfault/pacman/log"
+)
+
+// ChannelQueue in-process queue based on channel, messages are lost on restart and
+// only handled by the current process, it should only be used for development.
+type ChannelQueue struct {
+	mu     sync.Mutex
+	topics map[string]chan []byte
+}
+
+// NewChannelQueue new channel queue
+func NewChannelQueue() *ChannelQueue {
+	return &ChannelQueue{topics: make(map[string]chan []byte)}
+}
+
+func (q *ChannelQueue) getTopic(topic string) chan []byte {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	ch, ok := q.topics[topic]
+	if !ok {
+		ch = make(chan []byte, 128)
+		q.topics[topic] = ch
+	}
+	return ch
+}
+
+// Publish publish message to the topic channel
+func (q *ChannelQueue) Publish(ctx context.Context, topic string, payload []byte) error {
+	q.getTopic(topic) <- payload
+	return nil
+}
+
+// Subscribe handle the messages of topic in a new goroutine
+func (q *ChannelQueue) Subscribe(topic string, handler Handler) {
+	ch := q.getTopic(topic)
+	go func() {
+		for payload := range ch {
+			log.Debugf("received %s message %s", topic, payload)
+			// the failed message is never retried
+			ctx := withLastAttempt(context.Background(), true)
+			if err := handleSafely(ctx, handler, payload); err != nil {
+				log.Errorf("handle %s message failed: %s", topic, err)
+			}
+		}
+	}()
+}
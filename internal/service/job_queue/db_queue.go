@@ -0,0 +1,238 @@
+package job_queue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	defaultWorkers           = 2
+	defaultPollInterval      = time.Second
+	defaultVisibilityTimeout = time.Minute
+	defaultMaxAttempts       = 5
+	// retryBaseDelay the delay of first retry, it doubles after each failed attempt
+	retryBaseDelay = 10 * time.Second
+	retryMaxDelay  = time.Hour
+	fetchBatchSize = 20
+)
+
+// DBQueue durable queue stored in the job table. The workers of every replica poll the table and
+// claim the job by optimistic update, so a job is only handled by one worker at a time.
+// The claimed job is invisible to other workers until the visibility timeout, if the worker
+// crashes before the job completed, the job will be claimed by another worker after that.
+// The job failed more than max attempts will be marked as dead and never be retried.
+type DBQueue struct {
+	jobRepo           JobRepo
+	workerID          string
+	workers           int
+	pollInterval      time.Duration
+	visibilityTimeout time.Duration
+	maxAttempts       int
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	notify   chan struct{}
+	closeCh  chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewDBQueue new database queue and start the workers
+func NewDBQueue(queueConf *data.QueueConf, jobRepo JobRepo) *DBQueue {
+	q := &DBQueue{
+		jobRepo:           jobRepo,
+		workers:           defaultWorkers,
+		pollInterval:      defaultPollInterval,
+		visibilityTimeout: defaultVisibilityTimeout,
+		maxAttempts:       defaultMaxAttempts,
+		handlers:          make(map[string]Handler),
+		notify:            make(chan struct{}, 1),
+		closeCh:           make(chan struct{}),
+	}
+	if queueConf != nil {
+		if queueConf.Workers > 0 {
+			q.workers = queueConf.Workers
+		}
+		if queueConf.PollInterval > 0 {
+			q.pollInterval = time.Duration(queueConf.PollInterval) * time.Second
+		}
+		if queueConf.VisibilityTimeout > 0 {
+			q.visibilityTimeout = time.Duration(queueConf.VisibilityTimeout) * time.Second
+		}
+		if queueConf.MaxAttempts > 0 {
+			q.maxAttempts = queueConf.MaxAttempts
+		}
+	}
+	hostname, _ := os.Hostname()
+	q.workerID = fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano())
+	if len(q.workerID) > 64 {
+		q.workerID = q.workerID[len(q.workerID)-64:]
+	}
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// Publish save the message as a pending job
+func (q *DBQueue) Publish(ctx context.Context, topic string, payload []byte) error {
+	err := q.jobRepo.AddJob(ctx, &entity.Job{
+		Topic:       topic,
+		Payload:     string(payload),
+		Status:      entity.JobStatusPending,
+		AvailableAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	// wake up the idle worker of this replica, the other replicas will get the job in next poll
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Subscribe handle the jobs of topic by the workers
+func (q *DBQueue) Subscribe(topic string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[topic] = handler
+}
+
+// Close stop the workers and wait for the running jobs
+func (q *DBQueue) Close() {
+	close(q.closeCh)
+	q.wg.Wait()
+}
+
+func (q *DBQueue) work() {
+	defer q.wg.Done()
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.closeCh:
+			return
+		case <-ticker.C:
+		case <-q.notify:
+		}
+		// keep handling until there is no available job
+		for q.handleAvailableJobs() > 0 {
+			select {
+			case <-q.closeCh:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// handleAvailableJobs claim and handle the available jobs, returns the number of handled jobs
+func (q *DBQueue) handleAvailableJobs() (handled int) {
+	q.mu.RLock()
+	topics := make([]string, 0, len(q.handlers))
+	for topic := range q.handlers {
+		topics = append(topics, topic)
+	}
+	q.mu.RUnlock()
+	if len(topics) == 0 {
+		return 0
+	}
+
+	ctx := context.Background()
+	jobs, err := q.jobRepo.GetAvailableJobs(ctx, topics, time.Now(), fetchBatchSize)
+	if err != nil {
+		log.Error(err)
+		return 0
+	}
+	for _, job := range jobs {
+		claimed, err := q.jobRepo.ClaimJob(ctx, job, q.workerID, time.Now().Add(q.visibilityTimeout))
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		q.handleJob(ctx, job)
+		handled++
+	}
+	return handled
+}
+
+func (q *DBQueue) handleJob(ctx context.Context, job *entity.Job) {
+	q.mu.RLock()
+	handler := q.handlers[job.Topic]
+	q.mu.RUnlock()
+
+	// the job claimed again after visibility timeout may have exceeded the max attempts
+	var err error
+	if job.Attempts > q.maxAttempts {
+		err = fmt.Errorf("job exceeded max attempts %d", q.maxAttempts)
+	} else {
+		log.Debugf("received %s job %s", job.Topic, job.ID)
+		handleCtx, cancel := context.WithTimeout(withLastAttempt(ctx, job.Attempts >= q.maxAttempts), q.visibilityTimeout)
+		err = handleSafely(handleCtx, handler, []byte(job.Payload))
+		cancel()
+	}
+	if err == nil {
+		owned, err := q.jobRepo.RemoveJob(ctx, job)
+		q.checkOwned(job, owned, err)
+		return
+	}
+
+	if job.Attempts >= q.maxAttempts {
+		log.Errorf("%s job %s is dead after %d attempts: %s", job.Topic, job.ID, job.Attempts, err)
+		owned, err := q.jobRepo.DeadJob(ctx, job, err.Error())
+		q.checkOwned(job, owned, err)
+		return
+	}
+	log.Warnf("%s job %s failed in attempt %d: %s", job.Topic, job.ID, job.Attempts, err)
+	owned, err := q.jobRepo.RetryJob(ctx, job, time.Now().Add(retryDelay(job.Attempts)), err.Error())
+	q.checkOwned(job, owned, err)
+}
+
+// checkOwned log the job which is not changed because it has been claimed by another worker
+func (q *DBQueue) checkOwned(job *entity.Job, owned bool, err error) {
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if !owned {
+		log.Warnf("%s job %s has been claimed by another worker after the visibility timeout", job.Topic, job.ID)
+	}
+}
+
+// retryDelay exponential backoff of the failed job
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+// handleSafely call handler and convert the panic into error, so that the worker will not crash
+func handleSafely(ctx context.Context, handler Handler, payload []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+	if handler == nil {
+		return fmt.Errorf("no handler subscribed")
+	}
+	return handler(ctx, payload)
+}
@@ -0,0 +1,191 @@
+package job_queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryJobRepo job repo stored in memory for testing
+type memoryJobRepo struct {
+	mu   sync.Mutex
+	id   int
+	jobs map[string]*entity.Job
+}
+
+func newMemoryJobRepo() *memoryJobRepo {
+	return &memoryJobRepo{jobs: make(map[string]*entity.Job)}
+}
+
+func (r *memoryJobRepo) AddJob(ctx context.Context, job *entity.Job) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.id++
+	job.ID = strconv.Itoa(r.id)
+	j := *job
+	r.jobs[job.ID] = &j
+	return nil
+}
+
+func (r *memoryJobRepo) GetAvailableJobs(ctx context.Context, topics []string, now time.Time, limit int) (
+	jobs []*entity.Job, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.Status != entity.JobStatusDead && !job.AvailableAt.After(now) {
+			j := *job
+			jobs = append(jobs, &j)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *memoryJobRepo) ClaimJob(ctx context.Context, job *entity.Job, workerID string, visibleAt time.Time) (
+	claimed bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.jobs[job.ID]
+	if !ok || stored.Attempts != job.Attempts || stored.Status == entity.JobStatusDead {
+		return false, nil
+	}
+	stored.Status, stored.Attempts, stored.AvailableAt, stored.LockedBy =
+		entity.JobStatusRunning, stored.Attempts+1, visibleAt, workerID
+	*job = *stored
+	return true, nil
+}
+
+// owned whether the job is still claimed by the worker
+func (r *memoryJobRepo) owned(job *entity.Job) bool {
+	stored, ok := r.jobs[job.ID]
+	return ok && stored.Status == entity.JobStatusRunning &&
+		stored.LockedBy == job.LockedBy && stored.Attempts == job.Attempts
+}
+
+func (r *memoryJobRepo) RemoveJob(ctx context.Context, job *entity.Job) (owned bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.owned(job) {
+		return false, nil
+	}
+	delete(r.jobs, job.ID)
+	return true, nil
+}
+
+func (r *memoryJobRepo) RetryJob(ctx context.Context, job *entity.Job, availableAt time.Time, lastError string) (
+	owned bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.owned(job) {
+		return false, nil
+	}
+	// retry immediately for testing
+	stored := r.jobs[job.ID]
+	stored.Status, stored.AvailableAt, stored.LockedBy, stored.LastError =
+		entity.JobStatusPending, time.Now(), "", lastError
+	return true, nil
+}
+
+func (r *memoryJobRepo) DeadJob(ctx context.Context, job *entity.Job, lastError string) (owned bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.owned(job) {
+		return false, nil
+	}
+	stored := r.jobs[job.ID]
+	stored.Status, stored.LockedBy, stored.LastError = entity.JobStatusDead, "", lastError
+	return true, nil
+}
+
+func (r *memoryJobRepo) get(id string) entity.Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job, ok := r.jobs[id]; ok {
+		return *job
+	}
+	return entity.Job{}
+}
+
+func TestDBQueue_Retry(t *testing.T) {
+	jobRepo := newMemoryJobRepo()
+	queue := NewDBQueue(&data.QueueConf{Workers: 3, MaxAttempts: 3}, jobRepo)
+	defer queue.Close()
+
+	var mu sync.Mutex
+	calls := 0
+	done := make(chan string, 1)
+	queue.Subscribe("test", func(ctx context.Context, payload []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls < 2 {
+			return fmt.Errorf("failed")
+		}
+		done <- string(payload)
+		return nil
+	})
+	assert.NoError(t, queue.Publish(context.TODO(), "test", []byte("hello")))
+
+	select {
+	case payload := <-done:
+		assert.Equal(t, "hello", payload)
+	case <-time.After(5 * time.Second):
+		t.Fatal("job is not handled")
+	}
+	assert.Eventually(t, func() bool { return jobRepo.get("1").ID == "" }, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, 2, calls)
+	mu.Unlock()
+}
+
+func TestDBQueue_Dead(t *testing.T) {
+	jobRepo := newMemoryJobRepo()
+	queue := NewDBQueue(&data.QueueConf{Workers: 1, MaxAttempts: 2}, jobRepo)
+	defer queue.Close()
+
+	queue.Subscribe("test", func(ctx context.Context, payload []byte) error {
+		panic("always failed")
+	})
+	assert.NoError(t, queue.Publish(context.TODO(), "test", []byte("hello")))
+
+	assert.Eventually(t, func() bool {
+		return jobRepo.get("1").Status == entity.JobStatusDead
+	}, 5*time.Second, 10*time.Millisecond)
+	job := jobRepo.get("1")
+	assert.Equal(t, 2, job.Attempts)
+	assert.Contains(t, job.LastError, "always failed")
+}
+
+func TestDBQueue_LastAttempt(t *testing.T) {
+	jobRepo := newMemoryJobRepo()
+	queue := NewDBQueue(&data.QueueConf{Workers: 1, MaxAttempts: 2}, jobRepo)
+	defer queue.Close()
+
+	lastAttempts := make(chan bool, 2)
+	queue.Subscribe("test", func(ctx context.Context, payload []byte) error {
+		lastAttempts <- IsLastAttempt(ctx)
+		return fmt.Errorf("failed")
+	})
+	assert.NoError(t, queue.Publish(context.TODO(), "test", []byte("hello")))
+
+	for _, expected := range []bool{false, true} {
+		select {
+		case last := <-lastAttempts:
+			assert.Equal(t, expected, last)
+		case <-time.After(5 * time.Second):
+			t.Fatal("job is not handled")
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, retryBaseDelay, retryDelay(1))
+	assert.Equal(t, 2*retryBaseDelay, retryDelay(2))
+	assert.Equal(t, retryMaxDelay, retryDelay(100))
+}
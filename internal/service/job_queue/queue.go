@@ -0,0 +1,75 @@
+package job_queue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/segmentfault/pacman/log"
+)
+
+// Handler handle the message payload of topic, the message will be delivered again if error returned,
+// so the handler should be idempotent.
+type Handler func(ctx context.Context, payload []byte) error
+
+// Queue deliver the published messages to the handler subscribed the topic
+type Queue interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+	Subscribe(topic string, handler Handler)
+}
+
+// JobRepo job repository
+type JobRepo interface {
+	AddJob(ctx context.Context, job *entity.Job) (err error)
+	GetAvailableJobs(ctx context.Context, topics []string, now time.Time, limit int) (jobs []*entity.Job, err error)
+	ClaimJob(ctx context.Context, job *entity.Job, workerID string, visibleAt time.Time) (claimed bool, err error)
+	// RemoveJob, RetryJob and DeadJob only change the job still claimed by the worker,
+	// owned is false if it has been claimed by another worker after the visibility timeout
+	RemoveJob(ctx context.Context, job *entity.Job) (owned bool, err error)
+	RetryJob(ctx context.Context, job *entity.Job, availableAt time.Time, lastError string) (owned bool, err error)
+	DeadJob(ctx context.Context, job *entity.Job, lastError string) (owned bool, err error)
+}
+
+type lastAttemptKey struct{}
+
+// withLastAttempt mark whether the message will be delivered again if the handler returns error
+func withLastAttempt(ctx context.Context, last bool) context.Context {
+	return context.WithValue(ctx, lastAttemptKey{}, last)
+}
+
+// IsLastAttempt whether the message is handled for the last time, the message will never be delivered again
+// if the handler returns error, so the handler should record the failure by itself.
+func IsLastAttempt(ctx context.Context) bool {
+	last, _ := ctx.Value(lastAttemptKey{}).(bool)
+	return last
+}
+
+// defaultQueue the queue used by Publish, it will be replaced by the configured queue when the application starts
+var defaultQueue Queue = NewChannelQueue()
+
+// NewJobQueue new queue by config, the database queue is used by default.
+// The created queue is also used as the default queue of Publish.
+func NewJobQueue(queueConf *data.QueueConf, jobRepo JobRepo) (Queue, func(), error) {
+	if queueConf != nil && queueConf.Driver == data.QueueDriverChannel {
+		queue := NewChannelQueue()
+		defaultQueue = queue
+		return queue, func() {}, nil
+	}
+	queue := NewDBQueue(queueConf, jobRepo)
+	defaultQueue = queue
+	return queue, queue.Close, nil
+}
+
+// Publish encode the message as json and publish it to the default queue
+func Publish(topic string, msg interface{}) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		log.Errorf("encode %s message failed: %s", topic, err)
+		return
+	}
+	if err = defaultQueue.Publish(context.Background(), topic, payload); err != nil {
+		log.Errorf("publish %s message failed: %s", topic, err)
+	}
+}
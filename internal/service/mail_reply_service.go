@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/comment"
+	"github.com/answerdev/answer/internal/service/comment_common"
+	"github.com/answerdev/answer/internal/service/export"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/rank"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/pkg/mailreply"
+	"github.com/answerdev/answer/pkg/obj"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// MailReplyService create answers and comments from the replies of notification emails
+type MailReplyService struct {
+	emailService      *export.EmailService
+	userRepo          usercommon.UserRepo
+	commentCommonRepo comment_common.CommentCommonRepo
+	commentService    *comment.CommentService
+	answerService     *AnswerService
+	rankService       *rank.RankService
+}
+
+// NewMailReplyService new mail reply service
+func NewMailReplyService(
+	emailService *export.EmailService,
+	userRepo usercommon.UserRepo,
+	commentCommonRepo comment_common.CommentCommonRepo,
+	commentService *comment.CommentService,
+	answerService *AnswerService,
+	rankService *rank.RankService,
+) *MailReplyService {
+	return &MailReplyService{
+		emailService:      emailService,
+		userRepo:          userRepo,
+		commentCommonRepo: commentCommonRepo,
+		commentService:    commentService,
+		answerService:     answerService,
+		rankService:       rankService,
+	}
+}
+
+// CheckRecipient check the recipient is a valid reply address
+func (ms *MailReplyService) CheckRecipient(ctx context.Context, address string) (err error) {
+	_, _, err = ms.parseReplyAddress(address)
+	return err
+}
+
+// HandleMail post the reply as the user the reply address is signed for.
+// The reply to a question becomes an answer, the reply to an answer becomes a comment on it
+// and the reply to a comment becomes a reply comment.
+func (ms *MailReplyService) HandleMail(ctx context.Context, recipients []string, r io.Reader) (err error) {
+	var userID, objectID string
+	for _, recipient := range recipients {
+		userID, objectID, err = ms.parseReplyAddress(recipient)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	msg, err := mailreply.ReadMessage(r)
+	if err != nil {
+		log.Warnf("read reply mail failed: %s", err)
+		return errors.BadRequest(reason.MailReplyContentEmpty)
+	}
+
+	userInfo, exist, err := ms.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exist || userInfo.Status == entity.UserStatusDeleted {
+		return errors.BadRequest(reason.UserNotFound)
+	}
+	if userInfo.Status == entity.UserStatusSuspended {
+		return errors.Forbidden(reason.UserSuspended)
+	}
+	if userInfo.MailStatus != entity.EmailStatusAvailable {
+		return errors.Forbidden(reason.EmailNeedToBeVerified)
+	}
+	// the reply address may be forwarded, so the sender must be the owner of it
+	if !strings.EqualFold(msg.From, userInfo.EMail) {
+		return errors.Forbidden(reason.MailReplySenderNotMatch)
+	}
+
+	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return err
+	}
+	switch objectType {
+	case constant.QuestionObjectType:
+		return ms.addAnswer(ctx, userID, objectID, msg.Text)
+	case constant.AnswerObjectType:
+		return ms.addComment(ctx, userID, objectID, "", msg.Text)
+	case constant.CommentObjectType:
+		replyComment, exist, err := ms.commentCommonRepo.GetComment(ctx, objectID)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return errors.BadRequest(reason.CommentNotFound)
+		}
+		return ms.addComment(ctx, userID, replyComment.ObjectID, replyComment.ID, msg.Text)
+	default:
+		return errors.BadRequest(reason.MailReplyAddressInvalid)
+	}
+}
+
+func (ms *MailReplyService) addAnswer(ctx context.Context, userID, questionID, content string) (err error) {
+	can, err := ms.rankService.CheckOperationPermission(ctx, userID, permission.AnswerAdd, "")
+	if err != nil {
+		return err
+	}
+	if !can {
+		return errors.Forbidden(reason.RankFailToMeetTheCondition)
+	}
+
+	req := &schema.AnswerAddReq{QuestionID: questionID, Content: content, UserID: userID}
+	_, _ = req.Check()
+	answerID, err := ms.answerService.Insert(ctx, req)
+	if err != nil {
+		return err
+	}
+	log.Infof("user %s answered question %s by email: %s", userID, questionID, answerID)
+	return nil
+}
+
+func (ms *MailReplyService) addComment(ctx context.Context, userID, objectID, replyCommentID, content string) (
+	err error) {
+	canList, err := ms.rankService.CheckOperationPermissions(ctx, userID, []string{
+		permission.CommentAdd,
+		permission.CommentEdit,
+		permission.CommentDelete,
+	})
+	if err != nil {
+		return err
+	}
+	if !canList[0] {
+		return errors.Forbidden(reason.RankFailToMeetTheCondition)
+	}
+
+	req := &schema.AddCommentReq{
+		ObjectID:       objectID,
+		ReplyCommentID: replyCommentID,
+		OriginalText:   content,
+		UserID:         userID,
+		CanAdd:         canList[0],
+		CanEdit:        canList[1],
+		CanDelete:      canList[2],
+	}
+	_, _ = req.Check()
+	resp, err := ms.commentService.AddComment(ctx, req)
+	if err != nil {
+		return err
+	}
+	log.Infof("user %s commented on %s by email: %s", userID, objectID, resp.CommentID)
+	return nil
+}
+
+func (ms *MailReplyService) parseReplyAddress(address string) (userID, objectID string, err error) {
+	ec, err := ms.emailService.GetEmailConfig()
+	if err != nil {
+		return "", "", err
+	}
+	if len(ec.ReplyAddress) == 0 || len(ec.ReplySecret) == 0 {
+		return "", "", errors.BadRequest(reason.MailReplyAddressInvalid)
+	}
+	userID, objectID, err = mailreply.Parse(ec.ReplyAddress, ec.ReplySecret, address)
+	if err != nil {
+		return "", "", errors.BadRequest(reason.MailReplyAddressInvalid)
+	}
+	return userID, objectID, nil
+}
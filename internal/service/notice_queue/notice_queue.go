@@ -2,12 +2,17 @@ package notice_queue
 
 import (
 	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/job_queue"
+	"github.com/answerdev/answer/pkg/token"
 )
 
-var (
-	NotificationQueue = make(chan *schema.NotificationMsg, 128)
-)
+// Topic the topic of notification message
+const Topic = "notification"
 
+// AddNotification add new notification, the dedup key is generated if it is not set
 func AddNotification(msg *schema.NotificationMsg) {
-	NotificationQueue <- msg
+	if len(msg.DedupKey) == 0 {
+		msg.DedupKey = token.GenerateToken()
+	}
+	job_queue.Publish(Topic, msg)
 }
@@ -11,7 +11,9 @@ import (
 	"github.com/answerdev/answer/internal/base/translator"
 	"github.com/answerdev/answer/internal/schema"
 	notficationcommon "github.com/answerdev/answer/internal/service/notification_common"
+	"github.com/answerdev/answer/internal/service/pre_moderation"
 	"github.com/answerdev/answer/internal/service/revision_common"
+	"github.com/answerdev/answer/internal/service/stream"
 	"github.com/jinzhu/copier"
 	"github.com/segmentfault/pacman/i18n"
 	"github.com/segmentfault/pacman/log"
@@ -19,10 +21,11 @@ import (
 
 // NotificationService user service
 type NotificationService struct {
-	data               *data.Data
-	notificationRepo   notficationcommon.NotificationRepo
-	notificationCommon *notficationcommon.NotificationCommon
-	revisionService    *revision_common.RevisionService
+	data                 *data.Data
+	notificationRepo     notficationcommon.NotificationRepo
+	notificationCommon   *notficationcommon.NotificationCommon
+	revisionService      *revision_common.RevisionService
+	preModerationService *pre_moderation.PreModerationService
 }
 
 func NewNotificationService(
@@ -30,13 +33,14 @@ func NewNotificationService(
 	notificationRepo notficationcommon.NotificationRepo,
 	notificationCommon *notficationcommon.NotificationCommon,
 	revisionService *revision_common.RevisionService,
-
+	preModerationService *pre_moderation.PreModerationService,
 ) *NotificationService {
 	return &NotificationService{
-		data:               data,
-		notificationRepo:   notificationRepo,
-		notificationCommon: notificationCommon,
-		revisionService:    revisionService,
+		data:                 data,
+		notificationRepo:     notificationRepo,
+		notificationCommon:   notificationCommon,
+		revisionService:      revisionService,
+		preModerationService: preModerationService,
 	}
 }
 
@@ -66,6 +70,17 @@ func (ns *NotificationService) GetRedDot(ctx context.Context, req *schema.GetRed
 		}
 		redBot.Revision = revisionCountNum
 	}
+	pendingPostReq := &schema.GetPendingPostPageReq{
+		CanReviewQuestion: req.CanReviewQuestion,
+		CanReviewAnswer:   req.CanReviewAnswer,
+	}
+	if objectTypes := pendingPostReq.GetCanReviewObjectTypes(); len(objectTypes) > 0 {
+		pendingPostCount, err := ns.preModerationService.GetPendingPostCount(ctx, objectTypes)
+		if err != nil {
+			return redBot, err
+		}
+		redBot.PendingPost = pendingPostCount
+	}
 
 	return redBot, nil
 }
@@ -78,6 +93,8 @@ func (ns *NotificationService) ClearRedDot(ctx context.Context, req *schema.Noti
 		if err != nil {
 			log.Error("ClearRedDot del cache error", err.Error())
 		}
+		// the red dot is also cleared in the other pages of user
+		stream.Publish(ctx, stream.UserKey(req.UserID), stream.EventRedDot, nil)
 	}
 	getRedDotreq := &schema.GetRedDot{}
 	_ = copier.Copy(getRedDotreq, req)
@@ -11,8 +11,10 @@ import (
 	"github.com/answerdev/answer/internal/entity"
 	"github.com/answerdev/answer/internal/schema"
 	"github.com/answerdev/answer/internal/service/activity_common"
+	"github.com/answerdev/answer/internal/service/job_queue"
 	"github.com/answerdev/answer/internal/service/notice_queue"
 	"github.com/answerdev/answer/internal/service/object_info"
+	"github.com/answerdev/answer/internal/service/stream"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
 	"github.com/goccy/go-json"
 	"github.com/jinzhu/copier"
@@ -22,6 +24,7 @@ import (
 
 type NotificationRepo interface {
 	AddNotification(ctx context.Context, notification *entity.Notification) (err error)
+	ExistDedupKey(ctx context.Context, dedupKey string) (exist bool, err error)
 	GetNotificationPage(ctx context.Context, search *schema.NotificationSearch) ([]*entity.Notification, int64, error)
 	ClearUnRead(ctx context.Context, userID string, notificationType int) (err error)
 	ClearIDUnRead(ctx context.Context, userID string, id string) (err error)
@@ -46,6 +49,7 @@ func NewNotificationCommon(
 	activityRepo activity_common.ActivityRepo,
 	followRepo activity_common.FollowRepo,
 	objectInfoService *object_info.ObjService,
+	queue job_queue.Queue,
 ) *NotificationCommon {
 	notification := &NotificationCommon{
 		data:              data,
@@ -55,20 +59,19 @@ func NewNotificationCommon(
 		userCommon:        userCommon,
 		objectInfoService: objectInfoService,
 	}
-	notification.HandleNotification()
+	queue.Subscribe(notice_queue.Topic, notification.HandleNotification)
 	return notification
 }
 
-func (ns *NotificationCommon) HandleNotification() {
-	go func() {
-		for msg := range notice_queue.NotificationQueue {
-			log.Debugf("received notification %+v", msg)
-			err := ns.AddNotification(context.TODO(), msg)
-			if err != nil {
-				log.Error(err)
-			}
-		}
-	}()
+// HandleNotification handle notification message
+func (ns *NotificationCommon) HandleNotification(ctx context.Context, payload []byte) error {
+	msg := &schema.NotificationMsg{}
+	if err := json.Unmarshal(payload, msg); err != nil {
+		log.Errorf("decode notification message failed: %s", err)
+		return nil
+	}
+	log.Debugf("received notification %+v", msg)
+	return ns.AddNotification(ctx, msg)
 }
 
 // AddNotification
@@ -81,6 +84,16 @@ func (ns *NotificationCommon) HandleNotification() {
 // ObjectInfo.ObjectID
 // ObjectInfo.ObjectType
 func (ns *NotificationCommon) AddNotification(ctx context.Context, msg *schema.NotificationMsg) error {
+	if len(msg.DedupKey) > 0 {
+		exist, err := ns.notificationRepo.ExistDedupKey(ctx, msg.DedupKey)
+		if err != nil {
+			return err
+		}
+		if exist {
+			log.Debugf("notification %s has been added", msg.DedupKey)
+			return nil
+		}
+	}
 	req := &schema.NotificationContent{
 		TriggerUserID:  msg.TriggerUserID,
 		ReceiverUserID: msg.ReceiverUserID,
@@ -93,20 +106,24 @@ func (ns *NotificationCommon) AddNotification(ctx context.Context, msg *schema.N
 		Type:               msg.Type,
 	}
 	var questionID string // just for notify all followers
-	objInfo, err := ns.objectInfoService.GetInfo(ctx, req.ObjectInfo.ObjectID)
-	if err != nil {
-		log.Error(err)
-	} else {
-		req.ObjectInfo.Title = objInfo.Title
-		questionID = objInfo.QuestionID
-		objectMap := make(map[string]string)
-		objectMap["question"] = objInfo.QuestionID
-		objectMap["answer"] = objInfo.AnswerID
-		objectMap["comment"] = objInfo.CommentID
-		req.ObjectInfo.ObjectMap = objectMap
+	// the badge is not a post, its title is set by the sender and every award is a new notification
+	isBadge := msg.ObjectType == constant.BadgeObjectType
+	if !isBadge {
+		objInfo, err := ns.objectInfoService.GetInfo(ctx, req.ObjectInfo.ObjectID)
+		if err != nil {
+			log.Error(err)
+		} else {
+			req.ObjectInfo.Title = objInfo.Title
+			questionID = objInfo.QuestionID
+			objectMap := make(map[string]string)
+			objectMap["question"] = objInfo.QuestionID
+			objectMap["answer"] = objInfo.AnswerID
+			objectMap["comment"] = objInfo.CommentID
+			req.ObjectInfo.ObjectMap = objectMap
+		}
 	}
 
-	if msg.Type == schema.NotificationTypeAchievement {
+	if msg.Type == schema.NotificationTypeAchievement && !isBadge {
 		notificationInfo, exist, err := ns.notificationRepo.GetByUserIdObjectIdTypeId(ctx, req.ReceiverUserID, req.ObjectInfo.ObjectID, req.Type)
 		if err != nil {
 			return errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
@@ -146,6 +163,9 @@ func (ns *NotificationCommon) AddNotification(ctx context.Context, msg *schema.N
 	info.CreatedAt = now
 	info.UpdatedAt = now
 	info.ObjectID = req.ObjectInfo.ObjectID
+	if len(msg.DedupKey) > 0 {
+		info.DedupKey = &msg.DedupKey
+	}
 
 	userBasicInfo, exist, err := ns.userCommon.GetUserBasicInfoByID(ctx, req.TriggerUserID)
 	if err != nil {
@@ -168,6 +188,10 @@ func (ns *NotificationCommon) AddNotification(ctx context.Context, msg *schema.N
 	if err != nil {
 		log.Error("addRedDot Error", err.Error())
 	}
+	req.ID = info.ID
+	req.UpdateTime = info.UpdatedAt.Unix()
+	stream.Publish(ctx, stream.UserKey(info.UserID), stream.EventNotification, req)
+	stream.Publish(ctx, stream.UserKey(info.UserID), stream.EventRedDot, nil)
 
 	go ns.SendNotificationToAllFollower(context.Background(), msg, questionID)
 	return nil
@@ -210,6 +234,10 @@ func (ns *NotificationCommon) SendNotificationToAllFollower(ctx context.Context,
 		t.ReceiverUserID = userID
 		t.TriggerUserID = msg.TriggerUserID
 		t.NoNeedPushAllFollow = true
+		// the key is derived from the message, so that the followers are notified only once if it is redelivered
+		if len(msg.DedupKey) > 0 {
+			t.DedupKey = msg.DedupKey + "-" + userID
+		}
 		notice_queue.AddNotification(t)
 	}
 }
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/pager"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/answerdev/answer/internal/service/comment"
+	"github.com/answerdev/answer/internal/service/comment_common"
+	"github.com/answerdev/answer/internal/service/notice_queue"
+	"github.com/answerdev/answer/internal/service/object_info"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/pre_moderation"
+	questioncommon "github.com/answerdev/answer/internal/service/question_common"
+	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/answerdev/answer/internal/service/spam"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// PendingPostService the review queue of the posts held by the pre-moderation policy
+type PendingPostService struct {
+	preModerationService *pre_moderation.PreModerationService
+	questionService      *QuestionService
+	answerService        *AnswerService
+	commentService       *comment.CommentService
+	questionRepo         questioncommon.QuestionRepo
+	answerRepo           answercommon.AnswerRepo
+	commentCommonRepo    comment_common.CommentCommonRepo
+	objectInfoService    *object_info.ObjService
+	userCommon           *usercommon.UserCommon
+	auditLogService      *audit_log.AuditLogService
+	spamService          *spam.SpamService
+	rankService          *rank.RankService
+}
+
+// NewPendingPostService new pending post service
+func NewPendingPostService(
+	preModerationService *pre_moderation.PreModerationService,
+	questionService *QuestionService,
+	answerService *AnswerService,
+	commentService *comment.CommentService,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	commentCommonRepo comment_common.CommentCommonRepo,
+	objectInfoService *object_info.ObjService,
+	userCommon *usercommon.UserCommon,
+	auditLogService *audit_log.AuditLogService,
+	spamService *spam.SpamService,
+	rankService *rank.RankService,
+) *PendingPostService {
+	return &PendingPostService{
+		preModerationService: preModerationService,
+		questionService:      questionService,
+		answerService:        answerService,
+		commentService:       commentService,
+		questionRepo:         questionRepo,
+		answerRepo:           answerRepo,
+		commentCommonRepo:    commentCommonRepo,
+		objectInfoService:    objectInfoService,
+		userCommon:           userCommon,
+		auditLogService:      auditLogService,
+		spamService:          spamService,
+		rankService:          rankService,
+	}
+}
+
+// GetPendingPostPage get the page of the posts waiting for review
+func (ps *PendingPostService) GetPendingPostPage(ctx context.Context, req *schema.GetPendingPostPageReq) (
+	resp *pager.PageModel, err error) {
+	list := make([]*schema.GetPendingPostResp, 0)
+	objectTypes := req.GetCanReviewObjectTypes()
+	if len(objectTypes) == 0 {
+		return pager.NewPageModel(0, list), nil
+	}
+	pendingPosts, total, err := ps.preModerationService.GetPendingPostPage(ctx, req.Page, req.PageSize, objectTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(pendingPosts))
+	for _, pendingPost := range pendingPosts {
+		userIDs = append(userIDs, pendingPost.UserID)
+	}
+	userInfoMapping, err := ps.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, pendingPost := range pendingPosts {
+		// the user who can review in some tags only does not see the posts out of them
+		if !req.CanReviewAll(pendingPost.ObjectType) &&
+			!ps.CanReviewByObject(ctx, req.UserID, pendingPost.ObjectType, pendingPost.ObjectID) {
+			continue
+		}
+		objInfo, err := ps.objectInfoService.GetInfo(ctx, pendingPost.ObjectID)
+		if err != nil {
+			log.Errorf("get pending post %s info failed: %v", pendingPost.ObjectID, err)
+			continue
+		}
+		extra := &schema.PendingPostExtra{}
+		if len(pendingPost.Extra) > 0 {
+			_ = json.Unmarshal([]byte(pendingPost.Extra), extra)
+		}
+		list = append(list, &schema.GetPendingPostResp{
+			ID:         pendingPost.ID,
+			CreatedAt:  pendingPost.CreatedAt.Unix(),
+			ObjectID:   pendingPost.ObjectID,
+			ObjectType: objInfo.ObjectType,
+			QuestionID: objInfo.QuestionID,
+			AnswerID:   objInfo.AnswerID,
+			Title:      objInfo.Title,
+			Content:    objInfo.Content,
+			UserInfo:   userInfoMapping[pendingPost.UserID],
+			SpamReason: extra.SpamReason,
+		})
+	}
+	return pager.NewPageModel(total, list), nil
+}
+
+// GetPendingPostObjectID get the id of the object held by the pending post, it is empty if the post is not found
+func (ps *PendingPostService) GetPendingPostObjectID(ctx context.Context, id string) (objectID string, err error) {
+	pendingPost, exist, err := ps.preModerationService.GetPendingPost(ctx, id)
+	if err != nil || !exist {
+		return "", err
+	}
+	return pendingPost.ObjectID, nil
+}
+
+// CanReviewByObject whether the user can review the post including the powers scoped to the tags of it,
+// the comment can be reviewed by the user who can review questions or answers
+func (ps *PendingPostService) CanReviewByObject(ctx context.Context, userID string, objectType int,
+	objectID string) bool {
+	canList, err := ps.rankService.CheckOperationPermissionsByObject(ctx, userID, []string{
+		permission.QuestionAudit,
+		permission.AnswerAudit,
+	}, objectID)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	switch constant.ObjectTypeNumberMapping[objectType] {
+	case constant.QuestionObjectType:
+		return canList[0]
+	case constant.AnswerObjectType:
+		return canList[1]
+	case constant.CommentObjectType:
+		return canList[0] || canList[1]
+	}
+	return false
+}
+
+// ReviewPendingPost approve or reject the pending post, the approved post is published and the rejected one
+// is deleted, the author is notified of the result
+func (ps *PendingPostService) ReviewPendingPost(ctx context.Context, req *schema.ReviewPendingPostReq) (err error) {
+	pendingPost, exist, err := ps.preModerationService.GetPendingPost(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist || pendingPost.Status != entity.PendingPostStatusPending {
+		return errors.BadRequest(reason.ObjectNotFound)
+	}
+	if !req.CanReview(pendingPost.ObjectType) {
+		return errors.Forbidden(reason.RankFailToMeetTheCondition)
+	}
+	objectType := constant.ObjectTypeNumberMapping[pendingPost.ObjectType]
+
+	approved := req.Operation == schema.PendingPostApprove
+	// the post deleted by the author is only removed from the queue
+	pending, err := ps.isObjectPending(ctx, objectType, pendingPost.ObjectID)
+	if err != nil {
+		return err
+	}
+	if !pending {
+		_, err = ps.preModerationService.ReviewPendingPost(ctx, pendingPost.ID, false, req.UserID)
+		return err
+	}
+
+	// the post is taken from the queue first, so that it is not reviewed by two reviewers at the same time
+	updated, err := ps.preModerationService.ReviewPendingPost(ctx, pendingPost.ID, approved, req.UserID)
+	if err != nil || !updated {
+		return err
+	}
+	// the post is read before the rejected one is deleted, but the classifier is only trained after the review
+	// is applied, otherwise the post put back to the queue is trained again when it is reviewed again
+	train := approved || req.Spam
+	var trainTokens []string
+	if train {
+		trainTokens = ps.spamService.ObjectTokens(ctx, pendingPost.ObjectID)
+	}
+	if err = ps.applyReview(ctx, pendingPost, objectType, approved); err != nil {
+		// the post is put back to the queue, so that it can be reviewed again
+		if cancelErr := ps.preModerationService.CancelReview(ctx, pendingPost.ID, approved); cancelErr != nil {
+			log.Errorf("put pending post %s back to the queue failed: %v", pendingPost.ID, cancelErr)
+		}
+		return err
+	}
+	if train {
+		ps.spamService.TrainTokens(ctx, trainTokens, !approved)
+	}
+
+	ps.notifyAuthor(pendingPost, objectType, approved, req.UserID)
+	ps.addReviewAuditLog(ctx, pendingPost, objectType, approved, req.UserID)
+	return nil
+}
+
+// applyReview publish the approved post or delete the rejected one
+func (ps *PendingPostService) applyReview(ctx context.Context, pendingPost *entity.PendingPost, objectType string,
+	approved bool) (err error) {
+	switch objectType {
+	case constant.QuestionObjectType:
+		if approved {
+			err = ps.questionService.PublishPendingQuestion(ctx, pendingPost.ObjectID)
+		} else {
+			err = ps.questionService.RejectPendingQuestion(ctx, pendingPost.ObjectID)
+		}
+	case constant.AnswerObjectType:
+		if approved {
+			err = ps.answerService.PublishPendingAnswer(ctx, pendingPost.ObjectID)
+		} else {
+			err = ps.answerService.RejectPendingAnswer(ctx, pendingPost.ObjectID)
+		}
+	case constant.CommentObjectType:
+		if approved {
+			err = ps.commentService.PublishPendingComment(ctx, pendingPost.ObjectID, pendingPost.Extra)
+		} else {
+			err = ps.commentService.RejectPendingComment(ctx, pendingPost.ObjectID)
+		}
+	}
+	return err
+}
+
+// isObjectPending whether the post is still waiting for review
+func (ps *PendingPostService) isObjectPending(ctx context.Context, objectType, objectID string) (
+	pending bool, err error) {
+	switch objectType {
+	case constant.QuestionObjectType:
+		questionInfo, exist, err := ps.questionRepo.GetQuestion(ctx, objectID)
+		return exist && questionInfo.Status == entity.QuestionStatusPending, err
+	case constant.AnswerObjectType:
+		answerInfo, exist, err := ps.answerRepo.GetByID(ctx, objectID)
+		return exist && answerInfo.Status == entity.AnswerStatusPending, err
+	case constant.CommentObjectType:
+		commentInfo, exist, err := ps.commentCommonRepo.GetComment(ctx, objectID)
+		return exist && commentInfo.Status == entity.CommentStatusPending, err
+	}
+	return false, nil
+}
+
+func (ps *PendingPostService) notifyAuthor(pendingPost *entity.PendingPost, objectType string, approved bool,
+	reviewUserID string) {
+	action := constant.YourPostWasRejected
+	if approved {
+		action = constant.YourPostWasApproved
+	}
+	notice_queue.AddNotification(&schema.NotificationMsg{
+		TriggerUserID:       reviewUserID,
+		ReceiverUserID:      pendingPost.UserID,
+		Type:                schema.NotificationTypeInbox,
+		ObjectID:            pendingPost.ObjectID,
+		ObjectType:          objectType,
+		NotificationAction:  action,
+		NoNeedPushAllFollow: true,
+	})
+}
+
+func (ps *PendingPostService) addReviewAuditLog(ctx context.Context, pendingPost *entity.PendingPost,
+	objectType string, approved bool, reviewUserID string) {
+	status := "rejected"
+	if approved {
+		status = "approved"
+	}
+	ps.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     reviewUserID,
+		Action:     entity.AuditActionPostReview,
+		ObjectType: objectType,
+		ObjectID:   pendingPost.ObjectID,
+		Before:     &schema.AuditLogReviewSnapshot{ID: pendingPost.ID, Status: "pending"},
+		After:      &schema.AuditLogReviewSnapshot{ID: pendingPost.ID, Status: status},
+	})
+}
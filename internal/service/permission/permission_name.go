@@ -7,14 +7,19 @@ const (
 	QuestionEditWithoutReview = "question.edit_without_review"
 	QuestionDelete            = "question.delete"
 	QuestionClose             = "question.close"
+	QuestionMerge             = "question.merge"
+	QuestionLock              = "question.lock"
+	QuestionProtect           = "question.protect"
 	QuestionReopen            = "question.reopen"
 	QuestionVoteUp            = "question.vote_up"
 	QuestionVoteDown          = "question.vote_down"
 	AnswerAdd                 = "answer.add"
+	AnswerAddProtected        = "answer.add_protected"
 	AnswerEdit                = "answer.edit"
 	AnswerEditWithoutReview   = "answer.edit_without_review"
 	AnswerDelete              = "answer.delete"
 	AnswerAccept              = "answer.accept"
+	AnswerLock                = "answer.lock"
 	AnswerVoteUp              = "answer.vote_up"
 	AnswerVoteDown            = "answer.vote_down"
 	CommentAdd                = "comment.add"
@@ -0,0 +1,320 @@
+package post_lock
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/activity_queue"
+	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/answerdev/answer/internal/service/object_info"
+	"github.com/answerdev/answer/internal/service/permission"
+	questioncommon "github.com/answerdev/answer/internal/service/question_common"
+	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// PostLockRepo post lock repository
+type PostLockRepo interface {
+	UpdateQuestionLock(ctx context.Context, questionID string, locked bool, expiredAt time.Time) (err error)
+	UpdateQuestionProtect(ctx context.Context, questionID string, protected bool, expiredAt time.Time) (err error)
+	UpdateAnswerLock(ctx context.Context, answerID string, locked bool, expiredAt time.Time) (err error)
+	// ExpirePostLocks unlock the posts and unprotect the questions which are expired before the time
+	ExpirePostLocks(ctx context.Context, before time.Time) (expired *schema.ExpiredPostLocks, err error)
+}
+
+// systemUserID the user of the activities done by the system, such as releasing the expired locks
+const systemUserID = "0"
+
+// PostLockService lock the questions and answers, and protect the questions
+type PostLockService struct {
+	postLockRepo      PostLockRepo
+	questionRepo      questioncommon.QuestionRepo
+	answerRepo        answercommon.AnswerRepo
+	objectInfoService *object_info.ObjService
+	rankService       *rank.RankService
+	auditLogService   *audit_log.AuditLogService
+}
+
+// NewPostLockService new post lock service
+func NewPostLockService(
+	postLockRepo PostLockRepo,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	objectInfoService *object_info.ObjService,
+	rankService *rank.RankService,
+	auditLogService *audit_log.AuditLogService,
+) *PostLockService {
+	return &PostLockService{
+		postLockRepo:      postLockRepo,
+		questionRepo:      questionRepo,
+		answerRepo:        answerRepo,
+		objectInfoService: objectInfoService,
+		rankService:       rankService,
+		auditLogService:   auditLogService,
+	}
+}
+
+// LockPost lock the question or answer
+func (ps *PostLockService) LockPost(ctx context.Context, req *schema.LockPostReq) (err error) {
+	return ps.setPostLock(ctx, req.ObjectID, req.UserID, true, expiredAt(req.Duration))
+}
+
+// UnlockPost unlock the question or answer
+func (ps *PostLockService) UnlockPost(ctx context.Context, req *schema.UnlockPostReq) (err error) {
+	return ps.setPostLock(ctx, req.ObjectID, req.UserID, false, time.Time{})
+}
+
+func (ps *PostLockService) setPostLock(ctx context.Context, objectID, userID string, locked bool,
+	lockExpiredAt time.Time) (err error) {
+	objInfo, err := ps.objectInfoService.GetInfo(ctx, objectID)
+	if err != nil {
+		return err
+	}
+	var (
+		auditAction     string
+		activityTypeKey constant.ActivityTypeKey
+		beforeLocked    bool
+	)
+	switch objInfo.ObjectType {
+	case constant.QuestionObjectType:
+		questionInfo, exist, err := ps.questionRepo.GetQuestion(ctx, objInfo.QuestionID)
+		if err != nil {
+			return err
+		}
+		if !exist || questionInfo.Status == entity.QuestionStatusDeleted ||
+			questionInfo.Status == entity.QuestionStatusPending {
+			return errors.BadRequest(reason.ObjectCannotLock)
+		}
+		beforeLocked = questionInfo.IsLocked()
+		auditAction = entity.AuditActionQuestionLock
+		activityTypeKey = constant.ActQuestionUnlocked
+		if locked {
+			activityTypeKey = constant.ActQuestionLocked
+		}
+		err = ps.postLockRepo.UpdateQuestionLock(ctx, questionInfo.ID, locked, lockExpiredAt)
+		if err != nil {
+			return err
+		}
+	case constant.AnswerObjectType:
+		answerInfo, exist, err := ps.answerRepo.GetAnswer(ctx, objInfo.AnswerID)
+		if err != nil {
+			return err
+		}
+		if !exist || answerInfo.Status != entity.AnswerStatusAvailable {
+			return errors.BadRequest(reason.ObjectCannotLock)
+		}
+		beforeLocked = answerInfo.IsLocked()
+		auditAction = entity.AuditActionAnswerLock
+		activityTypeKey = constant.ActAnswerUnlocked
+		if locked {
+			activityTypeKey = constant.ActAnswerLocked
+		}
+		err = ps.postLockRepo.UpdateAnswerLock(ctx, answerInfo.ID, locked, lockExpiredAt)
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.BadRequest(reason.ObjectCannotLock)
+	}
+	return ps.addLockRecord(ctx, &lockRecord{
+		objectID:        objectID,
+		objectType:      objInfo.ObjectType,
+		userID:          userID,
+		auditAction:     auditAction,
+		activityTypeKey: activityTypeKey,
+		status:          constant.ActLocked,
+		before:          beforeLocked,
+		after:           locked,
+		expiredAt:       lockExpiredAt,
+	})
+}
+
+// ProtectQuestion protect the question, only the users with enough reputation can answer it
+func (ps *PostLockService) ProtectQuestion(ctx context.Context, req *schema.ProtectQuestionReq) (err error) {
+	return ps.setQuestionProtect(ctx, req.QuestionID, req.UserID, true, expiredAt(req.Duration))
+}
+
+// UnprotectQuestion unprotect the question
+func (ps *PostLockService) UnprotectQuestion(ctx context.Context, req *schema.UnprotectQuestionReq) (err error) {
+	return ps.setQuestionProtect(ctx, req.QuestionID, req.UserID, false, time.Time{})
+}
+
+func (ps *PostLockService) setQuestionProtect(ctx context.Context, questionID, userID string, protected bool,
+	protectExpiredAt time.Time) (err error) {
+	questionInfo, exist, err := ps.questionRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return err
+	}
+	if !exist || questionInfo.Status == entity.QuestionStatusDeleted ||
+		questionInfo.Status == entity.QuestionStatusPending {
+		return errors.BadRequest(reason.QuestionNotFound)
+	}
+	beforeProtected := questionInfo.IsProtected()
+	if err = ps.postLockRepo.UpdateQuestionProtect(ctx, questionInfo.ID, protected, protectExpiredAt); err != nil {
+		return err
+	}
+	activityTypeKey := constant.ActQuestionUnprotected
+	if protected {
+		activityTypeKey = constant.ActQuestionProtected
+	}
+	return ps.addLockRecord(ctx, &lockRecord{
+		objectID:        questionInfo.ID,
+		objectType:      constant.QuestionObjectType,
+		userID:          userID,
+		auditAction:     entity.AuditActionQuestionProtect,
+		activityTypeKey: activityTypeKey,
+		status:          constant.ActProtected,
+		before:          beforeProtected,
+		after:           protected,
+		expiredAt:       protectExpiredAt,
+	})
+}
+
+// lockRecord the lock or protection of the post which is recorded
+type lockRecord struct {
+	objectID, objectType, userID string
+	auditAction                  string
+	activityTypeKey              constant.ActivityTypeKey
+	// status the status name when it is enabled, such as locked or protected
+	status        string
+	before, after bool
+	expiredAt     time.Time
+}
+
+// addLockRecord add the activity with the expiry shown in the timeline and the audit log of the lock or protection
+func (ps *PostLockService) addLockRecord(ctx context.Context, record *lockRecord) (err error) {
+	activity_queue.AddActivity(&schema.ActivityMsg{
+		UserID:           record.userID,
+		ObjectID:         record.objectID,
+		OriginalObjectID: record.objectID,
+		ActivityTypeKey:  record.activityTypeKey,
+		ExpiredAt:        unix(record.expiredAt),
+	})
+	ps.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     record.userID,
+		Action:     record.auditAction,
+		ObjectType: record.objectType,
+		ObjectID:   record.objectID,
+		Before:     &schema.AuditLogLockSnapshot{Status: record.statusOf(record.before)},
+		After: &schema.AuditLogLockSnapshot{
+			Status:    record.statusOf(record.after),
+			ExpiredAt: unix(record.expiredAt),
+		},
+	})
+	return nil
+}
+
+func (r *lockRecord) statusOf(enabled bool) string {
+	if enabled {
+		return r.status
+	}
+	return "un" + r.status
+}
+
+// CheckPostLocked check the post can be changed, the post is locked if it or its question is locked.
+// The users who can lock the post are not limited by the lock.
+func (ps *PostLockService) CheckPostLocked(ctx context.Context, objectID, userID string) (err error) {
+	objInfo, err := ps.objectInfoService.GetInfo(ctx, objectID)
+	if err != nil {
+		return err
+	}
+	if objInfo == nil || len(objInfo.QuestionID) == 0 {
+		return nil
+	}
+	action := ""
+	questionInfo, exist, err := ps.questionRepo.GetQuestion(ctx, objInfo.QuestionID)
+	if err != nil {
+		return err
+	}
+	if exist && questionInfo.IsLocked() {
+		action = permission.QuestionLock
+	} else if len(objInfo.AnswerID) > 0 {
+		answerInfo, exist, err := ps.answerRepo.GetAnswer(ctx, objInfo.AnswerID)
+		if err != nil {
+			return err
+		}
+		if exist && answerInfo.IsLocked() {
+			action = permission.AnswerLock
+		}
+	}
+	if len(action) == 0 {
+		return nil
+	}
+	can, err := ps.rankService.CheckOperationPermissionByObject(ctx, userID, action, objInfo.QuestionID)
+	if err != nil {
+		return err
+	}
+	if !can {
+		return errors.BadRequest(reason.ObjectLocked)
+	}
+	return nil
+}
+
+// CheckCanAnswer check the user can answer the question, the protected question can be answered only by
+// the users with enough reputation
+func (ps *PostLockService) CheckCanAnswer(ctx context.Context, questionInfo *entity.Question, userID string) (
+	err error) {
+	if err = ps.CheckPostLocked(ctx, questionInfo.ID, userID); err != nil {
+		return err
+	}
+	if !questionInfo.IsProtected() {
+		return nil
+	}
+	can, err := ps.rankService.CheckOperationPermissionByObject(ctx, userID, permission.AnswerAddProtected,
+		questionInfo.ID)
+	if err != nil {
+		return err
+	}
+	if !can {
+		return errors.Forbidden(reason.QuestionProtected)
+	}
+	return nil
+}
+
+// ExpirePostLocks unlock the posts and unprotect the questions whose lock or protection is expired
+func (ps *PostLockService) ExpirePostLocks(ctx context.Context) {
+	expired, err := ps.postLockRepo.ExpirePostLocks(ctx, time.Now())
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	ps.addExpiredActivities(expired.LockedQuestionIDs, constant.ActQuestionUnlocked)
+	ps.addExpiredActivities(expired.ProtectedQuestionIDs, constant.ActQuestionUnprotected)
+	ps.addExpiredActivities(expired.LockedAnswerIDs, constant.ActAnswerUnlocked)
+	affected := len(expired.LockedQuestionIDs) + len(expired.ProtectedQuestionIDs) + len(expired.LockedAnswerIDs)
+	if affected > 0 {
+		log.Infof("%d expired post locks are released", affected)
+	}
+}
+
+// addExpiredActivities add the activities of the released posts to the timeline, they are done by the system
+func (ps *PostLockService) addExpiredActivities(objectIDs []string, activityTypeKey constant.ActivityTypeKey) {
+	for _, objectID := range objectIDs {
+		activity_queue.AddActivity(&schema.ActivityMsg{
+			UserID:           systemUserID,
+			ObjectID:         objectID,
+			OriginalObjectID: objectID,
+			ActivityTypeKey:  activityTypeKey,
+		})
+	}
+}
+
+func expiredAt(hours int) time.Time {
+	if hours <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(hours) * time.Hour)
+}
+
+func unix(t time.Time) int64 {
+	if t.Unix() <= 0 {
+		return 0
+	}
+	return t.Unix()
+}
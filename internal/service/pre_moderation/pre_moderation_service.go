@@ -0,0 +1,194 @@
+package pre_moderation
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/comment_common"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/role"
+	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	"github.com/answerdev/answer/internal/service/stream"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/pkg/obj"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// PendingPostRepo pending post repository
+type PendingPostRepo interface {
+	AddPendingPost(ctx context.Context, pendingPost *entity.PendingPost) (err error)
+	GetPendingPost(ctx context.Context, id string) (pendingPost *entity.PendingPost, exist bool, err error)
+	GetPendingPostByObjectID(ctx context.Context, objectID string) (
+		pendingPost *entity.PendingPost, exist bool, err error)
+	GetPendingPostPage(ctx context.Context, page, pageSize int, objectTypes []int) (
+		pendingPosts []*entity.PendingPost, total int64, err error)
+	GetPendingPostCount(ctx context.Context, objectTypes []int) (count int64, err error)
+	UpdatePendingPostStatus(ctx context.Context, id string, status int, reviewUserID string) (updated bool, err error)
+	// ReleasePendingPost put the reviewed post with the status back to the queue
+	ReleasePendingPost(ctx context.Context, id string, status int) (err error)
+}
+
+// PreModerationService pre-moderation service, it holds the new posts of the users matching the policy for review
+type PreModerationService struct {
+	pendingPostRepo       PendingPostRepo
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
+	userRoleRelService    *role.UserRoleRelService
+	rolePowerRelService   *role.RolePowerRelService
+	userRepo              usercommon.UserRepo
+	commentCommonRepo     comment_common.CommentCommonRepo
+}
+
+// NewPreModerationService new pre-moderation service
+func NewPreModerationService(
+	pendingPostRepo PendingPostRepo,
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+	userRoleRelService *role.UserRoleRelService,
+	rolePowerRelService *role.RolePowerRelService,
+	userRepo usercommon.UserRepo,
+	commentCommonRepo comment_common.CommentCommonRepo,
+) *PreModerationService {
+	return &PreModerationService{
+		pendingPostRepo:       pendingPostRepo,
+		siteInfoCommonService: siteInfoCommonService,
+		userRoleRelService:    userRoleRelService,
+		rolePowerRelService:   rolePowerRelService,
+		userRepo:              userRepo,
+		commentCommonRepo:     commentCommonRepo,
+	}
+}
+
+// NeedReview whether the new post of the user should be held for review
+func (ps *PreModerationService) NeedReview(ctx context.Context, userID string) (need bool, err error) {
+	conf, err := ps.siteInfoCommonService.GetSitePreModeration(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !conf.Enabled {
+		return false, nil
+	}
+	roleID, err := ps.userRoleRelService.GetUserRole(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	powers, err := ps.rolePowerRelService.GetRolePowerList(ctx, roleID)
+	if err != nil {
+		return false, err
+	}
+	if canReview(roleID, powers) {
+		return false, nil
+	}
+	userInfo, exist, err := ps.userRepo.GetByUserID(ctx, userID)
+	if err != nil || !exist {
+		return false, err
+	}
+	commentCount, err := ps.commentCommonRepo.GetUserCommentCount(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return needReview(conf, userInfo, int(commentCount)), nil
+}
+
+// canReview the posts of the users who can review the pending posts are never held,
+// they are the admins, the moderators and the users of the custom roles with the audit powers
+func canReview(roleID int, powers []string) bool {
+	if roleID == role.RoleAdminID || roleID == role.RoleModeratorID {
+		return true
+	}
+	for _, power := range powers {
+		if power == permission.QuestionAudit || power == permission.AnswerAudit {
+			return true
+		}
+	}
+	return false
+}
+
+// needReview the published questions, answers and comments are counted as the posts of the user
+func needReview(conf *schema.SitePreModerationResp, userInfo *entity.User, commentCount int) bool {
+	if !conf.Enabled {
+		return false
+	}
+	posts := userInfo.QuestionCount + userInfo.AnswerCount + commentCount
+	return posts < conf.FirstPosts || userInfo.Rank < conf.MinRank
+}
+
+// AddPendingPost hold the post for review, extra is the data needed to publish the post
+func (ps *PreModerationService) AddPendingPost(ctx context.Context, userID, objectID string,
+	extra *schema.PendingPostExtra) (err error) {
+	objectType, err := obj.GetObjectTypeNumberByObjectID(objectID)
+	if err != nil {
+		return errors.BadRequest(reason.ObjectNotFound)
+	}
+	extraJSON, _ := json.Marshal(extra)
+	err = ps.pendingPostRepo.AddPendingPost(ctx, &entity.PendingPost{
+		UserID:     userID,
+		ObjectID:   objectID,
+		ObjectType: objectType,
+		Status:     entity.PendingPostStatusPending,
+		Extra:      string(extraJSON),
+	})
+	if err != nil {
+		return err
+	}
+	stream.Publish(ctx, stream.ReviewKey, stream.EventRedDot, nil)
+	return nil
+}
+
+// GetPendingPost get pending post by id
+func (ps *PreModerationService) GetPendingPost(ctx context.Context, id string) (
+	pendingPost *entity.PendingPost, exist bool, err error) {
+	return ps.pendingPostRepo.GetPendingPost(ctx, id)
+}
+
+// IsPending whether the post is waiting for review
+func (ps *PreModerationService) IsPending(ctx context.Context, objectID string) (pending bool, err error) {
+	pendingPost, exist, err := ps.pendingPostRepo.GetPendingPostByObjectID(ctx, objectID)
+	if err != nil {
+		return false, err
+	}
+	return exist && pendingPost.Status == entity.PendingPostStatusPending, nil
+}
+
+// GetPendingPostPage get the page of the posts waiting for review
+func (ps *PreModerationService) GetPendingPostPage(ctx context.Context, page, pageSize int, objectTypes []int) (
+	pendingPosts []*entity.PendingPost, total int64, err error) {
+	return ps.pendingPostRepo.GetPendingPostPage(ctx, page, pageSize, objectTypes)
+}
+
+// GetPendingPostCount get the count of the posts waiting for review
+func (ps *PreModerationService) GetPendingPostCount(ctx context.Context, objectTypes []int) (count int64, err error) {
+	return ps.pendingPostRepo.GetPendingPostCount(ctx, objectTypes)
+}
+
+// ReviewPendingPost mark the pending post as reviewed, it returns false if the post has been reviewed by others
+func (ps *PreModerationService) ReviewPendingPost(ctx context.Context, id string, approved bool, reviewUserID string) (
+	updated bool, err error) {
+	status := entity.PendingPostStatusRejected
+	if approved {
+		status = entity.PendingPostStatusApproved
+	}
+	updated, err = ps.pendingPostRepo.UpdatePendingPostStatus(ctx, id, status, reviewUserID)
+	if err != nil {
+		return false, err
+	}
+	if updated {
+		stream.Publish(ctx, stream.ReviewKey, stream.EventRedDot, nil)
+	}
+	return updated, nil
+}
+
+// CancelReview put the post back to the queue if it can not be published or rejected, so that it can be
+// reviewed again
+func (ps *PreModerationService) CancelReview(ctx context.Context, id string, approved bool) (err error) {
+	status := entity.PendingPostStatusRejected
+	if approved {
+		status = entity.PendingPostStatusApproved
+	}
+	if err = ps.pendingPostRepo.ReleasePendingPost(ctx, id, status); err != nil {
+		return err
+	}
+	stream.Publish(ctx, stream.ReviewKey, stream.EventRedDot, nil)
+	return nil
+}
@@ -0,0 +1,36 @@
+package pre_moderation
+
+import (
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/role"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_needReview(t *testing.T) {
+	conf := &schema.SitePreModerationResp{Enabled: true, FirstPosts: 2, MinRank: 10}
+	newUser := &entity.User{QuestionCount: 1, Rank: 100}
+	lowRankUser := &entity.User{QuestionCount: 3, AnswerCount: 5, Rank: 1}
+	trustedUser := &entity.User{QuestionCount: 1, AnswerCount: 1, Rank: 10}
+
+	assert.True(t, needReview(conf, newUser, 0))
+	assert.True(t, needReview(conf, lowRankUser, 0))
+	assert.False(t, needReview(conf, trustedUser, 0))
+	// the published comments are also counted
+	assert.False(t, needReview(conf, newUser, 1))
+
+	conf.Enabled = false
+	assert.False(t, needReview(conf, newUser, 0))
+}
+
+func Test_canReview(t *testing.T) {
+	// the posts of the moderators are never held
+	assert.True(t, canReview(role.RoleModeratorID, nil))
+	assert.True(t, canReview(role.RoleAdminID, nil))
+	assert.False(t, canReview(role.RoleUserID, []string{permission.QuestionAdd}))
+	// the custom role with the audit power
+	assert.True(t, canReview(4, []string{permission.AnswerAudit}))
+}
@@ -1,23 +1,33 @@
 package service
 
 import (
+	"github.com/answerdev/answer/internal/service/access_token"
 	"github.com/answerdev/answer/internal/service/action"
 	"github.com/answerdev/answer/internal/service/activity"
 	"github.com/answerdev/answer/internal/service/activity_common"
 	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	"github.com/answerdev/answer/internal/service/audit_log"
 	"github.com/answerdev/answer/internal/service/auth"
+	"github.com/answerdev/answer/internal/service/badge"
+	"github.com/answerdev/answer/internal/service/bounty"
 	collectioncommon "github.com/answerdev/answer/internal/service/collection_common"
 	"github.com/answerdev/answer/internal/service/comment"
 	"github.com/answerdev/answer/internal/service/comment_common"
 	"github.com/answerdev/answer/internal/service/dashboard"
+	"github.com/answerdev/answer/internal/service/email_digest"
 	"github.com/answerdev/answer/internal/service/export"
 	"github.com/answerdev/answer/internal/service/follow"
+	"github.com/answerdev/answer/internal/service/job_queue"
 	"github.com/answerdev/answer/internal/service/meta"
 	"github.com/answerdev/answer/internal/service/notification"
 	notficationcommon "github.com/answerdev/answer/internal/service/notification_common"
 	"github.com/answerdev/answer/internal/service/object_info"
+	"github.com/answerdev/answer/internal/service/post_lock"
+	"github.com/answerdev/answer/internal/service/pre_moderation"
 	questioncommon "github.com/answerdev/answer/internal/service/question_common"
+	"github.com/answerdev/answer/internal/service/question_duplicate"
 	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/answerdev/answer/internal/service/rate_limit"
 	"github.com/answerdev/answer/internal/service/reason"
 	"github.com/answerdev/answer/internal/service/report"
 	"github.com/answerdev/answer/internal/service/report_admin"
@@ -25,13 +35,20 @@ import (
 	"github.com/answerdev/answer/internal/service/revision_common"
 	"github.com/answerdev/answer/internal/service/role"
 	"github.com/answerdev/answer/internal/service/search_parser"
+	"github.com/answerdev/answer/internal/service/search_queue"
+	"github.com/answerdev/answer/internal/service/serial_vote"
 	"github.com/answerdev/answer/internal/service/siteinfo"
 	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	"github.com/answerdev/answer/internal/service/spam"
+	"github.com/answerdev/answer/internal/service/stream"
 	"github.com/answerdev/answer/internal/service/tag"
 	tagcommon "github.com/answerdev/answer/internal/service/tag_common"
+	"github.com/answerdev/answer/internal/service/two_factor"
 	"github.com/answerdev/answer/internal/service/uploader"
 	"github.com/answerdev/answer/internal/service/user_admin"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/internal/service/user_external_login"
+	"github.com/answerdev/answer/internal/service/webhook"
 	"github.com/google/wire"
 )
 
@@ -62,6 +79,7 @@ var ProviderSetService = wire.NewSet(
 	rank.NewRankService,
 	search_parser.NewSearchParser,
 	NewSearchService,
+	search_queue.NewSearchQueueService,
 	meta.NewMetaService,
 	object_info.NewObjService,
 	report_handle_admin.NewReportHandle,
@@ -75,8 +93,26 @@ var ProviderSetService = wire.NewSet(
 	activity.NewAnswerActivityService,
 	dashboard.NewDashboardService,
 	activity_common.NewActivityCommon,
+	job_queue.NewJobQueue,
 	activity.NewActivityService,
 	role.NewRoleService,
 	role.NewUserRoleRelService,
 	role.NewRolePowerRelService,
+	user_external_login.NewUserExternalLoginService,
+	two_factor.NewTwoFactorService,
+	access_token.NewAccessTokenService,
+	webhook.NewWebhookService,
+	stream.NewStreamService,
+	email_digest.NewEmailDigestService,
+	NewMailReplyService,
+	badge.NewBadgeService,
+	bounty.NewBountyService,
+	serial_vote.NewSerialVoteService,
+	rate_limit.NewRateLimitService,
+	audit_log.NewAuditLogService,
+	pre_moderation.NewPreModerationService,
+	NewPendingPostService,
+	spam.NewSpamService,
+	question_duplicate.NewQuestionDuplicateService,
+	post_lock.NewPostLockService,
 )
@@ -13,6 +13,7 @@ import (
 	"github.com/answerdev/answer/internal/service/activity_queue"
 	"github.com/answerdev/answer/internal/service/config"
 	"github.com/answerdev/answer/internal/service/meta"
+	"github.com/answerdev/answer/internal/service/search_queue"
 	"github.com/answerdev/answer/pkg/checker"
 	"github.com/answerdev/answer/pkg/htmltext"
 	"github.com/segmentfault/pacman/errors"
@@ -50,16 +51,17 @@ type QuestionRepo interface {
 
 // QuestionCommon user service
 type QuestionCommon struct {
-	questionRepo     QuestionRepo
-	answerRepo       answercommon.AnswerRepo
-	voteRepo         activity_common.VoteRepo
-	followCommon     activity_common.FollowRepo
-	tagCommon        *tagcommon.TagCommonService
-	userCommon       *usercommon.UserCommon
-	collectionCommon *collectioncommon.CollectionCommon
-	AnswerCommon     *answercommon.AnswerCommon
-	metaService      *meta.MetaService
-	configRepo       config.ConfigRepo
+	questionRepo       QuestionRepo
+	answerRepo         answercommon.AnswerRepo
+	voteRepo           activity_common.VoteRepo
+	followCommon       activity_common.FollowRepo
+	tagCommon          *tagcommon.TagCommonService
+	userCommon         *usercommon.UserCommon
+	collectionCommon   *collectioncommon.CollectionCommon
+	AnswerCommon       *answercommon.AnswerCommon
+	metaService        *meta.MetaService
+	configRepo         config.ConfigRepo
+	searchQueueService *search_queue.SearchQueueService
 }
 
 func NewQuestionCommon(questionRepo QuestionRepo,
@@ -72,23 +74,29 @@ func NewQuestionCommon(questionRepo QuestionRepo,
 	answerCommon *answercommon.AnswerCommon,
 	metaService *meta.MetaService,
 	configRepo config.ConfigRepo,
+	searchQueueService *search_queue.SearchQueueService,
 ) *QuestionCommon {
 	return &QuestionCommon{
-		questionRepo:     questionRepo,
-		answerRepo:       answerRepo,
-		voteRepo:         voteRepo,
-		followCommon:     followCommon,
-		tagCommon:        tagCommon,
-		userCommon:       userCommon,
-		collectionCommon: collectionCommon,
-		AnswerCommon:     answerCommon,
-		metaService:      metaService,
-		configRepo:       configRepo,
+		questionRepo:       questionRepo,
+		answerRepo:         answerRepo,
+		voteRepo:           voteRepo,
+		followCommon:       followCommon,
+		tagCommon:          tagCommon,
+		userCommon:         userCommon,
+		collectionCommon:   collectionCommon,
+		AnswerCommon:       answerCommon,
+		metaService:        metaService,
+		configRepo:         configRepo,
+		searchQueueService: searchQueueService,
 	}
 }
 
 func (qs *QuestionCommon) UpdataPv(ctx context.Context, questionID string) error {
-	return qs.questionRepo.UpdatePvCount(ctx, questionID)
+	if err := qs.questionRepo.UpdatePvCount(ctx, questionID); err != nil {
+		return err
+	}
+	qs.searchQueueService.AddSearchSync(questionID)
+	return nil
 }
 
 func (qs *QuestionCommon) UpdateAnswerCount(ctx context.Context, questionID string, num int) error {
@@ -143,7 +151,9 @@ func (qs *QuestionCommon) FindInfoByID(ctx context.Context, questionIDs []string
 	return list, nil
 }
 
-func (qs *QuestionCommon) Info(ctx context.Context, questionID string, loginUserID string) (showinfo *schema.QuestionInfo, err error) {
+// Info get the question info, the question waiting for review is only visible to its author and the reviewer
+func (qs *QuestionCommon) Info(ctx context.Context, questionID string, loginUserID string, canReview bool) (
+	showinfo *schema.QuestionInfo, err error) {
 	dbinfo, has, err := qs.questionRepo.GetQuestion(ctx, questionID)
 	if err != nil {
 		return showinfo, err
@@ -151,6 +161,9 @@ func (qs *QuestionCommon) Info(ctx context.Context, questionID string, loginUser
 	if !has {
 		return showinfo, errors.BadRequest(reason.QuestionNotFound)
 	}
+	if dbinfo.Status == entity.QuestionStatusPending && dbinfo.UserID != loginUserID && !canReview {
+		return showinfo, errors.BadRequest(reason.QuestionNotFound)
+	}
 	showinfo = qs.ShowFormat(ctx, dbinfo)
 
 	if showinfo.Status == 2 {
@@ -431,6 +444,7 @@ func (qs *QuestionCommon) RemoveQuestion(ctx context.Context, req *schema.Remove
 
 	// todo rank remove
 
+	qs.searchQueueService.AddSearchSync(questionInfo.ID)
 	return nil
 }
 
@@ -488,7 +502,12 @@ func (as *QuestionCommon) RemoveAnswer(ctx context.Context, id string) (err erro
 		log.Error("user UpdateAnswerCount error", err.Error())
 	}
 
-	return as.answerRepo.RemoveAnswer(ctx, id)
+	err = as.answerRepo.RemoveAnswer(ctx, id)
+	if err != nil {
+		return err
+	}
+	as.searchQueueService.AddSearchSync(id)
+	return nil
 }
 
 func (qs *QuestionCommon) ShowListFormat(ctx context.Context, data *entity.Question) *schema.QuestionInfo {
@@ -523,6 +542,14 @@ func (qs *QuestionCommon) ShowFormat(ctx context.Context, data *entity.Question)
 	info.Status = data.Status
 	info.UserID = data.UserID
 	info.LastEditUserID = data.LastEditUserID
+	info.Locked = data.IsLocked()
+	if info.Locked && data.LockExpiredAt.Unix() > 0 {
+		info.LockExpiredAt = data.LockExpiredAt.Unix()
+	}
+	info.Protected = data.IsProtected()
+	if info.Protected && data.ProtectExpiredAt.Unix() > 0 {
+		info.ProtectExpiredAt = data.ProtectExpiredAt.Unix()
+	}
 	if data.LastAnswerID != "0" {
 		answerInfo, exist, err := qs.answerRepo.GetAnswer(ctx, data.LastAnswerID)
 		if err == nil && exist {
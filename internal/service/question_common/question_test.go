@@ -0,0 +1,121 @@
+package questioncommon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/activity_common"
+	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	collectioncommon "github.com/answerdev/answer/internal/service/collection_common"
+	tagcommon "github.com/answerdev/answer/internal/service/tag_common"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQuestionRepo struct {
+	QuestionRepo
+	question *entity.Question
+}
+
+func (f *fakeQuestionRepo) GetQuestion(ctx context.Context, id string) (*entity.Question, bool, error) {
+	if f.question.ID != id {
+		return nil, false, nil
+	}
+	return f.question, true, nil
+}
+
+type fakeAnswerRepo struct {
+	answercommon.AnswerRepo
+}
+
+func (f *fakeAnswerRepo) GetByUserIDQuestionID(ctx context.Context, userID string, questionID string) (
+	*entity.Answer, bool, error) {
+	return nil, false, nil
+}
+
+type fakeVoteRepo struct {
+	activity_common.VoteRepo
+}
+
+func (f *fakeVoteRepo) GetVoteStatus(ctx context.Context, objectID, userID string) string {
+	return ""
+}
+
+type fakeFollowRepo struct {
+	activity_common.FollowRepo
+}
+
+func (f *fakeFollowRepo) IsFollowed(userID, objectID string) (bool, error) {
+	return false, nil
+}
+
+type fakeTagCommonRepo struct {
+	tagcommon.TagCommonRepo
+}
+
+func (f *fakeTagCommonRepo) GetTagListByIDs(ctx context.Context, ids []string) ([]*entity.Tag, error) {
+	return nil, nil
+}
+
+type fakeTagRelRepo struct {
+	tagcommon.TagRelRepo
+}
+
+func (f *fakeTagRelRepo) GetObjectTagRelList(ctx context.Context, objectID string) ([]*entity.TagRel, error) {
+	return nil, nil
+}
+
+type fakeUserRepo struct {
+	usercommon.UserRepo
+}
+
+func (f *fakeUserRepo) BatchGetByID(ctx context.Context, ids []string) ([]*entity.User, error) {
+	return nil, nil
+}
+
+type fakeCollectionRepo struct {
+	collectioncommon.CollectionRepo
+}
+
+func (f *fakeCollectionRepo) SearchObjectCollected(ctx context.Context, userID string, objectIDs []string) (
+	map[string]bool, error) {
+	return map[string]bool{}, nil
+}
+
+func TestQuestionCommon_InfoPending(t *testing.T) {
+	const questionID = "10010000000000001"
+	answerRepo := &fakeAnswerRepo{}
+	qs := NewQuestionCommon(
+		&fakeQuestionRepo{question: &entity.Question{ID: questionID, UserID: "1", LastAnswerID: "0",
+			Status: entity.QuestionStatusPending}},
+		answerRepo,
+		&fakeVoteRepo{},
+		&fakeFollowRepo{},
+		tagcommon.NewTagCommonService(&fakeTagCommonRepo{}, &fakeTagRelRepo{}, nil, nil, nil),
+		usercommon.NewUserCommon(&fakeUserRepo{}),
+		collectioncommon.NewCollectionCommon(&fakeCollectionRepo{}),
+		answercommon.NewAnswerCommon(answerRepo),
+		nil,
+		nil,
+		nil,
+	)
+
+	// the author
+	info, err := qs.Info(context.TODO(), questionID, "1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, questionID, info.ID)
+
+	// the other users and the guest
+	for _, userID := range []string{"2", ""} {
+		_, err = qs.Info(context.TODO(), questionID, userID, false)
+		assert.Equal(t, errors.BadRequest(reason.QuestionNotFound), err)
+	}
+
+	// the reviewer
+	info, err = qs.Info(context.TODO(), questionID, "2", true)
+	assert.NoError(t, err)
+	assert.Equal(t, questionID, info.ID)
+}
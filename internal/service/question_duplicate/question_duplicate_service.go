@@ -0,0 +1,273 @@
+package question_duplicate
+
+import (
+	"context"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/activity_common"
+	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/answerdev/answer/internal/service/bounty"
+	"github.com/answerdev/answer/internal/service/config"
+	"github.com/answerdev/answer/internal/service/follow"
+	"github.com/answerdev/answer/internal/service/post_lock"
+	questioncommon "github.com/answerdev/answer/internal/service/question_common"
+	"github.com/answerdev/answer/internal/service/search_queue"
+	tagcommon "github.com/answerdev/answer/internal/service/tag_common"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/pkg/htmltext"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// QuestionDuplicateRepo question duplicate repository
+type QuestionDuplicateRepo interface {
+	SaveQuestionDuplicate(ctx context.Context, duplicate *entity.QuestionDuplicate) (err error)
+	RemoveQuestionDuplicate(ctx context.Context, questionID string) (err error)
+	GetQuestionDuplicate(ctx context.Context, questionID string) (
+		duplicate *entity.QuestionDuplicate, exist bool, err error)
+	GetDuplicateQuestionIDs(ctx context.Context, duplicateOfID string) (questionIDs []string, err error)
+	// MergeQuestion move the answers and comments to the canonical question and delete the question
+	MergeQuestion(ctx context.Context, duplicate *entity.QuestionDuplicate) (err error)
+}
+
+// QuestionDuplicateService the duplicate relationship between the questions
+type QuestionDuplicateService struct {
+	questionDuplicateRepo QuestionDuplicateRepo
+	questionRepo          questioncommon.QuestionRepo
+	answerRepo            answercommon.AnswerRepo
+	followRepo            follow.FollowRepo
+	followCommonRepo      activity_common.FollowRepo
+	tagCommonService      *tagcommon.TagCommonService
+	userCommon            *usercommon.UserCommon
+	configRepo            config.ConfigRepo
+	auditLogService       *audit_log.AuditLogService
+	bountyService         *bounty.BountyService
+	postLockService       *post_lock.PostLockService
+	searchQueueService    *search_queue.SearchQueueService
+}
+
+// NewQuestionDuplicateService new question duplicate service
+func NewQuestionDuplicateService(
+	questionDuplicateRepo QuestionDuplicateRepo,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	followRepo follow.FollowRepo,
+	followCommonRepo activity_common.FollowRepo,
+	tagCommonService *tagcommon.TagCommonService,
+	userCommon *usercommon.UserCommon,
+	configRepo config.ConfigRepo,
+	auditLogService *audit_log.AuditLogService,
+	bountyService *bounty.BountyService,
+	postLockService *post_lock.PostLockService,
+	searchQueueService *search_queue.SearchQueueService,
+) *QuestionDuplicateService {
+	return &QuestionDuplicateService{
+		questionDuplicateRepo: questionDuplicateRepo,
+		questionRepo:          questionRepo,
+		answerRepo:            answerRepo,
+		followRepo:            followRepo,
+		followCommonRepo:      followCommonRepo,
+		tagCommonService:      tagCommonService,
+		userCommon:            userCommon,
+		configRepo:            configRepo,
+		auditLogService:       auditLogService,
+		bountyService:         bountyService,
+		postLockService:       postLockService,
+		searchQueueService:    searchQueueService,
+	}
+}
+
+// IsDuplicateCloseType whether the close type is the duplicate reason
+func (qs *QuestionDuplicateService) IsDuplicateCloseType(closeType int) bool {
+	duplicateType, err := qs.configRepo.GetConfigType("reason.a_duplicate")
+	return err == nil && closeType == duplicateType
+}
+
+// GetCanonicalQuestionID check the question can be linked to the target and return the canonical question,
+// if the target is a duplicate too, its canonical question is used to avoid the chain of duplicates
+func (qs *QuestionDuplicateService) GetCanonicalQuestionID(ctx context.Context, questionID, targetID string) (
+	canonicalID string, err error) {
+	duplicate, exist, err := qs.questionDuplicateRepo.GetQuestionDuplicate(ctx, targetID)
+	if err != nil {
+		return "", err
+	}
+	if exist {
+		targetID = duplicate.DuplicateOfID
+	}
+	if targetID == questionID {
+		return "", errors.BadRequest(reason.QuestionDuplicateTargetInvalid)
+	}
+	target, exist, err := qs.questionRepo.GetQuestion(ctx, targetID)
+	if err != nil {
+		return "", err
+	}
+	if !exist || !isPublished(target.Status) {
+		return "", errors.BadRequest(reason.QuestionDuplicateTargetInvalid)
+	}
+	return target.ID, nil
+}
+
+// LinkDuplicate link the question to the canonical question
+func (qs *QuestionDuplicateService) LinkDuplicate(ctx context.Context, questionID, duplicateOfID, userID string) (
+	err error) {
+	return qs.questionDuplicateRepo.SaveQuestionDuplicate(ctx, &entity.QuestionDuplicate{
+		QuestionID:    questionID,
+		DuplicateOfID: duplicateOfID,
+		UserID:        userID,
+	})
+}
+
+// UnlinkDuplicate remove the link when the question is reopened, the merged question keeps redirecting
+func (qs *QuestionDuplicateService) UnlinkDuplicate(ctx context.Context, questionID string) (err error) {
+	duplicate, exist, err := qs.questionDuplicateRepo.GetQuestionDuplicate(ctx, questionID)
+	if err != nil || !exist || duplicate.Merged {
+		return err
+	}
+	return qs.questionDuplicateRepo.RemoveQuestionDuplicate(ctx, questionID)
+}
+
+// IsMerged whether the question is merged into another one
+func (qs *QuestionDuplicateService) IsMerged(ctx context.Context, questionID string) (merged bool, err error) {
+	duplicate, exist, err := qs.questionDuplicateRepo.GetQuestionDuplicate(ctx, questionID)
+	if err != nil {
+		return false, err
+	}
+	return exist && duplicate.Merged, nil
+}
+
+// SetQuestionLinks set the canonical question and the duplicates of the question
+func (qs *QuestionDuplicateService) SetQuestionLinks(ctx context.Context, question *schema.QuestionInfo) (
+	err error) {
+	duplicate, exist, err := qs.questionDuplicateRepo.GetQuestionDuplicate(ctx, question.ID)
+	if err != nil {
+		return err
+	}
+	if exist {
+		links, err := qs.getQuestionLinks(ctx, []string{duplicate.DuplicateOfID})
+		if err != nil {
+			return err
+		}
+		if len(links) > 0 {
+			question.DuplicateOf = links[0]
+			question.Merged = duplicate.Merged
+		}
+	}
+
+	questionIDs, err := qs.questionDuplicateRepo.GetDuplicateQuestionIDs(ctx, question.ID)
+	if err != nil {
+		return err
+	}
+	question.Duplicates, err = qs.getQuestionLinks(ctx, questionIDs)
+	return err
+}
+
+func (qs *QuestionDuplicateService) getQuestionLinks(ctx context.Context, questionIDs []string) (
+	links []*schema.QuestionLinkInfo, err error) {
+	links = make([]*schema.QuestionLinkInfo, 0, len(questionIDs))
+	if len(questionIDs) == 0 {
+		return links, nil
+	}
+	questions, err := qs.questionRepo.FindByID(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+	questionMapping := make(map[string]*entity.Question, len(questions))
+	for _, question := range questions {
+		questionMapping[question.ID] = question
+	}
+	for _, questionID := range questionIDs {
+		question, ok := questionMapping[questionID]
+		if !ok || !isPublished(question.Status) {
+			continue
+		}
+		links = append(links, &schema.QuestionLinkInfo{
+			ID:          question.ID,
+			Title:       question.Title,
+			UrlTitle:    htmltext.UrlTitle(question.Title),
+			AnswerCount: question.AnswerCount,
+			Status:      question.Status,
+		})
+	}
+	return links, nil
+}
+
+// MergeQuestion merge the duplicate question into the canonical question. The answers, comments and followers
+// are moved, the duplicate question is deleted and redirects to the canonical one.
+func (qs *QuestionDuplicateService) MergeQuestion(ctx context.Context, req *schema.MergeQuestionReq) (err error) {
+	question, exist, err := qs.questionRepo.GetQuestion(ctx, req.QuestionID)
+	if err != nil {
+		return err
+	}
+	if !exist || !isPublished(question.Status) {
+		return errors.BadRequest(reason.QuestionCannotMerge)
+	}
+	canonicalID, err := qs.GetCanonicalQuestionID(ctx, question.ID, req.DuplicateOfID)
+	if err != nil {
+		return err
+	}
+	// the answers of the locked question can not be moved, and the locked question can not receive them
+	for _, questionID := range []string{question.ID, canonicalID} {
+		if err = qs.postLockService.CheckPostLocked(ctx, questionID, req.UserID); err != nil {
+			return err
+		}
+	}
+
+	answers, err := qs.answerRepo.GetAnswerList(ctx, &entity.Answer{QuestionID: question.ID})
+	if err != nil {
+		return err
+	}
+	followerIDs, err := qs.followCommonRepo.GetFollowUserIDs(ctx, question.ID)
+	if err != nil {
+		return err
+	}
+	err = qs.questionDuplicateRepo.MergeQuestion(ctx, &entity.QuestionDuplicate{
+		QuestionID:    question.ID,
+		DuplicateOfID: canonicalID,
+		UserID:        req.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, followerID := range followerIDs {
+		if err := qs.followRepo.Follow(ctx, canonicalID, followerID); err != nil {
+			log.Errorf("move follower %s to question %s failed: %v", followerID, canonicalID, err)
+		}
+	}
+	// the merged question is no longer counted in its tags
+	if err := qs.tagCommonService.CreateOrUpdateTagRelList(ctx, question.ID, []string{}); err != nil {
+		log.Error(err)
+	}
+	if err := qs.userCommon.UpdateQuestionCount(ctx, question.UserID, -1); err != nil {
+		log.Error(err)
+	}
+	// the bounty can not be awarded after the answers are moved to the canonical question
+	if err := qs.bountyService.RefundQuestionBounty(ctx, question.ID); err != nil {
+		log.Errorf("refund the bounty of question %s failed: %s", question.ID, err)
+	}
+
+	qs.searchQueueService.AddSearchSync(question.ID)
+	qs.searchQueueService.AddSearchSync(canonicalID)
+	for _, answer := range answers {
+		qs.searchQueueService.AddSearchSync(answer.ID)
+	}
+
+	qs.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     req.UserID,
+		Action:     entity.AuditActionQuestionMerge,
+		ObjectType: constant.QuestionObjectType,
+		ObjectID:   question.ID,
+		Before:     &schema.AuditLogStatusSnapshot{Status: entity.AdminQuestionSearchStatusIntToString[question.Status]},
+		After:      &schema.AuditLogMergeSnapshot{Status: "merged", DuplicateOfID: canonicalID},
+	})
+	return nil
+}
+
+// isPublished whether the question can be linked, the deleted and pending questions are not visible
+func isPublished(status int) bool {
+	return status == entity.QuestionStatusAvailable || status == entity.QuestionStatusClosed
+}
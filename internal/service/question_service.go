@@ -17,12 +17,19 @@ import (
 	"github.com/answerdev/answer/internal/schema"
 	"github.com/answerdev/answer/internal/service/activity"
 	"github.com/answerdev/answer/internal/service/activity_queue"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/answerdev/answer/internal/service/bounty"
 	collectioncommon "github.com/answerdev/answer/internal/service/collection_common"
 	"github.com/answerdev/answer/internal/service/meta"
 	"github.com/answerdev/answer/internal/service/notice_queue"
 	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/post_lock"
+	"github.com/answerdev/answer/internal/service/pre_moderation"
 	questioncommon "github.com/answerdev/answer/internal/service/question_common"
+	"github.com/answerdev/answer/internal/service/question_duplicate"
 	"github.com/answerdev/answer/internal/service/revision_common"
+	"github.com/answerdev/answer/internal/service/search_queue"
+	"github.com/answerdev/answer/internal/service/spam"
 	tagcommon "github.com/answerdev/answer/internal/service/tag_common"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
 	"github.com/answerdev/answer/pkg/htmltext"
@@ -37,15 +44,22 @@ import (
 
 // QuestionService user service
 type QuestionService struct {
-	questionRepo          questioncommon.QuestionRepo
-	tagCommon             *tagcommon.TagCommonService
-	questioncommon        *questioncommon.QuestionCommon
-	userCommon            *usercommon.UserCommon
-	revisionService       *revision_common.RevisionService
-	metaService           *meta.MetaService
-	collectionCommon      *collectioncommon.CollectionCommon
-	answerActivityService *activity.AnswerActivityService
-	data                  *data.Data
+	questionRepo             questioncommon.QuestionRepo
+	tagCommon                *tagcommon.TagCommonService
+	questioncommon           *questioncommon.QuestionCommon
+	userCommon               *usercommon.UserCommon
+	revisionService          *revision_common.RevisionService
+	metaService              *meta.MetaService
+	collectionCommon         *collectioncommon.CollectionCommon
+	answerActivityService    *activity.AnswerActivityService
+	data                     *data.Data
+	auditLogService          *audit_log.AuditLogService
+	preModerationService     *pre_moderation.PreModerationService
+	spamService              *spam.SpamService
+	questionDuplicateService *question_duplicate.QuestionDuplicateService
+	postLockService          *post_lock.PostLockService
+	bountyService            *bounty.BountyService
+	searchQueueService       *search_queue.SearchQueueService
 }
 
 func NewQuestionService(
@@ -58,18 +72,31 @@ func NewQuestionService(
 	collectionCommon *collectioncommon.CollectionCommon,
 	answerActivityService *activity.AnswerActivityService,
 	data *data.Data,
-
+	auditLogService *audit_log.AuditLogService,
+	preModerationService *pre_moderation.PreModerationService,
+	spamService *spam.SpamService,
+	questionDuplicateService *question_duplicate.QuestionDuplicateService,
+	postLockService *post_lock.PostLockService,
+	bountyService *bounty.BountyService,
+	searchQueueService *search_queue.SearchQueueService,
 ) *QuestionService {
 	return &QuestionService{
-		questionRepo:          questionRepo,
-		tagCommon:             tagCommon,
-		questioncommon:        questioncommon,
-		userCommon:            userCommon,
-		revisionService:       revisionService,
-		metaService:           metaService,
-		collectionCommon:      collectionCommon,
-		answerActivityService: answerActivityService,
-		data:                  data,
+		questionRepo:             questionRepo,
+		tagCommon:                tagCommon,
+		questioncommon:           questioncommon,
+		userCommon:               userCommon,
+		revisionService:          revisionService,
+		metaService:              metaService,
+		collectionCommon:         collectionCommon,
+		answerActivityService:    answerActivityService,
+		data:                     data,
+		auditLogService:          auditLogService,
+		preModerationService:     preModerationService,
+		spamService:              spamService,
+		questionDuplicateService: questionDuplicateService,
+		postLockService:          postLockService,
+		bountyService:            bountyService,
+		searchQueueService:       searchQueueService,
 	}
 }
 
@@ -81,6 +108,14 @@ func (qs *QuestionService) CloseQuestion(ctx context.Context, req *schema.CloseQ
 	if !has {
 		return nil
 	}
+	// the question closed as a duplicate is linked to the original question
+	var duplicateOfID string
+	if len(req.DuplicateOfID) > 0 && qs.questionDuplicateService.IsDuplicateCloseType(req.CloseType) {
+		duplicateOfID, err = qs.questionDuplicateService.GetCanonicalQuestionID(ctx, questionInfo.ID, req.DuplicateOfID)
+		if err != nil {
+			return err
+		}
+	}
 
 	questionInfo.Status = entity.QuestionStatusClosed
 	err = qs.questionRepo.UpdateQuestionStatus(ctx, questionInfo)
@@ -96,6 +131,12 @@ func (qs *QuestionService) CloseQuestion(ctx context.Context, req *schema.CloseQ
 	if err != nil {
 		return err
 	}
+	if len(duplicateOfID) > 0 {
+		err = qs.questionDuplicateService.LinkDuplicate(ctx, questionInfo.ID, duplicateOfID, req.UserID)
+		if err != nil {
+			return err
+		}
+	}
 
 	activity_queue.AddActivity(&schema.ActivityMsg{
 		UserID:           req.UserID,
@@ -115,12 +156,24 @@ func (qs *QuestionService) ReopenQuestion(ctx context.Context, req *schema.Reope
 	if !has {
 		return nil
 	}
+	// the merged question has no answers and only redirects to the canonical question
+	merged, err := qs.questionDuplicateService.IsMerged(ctx, questionInfo.ID)
+	if err != nil {
+		return err
+	}
+	if merged {
+		return errors.BadRequest(reason.QuestionCannotUpdate)
+	}
 
 	questionInfo.Status = entity.QuestionStatusAvailable
 	err = qs.questionRepo.UpdateQuestionStatus(ctx, questionInfo)
 	if err != nil {
 		return err
 	}
+	err = qs.questionDuplicateService.UnlinkDuplicate(ctx, questionInfo.ID)
+	if err != nil {
+		return err
+	}
 	activity_queue.AddActivity(&schema.ActivityMsg{
 		UserID:           req.UserID,
 		ObjectID:         questionInfo.ID,
@@ -268,6 +321,26 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 	question.Status = entity.QuestionStatusAvailable
 	question.RevisionID = "0"
 	question.CreatedAt = now
+	verdict, err := qs.spamService.Check(ctx, &spam.Content{
+		UserID:     req.UserID,
+		ObjectType: constant.QuestionObjectType,
+		Title:      req.Title,
+		HTML:       req.HTML,
+	})
+	if err != nil {
+		return
+	}
+	if verdict.Rejected() {
+		return nil, errors.BadRequest(reason.SpamContentRejected)
+	}
+	needReview, err := qs.preModerationService.NeedReview(ctx, req.UserID)
+	if err != nil {
+		return
+	}
+	needReview = needReview || verdict.NeedReview()
+	if needReview {
+		question.Status = entity.QuestionStatusPending
+	}
 	//question.UpdatedAt = nil
 	err = qs.questionRepo.AddQuestion(ctx, question)
 	if err != nil {
@@ -277,7 +350,12 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 	objectTagData.ObjectID = question.ID
 	objectTagData.Tags = req.Tags
 	objectTagData.UserID = req.UserID
-	err = qs.ChangeTag(ctx, &objectTagData)
+	// the tags of the question held for review are not counted until it is published
+	if needReview {
+		err = qs.tagCommon.ObjectChangePendingTag(ctx, &objectTagData)
+	} else {
+		err = qs.ChangeTag(ctx, &objectTagData)
+	}
 	if err != nil {
 		return
 	}
@@ -299,22 +377,72 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 		return
 	}
 
+	if needReview {
+		err = qs.preModerationService.AddPendingPost(ctx, question.UserID, question.ID,
+			&schema.PendingPostExtra{SpamReason: verdict.String()})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		qs.publishQuestion(ctx, question.ID, question.UserID, revisionID)
+	}
+
+	questionInfo, err = qs.GetQuestion(ctx, question.ID, question.UserID, req.QuestionPermission)
+	return
+}
+
+// publishQuestion update the question count of the user and add the activity of the new question
+func (qs *QuestionService) publishQuestion(ctx context.Context, questionID, userID, revisionID string) {
 	// user add question count
-	err = qs.userCommon.UpdateQuestionCount(ctx, question.UserID, 1)
+	err := qs.userCommon.UpdateQuestionCount(ctx, userID, 1)
 	if err != nil {
 		log.Error("user IncreaseQuestionCount error", err.Error())
 	}
 
 	activity_queue.AddActivity(&schema.ActivityMsg{
-		UserID:           question.UserID,
-		ObjectID:         question.ID,
-		OriginalObjectID: question.ID,
+		UserID:           userID,
+		ObjectID:         questionID,
+		OriginalObjectID: questionID,
 		ActivityTypeKey:  constant.ActQuestionAsked,
 		RevisionID:       revisionID,
 	})
+	qs.searchQueueService.AddSearchSync(questionID)
+}
 
-	questionInfo, err = qs.GetQuestion(ctx, question.ID, question.UserID, req.QuestionPermission)
-	return
+// PublishPendingQuestion publish the question approved by the reviewer
+func (qs *QuestionService) PublishPendingQuestion(ctx context.Context, questionID string) (err error) {
+	questionInfo, exist, err := qs.questionRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.QuestionNotFound)
+	}
+	if err = qs.tagCommon.PublishObjectPendingTag(ctx, questionInfo.ID); err != nil {
+		return err
+	}
+	questionInfo.Status = entity.QuestionStatusAvailable
+	if err = qs.questionRepo.UpdateQuestionStatus(ctx, questionInfo); err != nil {
+		return err
+	}
+	qs.publishQuestion(ctx, questionInfo.ID, questionInfo.UserID, questionInfo.RevisionID)
+	return nil
+}
+
+// RejectPendingQuestion discard the question rejected by the reviewer
+func (qs *QuestionService) RejectPendingQuestion(ctx context.Context, questionID string) (err error) {
+	questionInfo, exist, err := qs.questionRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.QuestionNotFound)
+	}
+	questionInfo.Status = entity.QuestionStatusDeleted
+	if err = qs.questionRepo.UpdateQuestionStatus(ctx, questionInfo); err != nil {
+		return err
+	}
+	return qs.tagCommon.CreateOrUpdateTagRelList(ctx, questionInfo.ID, []string{})
 }
 
 // RemoveQuestion delete question
@@ -353,11 +481,19 @@ func (qs *QuestionService) RemoveQuestion(ctx context.Context, req *schema.Remov
 		}
 	}
 
+	pending := questionInfo.Status == entity.QuestionStatusPending
 	questionInfo.Status = entity.QuestionStatusDeleted
 	err = qs.questionRepo.UpdateQuestionStatus(ctx, questionInfo)
 	if err != nil {
 		return err
 	}
+	// the question waiting for review has not been published
+	if pending {
+		return nil
+	}
+	if err = qs.bountyService.RefundQuestionBounty(ctx, questionInfo.ID); err != nil {
+		log.Errorf("refund the bounty of question %s failed: %s", questionInfo.ID, err)
+	}
 
 	// user add question count
 	err = qs.userCommon.UpdateQuestionCount(ctx, questionInfo.UserID, -1)
@@ -375,6 +511,7 @@ func (qs *QuestionService) RemoveQuestion(ctx context.Context, req *schema.Remov
 		OriginalObjectID: questionInfo.ID,
 		ActivityTypeKey:  constant.ActQuestionDeleted,
 	})
+	qs.searchQueueService.AddSearchSync(questionInfo.ID)
 	return nil
 }
 
@@ -449,6 +586,9 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 	var canUpdate bool
 	questionInfo = &schema.QuestionInfo{}
 
+	if err = qs.postLockService.CheckPostLocked(ctx, req.ID, req.UserID); err != nil {
+		return
+	}
 	_, existUnreviewed, err := qs.revisionService.ExistUnreviewedByObjectID(ctx, req.ID)
 	if err != nil {
 		return
@@ -498,6 +638,19 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 	if dbinfo.Title == req.Title && dbinfo.OriginalText == req.Content && !isChange {
 		return
 	}
+	verdict, err := qs.spamService.Check(ctx, &spam.Content{
+		UserID:     req.UserID,
+		ObjectType: constant.QuestionObjectType,
+		Title:      req.Title,
+		HTML:       req.HTML,
+	})
+	if err != nil {
+		return
+	}
+	if verdict.Rejected() {
+		err = errors.BadRequest(reason.SpamContentRejected)
+		return
+	}
 
 	Tags, tagerr := qs.tagCommon.GetTagListByNames(ctx, tagNameList)
 	if tagerr != nil {
@@ -554,7 +707,8 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 		Log:      req.EditSummary,
 	}
 
-	if req.NoNeedReview {
+	// the edit that looks like spam is kept as an unreviewed revision
+	if req.NoNeedReview && !verdict.NeedReview() {
 		canUpdate = true
 	}
 
@@ -573,7 +727,12 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 		objectTagData.ObjectID = question.ID
 		objectTagData.Tags = req.Tags
 		objectTagData.UserID = req.UserID
-		tagerr := qs.ChangeTag(ctx, &objectTagData)
+		var tagerr error
+		if dbinfo.Status == entity.QuestionStatusPending {
+			tagerr = qs.tagCommon.ObjectChangePendingTag(ctx, &objectTagData)
+		} else {
+			tagerr = qs.ChangeTag(ctx, &objectTagData)
+		}
 		if err != nil {
 			return questionInfo, tagerr
 		}
@@ -597,6 +756,7 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 			RevisionID:       revisionID,
 			OriginalObjectID: question.ID,
 		})
+		qs.searchQueueService.AddSearchSync(question.ID)
 	}
 
 	questionInfo, err = qs.GetQuestion(ctx, question.ID, question.UserID, req.QuestionPermission)
@@ -606,7 +766,7 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 // GetQuestion get question one
 func (qs *QuestionService) GetQuestion(ctx context.Context, questionID, userID string,
 	per schema.QuestionPermission) (resp *schema.QuestionInfo, err error) {
-	question, err := qs.questioncommon.Info(ctx, questionID, userID)
+	question, err := qs.questioncommon.Info(ctx, questionID, userID, per.CanReview)
 	if err != nil {
 		return
 	}
@@ -617,6 +777,9 @@ func (qs *QuestionService) GetQuestion(ctx context.Context, questionID, userID s
 		per.CanClose = false
 	}
 	question.Description = htmltext.FetchExcerpt(question.HTML, "...", 240)
+	if err = qs.questionDuplicateService.SetQuestionLinks(ctx, question); err != nil {
+		log.Error(err)
+	}
 	question.MemberActions = permission.GetQuestionPermission(ctx, userID, question.UserID,
 		per.CanEdit, per.CanDelete, per.CanClose, per.CanReopen)
 	return question, nil
@@ -857,7 +1020,7 @@ func (qs *QuestionService) SearchByTitleLike(ctx context.Context, title string,
 
 // SimilarQuestion
 func (qs *QuestionService) SimilarQuestion(ctx context.Context, questionID string, loginUserID string) ([]*schema.QuestionPageResp, int64, error) {
-	question, err := qs.questioncommon.Info(ctx, questionID, loginUserID)
+	question, err := qs.questioncommon.Info(ctx, questionID, loginUserID, false)
 	if err != nil {
 		return nil, 0, nil
 	}
@@ -916,12 +1079,12 @@ func (qs *QuestionService) GetQuestionPage(ctx context.Context, req *schema.Ques
 	return questions, total, nil
 }
 
-func (qs *QuestionService) AdminSetQuestionStatus(ctx context.Context, questionID string, setStatusStr string) error {
-	setStatus, ok := entity.AdminQuestionSearchStatus[setStatusStr]
+func (qs *QuestionService) AdminSetQuestionStatus(ctx context.Context, req *schema.AdminSetQuestionStatusRequest) error {
+	setStatus, ok := entity.AdminQuestionSearchStatus[req.StatusStr]
 	if !ok {
 		return fmt.Errorf("question status does not exist")
 	}
-	questionInfo, exist, err := qs.questionRepo.GetQuestion(ctx, questionID)
+	questionInfo, exist, err := qs.questionRepo.GetQuestion(ctx, req.QuestionID)
 	if err != nil {
 		return err
 	}
@@ -932,6 +1095,16 @@ func (qs *QuestionService) AdminSetQuestionStatus(ctx context.Context, questionI
 	if err != nil {
 		return err
 	}
+	qs.searchQueueService.AddSearchSync(questionInfo.ID)
+	qs.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     req.UserID,
+		Action:     entity.AuditActionQuestionStatus,
+		ObjectType: constant.QuestionObjectType,
+		ObjectID:   questionInfo.ID,
+		Before:     &schema.AuditLogStatusSnapshot{Status: entity.AdminQuestionSearchStatusIntToString[questionInfo.Status]},
+		After:      &schema.AuditLogStatusSnapshot{Status: req.StatusStr},
+		Reason:     req.Reason,
+	})
 
 	if setStatus == entity.QuestionStatusDeleted {
 		err = qs.answerActivityService.DeleteQuestion(ctx, questionInfo.ID, questionInfo.CreatedAt, questionInfo.VoteCount)
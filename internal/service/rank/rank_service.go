@@ -24,7 +24,10 @@ const (
 )
 
 type UserRankRepo interface {
-	TriggerUserRank(ctx context.Context, session *xorm.Session, userId string, rank int, activityType int) (isReachStandard bool, err error)
+	// TriggerUserRank change the rank of user, the applied rank may be less than the given one by the limits
+	TriggerUserRank(ctx context.Context, session *xorm.Session, userId string, rank int, activityType int) (appliedRank int, err error)
+	// DebitUserRank debit the rank only if the user keeps at least 1 rank after that
+	DebitUserRank(ctx context.Context, session *xorm.Session, userId string, amount int) (debited bool, err error)
 	UserRankPage(ctx context.Context, userId string, page, pageSize int) (rankPage []*entity.Activity, total int64, err error)
 }
 
@@ -86,6 +89,9 @@ func (rs *RankService) CheckOperationPermission(ctx context.Context, userID stri
 			objectInfo.ObjectCreatorUserID == userID {
 			return true, nil
 		}
+		if objectInfo != nil && rs.checkTagScopedPower(ctx, userID, action, objectInfo.QuestionID) {
+			return true, nil
+		}
 	}
 
 	can = rs.checkUserRank(ctx, userInfo.ID, userInfo.Rank, PermissionPrefix+action)
@@ -121,6 +127,68 @@ func (rs *RankService) CheckOperationPermissions(ctx context.Context, userID str
 	return can, nil
 }
 
+// CheckOperationPermissionByObject verify that the user has the permission on the object,
+// the powers scoped to the tags of the object are included but the object creator is not treated specially
+func (rs *RankService) CheckOperationPermissionByObject(ctx context.Context, userID, action, objectID string) (
+	can bool, err error) {
+	cans, err := rs.CheckOperationPermissionsByObject(ctx, userID, []string{action}, objectID)
+	if err != nil {
+		return false, err
+	}
+	return cans[0], nil
+}
+
+// CheckOperationPermissionsByObject verify that the user has permissions on the object,
+// the powers scoped to the tags of the object are included but the object creator is not treated specially
+func (rs *RankService) CheckOperationPermissionsByObject(ctx context.Context, userID string, actions []string,
+	objectID string) (can []bool, err error) {
+	can, err = rs.CheckOperationPermissions(ctx, userID, actions)
+	if err != nil || len(userID) == 0 || len(objectID) == 0 {
+		return can, err
+	}
+
+	var questionID string
+	for idx, action := range actions {
+		if can[idx] {
+			continue
+		}
+		if len(questionID) == 0 {
+			objectInfo, err := rs.objectInfoService.GetInfo(ctx, objectID)
+			if err != nil {
+				return can, err
+			}
+			if objectInfo == nil || len(objectInfo.QuestionID) == 0 {
+				return can, nil
+			}
+			questionID = objectInfo.QuestionID
+		}
+		can[idx] = rs.checkTagScopedPower(ctx, userID, action, questionID)
+	}
+	return can, nil
+}
+
+// CheckOperationPermissionsInAnyTag verify that the user has permissions globally or in any tag,
+// it tells whether the user may review some objects, the object itself must be checked by CheckOperationPermissionsByObject
+func (rs *RankService) CheckOperationPermissionsInAnyTag(ctx context.Context, userID string, actions []string) (
+	can []bool, err error) {
+	can, err = rs.CheckOperationPermissions(ctx, userID, actions)
+	if err != nil || len(userID) == 0 {
+		return can, err
+	}
+	userRole, err := rs.roleService.GetUserRole(ctx, userID)
+	if err != nil {
+		return can, err
+	}
+	powers, err := rs.rolePowerService.GetRoleAllPowerTypes(ctx, userRole)
+	if err != nil {
+		return can, err
+	}
+	for idx, action := range actions {
+		can[idx] = can[idx] || powers[action]
+	}
+	return can, nil
+}
+
 // CheckOperationObjectOwner check operation object owner
 func (rs *RankService) CheckOperationObjectOwner(ctx context.Context, userID, objectID string) bool {
 	objectInfo, err := rs.objectInfoService.GetInfo(ctx, objectID)
@@ -205,6 +273,24 @@ func (rs *RankService) getUserPowerMapping(ctx context.Context, userID string) (
 	return powerMapping
 }
 
+// checkTagScopedPower check whether the role of user has the power in any tag of the question
+func (rs *RankService) checkTagScopedPower(ctx context.Context, userID, action, questionID string) bool {
+	if len(questionID) == 0 {
+		return false
+	}
+	userRole, err := rs.roleService.GetUserRole(ctx, userID)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	has, err := rs.rolePowerService.CheckRolePowerInObjectTags(ctx, userRole, action, questionID)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	return has
+}
+
 // CheckRankPermission verify that the user meets the prestige criteria
 func (rs *RankService) checkUserRank(ctx context.Context, userID string, userRank int, action string) (
 	can bool) {
@@ -0,0 +1,154 @@
+package rank
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/config"
+	"github.com/answerdev/answer/internal/service/object_info"
+	"github.com/answerdev/answer/internal/service/permission"
+	questioncommon "github.com/answerdev/answer/internal/service/question_common"
+	"github.com/answerdev/answer/internal/service/role"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	moderatorRoleID = 4
+	goQuestionID    = "10010000000000001"
+	rustQuestionID  = "10010000000000002"
+)
+
+type fakeUserRepo struct {
+	usercommon.UserRepo
+}
+
+func (f *fakeUserRepo) GetByUserID(ctx context.Context, userID string) (*entity.User, bool, error) {
+	return &entity.User{ID: userID, Rank: 1}, true, nil
+}
+
+type fakeUserRoleRelRepo struct {
+	role.UserRoleRelRepo
+}
+
+func (f *fakeUserRoleRelRepo) GetUserRoleRel(ctx context.Context, userID string) (*entity.UserRoleRel, bool, error) {
+	return &entity.UserRoleRel{UserID: userID, RoleID: moderatorRoleID}, true, nil
+}
+
+type fakeRolePowerRelRepo struct {
+	role.RolePowerRelRepo
+	rels           []*entity.RolePowerRel
+	questionTagIDs map[string][]int64
+}
+
+func (f *fakeRolePowerRelRepo) GetRolePowerTypeList(ctx context.Context, roleID int) ([]string, error) {
+	powers := make([]string, 0)
+	for _, rel := range f.rels {
+		if rel.RoleID == roleID && rel.TagID == 0 {
+			powers = append(powers, rel.PowerType)
+		}
+	}
+	return powers, nil
+}
+
+func (f *fakeRolePowerRelRepo) GetRolePowerRelList(ctx context.Context, roleID int) ([]*entity.RolePowerRel, error) {
+	rels := make([]*entity.RolePowerRel, 0)
+	for _, rel := range f.rels {
+		if rel.RoleID == roleID {
+			rels = append(rels, rel)
+		}
+	}
+	return rels, nil
+}
+
+func (f *fakeRolePowerRelRepo) CheckRolePowerInObjectTags(ctx context.Context, roleID int, powerType, objectID string) (
+	bool, error) {
+	for _, rel := range f.rels {
+		if rel.RoleID != roleID || rel.PowerType != powerType || rel.TagID == 0 {
+			continue
+		}
+		for _, tagID := range f.questionTagIDs[objectID] {
+			if tagID == rel.TagID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+type fakeQuestionRepo struct {
+	questioncommon.QuestionRepo
+}
+
+func (f *fakeQuestionRepo) GetQuestion(ctx context.Context, id string) (*entity.Question, bool, error) {
+	return &entity.Question{ID: id, UserID: "2"}, true, nil
+}
+
+type fakeConfigRepo struct {
+	config.ConfigRepo
+}
+
+// GetInt no rank is enough for the audit
+func (f *fakeConfigRepo) GetInt(key string) (int, error) {
+	return -1, nil
+}
+
+func newTestRankService(rels []*entity.RolePowerRel) *RankService {
+	rolePowerRelRepo := &fakeRolePowerRelRepo{
+		rels: rels,
+		questionTagIDs: map[string][]int64{
+			goQuestionID:   {1},
+			rustQuestionID: {2},
+		},
+	}
+	userRoleRelService := role.NewUserRoleRelService(&fakeUserRoleRelRepo{}, nil)
+	return NewRankService(
+		usercommon.NewUserCommon(&fakeUserRepo{}),
+		nil,
+		object_info.NewObjService(nil, &fakeQuestionRepo{}, nil, nil, nil),
+		userRoleRelService,
+		role.NewRolePowerRelService(rolePowerRelRepo, nil, nil, nil, userRoleRelService, nil),
+		&fakeConfigRepo{},
+	)
+}
+
+func TestRankService_CheckOperationPermissionsByObject(t *testing.T) {
+	ctx := context.Background()
+	actions := []string{permission.QuestionAudit, permission.AnswerAudit}
+	rs := newTestRankService([]*entity.RolePowerRel{
+		{RoleID: moderatorRoleID, PowerType: permission.QuestionAudit, TagID: 1},
+	})
+
+	can, err := rs.CheckOperationPermissions(ctx, "1", actions)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, false}, can)
+
+	can, err = rs.CheckOperationPermissionsInAnyTag(ctx, "1", actions)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, can)
+
+	can, err = rs.CheckOperationPermissionsByObject(ctx, "1", actions, goQuestionID)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, can)
+
+	can, err = rs.CheckOperationPermissionsByObject(ctx, "1", actions, rustQuestionID)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, false}, can)
+}
+
+func TestRankService_CheckOperationPermissionsByObject_GlobalPower(t *testing.T) {
+	ctx := context.Background()
+	actions := []string{permission.QuestionAudit, permission.AnswerAudit}
+	rs := newTestRankService([]*entity.RolePowerRel{
+		{RoleID: moderatorRoleID, PowerType: permission.QuestionAudit},
+	})
+
+	can, err := rs.CheckOperationPermissionsByObject(ctx, "1", actions, rustQuestionID)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, can)
+
+	can, err = rs.CheckOperationPermissionsInAnyTag(ctx, "", actions)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, false}, can)
+}
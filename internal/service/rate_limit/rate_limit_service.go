@@ -0,0 +1,152 @@
+package rate_limit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/role"
+	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+)
+
+// RateLimitService rate limit service
+type RateLimitService struct {
+	rateLimiter           data.RateLimiter
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
+	userRoleRelService    *role.UserRoleRelService
+	userRepo              usercommon.UserRepo
+}
+
+// NewRateLimitService new rate limit service
+func NewRateLimitService(
+	data *data.Data,
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+	userRoleRelService *role.UserRoleRelService,
+	userRepo usercommon.UserRepo,
+) *RateLimitService {
+	return &RateLimitService{
+		rateLimiter:           data.RateLimiter,
+		siteInfoCommonService: siteInfoCommonService,
+		userRoleRelService:    userRoleRelService,
+		userRepo:              userRepo,
+	}
+}
+
+// RateLimitStatus the status of the most restrictive bucket of the request
+type RateLimitStatus struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+// Take take a token from the buckets of the ip and then the user for the route class,
+// it returns nil if the rate limit is disabled or no rule matches
+func (rs *RateLimitService) Take(ctx context.Context, class, userID, ip string) (status *RateLimitStatus, err error) {
+	conf, err := rs.siteInfoCommonService.GetSiteRateLimit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !conf.Enabled {
+		return nil, nil
+	}
+
+	if rule := findIPRule(conf.Rules, class); rule != nil {
+		status, err = rs.take(ctx, fmt.Sprintf("%s:ip:%s", class, ip), rule, status)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// the request rejected by the ip bucket does not use up the quota of the user
+	if len(userID) == 0 || (status != nil && !status.Allowed) {
+		return status, nil
+	}
+
+	userInfo, exist, err := rs.userRepo.GetByUserID(ctx, userID)
+	if err != nil || !exist {
+		return status, err
+	}
+	roleID, err := rs.userRoleRelService.GetUserRole(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rule := findUserRule(conf.Rules, class, roleID, userInfo.Rank); rule != nil {
+		status, err = rs.take(ctx, fmt.Sprintf("%s:user:%s", class, userID), rule, status)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// take take a token by the rule and return the more restrictive status of it and the previous one
+func (rs *RateLimitService) take(ctx context.Context, key string, rule *schema.RateLimitRule,
+	previous *RateLimitStatus) (status *RateLimitStatus, err error) {
+	if rule.Limit == 0 {
+		return previous, nil
+	}
+	burst := rule.Burst
+	if burst == 0 {
+		burst = rule.Limit
+	}
+	result, err := rs.rateLimiter.Take(ctx, "rate_limit:"+key, rule.Limit, time.Duration(rule.Period)*time.Second, burst)
+	if err != nil {
+		return nil, err
+	}
+	status = &RateLimitStatus{
+		Allowed:    result.Allowed,
+		Limit:      burst,
+		Remaining:  result.Remaining,
+		RetryAfter: result.RetryAfter,
+		ResetAfter: result.ResetAfter,
+	}
+	if previous == nil {
+		return status, nil
+	}
+	if previous.Allowed != status.Allowed {
+		if previous.Allowed {
+			return status, nil
+		}
+		return previous, nil
+	}
+	if !status.Allowed && previous.RetryAfter > status.RetryAfter {
+		return previous, nil
+	}
+	if status.Allowed && previous.Remaining < status.Remaining {
+		return previous, nil
+	}
+	return status, nil
+}
+
+func findIPRule(rules []*schema.RateLimitRule, class string) *schema.RateLimitRule {
+	for _, rule := range rules {
+		if rule.Class == class && rule.Scope == schema.RateLimitScopeIP {
+			return rule
+		}
+	}
+	return nil
+}
+
+// findUserRule find the rule of the role with the highest min rank not above the rank,
+// the rules of all roles are used if no rule of the role matches
+func findUserRule(rules []*schema.RateLimitRule, class string, roleID, rank int) (matched *schema.RateLimitRule) {
+	for _, ruleRoleID := range []int{roleID, 0} {
+		for _, rule := range rules {
+			if rule.Class != class || rule.Scope != schema.RateLimitScopeUser || rule.RoleID != ruleRoleID ||
+				rule.MinRank > rank {
+				continue
+			}
+			if matched == nil || rule.MinRank > matched.MinRank {
+				matched = rule
+			}
+		}
+		if matched != nil {
+			return matched
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package rate_limit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/role"
+	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSiteInfoRepo struct {
+	siteinfo_common.SiteInfoRepo
+	content string
+}
+
+func (f *fakeSiteInfoRepo) GetByType(ctx context.Context, siteType string) (*entity.SiteInfo, bool, error) {
+	return &entity.SiteInfo{Type: siteType, Content: f.content}, true, nil
+}
+
+type fakeUserRepo struct {
+	usercommon.UserRepo
+}
+
+func (f *fakeUserRepo) GetByUserID(ctx context.Context, userID string) (*entity.User, bool, error) {
+	return &entity.User{ID: userID, Rank: 1}, true, nil
+}
+
+type fakeUserRoleRelRepo struct {
+	role.UserRoleRelRepo
+}
+
+func (f *fakeUserRoleRelRepo) GetUserRoleRel(ctx context.Context, userID string) (*entity.UserRoleRel, bool, error) {
+	return nil, false, nil
+}
+
+func Test_findUserRule(t *testing.T) {
+	rules := []*schema.RateLimitRule{
+		{Class: schema.RateLimitClassPosting, Scope: schema.RateLimitScopeIP, Limit: 60, Period: 3600},
+		{Class: schema.RateLimitClassPosting, Scope: schema.RateLimitScopeUser, Limit: 10, Period: 3600},
+		{Class: schema.RateLimitClassPosting, Scope: schema.RateLimitScopeUser, MinRank: 1000, Limit: 60, Period: 3600},
+		{Class: schema.RateLimitClassPosting, Scope: schema.RateLimitScopeUser, RoleID: 3, Limit: 0, Period: 3600},
+		{Class: schema.RateLimitClassVoting, Scope: schema.RateLimitScopeUser, Limit: 30, Period: 3600},
+	}
+	assert.Equal(t, rules[0], findIPRule(rules, schema.RateLimitClassPosting))
+	assert.Nil(t, findIPRule(rules, schema.RateLimitClassVoting))
+
+	// the reputation band of all roles
+	assert.Equal(t, rules[1], findUserRule(rules, schema.RateLimitClassPosting, 1, 999))
+	assert.Equal(t, rules[2], findUserRule(rules, schema.RateLimitClassPosting, 1, 1000))
+	// the rule of the role is used first
+	assert.Equal(t, rules[3], findUserRule(rules, schema.RateLimitClassPosting, 3, 5000))
+	assert.Equal(t, rules[4], findUserRule(rules, schema.RateLimitClassVoting, 3, 1))
+	assert.Nil(t, findUserRule(rules, schema.RateLimitClassFlagging, 1, 1))
+}
+
+func TestRateLimitService_take(t *testing.T) {
+	rs := &RateLimitService{rateLimiter: data.NewMemoryRateLimiter()}
+	ipRule := &schema.RateLimitRule{Limit: 3, Period: 60}
+	userRule := &schema.RateLimitRule{Limit: 10, Period: 60, Burst: 1}
+
+	// the status with the fewer remaining tokens is returned
+	status, err := rs.take(context.TODO(), "posting:ip:127.0.0.1", ipRule, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &RateLimitStatus{Allowed: true, Limit: 3, Remaining: 2, ResetAfter: status.ResetAfter}, status)
+	status, err = rs.take(context.TODO(), "posting:user:1", userRule, status)
+	assert.NoError(t, err)
+	assert.True(t, status.Allowed)
+	assert.Equal(t, 1, status.Limit)
+	assert.Equal(t, 0, status.Remaining)
+
+	// the rejected status is returned
+	status, err = rs.take(context.TODO(), "posting:ip:127.0.0.1", ipRule, nil)
+	assert.NoError(t, err)
+	status, err = rs.take(context.TODO(), "posting:user:1", userRule, status)
+	assert.NoError(t, err)
+	assert.False(t, status.Allowed)
+	assert.Equal(t, 1, status.Limit)
+	assert.Greater(t, status.RetryAfter.Seconds(), 0.0)
+
+	// 0 means no limit
+	status, err = rs.take(context.TODO(), "posting:user:1", &schema.RateLimitRule{Limit: 0, Period: 60}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+}
+
+func TestRateLimitService_Take(t *testing.T) {
+	content, _ := json.Marshal(&schema.SiteRateLimitResp{Enabled: true, Rules: []*schema.RateLimitRule{
+		{Class: schema.RateLimitClassPosting, Scope: schema.RateLimitScopeIP, Limit: 1, Period: 60},
+		{Class: schema.RateLimitClassPosting, Scope: schema.RateLimitScopeUser, Limit: 5, Period: 60},
+	}})
+	rateLimiter := data.NewMemoryRateLimiter()
+	rs := &RateLimitService{
+		rateLimiter:           rateLimiter,
+		siteInfoCommonService: siteinfo_common.NewSiteInfoCommonService(&fakeSiteInfoRepo{content: string(content)}),
+		userRoleRelService:    role.NewUserRoleRelService(&fakeUserRoleRelRepo{}, nil),
+		userRepo:              &fakeUserRepo{},
+	}
+
+	status, err := rs.Take(context.TODO(), schema.RateLimitClassPosting, "1", "127.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, status.Allowed)
+	status, err = rs.Take(context.TODO(), schema.RateLimitClassPosting, "1", "127.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, status.Allowed)
+
+	// the request rejected by the ip bucket does not take the token of the user
+	result, err := rateLimiter.Take(context.TODO(), "rate_limit:posting:user:1", 5, time.Minute, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Remaining)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/answerdev/answer/internal/service/config"
 	"github.com/answerdev/answer/pkg/htmltext"
+	"github.com/answerdev/answer/pkg/obj"
 	"github.com/segmentfault/pacman/log"
 
 	"github.com/answerdev/answer/internal/base/pager"
@@ -13,10 +14,12 @@ import (
 	"github.com/answerdev/answer/internal/repo/common"
 	"github.com/answerdev/answer/internal/schema"
 	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	"github.com/answerdev/answer/internal/service/audit_log"
 	"github.com/answerdev/answer/internal/service/comment_common"
 	questioncommon "github.com/answerdev/answer/internal/service/question_common"
 	"github.com/answerdev/answer/internal/service/report_common"
 	"github.com/answerdev/answer/internal/service/report_handle_admin"
+	"github.com/answerdev/answer/internal/service/spam"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
 	"github.com/jinzhu/copier"
 	"github.com/segmentfault/pacman/errors"
@@ -32,6 +35,8 @@ type ReportAdminService struct {
 	commentCommonRepo comment_common.CommentCommonRepo
 	reportHandle      *report_handle_admin.ReportHandle
 	configRepo        config.ConfigRepo
+	auditLogService   *audit_log.AuditLogService
+	spamService       *spam.SpamService
 }
 
 // NewReportAdminService new report service
@@ -43,7 +48,9 @@ func NewReportAdminService(
 	questionRepo questioncommon.QuestionRepo,
 	commentCommonRepo comment_common.CommentCommonRepo,
 	reportHandle *report_handle_admin.ReportHandle,
-	configRepo config.ConfigRepo) *ReportAdminService {
+	configRepo config.ConfigRepo,
+	auditLogService *audit_log.AuditLogService,
+	spamService *spam.SpamService) *ReportAdminService {
 	return &ReportAdminService{
 		reportRepo:        reportRepo,
 		commonUser:        commonUser,
@@ -53,6 +60,8 @@ func NewReportAdminService(
 		commentCommonRepo: commentCommonRepo,
 		reportHandle:      reportHandle,
 		configRepo:        configRepo,
+		auditLogService:   auditLogService,
+		spamService:       spamService,
 	}
 }
 
@@ -131,12 +140,33 @@ func (rs *ReportAdminService) HandleReported(ctx context.Context, req schema.Rep
 		return
 	}
 
+	// the content confirmed as spam is used to train the classifier before it is deleted
+	reasonSpam, _ := rs.configRepo.GetConfigType("reason.spam")
+	reasonDelete, _ := rs.configRepo.GetConfigType("reason.needs_delete")
+	if reported.ReportType == reasonSpam && req.FlaggedType == reasonDelete {
+		rs.spamService.TrainObject(ctx, reported.ObjectID, true)
+	}
+
 	if err = rs.reportHandle.HandleObject(ctx, reported, req); err != nil {
 		return
 	}
 
 	err = rs.reportRepo.UpdateByID(ctx, reported.ID, handleData)
-	return
+	if err != nil {
+		return err
+	}
+	objectType, _ := obj.GetObjectTypeStrByObjectID(reported.ObjectID)
+	rs.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     req.UserID,
+		Action:     entity.AuditActionReportHandle,
+		ObjectType: objectType,
+		ObjectID:   reported.ObjectID,
+		Before:     &schema.AuditLogReviewSnapshot{ID: reported.ID, Status: "pending"},
+		After: &schema.AuditLogReviewSnapshot{ID: reported.ID, Status: "completed",
+			FlaggedType: req.FlaggedType},
+		Reason: req.FlaggedContent,
+	})
+	return nil
 }
 
 func (rs *ReportAdminService) parseObject(ctx context.Context, resp *[]*schema.GetReportListPageResp) {
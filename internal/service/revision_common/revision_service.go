@@ -5,6 +5,7 @@ import (
 
 	"github.com/answerdev/answer/internal/base/reason"
 	"github.com/answerdev/answer/internal/service/revision"
+	"github.com/answerdev/answer/internal/service/stream"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
 	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
@@ -48,6 +49,9 @@ func (rs *RevisionService) AddRevision(ctx context.Context, req *schema.AddRevis
 	if err != nil {
 		return "", err
 	}
+	if rev.Status == entity.RevisionUnreviewedStatus {
+		stream.Publish(ctx, stream.ReviewKey, stream.EventRedDot, nil)
+	}
 	return rev.ID, nil
 }
 
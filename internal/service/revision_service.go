@@ -12,10 +12,14 @@ import (
 	"github.com/answerdev/answer/internal/schema"
 	"github.com/answerdev/answer/internal/service/activity_queue"
 	answercommon "github.com/answerdev/answer/internal/service/answer_common"
+	"github.com/answerdev/answer/internal/service/audit_log"
 	"github.com/answerdev/answer/internal/service/notice_queue"
 	"github.com/answerdev/answer/internal/service/object_info"
+	"github.com/answerdev/answer/internal/service/post_lock"
 	questioncommon "github.com/answerdev/answer/internal/service/question_common"
 	"github.com/answerdev/answer/internal/service/revision"
+	"github.com/answerdev/answer/internal/service/search_queue"
+	"github.com/answerdev/answer/internal/service/stream"
 	"github.com/answerdev/answer/internal/service/tag_common"
 	tagcommon "github.com/answerdev/answer/internal/service/tag_common"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
@@ -28,15 +32,18 @@ import (
 
 // RevisionService user service
 type RevisionService struct {
-	revisionRepo      revision.RevisionRepo
-	userCommon        *usercommon.UserCommon
-	questionCommon    *questioncommon.QuestionCommon
-	answerService     *AnswerService
-	objectInfoService *object_info.ObjService
-	questionRepo      questioncommon.QuestionRepo
-	answerRepo        answercommon.AnswerRepo
-	tagRepo           tag_common.TagRepo
-	tagCommon         *tagcommon.TagCommonService
+	revisionRepo       revision.RevisionRepo
+	userCommon         *usercommon.UserCommon
+	questionCommon     *questioncommon.QuestionCommon
+	answerService      *AnswerService
+	objectInfoService  *object_info.ObjService
+	questionRepo       questioncommon.QuestionRepo
+	answerRepo         answercommon.AnswerRepo
+	tagRepo            tag_common.TagRepo
+	tagCommon          *tagcommon.TagCommonService
+	auditLogService    *audit_log.AuditLogService
+	postLockService    *post_lock.PostLockService
+	searchQueueService *search_queue.SearchQueueService
 }
 
 func NewRevisionService(
@@ -49,20 +56,35 @@ func NewRevisionService(
 	answerRepo answercommon.AnswerRepo,
 	tagRepo tag_common.TagRepo,
 	tagCommon *tagcommon.TagCommonService,
+	auditLogService *audit_log.AuditLogService,
+	postLockService *post_lock.PostLockService,
+	searchQueueService *search_queue.SearchQueueService,
 ) *RevisionService {
 	return &RevisionService{
-		revisionRepo:      revisionRepo,
-		userCommon:        userCommon,
-		questionCommon:    questionCommon,
-		answerService:     answerService,
-		objectInfoService: objectInfoService,
-		questionRepo:      questionRepo,
-		answerRepo:        answerRepo,
-		tagRepo:           tagRepo,
-		tagCommon:         tagCommon,
+		revisionRepo:       revisionRepo,
+		userCommon:         userCommon,
+		questionCommon:     questionCommon,
+		answerService:      answerService,
+		objectInfoService:  objectInfoService,
+		questionRepo:       questionRepo,
+		answerRepo:         answerRepo,
+		tagRepo:            tagRepo,
+		tagCommon:          tagCommon,
+		auditLogService:    auditLogService,
+		postLockService:    postLockService,
+		searchQueueService: searchQueueService,
 	}
 }
 
+// GetRevisionObjectID get the id of the object edited by the revision, it is empty if the revision is not found
+func (rs *RevisionService) GetRevisionObjectID(ctx context.Context, revisionID string) (objectID string, err error) {
+	revisionInfo, exist, err := rs.revisionRepo.GetRevisionByID(ctx, revisionID)
+	if err != nil || !exist {
+		return "", err
+	}
+	return revisionInfo.ObjectID, nil
+}
+
 func (rs *RevisionService) RevisionAudit(ctx context.Context, req *schema.RevisionAuditReq) (err error) {
 	revisioninfo, exist, err := rs.revisionRepo.GetRevisionByID(ctx, req.ID)
 	if err != nil {
@@ -74,6 +96,12 @@ func (rs *RevisionService) RevisionAudit(ctx context.Context, req *schema.Revisi
 	if revisioninfo.Status != entity.RevisionUnreviewedStatus {
 		return
 	}
+	defer func() {
+		if err == nil {
+			stream.Publish(ctx, stream.ReviewKey, stream.EventRedDot, nil)
+			rs.addRevisionAuditLog(ctx, req, revisioninfo)
+		}
+	}()
 	if req.Operation == schema.RevisionAuditReject {
 		err = rs.revisionRepo.UpdateStatus(ctx, req.ID, entity.RevisionReviewRejectStatus, req.UserID)
 		return
@@ -83,6 +111,12 @@ func (rs *RevisionService) RevisionAudit(ctx context.Context, req *schema.Revisi
 		if objectTypeerr != nil {
 			return objectTypeerr
 		}
+		// the edit can not be applied to the locked post
+		if objectType != constant.TagObjectType {
+			if err = rs.postLockService.CheckPostLocked(ctx, revisioninfo.ObjectID, req.UserID); err != nil {
+				return err
+			}
+		}
 		revisionitem := &schema.GetRevisionResp{}
 		_ = copier.Copy(revisionitem, revisioninfo)
 		rs.parseItem(ctx, revisionitem)
@@ -117,6 +151,29 @@ func (rs *RevisionService) RevisionAudit(ctx context.Context, req *schema.Revisi
 	return nil
 }
 
+// addRevisionAuditLog record the review of the revision
+func (rs *RevisionService) addRevisionAuditLog(ctx context.Context, req *schema.RevisionAuditReq,
+	revisionInfo *entity.Revision) {
+	var status string
+	switch req.Operation {
+	case schema.RevisionAuditApprove:
+		status = "approved"
+	case schema.RevisionAuditReject:
+		status = "rejected"
+	default:
+		return
+	}
+	objectType, _ := obj.GetObjectTypeStrByObjectID(revisionInfo.ObjectID)
+	rs.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     req.UserID,
+		Action:     entity.AuditActionRevisionAudit,
+		ObjectType: objectType,
+		ObjectID:   revisionInfo.ObjectID,
+		Before:     &schema.AuditLogReviewSnapshot{ID: revisionInfo.ID, Status: "unreviewed"},
+		After:      &schema.AuditLogReviewSnapshot{ID: revisionInfo.ID, Status: status},
+	})
+}
+
 func (rs *RevisionService) revisionAuditQuestion(ctx context.Context, revisionitem *schema.GetRevisionResp) (err error) {
 	questioninfo, ok := revisionitem.ContentParsed.(*schema.QuestionInfo)
 	if ok {
@@ -162,6 +219,7 @@ func (rs *RevisionService) revisionAuditQuestion(ctx context.Context, revisionit
 			RevisionID:       revisionitem.ID,
 			OriginalObjectID: revisionitem.ObjectID,
 		})
+		rs.searchQueueService.AddSearchSync(question.ID)
 	}
 	return nil
 }
@@ -219,6 +277,7 @@ func (rs *RevisionService) revisionAuditAnswer(ctx context.Context, revisionitem
 			ActivityTypeKey:  constant.ActAnswerEdited,
 			RevisionID:       revisionitem.ID,
 		})
+		rs.searchQueueService.AddSearchSync(insertData.ID)
 	}
 	return nil
 }
@@ -408,6 +467,9 @@ func (rs *RevisionService) parseItem(ctx context.Context, item *schema.GetRevisi
 // CheckCanUpdateRevision can check revision
 func (rs *RevisionService) CheckCanUpdateRevision(ctx context.Context, req *schema.CheckCanQuestionUpdate) (
 	resp *schema.ErrTypeData, err error) {
+	if err = rs.postLockService.CheckPostLocked(ctx, req.ID, req.UserID); err != nil {
+		return &schema.ErrTypeToast, err
+	}
 	_, exist, err := rs.revisionRepo.ExistUnreviewedByObjectID(ctx, req.ID)
 	if err != nil {
 		return nil, nil
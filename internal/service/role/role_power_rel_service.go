@@ -2,25 +2,50 @@ package role
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	tagcommon "github.com/answerdev/answer/internal/service/tag_common"
+	"github.com/answerdev/answer/pkg/converter"
+	"github.com/segmentfault/pacman/errors"
 )
 
 // RolePowerRelRepo rolePowerRel repository
 type RolePowerRelRepo interface {
 	GetRolePowerTypeList(ctx context.Context, roleID int) (powers []string, err error)
+	GetRolePowerRelList(ctx context.Context, roleID int) (rels []*entity.RolePowerRel, err error)
+	ReplaceRolePowerRelList(ctx context.Context, roleID int, rels []*entity.RolePowerRel) (err error)
+	CheckRolePowerInObjectTags(ctx context.Context, roleID int, powerType, objectID string) (has bool, err error)
 }
 
 // RolePowerRelService user service
 type RolePowerRelService struct {
 	rolePowerRelRepo   RolePowerRelRepo
+	powerRepo          PowerRepo
+	roleRepo           RoleRepo
+	tagCommonRepo      tagcommon.TagCommonRepo
 	userRoleRelService *UserRoleRelService
+	auditLogService    *audit_log.AuditLogService
 }
 
 // NewRolePowerRelService new role power rel service
 func NewRolePowerRelService(rolePowerRelRepo RolePowerRelRepo,
-	userRoleRelService *UserRoleRelService) *RolePowerRelService {
+	powerRepo PowerRepo,
+	roleRepo RoleRepo,
+	tagCommonRepo tagcommon.TagCommonRepo,
+	userRoleRelService *UserRoleRelService,
+	auditLogService *audit_log.AuditLogService) *RolePowerRelService {
 	return &RolePowerRelService{
 		rolePowerRelRepo:   rolePowerRelRepo,
+		powerRepo:          powerRepo,
+		roleRepo:           roleRepo,
+		tagCommonRepo:      tagCommonRepo,
 		userRoleRelService: userRoleRelService,
+		auditLogService:    auditLogService,
 	}
 }
 
@@ -29,6 +54,20 @@ func (rs *RolePowerRelService) GetRolePowerList(ctx context.Context, roleID int)
 	return rs.rolePowerRelRepo.GetRolePowerTypeList(ctx, roleID)
 }
 
+// GetRoleAllPowerTypes get the power types of role including the ones scoped to tags
+func (rs *RolePowerRelService) GetRoleAllPowerTypes(ctx context.Context, roleID int) (
+	powers map[string]bool, err error) {
+	rels, err := rs.rolePowerRelRepo.GetRolePowerRelList(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	powers = make(map[string]bool, len(rels))
+	for _, rel := range rels {
+		powers[rel.PowerType] = true
+	}
+	return powers, nil
+}
+
 // GetUserPowerList get  list all
 func (rs *RolePowerRelService) GetUserPowerList(ctx context.Context, userID string) (powers []string, err error) {
 	roleID, err := rs.userRoleRelService.GetUserRole(ctx, userID)
@@ -37,3 +76,166 @@ func (rs *RolePowerRelService) GetUserPowerList(ctx context.Context, userID stri
 	}
 	return rs.rolePowerRelRepo.GetRolePowerTypeList(ctx, roleID)
 }
+
+// CheckRolePowerInObjectTags check whether the role has the power on the object by the tags of it
+func (rs *RolePowerRelService) CheckRolePowerInObjectTags(ctx context.Context, roleID int, powerType, objectID string) (
+	has bool, err error) {
+	return rs.rolePowerRelRepo.CheckRolePowerInObjectTags(ctx, roleID, powerType, objectID)
+}
+
+// GetRolePowerMatrix get all powers and whether the role has them, everywhere or in some tags
+func (rs *RolePowerRelService) GetRolePowerMatrix(ctx context.Context, req *schema.GetRolePowerListReq) (
+	resp []*schema.GetRolePowerResp, err error) {
+	if err = rs.checkRoleExist(ctx, req.RoleID); err != nil {
+		return nil, err
+	}
+	powers, err := rs.powerRepo.GetPowerList(ctx, &entity.Power{})
+	if err != nil {
+		return nil, err
+	}
+	rels, err := rs.rolePowerRelRepo.GetRolePowerRelList(ctx, req.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs := make([]string, 0)
+	for _, rel := range rels {
+		if rel.TagID > 0 {
+			tagIDs = append(tagIDs, converter.IntToString(rel.TagID))
+		}
+	}
+	tagMapping := make(map[string]*entity.Tag, len(tagIDs))
+	if len(tagIDs) > 0 {
+		tags, err := rs.tagCommonRepo.GetTagListByIDs(ctx, tagIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			tagMapping[tag.ID] = tag
+		}
+	}
+
+	resp = make([]*schema.GetRolePowerResp, 0, len(powers))
+	powerMapping := make(map[string]*schema.GetRolePowerResp, len(powers))
+	for _, power := range powers {
+		item := &schema.GetRolePowerResp{
+			PowerType:   power.PowerType,
+			Name:        power.Name,
+			Description: power.Description,
+			Tags:        make([]*schema.RolePowerTag, 0),
+		}
+		powerMapping[power.PowerType] = item
+		resp = append(resp, item)
+	}
+	for _, rel := range rels {
+		item := powerMapping[rel.PowerType]
+		if item == nil {
+			continue
+		}
+		if rel.TagID == 0 {
+			item.Granted = true
+			continue
+		}
+		if tag := tagMapping[converter.IntToString(rel.TagID)]; tag != nil {
+			item.Tags = append(item.Tags, &schema.RolePowerTag{SlugName: tag.SlugName, DisplayName: tag.DisplayName})
+		}
+	}
+	return resp, nil
+}
+
+// UpdateRolePower replace all powers of role, the power without tags works everywhere.
+// The admin role always has all powers, so its powers can not be changed.
+func (rs *RolePowerRelService) UpdateRolePower(ctx context.Context, req *schema.UpdateRolePowerReq) (err error) {
+	if req.RoleID == RoleAdminID {
+		return errors.BadRequest(reason.RolePowerCannotEdit)
+	}
+	if err = rs.checkRoleExist(ctx, req.RoleID); err != nil {
+		return err
+	}
+	powers, err := rs.powerRepo.GetPowerList(ctx, &entity.Power{})
+	if err != nil {
+		return err
+	}
+	powerTypes := make(map[string]bool, len(powers))
+	for _, power := range powers {
+		powerTypes[power.PowerType] = true
+	}
+
+	rels := make([]*entity.RolePowerRel, 0, len(req.Powers))
+	added := make(map[string]bool)
+	for _, power := range req.Powers {
+		if !powerTypes[power.PowerType] {
+			return errors.BadRequest(reason.RolePowerInvalid)
+		}
+		if len(power.Tags) == 0 {
+			if !added[power.PowerType] {
+				rels = append(rels, &entity.RolePowerRel{RoleID: req.RoleID, PowerType: power.PowerType})
+				added[power.PowerType] = true
+			}
+			continue
+		}
+		tags, err := rs.tagCommonRepo.GetTagListByNames(ctx, power.Tags)
+		if err != nil {
+			return err
+		}
+		if len(tags) != len(uniqueNames(power.Tags)) {
+			return errors.BadRequest(reason.RolePowerInvalid)
+		}
+		for _, tag := range tags {
+			key := power.PowerType + "@" + tag.ID
+			if added[key] {
+				continue
+			}
+			rels = append(rels, &entity.RolePowerRel{
+				RoleID: req.RoleID, PowerType: power.PowerType, TagID: converter.StringToInt64(tag.ID)})
+			added[key] = true
+		}
+	}
+	oldRels, err := rs.rolePowerRelRepo.GetRolePowerRelList(ctx, req.RoleID)
+	if err != nil {
+		return err
+	}
+	if err = rs.rolePowerRelRepo.ReplaceRolePowerRelList(ctx, req.RoleID, rels); err != nil {
+		return err
+	}
+	rs.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionRolePower,
+		ObjectType: entity.AuditObjectTypeRole,
+		ObjectID:   strconv.Itoa(req.RoleID),
+		Before:     rolePowerSnapshot(oldRels),
+		After:      rolePowerSnapshot(rels),
+	})
+	return nil
+}
+
+// rolePowerSnapshot the powers of role in the audit log
+func rolePowerSnapshot(rels []*entity.RolePowerRel) *schema.AuditLogRolePowerSnapshot {
+	snapshot := &schema.AuditLogRolePowerSnapshot{Powers: make([]string, 0, len(rels))}
+	for _, rel := range rels {
+		if rel.TagID == 0 {
+			snapshot.Powers = append(snapshot.Powers, rel.PowerType)
+		} else {
+			snapshot.Powers = append(snapshot.Powers, fmt.Sprintf("%s@%d", rel.PowerType, rel.TagID))
+		}
+	}
+	return snapshot
+}
+
+func (rs *RolePowerRelService) checkRoleExist(ctx context.Context, roleID int) (err error) {
+	_, exist, err := rs.roleRepo.GetRole(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.RoleNotFound)
+	}
+	return nil
+}
+
+func uniqueNames(names []string) map[string]bool {
+	unique := make(map[string]bool, len(names))
+	for _, name := range names {
+		unique[name] = true
+	}
+	return unique
+}
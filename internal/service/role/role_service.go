@@ -2,18 +2,21 @@ package role
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/answerdev/answer/internal/base/handler"
+	"github.com/answerdev/answer/internal/base/reason"
 	"github.com/answerdev/answer/internal/base/translator"
 	"github.com/answerdev/answer/internal/entity"
 	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
 	"github.com/jinzhu/copier"
+	"github.com/segmentfault/pacman/errors"
 )
 
 const (
-	// Since there is currently no need to edit roles to add roles and other operations,
-	// the current role information is translated directly.
-	// Later on, when the relevant ability is available, it can be adjusted by the user himself.
+	// The built-in roles can not be deleted, the information of them is translated
+	// unless the admin has renamed them.
 
 	RoleUserID      = 1
 	RoleAdminID     = 2
@@ -36,16 +39,28 @@ const (
 type RoleRepo interface {
 	GetRoleAllList(ctx context.Context) (roles []*entity.Role, err error)
 	GetRoleAllMapping(ctx context.Context) (roleMapping map[int]*entity.Role, err error)
+	GetRole(ctx context.Context, roleID int) (role *entity.Role, exist bool, err error)
+	GetRoleByName(ctx context.Context, name string) (role *entity.Role, exist bool, err error)
+	AddRole(ctx context.Context, role *entity.Role) (err error)
+	UpdateRole(ctx context.Context, role *entity.Role) (err error)
+	RemoveRole(ctx context.Context, roleID int) (err error)
 }
 
 // RoleService user service
 type RoleService struct {
-	roleRepo RoleRepo
+	roleRepo         RoleRepo
+	userRoleRelRepo  UserRoleRelRepo
+	rolePowerRelRepo RolePowerRelRepo
+	auditLogService  *audit_log.AuditLogService
 }
 
-func NewRoleService(roleRepo RoleRepo) *RoleService {
+func NewRoleService(roleRepo RoleRepo, userRoleRelRepo UserRoleRelRepo, rolePowerRelRepo RolePowerRelRepo,
+	auditLogService *audit_log.AuditLogService) *RoleService {
 	return &RoleService{
-		roleRepo: roleRepo,
+		roleRepo:         roleRepo,
+		userRoleRelRepo:  userRoleRelRepo,
+		rolePowerRelRepo: rolePowerRelRepo,
+		auditLogService:  auditLogService,
 	}
 }
 
@@ -65,11 +80,121 @@ func (rs *RoleService) GetRoleList(ctx context.Context) (resp []*schema.GetRoleR
 	return
 }
 
+// AddRole add a custom role without any power
+func (rs *RoleService) AddRole(ctx context.Context, req *schema.AddRoleReq) (resp *schema.GetRoleResp, err error) {
+	if err = rs.checkNameDuplicate(ctx, 0, req.Name); err != nil {
+		return nil, err
+	}
+	role := &entity.Role{Name: req.Name, Description: req.Description}
+	if err = rs.roleRepo.AddRole(ctx, role); err != nil {
+		return nil, err
+	}
+	resp = &schema.GetRoleResp{ID: role.ID, Name: role.Name, Description: role.Description}
+	rs.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionRoleAdd,
+		ObjectType: entity.AuditObjectTypeRole,
+		ObjectID:   strconv.Itoa(role.ID),
+		After:      resp,
+	})
+	return resp, nil
+}
+
+// UpdateRole rename role and update its description
+func (rs *RoleService) UpdateRole(ctx context.Context, req *schema.UpdateRoleReq) (err error) {
+	oldRole, exist, err := rs.roleRepo.GetRole(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.RoleNotFound)
+	}
+	if err = rs.checkNameDuplicate(ctx, req.ID, req.Name); err != nil {
+		return err
+	}
+	if err = rs.roleRepo.UpdateRole(ctx, &entity.Role{ID: req.ID, Name: req.Name, Description: req.Description}); err != nil {
+		return err
+	}
+	rs.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionRoleUpdate,
+		ObjectType: entity.AuditObjectTypeRole,
+		ObjectID:   strconv.Itoa(req.ID),
+		Before:     &schema.GetRoleResp{ID: oldRole.ID, Name: oldRole.Name, Description: oldRole.Description},
+		After:      &schema.GetRoleResp{ID: req.ID, Name: req.Name, Description: req.Description},
+	})
+	return nil
+}
+
+// RemoveRole remove the custom role which is not assigned to any user
+func (rs *RoleService) RemoveRole(ctx context.Context, req *schema.RemoveRoleReq) (err error) {
+	if IsBuiltInRole(req.ID) {
+		return errors.BadRequest(reason.RoleCannotDelete)
+	}
+	oldRole, exist, err := rs.roleRepo.GetRole(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.RoleNotFound)
+	}
+	rels, err := rs.userRoleRelRepo.GetUserRoleRelListByRoleID(ctx, []int{req.ID})
+	if err != nil {
+		return err
+	}
+	if len(rels) > 0 {
+		return errors.BadRequest(reason.RoleInUse)
+	}
+	// the powers are removed with the role, they are recorded before
+	powerRels, err := rs.rolePowerRelRepo.GetRolePowerRelList(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if err = rs.roleRepo.RemoveRole(ctx, req.ID); err != nil {
+		return err
+	}
+	rs.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionRoleRemove,
+		ObjectType: entity.AuditObjectTypeRole,
+		ObjectID:   strconv.Itoa(req.ID),
+		Before: &schema.AuditLogRoleDetailSnapshot{
+			Name:        oldRole.Name,
+			Description: oldRole.Description,
+			Powers:      rolePowerSnapshot(powerRels).Powers,
+		},
+	})
+	return nil
+}
+
+// CheckRoleExist check the role exists
+func (rs *RoleService) CheckRoleExist(ctx context.Context, roleID int) (err error) {
+	_, exist, err := rs.roleRepo.GetRole(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.RoleNotFound)
+	}
+	return nil
+}
+
+func (rs *RoleService) checkNameDuplicate(ctx context.Context, roleID int, name string) (err error) {
+	role, exist, err := rs.roleRepo.GetRoleByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if exist && role.ID != roleID {
+		return errors.BadRequest(reason.RoleNameDuplicate)
+	}
+	return nil
+}
+
 func (rs *RoleService) GetRoleMapping(ctx context.Context) (roleMapping map[int]*entity.Role, err error) {
 	return rs.roleRepo.GetRoleAllMapping(ctx)
 }
 
 func (rs *RoleService) translateRole(ctx context.Context, role *entity.Role) {
+	if !IsBuiltInRole(role.ID) {
+		return
+	}
 	switch role.Name {
 	case roleUserName:
 		role.Name = translator.GlobalTrans.Tr(handler.GetLangByCtx(ctx), trRoleNameUser)
@@ -82,3 +207,8 @@ func (rs *RoleService) translateRole(ctx context.Context, role *entity.Role) {
 		role.Description = translator.GlobalTrans.Tr(handler.GetLangByCtx(ctx), trRoleDescriptionModerator)
 	}
 }
+
+// IsBuiltInRole whether the role is the user, admin or moderator role which can not be removed
+func IsBuiltInRole(roleID int) bool {
+	return roleID == RoleUserID || roleID == RoleAdminID || roleID == RoleModeratorID
+}
@@ -0,0 +1,156 @@
+package role
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	tagcommon "github.com/answerdev/answer/internal/service/tag_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRoleRepo struct {
+	RoleRepo
+	roles map[int]*entity.Role
+}
+
+func (f *fakeRoleRepo) GetRole(ctx context.Context, roleID int) (*entity.Role, bool, error) {
+	role, ok := f.roles[roleID]
+	return role, ok, nil
+}
+
+func (f *fakeRoleRepo) RemoveRole(ctx context.Context, roleID int) error {
+	delete(f.roles, roleID)
+	return nil
+}
+
+type fakeUserRoleRelRepo struct {
+	UserRoleRelRepo
+	rels []*entity.UserRoleRel
+}
+
+func (f *fakeUserRoleRelRepo) GetUserRoleRelListByRoleID(ctx context.Context, roleIDs []int) (
+	[]*entity.UserRoleRel, error) {
+	rels := make([]*entity.UserRoleRel, 0)
+	for _, rel := range f.rels {
+		if rel.RoleID == roleIDs[0] {
+			rels = append(rels, rel)
+		}
+	}
+	return rels, nil
+}
+
+type fakePowerRepo struct{}
+
+func (fakePowerRepo) GetPowerList(ctx context.Context, power *entity.Power) ([]*entity.Power, error) {
+	return []*entity.Power{{PowerType: "question.close"}, {PowerType: "tag.synonym"}}, nil
+}
+
+type fakeRolePowerRelRepo struct {
+	RolePowerRelRepo
+	rels []*entity.RolePowerRel
+}
+
+func (f *fakeRolePowerRelRepo) GetRolePowerRelList(ctx context.Context, roleID int) ([]*entity.RolePowerRel, error) {
+	return f.rels, nil
+}
+
+func (f *fakeRolePowerRelRepo) ReplaceRolePowerRelList(ctx context.Context, roleID int,
+	rels []*entity.RolePowerRel) error {
+	f.rels = rels
+	return nil
+}
+
+type fakeAuditLogRepo struct {
+	audit_log.AuditLogRepo
+	logs []*entity.AuditLog
+}
+
+func (f *fakeAuditLogRepo) AddAuditLog(ctx context.Context, auditLog *entity.AuditLog) error {
+	f.logs = append(f.logs, auditLog)
+	return nil
+}
+
+type fakeTagCommonRepo struct {
+	tagcommon.TagCommonRepo
+	tags []*entity.Tag
+}
+
+func (f *fakeTagCommonRepo) GetTagListByNames(ctx context.Context, names []string) ([]*entity.Tag, error) {
+	tags := make([]*entity.Tag, 0)
+	for _, tag := range f.tags {
+		for _, name := range names {
+			if tag.SlugName == name {
+				tags = append(tags, tag)
+				break
+			}
+		}
+	}
+	return tags, nil
+}
+
+func TestRoleService_RemoveRole(t *testing.T) {
+	roleRepo := &fakeRoleRepo{roles: map[int]*entity.Role{
+		RoleModeratorID: {ID: RoleModeratorID}, 4: {ID: 4}, 5: {ID: 5},
+	}}
+	auditLogRepo := &fakeAuditLogRepo{}
+	rs := NewRoleService(roleRepo, &fakeUserRoleRelRepo{rels: []*entity.UserRoleRel{{UserID: "1", RoleID: 5}}},
+		&fakeRolePowerRelRepo{rels: []*entity.RolePowerRel{{RoleID: 4, PowerType: "tag.synonym"}}},
+		audit_log.NewAuditLogService(auditLogRepo, nil))
+
+	err := rs.RemoveRole(context.TODO(), &schema.RemoveRoleReq{ID: RoleModeratorID})
+	assert.Equal(t, errors.BadRequest(reason.RoleCannotDelete), err)
+	err = rs.RemoveRole(context.TODO(), &schema.RemoveRoleReq{ID: 5})
+	assert.Equal(t, errors.BadRequest(reason.RoleInUse), err)
+	err = rs.RemoveRole(context.TODO(), &schema.RemoveRoleReq{ID: 6})
+	assert.Equal(t, errors.BadRequest(reason.RoleNotFound), err)
+
+	err = rs.RemoveRole(context.TODO(), &schema.RemoveRoleReq{ID: 4})
+	assert.NoError(t, err)
+	assert.NotContains(t, roleRepo.roles, 4)
+	assert.Len(t, auditLogRepo.logs, 1)
+	assert.Equal(t, entity.AuditActionRoleRemove, auditLogRepo.logs[0].Action)
+	assert.JSONEq(t, `{"name":"","description":"","powers":["tag.synonym"]}`, auditLogRepo.logs[0].Before)
+}
+
+func TestRolePowerRelService_UpdateRolePower(t *testing.T) {
+	rolePowerRelRepo := &fakeRolePowerRelRepo{rels: []*entity.RolePowerRel{{RoleID: 4, PowerType: "tag.synonym"}}}
+	auditLogRepo := &fakeAuditLogRepo{}
+	rs := NewRolePowerRelService(rolePowerRelRepo, fakePowerRepo{},
+		&fakeRoleRepo{roles: map[int]*entity.Role{4: {ID: 4}}},
+		&fakeTagCommonRepo{tags: []*entity.Tag{{ID: "10030000000000001", SlugName: "kubernetes"}}}, nil,
+		audit_log.NewAuditLogService(auditLogRepo, nil))
+
+	err := rs.UpdateRolePower(context.TODO(), &schema.UpdateRolePowerReq{RoleID: 4, Powers: []*schema.RolePowerConfig{
+		{PowerType: "question.close", Tags: []string{"kubernetes", "kubernetes"}},
+		{PowerType: "tag.synonym"},
+		{PowerType: "tag.synonym"},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.RolePowerRel{
+		{RoleID: 4, PowerType: "question.close", TagID: 10030000000000001},
+		{RoleID: 4, PowerType: "tag.synonym"},
+	}, rolePowerRelRepo.rels)
+	assert.Len(t, auditLogRepo.logs, 1)
+	assert.Equal(t, entity.AuditActionRolePower, auditLogRepo.logs[0].Action)
+	assert.Equal(t, "4", auditLogRepo.logs[0].ObjectID)
+	assert.Equal(t, `{"powers":["tag.synonym"]}`, auditLogRepo.logs[0].Before)
+	assert.Equal(t, `{"powers":["question.close@10030000000000001","tag.synonym"]}`, auditLogRepo.logs[0].After)
+
+	err = rs.UpdateRolePower(context.TODO(), &schema.UpdateRolePowerReq{RoleID: 4, Powers: []*schema.RolePowerConfig{
+		{PowerType: "question.close", Tags: []string{"docker"}},
+	}})
+	assert.Equal(t, errors.BadRequest(reason.RolePowerInvalid), err)
+	err = rs.UpdateRolePower(context.TODO(), &schema.UpdateRolePowerReq{RoleID: 4, Powers: []*schema.RolePowerConfig{
+		{PowerType: "site.destroy"},
+	}})
+	assert.Equal(t, errors.BadRequest(reason.RolePowerInvalid), err)
+	err = rs.UpdateRolePower(context.TODO(), &schema.UpdateRolePowerReq{RoleID: 5})
+	assert.Equal(t, errors.BadRequest(reason.RoleNotFound), err)
+	err = rs.UpdateRolePower(context.TODO(), &schema.UpdateRolePowerReq{RoleID: RoleAdminID})
+	assert.Equal(t, errors.BadRequest(reason.RolePowerCannotEdit), err)
+}
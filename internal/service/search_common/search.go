@@ -2,6 +2,7 @@ package search_common
 
 import (
 	"context"
+
 	"github.com/answerdev/answer/internal/schema"
 )
 
@@ -9,4 +10,6 @@ type SearchRepo interface {
 	SearchContents(ctx context.Context, words []string, tagIDs []string, userID string, votes, page, size int, order string) (resp []schema.SearchResp, total int64, err error)
 	SearchQuestions(ctx context.Context, words []string, tagIDs []string, notAccepted bool, views, answers int, page, size int, order string) (resp []schema.SearchResp, total int64, err error)
 	SearchAnswers(ctx context.Context, words []string, tagIDs []string, accepted bool, questionID string, page, size int, order string) (resp []schema.SearchResp, total int64, err error)
+	// SyncObject sync the question or answer to the search engine, the deleted one will be removed from it
+	SyncObject(ctx context.Context, objectID string) (err error)
 }
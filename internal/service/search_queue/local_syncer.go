@@ -0,0 +1,93 @@
+package search_queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// localSyncRetryBaseDelay the delay of first retry, it doubles after each failed round
+	localSyncRetryBaseDelay = 10 * time.Second
+	localSyncRetryMaxDelay  = 5 * time.Minute
+)
+
+// LocalSyncer sync the objects to the index of the current replica. The objects are kept in a set until
+// they are synced, so nothing is dropped however many are added, and the failed ones are retried later.
+type LocalSyncer struct {
+	syncObject func(ctx context.Context, objectID string) error
+	retryDelay time.Duration
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+	notify  chan struct{}
+}
+
+// NewLocalSyncer new local syncer and start the worker
+func NewLocalSyncer(syncObject func(ctx context.Context, objectID string) error) *LocalSyncer {
+	s := &LocalSyncer{
+		syncObject: syncObject,
+		retryDelay: localSyncRetryBaseDelay,
+		pending:    make(map[string]struct{}),
+		notify:     make(chan struct{}, 1),
+	}
+	go s.work()
+	return s
+}
+
+// Add add the object to be synced, it never blocks
+func (s *LocalSyncer) Add(objectID string) {
+	s.mu.Lock()
+	s.pending[objectID] = struct{}{}
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *LocalSyncer) work() {
+	retryDelay := s.retryDelay
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-s.notify:
+		case <-retry:
+		}
+		if failed := s.syncPending(); failed > 0 {
+			log.Warnf("sync %d objects to the search index failed, retry in %s", failed, retryDelay)
+			retry = time.After(retryDelay)
+			retryDelay *= 2
+			if retryDelay > localSyncRetryMaxDelay {
+				retryDelay = localSyncRetryMaxDelay
+			}
+			continue
+		}
+		retry = nil
+		retryDelay = s.retryDelay
+	}
+}
+
+// syncPending sync all the pending objects, the failed ones are kept, returns the number of them
+func (s *LocalSyncer) syncPending() (failed int) {
+	s.mu.Lock()
+	objectIDs := make([]string, 0, len(s.pending))
+	for objectID := range s.pending {
+		objectIDs = append(objectIDs, objectID)
+	}
+	s.pending = make(map[string]struct{})
+	s.mu.Unlock()
+
+	for _, objectID := range objectIDs {
+		if err := s.syncObject(context.Background(), objectID); err != nil {
+			log.Errorf("sync %s to the search index failed: %s", objectID, err)
+			s.mu.Lock()
+			s.pending[objectID] = struct{}{}
+			s.mu.Unlock()
+			failed++
+		}
+	}
+	return failed
+}
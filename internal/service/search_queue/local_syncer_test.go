@@ -0,0 +1,46 @@
+package search_queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalSyncer(t *testing.T) {
+	var mu sync.Mutex
+	synced := make(map[string]int)
+	failing := true
+	syncer := &LocalSyncer{
+		syncObject: func(ctx context.Context, objectID string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if objectID == "failed" && failing {
+				failing = false
+				return fmt.Errorf("index is busy")
+			}
+			synced[objectID]++
+			return nil
+		},
+		retryDelay: 10 * time.Millisecond,
+		pending:    make(map[string]struct{}),
+		notify:     make(chan struct{}, 1),
+	}
+
+	// nothing is dropped however many objects are added before the worker starts
+	for i := 0; i < 1000; i++ {
+		syncer.Add(fmt.Sprintf("%d", i))
+	}
+	syncer.Add("failed")
+	go syncer.work()
+
+	// the failed object is retried
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(synced) == 1001 && synced["failed"] == 1
+	}, 2*time.Second, 10*time.Millisecond)
+}
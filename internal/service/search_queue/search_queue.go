@@ -0,0 +1,40 @@
+package search_queue
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/service/job_queue"
+	"github.com/segmentfault/pacman/log"
+)
+
+// Topic the topic of search sync message
+const Topic = "search_sync"
+
+// SearchQueueService publish the objects which should be synced to the search engine
+type SearchQueueService struct {
+	queue job_queue.Queue
+	// disabled the search driver has no index to sync, such as the sql search which reads the tables directly,
+	// so that the queue is not filled with the messages which do nothing
+	disabled bool
+}
+
+// NewSearchQueueService new search queue service
+func NewSearchQueueService(queue job_queue.Queue, searchConf *data.SearchConf) *SearchQueueService {
+	return &SearchQueueService{
+		queue:    queue,
+		disabled: searchConf == nil || !searchConf.IsIndexDriver(),
+	}
+}
+
+// AddSearchSync add the question or answer id which should be synced to the search engine
+func (sq *SearchQueueService) AddSearchSync(objectID string) {
+	if sq.disabled {
+		return
+	}
+	payload, _ := json.Marshal(objectID)
+	if err := sq.queue.Publish(context.Background(), Topic, payload); err != nil {
+		log.Errorf("publish %s message failed: %s", Topic, err)
+	}
+}
@@ -0,0 +1,32 @@
+package search_queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/answerdev/answer/internal/service/job_queue"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueue struct {
+	job_queue.Queue
+	payloads []string
+}
+
+func (f *fakeQueue) Publish(ctx context.Context, topic string, payload []byte) error {
+	f.payloads = append(f.payloads, topic+":"+string(payload))
+	return nil
+}
+
+func TestSearchQueueService_AddSearchSync(t *testing.T) {
+	queue := &fakeQueue{}
+	NewSearchQueueService(queue, &data.SearchConf{Driver: data.SearchDriverIndex}).AddSearchSync("10010000000000001")
+	assert.Equal(t, []string{`search_sync:"10010000000000001"`}, queue.payloads)
+
+	// the sql search has no index to sync
+	queue = &fakeQueue{}
+	NewSearchQueueService(queue, &data.SearchConf{}).AddSearchSync("10010000000000001")
+	NewSearchQueueService(queue, nil).AddSearchSync("10010000000000001")
+	assert.Empty(t, queue.payloads)
+}
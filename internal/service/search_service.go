@@ -2,24 +2,99 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
 	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/job_queue"
 	"github.com/answerdev/answer/internal/service/search_common"
 	"github.com/answerdev/answer/internal/service/search_parser"
+	"github.com/answerdev/answer/internal/service/search_queue"
+	"github.com/segmentfault/pacman/log"
 )
 
 type SearchService struct {
 	searchParser *search_parser.SearchParser
 	searchRepo   search_common.SearchRepo
+	pubSub       data.PubSub
+	localSyncer  *search_queue.LocalSyncer
+	// instanceID the id of current replica, the broadcast search sync of itself is skipped
+	instanceID string
+}
+
+// searchSyncBroadcast the search sync broadcast to the other replicas
+type searchSyncBroadcast struct {
+	InstanceID string `json:"instance_id"`
+	ObjectID   string `json:"object_id"`
 }
 
 func NewSearchService(
 	searchParser *search_parser.SearchParser,
 	searchRepo search_common.SearchRepo,
+	queue job_queue.Queue,
+	searchConf *data.SearchConf,
+	data *data.Data,
 ) *SearchService {
-	return &SearchService{
+	ss := &SearchService{
 		searchParser: searchParser,
 		searchRepo:   searchRepo,
 	}
+	if searchConf == nil || !searchConf.IsIndexDriver() {
+		// the sql search has no index to sync, the messages left in the queue by the index driver are consumed
+		queue.Subscribe(search_queue.Topic, ss.HandleSearchSync)
+		return ss
+	}
+	// the index is stored in the local files of every replica. The job queue delivers the message durably
+	// to one of them, which syncs its own index and broadcasts the message to the others by the pub/sub.
+	hostname, _ := os.Hostname()
+	ss.instanceID = fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano())
+	ss.pubSub = data.PubSub
+	ss.localSyncer = search_queue.NewLocalSyncer(searchRepo.SyncObject)
+	queue.Subscribe(search_queue.Topic, ss.HandleSearchSync)
+	data.PubSub.Subscribe(context.Background(), search_queue.Topic, ss.handleSearchSyncBroadcast)
+	return ss
+}
+
+// HandleSearchSync sync the changed question or answer to the search engine, the message is delivered again
+// if the sync failed
+func (ss *SearchService) HandleSearchSync(ctx context.Context, payload []byte) error {
+	var objectID string
+	if err := json.Unmarshal(payload, &objectID); err != nil {
+		log.Errorf("decode search sync message failed: %s", err)
+		return nil
+	}
+	log.Debugf("received search sync %s", objectID)
+	if ss.pubSub != nil {
+		// the sync is idempotent, so the message delivered again is broadcast again
+		broadcast, _ := json.Marshal(&searchSyncBroadcast{InstanceID: ss.instanceID, ObjectID: objectID})
+		if err := ss.pubSub.Publish(ctx, search_queue.Topic, broadcast); err != nil {
+			return err
+		}
+	}
+	err := ss.searchRepo.SyncObject(ctx, objectID)
+	if err != nil && ss.localSyncer != nil && job_queue.IsLastAttempt(ctx) {
+		// the message will never be delivered again, it is kept retrying by the local syncer
+		log.Errorf("sync %s to the search index failed: %s", objectID, err)
+		ss.localSyncer.Add(objectID)
+		return nil
+	}
+	return err
+}
+
+// handleSearchSyncBroadcast sync the question or answer synced by another replica to the index of current replica
+func (ss *SearchService) handleSearchSyncBroadcast(payload []byte) {
+	msg := &searchSyncBroadcast{}
+	if err := json.Unmarshal(payload, msg); err != nil {
+		log.Errorf("decode search sync broadcast failed: %s", err)
+		return
+	}
+	if msg.InstanceID == ss.instanceID {
+		return
+	}
+	ss.localSyncer.Add(msg.ObjectID)
 }
 
 // Search search contents
@@ -0,0 +1,136 @@
+package serial_vote
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/config"
+	"github.com/answerdev/answer/internal/service/search_queue"
+	"github.com/segmentfault/pacman/log"
+)
+
+// Duration the period of the votes checked by every run
+const Duration = 24 * time.Hour
+
+// SerialVoteRepo serial vote repository
+type SerialVoteRepo interface {
+	// GetVotePairs get the number of upvotes every user received from every other user since the time
+	GetVotePairs(ctx context.Context, since time.Time) (pairs []*entity.ActivityUserVotePairStat, err error)
+	// ReverseVotes cancel the upvotes from the voter to the receiver since the time and roll back the reputation
+	ReverseVotes(ctx context.Context, voterID, receiverID string, since time.Time) (
+		objectIDs []string, reversedRank int, err error)
+	AddModerationLog(ctx context.Context, moderationLog *entity.ModerationLog) (err error)
+}
+
+// SerialVoteLogContent the content of moderation log for the reversed serial votes
+type SerialVoteLogContent struct {
+	// Ring the two users vote up each other
+	Ring      bool     `json:"ring"`
+	Votes     int      `json:"votes"`
+	Rank      int      `json:"rank"`
+	ObjectIDs []string `json:"object_ids"`
+	Since     int64    `json:"since"`
+}
+
+// SerialVoteService serial vote service
+type SerialVoteService struct {
+	serialVoteRepo     SerialVoteRepo
+	configRepo         config.ConfigRepo
+	searchQueueService *search_queue.SearchQueueService
+}
+
+// NewSerialVoteService new serial vote service
+func NewSerialVoteService(
+	serialVoteRepo SerialVoteRepo,
+	configRepo config.ConfigRepo,
+	searchQueueService *search_queue.SearchQueueService,
+) *SerialVoteService {
+	return &SerialVoteService{
+		serialVoteRepo:     serialVoteRepo,
+		configRepo:         configRepo,
+		searchQueueService: searchQueueService,
+	}
+}
+
+// ReverseSerialVotes reverse the upvotes in the last day from one user to another if there are at least
+// serial_vote.limit of them, the votes of both users are marked as a ring if each of them reaches the limit
+func (ss *SerialVoteService) ReverseSerialVotes(ctx context.Context) {
+	limit, err := ss.configRepo.GetInt("serial_vote.limit")
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if limit <= 0 {
+		return
+	}
+
+	since := time.Now().Add(-Duration)
+	pairs, err := ss.serialVoteRepo.GetVotePairs(ctx, since)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, pair := range findSerialVotes(pairs, limit) {
+		objectIDs, reversedRank, err := ss.serialVoteRepo.ReverseVotes(ctx, pair.voterID, pair.receiverID, since)
+		if err != nil {
+			log.Errorf("reverse votes from user %s to user %s failed: %s", pair.voterID, pair.receiverID, err)
+			continue
+		}
+		if len(objectIDs) == 0 {
+			continue
+		}
+		log.Infof("reversed %d votes from user %s to user %s", len(objectIDs), pair.voterID, pair.receiverID)
+		for _, objectID := range objectIDs {
+			ss.searchQueueService.AddSearchSync(objectID)
+		}
+
+		content, _ := json.Marshal(&SerialVoteLogContent{
+			Ring:      pair.ring,
+			Votes:     len(objectIDs),
+			Rank:      reversedRank,
+			ObjectIDs: objectIDs,
+			Since:     since.Unix(),
+		})
+		err = ss.serialVoteRepo.AddModerationLog(ctx, &entity.ModerationLog{
+			Action:       entity.ModerationActionSerialVoteReversed,
+			UserID:       pair.voterID,
+			TargetUserID: pair.receiverID,
+			Content:      string(content),
+		})
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+type serialVotePair struct {
+	voterID    string
+	receiverID string
+	ring       bool
+}
+
+// findSerialVotes find the pairs of users whose votes should be reversed,
+// it is a ring only if the votes in each direction reach the limit
+func findSerialVotes(pairs []*entity.ActivityUserVotePairStat, limit int) (serialPairs []*serialVotePair) {
+	counts := make(map[[2]string]int, len(pairs))
+	for _, pair := range pairs {
+		counts[[2]string{pair.TriggerUserID, pair.UserID}] = pair.VoteCount
+	}
+	serialPairs = make([]*serialVotePair, 0)
+	for _, pair := range pairs {
+		if pair.TriggerUserID == pair.UserID {
+			continue
+		}
+		if pair.VoteCount < limit {
+			continue
+		}
+		serialPairs = append(serialPairs, &serialVotePair{
+			voterID:    pair.TriggerUserID,
+			receiverID: pair.UserID,
+			ring:       counts[[2]string{pair.UserID, pair.TriggerUserID}] >= limit,
+		})
+	}
+	return serialPairs
+}
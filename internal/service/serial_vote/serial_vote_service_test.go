@@ -0,0 +1,83 @@
+package serial_vote
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/config"
+	"github.com/answerdev/answer/internal/service/job_queue"
+	"github.com/answerdev/answer/internal/service/search_queue"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConfigRepo struct {
+	config.ConfigRepo
+	limit int
+}
+
+func (f *fakeConfigRepo) GetInt(key string) (int, error) {
+	return f.limit, nil
+}
+
+type fakeSerialVoteRepo struct {
+	SerialVoteRepo
+	pairs    []*entity.ActivityUserVotePairStat
+	reversed [][2]string
+	logs     []*entity.ModerationLog
+}
+
+func (f *fakeSerialVoteRepo) GetVotePairs(ctx context.Context, since time.Time) (
+	[]*entity.ActivityUserVotePairStat, error) {
+	return f.pairs, nil
+}
+
+func (f *fakeSerialVoteRepo) ReverseVotes(ctx context.Context, voterID, receiverID string, since time.Time) (
+	[]string, int, error) {
+	f.reversed = append(f.reversed, [2]string{voterID, receiverID})
+	return []string{"10010000000000001", "10020000000000001"}, 20, nil
+}
+
+func (f *fakeSerialVoteRepo) AddModerationLog(ctx context.Context, moderationLog *entity.ModerationLog) error {
+	f.logs = append(f.logs, moderationLog)
+	return nil
+}
+
+func TestSerialVoteService_ReverseSerialVotes(t *testing.T) {
+	repo := &fakeSerialVoteRepo{pairs: []*entity.ActivityUserVotePairStat{
+		// serial votes from 1 to 2
+		{UserID: "2", TriggerUserID: "1", VoteCount: 5},
+		// normal votes from 1 to 3
+		{UserID: "3", TriggerUserID: "1", VoteCount: 4},
+		// 4 and 5 vote up each other
+		{UserID: "5", TriggerUserID: "4", VoteCount: 6},
+		{UserID: "4", TriggerUserID: "5", VoteCount: 5},
+		// 7 votes back to 6 only a few times, it is not a ring
+		{UserID: "7", TriggerUserID: "6", VoteCount: 5},
+		{UserID: "6", TriggerUserID: "7", VoteCount: 3},
+	}}
+	s := NewSerialVoteService(repo, &fakeConfigRepo{limit: 5},
+		search_queue.NewSearchQueueService(job_queue.NewChannelQueue(), nil))
+	s.ReverseSerialVotes(context.TODO())
+
+	assert.Equal(t, [][2]string{{"1", "2"}, {"4", "5"}, {"5", "4"}, {"6", "7"}}, repo.reversed)
+	assert.Len(t, repo.logs, 4)
+	assert.Equal(t, entity.ModerationActionSerialVoteReversed, repo.logs[0].Action)
+	assert.Equal(t, "1", repo.logs[0].UserID)
+	assert.Equal(t, "2", repo.logs[0].TargetUserID)
+	content := &SerialVoteLogContent{}
+	assert.NoError(t, json.Unmarshal([]byte(repo.logs[1].Content), content))
+	assert.True(t, content.Ring)
+	assert.Equal(t, 2, content.Votes)
+	assert.Equal(t, 20, content.Rank)
+	content = &SerialVoteLogContent{}
+	assert.NoError(t, json.Unmarshal([]byte(repo.logs[3].Content), content))
+	assert.False(t, content.Ring)
+
+	// 0 means no limit
+	repo = &fakeSerialVoteRepo{pairs: repo.pairs}
+	NewSerialVoteService(repo, &fakeConfigRepo{limit: 0}, nil).ReverseSerialVotes(context.TODO())
+	assert.Empty(t, repo.reversed)
+}
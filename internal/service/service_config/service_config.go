@@ -1,6 +1,9 @@
 package service_config
 
+import "github.com/answerdev/answer/internal/base/storage"
+
 type ServiceConfig struct {
-	SecretKey  string `json:"secret_key" mapstructure:"secret_key" yaml:"secret_key"`
-	UploadPath string `json:"upload_path" mapstructure:"upload_path" yaml:"upload_path"`
+	SecretKey  string          `json:"secret_key" mapstructure:"secret_key" yaml:"secret_key"`
+	UploadPath string          `json:"upload_path" mapstructure:"upload_path" yaml:"upload_path"`
+	Storage    *storage.Config `json:"storage" mapstructure:"storage" yaml:"storage,omitempty"`
 }
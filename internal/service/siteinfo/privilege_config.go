@@ -0,0 +1,201 @@
+package siteinfo
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// maxPrivilegeRank the max reputation required for the action
+	maxPrivilegeRank = 1000000000
+	// maxReputationDelta the max reputation earned or lost by one activity
+	maxReputationDelta = 10000
+)
+
+var (
+	// privilegeActions the actions gated by the reputation of user
+	privilegeActions = []string{
+		permission.QuestionAdd,
+		permission.QuestionEdit,
+		permission.QuestionEditWithoutReview,
+		permission.QuestionDelete,
+		permission.QuestionClose,
+		permission.QuestionReopen,
+		permission.QuestionVoteUp,
+		permission.QuestionVoteDown,
+		permission.QuestionAudit,
+		permission.AnswerAdd,
+		permission.AnswerAddProtected,
+		permission.AnswerEdit,
+		permission.AnswerEditWithoutReview,
+		permission.AnswerDelete,
+		permission.AnswerAccept,
+		permission.AnswerVoteUp,
+		permission.AnswerVoteDown,
+		permission.AnswerAudit,
+		permission.CommentAdd,
+		permission.CommentEdit,
+		permission.CommentDelete,
+		permission.CommentVoteUp,
+		permission.CommentVoteDown,
+		permission.ReportAdd,
+		permission.TagAdd,
+		permission.TagEdit,
+		permission.TagEditWithoutReview,
+		permission.TagDelete,
+		permission.TagSynonym,
+		permission.TagAudit,
+		permission.TagUseReservedTag,
+		permission.LinkUrlLimit,
+		permission.VoteDetail,
+	}
+	// reputationActivityTypes the activity types which change the reputation of user
+	reputationActivityTypes = []string{
+		"user.activated",
+		"question.voted_up",
+		"question.voted_up_cancel",
+		"question.voted_down",
+		"question.voted_down_cancel",
+		"question.vote_up",
+		"question.vote_up_cancel",
+		"question.vote_down",
+		"question.vote_down_cancel",
+		"answer.voted_up",
+		"answer.voted_up_cancel",
+		"answer.voted_down",
+		"answer.voted_down_cancel",
+		"answer.vote_up",
+		"answer.vote_up_cancel",
+		"answer.vote_down",
+		"answer.vote_down_cancel",
+		"answer.accepted",
+		"answer.accepted_cancel",
+		"answer.accept",
+		"answer.accept_cancel",
+		"answer.deleted",
+		"comment.vote_up",
+		"comment.vote_up_cancel",
+		"edit.accepted",
+		"edit.rejected",
+		"tag.edit_accepted",
+		"object.reported",
+	}
+	// reputationPenalties the activity types which take the reputation away, their values can not be positive,
+	// and the values of the other activity types can not be negative
+	reputationPenalties = map[string]bool{
+		"question.voted_up_cancel": true,
+		"question.voted_down":      true,
+		"question.vote_up_cancel":  true,
+		"question.vote_down":       true,
+		"answer.voted_up_cancel":   true,
+		"answer.voted_down":        true,
+		"answer.vote_up_cancel":    true,
+		"answer.vote_down":         true,
+		"answer.accepted_cancel":   true,
+		"answer.accept_cancel":     true,
+		"answer.deleted":           true,
+		"comment.vote_up_cancel":   true,
+		"edit.rejected":            true,
+		"object.reported":          true,
+	}
+	// limitKeys the limits of the reputation earned by user, 0 means no limit
+	limitKeys = []string{
+		"daily_rank_limit",
+		"serial_vote.limit",
+	}
+)
+
+// GetPrivilegeConfig get the reputation required by every action and earned by every activity
+func (s *SiteInfoService) GetPrivilegeConfig(ctx context.Context) (resp *schema.GetPrivilegeConfigResp, err error) {
+	resp = &schema.GetPrivilegeConfigResp{
+		Privileges:  s.getRankConfigList(privilegeActions, rank.PermissionPrefix),
+		Reputations: s.getRankConfigList(reputationActivityTypes, ""),
+		Limits:      s.getRankConfigList(limitKeys, ""),
+	}
+	return resp, nil
+}
+
+// UpdatePrivilegeConfig update the reputation required by actions, earned by activities and the limits of it,
+// every change is logged with the admin who made it
+func (s *SiteInfoService) UpdatePrivilegeConfig(ctx context.Context, req *schema.UpdatePrivilegeConfigReq) (err error) {
+	changes := make(map[string]int)
+	for _, item := range req.Privileges {
+		if !containsKey(privilegeActions, item.Key) || item.Value < -1 || item.Value > maxPrivilegeRank {
+			return errors.BadRequest(reason.RankConfigInvalid)
+		}
+		changes[rank.PermissionPrefix+item.Key] = item.Value
+	}
+	for _, item := range req.Reputations {
+		if !containsKey(reputationActivityTypes, item.Key) ||
+			item.Value < -maxReputationDelta || item.Value > maxReputationDelta {
+			return errors.BadRequest(reason.RankConfigInvalid)
+		}
+		if reputationPenalties[item.Key] && item.Value > 0 || !reputationPenalties[item.Key] && item.Value < 0 {
+			return errors.BadRequest(reason.RankConfigInvalid)
+		}
+		changes[item.Key] = item.Value
+	}
+	for _, item := range req.Limits {
+		if !containsKey(limitKeys, item.Key) || item.Value < 0 || item.Value > maxPrivilegeRank {
+			return errors.BadRequest(reason.RankConfigInvalid)
+		}
+		changes[item.Key] = item.Value
+	}
+
+	// all keys must exist before any of them is changed
+	oldValues := make(map[string]int, len(changes))
+	for key := range changes {
+		oldValues[key], err = s.configRepo.GetInt(key)
+		if err != nil {
+			return errors.BadRequest(reason.RankConfigInvalid)
+		}
+	}
+	for key, value := range changes {
+		if oldValues[key] == value {
+			continue
+		}
+		if err = s.configRepo.SetConfig(key, strconv.Itoa(value)); err != nil {
+			return err
+		}
+		log.Infof("admin %s changed config %s from %d to %d", req.UserID, key, oldValues[key], value)
+		s.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+			UserID:     req.UserID,
+			Action:     entity.AuditActionConfigUpdate,
+			ObjectType: entity.AuditObjectTypeConfig,
+			ObjectID:   key,
+			Before:     oldValues[key],
+			After:      value,
+		})
+	}
+	return nil
+}
+
+// getRankConfigList get the values of keys, the keys not in the config table are skipped
+func (s *SiteInfoService) getRankConfigList(keys []string, prefix string) (items []*schema.RankConfigItem) {
+	items = make([]*schema.RankConfigItem, 0, len(keys))
+	for _, key := range keys {
+		value, err := s.configRepo.GetInt(prefix + key)
+		if err != nil {
+			continue
+		}
+		items = append(items, &schema.RankConfigItem{Key: key, Value: value})
+	}
+	return items
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
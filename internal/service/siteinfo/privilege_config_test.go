@@ -0,0 +1,102 @@
+package siteinfo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/answerdev/answer/internal/service/config"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConfigRepo struct {
+	config.ConfigRepo
+	values map[string]string
+}
+
+func (f *fakeConfigRepo) GetInt(key string) (int, error) {
+	value, ok := f.values[key]
+	if !ok {
+		return 0, fmt.Errorf("no such config key: %s", key)
+	}
+	return strconv.Atoi(value)
+}
+
+func (f *fakeConfigRepo) SetConfig(key, value string) error {
+	f.values[key] = value
+	return nil
+}
+
+type fakeAuditLogRepo struct {
+	audit_log.AuditLogRepo
+	logs []*entity.AuditLog
+}
+
+func (f *fakeAuditLogRepo) AddAuditLog(ctx context.Context, auditLog *entity.AuditLog) error {
+	f.logs = append(f.logs, auditLog)
+	return nil
+}
+
+func TestSiteInfoService_PrivilegeConfig(t *testing.T) {
+	configRepo := &fakeConfigRepo{values: map[string]string{
+		"rank.question.close": "-1",
+		"rank.answer.add":     "1",
+		"answer.accepted":     "15",
+		"daily_rank_limit":    "200",
+	}}
+	auditLogRepo := &fakeAuditLogRepo{}
+	s := NewSiteInfoService(nil, nil, nil, nil, configRepo, audit_log.NewAuditLogService(auditLogRepo, nil))
+
+	resp, err := s.GetPrivilegeConfig(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []*schema.RankConfigItem{
+		{Key: "question.close", Value: -1},
+		{Key: "answer.add", Value: 1},
+	}, resp.Privileges)
+	assert.Equal(t, []*schema.RankConfigItem{{Key: "answer.accepted", Value: 15}}, resp.Reputations)
+	assert.Equal(t, []*schema.RankConfigItem{{Key: "daily_rank_limit", Value: 200}}, resp.Limits)
+
+	err = s.UpdatePrivilegeConfig(context.TODO(), &schema.UpdatePrivilegeConfigReq{
+		Privileges:  []*schema.RankConfigItem{{Key: "question.close", Value: 3000}},
+		Reputations: []*schema.RankConfigItem{{Key: "answer.accepted", Value: 25}},
+		Limits:      []*schema.RankConfigItem{{Key: "daily_rank_limit", Value: 0}},
+		UserID:      "1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "3000", configRepo.values["rank.question.close"])
+	assert.Equal(t, "25", configRepo.values["answer.accepted"])
+	assert.Equal(t, "0", configRepo.values["daily_rank_limit"])
+	// every change is recorded in the audit log
+	changes := make(map[string][2]string)
+	for _, item := range auditLogRepo.logs {
+		assert.Equal(t, "1", item.UserID)
+		assert.Equal(t, entity.AuditActionConfigUpdate, item.Action)
+		changes[item.ObjectID] = [2]string{item.Before, item.After}
+	}
+	assert.Equal(t, map[string][2]string{
+		"rank.question.close": {"-1", "3000"},
+		"answer.accepted":     {"15", "25"},
+		"daily_rank_limit":    {"200", "0"},
+	}, changes)
+
+	// the unknown key, the key not in the config table and the value out of range are rejected
+	for _, req := range []*schema.UpdatePrivilegeConfigReq{
+		{Privileges: []*schema.RankConfigItem{{Key: "admin.access", Value: 1}}},
+		{Privileges: []*schema.RankConfigItem{{Key: "tag.synonym", Value: 1}}},
+		{Privileges: []*schema.RankConfigItem{{Key: "answer.add", Value: -2}}},
+		{Reputations: []*schema.RankConfigItem{{Key: "answer.accepted", Value: 100000}}},
+		{Reputations: []*schema.RankConfigItem{{Key: "answer.accepted", Value: -15}}},
+		{Reputations: []*schema.RankConfigItem{{Key: "object.reported", Value: 100}}},
+		{Limits: []*schema.RankConfigItem{{Key: "daily_rank_limit", Value: -1}}},
+	} {
+		err = s.UpdatePrivilegeConfig(context.TODO(), req)
+		assert.Equal(t, errors.BadRequest(reason.RankConfigInvalid), err)
+	}
+	assert.Equal(t, "1", configRepo.values["rank.answer.add"])
+}
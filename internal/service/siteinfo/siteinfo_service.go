@@ -2,38 +2,56 @@ package siteinfo
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/answerdev/answer/internal/base/constant"
 	"github.com/answerdev/answer/internal/base/reason"
 	"github.com/answerdev/answer/internal/base/translator"
 	"github.com/answerdev/answer/internal/entity"
 	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/answerdev/answer/internal/service/config"
 	"github.com/answerdev/answer/internal/service/export"
 	"github.com/answerdev/answer/internal/service/siteinfo_common"
 	tagcommon "github.com/answerdev/answer/internal/service/tag_common"
+	"github.com/answerdev/answer/pkg/ldapauth"
+	"github.com/answerdev/answer/pkg/saml"
 	"github.com/jinzhu/copier"
 	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
 )
 
+// spCertificateValidFor the validity of the generated SP certificate, IdPs usually do not check it
+const spCertificateValidFor = 10 * 365 * 24 * time.Hour
+
 type SiteInfoService struct {
 	siteInfoRepo          siteinfo_common.SiteInfoRepo
 	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
 	emailService          *export.EmailService
 	tagCommonService      *tagcommon.TagCommonService
+	configRepo            config.ConfigRepo
+	auditLogService       *audit_log.AuditLogService
 }
 
 func NewSiteInfoService(
 	siteInfoRepo siteinfo_common.SiteInfoRepo,
 	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
 	emailService *export.EmailService,
-	tagCommonService *tagcommon.TagCommonService) *SiteInfoService {
+	tagCommonService *tagcommon.TagCommonService,
+	configRepo config.ConfigRepo,
+	auditLogService *audit_log.AuditLogService) *SiteInfoService {
 	return &SiteInfoService{
 		siteInfoRepo:          siteInfoRepo,
 		siteInfoCommonService: siteInfoCommonService,
 		emailService:          emailService,
 		tagCommonService:      tagCommonService,
+		configRepo:            configRepo,
+		auditLogService:       auditLogService,
 	}
 }
 
@@ -108,7 +126,7 @@ func (s *SiteInfoService) SaveSiteGeneral(ctx context.Context, req schema.SiteGe
 		Content: string(content),
 	}
 
-	err = s.siteInfoRepo.SaveByType(ctx, siteType, &data)
+	err = s.saveSiteInfo(ctx, siteType, &data)
 	return
 }
 
@@ -131,7 +149,7 @@ func (s *SiteInfoService) SaveSiteInterface(ctx context.Context, req schema.Site
 		Content: string(content),
 	}
 
-	err = s.siteInfoRepo.SaveByType(ctx, siteType, &data)
+	err = s.saveSiteInfo(ctx, siteType, &data)
 	return
 }
 
@@ -143,7 +161,7 @@ func (s *SiteInfoService) SaveSiteBranding(ctx context.Context, req *schema.Site
 		Content: string(content),
 		Status:  1,
 	}
-	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeBranding, data)
+	return s.saveSiteInfo(ctx, constant.SiteTypeBranding, data)
 }
 
 // SaveSiteWrite save site configuration about write
@@ -159,7 +177,7 @@ func (s *SiteInfoService) SaveSiteWrite(ctx context.Context, req *schema.SiteWri
 		Content: string(content),
 		Status:  1,
 	}
-	return nil, s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeWrite, data)
+	return nil, s.saveSiteInfo(ctx, constant.SiteTypeWrite, data)
 }
 
 // SaveSiteLegal save site legal configuration
@@ -170,7 +188,7 @@ func (s *SiteInfoService) SaveSiteLegal(ctx context.Context, req *schema.SiteLeg
 		Content: string(content),
 		Status:  1,
 	}
-	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeLegal, data)
+	return s.saveSiteInfo(ctx, constant.SiteTypeLegal, data)
 }
 
 // SaveSiteLogin save site legal configuration
@@ -181,7 +199,180 @@ func (s *SiteInfoService) SaveSiteLogin(ctx context.Context, req *schema.SiteLog
 		Content: string(content),
 		Status:  1,
 	}
-	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeLogin, data)
+	return s.saveSiteInfo(ctx, constant.SiteTypeLogin, data)
+}
+
+// GetSiteLoginConnector get site login connector configuration
+func (s *SiteInfoService) GetSiteLoginConnector(ctx context.Context) (resp *schema.SiteLoginConnectorResp, err error) {
+	return s.siteInfoCommonService.GetSiteLoginConnector(ctx)
+}
+
+// SaveSiteLoginConnector save site login connector configuration
+func (s *SiteInfoService) SaveSiteLoginConnector(ctx context.Context, req *schema.SiteLoginConnectorReq) (err error) {
+	names := make(map[string]bool)
+	for _, connector := range req.Connectors {
+		if names[connector.Name] {
+			return errors.BadRequest(reason.LoginConnectorInvalid)
+		}
+		names[connector.Name] = true
+		if connector.Name == schema.LoginConnectorTypeSAML || connector.Name == schema.LoginConnectorTypeLDAP {
+			return errors.BadRequest(reason.LoginConnectorInvalid)
+		}
+		if connector.Type == schema.LoginConnectorTypeOIDC && len(connector.Issuer) == 0 {
+			return errors.BadRequest(reason.LoginConnectorInvalid)
+		}
+	}
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeLoginConnector,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.saveSiteInfo(ctx, constant.SiteTypeLoginConnector, data)
+}
+
+// GetSiteLoginSAML get site login SAML configuration, the private key of SP is not returned
+func (s *SiteInfoService) GetSiteLoginSAML(ctx context.Context) (resp *schema.SiteLoginSAMLResp, err error) {
+	resp, err = s.siteInfoCommonService.GetSiteLoginSAML(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp.SPPrivateKey = ""
+	return resp, nil
+}
+
+// SaveSiteLoginSAML save site login SAML configuration, keep the key pair of SP if the private key is empty
+func (s *SiteInfoService) SaveSiteLoginSAML(ctx context.Context, req *schema.SiteLoginSAMLReq) (err error) {
+	if len(req.SPPrivateKey) == 0 {
+		oldConfig, err := s.siteInfoCommonService.GetSiteLoginSAML(ctx)
+		if err != nil {
+			return err
+		}
+		req.SPCertificate, req.SPPrivateKey = oldConfig.SPCertificate, oldConfig.SPPrivateKey
+	}
+	if len(req.SPPrivateKey) == 0 {
+		req.SPCertificate, req.SPPrivateKey, err = saml.GenerateKeyPair("answer", spCertificateValidFor)
+		if err != nil {
+			return errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		}
+	}
+	if _, err = saml.ParseCertificatePEM(req.SPCertificate); err != nil {
+		return errors.BadRequest(reason.LoginSAMLInvalid).WithError(err)
+	}
+	if _, err = saml.ParsePrivateKeyPEM(req.SPPrivateKey); err != nil {
+		return errors.BadRequest(reason.LoginSAMLInvalid).WithError(err)
+	}
+	if req.Enabled {
+		if len(req.IDPEntityID) == 0 || len(req.IDPSSOURL) == 0 {
+			return errors.BadRequest(reason.LoginSAMLInvalid)
+		}
+		if _, err = saml.ParseCertificatePEM(req.IDPCertificate); err != nil {
+			return errors.BadRequest(reason.LoginSAMLInvalid).WithError(err)
+		}
+	}
+
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeLoginSAML,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.saveSiteInfo(ctx, constant.SiteTypeLoginSAML, data)
+}
+
+// GetSiteLoginLDAP get site login LDAP configuration, the bind password is not returned
+func (s *SiteInfoService) GetSiteLoginLDAP(ctx context.Context) (resp *schema.SiteLoginLDAPResp, err error) {
+	resp, err = s.siteInfoCommonService.GetSiteLoginLDAP(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp.BindPassword = ""
+	return resp, nil
+}
+
+// SaveSiteLoginLDAP save site login LDAP configuration, keep the bind password if it is empty
+func (s *SiteInfoService) SaveSiteLoginLDAP(ctx context.Context, req *schema.SiteLoginLDAPReq) (err error) {
+	if len(req.BindPassword) == 0 && len(req.BindDN) > 0 {
+		oldConfig, err := s.siteInfoCommonService.GetSiteLoginLDAP(ctx)
+		if err != nil {
+			return err
+		}
+		req.BindPassword = oldConfig.BindPassword
+	}
+	if req.Enabled {
+		if !strings.HasPrefix(req.URL, "ldap://") && !strings.HasPrefix(req.URL, "ldaps://") {
+			return errors.BadRequest(reason.LoginLDAPInvalid)
+		}
+		if len(req.BaseDN) == 0 || !strings.Contains(req.UserFilter, ldapauth.LoginPlaceholder) {
+			return errors.BadRequest(reason.LoginLDAPInvalid)
+		}
+	}
+
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeLoginLDAP,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.saveSiteInfo(ctx, constant.SiteTypeLoginLDAP, data)
+}
+
+// GetSiteRateLimit get site rate limit configuration
+func (s *SiteInfoService) GetSiteRateLimit(ctx context.Context) (resp *schema.SiteRateLimitResp, err error) {
+	return s.siteInfoCommonService.GetSiteRateLimit(ctx)
+}
+
+// SaveSiteRateLimit save site rate limit configuration
+func (s *SiteInfoService) SaveSiteRateLimit(ctx context.Context, req *schema.SiteRateLimitReq) (err error) {
+	for _, rule := range req.Rules {
+		if rule.Scope == schema.RateLimitScopeIP && (rule.RoleID > 0 || rule.MinRank > 0) {
+			return errors.BadRequest(reason.RateLimitRuleInvalid)
+		}
+	}
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeRateLimit,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.saveSiteInfo(ctx, constant.SiteTypeRateLimit, data)
+}
+
+// GetSitePreModeration get site pre-moderation configuration
+func (s *SiteInfoService) GetSitePreModeration(ctx context.Context) (resp *schema.SitePreModerationResp, err error) {
+	return s.siteInfoCommonService.GetSitePreModeration(ctx)
+}
+
+// SaveSitePreModeration save site pre-moderation configuration
+func (s *SiteInfoService) SaveSitePreModeration(ctx context.Context, req *schema.SitePreModerationReq) (err error) {
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypePreModeration,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.saveSiteInfo(ctx, constant.SiteTypePreModeration, data)
+}
+
+// GetSiteSpam get site spam detection configuration
+func (s *SiteInfoService) GetSiteSpam(ctx context.Context) (resp *schema.SiteSpamResp, err error) {
+	return s.siteInfoCommonService.GetSiteSpam(ctx)
+}
+
+// SaveSiteSpam save site spam detection configuration
+func (s *SiteInfoService) SaveSiteSpam(ctx context.Context, req *schema.SiteSpamReq) (err error) {
+	for _, pattern := range req.BlockedPatterns {
+		if _, err = regexp.Compile(pattern); err != nil {
+			return errors.BadRequest(reason.SpamPatternInvalid)
+		}
+	}
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeSpam,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.saveSiteInfo(ctx, constant.SiteTypeSpam, data)
 }
 
 // SaveSiteCustomCssHTML save site custom html configuration
@@ -192,7 +383,7 @@ func (s *SiteInfoService) SaveSiteCustomCssHTML(ctx context.Context, req *schema
 		Content: string(content),
 		Status:  1,
 	}
-	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeCustomCssHTML, data)
+	return s.saveSiteInfo(ctx, constant.SiteTypeCustomCssHTML, data)
 }
 
 // SaveSiteTheme save site custom html configuration
@@ -203,7 +394,7 @@ func (s *SiteInfoService) SaveSiteTheme(ctx context.Context, req *schema.SiteThe
 		Content: string(content),
 		Status:  1,
 	}
-	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeTheme, data)
+	return s.saveSiteInfo(ctx, constant.SiteTypeTheme, data)
 }
 
 // GetSMTPConfig get smtp config
@@ -225,12 +416,27 @@ func (s *SiteInfoService) UpdateSMTPConfig(ctx context.Context, req *schema.Upda
 	if err != nil {
 		return err
 	}
+	before := *oldEmailConfig
 	_ = copier.Copy(oldEmailConfig, req)
+	// the secret is generated once, the sent reply addresses are invalid if it is changed
+	if len(oldEmailConfig.ReplyAddress) > 0 && len(oldEmailConfig.ReplySecret) == 0 {
+		oldEmailConfig.ReplySecret, err = generateReplySecret()
+		if err != nil {
+			return err
+		}
+	}
 
 	err = s.emailService.SetEmailConfig(oldEmailConfig)
 	if err != nil {
 		return err
 	}
+	s.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionConfigUpdate,
+		ObjectType: entity.AuditObjectTypeConfig,
+		ObjectID:   "email.config",
+		Before:     &before,
+		After:      oldEmailConfig,
+	})
 	if len(req.TestEmailRecipient) > 0 {
 		title, body, err := s.emailService.TestTemplate(ctx)
 		if err != nil {
@@ -279,6 +485,37 @@ func (s *SiteInfoService) SaveSeo(ctx context.Context, req schema.SiteSeoReq) (e
 		Content: string(content),
 	}
 
-	err = s.siteInfoRepo.SaveByType(ctx, siteType, &data)
+	err = s.saveSiteInfo(ctx, siteType, &data)
 	return
 }
+
+// saveSiteInfo save the site info of the type, the change is recorded in the audit log
+func (s *SiteInfoService) saveSiteInfo(ctx context.Context, siteType string, data *entity.SiteInfo) (err error) {
+	var before interface{}
+	oldSiteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, siteType)
+	if err != nil {
+		return err
+	}
+	if exist && len(oldSiteInfo.Content) > 0 {
+		before = json.RawMessage(oldSiteInfo.Content)
+	}
+	if err = s.siteInfoRepo.SaveByType(ctx, siteType, data); err != nil {
+		return err
+	}
+	s.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionSiteInfoUpdate,
+		ObjectType: entity.AuditObjectTypeSiteInfo,
+		ObjectID:   siteType,
+		Before:     before,
+		After:      json.RawMessage(data.Content),
+	})
+	return nil
+}
+
+func generateReplySecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -81,6 +81,70 @@ func (s *SiteInfoCommonService) GetSiteLogin(ctx context.Context) (resp *schema.
 	return resp, nil
 }
 
+// GetSiteLoginConnector get site login connector config
+func (s *SiteInfoCommonService) GetSiteLoginConnector(ctx context.Context) (resp *schema.SiteLoginConnectorResp, err error) {
+	resp = &schema.SiteLoginConnectorResp{Connectors: make([]*schema.LoginConnector, 0)}
+	if err = s.getSiteInfoByType(ctx, constant.SiteTypeLoginConnector, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetSiteLoginSAML get site login SAML config
+func (s *SiteInfoCommonService) GetSiteLoginSAML(ctx context.Context) (resp *schema.SiteLoginSAMLResp, err error) {
+	resp = &schema.SiteLoginSAMLResp{}
+	if err = s.getSiteInfoByType(ctx, constant.SiteTypeLoginSAML, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetSiteLoginLDAP get site login LDAP config
+func (s *SiteInfoCommonService) GetSiteLoginLDAP(ctx context.Context) (resp *schema.SiteLoginLDAPResp, err error) {
+	resp = &schema.SiteLoginLDAPResp{}
+	if err = s.getSiteInfoByType(ctx, constant.SiteTypeLoginLDAP, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetSiteRateLimit get site rate limit config, the default rules are returned if it is not configured
+func (s *SiteInfoCommonService) GetSiteRateLimit(ctx context.Context) (resp *schema.SiteRateLimitResp, err error) {
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeRateLimit)
+	if err != nil {
+		return nil, err
+	}
+	resp = &schema.SiteRateLimitResp{}
+	if !exist {
+		resp.Rules = make([]*schema.RateLimitRule, 0, len(schema.DefaultSiteRateLimitRules))
+		for _, rule := range schema.DefaultSiteRateLimitRules {
+			r := *rule
+			resp.Rules = append(resp.Rules, &r)
+		}
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSitePreModeration get site pre-moderation config
+func (s *SiteInfoCommonService) GetSitePreModeration(ctx context.Context) (resp *schema.SitePreModerationResp, err error) {
+	resp = &schema.SitePreModerationResp{}
+	if err = s.getSiteInfoByType(ctx, constant.SiteTypePreModeration, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetSiteSpam get site spam detection config
+func (s *SiteInfoCommonService) GetSiteSpam(ctx context.Context) (resp *schema.SiteSpamResp, err error) {
+	resp = &schema.SiteSpamResp{}
+	if err = s.getSiteInfoByType(ctx, constant.SiteTypeSpam, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetSiteCustomCssHTML get site custom css html config
 func (s *SiteInfoCommonService) GetSiteCustomCssHTML(ctx context.Context) (resp *schema.SiteCustomCssHTMLResp, err error) {
 	resp = &schema.SiteCustomCssHTMLResp{}
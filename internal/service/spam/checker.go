@@ -0,0 +1,249 @@
+package spam
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/rank"
+)
+
+const (
+	// minFingerprintLength the short content like "thanks" is not fingerprinted
+	minFingerprintLength = 20
+	// minTrainedDocuments the classifier is used after both the spam and the ham documents are enough
+	minTrainedDocuments = 10
+	maxTokenLength      = 40
+	maxTokens           = 500
+)
+
+var linkRegexp = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// blocklistChecker match the content with the blocked keywords and patterns
+type blocklistChecker struct {
+	patterns sync.Map
+}
+
+func (c *blocklistChecker) Name() string {
+	return "blocklist"
+}
+
+func (c *blocklistChecker) Check(ctx context.Context, conf *schema.SiteSpamResp, content *Content) (
+	verdict *Verdict, err error) {
+	action := conf.BlocklistAction
+	if len(action) == 0 {
+		action = entity.SpamActionReject
+	}
+	// the links are checked too, they are removed from the text
+	text := strings.Join(append([]string{content.Email, content.Text()}, findLinks(content.HTML)...), "\n")
+	lowerText := strings.ToLower(text)
+	for _, keyword := range conf.BlockedKeywords {
+		if len(keyword) > 0 && strings.Contains(lowerText, strings.ToLower(keyword)) {
+			return &Verdict{Action: action, Reason: fmt.Sprintf("contains the blocked keyword %q", keyword)}, nil
+		}
+	}
+	for _, pattern := range conf.BlockedPatterns {
+		re, err := c.compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if re.MatchString(text) {
+			return &Verdict{Action: action, Reason: fmt.Sprintf("matches the blocked pattern %q", pattern)}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *blocklistChecker) compile(pattern string) (re *regexp.Regexp, err error) {
+	if v, ok := c.patterns.Load(pattern); ok {
+		return v.(*regexp.Regexp), nil
+	}
+	re, err = regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	c.patterns.Store(pattern, re)
+	return re, nil
+}
+
+// linkChecker hold the post with too many links for review, the users with the link url limit power are not limited
+type linkChecker struct {
+	rankService *rank.RankService
+}
+
+func (c *linkChecker) Name() string {
+	return "link_limit"
+}
+
+func (c *linkChecker) Check(ctx context.Context, conf *schema.SiteSpamResp, content *Content) (
+	verdict *Verdict, err error) {
+	if conf.MaxLinks == 0 || len(content.UserID) == 0 {
+		return nil, nil
+	}
+	links := len(findLinks(content.HTML))
+	if links <= conf.MaxLinks {
+		return nil, nil
+	}
+	unlimited, err := c.rankService.CheckOperationPermission(ctx, content.UserID, permission.LinkUrlLimit, "")
+	if err != nil || unlimited {
+		return nil, err
+	}
+	return &Verdict{
+		Action: entity.SpamActionReview,
+		Reason: fmt.Sprintf("contains %d links, at most %d are allowed", links, conf.MaxLinks),
+	}, nil
+}
+
+// findLinks the distinct links in the html, the link in both the href and the text is counted once
+func findLinks(html string) (links []string) {
+	seen := make(map[string]bool)
+	for _, link := range linkRegexp.FindAllString(html, -1) {
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// duplicateChecker hold the content posted too many times in the window for review
+type duplicateChecker struct {
+	spamRepo SpamRepo
+}
+
+func (c *duplicateChecker) Name() string {
+	return "duplicate"
+}
+
+func (c *duplicateChecker) Check(ctx context.Context, conf *schema.SiteSpamResp, content *Content) (
+	verdict *Verdict, err error) {
+	if conf.DuplicateLimit == 0 || conf.DuplicateWindow == 0 || content.ObjectType == constant.UserObjectType {
+		return nil, nil
+	}
+	fp := fingerprint(content.Text())
+	if len(fp) == 0 {
+		return nil, nil
+	}
+	window := time.Duration(conf.DuplicateWindow) * time.Second
+	count, err := c.spamRepo.IncreaseFingerprintCount(ctx, fp, window)
+	if err != nil || count <= int64(conf.DuplicateLimit) {
+		return nil, err
+	}
+	return &Verdict{
+		Action: entity.SpamActionReview,
+		Reason: fmt.Sprintf("the same content has been posted %d times in %s", count, window),
+	}, nil
+}
+
+// fingerprint the hash of the normalized text, the case and the spaces are ignored
+func fingerprint(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	if len([]rune(normalized)) < minFingerprintLength {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// classifierChecker the naive Bayes classifier trained by the spam decisions of the moderators
+type classifierChecker struct {
+	spamRepo SpamRepo
+}
+
+func (c *classifierChecker) Name() string {
+	return "classifier"
+}
+
+func (c *classifierChecker) Check(ctx context.Context, conf *schema.SiteSpamResp, content *Content) (
+	verdict *Verdict, err error) {
+	if !conf.ClassifierEnabled {
+		return nil, nil
+	}
+	tokens := tokenize(content.Text())
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	spamTokens, err := c.spamRepo.GetSpamTokens(ctx, append(tokens, entity.SpamTokenDocuments))
+	if err != nil {
+		return nil, err
+	}
+	score, trained := spamScore(spamTokens)
+	if !trained {
+		return nil, nil
+	}
+	reason := fmt.Sprintf("the spam probability is %.2f", score)
+	if conf.ClassifierRejectScore > 0 && score >= conf.ClassifierRejectScore {
+		return &Verdict{Action: entity.SpamActionReject, Reason: reason}, nil
+	}
+	if conf.ClassifierReviewScore > 0 && score >= conf.ClassifierReviewScore {
+		return &Verdict{Action: entity.SpamActionReview, Reason: reason}, nil
+	}
+	return nil, nil
+}
+
+// spamScore the spam probability of the content with the trained tokens, the tokens are counted once per document
+// and smoothed by Laplace, it returns false if the classifier is not trained enough
+func spamScore(spamTokens []*entity.SpamToken) (score float64, trained bool) {
+	var documents *entity.SpamToken
+	for _, spamToken := range spamTokens {
+		if spamToken.Token == entity.SpamTokenDocuments {
+			documents = spamToken
+		}
+	}
+	if documents == nil || documents.SpamCount < minTrainedDocuments || documents.HamCount < minTrainedDocuments {
+		return 0, false
+	}
+	spamDocuments, hamDocuments := float64(documents.SpamCount), float64(documents.HamCount)
+	logOdds := math.Log(spamDocuments) - math.Log(hamDocuments)
+	for _, spamToken := range spamTokens {
+		if spamToken.Token == entity.SpamTokenDocuments {
+			continue
+		}
+		logOdds += math.Log((float64(spamToken.SpamCount)+1)/(spamDocuments+2)) -
+			math.Log((float64(spamToken.HamCount)+1)/(hamDocuments+2))
+	}
+	return 1 / (1 + math.Exp(-logOdds)), true
+}
+
+// tokenize the distinct lower case words of the text, every Han character is a token as the words are not
+// separated by spaces
+func tokenize(text string) (tokens []string) {
+	seen := make(map[string]bool)
+	word := make([]rune, 0, maxTokenLength)
+	add := func(token string) {
+		if !seen[token] && len(tokens) < maxTokens {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	flush := func() {
+		if len(word) >= 2 && len(word) <= maxTokenLength {
+			add(string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			add(string(r))
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			word = append(word, unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
@@ -0,0 +1,68 @@
+package spam
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_blocklistChecker_Check(t *testing.T) {
+	checker := &blocklistChecker{}
+	conf := &schema.SiteSpamResp{
+		BlockedKeywords: []string{"Casino"},
+		BlockedPatterns: []string{`cheap-[a-z]+\.example\.com`},
+	}
+
+	verdict, err := checker.Check(context.TODO(), conf, &Content{Title: "Best CASINO bonus"})
+	assert.NoError(t, err)
+	assert.True(t, verdict.Rejected())
+
+	// the link is removed from the text but still checked
+	conf.BlocklistAction = entity.SpamActionReview
+	verdict, err = checker.Check(context.TODO(), conf, &Content{
+		HTML: `<p><a href="https://cheap-pills.example.com/buy">click</a></p>`,
+	})
+	assert.NoError(t, err)
+	assert.True(t, verdict.NeedReview())
+
+	verdict, err = checker.Check(context.TODO(), conf, &Content{Title: "How to use goroutines"})
+	assert.NoError(t, err)
+	assert.Nil(t, verdict)
+}
+
+func Test_findLinks(t *testing.T) {
+	html := `<p><a href="https://example.com/a">https://example.com/a</a> and http://example.org</p>`
+	assert.Equal(t, []string{"https://example.com/a", "http://example.org"}, findLinks(html))
+	assert.Empty(t, findLinks("<p>no links</p>"))
+}
+
+func Test_fingerprint(t *testing.T) {
+	assert.Equal(t, fingerprint("Buy cheap watches at our store"), fingerprint("  buy CHEAP watches\nat our   store"))
+	assert.NotEqual(t, fingerprint("Buy cheap watches at our store"), fingerprint("Buy cheap watches at your store"))
+	assert.Empty(t, fingerprint("thanks"))
+}
+
+func Test_tokenize(t *testing.T) {
+	assert.Equal(t, []string{"hello", "world", "你", "好", "go118"}, tokenize("Hello, world! 你好 a Go118 hello"))
+}
+
+func Test_spamScore(t *testing.T) {
+	documents := &entity.SpamToken{Token: entity.SpamTokenDocuments, SpamCount: 20, HamCount: 20}
+	casino := &entity.SpamToken{Token: "casino", SpamCount: 18, HamCount: 1}
+	golang := &entity.SpamToken{Token: "golang", SpamCount: 1, HamCount: 15}
+
+	score, trained := spamScore([]*entity.SpamToken{documents, casino})
+	assert.True(t, trained)
+	assert.Greater(t, score, 0.9)
+
+	score, trained = spamScore([]*entity.SpamToken{documents, golang})
+	assert.True(t, trained)
+	assert.Less(t, score, 0.2)
+
+	// the classifier is not used before it is trained enough
+	_, trained = spamScore([]*entity.SpamToken{{Token: entity.SpamTokenDocuments, SpamCount: 3, HamCount: 50}, casino})
+	assert.False(t, trained)
+}
@@ -0,0 +1,235 @@
+package spam
+
+import (
+	"context"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/pager"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/object_info"
+	"github.com/answerdev/answer/internal/service/rank"
+	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/pkg/htmltext"
+	"github.com/segmentfault/pacman/log"
+)
+
+// SpamRepo spam repository
+type SpamRepo interface {
+	AddSpamLog(ctx context.Context, spamLog *entity.SpamLog) (err error)
+	GetSpamLogPage(ctx context.Context, page, pageSize int, cond *entity.SpamLog) (
+		spamLogs []*entity.SpamLog, total int64, err error)
+	IncreaseFingerprintCount(ctx context.Context, fingerprint string, window time.Duration) (count int64, err error)
+	GetSpamTokens(ctx context.Context, tokens []string) (spamTokens []*entity.SpamToken, err error)
+	TrainSpamTokens(ctx context.Context, tokens []string, isSpam bool) (err error)
+}
+
+// Content the content checked by the spam detection
+type Content struct {
+	// UserID the author of the content, it is empty for the registration
+	UserID string
+	// ObjectType question, answer, comment or user
+	ObjectType string
+	Title      string
+	HTML       string
+	// Email the email of the registration
+	Email string
+}
+
+// Text the plain text of the title and the content
+func (c *Content) Text() string {
+	if len(c.HTML) == 0 {
+		return c.Title
+	}
+	return c.Title + "\n" + htmltext.ClearText(c.HTML)
+}
+
+// Verdict the result of the spam check, nil means the content is allowed
+type Verdict struct {
+	// Action review or reject
+	Action  string
+	Checker string
+	Reason  string
+}
+
+// Rejected whether the content should be rejected
+func (v *Verdict) Rejected() bool {
+	return v != nil && v.Action == entity.SpamActionReject
+}
+
+// NeedReview whether the content should be held for review
+func (v *Verdict) NeedReview() bool {
+	return v != nil && v.Action == entity.SpamActionReview
+}
+
+// String the reason shown to the moderators
+func (v *Verdict) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.Checker + ": " + v.Reason
+}
+
+// Checker the checker of the spam detection pipeline, it returns nil if the content is allowed
+type Checker interface {
+	Name() string
+	Check(ctx context.Context, conf *schema.SiteSpamResp, content *Content) (verdict *Verdict, err error)
+}
+
+// SpamService spam detection service
+type SpamService struct {
+	spamRepo              SpamRepo
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
+	objectInfoService     *object_info.ObjService
+	userCommon            *usercommon.UserCommon
+	checkers              []Checker
+}
+
+// NewSpamService new spam service with the built-in checkers
+func NewSpamService(
+	spamRepo SpamRepo,
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+	objectInfoService *object_info.ObjService,
+	userCommon *usercommon.UserCommon,
+	rankService *rank.RankService,
+) *SpamService {
+	return &SpamService{
+		spamRepo:              spamRepo,
+		siteInfoCommonService: siteInfoCommonService,
+		objectInfoService:     objectInfoService,
+		userCommon:            userCommon,
+		checkers: []Checker{
+			&blocklistChecker{},
+			&linkChecker{rankService: rankService},
+			&duplicateChecker{spamRepo: spamRepo},
+			&classifierChecker{spamRepo: spamRepo},
+		},
+	}
+}
+
+// RegisterChecker add the checker to the end of the pipeline
+func (ss *SpamService) RegisterChecker(checker Checker) {
+	ss.checkers = append(ss.checkers, checker)
+}
+
+// Check run the checkers in order and return the most severe verdict, the verdict is logged for the moderators.
+// The failed checker is skipped, it should not stop the users from posting.
+func (ss *SpamService) Check(ctx context.Context, content *Content) (verdict *Verdict, err error) {
+	conf, err := ss.siteInfoCommonService.GetSiteSpam(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !conf.Enabled {
+		return nil, nil
+	}
+	for _, checker := range ss.checkers {
+		v, err := checker.Check(ctx, conf, content)
+		if err != nil {
+			log.Errorf("spam checker %s failed: %v", checker.Name(), err)
+			continue
+		}
+		if v == nil {
+			continue
+		}
+		v.Checker = checker.Name()
+		if verdict == nil || v.Rejected() {
+			verdict = v
+		}
+		if verdict.Rejected() {
+			break
+		}
+	}
+	if verdict != nil {
+		ss.addSpamLog(ctx, content, verdict)
+	}
+	return verdict, nil
+}
+
+func (ss *SpamService) addSpamLog(ctx context.Context, content *Content, verdict *Verdict) {
+	spamLog := &entity.SpamLog{
+		UserID:     content.UserID,
+		ObjectType: content.ObjectType,
+		Action:     verdict.Action,
+		Checker:    verdict.Checker,
+		Reason:     verdict.Reason,
+		Content:    content.Text(),
+	}
+	if len(spamLog.UserID) == 0 {
+		spamLog.UserID = "0"
+	}
+	if len(content.Email) > 0 {
+		spamLog.Content = content.Email + "\n" + spamLog.Content
+	}
+	if ip, ok := ctx.Value(constant.ClientIPFlag).(string); ok {
+		spamLog.IP = ip
+	}
+	if err := ss.spamRepo.AddSpamLog(ctx, spamLog); err != nil {
+		log.Error(err)
+	}
+}
+
+// TrainObject train the classifier with the post decided by the moderator
+func (ss *SpamService) TrainObject(ctx context.Context, objectID string, isSpam bool) {
+	ss.TrainTokens(ctx, ss.ObjectTokens(ctx, objectID), isSpam)
+}
+
+// ObjectTokens get the tokens of the post to train the classifier, so that the post can be trained
+// after it is deleted
+func (ss *SpamService) ObjectTokens(ctx context.Context, objectID string) (tokens []string) {
+	objInfo, err := ss.objectInfoService.GetInfo(ctx, objectID)
+	if err != nil {
+		log.Error(err)
+		return nil
+	}
+	content := &Content{HTML: objInfo.Content}
+	// the title of the answer and the comment is the title of the question
+	if objInfo.ObjectType == constant.QuestionObjectType {
+		content.Title = objInfo.Title
+	}
+	return tokenize(content.Text())
+}
+
+// TrainTokens train the classifier with the tokens of a post
+func (ss *SpamService) TrainTokens(ctx context.Context, tokens []string, isSpam bool) {
+	if len(tokens) == 0 {
+		return
+	}
+	if err := ss.spamRepo.TrainSpamTokens(ctx, append(tokens, entity.SpamTokenDocuments), isSpam); err != nil {
+		log.Error(err)
+	}
+}
+
+// GetSpamLogPage get spam log page
+func (ss *SpamService) GetSpamLogPage(ctx context.Context, req *schema.GetSpamLogPageReq) (
+	resp *pager.PageModel, err error) {
+	cond := &entity.SpamLog{Action: req.Action, ObjectType: req.ObjectType}
+	spamLogs, total, err := ss.spamRepo.GetSpamLogPage(ctx, req.Page, req.PageSize, cond)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(spamLogs))
+	for _, spamLog := range spamLogs {
+		userIDs = append(userIDs, spamLog.UserID)
+	}
+	userInfoMapping, err := ss.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*schema.GetSpamLogResp, 0, len(spamLogs))
+	for _, spamLog := range spamLogs {
+		list = append(list, &schema.GetSpamLogResp{
+			ID:         spamLog.ID,
+			CreatedAt:  spamLog.CreatedAt.Unix(),
+			UserInfo:   userInfoMapping[spamLog.UserID],
+			ObjectType: spamLog.ObjectType,
+			Action:     spamLog.Action,
+			Checker:    spamLog.Checker,
+			Reason:     spamLog.Reason,
+			Content:    spamLog.Content,
+			IP:         spamLog.IP,
+		})
+	}
+	return pager.NewPageModel(total, list), nil
+}
@@ -0,0 +1,158 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/goccy/go-json"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// channel the pub/sub channel of all stream events, every instance dispatches them to its own clients
+	channel = "stream"
+	// clientBufferSize the events are dropped if the client is too slow to receive them
+	clientBufferSize = 32
+)
+
+const (
+	// EventRedDot the red dot counts should be refreshed, the data is the counts of the receiver
+	EventRedDot = "red_dot"
+	// EventNotification new notification of user
+	EventNotification = "notification"
+	// EventVote the vote count of question or answer changed
+	EventVote = "vote"
+	// EventAnswerCount the answer count of question changed
+	EventAnswerCount = "answer_count"
+)
+
+// ReviewKey the key of the users who can review the revisions and the pending posts
+const ReviewKey = "review"
+
+// UserKey the key of the events of user
+func UserKey(userID string) string {
+	return "user:" + userID
+}
+
+// QuestionKey the key of the events of question page
+func QuestionKey(questionID string) string {
+	return "question:" + questionID
+}
+
+// Event the event pushed to client
+type Event struct {
+	Event string
+	Data  json.RawMessage
+}
+
+type message struct {
+	Key   string          `json:"key"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// Client the subscriber of the events of keys
+type Client struct {
+	keys   []string
+	events chan *Event
+}
+
+// Events the events of client
+func (c *Client) Events() <-chan *Event {
+	return c.events
+}
+
+// StreamService dispatch the events published by all instances to the clients connected to current instance
+type StreamService struct {
+	pubSub  data.PubSub
+	mu      sync.RWMutex
+	clients map[string]map[*Client]struct{}
+}
+
+// defaultService the service used by Publish, it is set when the service is created
+var defaultService *StreamService
+
+// NewStreamService new stream service
+func NewStreamService(data *data.Data) *StreamService {
+	ss := &StreamService{
+		pubSub:  data.PubSub,
+		clients: make(map[string]map[*Client]struct{}),
+	}
+	ss.pubSub.Subscribe(context.Background(), channel, ss.dispatch)
+	defaultService = ss
+	return ss
+}
+
+// Subscribe new client subscribed the events of keys, it must be unsubscribed after used
+func (ss *StreamService) Subscribe(keys ...string) *Client {
+	client := &Client{keys: keys, events: make(chan *Event, clientBufferSize)}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for _, key := range keys {
+		if ss.clients[key] == nil {
+			ss.clients[key] = make(map[*Client]struct{})
+		}
+		ss.clients[key][client] = struct{}{}
+	}
+	return client
+}
+
+// Unsubscribe remove the client
+func (ss *StreamService) Unsubscribe(client *Client) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for _, key := range client.keys {
+		delete(ss.clients[key], client)
+		if len(ss.clients[key]) == 0 {
+			delete(ss.clients, key)
+		}
+	}
+}
+
+// Publish publish the event to the clients subscribed the key on all instances
+func (ss *StreamService) Publish(ctx context.Context, key, event string, eventData interface{}) {
+	msg := &message{Key: key, Event: event}
+	if eventData != nil {
+		raw, err := json.Marshal(eventData)
+		if err != nil {
+			log.Errorf("encode %s stream event failed: %s", event, err)
+			return
+		}
+		msg.Data = raw
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		log.Errorf("encode %s stream event failed: %s", event, err)
+		return
+	}
+	if err = ss.pubSub.Publish(ctx, channel, payload); err != nil {
+		log.Errorf("publish %s stream event failed: %s", event, err)
+	}
+}
+
+func (ss *StreamService) dispatch(payload []byte) {
+	msg := &message{}
+	if err := json.Unmarshal(payload, msg); err != nil {
+		log.Errorf("decode stream event failed: %s", err)
+		return
+	}
+	event := &Event{Event: msg.Event, Data: msg.Data}
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	for client := range ss.clients[msg.Key] {
+		select {
+		case client.events <- event:
+		default:
+			log.Debugf("drop %s stream event of slow client", msg.Event)
+		}
+	}
+}
+
+// Publish publish the event by the default service, it does nothing before the service is created
+func Publish(ctx context.Context, key, event string, eventData interface{}) {
+	if defaultService == nil {
+		return
+	}
+	defaultService.Publish(ctx, key, event, eventData)
+}
@@ -0,0 +1,34 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamService(t *testing.T) {
+	ss := NewStreamService(&data.Data{PubSub: data.NewMemoryPubSub()})
+	user := ss.Subscribe(UserKey("1"), QuestionKey("10"))
+	other := ss.Subscribe(UserKey("2"))
+
+	Publish(context.TODO(), QuestionKey("10"), EventAnswerCount, map[string]int{"answer_count": 2})
+	select {
+	case event := <-user.Events():
+		assert.Equal(t, EventAnswerCount, event.Event)
+		assert.JSONEq(t, `{"answer_count":2}`, string(event.Data))
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+	select {
+	case event := <-other.Events():
+		t.Errorf("unexpected event %s", event.Event)
+	default:
+	}
+
+	ss.Unsubscribe(user)
+	ss.Unsubscribe(other)
+	assert.Empty(t, ss.clients)
+}
@@ -45,8 +45,10 @@ type TagRelRepo interface {
 	AddTagRelList(ctx context.Context, tagList []*entity.TagRel) (err error)
 	RemoveTagRelListByIDs(ctx context.Context, ids []int64) (err error)
 	EnableTagRelByIDs(ctx context.Context, ids []int64) (err error)
+	HoldTagRelByIDs(ctx context.Context, ids []int64) (err error)
 	GetObjectTagRelWithoutStatus(ctx context.Context, objectId, tagID string) (tagRel *entity.TagRel, exist bool, err error)
 	GetObjectTagRelList(ctx context.Context, objectId string) (tagListList []*entity.TagRel, err error)
+	GetObjectPendingTagRelList(ctx context.Context, objectId string) (tagListList []*entity.TagRel, err error)
 	BatchGetObjectTagRelList(ctx context.Context, objectIds []string) (tagListList []*entity.TagRel, err error)
 	CountTagRelByTagID(ctx context.Context, tagID string) (count int64, err error)
 }
@@ -492,9 +494,95 @@ func (ts *TagCommonService) ObjectChangeTag(ctx context.Context, objectTagData *
 	if len(objectTagData.Tags) == 0 {
 		return nil
 	}
+	tagIDs, err := ts.addObjectTagList(ctx, objectTagData)
+	if err != nil {
+		return err
+	}
+	return ts.CreateOrUpdateTagRelList(ctx, objectTagData.ObjectID, tagIDs)
+}
+
+// ObjectChangePendingTag change the tag list of the object held for review, the relations are kept pending
+// and not counted until the object is published
+func (ts *TagCommonService) ObjectChangePendingTag(ctx context.Context, objectTagData *schema.TagChange) (err error) {
+	if len(objectTagData.Tags) == 0 {
+		return nil
+	}
+	tagIDs, err := ts.addObjectTagList(ctx, objectTagData)
+	if err != nil {
+		return err
+	}
+
+	holdTagIDMapping := make(map[string]bool)
+	for _, tagID := range tagIDs {
+		holdTagIDMapping[tagID] = true
+	}
+	oldTagRelList, err := ts.tagRelRepo.GetObjectPendingTagRelList(ctx, objectTagData.ObjectID)
+	if err != nil {
+		return err
+	}
+	var deleteTagRel []int64
+	for _, rel := range oldTagRelList {
+		if !holdTagIDMapping[rel.TagID] {
+			deleteTagRel = append(deleteTagRel, rel.ID)
+		}
+	}
+
+	addTagRelList := make([]*entity.TagRel, 0)
+	holdTagRelList := make([]int64, 0)
+	for _, tagID := range tagIDs {
+		rel, exist, err := ts.tagRelRepo.GetObjectTagRelWithoutStatus(ctx, objectTagData.ObjectID, tagID)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			addTagRelList = append(addTagRelList, &entity.TagRel{
+				TagID: tagID, ObjectID: objectTagData.ObjectID, Status: entity.TagRelStatusPending,
+			})
+		}
+		if exist && rel.Status != entity.TagRelStatusPending {
+			holdTagRelList = append(holdTagRelList, rel.ID)
+		}
+	}
+
+	if len(deleteTagRel) > 0 {
+		if err = ts.tagRelRepo.RemoveTagRelListByIDs(ctx, deleteTagRel); err != nil {
+			return err
+		}
+	}
+	if len(addTagRelList) > 0 {
+		if err = ts.tagRelRepo.AddTagRelList(ctx, addTagRelList); err != nil {
+			return err
+		}
+	}
+	if len(holdTagRelList) > 0 {
+		if err = ts.tagRelRepo.HoldTagRelByIDs(ctx, holdTagRelList); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// PublishObjectPendingTag enable the pending tag relations of the published object and refresh the tag counts
+func (ts *TagCommonService) PublishObjectPendingTag(ctx context.Context, objectID string) (err error) {
+	pendingTagRelList, err := ts.tagRelRepo.GetObjectPendingTagRelList(ctx, objectID)
+	if err != nil {
+		return err
+	}
+	if len(pendingTagRelList) == 0 {
+		return nil
+	}
+	tagIDs := make([]string, 0, len(pendingTagRelList))
+	for _, rel := range pendingTagRelList {
+		tagIDs = append(tagIDs, rel.TagID)
+	}
+	return ts.CreateOrUpdateTagRelList(ctx, objectID, tagIDs)
+}
+
+// addObjectTagList add the tags of object which do not exist, returns the ids of all the tags
+func (ts *TagCommonService) addObjectTagList(ctx context.Context, objectTagData *schema.TagChange) (
+	thisObjTagIDList []string, err error) {
 	thisObjTagNameList := make([]string, 0)
-	thisObjTagIDList := make([]string, 0)
+	thisObjTagIDList = make([]string, 0)
 	for _, t := range objectTagData.Tags {
 		t.SlugName = strings.ToLower(t.SlugName)
 		thisObjTagNameList = append(thisObjTagNameList, t.SlugName)
@@ -503,7 +591,7 @@ func (ts *TagCommonService) ObjectChangeTag(ctx context.Context, objectTagData *
 	// find tags name
 	tagListInDb, err := ts.tagCommonRepo.GetTagListByNames(ctx, thisObjTagNameList)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	tagInDbMapping := make(map[string]*entity.Tag)
@@ -531,7 +619,7 @@ func (ts *TagCommonService) ObjectChangeTag(ctx context.Context, objectTagData *
 	if len(addTagList) > 0 {
 		err = ts.tagCommonRepo.AddTagList(ctx, addTagList)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, tag := range addTagList {
 			thisObjTagIDList = append(thisObjTagIDList, tag.ID)
@@ -544,7 +632,7 @@ func (ts *TagCommonService) ObjectChangeTag(ctx context.Context, objectTagData *
 			revisionDTO.Content = string(tagInfoJson)
 			revisionID, err := ts.revisionService.AddRevision(ctx, revisionDTO, true)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			activity_queue.AddActivity(&schema.ActivityMsg{
 				UserID:           objectTagData.UserID,
@@ -556,11 +644,7 @@ func (ts *TagCommonService) ObjectChangeTag(ctx context.Context, objectTagData *
 		}
 	}
 
-	err = ts.CreateOrUpdateTagRelList(ctx, objectTagData.ObjectID, thisObjTagIDList)
-	if err != nil {
-		return err
-	}
-	return nil
+	return thisObjTagIDList, nil
 }
 
 // RefreshTagQuestionCount refresh tag question count
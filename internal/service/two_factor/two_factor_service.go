@@ -0,0 +1,412 @@
+package two_factor
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/role"
+	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/pkg/twofactor"
+	"github.com/google/uuid"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const recoveryCodeCount = 10
+
+// LoginCache the user who has passed the password verification and waits for the two-factor code
+type LoginCache struct {
+	UserID string `json:"user_id"`
+}
+
+// TwoFactorRepo user two-factor repository
+type TwoFactorRepo interface {
+	GetByUserID(ctx context.Context, userID string) (info *entity.UserTwoFactor, exist bool, err error)
+	// SavePending save the secret which is not enabled yet, replace the old pending secret
+	SavePending(ctx context.Context, userID, secret string) (err error)
+	Enable(ctx context.Context, userID, recoveryCodes string) (err error)
+	// UpdateLastUsedStep update the step only if it is after the saved one, so the code can be only used once
+	UpdateLastUsedStep(ctx context.Context, userID string, step int64) (updated bool, err error)
+	// UpdateRecoveryCodes update the codes only if they are not changed, so the recovery code can be only used once
+	UpdateRecoveryCodes(ctx context.Context, userID, oldRecoveryCodes, recoveryCodes string) (updated bool, err error)
+	RemoveByUserID(ctx context.Context, userID string) (err error)
+	SetLoginCache(ctx context.Context, token string, cache *LoginCache) (err error)
+	GetLoginCache(ctx context.Context, token string) (cache *LoginCache, exist bool, err error)
+	RemoveLoginCache(ctx context.Context, token string) (err error)
+	// IncreaseLoginAttempts increase the wrong codes of the login atomically, so the concurrent attempts are all counted
+	IncreaseLoginAttempts(ctx context.Context, token string) (attempts int64, err error)
+}
+
+// moderatorPowers the powers to moderate the posts of others, the role with any of them is treated as moderator,
+// even if they are scoped to tags
+var moderatorPowers = []string{
+	permission.QuestionDelete,
+	permission.QuestionClose,
+	permission.QuestionAudit,
+	permission.AnswerDelete,
+	permission.AnswerAudit,
+	permission.CommentDelete,
+	permission.TagAudit,
+}
+
+// TwoFactorService user two-factor authentication service
+type TwoFactorService struct {
+	twoFactorRepo         TwoFactorRepo
+	userRepo              usercommon.UserRepo
+	userRoleService       *role.UserRoleRelService
+	rolePowerService      *role.RolePowerRelService
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
+	auditLogService       *audit_log.AuditLogService
+}
+
+// NewTwoFactorService new two-factor service
+func NewTwoFactorService(
+	twoFactorRepo TwoFactorRepo,
+	userRepo usercommon.UserRepo,
+	userRoleService *role.UserRoleRelService,
+	rolePowerService *role.RolePowerRelService,
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+	auditLogService *audit_log.AuditLogService,
+) *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo:         twoFactorRepo,
+		userRepo:              userRepo,
+		userRoleService:       userRoleService,
+		rolePowerService:      rolePowerService,
+		siteInfoCommonService: siteInfoCommonService,
+		auditLogService:       auditLogService,
+	}
+}
+
+// GetStatus get the two-factor status of user
+func (ts *TwoFactorService) GetStatus(ctx context.Context, userID string) (resp *schema.TwoFactorStatusResp, err error) {
+	resp = &schema.TwoFactorStatusResp{}
+	info, exist, err := ts.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exist && info.Enabled {
+		resp.Enabled = true
+		resp.RecoveryCodesRemaining = len(parseRecoveryCodes(info.RecoveryCodes))
+	}
+	resp.Required, err = ts.isRequired(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Enroll generate a new pending secret, it is enabled after the first code is verified
+func (ts *TwoFactorService) Enroll(ctx context.Context, userID string) (resp *schema.TwoFactorEnrollResp, err error) {
+	info, exist, err := ts.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exist && info.Enabled {
+		return nil, errors.BadRequest(reason.TwoFactorAlreadyEnabled)
+	}
+	userInfo, exist, err := ts.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	siteGeneral, err := ts.siteInfoCommonService.GetSiteGeneral(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := twofactor.GenerateKey(siteGeneral.Name, userInfo.EMail)
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	if err = ts.twoFactorRepo.SavePending(ctx, userID, key.Secret); err != nil {
+		return nil, err
+	}
+	return &schema.TwoFactorEnrollResp{
+		Secret:          key.Secret,
+		ProvisioningURI: key.URI,
+		QRCode:          key.QRCode,
+	}, nil
+}
+
+// Enable verify the code of pending secret and enable it, the recovery codes are returned
+func (ts *TwoFactorService) Enable(ctx context.Context, req *schema.TwoFactorCodeReq) (
+	resp *schema.TwoFactorRecoveryCodesResp, err error) {
+	info, exist, err := ts.twoFactorRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.TwoFactorNotEnabled)
+	}
+	if info.Enabled {
+		return nil, errors.BadRequest(reason.TwoFactorAlreadyEnabled)
+	}
+	return ts.enable(ctx, info, req.Code)
+}
+
+// Disable verify the code and disable two-factor authentication, it can not be disabled if it is required
+func (ts *TwoFactorService) Disable(ctx context.Context, req *schema.TwoFactorCodeReq) (err error) {
+	required, err := ts.isRequired(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errors.Forbidden(reason.TwoFactorRequired)
+	}
+	info, err := ts.getEnabled(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	ok, err := ts.verifyCode(ctx, info, req.Code, true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	return ts.twoFactorRepo.RemoveByUserID(ctx, req.UserID)
+}
+
+// RegenerateRecoveryCodes verify the TOTP code and replace all recovery codes
+func (ts *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, req *schema.TwoFactorCodeReq) (
+	resp *schema.TwoFactorRecoveryCodesResp, err error) {
+	info, err := ts.getEnabled(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := ts.verifyCode(ctx, info, req.Code, false)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	codes, hashes, err := twofactor.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	updated, err := ts.twoFactorRepo.UpdateRecoveryCodes(ctx, req.UserID, info.RecoveryCodes, formatRecoveryCodes(hashes))
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	return &schema.TwoFactorRecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+// AdminReset remove the two-factor authentication of user, the user can login with password only
+// or enroll again on next login if it is required.
+func (ts *TwoFactorService) AdminReset(ctx context.Context, req *schema.AdminResetTwoFactorReq) (err error) {
+	if err = ts.twoFactorRepo.RemoveByUserID(ctx, req.UserID); err != nil {
+		return err
+	}
+	ts.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionTwoFactorReset,
+		ObjectType: constant.UserObjectType,
+		ObjectID:   req.UserID,
+	})
+	return nil
+}
+
+// CheckLogin check whether the user should verify the two-factor code after the password is verified,
+// the returned response contains the token for verification, it is nil if the verification is not needed.
+func (ts *TwoFactorService) CheckLogin(ctx context.Context, userID string, roleID int) (
+	resp *schema.GetUserResp, err error) {
+	info, exist, err := ts.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := exist && info.Enabled
+	if !enabled {
+		required, err := ts.isRoleRequired(ctx, roleID)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+	}
+
+	token := strings.ReplaceAll(uuid.NewString(), "-", "")
+	if err = ts.twoFactorRepo.SetLoginCache(ctx, token, &LoginCache{UserID: userID}); err != nil {
+		return nil, err
+	}
+	return &schema.GetUserResp{TwoFactorToken: token, TwoFactorEnrollRequired: !enabled}, nil
+}
+
+// LoginEnroll enroll two-factor authentication for the user who is required to enroll during login
+func (ts *TwoFactorService) LoginEnroll(ctx context.Context, req *schema.TwoFactorLoginEnrollReq) (
+	resp *schema.TwoFactorEnrollResp, err error) {
+	loginCache, exist, err := ts.twoFactorRepo.GetLoginCache(ctx, req.TwoFactorToken)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.TwoFactorLoginExpired)
+	}
+	return ts.Enroll(ctx, loginCache.UserID)
+}
+
+// VerifyLogin verify the code of the user who is logging in, the pending secret is enabled if the user
+// is enrolling. The login must be restarted after too many wrong codes.
+func (ts *TwoFactorService) VerifyLogin(ctx context.Context, req *schema.TwoFactorLoginReq) (
+	userID string, recoveryCodes []string, err error) {
+	loginCache, exist, err := ts.twoFactorRepo.GetLoginCache(ctx, req.TwoFactorToken)
+	if err != nil {
+		return "", nil, err
+	}
+	if !exist {
+		return "", nil, errors.BadRequest(reason.TwoFactorLoginExpired)
+	}
+	info, exist, err := ts.twoFactorRepo.GetByUserID(ctx, loginCache.UserID)
+	if err != nil {
+		return "", nil, err
+	}
+	if !exist {
+		return "", nil, errors.BadRequest(reason.TwoFactorNotEnabled)
+	}
+
+	ok := false
+	if info.Enabled {
+		ok, err = ts.verifyCode(ctx, info, req.Code, true)
+		if err != nil {
+			return "", nil, err
+		}
+	} else {
+		// the user who is required to enroll verifies the code of pending secret
+		resp, enableErr := ts.enable(ctx, info, req.Code)
+		if enableErr == nil {
+			ok, recoveryCodes = true, resp.RecoveryCodes
+		} else if myErr, isErr := enableErr.(*errors.Error); !isErr || !errors.IsBadRequest(myErr) {
+			return "", nil, enableErr
+		}
+	}
+	if !ok {
+		attempts, err := ts.twoFactorRepo.IncreaseLoginAttempts(ctx, req.TwoFactorToken)
+		if err == nil && attempts >= constant.TwoFactorLoginMaxAttempts {
+			err = ts.twoFactorRepo.RemoveLoginCache(ctx, req.TwoFactorToken)
+		}
+		if err != nil {
+			log.Error(err)
+		}
+		return "", nil, errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+
+	if err = ts.twoFactorRepo.RemoveLoginCache(ctx, req.TwoFactorToken); err != nil {
+		return "", nil, err
+	}
+	return loginCache.UserID, recoveryCodes, nil
+}
+
+// enable verify the TOTP code of pending secret and enable it with new recovery codes
+func (ts *TwoFactorService) enable(ctx context.Context, info *entity.UserTwoFactor, code string) (
+	resp *schema.TwoFactorRecoveryCodesResp, err error) {
+	ok, err := ts.verifyCode(ctx, info, code, false)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	codes, hashes, err := twofactor.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	if err = ts.twoFactorRepo.Enable(ctx, info.UserID, formatRecoveryCodes(hashes)); err != nil {
+		return nil, err
+	}
+	return &schema.TwoFactorRecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+// verifyCode verify the TOTP code, or the recovery code if it is allowed, the code can be used only once
+func (ts *TwoFactorService) verifyCode(ctx context.Context, info *entity.UserTwoFactor, code string,
+	allowRecoveryCode bool) (ok bool, err error) {
+	if step, ok := twofactor.ValidateCode(info.Secret, code, time.Now(), info.LastUsedStep); ok {
+		return ts.twoFactorRepo.UpdateLastUsedStep(ctx, info.UserID, step)
+	}
+	if !allowRecoveryCode {
+		return false, nil
+	}
+	hashes := parseRecoveryCodes(info.RecoveryCodes)
+	index := twofactor.MatchRecoveryCode(hashes, code)
+	if index < 0 {
+		return false, nil
+	}
+	hashes = append(hashes[:index], hashes[index+1:]...)
+	return ts.twoFactorRepo.UpdateRecoveryCodes(ctx, info.UserID, info.RecoveryCodes, formatRecoveryCodes(hashes))
+}
+
+func (ts *TwoFactorService) getEnabled(ctx context.Context, userID string) (info *entity.UserTwoFactor, err error) {
+	info, exist, err := ts.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || !info.Enabled {
+		return nil, errors.BadRequest(reason.TwoFactorNotEnabled)
+	}
+	return info, nil
+}
+
+func (ts *TwoFactorService) isRequired(ctx context.Context, userID string) (required bool, err error) {
+	roleID, err := ts.userRoleService.GetUserRole(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return ts.isRoleRequired(ctx, roleID)
+}
+
+// isRoleRequired check whether the role must enroll two-factor authentication by its powers, the role with
+// admin access follows the admin requirement, the other roles with moderator powers follow the moderator one
+func (ts *TwoFactorService) isRoleRequired(ctx context.Context, roleID int) (required bool, err error) {
+	siteLogin, err := ts.siteInfoCommonService.GetSiteLogin(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !siteLogin.RequireTwoFactorAdmin && !siteLogin.RequireTwoFactorModerator {
+		return false, nil
+	}
+	powers, err := ts.rolePowerService.GetRoleAllPowerTypes(ctx, roleID)
+	if err != nil {
+		return false, err
+	}
+	if powers[permission.AdminAccess] {
+		return siteLogin.RequireTwoFactorAdmin, nil
+	}
+	for _, power := range moderatorPowers {
+		if powers[power] {
+			return siteLogin.RequireTwoFactorModerator, nil
+		}
+	}
+	return false, nil
+}
+
+func parseRecoveryCodes(recoveryCodes string) (hashes []string) {
+	if len(recoveryCodes) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(recoveryCodes), &hashes); err != nil {
+		log.Error(err)
+	}
+	return hashes
+}
+
+func formatRecoveryCodes(hashes []string) string {
+	if hashes == nil {
+		hashes = make([]string, 0)
+	}
+	data, _ := json.Marshal(hashes)
+	return string(data)
+}
@@ -0,0 +1,79 @@
+package two_factor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/permission"
+	"github.com/answerdev/answer/internal/service/role"
+	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSiteInfoRepo struct {
+	siteinfo_common.SiteInfoRepo
+	content string
+}
+
+func (f *fakeSiteInfoRepo) GetByType(ctx context.Context, siteType string) (*entity.SiteInfo, bool, error) {
+	return &entity.SiteInfo{Type: siteType, Content: f.content}, true, nil
+}
+
+type fakeRolePowerRelRepo struct {
+	role.RolePowerRelRepo
+	rels []*entity.RolePowerRel
+}
+
+func (f *fakeRolePowerRelRepo) GetRolePowerRelList(ctx context.Context, roleID int) ([]*entity.RolePowerRel, error) {
+	rels := make([]*entity.RolePowerRel, 0)
+	for _, rel := range f.rels {
+		if rel.RoleID == roleID {
+			rels = append(rels, rel)
+		}
+	}
+	return rels, nil
+}
+
+func TestRecoveryCodesFormat(t *testing.T) {
+	assert.Equal(t, "[]", formatRecoveryCodes(nil))
+	assert.Nil(t, parseRecoveryCodes(""))
+
+	hashes := []string{"a", "b"}
+	assert.Equal(t, hashes, parseRecoveryCodes(formatRecoveryCodes(hashes)))
+	// the codes are compared when they are used, so the format must be stable
+	assert.Equal(t, `["a","b"]`, formatRecoveryCodes(hashes))
+}
+
+func TestTwoFactorService_isRoleRequired(t *testing.T) {
+	siteInfoRepo := &fakeSiteInfoRepo{content: `{"require_two_factor_moderator":true}`}
+	rolePowerRelRepo := &fakeRolePowerRelRepo{rels: []*entity.RolePowerRel{
+		{RoleID: 2, PowerType: permission.AdminAccess},
+		{RoleID: 2, PowerType: permission.QuestionDelete},
+		{RoleID: 3, PowerType: permission.QuestionDelete},
+		// the custom role which moderates the posts of a tag
+		{RoleID: 4, PowerType: permission.AnswerAudit, TagID: 10030000000000001},
+		{RoleID: 5, PowerType: permission.QuestionAdd},
+	}}
+	ts := &TwoFactorService{
+		rolePowerService:      role.NewRolePowerRelService(rolePowerRelRepo, nil, nil, nil, nil, nil),
+		siteInfoCommonService: siteinfo_common.NewSiteInfoCommonService(siteInfoRepo),
+	}
+
+	required := func(roleID int) bool {
+		ok, err := ts.isRoleRequired(context.TODO(), roleID)
+		assert.NoError(t, err)
+		return ok
+	}
+	// the admin only follows the admin requirement
+	assert.False(t, required(2))
+	assert.True(t, required(3))
+	assert.True(t, required(4))
+	assert.False(t, required(5))
+	assert.False(t, required(1))
+
+	siteInfoRepo.content = `{"require_two_factor_admin":true}`
+	assert.True(t, required(2))
+	assert.False(t, required(3))
+	assert.False(t, required(4))
+}
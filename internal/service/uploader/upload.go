@@ -2,17 +2,19 @@ package uploader
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
-	"os"
 	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/answerdev/answer/internal/base/handler"
 	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/base/storage"
 	"github.com/answerdev/answer/internal/service/service_config"
 	"github.com/answerdev/answer/internal/service/siteinfo_common"
 	"github.com/answerdev/answer/pkg/dir"
@@ -51,6 +53,7 @@ var (
 type UploaderService struct {
 	serviceConfig   *service_config.ServiceConfig
 	siteInfoService *siteinfo_common.SiteInfoCommonService
+	storage         storage.Storage
 }
 
 // NewUploaderService new upload service
@@ -62,9 +65,14 @@ func NewUploaderService(serviceConfig *service_config.ServiceConfig,
 			panic(err)
 		}
 	}
+	fileStorage, err := storage.NewStorage(serviceConfig.Storage, serviceConfig.UploadPath)
+	if err != nil {
+		panic(err)
+	}
 	return &UploaderService{
 		serviceConfig:   serviceConfig,
 		siteInfoService: siteInfoService,
+		storage:         fileStorage,
 	}
 }
 
@@ -88,57 +96,65 @@ func (us *UploaderService) UploadAvatarFile(ctx *gin.Context) (url string, err e
 	return us.uploadFile(ctx, file, avatarFilePath)
 }
 
-func (us *UploaderService) AvatarThumbFile(ctx *gin.Context, uploadPath, fileName string, size int) (
-	avatarfile []byte, err error) {
+// GetAvatarFile get the avatar file, the thumbnail is generated and saved to storage if size is not zero.
+// If the file can be fetched from storage directly, the redirect url is returned instead of the file content.
+func (us *UploaderService) GetAvatarFile(ctx context.Context, fileName string, size int) (
+	avatarFile []byte, redirectURL string, err error) {
+	fileKey := path.Join(avatarSubPath, fileName)
+	if size > 0 {
+		fileKey, err = us.avatarThumb(ctx, fileName, size)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if redirectURL = us.storage.PublicURL(fileKey); len(redirectURL) > 0 {
+		return nil, redirectURL, nil
+	}
+	avatarFile, err = us.storage.Get(ctx, fileKey)
+	if err != nil {
+		return nil, "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	return avatarFile, "", nil
+}
+
+// avatarThumb generate the thumbnail of avatar if not exist, returns the key of thumbnail
+func (us *UploaderService) avatarThumb(ctx context.Context, fileName string, size int) (thumbKey string, err error) {
 	if size > 1024 {
 		size = 1024
 	}
 	thumbFileName := fmt.Sprintf("%d_%d@%s", size, size, fileName)
-	thumbfilePath := fmt.Sprintf("%s/%s/%s", uploadPath, avatarThumbSubPath, thumbFileName)
-	avatarfile, err = os.ReadFile(thumbfilePath)
-	if err == nil {
-		return avatarfile, nil
-	}
-	filePath := fmt.Sprintf("%s/avatar/%s", uploadPath, fileName)
-	avatarfile, err = os.ReadFile(filePath)
+	thumbKey = path.Join(avatarThumbSubPath, thumbFileName)
+	exist, err := us.storage.Exist(ctx, thumbKey)
 	if err != nil {
-		return avatarfile, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
 	}
-	reader := bytes.NewReader(avatarfile)
-	img, err := imaging.Decode(reader)
-	if err != nil {
-		return avatarfile, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	if exist {
+		return thumbKey, nil
 	}
-	new_image := imaging.Fill(img, size, size, imaging.Center, imaging.Linear)
-	var buf bytes.Buffer
-	fileSuffix := path.Ext(fileName)
-
-	_, ok := FormatExts[fileSuffix]
 
-	if !ok {
-		return avatarfile, fmt.Errorf("img extension not exist")
+	fileSuffix := path.Ext(fileName)
+	if _, ok := FormatExts[fileSuffix]; !ok {
+		return "", fmt.Errorf("img extension not exist")
 	}
-	err = imaging.Encode(&buf, new_image, FormatExts[fileSuffix])
+	avatarFile, err := us.storage.Get(ctx, path.Join(avatarSubPath, fileName))
 	if err != nil {
-		return avatarfile, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
 	}
-	thumbReader := bytes.NewReader(buf.Bytes())
-	err = dir.CreateDirIfNotExist(path.Join(us.serviceConfig.UploadPath, avatarThumbSubPath))
+	img, err := imaging.Decode(bytes.NewReader(avatarFile))
 	if err != nil {
-		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
 	}
-	avatarFilePath := path.Join(avatarThumbSubPath, thumbFileName)
-	savefilePath := path.Join(us.serviceConfig.UploadPath, avatarFilePath)
-	out, err := os.Create(savefilePath)
+	newImage := imaging.Fill(img, size, size, imaging.Center, imaging.Linear)
+	var buf bytes.Buffer
+	err = imaging.Encode(&buf, newImage, FormatExts[fileSuffix])
 	if err != nil {
-		return avatarfile, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
 	}
-	defer out.Close()
-	_, err = io.Copy(out, thumbReader)
+	err = us.storage.Put(ctx, thumbKey, buf.Bytes(), mime.TypeByExtension(fileSuffix))
 	if err != nil {
-		return avatarfile, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
 	}
-	return buf.Bytes(), nil
+	return thumbKey, nil
 }
 
 func (us *UploaderService) UploadPostFile(ctx *gin.Context) (
@@ -188,10 +204,26 @@ func (us *UploaderService) uploadFile(ctx *gin.Context, file *multipart.FileHead
 	if err != nil {
 		return "", err
 	}
-	filePath := path.Join(us.serviceConfig.UploadPath, fileSubPath)
-	if err := ctx.SaveUploadedFile(file, filePath); err != nil {
+	src, err := file.Open()
+	if err != nil {
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	defer src.Close()
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	err = us.storage.Put(ctx, fileSubPath, data, mime.TypeByExtension(path.Ext(fileSubPath)))
+	if err != nil {
 		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
 	}
+
+	// avatar is always served by answer, so that the thumbnail can be generated by size
+	if !strings.HasPrefix(fileSubPath, avatarSubPath+"/") {
+		if publicURL := us.storage.PublicURL(fileSubPath); len(publicURL) > 0 {
+			return publicURL, nil
+		}
+	}
 	url = fmt.Sprintf("%s/uploads/%s", siteGeneral.SiteUrl, fileSubPath)
 	return url, nil
 }
@@ -8,10 +8,12 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/answerdev/answer/internal/base/constant"
 	"github.com/answerdev/answer/internal/base/pager"
 	"github.com/answerdev/answer/internal/base/reason"
 	"github.com/answerdev/answer/internal/entity"
 	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
 	"github.com/answerdev/answer/internal/service/auth"
 	"github.com/answerdev/answer/internal/service/role"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
@@ -38,6 +40,7 @@ type UserAdminService struct {
 	userRoleRelService *role.UserRoleRelService
 	authService        *auth.AuthService
 	userCommonService  *usercommon.UserCommon
+	auditLogService    *audit_log.AuditLogService
 }
 
 // NewUserAdminService new user admin service
@@ -46,12 +49,14 @@ func NewUserAdminService(
 	userRoleRelService *role.UserRoleRelService,
 	authService *auth.AuthService,
 	userCommonService *usercommon.UserCommon,
+	auditLogService *audit_log.AuditLogService,
 ) *UserAdminService {
 	return &UserAdminService{
 		userRepo:           userRepo,
 		userRoleRelService: userRoleRelService,
 		authService:        authService,
 		userCommonService:  userCommonService,
+		auditLogService:    auditLogService,
 	}
 }
 
@@ -68,6 +73,7 @@ func (us *UserAdminService) UpdateUserStatus(ctx context.Context, req *schema.Up
 	if userInfo.Status == entity.UserStatusDeleted {
 		return nil
 	}
+	before := &schema.AuditLogStatusSnapshot{Status: userStatusStr(userInfo)}
 
 	if req.IsInactive() {
 		userInfo.MailStatus = entity.EmailStatusToBeVerified
@@ -83,7 +89,19 @@ func (us *UserAdminService) UpdateUserStatus(ctx context.Context, req *schema.Up
 		userInfo.Status = entity.UserStatusAvailable
 		userInfo.MailStatus = entity.EmailStatusAvailable
 	}
-	return us.userRepo.UpdateUserStatus(ctx, userInfo.ID, userInfo.Status, userInfo.MailStatus, userInfo.EMail)
+	err = us.userRepo.UpdateUserStatus(ctx, userInfo.ID, userInfo.Status, userInfo.MailStatus, userInfo.EMail)
+	if err != nil {
+		return err
+	}
+	us.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionUserStatus,
+		ObjectType: constant.UserObjectType,
+		ObjectID:   userInfo.ID,
+		Before:     before,
+		After:      &schema.AuditLogStatusSnapshot{Status: userStatusStr(userInfo)},
+		Reason:     req.Reason,
+	})
+	return nil
 }
 
 // UpdateUserRole update user role
@@ -93,10 +111,23 @@ func (us *UserAdminService) UpdateUserRole(ctx context.Context, req *schema.Upda
 		return errors.BadRequest(reason.UserCannotUpdateYourRole)
 	}
 
+	oldRoleID, err := us.userRoleRelService.GetUserRole(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
 	err = us.userRoleRelService.SaveUserRole(ctx, req.UserID, req.RoleID)
 	if err != nil {
 		return err
 	}
+	us.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		UserID:     req.LoginUserID,
+		Action:     entity.AuditActionUserRole,
+		ObjectType: constant.UserObjectType,
+		ObjectID:   req.UserID,
+		Before:     &schema.AuditLogRoleSnapshot{RoleID: oldRoleID},
+		After:      &schema.AuditLogRoleSnapshot{RoleID: req.RoleID},
+		Reason:     req.Reason,
+	})
 
 	us.authService.RemoveAllUserTokens(ctx, req.UserID)
 	return
@@ -229,16 +260,11 @@ func (us *UserAdminService) GetUserPage(ctx context.Context, req *schema.GetUser
 			DisplayName: u.DisplayName,
 			Avatar:      avatar,
 		}
+		t.Status = userStatusStr(u)
 		if u.Status == entity.UserStatusDeleted {
-			t.Status = schema.UserDeleted
 			t.DeletedAt = u.DeletedAt.Unix()
 		} else if u.Status == entity.UserStatusSuspended {
-			t.Status = schema.UserSuspended
 			t.SuspendedAt = u.SuspendedAt.Unix()
-		} else if u.MailStatus == entity.EmailStatusToBeVerified {
-			t.Status = schema.UserInactive
-		} else {
-			t.Status = schema.UserNormal
 		}
 		resp = append(resp, t)
 	}
@@ -267,3 +293,17 @@ func (us *UserAdminService) setUserRoleInfo(ctx context.Context, resp []*schema.
 		u.RoleName = r.Name
 	}
 }
+
+// userStatusStr the status of the user shown to the admin
+func userStatusStr(u *entity.User) string {
+	switch {
+	case u.Status == entity.UserStatusDeleted:
+		return schema.UserDeleted
+	case u.Status == entity.UserStatusSuspended:
+		return schema.UserSuspended
+	case u.MailStatus == entity.EmailStatusToBeVerified:
+		return schema.UserInactive
+	default:
+		return schema.UserNormal
+	}
+}
@@ -0,0 +1,209 @@
+package user_external_login
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/pkg/oauth"
+)
+
+const (
+	googleIssuer        = "https://accounts.google.com"
+	githubAuthURL       = "https://github.com/login/oauth/authorize"
+	githubTokenURL      = "https://github.com/login/oauth/access_token"
+	githubUserURL       = "https://api.github.com/user"
+	githubUserEmailsURL = "https://api.github.com/user/emails"
+)
+
+// ExternalUserInfo the user info returned by the provider
+type ExternalUserInfo struct {
+	ExternalID    string
+	Name          string
+	Username      string
+	Email         string
+	EmailVerified bool
+	// MetaInfo the raw user info of provider
+	MetaInfo string
+}
+
+// Connector login by the OAuth2 authorization code flow of provider
+type Connector interface {
+	// AuthCodeURL the url to redirect the user to the provider
+	AuthCodeURL(ctx context.Context, state string) (string, error)
+	// GetUserInfo exchange the code and get the user info from the provider
+	GetUserInfo(ctx context.Context, code string) (*ExternalUserInfo, error)
+}
+
+// newConnector new connector of the config, the redirectURL is the callback url of answer
+func newConnector(client *oauth.Client, conf *schema.LoginConnector, redirectURL string) (Connector, error) {
+	oauthConf := &oauth.Config{
+		ClientID:     conf.ClientID,
+		ClientSecret: conf.ClientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       conf.Scopes,
+	}
+	switch conf.Type {
+	case schema.LoginConnectorTypeGithub:
+		oauthConf.AuthURL = githubAuthURL
+		oauthConf.TokenURL = githubTokenURL
+		if len(oauthConf.Scopes) == 0 {
+			oauthConf.Scopes = []string{"read:user", "user:email"}
+		}
+		return &githubConnector{
+			client:       client,
+			conf:         oauthConf,
+			userURL:      githubUserURL,
+			userEmailURL: githubUserEmailsURL,
+		}, nil
+	case schema.LoginConnectorTypeGoogle:
+		return newOIDCConnector(client, oauthConf, googleIssuer), nil
+	case schema.LoginConnectorTypeOIDC:
+		if len(conf.Issuer) == 0 {
+			return nil, fmt.Errorf("issuer of connector %s is required", conf.Name)
+		}
+		return newOIDCConnector(client, oauthConf, conf.Issuer), nil
+	default:
+		return nil, fmt.Errorf("connector type %s is not supported", conf.Type)
+	}
+}
+
+// oidcConnector generic OpenID Connect provider, the endpoints are discovered from the issuer
+type oidcConnector struct {
+	client *oauth.Client
+	conf   *oauth.Config
+	issuer string
+}
+
+func newOIDCConnector(client *oauth.Client, conf *oauth.Config, issuer string) *oidcConnector {
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &oidcConnector{client: client, conf: conf, issuer: issuer}
+}
+
+func (c *oidcConnector) discover(ctx context.Context) (userInfoURL string, err error) {
+	metadata, err := c.client.Discover(ctx, c.issuer)
+	if err != nil {
+		return "", err
+	}
+	if len(metadata.UserinfoEndpoint) == 0 {
+		return "", fmt.Errorf("issuer %s has no userinfo endpoint", c.issuer)
+	}
+	c.conf.AuthURL = metadata.AuthorizationEndpoint
+	c.conf.TokenURL = metadata.TokenEndpoint
+	return metadata.UserinfoEndpoint, nil
+}
+
+func (c *oidcConnector) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	if _, err := c.discover(ctx); err != nil {
+		return "", err
+	}
+	return c.conf.AuthCodeURL(state), nil
+}
+
+// GetUserInfo the user info is fetched from the userinfo endpoint with the access token which is
+// issued by the token endpoint directly, so the id token is not needed to be verified.
+func (c *oidcConnector) GetUserInfo(ctx context.Context, code string) (*ExternalUserInfo, error) {
+	userInfoURL, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := c.client.Exchange(ctx, c.conf, code)
+	if err != nil {
+		return nil, err
+	}
+	raw := json.RawMessage{}
+	if err = c.client.GetJSON(ctx, userInfoURL, token.AccessToken, &raw); err != nil {
+		return nil, err
+	}
+	claims := &struct {
+		Subject           string      `json:"sub"`
+		Name              string      `json:"name"`
+		PreferredUsername string      `json:"preferred_username"`
+		Email             string      `json:"email"`
+		EmailVerified     interface{} `json:"email_verified"`
+	}{}
+	if err = json.Unmarshal(raw, claims); err != nil {
+		return nil, err
+	}
+	if len(claims.Subject) == 0 {
+		return nil, fmt.Errorf("userinfo of issuer %s has no subject", c.issuer)
+	}
+	// some providers return the email_verified claim as string
+	emailVerified := false
+	switch v := claims.EmailVerified.(type) {
+	case bool:
+		emailVerified = v
+	case string:
+		emailVerified, _ = strconv.ParseBool(v)
+	}
+	return &ExternalUserInfo{
+		ExternalID:    claims.Subject,
+		Name:          claims.Name,
+		Username:      claims.PreferredUsername,
+		Email:         claims.Email,
+		EmailVerified: emailVerified,
+		MetaInfo:      string(raw),
+	}, nil
+}
+
+// githubConnector GitHub OAuth app, GitHub does not support OpenID Connect for users
+type githubConnector struct {
+	client       *oauth.Client
+	conf         *oauth.Config
+	userURL      string
+	userEmailURL string
+}
+
+func (c *githubConnector) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	return c.conf.AuthCodeURL(state), nil
+}
+
+func (c *githubConnector) GetUserInfo(ctx context.Context, code string) (*ExternalUserInfo, error) {
+	token, err := c.client.Exchange(ctx, c.conf, code)
+	if err != nil {
+		return nil, err
+	}
+	raw := json.RawMessage{}
+	if err = c.client.GetJSON(ctx, c.userURL, token.AccessToken, &raw); err != nil {
+		return nil, err
+	}
+	user := &struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}{}
+	if err = json.Unmarshal(raw, user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("github user has no id")
+	}
+	info := &ExternalUserInfo{
+		ExternalID: strconv.FormatInt(user.ID, 10),
+		Name:       user.Name,
+		Username:   user.Login,
+		MetaInfo:   string(raw),
+	}
+
+	// the email of user profile may be empty or unverified, use the verified primary email instead
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err = c.client.GetJSON(ctx, c.userEmailURL, token.AccessToken, &emails); err != nil {
+		return nil, err
+	}
+	for _, email := range emails {
+		if email.Primary && email.Verified {
+			info.Email = email.Email
+			info.EmailVerified = true
+			break
+		}
+	}
+	return info, nil
+}
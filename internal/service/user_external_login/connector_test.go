@@ -0,0 +1,109 @@
+package user_external_login
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/pkg/oauth"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProviderServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		assert.NoError(t, json.NewEncoder(w).Encode(v))
+	}
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 server.URL,
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"userinfo_endpoint":      server.URL + "/userinfo",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"access_token": "at"})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"sub":                "abc",
+			"name":               "Jane Doe",
+			"preferred_username": "jane",
+			"email":              "jane@example.com",
+			"email_verified":     "true",
+		})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"id": 42, "login": "octocat", "name": "The Octocat"})
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []map[string]interface{}{
+			{"email": "other@example.com", "primary": false, "verified": true},
+			{"email": "octocat@example.com", "primary": true, "verified": true},
+		})
+	})
+	return server
+}
+
+func TestOIDCConnector(t *testing.T) {
+	server := newProviderServer(t)
+	defer server.Close()
+
+	connector, err := newConnector(oauth.NewClient(), &schema.LoginConnector{
+		Name:     "corp",
+		Type:     schema.LoginConnectorTypeOIDC,
+		ClientID: "client",
+		Issuer:   server.URL,
+	}, "https://answer.example.com/answer/api/v1/connector/redirect/corp")
+	assert.NoError(t, err)
+
+	authURL, err := connector.AuthCodeURL(context.TODO(), "state")
+	assert.NoError(t, err)
+	u, err := url.Parse(authURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "/authorize", u.Path)
+	assert.Equal(t, "openid email profile", u.Query().Get("scope"))
+
+	info, err := connector.GetUserInfo(context.TODO(), "code")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", info.ExternalID)
+	assert.Equal(t, "jane", info.Username)
+	assert.Equal(t, "jane@example.com", info.Email)
+	assert.True(t, info.EmailVerified)
+}
+
+func TestGithubConnector(t *testing.T) {
+	server := newProviderServer(t)
+	defer server.Close()
+
+	connector, err := newConnector(oauth.NewClient(), &schema.LoginConnector{
+		Name:     "github",
+		Type:     schema.LoginConnectorTypeGithub,
+		ClientID: "client",
+	}, "https://answer.example.com/answer/api/v1/connector/redirect/github")
+	assert.NoError(t, err)
+	githubConn := connector.(*githubConnector)
+	githubConn.conf.TokenURL = server.URL + "/token"
+	githubConn.userURL = server.URL + "/user"
+	githubConn.userEmailURL = server.URL + "/user/emails"
+
+	info, err := connector.GetUserInfo(context.TODO(), "code")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", info.ExternalID)
+	assert.Equal(t, "octocat", info.Username)
+	assert.Equal(t, "octocat@example.com", info.Email)
+	assert.True(t, info.EmailVerified)
+}
+
+func TestNewConnector_Invalid(t *testing.T) {
+	_, err := newConnector(oauth.NewClient(), &schema.LoginConnector{Type: schema.LoginConnectorTypeOIDC}, "")
+	assert.Error(t, err)
+	_, err = newConnector(oauth.NewClient(), &schema.LoginConnector{Type: "saml"}, "")
+	assert.Error(t, err)
+}
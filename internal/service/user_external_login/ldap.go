@@ -0,0 +1,114 @@
+package user_external_login
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/pkg/ldapauth"
+	myErrors "github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// ldapProvider the provider of the users verified by the directory
+const ldapProvider = schema.LoginConnectorTypeLDAP
+
+// ldapAuthenticate verify the password by the directory, it is replaced in tests
+var ldapAuthenticate = ldapauth.Authenticate
+
+// LDAPLogin verify the password of email login by the directory if the LDAP login is enabled,
+// the user is provisioned if not exist. The handled is false if the LDAP login is disabled
+// or the directory can not verify the user, then the local password should be checked instead.
+func (us *UserExternalLoginService) LDAPLogin(ctx context.Context, email, password string) (
+	userInfo *entity.User, handled bool, err error) {
+	conf, err := us.siteInfoCommonService.GetSiteLoginLDAP(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if !conf.Enabled {
+		return nil, false, nil
+	}
+
+	entry, err := ldapAuthenticate(newLDAPConfig(conf), email, password)
+	if err != nil {
+		if errors.Is(err, ldapauth.ErrInvalidCredentials) {
+			return nil, true, myErrors.BadRequest(reason.EmailOrPasswordWrong)
+		}
+		// the local users such as the admin can still login if they are not in the directory
+		// or the directory is unavailable
+		if !errors.Is(err, ldapauth.ErrUserNotFound) {
+			log.Errorf("ldap authenticate failed: %v", err)
+		}
+		return nil, false, nil
+	}
+
+	userInfo, err = us.getOrCreateUser(ctx, ldapProvider, ldapExternalUserInfo(conf, entry, email))
+	if err != nil {
+		return nil, true, err
+	}
+	if len(conf.GroupAttribute) > 0 {
+		groups := ldapGroups(entry.GetAttributeValues(conf.GroupAttribute))
+		roleID, matched := groupsRole(conf.AdminGroups, conf.ModeratorGroups, groups)
+		if err = us.syncMappedRole(ctx, ldapProvider, entry.DN, userInfo.ID, roleID, matched); err != nil {
+			return nil, true, err
+		}
+	}
+	return userInfo, true, nil
+}
+
+func newLDAPConfig(conf *schema.SiteLoginLDAPResp) *ldapauth.Config {
+	attributes := make([]string, 0)
+	for _, attr := range []string{conf.UsernameAttribute, conf.DisplayNameAttribute, conf.EmailAttribute, conf.GroupAttribute} {
+		if len(attr) > 0 {
+			attributes = append(attributes, attr)
+		}
+	}
+	return &ldapauth.Config{
+		URL:                conf.URL,
+		StartTLS:           conf.StartTLS,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+		BindDN:             conf.BindDN,
+		BindPassword:       conf.BindPassword,
+		BaseDN:             conf.BaseDN,
+		UserFilter:         conf.UserFilter,
+		Attributes:         attributes,
+	}
+}
+
+// ldapExternalUserInfo map the attributes of entry to user info, the DN identifies the linked user
+// and the email in the directory is trusted
+func ldapExternalUserInfo(conf *schema.SiteLoginLDAPResp, entry *ldapauth.Entry, loginEmail string) *ExternalUserInfo {
+	metaInfo, _ := json.Marshal(entry.Attributes)
+	info := &ExternalUserInfo{
+		ExternalID:    entry.DN,
+		Email:         loginEmail,
+		EmailVerified: true,
+		MetaInfo:      string(metaInfo),
+	}
+	if len(conf.UsernameAttribute) > 0 {
+		info.Username = entry.GetAttribute(conf.UsernameAttribute)
+	}
+	if len(conf.DisplayNameAttribute) > 0 {
+		info.Name = entry.GetAttribute(conf.DisplayNameAttribute)
+	}
+	if len(conf.EmailAttribute) > 0 {
+		if email := entry.GetAttribute(conf.EmailAttribute); len(email) > 0 {
+			info.Email = email
+		}
+	}
+	return info
+}
+
+// ldapGroups the groups of directory are usually DN, both the DN and the name of group can be mapped to role
+func ldapGroups(values []string) (groups []string) {
+	for _, value := range values {
+		groups = append(groups, value)
+		if name := ldapauth.GroupName(value); name != value {
+			groups = append(groups, name)
+		}
+	}
+	return groups
+}
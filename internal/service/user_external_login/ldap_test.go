@@ -0,0 +1,74 @@
+package user_external_login
+
+import (
+	"testing"
+
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/role"
+	"github.com/answerdev/answer/pkg/ldapauth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLDAPExternalUserInfo(t *testing.T) {
+	entry := &ldapauth.Entry{
+		DN: "uid=jane,ou=people,dc=example,dc=org",
+		Attributes: map[string][]string{
+			"uid":  {"jane"},
+			"cn":   {"Jane Doe"},
+			"mail": {"jane.doe@example.org"},
+		},
+	}
+	conf := &schema.SiteLoginLDAPResp{
+		UsernameAttribute:    "uid",
+		DisplayNameAttribute: "cn",
+		EmailAttribute:       "mail",
+	}
+	info := ldapExternalUserInfo(conf, entry, "jane@example.org")
+	assert.Equal(t, entry.DN, info.ExternalID)
+	assert.Equal(t, "jane", info.Username)
+	assert.Equal(t, "Jane Doe", info.Name)
+	assert.Equal(t, "jane.doe@example.org", info.Email)
+	assert.True(t, info.EmailVerified)
+
+	// the login email is used if the entry has no email
+	conf.EmailAttribute = "email"
+	info = ldapExternalUserInfo(conf, entry, "jane@example.org")
+	assert.Equal(t, "jane@example.org", info.Email)
+}
+
+func TestGroupsRole(t *testing.T) {
+	adminGroups := []string{"answer-admins"}
+	moderatorGroups := []string{"cn=answer-moderators,ou=groups,dc=example,dc=org"}
+
+	groups := ldapGroups([]string{"cn=Answer-Admins,ou=groups,dc=example,dc=org"})
+	roleID, matched := groupsRole(adminGroups, moderatorGroups, groups)
+	assert.True(t, matched)
+	assert.Equal(t, role.RoleAdminID, roleID)
+
+	groups = ldapGroups([]string{"cn=staff,ou=groups,dc=example,dc=org", "CN=answer-moderators,OU=groups,DC=example,DC=org"})
+	roleID, matched = groupsRole(adminGroups, moderatorGroups, groups)
+	assert.True(t, matched)
+	assert.Equal(t, role.RoleModeratorID, roleID)
+
+	roleID, matched = groupsRole(adminGroups, moderatorGroups, []string{"staff", "answer-admins"})
+	assert.True(t, matched)
+	assert.Equal(t, role.RoleAdminID, roleID)
+
+	// the role is not changed if the user is in none of the mapped groups
+	_, matched = groupsRole(adminGroups, moderatorGroups, []string{"staff"})
+	assert.False(t, matched)
+	_, matched = groupsRole(adminGroups, moderatorGroups, nil)
+	assert.False(t, matched)
+}
+
+func TestNewLDAPConfig(t *testing.T) {
+	conf := newLDAPConfig(&schema.SiteLoginLDAPResp{
+		URL:            "ldap://localhost:389",
+		BaseDN:         "dc=example,dc=org",
+		UserFilter:     "(mail={login})",
+		EmailAttribute: "mail",
+		GroupAttribute: "memberOf",
+	})
+	assert.Equal(t, []string{"mail", "memberOf"}, conf.Attributes)
+	assert.Equal(t, "(mail={login})", conf.UserFilter)
+}
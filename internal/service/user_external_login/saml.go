@@ -0,0 +1,164 @@
+package user_external_login
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"strings"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/pkg/saml"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// samlProvider the provider of the users logged in by SAML
+const samlProvider = schema.LoginConnectorTypeSAML
+
+// SAMLMetadata get the metadata xml of SP which is imported by the IdP
+func (us *UserExternalLoginService) SAMLMetadata(ctx context.Context) (metadata []byte, err error) {
+	sp, _, err := us.getServiceProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err = sp.Metadata()
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	return metadata, nil
+}
+
+// SAMLLoginRedirectURL get the url of IdP with the signed AuthnRequest to redirect the user to,
+// the request id should be saved in the browser which started the login
+func (us *UserExternalLoginService) SAMLLoginRedirectURL(ctx context.Context) (
+	redirectURL, requestID string, err error) {
+	sp, _, err := us.getServiceProvider(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	redirectURL, requestID, err = sp.AuthnRequestURL("")
+	if err != nil {
+		return "", "", errors.BadRequest(reason.ExternalLoginFailed).WithError(err).WithStack()
+	}
+	// the request id is checked by the InResponseTo of response, so it works like the state of OAuth
+	if err = us.userExternalLoginRepo.SetCacheState(ctx, requestID, samlProvider); err != nil {
+		return "", "", err
+	}
+	return redirectURL, requestID, nil
+}
+
+// SAMLLogin the assertion consumer service, login the linked user or register a new user,
+// the role of user is updated by the groups of IdP if the group attribute is configured.
+func (us *UserExternalLoginService) SAMLLogin(ctx context.Context, req *schema.SAMLACSReq) (
+	resp *schema.GetUserResp, err error) {
+	if len(req.SAMLResponse) == 0 {
+		return nil, errors.BadRequest(reason.ExternalLoginFailed)
+	}
+	requestID, err := saml.ResponseRequestID(req.SAMLResponse)
+	if err != nil {
+		return nil, errors.BadRequest(reason.ExternalLoginFailed).WithError(err).WithStack()
+	}
+	// the response must be posted by the browser which started the login, otherwise the attacker can
+	// post the attacker's own response to login the victim to the attacker's account
+	if subtle.ConstantTimeCompare([]byte(requestID), []byte(req.RequestIDCookie)) != 1 {
+		return nil, errors.BadRequest(reason.ExternalLoginFailed).WithMsg("invalid saml request id")
+	}
+	sp, conf, err := us.getServiceProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := us.userExternalLoginRepo.GetCacheState(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if provider != samlProvider {
+		return nil, errors.BadRequest(reason.ExternalLoginFailed).WithMsg("invalid saml request id")
+	}
+	assertion, err := sp.ParseResponse(req.SAMLResponse, requestID)
+	if err != nil {
+		return nil, errors.BadRequest(reason.ExternalLoginFailed).WithError(err).WithStack()
+	}
+
+	externalUserInfo := samlExternalUserInfo(conf, assertion)
+	userInfo, err := us.getOrCreateUser(ctx, samlProvider, externalUserInfo)
+	if err != nil {
+		return nil, err
+	}
+	if len(conf.GroupAttribute) > 0 {
+		groups := assertion.Attributes[conf.GroupAttribute]
+		roleID, matched := groupsRole(conf.AdminGroups, conf.ModeratorGroups, groups)
+		if err = us.syncMappedRole(ctx, samlProvider, externalUserInfo.ExternalID, userInfo.ID, roleID, matched); err != nil {
+			return nil, err
+		}
+	}
+	if err = us.userRepo.UpdateLastLoginDate(ctx, userInfo.ID); err != nil {
+		log.Error(err)
+	}
+	return us.login(ctx, userInfo)
+}
+
+// getServiceProvider get the SP built from the enabled SAML config
+func (us *UserExternalLoginService) getServiceProvider(ctx context.Context) (
+	sp *saml.ServiceProvider, conf *schema.SiteLoginSAMLResp, err error) {
+	conf, err = us.siteInfoCommonService.GetSiteLoginSAML(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !conf.Enabled {
+		return nil, nil, errors.NotFound(reason.ObjectNotFound)
+	}
+	siteGeneral, err := us.siteInfoCommonService.GetSiteGeneral(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sp, err = newServiceProvider(conf, strings.TrimSuffix(siteGeneral.SiteUrl, "/"))
+	if err != nil {
+		return nil, nil, errors.BadRequest(reason.LoginSAMLInvalid).WithError(err).WithStack()
+	}
+	return sp, conf, nil
+}
+
+func newServiceProvider(conf *schema.SiteLoginSAMLResp, siteURL string) (sp *saml.ServiceProvider, err error) {
+	sp = &saml.ServiceProvider{
+		EntityID:    siteURL + "/answer/api/v1/saml/metadata",
+		ACSURL:      siteURL + "/answer/api/v1/saml/acs",
+		IDPEntityID: conf.IDPEntityID,
+		IDPSSOURL:   conf.IDPSSOURL,
+	}
+	if sp.Key, err = saml.ParsePrivateKeyPEM(conf.SPPrivateKey); err != nil {
+		return nil, err
+	}
+	if sp.Certificate, err = saml.ParseCertificatePEM(conf.SPCertificate); err != nil {
+		return nil, err
+	}
+	idpCertificate, err := saml.ParseCertificatePEM(conf.IDPCertificate)
+	if err != nil {
+		return nil, err
+	}
+	sp.IDPCertificates = []*x509.Certificate{idpCertificate}
+	return sp, nil
+}
+
+// samlExternalUserInfo map the attributes of assertion to user info, the email asserted by the IdP is trusted
+func samlExternalUserInfo(conf *schema.SiteLoginSAMLResp, assertion *saml.Assertion) *ExternalUserInfo {
+	metaInfo, _ := json.Marshal(assertion.Attributes)
+	info := &ExternalUserInfo{
+		ExternalID:    assertion.NameID,
+		EmailVerified: true,
+		MetaInfo:      string(metaInfo),
+	}
+	if len(conf.UsernameAttribute) > 0 {
+		info.Username = assertion.GetAttribute(conf.UsernameAttribute)
+	}
+	if len(conf.DisplayNameAttribute) > 0 {
+		info.Name = assertion.GetAttribute(conf.DisplayNameAttribute)
+	}
+	if len(conf.EmailAttribute) > 0 {
+		info.Email = assertion.GetAttribute(conf.EmailAttribute)
+	} else if strings.Contains(assertion.NameID, "@") {
+		info.Email = assertion.NameID
+	}
+	return info
+}
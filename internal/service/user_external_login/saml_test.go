@@ -0,0 +1,69 @@
+package user_external_login
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/pkg/saml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSAMLExternalUserInfo(t *testing.T) {
+	assertion := &saml.Assertion{
+		NameID: "jane@example.com",
+		Attributes: map[string][]string{
+			"uid":         {"jane"},
+			"displayName": {"Jane Doe"},
+			"mail":        {"jane.doe@example.com"},
+		},
+	}
+	info := samlExternalUserInfo(&schema.SiteLoginSAMLResp{
+		UsernameAttribute:    "uid",
+		DisplayNameAttribute: "displayName",
+		EmailAttribute:       "mail",
+	}, assertion)
+	assert.Equal(t, "jane@example.com", info.ExternalID)
+	assert.Equal(t, "jane", info.Username)
+	assert.Equal(t, "Jane Doe", info.Name)
+	assert.Equal(t, "jane.doe@example.com", info.Email)
+	assert.True(t, info.EmailVerified)
+
+	// the NameID is used as email if the email attribute is not configured
+	info = samlExternalUserInfo(&schema.SiteLoginSAMLResp{}, assertion)
+	assert.Equal(t, "jane@example.com", info.Email)
+	assert.Empty(t, info.Username)
+}
+
+func TestNewServiceProvider(t *testing.T) {
+	certPEM, keyPEM, err := saml.GenerateKeyPair("answer", 0)
+	assert.NoError(t, err)
+	sp, err := newServiceProvider(&schema.SiteLoginSAMLResp{
+		IDPEntityID:    "https://idp.example.com",
+		IDPSSOURL:      "https://idp.example.com/sso",
+		IDPCertificate: certPEM,
+		SPCertificate:  certPEM,
+		SPPrivateKey:   keyPEM,
+	}, "https://answer.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://answer.example.com/answer/api/v1/saml/acs", sp.ACSURL)
+	assert.Len(t, sp.IDPCertificates, 1)
+
+	_, err = newServiceProvider(&schema.SiteLoginSAMLResp{SPCertificate: certPEM, SPPrivateKey: keyPEM},
+		"https://answer.example.com")
+	assert.Error(t, err)
+}
+
+func TestSAMLLogin_RequestIDCookie(t *testing.T) {
+	us := &UserExternalLoginService{}
+	samlResponse := base64.StdEncoding.EncodeToString([]byte(
+		`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" ID="_r" InResponseTo="_request"/>`))
+
+	// the response posted to the browser which did not start the login is rejected
+	for _, cookie := range []string{"", "_other"} {
+		_, err := us.SAMLLogin(context.TODO(), &schema.SAMLACSReq{SAMLResponse: samlResponse, RequestIDCookie: cookie})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid saml request id")
+	}
+}
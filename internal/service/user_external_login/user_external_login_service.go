@@ -0,0 +1,479 @@
+package user_external_login
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/activity"
+	"github.com/answerdev/answer/internal/service/auth"
+	"github.com/answerdev/answer/internal/service/role"
+	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	"github.com/answerdev/answer/internal/service/two_factor"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/pkg/oauth"
+	"github.com/google/uuid"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const displayNameMaxLength = 30
+
+// UserExternalLoginRepo user external login repository
+type UserExternalLoginRepo interface {
+	AddUserExternalLogin(ctx context.Context, user *entity.ExternalLoginUserInfo) (err error)
+	UpdateInfo(ctx context.Context, userInfo *entity.ExternalLoginUserInfo) (err error)
+	UpdateMappedRole(ctx context.Context, id, mappedRoleID int) (err error)
+	GetByExternalID(ctx context.Context, provider, externalID string) (
+		userInfo *entity.ExternalLoginUserInfo, exist bool, err error)
+	SetCacheState(ctx context.Context, state, provider string) (err error)
+	// GetCacheState get the provider of state, the state can only be used once
+	GetCacheState(ctx context.Context, state string) (provider string, err error)
+	SetCacheLoginCode(ctx context.Context, code, accessToken string) (err error)
+	// GetCacheLoginCode get the access token of the login code, the code can only be used once
+	GetCacheLoginCode(ctx context.Context, code string) (accessToken string, err error)
+}
+
+// UserExternalLoginService user external login service
+type UserExternalLoginService struct {
+	userRepo              usercommon.UserRepo
+	userExternalLoginRepo UserExternalLoginRepo
+	userCommonService     *usercommon.UserCommon
+	userActivity          activity.UserActiveActivityRepo
+	authService           *auth.AuthService
+	userRoleService       *role.UserRoleRelService
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
+	twoFactorService      *two_factor.TwoFactorService
+	oauthClient           *oauth.Client
+}
+
+// NewUserExternalLoginService new user external login service
+func NewUserExternalLoginService(
+	userRepo usercommon.UserRepo,
+	userExternalLoginRepo UserExternalLoginRepo,
+	userCommonService *usercommon.UserCommon,
+	userActivity activity.UserActiveActivityRepo,
+	authService *auth.AuthService,
+	userRoleService *role.UserRoleRelService,
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+	twoFactorService *two_factor.TwoFactorService,
+) *UserExternalLoginService {
+	return &UserExternalLoginService{
+		userRepo:              userRepo,
+		userExternalLoginRepo: userExternalLoginRepo,
+		userCommonService:     userCommonService,
+		userActivity:          userActivity,
+		authService:           authService,
+		userRoleService:       userRoleService,
+		siteInfoCommonService: siteInfoCommonService,
+		twoFactorService:      twoFactorService,
+		oauthClient:           oauth.NewClient(),
+	}
+}
+
+// GetExternalLoginConnectors get the enabled connectors which are shown on the login page
+func (us *UserExternalLoginService) GetExternalLoginConnectors(ctx context.Context) (
+	resp []*schema.ExternalLoginConnectorResp, err error) {
+	resp = make([]*schema.ExternalLoginConnectorResp, 0)
+	connectorConfig, err := us.siteInfoCommonService.GetSiteLoginConnector(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, connector := range connectorConfig.Connectors {
+		if !connector.Enabled {
+			continue
+		}
+		resp = append(resp, &schema.ExternalLoginConnectorResp{
+			Name:        connector.Name,
+			DisplayName: connector.DisplayName,
+			Type:        connector.Type,
+			Link:        "/answer/api/v1/connector/login/" + connector.Name,
+		})
+	}
+
+	samlConfig, err := us.siteInfoCommonService.GetSiteLoginSAML(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if samlConfig.Enabled {
+		resp = append(resp, &schema.ExternalLoginConnectorResp{
+			Name:        samlProvider,
+			DisplayName: samlConfig.DisplayName,
+			Type:        schema.LoginConnectorTypeSAML,
+			Link:        "/answer/api/v1/saml/login",
+		})
+	}
+	return resp, nil
+}
+
+// ExternalLoginRedirectURL get the url of provider to redirect the user to,
+// the state should be saved in the browser and sent back with the callback
+func (us *UserExternalLoginService) ExternalLoginRedirectURL(ctx context.Context, name string) (
+	redirectURL, state string, err error) {
+	connector, err := us.getConnector(ctx, name)
+	if err != nil {
+		return "", "", err
+	}
+	state = strings.ReplaceAll(uuid.NewString(), "-", "")
+	if err = us.userExternalLoginRepo.SetCacheState(ctx, state, name); err != nil {
+		return "", "", err
+	}
+	redirectURL, err = connector.AuthCodeURL(ctx, state)
+	if err != nil {
+		return "", "", errors.BadRequest(reason.ExternalLoginFailed).WithError(err).WithStack()
+	}
+	return redirectURL, state, nil
+}
+
+// ExternalLogin the callback of provider, login the linked user or register a new user
+func (us *UserExternalLoginService) ExternalLogin(ctx context.Context, name string,
+	req *schema.ExternalLoginCallbackReq) (resp *schema.GetUserResp, err error) {
+	if len(req.Error) > 0 || len(req.Code) == 0 || len(req.State) == 0 {
+		return nil, errors.BadRequest(reason.ExternalLoginFailed).WithMsg(req.Error)
+	}
+	// the callback must be finished by the browser which started the login, otherwise the attacker can
+	// login the victim to the attacker's account
+	if subtle.ConstantTimeCompare([]byte(req.State), []byte(req.StateCookie)) != 1 {
+		return nil, errors.BadRequest(reason.ExternalLoginFailed).WithMsg("invalid state")
+	}
+	provider, err := us.userExternalLoginRepo.GetCacheState(ctx, req.State)
+	if err != nil {
+		return nil, err
+	}
+	if provider != name {
+		return nil, errors.BadRequest(reason.ExternalLoginFailed).WithMsg("invalid state")
+	}
+	connector, err := us.getConnector(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	externalUserInfo, err := connector.GetUserInfo(ctx, req.Code)
+	if err != nil {
+		return nil, errors.BadRequest(reason.ExternalLoginFailed).WithError(err).WithStack()
+	}
+
+	userInfo, err := us.getOrCreateUser(ctx, name, externalUserInfo)
+	if err != nil {
+		return nil, err
+	}
+	if err = us.userRepo.UpdateLastLoginDate(ctx, userInfo.ID); err != nil {
+		log.Error(err)
+	}
+	return us.login(ctx, userInfo)
+}
+
+// getOrCreateUser get the linked user, or link the user which has the same verified email,
+// otherwise register a new user.
+func (us *UserExternalLoginService) getOrCreateUser(ctx context.Context, provider string,
+	externalUserInfo *ExternalUserInfo) (userInfo *entity.User, err error) {
+	externalLoginInfo, exist, err := us.userExternalLoginRepo.GetByExternalID(ctx, provider, externalUserInfo.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		userInfo, exist, err = us.userRepo.GetByUserID(ctx, externalLoginInfo.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if !exist || userInfo.Status == entity.UserStatusDeleted {
+			return nil, errors.BadRequest(reason.UserNotFound)
+		}
+		externalLoginInfo.Name = externalUserInfo.Name
+		externalLoginInfo.Email = externalUserInfo.Email
+		externalLoginInfo.MetaInfo = externalUserInfo.MetaInfo
+		if err = us.userExternalLoginRepo.UpdateInfo(ctx, externalLoginInfo); err != nil {
+			log.Error(err)
+		}
+		return userInfo, nil
+	}
+
+	if len(externalUserInfo.Email) == 0 {
+		return nil, errors.BadRequest(reason.ExternalLoginFailed).WithMsg("email is required")
+	}
+	userInfo, exist, err = us.userRepo.GetByEmail(ctx, externalUserInfo.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		// only the email verified by provider proves the ownership of the existing account
+		if !externalUserInfo.EmailVerified {
+			return nil, errors.BadRequest(reason.EmailDuplicate)
+		}
+		if userInfo.Status == entity.UserStatusDeleted {
+			return nil, errors.BadRequest(reason.UserNotFound)
+		}
+		if err = us.verifyExistingUserEmail(ctx, userInfo); err != nil {
+			return nil, err
+		}
+	} else {
+		userInfo, err = us.registerUser(ctx, externalUserInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = us.userExternalLoginRepo.AddUserExternalLogin(ctx, &entity.ExternalLoginUserInfo{
+		UserID:     userInfo.ID,
+		Provider:   provider,
+		ExternalID: externalUserInfo.ExternalID,
+		Name:       externalUserInfo.Name,
+		Email:      externalUserInfo.Email,
+		MetaInfo:   externalUserInfo.MetaInfo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userInfo, nil
+}
+
+// verifyExistingUserEmail the existing account with unverified email may be registered by someone else
+// who does not own the email, so the password is cleared before it is linked to the owner.
+func (us *UserExternalLoginService) verifyExistingUserEmail(ctx context.Context, userInfo *entity.User) (err error) {
+	if userInfo.MailStatus == entity.EmailStatusAvailable {
+		return nil
+	}
+	if err = us.userRepo.UpdatePass(ctx, userInfo.ID, ""); err != nil {
+		return err
+	}
+	userInfo.MailStatus = entity.EmailStatusAvailable
+	if err = us.userRepo.UpdateEmailStatus(ctx, userInfo.ID, userInfo.MailStatus); err != nil {
+		return err
+	}
+	us.authService.RemoveAllUserTokens(ctx, userInfo.ID)
+	if err = us.userActivity.UserActive(ctx, userInfo.ID); err != nil {
+		log.Error(err)
+	}
+	return nil
+}
+
+func (us *UserExternalLoginService) registerUser(ctx context.Context, externalUserInfo *ExternalUserInfo) (
+	userInfo *entity.User, err error) {
+	siteLogin, err := us.siteInfoCommonService.GetSiteLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !siteLogin.AllowNewRegistrations {
+		return nil, errors.BadRequest(reason.NotAllowedRegistration)
+	}
+
+	userInfo = &entity.User{}
+	userInfo.EMail = externalUserInfo.Email
+	userInfo.Username, err = us.makeUsername(ctx, externalUserInfo)
+	if err != nil {
+		return nil, err
+	}
+	userInfo.DisplayName = externalUserInfo.Name
+	if len(userInfo.DisplayName) == 0 {
+		userInfo.DisplayName = userInfo.Username
+	}
+	if runes := []rune(userInfo.DisplayName); len(runes) > displayNameMaxLength {
+		userInfo.DisplayName = string(runes[:displayNameMaxLength])
+	}
+	userInfo.MailStatus = entity.EmailStatusToBeVerified
+	if externalUserInfo.EmailVerified {
+		userInfo.MailStatus = entity.EmailStatusAvailable
+	}
+	userInfo.Status = entity.UserStatusAvailable
+	userInfo.LastLoginDate = time.Now()
+	if err = us.userRepo.AddUser(ctx, userInfo); err != nil {
+		return nil, err
+	}
+	if userInfo.MailStatus == entity.EmailStatusAvailable {
+		if err = us.userActivity.UserActive(ctx, userInfo.ID); err != nil {
+			log.Error(err)
+		}
+	}
+	return userInfo, nil
+}
+
+// makeUsername try the username, display name and email of provider in order, use a random one if all invalid
+func (us *UserExternalLoginService) makeUsername(ctx context.Context, externalUserInfo *ExternalUserInfo) (
+	username string, err error) {
+	candidates := []string{
+		externalUserInfo.Username,
+		externalUserInfo.Name,
+		strings.Split(externalUserInfo.Email, "@")[0],
+	}
+	for _, candidate := range candidates {
+		if len(candidate) == 0 {
+			continue
+		}
+		username, err = us.userCommonService.MakeUsername(ctx, candidate)
+		if err == nil {
+			return username, nil
+		}
+	}
+	randomBytes := make([]byte, 4)
+	_, _ = rand.Read(randomBytes)
+	return us.userCommonService.MakeUsername(ctx, fmt.Sprintf("user_%s", hex.EncodeToString(randomBytes)))
+}
+
+// NewLoginCode make the one-time code of the access token, the landing page exchanges it for the access token
+func (us *UserExternalLoginService) NewLoginCode(ctx context.Context, accessToken string) (code string, err error) {
+	randomBytes := make([]byte, 32)
+	if _, err = rand.Read(randomBytes); err != nil {
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	code = hex.EncodeToString(randomBytes)
+	if err = us.userExternalLoginRepo.SetCacheLoginCode(ctx, code, accessToken); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// ExchangeLoginCode get the access token of the one-time login code
+func (us *UserExternalLoginService) ExchangeLoginCode(ctx context.Context, req *schema.ExternalLoginTokenReq) (
+	resp *schema.ExternalLoginTokenResp, err error) {
+	accessToken, err := us.userExternalLoginRepo.GetCacheLoginCode(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.ExternalLoginTokenResp{AccessToken: accessToken}, nil
+}
+
+func (us *UserExternalLoginService) login(ctx context.Context, userInfo *entity.User) (
+	resp *schema.GetUserResp, err error) {
+	roleID, err := us.userRoleService.GetUserRole(ctx, userInfo.ID)
+	if err != nil {
+		log.Error(err)
+	}
+	// the access token is issued after the two-factor code is verified
+	resp, err = us.twoFactorService.CheckLogin(ctx, userInfo.ID, roleID)
+	if err != nil || resp != nil {
+		return resp, err
+	}
+
+	resp = &schema.GetUserResp{}
+	resp.GetFromUserEntity(userInfo)
+	userCacheInfo := &entity.UserCacheInfo{
+		UserID:      userInfo.ID,
+		EmailStatus: userInfo.MailStatus,
+		UserStatus:  userInfo.Status,
+		IsAdmin:     roleID == role.RoleAdminID,
+	}
+	resp.AccessToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo)
+	if err != nil {
+		return nil, err
+	}
+	resp.IsAdmin = userCacheInfo.IsAdmin
+	if resp.IsAdmin {
+		err = us.authService.SetAdminUserCacheInfo(ctx, resp.AccessToken, &entity.UserCacheInfo{UserID: userInfo.ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// syncUserRole set the role mapped from the groups of provider, the sessions are removed if it is changed.
+// The custom role given by the admin is kept unless the user is mapped to the admin role.
+func (us *UserExternalLoginService) syncUserRole(ctx context.Context, userID string, roleID int) (err error) {
+	oldRoleID, err := us.userRoleService.GetUserRole(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if oldRoleID == roleID {
+		return nil
+	}
+	if !role.IsBuiltInRole(oldRoleID) && roleID != role.RoleAdminID {
+		return nil
+	}
+	if err = us.userRoleService.SaveUserRole(ctx, userID, roleID); err != nil {
+		return err
+	}
+	us.authService.RemoveAllUserTokens(ctx, userID)
+	return nil
+}
+
+// syncMappedRole sync the role of user by the groups of provider. The role granted by the group mapping
+// is recorded, so that it is taken back when the user is in none of the mapped groups, the role assigned
+// manually is never taken back.
+func (us *UserExternalLoginService) syncMappedRole(ctx context.Context, provider, externalID, userID string,
+	roleID int, matched bool) (err error) {
+	externalLoginInfo, exist, err := us.userExternalLoginRepo.GetByExternalID(ctx, provider, externalID)
+	if err != nil || !exist {
+		return err
+	}
+	currentRoleID, err := us.userRoleService.GetUserRole(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	mappedRoleID := 0
+	if matched {
+		if currentRoleID != roleID {
+			if err = us.syncUserRole(ctx, userID, roleID); err != nil {
+				return err
+			}
+			if currentRoleID, err = us.userRoleService.GetUserRole(ctx, userID); err != nil {
+				return err
+			}
+			if currentRoleID == roleID {
+				mappedRoleID = roleID
+			}
+		} else if externalLoginInfo.MappedRoleID != 0 {
+			mappedRoleID = roleID
+		}
+	} else if externalLoginInfo.MappedRoleID != 0 && currentRoleID == externalLoginInfo.MappedRoleID {
+		if err = us.userRoleService.SaveUserRole(ctx, userID, role.RoleUserID); err != nil {
+			return err
+		}
+		us.authService.RemoveAllUserTokens(ctx, userID)
+	}
+
+	if mappedRoleID != externalLoginInfo.MappedRoleID {
+		return us.userExternalLoginRepo.UpdateMappedRole(ctx, externalLoginInfo.ID, mappedRoleID)
+	}
+	return nil
+}
+
+// groupsRole get the role of groups, the admin groups take precedence over the moderator groups,
+// the group names are case-insensitive. It is not matched if the user is in none of the mapped groups.
+func groupsRole(adminGroups, moderatorGroups, groups []string) (roleID int, matched bool) {
+	groupSet := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		groupSet[strings.ToLower(group)] = true
+	}
+	for _, group := range adminGroups {
+		if groupSet[strings.ToLower(group)] {
+			return role.RoleAdminID, true
+		}
+	}
+	for _, group := range moderatorGroups {
+		if groupSet[strings.ToLower(group)] {
+			return role.RoleModeratorID, true
+		}
+	}
+	return role.RoleUserID, false
+}
+
+// getConnector get the enabled connector by name
+func (us *UserExternalLoginService) getConnector(ctx context.Context, name string) (connector Connector, err error) {
+	connectorConfig, err := us.siteInfoCommonService.GetSiteLoginConnector(ctx)
+	if err != nil {
+		return nil, err
+	}
+	siteGeneral, err := us.siteInfoCommonService.GetSiteGeneral(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, conf := range connectorConfig.Connectors {
+		if conf.Name != name || !conf.Enabled {
+			continue
+		}
+		redirectURL := strings.TrimSuffix(siteGeneral.SiteUrl, "/") + "/answer/api/v1/connector/redirect/" + name
+		connector, err = newConnector(us.oauthClient, conf, redirectURL)
+		if err != nil {
+			return nil, errors.BadRequest(reason.LoginConnectorInvalid).WithError(err).WithStack()
+		}
+		return connector, nil
+	}
+	return nil, errors.NotFound(reason.ObjectNotFound)
+}
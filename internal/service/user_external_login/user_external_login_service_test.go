@@ -0,0 +1,131 @@
+package user_external_login
+
+import (
+	"context"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/service/auth"
+	"github.com/answerdev/answer/internal/service/role"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRoleRepo struct {
+	role.RoleRepo
+}
+
+func (f *fakeRoleRepo) GetRole(ctx context.Context, roleID int) (*entity.Role, bool, error) {
+	return &entity.Role{ID: roleID}, true, nil
+}
+
+type fakeUserRoleRelRepo struct {
+	role.UserRoleRelRepo
+	roles map[string]int
+}
+
+func (f *fakeUserRoleRelRepo) GetUserRoleRel(ctx context.Context, userID string) (*entity.UserRoleRel, bool, error) {
+	roleID, ok := f.roles[userID]
+	if !ok {
+		return nil, false, nil
+	}
+	return &entity.UserRoleRel{UserID: userID, RoleID: roleID}, true, nil
+}
+
+func (f *fakeUserRoleRelRepo) SaveUserRoleRel(ctx context.Context, userID string, roleID int) error {
+	f.roles[userID] = roleID
+	return nil
+}
+
+type fakeAuthRepo struct {
+	auth.AuthRepo
+	removed []string
+}
+
+func (f *fakeAuthRepo) RemoveAllUserTokens(ctx context.Context, userID string) {
+	f.removed = append(f.removed, userID)
+}
+
+func TestSyncUserRole(t *testing.T) {
+	const customRoleID = 4
+	userRoleRelRepo := &fakeUserRoleRelRepo{roles: map[string]int{
+		"1": role.RoleAdminID,
+		"2": customRoleID,
+		"3": customRoleID,
+	}}
+	authRepo := &fakeAuthRepo{}
+	us := &UserExternalLoginService{
+		authService: auth.NewAuthService(authRepo),
+		userRoleService: role.NewUserRoleRelService(userRoleRelRepo,
+			role.NewRoleService(&fakeRoleRepo{}, userRoleRelRepo, nil, nil)),
+	}
+
+	// the admin is moved to the moderator role and logged out
+	assert.NoError(t, us.syncUserRole(context.TODO(), "1", role.RoleModeratorID))
+	assert.Equal(t, role.RoleModeratorID, userRoleRelRepo.roles["1"])
+	assert.Equal(t, []string{"1"}, authRepo.removed)
+
+	// the custom role is never downgraded
+	assert.NoError(t, us.syncUserRole(context.TODO(), "2", role.RoleModeratorID))
+	assert.Equal(t, customRoleID, userRoleRelRepo.roles["2"])
+
+	// the custom role is upgraded to the admin role
+	assert.NoError(t, us.syncUserRole(context.TODO(), "3", role.RoleAdminID))
+	assert.Equal(t, role.RoleAdminID, userRoleRelRepo.roles["3"])
+	assert.Equal(t, []string{"1", "3"}, authRepo.removed)
+}
+
+type fakeUserExternalLoginRepo struct {
+	UserExternalLoginRepo
+	links map[string]*entity.ExternalLoginUserInfo
+}
+
+func (f *fakeUserExternalLoginRepo) GetByExternalID(ctx context.Context, provider, externalID string) (
+	*entity.ExternalLoginUserInfo, bool, error) {
+	link, ok := f.links[externalID]
+	return link, ok, nil
+}
+
+func (f *fakeUserExternalLoginRepo) UpdateMappedRole(ctx context.Context, id, mappedRoleID int) error {
+	for _, link := range f.links {
+		if link.ID == id {
+			link.MappedRoleID = mappedRoleID
+		}
+	}
+	return nil
+}
+
+func TestSyncMappedRole(t *testing.T) {
+	userRoleRelRepo := &fakeUserRoleRelRepo{roles: map[string]int{
+		"1": role.RoleUserID,
+		"2": role.RoleModeratorID,
+	}}
+	externalLoginRepo := &fakeUserExternalLoginRepo{links: map[string]*entity.ExternalLoginUserInfo{
+		"uid=jane": {ID: 1, UserID: "1"},
+		"uid=john": {ID: 2, UserID: "2"},
+	}}
+	authRepo := &fakeAuthRepo{}
+	us := &UserExternalLoginService{
+		userExternalLoginRepo: externalLoginRepo,
+		authService:           auth.NewAuthService(authRepo),
+		userRoleService: role.NewUserRoleRelService(userRoleRelRepo,
+			role.NewRoleService(&fakeRoleRepo{}, userRoleRelRepo, nil, nil)),
+	}
+	ctx := context.TODO()
+
+	// the role granted by the admin group is recorded
+	assert.NoError(t, us.syncMappedRole(ctx, ldapProvider, "uid=jane", "1", role.RoleAdminID, true))
+	assert.Equal(t, role.RoleAdminID, userRoleRelRepo.roles["1"])
+	assert.Equal(t, role.RoleAdminID, externalLoginRepo.links["uid=jane"].MappedRoleID)
+
+	// the user left all the mapped groups is demoted and logged out
+	assert.NoError(t, us.syncMappedRole(ctx, ldapProvider, "uid=jane", "1", role.RoleUserID, false))
+	assert.Equal(t, role.RoleUserID, userRoleRelRepo.roles["1"])
+	assert.Equal(t, 0, externalLoginRepo.links["uid=jane"].MappedRoleID)
+	assert.Equal(t, []string{"1", "1"}, authRepo.removed)
+
+	// the role assigned manually is kept
+	assert.NoError(t, us.syncMappedRole(ctx, ldapProvider, "uid=john", "2", role.RoleModeratorID, true))
+	assert.Equal(t, 0, externalLoginRepo.links["uid=john"].MappedRoleID)
+	assert.NoError(t, us.syncMappedRole(ctx, ldapProvider, "uid=john", "2", role.RoleUserID, false))
+	assert.Equal(t, role.RoleModeratorID, userRoleRelRepo.roles["2"])
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/answerdev/answer/internal/base/constant"
 	"github.com/answerdev/answer/internal/base/handler"
 	"github.com/answerdev/answer/internal/base/reason"
 	"github.com/answerdev/answer/internal/base/translator"
@@ -15,11 +16,15 @@ import (
 	"github.com/answerdev/answer/internal/service/activity"
 	"github.com/answerdev/answer/internal/service/activity_common"
 	"github.com/answerdev/answer/internal/service/auth"
+	"github.com/answerdev/answer/internal/service/badge"
 	"github.com/answerdev/answer/internal/service/export"
 	"github.com/answerdev/answer/internal/service/role"
 	"github.com/answerdev/answer/internal/service/service_config"
 	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	"github.com/answerdev/answer/internal/service/spam"
+	"github.com/answerdev/answer/internal/service/two_factor"
 	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/internal/service/user_external_login"
 	"github.com/answerdev/answer/pkg/checker"
 	"github.com/google/uuid"
 	"github.com/segmentfault/pacman/errors"
@@ -40,6 +45,11 @@ type UserService struct {
 	authService       *auth.AuthService
 	siteInfoService   *siteinfo_common.SiteInfoCommonService
 	userRoleService   *role.UserRoleRelService
+
+	userExternalLoginService *user_external_login.UserExternalLoginService
+	twoFactorService         *two_factor.TwoFactorService
+	badgeService             *badge.BadgeService
+	spamService              *spam.SpamService
 }
 
 func NewUserService(userRepo usercommon.UserRepo,
@@ -51,6 +61,10 @@ func NewUserService(userRepo usercommon.UserRepo,
 	siteInfoService *siteinfo_common.SiteInfoCommonService,
 	userRoleService *role.UserRoleRelService,
 	userCommonService *usercommon.UserCommon,
+	userExternalLoginService *user_external_login.UserExternalLoginService,
+	twoFactorService *two_factor.TwoFactorService,
+	badgeService *badge.BadgeService,
+	spamService *spam.SpamService,
 ) *UserService {
 	return &UserService{
 		userCommonService: userCommonService,
@@ -62,6 +76,11 @@ func NewUserService(userRepo usercommon.UserRepo,
 		authService:       authService,
 		siteInfoService:   siteInfoService,
 		userRoleService:   userRoleService,
+
+		userExternalLoginService: userExternalLoginService,
+		twoFactorService:         twoFactorService,
+		badgeService:             badgeService,
+		spamService:              spamService,
 	}
 }
 
@@ -82,6 +101,8 @@ func (us *UserService) GetUserInfoByUserID(ctx context.Context, token, userID st
 	resp.GetFromUserEntity(userInfo)
 	resp.AccessToken = token
 	resp.IsAdmin = roleID == role.RoleAdminID
+	// the user info is fetched on every page load, so it is where the visit is recorded
+	us.badgeService.RecordVisit(ctx, userInfo.ID)
 	return resp, nil
 }
 
@@ -99,31 +120,90 @@ func (us *UserService) GetOtherUserInfoByUsername(ctx context.Context, username
 	resp.Has = true
 	resp.Info = &schema.GetOtherUserInfoByUsernameResp{}
 	resp.Info.GetFromUserEntity(userInfo)
+	resp.Info.Badges, err = us.badgeService.GetUserBadgeSummary(ctx, userInfo.ID)
+	if err != nil {
+		return nil, err
+	}
 	return resp, nil
 }
 
 // EmailLogin email login
 func (us *UserService) EmailLogin(ctx context.Context, req *schema.UserEmailLogin) (resp *schema.GetUserResp, err error) {
-	userInfo, exist, err := us.userRepo.GetByEmail(ctx, req.Email)
+	// the password is verified by the directory if the LDAP login is enabled
+	userInfo, verifiedByLDAP, err := us.userExternalLoginService.LDAPLogin(ctx, req.Email, req.Pass)
 	if err != nil {
 		return nil, err
 	}
-	if !exist || userInfo.Status == entity.UserStatusDeleted {
-		return nil, errors.BadRequest(reason.EmailOrPasswordWrong)
-	}
-	if !us.verifyPassword(ctx, req.Pass, userInfo.Pass) {
-		return nil, errors.BadRequest(reason.EmailOrPasswordWrong)
+	var siteLogin *schema.SiteLoginResp
+	if !verifiedByLDAP {
+		siteLogin, err = us.siteInfoService.GetSiteLogin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var exist bool
+		userInfo, exist, err = us.userRepo.GetByEmail(ctx, req.Email)
+		if err != nil {
+			return nil, err
+		}
+		if !exist || userInfo.Status == entity.UserStatusDeleted {
+			return nil, errors.BadRequest(reason.EmailOrPasswordWrong)
+		}
 	}
 
-	err = us.userRepo.UpdateLastLoginDate(ctx, userInfo.ID)
+	roleID, err := us.userRoleService.GetUserRole(ctx, userInfo.ID)
 	if err != nil {
-		log.Error("UpdateLastLoginDate", err.Error())
+		log.Error(err)
+	}
+	if !verifiedByLDAP {
+		// the password is always verified and the same error is returned, so that it is not told whether
+		// the email is registered or the user is an admin. Only the admin can login with password if
+		// the password login is disabled, so that the site can be recovered if the connectors are broken.
+		passwordRight := us.verifyPassword(ctx, req.Pass, userInfo.Pass)
+		if !passwordRight || (siteLogin.DisallowPasswordLogin && roleID != role.RoleAdminID) {
+			return nil, errors.BadRequest(reason.EmailOrPasswordWrong)
+		}
 	}
 
+	// the access token is issued after the two-factor code is verified
+	resp, err = us.twoFactorService.CheckLogin(ctx, userInfo.ID, roleID)
+	if err != nil || resp != nil {
+		return resp, err
+	}
+	return us.login(ctx, userInfo, roleID)
+}
+
+// TwoFactorLogin finish the login after the two-factor code is verified
+func (us *UserService) TwoFactorLogin(ctx context.Context, req *schema.TwoFactorLoginReq) (
+	resp *schema.TwoFactorLoginResp, err error) {
+	userID, recoveryCodes, err := us.twoFactorService.VerifyLogin(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || userInfo.Status == entity.UserStatusDeleted {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
 	roleID, err := us.userRoleService.GetUserRole(ctx, userInfo.ID)
 	if err != nil {
 		log.Error(err)
 	}
+	userResp, err := us.login(ctx, userInfo, roleID)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.TwoFactorLoginResp{GetUserResp: userResp, RecoveryCodes: recoveryCodes}, nil
+}
+
+// login issue the access token for the verified user
+func (us *UserService) login(ctx context.Context, userInfo *entity.User, roleID int) (
+	resp *schema.GetUserResp, err error) {
+	err = us.userRepo.UpdateLastLoginDate(ctx, userInfo.ID)
+	if err != nil {
+		log.Error("UpdateLastLoginDate", err.Error())
+	}
 
 	resp = &schema.GetUserResp{}
 	resp.GetFromUserEntity(userInfo)
@@ -316,6 +396,19 @@ func (us *UserService) UserRegisterByEmail(ctx context.Context, registerUserInfo
 		return nil, errFields, errors.BadRequest(reason.EmailDuplicate)
 	}
 
+	// a registration can not be held for review, so only a reject verdict blocks it
+	verdict, err := us.spamService.Check(ctx, &spam.Content{
+		ObjectType: constant.UserObjectType,
+		Title:      registerUserInfo.Name,
+		Email:      registerUserInfo.Email,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if verdict.Rejected() {
+		return nil, nil, errors.BadRequest(reason.SpamContentRejected)
+	}
+
 	userInfo := &entity.User{}
 	userInfo.EMail = registerUserInfo.Email
 	userInfo.DisplayName = registerUserInfo.Name
@@ -451,22 +544,28 @@ func (us *UserService) UserVerifyEmail(ctx context.Context, req *schema.UserVeri
 		log.Error(err)
 	}
 
-	resp = &schema.GetUserResp{}
-	resp.GetFromUserEntity(userInfo)
 	userCacheInfo := &entity.UserCacheInfo{
 		UserID:      userInfo.ID,
 		EmailStatus: userInfo.MailStatus,
 		UserStatus:  userInfo.Status,
 		IsAdmin:     roleID == role.RoleAdminID,
 	}
-	resp.AccessToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo)
-	if err != nil {
-		return nil, err
-	}
 	// User verified email will update user email status. So user status cache should be updated.
 	if err = us.authService.SetUserStatus(ctx, userCacheInfo); err != nil {
 		return nil, err
 	}
+	// the verification link logs the user in, so the two-factor code is verified as the login does
+	resp, err = us.twoFactorService.CheckLogin(ctx, userInfo.ID, roleID)
+	if err != nil || resp != nil {
+		return resp, err
+	}
+
+	resp = &schema.GetUserResp{}
+	resp.GetFromUserEntity(userInfo)
+	resp.AccessToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo)
+	if err != nil {
+		return nil, err
+	}
 	resp.IsAdmin = userCacheInfo.IsAdmin
 	if resp.IsAdmin {
 		err = us.authService.SetAdminUserCacheInfo(ctx, resp.AccessToken, &entity.UserCacheInfo{UserID: userInfo.ID})
@@ -6,9 +6,13 @@ import (
 	"github.com/answerdev/answer/internal/base/pager"
 	"github.com/answerdev/answer/internal/entity"
 	"github.com/answerdev/answer/internal/service/activity_type"
+	"github.com/answerdev/answer/internal/service/badge_queue"
 	"github.com/answerdev/answer/internal/service/comment_common"
 	"github.com/answerdev/answer/internal/service/config"
 	"github.com/answerdev/answer/internal/service/object_info"
+	"github.com/answerdev/answer/internal/service/post_lock"
+	"github.com/answerdev/answer/internal/service/search_queue"
+	"github.com/answerdev/answer/internal/service/stream"
 	"github.com/answerdev/answer/pkg/obj"
 	"github.com/segmentfault/pacman/log"
 
@@ -32,13 +36,15 @@ type VoteRepo interface {
 
 // VoteService user service
 type VoteService struct {
-	voteRepo          VoteRepo
-	UniqueIDRepo      unique.UniqueIDRepo
-	configRepo        config.ConfigRepo
-	questionRepo      questioncommon.QuestionRepo
-	answerRepo        answercommon.AnswerRepo
-	commentCommonRepo comment_common.CommentCommonRepo
-	objectService     *object_info.ObjService
+	voteRepo           VoteRepo
+	UniqueIDRepo       unique.UniqueIDRepo
+	configRepo         config.ConfigRepo
+	questionRepo       questioncommon.QuestionRepo
+	answerRepo         answercommon.AnswerRepo
+	commentCommonRepo  comment_common.CommentCommonRepo
+	objectService      *object_info.ObjService
+	postLockService    *post_lock.PostLockService
+	searchQueueService *search_queue.SearchQueueService
 }
 
 func NewVoteService(
@@ -49,15 +55,19 @@ func NewVoteService(
 	answerRepo answercommon.AnswerRepo,
 	commentCommonRepo comment_common.CommentCommonRepo,
 	objectService *object_info.ObjService,
+	postLockService *post_lock.PostLockService,
+	searchQueueService *search_queue.SearchQueueService,
 ) *VoteService {
 	return &VoteService{
-		voteRepo:          VoteRepo,
-		UniqueIDRepo:      uniqueIDRepo,
-		configRepo:        configRepo,
-		questionRepo:      questionRepo,
-		answerRepo:        answerRepo,
-		commentCommonRepo: commentCommonRepo,
-		objectService:     objectService,
+		voteRepo:           VoteRepo,
+		UniqueIDRepo:       uniqueIDRepo,
+		configRepo:         configRepo,
+		questionRepo:       questionRepo,
+		answerRepo:         answerRepo,
+		commentCommonRepo:  commentCommonRepo,
+		objectService:      objectService,
+		postLockService:    postLockService,
+		searchQueueService: searchQueueService,
 	}
 }
 
@@ -71,6 +81,9 @@ func (as *VoteService) VoteUp(ctx context.Context, dto *schema.VoteDTO) (voteRes
 	if err != nil {
 		return
 	}
+	if err = as.postLockService.CheckPostLocked(ctx, dto.ObjectID, dto.UserID); err != nil {
+		return
+	}
 
 	// check user is voting self or not
 	if objectUserID == dto.UserID {
@@ -79,10 +92,21 @@ func (as *VoteService) VoteUp(ctx context.Context, dto *schema.VoteDTO) (voteRes
 	}
 
 	if dto.IsCancel {
-		return as.voteRepo.VoteUpCancel(ctx, dto.ObjectID, dto.UserID, objectUserID)
+		voteResp, err = as.voteRepo.VoteUpCancel(ctx, dto.ObjectID, dto.UserID, objectUserID)
 	} else {
-		return as.voteRepo.VoteUp(ctx, dto.ObjectID, dto.UserID, objectUserID)
+		voteResp, err = as.voteRepo.VoteUp(ctx, dto.ObjectID, dto.UserID, objectUserID)
+	}
+	if err == nil {
+		as.publishVote(ctx, dto.ObjectID, voteResp)
+		if !dto.IsCancel {
+			badge_queue.AddEvent(&schema.BadgeEventMsg{
+				Event:    schema.BadgeEventVotedUp,
+				UserID:   objectUserID,
+				ObjectID: dto.ObjectID,
+			})
+		}
 	}
+	return
 }
 
 // VoteDown vote down
@@ -95,6 +119,9 @@ func (as *VoteService) VoteDown(ctx context.Context, dto *schema.VoteDTO) (voteR
 	if err != nil {
 		return
 	}
+	if err = as.postLockService.CheckPostLocked(ctx, dto.ObjectID, dto.UserID); err != nil {
+		return
+	}
 
 	// check user is voting self or not
 	if objectUserID == dto.UserID {
@@ -103,10 +130,14 @@ func (as *VoteService) VoteDown(ctx context.Context, dto *schema.VoteDTO) (voteR
 	}
 
 	if dto.IsCancel {
-		return as.voteRepo.VoteDownCancel(ctx, dto.ObjectID, dto.UserID, objectUserID)
+		voteResp, err = as.voteRepo.VoteDownCancel(ctx, dto.ObjectID, dto.UserID, objectUserID)
 	} else {
-		return as.voteRepo.VoteDown(ctx, dto.ObjectID, dto.UserID, objectUserID)
+		voteResp, err = as.voteRepo.VoteDown(ctx, dto.ObjectID, dto.UserID, objectUserID)
+	}
+	if err == nil {
+		as.publishVote(ctx, dto.ObjectID, voteResp)
 	}
+	return
 }
 
 func (vs *VoteService) GetObjectUserID(ctx context.Context, objectID string) (userID string, err error) {
@@ -148,6 +179,25 @@ func (vs *VoteService) GetObjectUserID(ctx context.Context, objectID string) (us
 	return
 }
 
+// publishVote push the vote count to the users viewing the question and resync it to the search engine
+func (as *VoteService) publishVote(ctx context.Context, objectID string, voteResp *schema.VoteResp) {
+	as.searchQueueService.AddSearchSync(objectID)
+	objInfo, err := as.objectService.GetInfo(ctx, objectID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if len(objInfo.QuestionID) == 0 {
+		return
+	}
+	stream.Publish(ctx, stream.QuestionKey(objInfo.QuestionID), stream.EventVote, &schema.NotificationStreamVote{
+		ObjectID:  objectID,
+		UpVotes:   voteResp.UpVotes,
+		DownVotes: voteResp.DownVotes,
+		Votes:     voteResp.Votes,
+	})
+}
+
 // ListUserVotes list user's votes
 func (vs *VoteService) ListUserVotes(ctx context.Context, req schema.GetVoteWithPageReq) (model *pager.PageModel, err error) {
 	var (
@@ -0,0 +1,581 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/answerdev/answer/internal/base/constant"
+	"github.com/answerdev/answer/internal/base/pager"
+	"github.com/answerdev/answer/internal/base/reason"
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/answerdev/answer/internal/service/job_queue"
+	"github.com/answerdev/answer/internal/service/object_info"
+	"github.com/answerdev/answer/internal/service/siteinfo_common"
+	tagcommon "github.com/answerdev/answer/internal/service/tag_common"
+	usercommon "github.com/answerdev/answer/internal/service/user_common"
+	"github.com/answerdev/answer/internal/service/webhook_queue"
+	"github.com/goccy/go-json"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// SignatureHeader the header of HMAC-SHA256 signature of request body, the format is sha256=<hex>
+	SignatureHeader = "X-Answer-Signature-256"
+	EventHeader     = "X-Answer-Event"
+	DeliveryHeader  = "X-Answer-Delivery"
+	// maxDeliveryAttempts the delivery is marked as failed after that, it can be redelivered by admin
+	maxDeliveryAttempts = 5
+	deliveryTimeout     = 10 * time.Second
+	// maxResponseBodyLength only the beginning of response body is recorded
+	maxResponseBodyLength = 1024
+)
+
+// WebhookRepo webhook repository
+type WebhookRepo interface {
+	AddWebhook(ctx context.Context, webhook *entity.Webhook) (err error)
+	UpdateWebhook(ctx context.Context, webhook *entity.Webhook) (err error)
+	RemoveWebhook(ctx context.Context, id string) (err error)
+	GetWebhook(ctx context.Context, id string) (webhook *entity.Webhook, exist bool, err error)
+	GetWebhookList(ctx context.Context) (webhooks []*entity.Webhook, err error)
+	GetEnabledWebhooks(ctx context.Context) (webhooks []*entity.Webhook, err error)
+	AddDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (err error)
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (err error)
+	GetDelivery(ctx context.Context, id string) (delivery *entity.WebhookDelivery, exist bool, err error)
+	GetDeliveryPage(ctx context.Context, webhookID string, page, pageSize int) (
+		deliveries []*entity.WebhookDelivery, total int64, err error)
+}
+
+// WebhookService webhook service
+type WebhookService struct {
+	webhookRepo           WebhookRepo
+	objectInfoService     *object_info.ObjService
+	tagCommonService      *tagcommon.TagCommonService
+	tagCommonRepo         tagcommon.TagCommonRepo
+	userRepo              usercommon.UserRepo
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
+	auditLogService       *audit_log.AuditLogService
+	httpClient            *http.Client
+}
+
+// NewWebhookService new webhook service
+func NewWebhookService(
+	webhookRepo WebhookRepo,
+	objectInfoService *object_info.ObjService,
+	tagCommonService *tagcommon.TagCommonService,
+	tagCommonRepo tagcommon.TagCommonRepo,
+	userRepo usercommon.UserRepo,
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+	auditLogService *audit_log.AuditLogService,
+	queue job_queue.Queue,
+) *WebhookService {
+	ws := &WebhookService{
+		webhookRepo:           webhookRepo,
+		objectInfoService:     objectInfoService,
+		tagCommonService:      tagCommonService,
+		tagCommonRepo:         tagCommonRepo,
+		userRepo:              userRepo,
+		siteInfoCommonService: siteInfoCommonService,
+		auditLogService:       auditLogService,
+		httpClient:            newDeliveryClient(),
+	}
+	queue.Subscribe(webhook_queue.Topic, ws.HandleEvent)
+	queue.Subscribe(webhook_queue.DeliveryTopic, ws.HandleDelivery)
+	return ws
+}
+
+// GetWebhookEvents get the events which can trigger webhooks
+func (ws *WebhookService) GetWebhookEvents(ctx context.Context) (resp []string) {
+	resp = make([]string, 0, len(schema.WebhookEvents))
+	for _, event := range schema.WebhookEvents {
+		resp = append(resp, string(event))
+	}
+	return resp
+}
+
+// GetWebhookList get all webhooks
+func (ws *WebhookService) GetWebhookList(ctx context.Context) (resp []*schema.GetWebhookResp, err error) {
+	webhooks, err := ws.webhookRepo.GetWebhookList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.GetWebhookResp, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		resp = append(resp, &schema.GetWebhookResp{
+			ID:         webhook.ID,
+			Name:       webhook.Name,
+			URL:        webhook.URL,
+			SecretHint: secretHint(webhook.Secret),
+			Events:     splitList(webhook.Events),
+			Tags:       splitList(webhook.Tags),
+			Enabled:    webhook.Enabled,
+			CreatedAt:  webhook.CreatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// AddWebhook add webhook, the secret is returned only once
+func (ws *WebhookService) AddWebhook(ctx context.Context, req *schema.AddWebhookReq) (
+	resp *schema.AddWebhookResp, err error) {
+	webhook := &entity.Webhook{}
+	if err = ws.fillWebhook(webhook, req); err != nil {
+		return nil, err
+	}
+	if err = ws.webhookRepo.AddWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+	ws.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionWebhookAdd,
+		ObjectType: entity.AuditObjectTypeWebhook,
+		ObjectID:   webhook.ID,
+		After:      webhookSnapshot(webhook),
+	})
+	return &schema.AddWebhookResp{ID: webhook.ID, Secret: webhook.Secret}, nil
+}
+
+// UpdateWebhook update webhook
+func (ws *WebhookService) UpdateWebhook(ctx context.Context, req *schema.UpdateWebhookReq) (err error) {
+	webhook, exist, err := ws.webhookRepo.GetWebhook(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.WebhookNotFound)
+	}
+	before := webhookSnapshot(webhook)
+	if err = ws.fillWebhook(webhook, &req.AddWebhookReq); err != nil {
+		return err
+	}
+	if err = ws.webhookRepo.UpdateWebhook(ctx, webhook); err != nil {
+		return err
+	}
+	ws.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionWebhookUpdate,
+		ObjectType: entity.AuditObjectTypeWebhook,
+		ObjectID:   webhook.ID,
+		Before:     before,
+		After:      webhookSnapshot(webhook),
+	})
+	return nil
+}
+
+// RemoveWebhook remove webhook and its deliveries
+func (ws *WebhookService) RemoveWebhook(ctx context.Context, req *schema.RemoveWebhookReq) (err error) {
+	webhook, exist, err := ws.webhookRepo.GetWebhook(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.WebhookNotFound)
+	}
+	if err = ws.webhookRepo.RemoveWebhook(ctx, req.ID); err != nil {
+		return err
+	}
+	ws.auditLogService.AddAuditLog(ctx, &schema.AddAuditLogReq{
+		Action:     entity.AuditActionWebhookRemove,
+		ObjectType: entity.AuditObjectTypeWebhook,
+		ObjectID:   webhook.ID,
+		Before:     webhookSnapshot(webhook),
+	})
+	return nil
+}
+
+// webhookSnapshot the webhook in the audit log without the secret
+func webhookSnapshot(webhook *entity.Webhook) *schema.AuditLogWebhookSnapshot {
+	return &schema.AuditLogWebhookSnapshot{
+		Name:    webhook.Name,
+		URL:     webhook.URL,
+		Events:  splitList(webhook.Events),
+		Tags:    splitList(webhook.Tags),
+		Enabled: webhook.Enabled,
+	}
+}
+
+func (ws *WebhookService) fillWebhook(webhook *entity.Webhook, req *schema.AddWebhookReq) (err error) {
+	if !strings.HasPrefix(req.URL, "http://") && !strings.HasPrefix(req.URL, "https://") {
+		return errors.BadRequest(reason.WebhookInvalid)
+	}
+	for _, event := range req.Events {
+		if !isWebhookEvent(event) {
+			return errors.BadRequest(reason.WebhookInvalid)
+		}
+	}
+	// the secret is kept if it is not changed when updating
+	if len(req.Secret) > 0 {
+		webhook.Secret = req.Secret
+	}
+	if len(webhook.Secret) == 0 {
+		webhook.Secret, err = generateSecret()
+		if err != nil {
+			return errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		}
+	}
+	webhook.Name = req.Name
+	webhook.URL = req.URL
+	webhook.Events = strings.Join(req.Events, ",")
+	webhook.Tags = strings.Join(req.Tags, ",")
+	webhook.Enabled = req.Enabled
+	return nil
+}
+
+// GetDeliveryPage get the delivery log of webhook, the newest first
+func (ws *WebhookService) GetDeliveryPage(ctx context.Context, req *schema.GetWebhookDeliveryPageReq) (
+	pageModel *pager.PageModel, err error) {
+	deliveries, total, err := ws.webhookRepo.GetDeliveryPage(ctx, req.WebhookID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]*schema.GetWebhookDeliveryResp, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		item := &schema.GetWebhookDeliveryResp{
+			ID:             delivery.ID,
+			WebhookID:      delivery.WebhookID,
+			Event:          delivery.Event,
+			Payload:        delivery.Payload,
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			ResponseStatus: delivery.ResponseStatus,
+			ResponseBody:   delivery.ResponseBody,
+			Error:          delivery.Error,
+			Duration:       delivery.Duration,
+			CreatedAt:      delivery.CreatedAt.Unix(),
+		}
+		if !delivery.DeliveredAt.IsZero() {
+			item.DeliveredAt = delivery.DeliveredAt.Unix()
+		}
+		resp = append(resp, item)
+	}
+	return pager.NewPageModel(total, resp), nil
+}
+
+// Redeliver send the payload of delivery again as a new delivery
+func (ws *WebhookService) Redeliver(ctx context.Context, req *schema.RedeliverWebhookReq) (err error) {
+	delivery, exist, err := ws.webhookRepo.GetDelivery(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.WebhookDeliveryNotFound)
+	}
+	newDelivery := &entity.WebhookDelivery{
+		WebhookID: delivery.WebhookID,
+		Event:     delivery.Event,
+		Payload:   delivery.Payload,
+		Status:    entity.WebhookDeliveryStatusPending,
+	}
+	if err = ws.webhookRepo.AddDelivery(ctx, newDelivery); err != nil {
+		return err
+	}
+	webhook_queue.AddDelivery(newDelivery.ID)
+	return nil
+}
+
+// HandleEvent create the deliveries of webhooks subscribed the event
+func (ws *WebhookService) HandleEvent(ctx context.Context, payload []byte) error {
+	msg := &schema.ActivityMsg{}
+	if err := json.Unmarshal(payload, msg); err != nil {
+		log.Errorf("decode webhook event failed: %s", err)
+		return nil
+	}
+	event := string(msg.ActivityTypeKey)
+	if !isWebhookEvent(event) {
+		return nil
+	}
+
+	webhooks, err := ws.webhookRepo.GetEnabledWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	subscribed := make([]*entity.Webhook, 0)
+	for _, webhook := range webhooks {
+		if containsItem(splitList(webhook.Events), event) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	body, err := ws.buildPayload(ctx, msg)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	for _, webhook := range subscribed {
+		if !matchTags(splitList(webhook.Tags), body.Object.Tags) {
+			continue
+		}
+		delivery := &entity.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Event:     event,
+			Payload:   string(data),
+			Status:    entity.WebhookDeliveryStatusPending,
+		}
+		if err = ws.webhookRepo.AddDelivery(ctx, delivery); err != nil {
+			log.Error(err)
+			continue
+		}
+		webhook_queue.AddDelivery(delivery.ID)
+	}
+	return nil
+}
+
+func (ws *WebhookService) buildPayload(ctx context.Context, msg *schema.ActivityMsg) (
+	payload *schema.WebhookPayload, err error) {
+	objInfo, err := ws.objectInfoService.GetInfo(ctx, msg.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	payload = &schema.WebhookPayload{
+		Event:     string(msg.ActivityTypeKey),
+		CreatedAt: time.Now().Unix(),
+		Object: schema.WebhookPayloadObject{
+			Type:       objInfo.ObjectType,
+			ID:         objInfo.ObjectID,
+			QuestionID: objInfo.QuestionID,
+			AnswerID:   objInfo.AnswerID,
+			CommentID:  objInfo.CommentID,
+			TagID:      objInfo.TagID,
+			Title:      objInfo.Title,
+			Tags:       make([]string, 0),
+		},
+	}
+
+	siteGeneral, err := ws.siteInfoCommonService.GetSiteGeneral(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if objInfo.ObjectType == constant.TagObjectType {
+		// the deleted tag is also included
+		tag, exist, err := ws.tagCommonRepo.GetTagByID(ctx, objInfo.TagID, true)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			payload.Object.Tags = append(payload.Object.Tags, tag.SlugName)
+			payload.Object.URL = fmt.Sprintf("%s/tags/%s", siteGeneral.SiteUrl, tag.SlugName)
+		}
+	} else if len(objInfo.QuestionID) > 0 {
+		tags, err := ws.tagCommonService.GetObjectEntityTag(ctx, objInfo.QuestionID)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			payload.Object.Tags = append(payload.Object.Tags, tag.SlugName)
+		}
+		payload.Object.URL = fmt.Sprintf("%s/questions/%s", siteGeneral.SiteUrl, objInfo.QuestionID)
+		if len(objInfo.AnswerID) > 0 {
+			payload.Object.URL += "/" + objInfo.AnswerID
+		}
+	}
+
+	if len(msg.UserID) > 0 {
+		userInfo, exist, err := ws.userRepo.GetByUserID(ctx, msg.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			payload.User = &schema.WebhookPayloadUser{
+				ID:          userInfo.ID,
+				Username:    userInfo.Username,
+				DisplayName: userInfo.DisplayName,
+			}
+		}
+	}
+	return payload, nil
+}
+
+// HandleDelivery send the delivery, the error is returned to retry later until the max attempts of delivery or queue
+func (ws *WebhookService) HandleDelivery(ctx context.Context, payload []byte) error {
+	var deliveryID string
+	if err := json.Unmarshal(payload, &deliveryID); err != nil {
+		log.Errorf("decode webhook delivery failed: %s", err)
+		return nil
+	}
+	delivery, exist, err := ws.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if !exist || delivery.Status != entity.WebhookDeliveryStatusPending {
+		return nil
+	}
+	webhook, exist, err := ws.webhookRepo.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return nil
+	}
+	if !webhook.Enabled {
+		delivery.Status = entity.WebhookDeliveryStatusFailed
+		delivery.Error = "webhook is disabled"
+		return ws.webhookRepo.UpdateDelivery(ctx, delivery)
+	}
+
+	sendErr := ws.send(ctx, webhook, delivery)
+	switch {
+	case sendErr == nil:
+		delivery.Status = entity.WebhookDeliveryStatusSucceeded
+	case delivery.Attempts >= maxDeliveryAttempts || job_queue.IsLastAttempt(ctx):
+		// the job will not be retried by the queue, so the delivery is failed now
+		delivery.Status = entity.WebhookDeliveryStatusFailed
+		sendErr = nil
+	}
+	if err = ws.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// send post the payload to the webhook url and record the result in delivery
+func (ws *WebhookService) send(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) error {
+	delivery.Attempts++
+	delivery.DeliveredAt = time.Now()
+	delivery.ResponseStatus = 0
+	delivery.ResponseBody = ""
+	delivery.Error = ""
+	defer func() {
+		delivery.Duration = int(time.Since(delivery.DeliveredAt).Milliseconds())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		delivery.Error = err.Error()
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Answer-Webhook")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(SignatureHeader, sign(webhook.Secret, []byte(delivery.Payload)))
+
+	resp, err := ws.httpClient.Do(req)
+	if err != nil {
+		delivery.Error = err.Error()
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyLength))
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = strings.ToValidUTF8(string(body), "")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err = fmt.Errorf("unexpected response status %d", resp.StatusCode)
+		delivery.Error = err.Error()
+		return err
+	}
+	return nil
+}
+
+// newDeliveryClient the client can only connect to the public addresses. The address is checked after
+// the host is resolved, so that it can not be bypassed by DNS rebinding or redirecting to the private address.
+func newDeliveryClient() *http.Client {
+	dialer := &net.Dialer{Timeout: deliveryTimeout, Control: denyPrivateAddress}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// the proxy is not used, otherwise only the address of proxy is checked
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: deliveryTimeout, Transport: transport}
+}
+
+// denyPrivateAddress reject the connection to the loopback, private, link-local and other non-public addresses
+func denyPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("webhook can not be delivered to the non-public address %s", host)
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	// the shared address space of carrier-grade NAT, some cloud metadata services use it
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return false
+	}
+	return true
+}
+
+// sign the HMAC-SHA256 signature of body
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// matchTags the webhook without tags matches all objects
+func matchTags(webhookTags, objectTags []string) bool {
+	if len(webhookTags) == 0 {
+		return true
+	}
+	for _, tag := range objectTags {
+		if containsItem(webhookTags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWebhookEvent(event string) bool {
+	for _, e := range schema.WebhookEvents {
+		if string(e) == event {
+			return true
+		}
+	}
+	return false
+}
+
+// secretHint the last 4 characters of secret, so that the admin can tell which secret is used
+func secretHint(secret string) string {
+	if len(secret) <= 8 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func splitList(s string) []string {
+	if len(s) == 0 {
+		return []string{}
+	}
+	return strings.Split(s, ",")
+}
+
+func containsItem(list []string, item string) bool {
+	for _, s := range list {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/answerdev/answer/internal/entity"
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/audit_log"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWebhookRepo struct {
+	WebhookRepo
+	webhooks map[string]*entity.Webhook
+}
+
+func (f *fakeWebhookRepo) AddWebhook(ctx context.Context, webhook *entity.Webhook) error {
+	webhook.ID = "1"
+	f.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (f *fakeWebhookRepo) UpdateWebhook(ctx context.Context, webhook *entity.Webhook) error {
+	f.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (f *fakeWebhookRepo) RemoveWebhook(ctx context.Context, id string) error {
+	delete(f.webhooks, id)
+	return nil
+}
+
+func (f *fakeWebhookRepo) GetWebhook(ctx context.Context, id string) (*entity.Webhook, bool, error) {
+	webhook, ok := f.webhooks[id]
+	if !ok {
+		return nil, false, nil
+	}
+	copied := *webhook
+	return &copied, true, nil
+}
+
+type fakeAuditLogRepo struct {
+	audit_log.AuditLogRepo
+	logs []*entity.AuditLog
+}
+
+func (f *fakeAuditLogRepo) AddAuditLog(ctx context.Context, auditLog *entity.AuditLog) error {
+	f.logs = append(f.logs, auditLog)
+	return nil
+}
+
+func TestWebhookService_AuditLog(t *testing.T) {
+	auditLogRepo := &fakeAuditLogRepo{}
+	ws := &WebhookService{
+		webhookRepo:     &fakeWebhookRepo{webhooks: map[string]*entity.Webhook{}},
+		auditLogService: audit_log.NewAuditLogService(auditLogRepo, nil),
+	}
+	req := &schema.AddWebhookReq{Name: "ci", URL: "https://example.com/hook", Secret: "secret",
+		Events: []string{"question.asked"}, Enabled: true}
+	resp, err := ws.AddWebhook(context.TODO(), req)
+	assert.NoError(t, err)
+	req.Enabled = false
+	err = ws.UpdateWebhook(context.TODO(), &schema.UpdateWebhookReq{ID: resp.ID, AddWebhookReq: *req})
+	assert.NoError(t, err)
+	err = ws.RemoveWebhook(context.TODO(), &schema.RemoveWebhookReq{ID: resp.ID})
+	assert.NoError(t, err)
+
+	assert.Len(t, auditLogRepo.logs, 3)
+	assert.Equal(t, entity.AuditActionWebhookAdd, auditLogRepo.logs[0].Action)
+	assert.Equal(t, entity.AuditActionWebhookUpdate, auditLogRepo.logs[1].Action)
+	assert.Equal(t, entity.AuditActionWebhookRemove, auditLogRepo.logs[2].Action)
+	// the secret is never recorded
+	assert.Equal(t, `{"enabled":true,"events":["question.asked"],"name":"ci","tags":[],"url":"https://example.com/hook"}`,
+		auditLogRepo.logs[1].Before)
+	assert.Equal(t, auditLogRepo.logs[1].After, auditLogRepo.logs[2].Before)
+}
+
+func TestSign(t *testing.T) {
+	// the signature can be verified by the receiver with the same algorithm
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
+
+func TestMatchTags(t *testing.T) {
+	assert.True(t, matchTags([]string{}, []string{"go"}))
+	assert.True(t, matchTags([]string{"go", "rust"}, []string{"java", "go"}))
+	assert.False(t, matchTags([]string{"go"}, []string{"java"}))
+	assert.False(t, matchTags([]string{"go"}, []string{}))
+}
+
+func TestWebhookService_send(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, sign("secret", body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "question.asked", r.Header.Get(EventHeader))
+		assert.Equal(t, "10", r.Header.Get(DeliveryHeader))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	ws := &WebhookService{httpClient: server.Client()}
+	webhook := &entity.Webhook{URL: server.URL, Secret: "secret"}
+	delivery := &entity.WebhookDelivery{ID: "10", Event: "question.asked", Payload: `{"event":"question.asked"}`}
+	err := ws.send(context.TODO(), webhook, delivery)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusOK, delivery.ResponseStatus)
+	assert.Equal(t, "ok", delivery.ResponseBody)
+
+	status = http.StatusInternalServerError
+	err = ws.send(context.TODO(), webhook, delivery)
+	assert.Error(t, err)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, delivery.ResponseStatus)
+	assert.NotEmpty(t, delivery.Error)
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"100.100.100.200", "0.0.0.0", "::1", "fe80::1", "fc00::1", "::ffff:127.0.0.1"} {
+		assert.False(t, isPublicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "1.1.1.1", "2001:4860:4860::8888"} {
+		assert.True(t, isPublicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestWebhookService_sendToPrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// the test server listens on the loopback address
+	ws := &WebhookService{httpClient: newDeliveryClient()}
+	webhook := &entity.Webhook{URL: server.URL, Secret: "secret"}
+	delivery := &entity.WebhookDelivery{ID: "10", Event: "question.asked", Payload: `{"event":"question.asked"}`}
+	err := ws.send(context.TODO(), webhook, delivery)
+	assert.Error(t, err)
+	assert.Equal(t, 0, delivery.ResponseStatus)
+	assert.Contains(t, delivery.Error, "non-public address")
+}
@@ -0,0 +1,23 @@
+package webhook_queue
+
+import (
+	"github.com/answerdev/answer/internal/schema"
+	"github.com/answerdev/answer/internal/service/job_queue"
+)
+
+const (
+	// Topic the topic of the event which may trigger webhooks
+	Topic = "webhook"
+	// DeliveryTopic the topic of webhook delivery, the message is the delivery id
+	DeliveryTopic = "webhook.delivery"
+)
+
+// AddEvent add new event, the activity message is used as the event
+func AddEvent(msg *schema.ActivityMsg) {
+	job_queue.Publish(Topic, msg)
+}
+
+// AddDelivery add the delivery to send
+func AddDelivery(deliveryID string) {
+	job_queue.Publish(DeliveryTopic, deliveryID)
+}
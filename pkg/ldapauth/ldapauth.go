@@ -0,0 +1,159 @@
+package ldapauth
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// LoginPlaceholder the placeholder in the user filter which is replaced with the escaped login name
+const LoginPlaceholder = "{login}"
+
+const defaultTimeout = 10 * time.Second
+
+var (
+	// ErrUserNotFound the user is not found in the directory by the user filter
+	ErrUserNotFound = errors.New("ldap user not found")
+	// ErrInvalidCredentials the password of user is wrong
+	ErrInvalidCredentials = errors.New("ldap invalid credentials")
+)
+
+// Config the connection and search config of directory
+type Config struct {
+	// URL ldap://host:389 or ldaps://host:636
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	// BindDN BindPassword the service account to search the user, the anonymous bind is used if it is empty
+	BindDN       string
+	BindPassword string
+	BaseDN       string
+	// UserFilter the filter to find the user, e.g. (&(objectClass=person)(mail={login}))
+	UserFilter string
+	// Attributes the attributes of user to fetch
+	Attributes []string
+	Timeout    time.Duration
+}
+
+// Entry the authenticated user in directory
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// GetAttribute get the first value of attribute, the attribute name is case-insensitive
+func (e *Entry) GetAttribute(name string) string {
+	if values := e.GetAttributeValues(name); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// GetAttributeValues get all values of attribute, the attribute name is case-insensitive
+func (e *Entry) GetAttributeValues(name string) []string {
+	for attrName, values := range e.Attributes {
+		if strings.EqualFold(attrName, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+// Authenticate find the user by login name and verify the password by binding as the user
+func Authenticate(conf *Config, login, password string) (entry *Entry, err error) {
+	// the empty password is an unauthenticated bind which always succeeds on many servers
+	if len(login) == 0 || len(password) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+	conn, err := dial(conf)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if len(conf.BindDN) > 0 {
+		err = conn.Bind(conf.BindDN, conf.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ldap bind service account failed: %w", err)
+	}
+
+	searchReq := ldap.NewSearchRequest(conf.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		2, int(conf.getTimeout().Seconds()), false, UserFilter(conf.UserFilter, login), conf.Attributes, nil)
+	result, err := conn.Search(searchReq)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("ldap search user failed: %w", err)
+	}
+	if result == nil || len(result.Entries) == 0 {
+		return nil, ErrUserNotFound
+	}
+	if len(result.Entries) > 1 {
+		return nil, fmt.Errorf("ldap user filter matches more than one user of %s", login)
+	}
+
+	userEntry := result.Entries[0]
+	if err = conn.Bind(userEntry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("ldap bind user failed: %w", err)
+	}
+
+	entry = &Entry{DN: userEntry.DN, Attributes: make(map[string][]string)}
+	for _, attr := range userEntry.Attributes {
+		entry.Attributes[attr.Name] = attr.Values
+	}
+	return entry, nil
+}
+
+// UserFilter replace the placeholder of filter with the escaped login name
+func UserFilter(filter, login string) string {
+	return strings.ReplaceAll(filter, LoginPlaceholder, ldap.EscapeFilter(login))
+}
+
+// GroupName get the value of first RDN if the group is a DN, e.g. cn=admins,ou=groups,dc=example,dc=com is admins
+func GroupName(group string) string {
+	dn, err := ldap.ParseDN(group)
+	if err != nil || len(dn.RDNs) == 0 || len(dn.RDNs[0].Attributes) == 0 {
+		return group
+	}
+	return dn.RDNs[0].Attributes[0].Value
+}
+
+func dial(conf *Config) (conn *ldap.Conn, err error) {
+	u, err := url.Parse(conf.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ldap url: %w", err)
+	}
+	// the server name is required to verify the certificate of StartTLS
+	tlsConfig := &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: conf.InsecureSkipVerify}
+	conn, err = ldap.DialURL(conf.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: conf.getTimeout()}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("ldap dial failed: %w", err)
+	}
+	conn.SetTimeout(conf.getTimeout())
+	if conf.StartTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("ldap start tls failed: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (conf *Config) getTimeout() time.Duration {
+	if conf.Timeout > 0 {
+		return conf.Timeout
+	}
+	return defaultTimeout
+}
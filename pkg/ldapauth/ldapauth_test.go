@@ -0,0 +1,115 @@
+package ldapauth
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBaseDN        = "dc=example,dc=org"
+	testAdminDN       = "cn=admin,dc=example,dc=org"
+	testAdminPassword = "admin"
+)
+
+func TestUserFilter(t *testing.T) {
+	assert.Equal(t, "(&(objectClass=person)(mail=jane@example.com))",
+		UserFilter("(&(objectClass=person)(mail={login}))", "jane@example.com"))
+	assert.Equal(t, `(uid=\2a\29\28uid=\2a)`, UserFilter("(uid={login})", "*)(uid=*"))
+}
+
+func TestGroupName(t *testing.T) {
+	assert.Equal(t, "admins", GroupName("cn=admins,ou=groups,dc=example,dc=org"))
+	assert.Equal(t, "admins", GroupName("admins"))
+}
+
+func TestEntry_GetAttribute(t *testing.T) {
+	entry := &Entry{Attributes: map[string][]string{"memberOf": {"cn=a", "cn=b"}}}
+	assert.Equal(t, "cn=a", entry.GetAttribute("memberof"))
+	assert.Equal(t, []string{"cn=a", "cn=b"}, entry.GetAttributeValues("MEMBEROF"))
+	assert.Empty(t, entry.GetAttribute("mail"))
+}
+
+func TestAuthenticate_EmptyPassword(t *testing.T) {
+	_, err := Authenticate(&Config{URL: "ldap://127.0.0.1:1"}, "jane", "")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+// TestAuthenticate run with a local OpenLDAP container, set TEST_LDAP=1 to enable it
+func TestAuthenticate(t *testing.T) {
+	if len(os.Getenv("TEST_LDAP")) == 0 {
+		t.Skip("set TEST_LDAP=1 to test with the OpenLDAP container")
+	}
+	url := initOpenLDAP(t)
+	conf := &Config{
+		URL:          url,
+		BindDN:       testAdminDN,
+		BindPassword: testAdminPassword,
+		BaseDN:       testBaseDN,
+		UserFilter:   "(&(objectClass=inetOrgPerson)(mail={login}))",
+		Attributes:   []string{"uid", "cn", "mail"},
+	}
+
+	entry, err := Authenticate(conf, "jane@example.org", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "uid=jane,"+testBaseDN, entry.DN)
+	assert.Equal(t, "jane", entry.GetAttribute("uid"))
+	assert.Equal(t, "Jane Doe", entry.GetAttribute("cn"))
+
+	_, err = Authenticate(conf, "jane@example.org", "wrong")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = Authenticate(conf, "nobody@example.org", "secret")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	conf.BindPassword = "wrong"
+	_, err = Authenticate(conf, "jane@example.org", "secret")
+	assert.Error(t, err)
+}
+
+// initOpenLDAP start the OpenLDAP container and add the test user
+func initOpenLDAP(t *testing.T) (url string) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+	pool.MaxWait = time.Minute * 2
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "osixia/openldap",
+		Tag:        "1.5.0",
+		Env:        []string{"LDAP_ADMIN_PASSWORD=" + testAdminPassword},
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pool.Purge(resource) })
+
+	url = fmt.Sprintf("ldap://localhost:%s", resource.GetPort("389/tcp"))
+	var conn *ldap.Conn
+	require.NoError(t, pool.Retry(func() error {
+		conn, err = ldap.DialURL(url)
+		if err != nil {
+			return err
+		}
+		if err = conn.Bind(testAdminDN, testAdminPassword); err != nil {
+			conn.Close()
+		}
+		return err
+	}))
+	defer conn.Close()
+
+	addReq := ldap.NewAddRequest("uid=jane,"+testBaseDN, nil)
+	addReq.Attribute("objectClass", []string{"inetOrgPerson"})
+	addReq.Attribute("uid", []string{"jane"})
+	addReq.Attribute("cn", []string{"Jane Doe"})
+	addReq.Attribute("sn", []string{"Doe"})
+	addReq.Attribute("mail", []string{"jane@example.org"})
+	addReq.Attribute("userPassword", []string{"secret"})
+	require.NoError(t, conn.Add(addReq))
+	return url
+}
@@ -0,0 +1,88 @@
+package mailreply
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// signatureLength the number of bytes of HMAC kept in the token, it is 16 characters in base32
+const signatureLength = 10
+
+var (
+	// ErrInvalidAddress the address is not a reply address or the signature does not match
+	ErrInvalidAddress = errors.New("invalid reply address")
+
+	signatureEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// Address build the signed reply address from the base address,
+// e.g. reply@example.com -> reply+<token>@example.com
+// The token contains the object id and user id in base36 and the signature of them,
+// so it is short enough to fit in the 64 characters limit of local part.
+func Address(baseAddress, secret, userID, objectID string) (string, error) {
+	local, domain, ok := splitAddress(baseAddress)
+	if !ok {
+		return "", fmt.Errorf("invalid base address %s", baseAddress)
+	}
+	objectNum, err := strconv.ParseUint(objectID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid object id %s", objectID)
+	}
+	userNum, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid user id %s", userID)
+	}
+	token := strconv.FormatUint(objectNum, 36) + "-" + strconv.FormatUint(userNum, 36) + "-" +
+		sign(secret, objectID, userID)
+	return local + "+" + token + "@" + domain, nil
+}
+
+// Parse verify the signed reply address and get the user id and object id from it
+func Parse(baseAddress, secret, address string) (userID, objectID string, err error) {
+	baseLocal, baseDomain, ok := splitAddress(baseAddress)
+	if !ok {
+		return "", "", ErrInvalidAddress
+	}
+	local, domain, ok := splitAddress(address)
+	if !ok || !strings.EqualFold(domain, baseDomain) ||
+		!strings.HasPrefix(strings.ToLower(local), strings.ToLower(baseLocal)+"+") {
+		return "", "", ErrInvalidAddress
+	}
+
+	parts := strings.Split(strings.ToLower(local[len(baseLocal)+1:]), "-")
+	if len(parts) != 3 {
+		return "", "", ErrInvalidAddress
+	}
+	objectNum, err := strconv.ParseUint(parts[0], 36, 64)
+	if err != nil {
+		return "", "", ErrInvalidAddress
+	}
+	userNum, err := strconv.ParseUint(parts[1], 36, 64)
+	if err != nil {
+		return "", "", ErrInvalidAddress
+	}
+	objectID, userID = strconv.FormatUint(objectNum, 10), strconv.FormatUint(userNum, 10)
+	if !hmac.Equal([]byte(parts[2]), []byte(sign(secret, objectID, userID))) {
+		return "", "", ErrInvalidAddress
+	}
+	return userID, objectID, nil
+}
+
+func sign(secret, objectID, userID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(objectID + ":" + userID))
+	return strings.ToLower(signatureEncoding.EncodeToString(mac.Sum(nil)[:signatureLength]))
+}
+
+func splitAddress(address string) (local, domain string, ok bool) {
+	idx := strings.LastIndex(address, "@")
+	if idx <= 0 || idx == len(address)-1 {
+		return "", "", false
+	}
+	return address[:idx], address[idx+1:], true
+}
@@ -0,0 +1,50 @@
+package mailreply
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddress(t *testing.T) {
+	addr, err := Address("reply@example.com", "secret", "1", "10070000000000001")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(addr, "reply+"))
+	assert.True(t, strings.HasSuffix(addr, "@example.com"))
+	assert.LessOrEqual(t, strings.Index(addr, "@"), 64)
+
+	userID, objectID, err := Parse("reply@example.com", "secret", addr)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", userID)
+	assert.Equal(t, "10070000000000001", objectID)
+
+	// the address is case-insensitive
+	_, _, err = Parse("reply@example.com", "secret", strings.ToUpper(addr))
+	assert.NoError(t, err)
+
+	_, err = Address("reply", "secret", "1", "10070000000000001")
+	assert.Error(t, err)
+	_, err = Address("reply@example.com", "secret", "a", "10070000000000001")
+	assert.Error(t, err)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	addr, err := Address("reply@example.com", "secret", "1", "10070000000000001")
+	assert.NoError(t, err)
+
+	for _, tt := range []struct {
+		name, base, secret, address string
+	}{
+		{"wrong secret", "reply@example.com", "other", addr},
+		{"wrong domain", "reply@example.org", "secret", addr},
+		{"wrong local part", "answer@example.com", "secret", addr},
+		{"no token", "reply@example.com", "secret", "reply@example.com"},
+		{"tampered user", "reply@example.com", "secret", strings.Replace(addr, "-1-", "-2-", 1)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Parse(tt.base, tt.secret, tt.address)
+			assert.ErrorIs(t, err, ErrInvalidAddress)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package mailreply
+
+import (
+	"encoding/base64"
+	"errors"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	strip "github.com/grokify/html-strip-tags-go"
+)
+
+// maxPartDepth the max depth of nested multipart
+const maxPartDepth = 5
+
+// ErrNoText the mail has no text content
+var ErrNoText = errors.New("no text content in mail")
+
+var (
+	// quoteHeaderRegs the lines start the quoted original message, the reply is above them
+	quoteHeaderRegs = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^on\s.+wrote:$`),
+		regexp.MustCompile(`^在.+写道[:：]$`),
+		regexp.MustCompile(`(?i)^-+\s*original message\s*-+$`),
+		regexp.MustCompile(`^_{10,}$`),
+		regexp.MustCompile(`(?i)^from:\s.*@`),
+		regexp.MustCompile(`(?i)^sent from my `),
+	}
+	// htmlQuoteReg the quoted message of html mail
+	htmlQuoteReg     = regexp.MustCompile(`(?is)<blockquote.*|<div[^>]*class="[^"]*(gmail_quote|moz-cite-prefix)[^"]*".*`)
+	htmlLineBreakReg = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>`)
+)
+
+// Message the reply mail
+type Message struct {
+	// From the address of sender
+	From string
+	// Text the reply text with the quoted message and signature stripped
+	Text string
+}
+
+// ReadMessage read the reply mail, the text/plain part is preferred and the text/html part is used if it not exists
+func ReadMessage(r io.Reader) (*Message, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return nil, err
+	}
+
+	plain, htmlText, err := readPart(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"),
+		msg.Body, 0)
+	if err != nil {
+		return nil, err
+	}
+	var text string
+	if len(strings.TrimSpace(plain)) > 0 {
+		text = StripQuoted(plain)
+	} else {
+		text = StripQuoted(htmlToText(htmlText))
+	}
+	if len(text) == 0 {
+		return nil, ErrNoText
+	}
+	return &Message{From: from.Address, Text: text}, nil
+}
+
+// StripQuoted strip the quoted original message and signature from the reply text
+func StripQuoted(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if line == "-- " || isQuoteHeader(trimmed) {
+			break
+		}
+		// some clients wrap the quote header in two lines, e.g. "On Mon, Jan 2, 2023 at 10:00 AM Someone\n<a@b.c> wrote:"
+		if strings.HasSuffix(trimmed, "wrote:") && len(lines) > 0 &&
+			isQuoteHeader(strings.TrimSpace(lines[len(lines)-1])+" "+trimmed) {
+			lines = lines[:len(lines)-1]
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func isQuoteHeader(line string) bool {
+	for _, reg := range quoteHeaderRegs {
+		if reg.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// readPart read the text/plain and text/html content of the part, the nested multipart is walked through
+func readPart(contentType, encoding string, body io.Reader, depth int) (plain, htmlText string, err error) {
+	if len(contentType) == 0 {
+		contentType = "text/plain"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", "", err
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxPartDepth {
+			return "", "", nil
+		}
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return plain, htmlText, nil
+			}
+			if err != nil {
+				return "", "", err
+			}
+			// skip the attachments
+			if strings.HasPrefix(strings.ToLower(part.Header.Get("Content-Disposition")), "attachment") {
+				continue
+			}
+			partPlain, partHTML, err := readPart(part.Header.Get("Content-Type"),
+				part.Header.Get("Content-Transfer-Encoding"), part, depth+1)
+			if err != nil {
+				return "", "", err
+			}
+			if len(plain) == 0 {
+				plain = partPlain
+			}
+			if len(htmlText) == 0 {
+				htmlText = partHTML
+			}
+		}
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil
+	}
+	content, err := io.ReadAll(decode(encoding, body))
+	if err != nil {
+		return "", "", err
+	}
+	if mediaType == "text/plain" {
+		return string(content), "", nil
+	}
+	return "", string(content), nil
+}
+
+func decode(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	default:
+		return body
+	}
+}
+
+func htmlToText(content string) string {
+	content = htmlQuoteReg.ReplaceAllString(content, "")
+	content = htmlLineBreakReg.ReplaceAllString(content, "\n")
+	return html.UnescapeString(strip.StripTags(content))
+}
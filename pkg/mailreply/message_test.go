@@ -0,0 +1,98 @@
+package mailreply
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripQuoted(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{
+			name: "gmail",
+			text: "Thanks, it works.\r\n\r\nOn Mon, Jan 2, 2023 at 10:00 AM Answer <reply@example.com> wrote:\r\n> the answer\r\n",
+			want: "Thanks, it works.",
+		},
+		{
+			name: "wrapped quote header",
+			text: "Thanks, it works.\n\nOn Mon, Jan 2, 2023 at 10:00 AM Answer\n<reply@example.com> wrote:\n> the answer\n",
+			want: "Thanks, it works.",
+		},
+		{
+			name: "outlook",
+			text: "Thanks.\n\n________________________________\nFrom: Answer <reply@example.com>\nSent: Monday\n",
+			want: "Thanks.",
+		},
+		{
+			name: "signature",
+			text: "Thanks.\n-- \nBob\n",
+			want: "Thanks.",
+		},
+		{
+			name: "inline quote",
+			text: "> the question\nmy answer\n> more\nfrom: my side it works",
+			want: "my answer\nfrom: my side it works",
+		},
+		{
+			name: "chinese",
+			text: "谢谢\n\n在 2023年1月2日 10:00，Answer <reply@example.com> 写道：\n> 回答",
+			want: "谢谢",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StripQuoted(tt.text))
+		})
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	raw := strings.Join([]string{
+		"From: Bob <bob@example.com>",
+		"To: reply+abc@example.com",
+		"Subject: Re: new answer",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/alternative; boundary="b1"`,
+		"",
+		"--b1",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"It works, th=",
+		"anks!",
+		"",
+		"On Mon, Jan 2, 2023 Answer <reply@example.com> wrote:",
+		"> the answer",
+		"--b1",
+		"Content-Type: text/html; charset=utf-8",
+		"",
+		"<div>It works, thanks!</div><blockquote>the answer</blockquote>",
+		"--b1--",
+		"",
+	}, "\r\n")
+	msg, err := ReadMessage(strings.NewReader(raw))
+	assert.NoError(t, err)
+	assert.Equal(t, "bob@example.com", msg.From)
+	assert.Equal(t, "It works, thanks!", msg.Text)
+}
+
+func TestReadMessage_HTML(t *testing.T) {
+	raw := strings.Join([]string{
+		"From: bob@example.com",
+		"Content-Type: text/html; charset=utf-8",
+		"Content-Transfer-Encoding: base64",
+		"",
+		"PGRpdj5JdCB3b3JrcyAmYW1wOyB0aGFua3M8L2Rpdj48ZGl2IGNsYXNzPSJnbWFpbF9xdW90ZSI+",
+		"b2xkPC9kaXY+",
+		"",
+	}, "\r\n")
+	msg, err := ReadMessage(strings.NewReader(raw))
+	assert.NoError(t, err)
+	assert.Equal(t, "It works & thanks", msg.Text)
+
+	_, err = ReadMessage(strings.NewReader("From: bob@example.com\r\n\r\n> quoted only\r\n"))
+	assert.ErrorIs(t, err, ErrNoText)
+}
@@ -0,0 +1,148 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config OAuth2 authorization code flow config of a provider
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Token the token response of provider
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	IDToken      string `json:"id_token"`
+	Error        string `json:"error"`
+	ErrorDesc    string `json:"error_description"`
+}
+
+// ProviderMetadata the OpenID Connect discovery document
+type ProviderMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// Client send requests to the provider
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient new oauth client
+func NewClient() *Client {
+	return &Client{httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// AuthCodeURL the url of provider consent page to redirect the user to
+func (c *Config) AuthCodeURL(state string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", c.ClientID)
+	params.Set("redirect_uri", c.RedirectURL)
+	if len(c.Scopes) > 0 {
+		params.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	params.Set("state", state)
+	sep := "?"
+	if strings.Contains(c.AuthURL, "?") {
+		sep = "&"
+	}
+	return c.AuthURL + sep + params.Encode()
+}
+
+// Exchange exchange the authorization code for token
+func (cli *Client) Exchange(ctx context.Context, conf *Config, code string) (token *Token, err error) {
+	params := url.Values{}
+	params.Set("grant_type", "authorization_code")
+	params.Set("code", code)
+	params.Set("redirect_uri", conf.RedirectURL)
+	params.Set("client_id", conf.ClientID)
+	params.Set("client_secret", conf.ClientSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, conf.TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// GitHub returns form encoded body without this header
+	req.Header.Set("Accept", "application/json")
+
+	token = &Token{}
+	if err = cli.do(req, token); err != nil {
+		return nil, err
+	}
+	if len(token.Error) > 0 {
+		return nil, fmt.Errorf("exchange token failed: %s %s", token.Error, token.ErrorDesc)
+	}
+	if len(token.AccessToken) == 0 {
+		return nil, fmt.Errorf("exchange token failed: access token is empty")
+	}
+	return token, nil
+}
+
+// GetJSON request the resource with access token and decode the json response into v
+func (cli *Client) GetJSON(ctx context.Context, resourceURL, accessToken string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resourceURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if len(accessToken) > 0 {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	return cli.do(req, v)
+}
+
+// Discover fetch the OpenID Connect discovery document of issuer
+func (cli *Client) Discover(ctx context.Context, issuer string) (metadata *ProviderMetadata, err error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	metadata = &ProviderMetadata{}
+	if err = cli.GetJSON(ctx, issuer+"/.well-known/openid-configuration", "", metadata); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(metadata.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("issuer %s does not match the discovery document issuer %s", issuer, metadata.Issuer)
+	}
+	if len(metadata.AuthorizationEndpoint) == 0 || len(metadata.TokenEndpoint) == 0 {
+		return nil, fmt.Errorf("discovery document of %s has no authorization or token endpoint", issuer)
+	}
+	return metadata, nil
+}
+
+func (cli *Client) do(req *http.Request, v interface{}) error {
+	resp, err := cli.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if len(body) > 1024 {
+			body = body[:1024]
+		}
+		return fmt.Errorf("%s %s failed: %s %s", req.Method, req.URL.Host+req.URL.Path, resp.Status, body)
+	}
+	if err = json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decode response of %s failed: %w", req.URL.Host+req.URL.Path, err)
+	}
+	return nil
+}
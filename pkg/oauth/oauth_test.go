@@ -0,0 +1,86 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_AuthCodeURL(t *testing.T) {
+	conf := &Config{
+		ClientID:    "client",
+		AuthURL:     "https://idp.example.com/authorize",
+		RedirectURL: "https://answer.example.com/callback",
+		Scopes:      []string{"openid", "email"},
+	}
+	u, err := url.Parse(conf.AuthCodeURL("xyz"))
+	assert.NoError(t, err)
+	assert.Equal(t, "idp.example.com", u.Host)
+	assert.Equal(t, "code", u.Query().Get("response_type"))
+	assert.Equal(t, "client", u.Query().Get("client_id"))
+	assert.Equal(t, "https://answer.example.com/callback", u.Query().Get("redirect_uri"))
+	assert.Equal(t, "openid email", u.Query().Get("scope"))
+	assert.Equal(t, "xyz", u.Query().Get("state"))
+
+	conf.AuthURL = "https://idp.example.com/authorize?prompt=login"
+	u, err = url.Parse(conf.AuthCodeURL("xyz"))
+	assert.NoError(t, err)
+	assert.Equal(t, "login", u.Query().Get("prompt"))
+	assert.Equal(t, "xyz", u.Query().Get("state"))
+}
+
+func TestClient_CodeFlow(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 server.URL,
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"userinfo_endpoint":      server.URL + "/userinfo",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.PostForm.Get("code") != "good" || r.PostForm.Get("client_secret") != "secret" {
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "at", "token_type": "Bearer"})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer at" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"sub": "123"})
+	})
+
+	ctx := context.Background()
+	cli := NewClient()
+	metadata, err := cli.Discover(ctx, server.URL+"/")
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/token", metadata.TokenEndpoint)
+
+	_, err = cli.Discover(ctx, "http://"+server.Listener.Addr().String()+"/other")
+	assert.Error(t, err)
+
+	conf := &Config{ClientID: "client", ClientSecret: "secret", TokenURL: metadata.TokenEndpoint}
+	_, err = cli.Exchange(ctx, conf, "bad")
+	assert.Error(t, err)
+	token, err := cli.Exchange(ctx, conf, "good")
+	assert.NoError(t, err)
+	assert.Equal(t, "at", token.AccessToken)
+
+	userInfo := map[string]string{}
+	assert.NoError(t, cli.GetJSON(ctx, metadata.UserinfoEndpoint, token.AccessToken, &userInfo))
+	assert.Equal(t, "123", userInfo["sub"])
+	assert.Error(t, cli.GetJSON(ctx, metadata.UserinfoEndpoint, "wrong", &userInfo))
+}
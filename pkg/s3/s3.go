@@ -0,0 +1,222 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signAlgorithm   = "AWS4-HMAC-SHA256"
+	amzDateFormat   = "20060102T150405Z"
+	scopeDateFormat = "20060102"
+	service         = "s3"
+)
+
+// ErrNotFound the object does not exist
+var ErrNotFound = errors.New("s3 object not found")
+
+// Config S3-compatible storage config
+type Config struct {
+	// Endpoint with scheme, e.g. https://s3.us-east-1.amazonaws.com or http://127.0.0.1:9000
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle use {endpoint}/{bucket}/{key} instead of {bucket}.{endpoint host}/{key}, MinIO requires it
+	PathStyle bool
+}
+
+// Client minimal S3-compatible client which only supports the object operations, requests are signed by AWS signature V4
+type Client struct {
+	conf       Config
+	endpoint   *url.URL
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewClient new s3 client
+func NewClient(conf Config) (*Client, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(conf.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("parse s3 endpoint failed: %w", err)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %s must contain scheme and host", conf.Endpoint)
+	}
+	if len(conf.Bucket) == 0 {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if len(conf.Region) == 0 {
+		conf.Region = "us-east-1"
+	}
+	return &Client{
+		conf:       conf,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: time.Minute},
+		now:        time.Now,
+	}, nil
+}
+
+// ObjectURL the url of object
+func (c *Client) ObjectURL(key string) string {
+	u := *c.endpoint
+	if c.conf.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + c.conf.Bucket + "/" + strings.TrimPrefix(key, "/")
+	} else {
+		u.Host = c.conf.Bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.TrimPrefix(key, "/")
+	}
+	return u.String()
+}
+
+// PutObject upload object
+func (c *Client) PutObject(ctx context.Context, key string, data []byte, contentType string) error {
+	header := http.Header{}
+	if len(contentType) > 0 {
+		header.Set("Content-Type", contentType)
+	}
+	resp, err := c.do(ctx, http.MethodPut, key, header, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// GetObject download object, ErrNotFound is returned if the object does not exist
+func (c *Client) GetObject(ctx context.Context, key string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// HasObject check whether the object exists
+func (c *Client) HasObject(ctx context.Context, key string) (bool, error) {
+	resp, err := c.do(ctx, http.MethodHead, key, nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+func (c *Client) do(ctx context.Context, method, key string, header http.Header, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.ObjectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.ContentLength = int64(len(body))
+	c.sign(req, body, c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s failed: %s %s", method, key, resp.Status, msg)
+}
+
+// sign add the authorization header of AWS signature V4
+func (c *Client) sign(req *http.Request, body []byte, t time.Time) {
+	t = t.UTC()
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", t.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// canonical headers
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if k == "content-type" || k == "content-md5" || k == "range" || strings.HasPrefix(k, "x-amz-") {
+			headers[k] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		encodePath(req.URL.Path),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{t.Format(scopeDateFormat), c.conf.Region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		t.Format(amzDateFormat),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.conf.SecretAccessKey), t.Format(scopeDateFormat))
+	key = hmacSHA256(key, c.conf.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		signAlgorithm, c.conf.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// encodePath uri encode every segment of path as required by signature V4
+func encodePath(p string) string {
+	if len(p) == 0 {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,360 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	xrv "github.com/mattermost/xml-roundtrip-validator"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+const (
+	protocolNS  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	assertionNS = "urn:oasis:names:tc:SAML:2.0:assertion"
+	metadataNS  = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	bindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	bindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	nameIDFormat        = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	statusSuccess       = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	methodBearer        = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	timeFormat          = "2006-01-02T15:04:05Z"
+
+	// maxClockSkew the allowed clock difference between the IdP and SP
+	maxClockSkew = 3 * time.Minute
+)
+
+// ServiceProvider SAML 2.0 service provider which sends the signed AuthnRequest by HTTP-Redirect binding
+// and receives the response by HTTP-POST binding. The response or the assertion must be signed by the IdP,
+// the encrypted assertion is not supported.
+type ServiceProvider struct {
+	// EntityID the entity id of SP, it is usually the url of metadata
+	EntityID string
+	// ACSURL the url of assertion consumer service
+	ACSURL      string
+	Key         *rsa.PrivateKey
+	Certificate *x509.Certificate
+
+	IDPEntityID     string
+	IDPSSOURL       string
+	IDPCertificates []*x509.Certificate
+
+	now func() time.Time
+}
+
+// Assertion the verified assertion of the user
+type Assertion struct {
+	NameID     string
+	Attributes map[string][]string
+}
+
+// GetAttribute get the first value of attribute by name or friendly name
+func (a *Assertion) GetAttribute(name string) string {
+	if values := a.Attributes[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Metadata the metadata xml of SP
+func (sp *ServiceProvider) Metadata() ([]byte, error) {
+	metadata := &entityDescriptor{
+		XMLNS:    metadataNS,
+		EntityID: sp.EntityID,
+		SPSSODescriptor: spSSODescriptor{
+			AuthnRequestsSigned:        true,
+			WantAssertionsSigned:       true,
+			ProtocolSupportEnumeration: protocolNS,
+			KeyDescriptor: keyDescriptor{
+				Use: "signing",
+				KeyInfo: keyInfo{
+					XMLNS:           dsig.Namespace,
+					X509Certificate: base64.StdEncoding.EncodeToString(sp.Certificate.Raw),
+				},
+			},
+			NameIDFormat: nameIDFormat,
+			AssertionConsumerService: indexedEndpoint{
+				Binding:  bindingHTTPPost,
+				Location: sp.ACSURL,
+				Index:    1,
+			},
+		},
+	}
+	data, err := xml.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// AuthnRequestURL the url to redirect the user to the IdP, the returned request id should be
+// saved to check the InResponseTo of response.
+func (sp *ServiceProvider) AuthnRequestURL(relayState string) (redirectURL, requestID string, err error) {
+	requestID, err = newID()
+	if err != nil {
+		return "", "", err
+	}
+	req := &authnRequest{
+		XMLNS:                       protocolNS,
+		ID:                          requestID,
+		Version:                     "2.0",
+		IssueInstant:                sp.getNow().UTC().Format(timeFormat),
+		Destination:                 sp.IDPSSOURL,
+		ProtocolBinding:             bindingHTTPPost,
+		AssertionConsumerServiceURL: sp.ACSURL,
+		Issuer:                      issuer{XMLNS: assertionNS, Value: sp.EntityID},
+		NameIDPolicy:                nameIDPolicy{AllowCreate: true, Format: nameIDFormat},
+	}
+	data, err := xml.Marshal(req)
+	if err != nil {
+		return "", "", err
+	}
+
+	// HTTP-Redirect binding: deflate, base64 and sign the query string
+	var buf bytes.Buffer
+	writer, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err = writer.Write(data); err != nil {
+		return "", "", err
+	}
+	if err = writer.Close(); err != nil {
+		return "", "", err
+	}
+
+	signingContext, err := dsig.NewSigningContext(sp.Key, [][]byte{sp.Certificate.Raw})
+	if err != nil {
+		return "", "", err
+	}
+	query := "SAMLRequest=" + url.QueryEscape(base64.StdEncoding.EncodeToString(buf.Bytes()))
+	if len(relayState) > 0 {
+		query += "&RelayState=" + url.QueryEscape(relayState)
+	}
+	query += "&SigAlg=" + url.QueryEscape(signingContext.GetSignatureMethodIdentifier())
+	signature, err := signingContext.SignString(query)
+	if err != nil {
+		return "", "", err
+	}
+	query += "&Signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(signature))
+
+	sep := "?"
+	if strings.Contains(sp.IDPSSOURL, "?") {
+		sep = "&"
+	}
+	return sp.IDPSSOURL + sep + query, requestID, nil
+}
+
+// ParseResponse verify the base64 encoded SAMLResponse of HTTP-POST binding which responds to the request id,
+// only the data covered by the signature is returned.
+func (sp *ServiceProvider) ParseResponse(samlResponse, requestID string) (assertion *Assertion, err error) {
+	raw, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return nil, fmt.Errorf("decode saml response failed: %w", err)
+	}
+	if err = xrv.Validate(bytes.NewReader(raw)); err != nil {
+		return nil, fmt.Errorf("invalid saml response xml: %w", err)
+	}
+	doc := etree.NewDocument()
+	if err = doc.ReadFromBytes(raw); err != nil {
+		return nil, fmt.Errorf("parse saml response failed: %w", err)
+	}
+	root := doc.Root()
+	if root == nil || root.Tag != "Response" || root.NamespaceURI() != protocolNS {
+		return nil, errors.New("saml response root element is not Response")
+	}
+
+	resp := &response{}
+	if err = xml.Unmarshal(raw, resp); err != nil {
+		return nil, fmt.Errorf("parse saml response failed: %w", err)
+	}
+	if resp.Status.StatusCode.Value != statusSuccess {
+		return nil, fmt.Errorf("saml response status is %s", resp.Status.StatusCode.Value)
+	}
+	if len(resp.Destination) > 0 && resp.Destination != sp.ACSURL {
+		return nil, fmt.Errorf("saml response destination %s is not %s", resp.Destination, sp.ACSURL)
+	}
+
+	assertionEl, err := sp.verifiedAssertion(root)
+	if err != nil {
+		return nil, err
+	}
+	assertionDoc := etree.NewDocument()
+	assertionDoc.SetRoot(assertionEl)
+	assertionData, err := assertionDoc.WriteToBytes()
+	if err != nil {
+		return nil, err
+	}
+	a := &samlAssertion{}
+	if err = xml.Unmarshal(assertionData, a); err != nil {
+		return nil, fmt.Errorf("parse saml assertion failed: %w", err)
+	}
+	if err = sp.validateAssertion(a, requestID); err != nil {
+		return nil, err
+	}
+
+	assertion = &Assertion{
+		NameID:     strings.TrimSpace(a.Subject.NameID.Value),
+		Attributes: make(map[string][]string),
+	}
+	for _, statement := range a.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			values := make([]string, 0, len(attr.Values))
+			for _, v := range attr.Values {
+				values = append(values, strings.TrimSpace(v.Value))
+			}
+			assertion.Attributes[attr.Name] = append(assertion.Attributes[attr.Name], values...)
+			if len(attr.FriendlyName) > 0 && attr.FriendlyName != attr.Name {
+				assertion.Attributes[attr.FriendlyName] = append(assertion.Attributes[attr.FriendlyName], values...)
+			}
+		}
+	}
+	return assertion, nil
+}
+
+// ResponseRequestID get the InResponseTo of the base64 encoded SAMLResponse without verification,
+// it is only used to find the saved request id which is then passed to ParseResponse.
+func ResponseRequestID(samlResponse string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return "", fmt.Errorf("decode saml response failed: %w", err)
+	}
+	resp := &response{}
+	if err = xml.Unmarshal(raw, resp); err != nil {
+		return "", fmt.Errorf("parse saml response failed: %w", err)
+	}
+	if len(resp.InResponseTo) == 0 {
+		return "", errors.New("saml response is not in response to any request")
+	}
+	return resp.InResponseTo, nil
+}
+
+// verifiedAssertion get the assertion covered by the signature of response or assertion itself
+func (sp *ServiceProvider) verifiedAssertion(root *etree.Element) (*etree.Element, error) {
+	certStore := &dsig.MemoryX509CertificateStore{Roots: sp.IDPCertificates}
+	validationContext := dsig.NewDefaultValidationContext(certStore)
+	validationContext.Clock = dsig.NewFakeClockAt(sp.getNow())
+
+	signed := false
+	validatedRoot, err := validationContext.Validate(root)
+	if err == nil {
+		root = validatedRoot
+		signed = true
+	} else if !errors.Is(err, dsig.ErrMissingSignature) {
+		return nil, fmt.Errorf("verify saml response signature failed: %w", err)
+	}
+
+	var assertions []*etree.Element
+	for _, el := range root.ChildElements() {
+		if el.Tag == "EncryptedAssertion" {
+			return nil, errors.New("encrypted saml assertion is not supported")
+		}
+		if el.Tag == "Assertion" && el.NamespaceURI() == assertionNS {
+			assertions = append(assertions, el)
+		}
+	}
+	if len(assertions) != 1 {
+		return nil, fmt.Errorf("saml response should contain exactly one assertion, got %d", len(assertions))
+	}
+
+	nsContext, err := etreeutils.NSBuildParentContext(assertions[0])
+	if err != nil {
+		return nil, err
+	}
+	assertionEl, err := etreeutils.NSDetatch(nsContext, assertions[0])
+	if err != nil {
+		return nil, err
+	}
+	validatedAssertion, err := validationContext.Validate(assertionEl)
+	if err == nil {
+		return validatedAssertion, nil
+	}
+	if signed && errors.Is(err, dsig.ErrMissingSignature) {
+		return assertionEl, nil
+	}
+	if !signed && errors.Is(err, dsig.ErrMissingSignature) {
+		return nil, errors.New("neither saml response nor assertion is signed")
+	}
+	return nil, fmt.Errorf("verify saml assertion signature failed: %w", err)
+}
+
+func (sp *ServiceProvider) validateAssertion(a *samlAssertion, requestID string) error {
+	now := sp.getNow()
+	if a.Issuer.Value != sp.IDPEntityID {
+		return fmt.Errorf("saml assertion issuer %s is not %s", a.Issuer.Value, sp.IDPEntityID)
+	}
+	if len(strings.TrimSpace(a.Subject.NameID.Value)) == 0 {
+		return errors.New("saml assertion has no name id")
+	}
+
+	confirmed := false
+	for _, confirmation := range a.Subject.SubjectConfirmations {
+		data := confirmation.SubjectConfirmationData
+		if confirmation.Method != methodBearer || data == nil {
+			continue
+		}
+		if data.Recipient != sp.ACSURL || data.InResponseTo != requestID {
+			continue
+		}
+		if data.NotOnOrAfter.IsZero() || !now.Before(data.NotOnOrAfter.Add(maxClockSkew)) {
+			continue
+		}
+		confirmed = true
+		break
+	}
+	if !confirmed {
+		return errors.New("saml assertion has no valid bearer subject confirmation")
+	}
+
+	if a.Conditions == nil {
+		return nil
+	}
+	if !a.Conditions.NotBefore.IsZero() && now.Add(maxClockSkew).Before(a.Conditions.NotBefore) {
+		return errors.New("saml assertion is not yet valid")
+	}
+	if !a.Conditions.NotOnOrAfter.IsZero() && !now.Before(a.Conditions.NotOnOrAfter.Add(maxClockSkew)) {
+		return errors.New("saml assertion has expired")
+	}
+	for _, restriction := range a.Conditions.AudienceRestrictions {
+		matched := false
+		for _, audience := range restriction.Audiences {
+			if strings.TrimSpace(audience.Value) == sp.EntityID {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("saml assertion audience does not contain %s", sp.EntityID)
+		}
+	}
+	return nil
+}
+
+func (sp *ServiceProvider) getNow() time.Time {
+	if sp.now != nil {
+		return sp.now()
+	}
+	return time.Now()
+}
+
+// newID the id of xml must not start with digit
+func newID() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "id-" + hex.EncodeToString(b), nil
+}
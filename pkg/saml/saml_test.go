@@ -0,0 +1,206 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testACSURL   = "https://answer.example.com/answer/api/v1/saml/acs"
+	testEntityID = "https://answer.example.com/answer/api/v1/saml/metadata"
+	testIDP      = "https://idp.example.com"
+)
+
+type testIDPKey struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newTestKey(t *testing.T, name string) *testIDPKey {
+	certPEM, keyPEM, err := GenerateKeyPair(name, time.Hour)
+	require.NoError(t, err)
+	cert, err := ParseCertificatePEM(certPEM)
+	require.NoError(t, err)
+	key, err := ParsePrivateKeyPEM(keyPEM)
+	require.NoError(t, err)
+	return &testIDPKey{key: key, cert: cert}
+}
+
+func newTestSP(t *testing.T, idp *testIDPKey, now time.Time) *ServiceProvider {
+	spKey := newTestKey(t, "sp")
+	return &ServiceProvider{
+		EntityID:        testEntityID,
+		ACSURL:          testACSURL,
+		Key:             spKey.key,
+		Certificate:     spKey.cert,
+		IDPEntityID:     testIDP,
+		IDPSSOURL:       testIDP + "/sso",
+		IDPCertificates: []*x509.Certificate{idp.cert},
+		now:             func() time.Time { return now },
+	}
+}
+
+type testAssertionOptions struct {
+	requestID string
+	audience  string
+	notAfter  time.Time
+}
+
+// buildResponse build the response with the assertion signed by the key
+func buildResponse(t *testing.T, signer *testIDPKey, opts testAssertionOptions) *etree.Document {
+	doc := etree.NewDocument()
+	resp := doc.CreateElement("samlp:Response")
+	resp.CreateAttr("xmlns:samlp", protocolNS)
+	resp.CreateAttr("xmlns:saml", assertionNS)
+	resp.CreateAttr("ID", "resp-1")
+	resp.CreateAttr("Destination", testACSURL)
+	resp.CreateAttr("InResponseTo", opts.requestID)
+	resp.CreateElement("saml:Issuer").SetText(testIDP)
+	resp.CreateElement("samlp:Status").CreateElement("samlp:StatusCode").CreateAttr("Value", statusSuccess)
+
+	assertion := etree.NewElement("saml:Assertion")
+	assertion.CreateAttr("ID", "assertion-1")
+	assertion.CreateElement("saml:Issuer").SetText(testIDP)
+	subject := assertion.CreateElement("saml:Subject")
+	subject.CreateElement("saml:NameID").SetText("jane@example.com")
+	confirmation := subject.CreateElement("saml:SubjectConfirmation")
+	confirmation.CreateAttr("Method", methodBearer)
+	confirmationData := confirmation.CreateElement("saml:SubjectConfirmationData")
+	confirmationData.CreateAttr("Recipient", testACSURL)
+	confirmationData.CreateAttr("InResponseTo", opts.requestID)
+	confirmationData.CreateAttr("NotOnOrAfter", opts.notAfter.UTC().Format(timeFormat))
+	conditions := assertion.CreateElement("saml:Conditions")
+	conditions.CreateAttr("NotOnOrAfter", opts.notAfter.UTC().Format(timeFormat))
+	conditions.CreateElement("saml:AudienceRestriction").CreateElement("saml:Audience").SetText(opts.audience)
+	statement := assertion.CreateElement("saml:AttributeStatement")
+	for name, values := range map[string][]string{"email": {"jane@example.com"}, "groups": {"staff", "answer-admins"}} {
+		attr := statement.CreateElement("saml:Attribute")
+		attr.CreateAttr("Name", name)
+		for _, v := range values {
+			attr.CreateElement("saml:AttributeValue").SetText(v)
+		}
+	}
+
+	// sign the assertion in the context of the response, so that the namespaces are declared
+	resp.AddChild(assertion)
+	nsAssertion := assertion.Copy()
+	nsAssertion.CreateAttr("xmlns:saml", assertionNS)
+	signingContext, err := dsig.NewSigningContext(signer.key, [][]byte{signer.cert.Raw})
+	require.NoError(t, err)
+	signingContext.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	signed, err := signingContext.SignEnveloped(nsAssertion)
+	require.NoError(t, err)
+	resp.RemoveChild(assertion)
+	resp.AddChild(signed)
+
+	// reload the document to link the signature to its parent
+	data, err := doc.WriteToBytes()
+	require.NoError(t, err)
+	doc = etree.NewDocument()
+	require.NoError(t, doc.ReadFromBytes(data))
+	return doc
+}
+
+func encodeDoc(t *testing.T, doc *etree.Document) string {
+	data, err := doc.WriteToBytes()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestServiceProvider_ParseResponse(t *testing.T) {
+	now := time.Now()
+	idp := newTestKey(t, "idp")
+	sp := newTestSP(t, idp, now)
+	opts := testAssertionOptions{requestID: "id-req", audience: testEntityID, notAfter: now.Add(5 * time.Minute)}
+
+	requestID, err := ResponseRequestID(encodeDoc(t, buildResponse(t, idp, opts)))
+	require.NoError(t, err)
+	assert.Equal(t, "id-req", requestID)
+
+	assertion, err := sp.ParseResponse(encodeDoc(t, buildResponse(t, idp, opts)), "id-req")
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", assertion.NameID)
+	assert.Equal(t, "jane@example.com", assertion.GetAttribute("email"))
+	assert.Equal(t, []string{"staff", "answer-admins"}, assertion.Attributes["groups"])
+
+	// the response of another request
+	_, err = sp.ParseResponse(encodeDoc(t, buildResponse(t, idp, opts)), "id-other")
+	assert.Error(t, err)
+
+	// signed by the untrusted key
+	_, err = sp.ParseResponse(encodeDoc(t, buildResponse(t, newTestKey(t, "evil"), opts)), "id-req")
+	assert.Error(t, err)
+
+	// the assertion is modified after signed
+	doc := buildResponse(t, idp, opts)
+	doc.FindElement("//NameID").SetText("admin@example.com")
+	_, err = sp.ParseResponse(encodeDoc(t, doc), "id-req")
+	assert.Error(t, err)
+
+	// the signature is removed
+	doc = buildResponse(t, idp, opts)
+	sig := doc.FindElement("//Signature")
+	sig.Parent().RemoveChild(sig)
+	_, err = sp.ParseResponse(encodeDoc(t, doc), "id-req")
+	assert.Error(t, err)
+
+	// the assertion for another SP
+	_, err = sp.ParseResponse(encodeDoc(t, buildResponse(t, idp, testAssertionOptions{
+		requestID: "id-req", audience: "https://other.example.com", notAfter: now.Add(5 * time.Minute)})), "id-req")
+	assert.Error(t, err)
+
+	// expired assertion
+	_, err = sp.ParseResponse(encodeDoc(t, buildResponse(t, idp, testAssertionOptions{
+		requestID: "id-req", audience: testEntityID, notAfter: now.Add(-5 * time.Minute)})), "id-req")
+	assert.Error(t, err)
+}
+
+func TestServiceProvider_AuthnRequestURL(t *testing.T) {
+	idp := newTestKey(t, "idp")
+	sp := newTestSP(t, idp, time.Now())
+
+	redirectURL, requestID, err := sp.AuthnRequestURL("state")
+	require.NoError(t, err)
+	u, err := url.Parse(redirectURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/sso", u.Path)
+	assert.Equal(t, "state", u.Query().Get("RelayState"))
+
+	// the signature covers the query string before Signature
+	signed := u.RawQuery[:strings.Index(u.RawQuery, "&Signature=")]
+	signature, err := base64.StdEncoding.DecodeString(u.Query().Get("Signature"))
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(signed))
+	assert.NoError(t, rsa.VerifyPKCS1v15(&sp.Key.PublicKey, crypto.SHA256, digest[:], signature))
+
+	deflated, err := base64.StdEncoding.DecodeString(u.Query().Get("SAMLRequest"))
+	require.NoError(t, err)
+	data, err := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	require.NoError(t, err)
+	req := &authnRequest{}
+	require.NoError(t, xml.Unmarshal(data, req))
+	assert.Equal(t, requestID, req.ID)
+	assert.Equal(t, testACSURL, req.AssertionConsumerServiceURL)
+	assert.Equal(t, testEntityID, req.Issuer.Value)
+
+	metadata, err := sp.Metadata()
+	require.NoError(t, err)
+	assert.Contains(t, string(metadata), `Location="`+testACSURL+`"`)
+	assert.Contains(t, string(metadata), `AuthnRequestsSigned="true"`)
+}
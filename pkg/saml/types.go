@@ -0,0 +1,116 @@
+package saml
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type entityDescriptor struct {
+	XMLName         xml.Name        `xml:"EntityDescriptor"`
+	XMLNS           string          `xml:"xmlns,attr"`
+	EntityID        string          `xml:"entityID,attr"`
+	SPSSODescriptor spSSODescriptor `xml:"SPSSODescriptor"`
+}
+
+type spSSODescriptor struct {
+	AuthnRequestsSigned        bool            `xml:"AuthnRequestsSigned,attr"`
+	WantAssertionsSigned       bool            `xml:"WantAssertionsSigned,attr"`
+	ProtocolSupportEnumeration string          `xml:"protocolSupportEnumeration,attr"`
+	KeyDescriptor              keyDescriptor   `xml:"KeyDescriptor"`
+	NameIDFormat               string          `xml:"NameIDFormat"`
+	AssertionConsumerService   indexedEndpoint `xml:"AssertionConsumerService"`
+}
+
+type keyDescriptor struct {
+	Use     string  `xml:"use,attr"`
+	KeyInfo keyInfo `xml:"KeyInfo"`
+}
+
+type keyInfo struct {
+	XMLNS           string `xml:"xmlns,attr"`
+	X509Certificate string `xml:"X509Data>X509Certificate"`
+}
+
+type indexedEndpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+	Index    int    `xml:"index,attr"`
+}
+
+type authnRequest struct {
+	XMLName                     xml.Name     `xml:"AuthnRequest"`
+	XMLNS                       string       `xml:"xmlns,attr"`
+	ID                          string       `xml:"ID,attr"`
+	Version                     string       `xml:"Version,attr"`
+	IssueInstant                string       `xml:"IssueInstant,attr"`
+	Destination                 string       `xml:"Destination,attr"`
+	ProtocolBinding             string       `xml:"ProtocolBinding,attr"`
+	AssertionConsumerServiceURL string       `xml:"AssertionConsumerServiceURL,attr"`
+	Issuer                      issuer       `xml:"Issuer"`
+	NameIDPolicy                nameIDPolicy `xml:"NameIDPolicy"`
+}
+
+type issuer struct {
+	XMLNS string `xml:"xmlns,attr"`
+	Value string `xml:",chardata"`
+}
+
+type nameIDPolicy struct {
+	AllowCreate bool   `xml:"AllowCreate,attr"`
+	Format      string `xml:"Format,attr"`
+}
+
+type response struct {
+	XMLName      xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:protocol Response"`
+	Destination  string   `xml:"Destination,attr"`
+	InResponseTo string   `xml:"InResponseTo,attr"`
+	Status       struct {
+		StatusCode struct {
+			Value string `xml:"Value,attr"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:protocol StatusCode"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:protocol Status"`
+}
+
+type samlAssertion struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:assertion Assertion"`
+	Issuer  struct {
+		Value string `xml:",chardata"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+	Subject struct {
+		NameID struct {
+			Value string `xml:",chardata"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:assertion NameID"`
+		SubjectConfirmations []subjectConfirmation `xml:"urn:oasis:names:tc:SAML:2.0:assertion SubjectConfirmation"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:assertion Subject"`
+	Conditions          *conditions          `xml:"urn:oasis:names:tc:SAML:2.0:assertion Conditions"`
+	AttributeStatements []attributeStatement `xml:"urn:oasis:names:tc:SAML:2.0:assertion AttributeStatement"`
+}
+
+type subjectConfirmation struct {
+	Method                  string `xml:"Method,attr"`
+	SubjectConfirmationData *struct {
+		Recipient    string    `xml:"Recipient,attr"`
+		InResponseTo string    `xml:"InResponseTo,attr"`
+		NotOnOrAfter time.Time `xml:"NotOnOrAfter,attr"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:assertion SubjectConfirmationData"`
+}
+
+type conditions struct {
+	NotBefore            time.Time `xml:"NotBefore,attr"`
+	NotOnOrAfter         time.Time `xml:"NotOnOrAfter,attr"`
+	AudienceRestrictions []struct {
+		Audiences []struct {
+			Value string `xml:",chardata"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:assertion Audience"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:assertion AudienceRestriction"`
+}
+
+type attributeStatement struct {
+	Attributes []struct {
+		Name         string `xml:"Name,attr"`
+		FriendlyName string `xml:"FriendlyName,attr"`
+		Values       []struct {
+			Value string `xml:",chardata"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:assertion AttributeValue"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:assertion Attribute"`
+}
@@ -0,0 +1,69 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords common english words which are too frequent to be useful in search
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "how": true, "in": true, "is": true, "it": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "this": true, "to": true, "was": true, "what": true,
+	"when": true, "where": true, "which": true, "who": true, "why": true, "will": true, "with": true,
+}
+
+// Tokenize split text into lower case terms.
+// Latin words are split by spaces and punctuations, but keep the characters like `c++`, `c#` and `node.js`.
+// CJK characters have no spaces between words, so they are split into overlapping bigrams.
+func Tokenize(text string) (terms []string) {
+	var (
+		runes = []rune(strings.ToLower(text))
+		word  []rune
+		cjk   []rune
+	)
+	flushWord := func() {
+		w := strings.Trim(string(word), ".-")
+		if len(w) > 0 && !stopWords[w] {
+			terms = append(terms, w)
+		}
+		word = word[:0]
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 {
+			terms = append(terms, string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			terms = append(terms, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for i, r := range runes {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#' || r == '_':
+			flushCJK()
+			word = append(word, r)
+		case (r == '.' || r == '-') && len(word) > 0 && i+1 < len(runes) && isWordRune(runes[i+1]):
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return terms
+}
+
+func isWordRune(r rune) bool {
+	return !isCJK(r) && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
@@ -0,0 +1,22 @@
+package tokenizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	// test english string
+	assert.Equal(t, []string{"install", "answer", "docker"}, Tokenize("How to install Answer with Docker?"))
+
+	// test programming words
+	assert.Equal(t, []string{"c++", "c#", "node.js", "go-sql-driver"}, Tokenize("C++, C# and node.js, go-sql-driver."))
+
+	// test chinese string
+	assert.Equal(t, []string{"安装", "装问", "问答"}, Tokenize("安装问答"))
+	assert.Equal(t, []string{"答", "answer"}, Tokenize("答 answer"))
+
+	// test empty string
+	assert.Empty(t, Tokenize(" ,.!? "))
+}
@@ -0,0 +1,132 @@
+package twofactor
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// period the time step of TOTP in seconds
+	period = 30
+	// skew the number of time steps before and after the current one which are accepted
+	skew = 1
+
+	qrCodeSize         = 200
+	recoveryCodeLength = 10
+	// recoveryCodeAlphabet base32 without the characters which are easily confused
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var validateOpts = totp.ValidateOpts{
+	Period:    period,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// Key the TOTP secret and provisioning uri
+type Key struct {
+	Secret string
+	// URI the otpauth uri
+	URI string
+	// QRCode the data uri of png image of uri
+	QRCode string
+}
+
+// GenerateKey generate a new TOTP secret, the issuer and account name are shown in the authenticator app
+func GenerateKey(issuer, accountName string) (*Key, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: accountName,
+		Period:      period,
+		Digits:      validateOpts.Digits,
+		Algorithm:   validateOpts.Algorithm,
+	})
+	if err != nil {
+		return nil, err
+	}
+	img, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return &Key{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// ValidateCode validate the code at time t, only the time steps after lastUsedStep are accepted so that
+// the code can not be replayed. The matched time step is returned and should be saved as the new lastUsedStep.
+func ValidateCode(secret, code string, t time.Time, lastUsedStep int64) (step int64, ok bool) {
+	code = normalizeCode(code)
+	current := t.Unix() / period
+	for step = current - skew; step <= current+skew; step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*period, 0), validateOpts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes generate the recovery codes shown to the user and their hashes to save
+func GenerateRecoveryCodes(n int) (codes, hashes []string, err error) {
+	for i := 0; i < n; i++ {
+		b := make([]byte, recoveryCodeLength)
+		if _, err = rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		for j := range b {
+			b[j] = recoveryCodeAlphabet[int(b[j])%len(recoveryCodeAlphabet)]
+		}
+		code := string(b[:recoveryCodeLength/2]) + "-" + string(b[recoveryCodeLength/2:])
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode the recovery code is random enough to be hashed without salt,
+// the case, spaces and dashes are ignored.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(normalizeCode(code))
+	code = strings.ReplaceAll(code, "-", "")
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchRecoveryCode find the index of the hash of code, -1 if not found
+func MatchRecoveryCode(hashes []string, code string) int {
+	hashed := HashRecoveryCode(code)
+	index := -1
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hashed)) == 1 {
+			index = i
+		}
+	}
+	return index
+}
+
+func normalizeCode(code string) string {
+	return strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+}
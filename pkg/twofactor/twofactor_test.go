@@ -0,0 +1,66 @@
+package twofactor
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateKey(t *testing.T) {
+	key, err := GenerateKey("Answer", "jane@example.com")
+	require.NoError(t, err)
+	assert.NotEmpty(t, key.Secret)
+	assert.True(t, strings.HasPrefix(key.QRCode, "data:image/png;base64,"))
+
+	u, err := url.Parse(key.URI)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "Answer", u.Query().Get("issuer"))
+	assert.Equal(t, key.Secret, u.Query().Get("secret"))
+}
+
+func TestValidateCode(t *testing.T) {
+	key, err := GenerateKey("Answer", "jane@example.com")
+	require.NoError(t, err)
+	now := time.Now()
+	code, err := totp.GenerateCodeCustom(key.Secret, now, validateOpts)
+	require.NoError(t, err)
+
+	step, ok := ValidateCode(key.Secret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/period, step)
+
+	// the clock of device is a little behind
+	_, ok = ValidateCode(key.Secret, code, now.Add(period*time.Second), 0)
+	assert.True(t, ok)
+	_, ok = ValidateCode(key.Secret, code, now.Add(5*period*time.Second), 0)
+	assert.False(t, ok)
+
+	// the used code can not be replayed
+	_, ok = ValidateCode(key.Secret, code, now, step)
+	assert.False(t, ok)
+
+	_, ok = ValidateCode(key.Secret, "000000", now, 0)
+	if code != "000000" {
+		assert.False(t, ok)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+	assert.Len(t, codes, 10)
+	assert.Len(t, hashes, 10)
+	assert.Len(t, codes[0], recoveryCodeLength+1)
+
+	assert.Equal(t, 3, MatchRecoveryCode(hashes, codes[3]))
+	assert.Equal(t, 3, MatchRecoveryCode(hashes, " "+strings.ToUpper(codes[3])))
+	assert.Equal(t, 3, MatchRecoveryCode(hashes, strings.ReplaceAll(codes[3], "-", "")))
+	assert.Equal(t, -1, MatchRecoveryCode(hashes, "aaaaa-aaaaa"))
+}
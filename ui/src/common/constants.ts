@@ -49,6 +49,12 @@ export const ADMIN_NAV_MENUS = [
   {
     name: 'users',
   },
+  {
+    name: 'roles',
+  },
+  {
+    name: 'badges',
+  },
   {
     name: 'flags',
     // badgeContent: 5,
@@ -75,6 +81,8 @@ export const ADMIN_NAV_MENUS = [
       { name: 'write' },
       { name: 'seo' },
       { name: 'login' },
+      { name: 'webhooks' },
+      { name: 'privileges' },
     ],
   },
 ];
@@ -579,4 +587,17 @@ export const TIMELINE_NORMAL_ACTIVITY_TYPE = [
   'upvote',
   'reopened',
   'closed',
+  'bounty',
 ];
+
+export const BADGE_LEVELS = {
+  1: 'bronze',
+  2: 'silver',
+  3: 'gold',
+};
+
+export const BADGE_LEVEL_COLORS = {
+  1: '#cd7f32',
+  2: '#a8a9ad',
+  3: '#d4af37',
+};
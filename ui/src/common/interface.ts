@@ -116,6 +116,15 @@ export interface UserInfoBase {
   is_admin?: boolean;
 }
 
+export interface UserBadgeSummary {
+  id: string;
+  name: string;
+  description: string;
+  /** 1 bronze 2 silver 3 gold */
+  level: number;
+  count: number;
+}
+
 export interface UserInfoRes extends UserInfoBase {
   bio: string;
   bio_html: string;
@@ -128,9 +137,154 @@ export interface UserInfoRes extends UserInfoBase {
   language: string;
   is_admin: boolean;
   e_mail?: string;
+  badges?: UserBadgeSummary[];
   [prop: string]: any;
 }
 
+export interface TwoFactorLoginReq {
+  two_factor_token: string;
+  code: string;
+}
+
+export interface TwoFactorLoginRes extends UserInfoRes {
+  recovery_codes?: string[];
+}
+
+export interface TwoFactorEnrollRes {
+  secret: string;
+  provisioning_uri: string;
+  qr_code: string;
+}
+
+export interface TwoFactorStatusRes {
+  enabled: boolean;
+  required: boolean;
+  recovery_codes_remaining: number;
+}
+
+export interface TwoFactorRecoveryCodesRes {
+  recovery_codes: string[];
+}
+
+export interface AddAccessTokenReq {
+  name: string;
+  scopes: string[];
+  expires_in_days: number;
+}
+
+export interface AccessTokenItem {
+  id: string;
+  name: string;
+  token_prefix: string;
+  scopes: string[];
+  created_at: number;
+  expired_at: number;
+  last_used_at: number;
+}
+
+export interface WebhookReq {
+  id?: string;
+  name: string;
+  url: string;
+  secret: string;
+  events: string[];
+  tags: string[];
+  enabled: boolean;
+}
+
+export interface WebhookItem extends Omit<WebhookReq, 'secret'> {
+  id: string;
+  /** the last characters of secret, the secret itself is never returned */
+  secret_hint: string;
+  created_at: number;
+}
+
+export interface AddWebhookRes {
+  id: string;
+  secret: string;
+}
+
+export interface RankConfigItem {
+  key: string;
+  value: number;
+}
+
+export interface AdminSettingsPrivilege {
+  /** the reputation required by the action, -1 means only the roles with the power */
+  privileges: RankConfigItem[];
+  /** the reputation earned or lost by the activity */
+  reputations: RankConfigItem[];
+  /** the daily reputation limit and the serial votes limit, 0 means no limit */
+  limits: RankConfigItem[];
+}
+
+export interface RoleItem {
+  id: number;
+  name: string;
+  description: string;
+}
+
+export interface RolePowerItem {
+  power_type: string;
+  name: string;
+  description: string;
+  /** the role has the power everywhere */
+  granted: boolean;
+  /** the role only has the power on the questions with these tags */
+  tags: { slug_name: string; display_name: string }[];
+}
+
+export interface RolePowerReq {
+  role_id: number;
+  powers: { power_type: string; tags: string[] }[];
+}
+
+export interface BadgeReq {
+  id?: string;
+  name: string;
+  description: string;
+  /** 1 bronze 2 silver 3 gold */
+  level: number;
+  /** awarded once for each question or tag meeting the rule */
+  repeatable: boolean;
+  rule: string;
+  threshold: number;
+  enabled: boolean;
+}
+
+export interface BadgeItem extends BadgeReq {
+  id: string;
+  award_count: number;
+}
+
+export interface UserBadgeItem {
+  id: string;
+  badge_id: string;
+  badge_name: string;
+  badge_level: number;
+  object_id: string;
+  user_info: UserInfoBase;
+  /** awarded by admin */
+  manual: boolean;
+  created_at: number;
+}
+
+export interface WebhookDeliveryItem {
+  id: string;
+  webhook_id: string;
+  event: string;
+  payload: string;
+  /** 1 pending 2 succeeded 3 failed */
+  status: number;
+  attempts: number;
+  response_status: number;
+  response_body: string;
+  error: string;
+  duration: number;
+  created_at: number;
+  delivered_at: number;
+}
+
 export type UploadType = 'post' | 'avatar' | 'branding';
 export interface UploadReq {
   file: FormData;
@@ -159,6 +313,14 @@ export interface SetNoticeReq {
   notice_switch: boolean;
 }
 
+export type NoticeFrequency = 'immediate' | 'daily' | 'weekly' | 'off';
+
+export interface NoticeConfig {
+  new_answer: NoticeFrequency;
+  new_comment: NoticeFrequency;
+  followed_tags: Exclude<NoticeFrequency, 'immediate'>;
+}
+
 export interface NotificationStatus {
   inbox: number;
   achievement: number;
@@ -166,6 +328,38 @@ export interface NotificationStatus {
   can_revision: boolean;
 }
 
+export interface NotificationStreamReq {
+  /** the question being viewed, its vote and answer counts are pushed */
+  question_id?: string;
+}
+
+export interface NotificationStreamVote {
+  object_id: string;
+  up_votes: number;
+  down_votes: number;
+  votes: number;
+}
+
+export interface NotificationStreamHandlers {
+  red_dot?: (data: NotificationStatus) => void;
+  notification?: (data: any) => void;
+  vote?: (data: NotificationStreamVote) => void;
+  answer_count?: (data: { question_id: string; answer_count: number }) => void;
+}
+
+export interface QuestionBounty {
+  id: string;
+  amount: number;
+  created_at: number;
+  expired_at: number;
+  user_info: UserInfoBase;
+}
+
+export interface StartBountyReq {
+  question_id: string;
+  amount: number;
+}
+
 export interface QuestionDetailRes {
   id: string;
   title: string;
@@ -230,7 +424,8 @@ export type QuestionOrderBy =
   | 'active'
   | 'frequent'
   | 'score'
-  | 'unanswered';
+  | 'unanswered'
+  | 'featured';
 
 export interface QueryQuestionsReq extends Paging {
   order: QuestionOrderBy;
@@ -308,6 +503,7 @@ export interface AdminSettingsSmtp {
   smtp_port: number;
   smtp_username?: string;
   test_email_recipient?: string;
+  reply_address?: string;
 }
 
 export interface SiteSettings {
@@ -371,6 +567,9 @@ export interface AdminSettingsCustom {
 export interface AdminSettingsLogin {
   allow_new_registrations: boolean;
   login_required: boolean;
+  disallow_password_login?: boolean;
+  require_two_factor_admin?: boolean;
+  require_two_factor_moderator?: boolean;
 }
 
 /**
@@ -47,6 +47,13 @@ const Index: FC<Props> = ({ className, data }) => {
     }
   }, []);
 
+  useEffect(() => {
+    // the vote count may be pushed by the notification stream
+    if (data) {
+      setVotes(data.votesCount);
+    }
+  }, [data?.votesCount]);
+
   const handleVote = (type: 'up' | 'down') => {
     if (!tryNormalLogged(true)) {
       return;
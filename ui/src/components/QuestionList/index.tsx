@@ -22,6 +22,7 @@ const QuestionOrderKeys: Type.QuestionOrderBy[] = [
   'frequent',
   'score',
   'unanswered',
+  'featured',
 ];
 
 interface Props {
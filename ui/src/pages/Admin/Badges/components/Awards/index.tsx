@@ -0,0 +1,165 @@
+import { FC, FormEvent, useState } from 'react';
+import { Table, Button, Form, Badge } from 'react-bootstrap';
+import { useSearchParams } from 'react-router-dom';
+import { useTranslation } from 'react-i18next';
+
+import {
+  FormatTime,
+  Pagination,
+  Empty,
+  Modal,
+  BaseUserCard,
+} from '@/components';
+import type * as Type from '@/common/interface';
+import { useToast } from '@/hooks';
+import { useQueryUserBadges, awardBadge, revokeBadge } from '@/services';
+
+const PAGE_SIZE = 20;
+
+interface IProps {
+  badges: Type.BadgeItem[];
+}
+
+const Index: FC<IProps> = ({ badges }) => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'admin.badges.awards',
+  });
+  const [urlSearchParams, setUrlSearchParams] = useSearchParams();
+  const curPage = Number(urlSearchParams.get('page') || '1');
+  const curUsername = urlSearchParams.get('username') || '';
+  const Toast = useToast();
+  const [badgeID, setBadgeID] = useState(badges[0]?.id || '');
+  const [username, setUsername] = useState('');
+  const [errorMsg, setErrorMsg] = useState('');
+  const { data, mutate } = useQueryUserBadges({
+    username: curUsername || undefined,
+    page: curPage,
+    page_size: PAGE_SIZE,
+  });
+
+  const handleAward = (evt: FormEvent) => {
+    evt.preventDefault();
+    if (!badgeID || !username) {
+      setErrorMsg(t('required'));
+      return;
+    }
+    awardBadge({ badge_id: badgeID, username })
+      .then(() => {
+        Toast.onShow({
+          msg: t('awarded'),
+          variant: 'success',
+        });
+        setErrorMsg('');
+        setUsername('');
+        mutate();
+      })
+      .catch((err) => {
+        setErrorMsg(err?.msg || '');
+      });
+  };
+
+  const handleRevoke = (item: Type.UserBadgeItem) => {
+    Modal.confirm({
+      title: t('title'),
+      content: t('revoke_confirm', {
+        badge: item.badge_name,
+        user: item.user_info?.display_name,
+      }),
+      cancelBtnVariant: 'link',
+      confirmBtnVariant: 'danger',
+      confirmText: t('revoke_btn'),
+      onConfirm: () => {
+        revokeBadge(item.id).then(() => {
+          mutate();
+        });
+      },
+    });
+  };
+
+  return (
+    <>
+      <h5 className="mb-3">{t('title')}</h5>
+      <Form noValidate onSubmit={handleAward} className="d-flex mb-3">
+        <Form.Select
+          className="w-auto me-2"
+          value={badgeID}
+          onChange={(e) => setBadgeID(e.target.value)}>
+          {badges.map((badge) => (
+            <option key={badge.id} value={badge.id}>
+              {badge.name}
+            </option>
+          ))}
+        </Form.Select>
+        <Form.Control
+          className="w-auto me-2"
+          placeholder={t('username')}
+          value={username}
+          onChange={(e) => setUsername(e.target.value)}
+        />
+        <Button type="submit" variant="outline-secondary">
+          {t('award_btn')}
+        </Button>
+      </Form>
+      {errorMsg && <p className="text-danger">{errorMsg}</p>}
+      <Form.Control
+        size="sm"
+        className="w-auto mb-3"
+        placeholder={t('filter')}
+        defaultValue={curUsername}
+        onKeyDown={(e) => {
+          if (e.key === 'Enter') {
+            setUrlSearchParams({ username: e.currentTarget.value });
+          }
+        }}
+      />
+      <Table responsive>
+        <thead>
+          <tr>
+            <th>{t('user')}</th>
+            <th>{t('badge')}</th>
+            <th>{t('created_at')}</th>
+            <th />
+          </tr>
+        </thead>
+        <tbody>
+          {data?.list.map((item) => (
+            <tr key={item.id}>
+              <td>
+                <BaseUserCard data={item.user_info} />
+              </td>
+              <td>
+                {item.badge_name}
+                {item.manual && (
+                  <Badge bg="secondary" className="ms-2">
+                    {t('manual')}
+                  </Badge>
+                )}
+              </td>
+              <td>
+                <FormatTime time={item.created_at} />
+              </td>
+              <td className="text-end">
+                <Button
+                  variant="link"
+                  className="p-0 text-danger"
+                  onClick={() => handleRevoke(item)}>
+                  {t('revoke_btn')}
+                </Button>
+              </td>
+            </tr>
+          ))}
+        </tbody>
+      </Table>
+      {Number(data?.count) <= 0 && <Empty />}
+      <div className="mt-4 mb-2 d-flex justify-content-center">
+        <Pagination
+          currentPage={curPage}
+          totalSize={data?.count || 0}
+          pageSize={PAGE_SIZE}
+        />
+      </div>
+    </>
+  );
+};
+
+export default Index;
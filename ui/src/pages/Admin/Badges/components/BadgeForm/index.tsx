@@ -0,0 +1,145 @@
+import { FC, FormEvent, useState } from 'react';
+import { Form, Button } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+
+import type * as Type from '@/common/interface';
+import { BADGE_LEVELS } from '@/common/constants';
+
+const RULES = [
+  'manual',
+  'accepted_answers',
+  'tag_upvoted_answers',
+  'visit_days',
+  'question_votes',
+];
+
+// the rules awarded for each question or tag
+const REPEATABLE_RULES = ['tag_upvoted_answers', 'question_votes'];
+
+interface IProps {
+  data?: Type.BadgeItem;
+  onSubmit: (data: Type.BadgeReq) => Promise<any>;
+  onCancel: () => void;
+}
+
+const Index: FC<IProps> = ({ data, onSubmit, onCancel }) => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'admin.badges.form',
+  });
+  const [name, setName] = useState(data?.name || '');
+  const [description, setDescription] = useState(data?.description || '');
+  const [level, setLevel] = useState(data?.level || 1);
+  const [rule, setRule] = useState(data?.rule || 'manual');
+  const [threshold, setThreshold] = useState(data?.threshold || 0);
+  const [repeatable, setRepeatable] = useState(data?.repeatable || false);
+  const [enabled, setEnabled] = useState(data ? data.enabled : true);
+  const [errorMsg, setErrorMsg] = useState('');
+
+  const handleSubmit = (evt: FormEvent) => {
+    evt.preventDefault();
+    if (!name) {
+      setErrorMsg(t('required'));
+      return;
+    }
+    onSubmit({
+      id: data?.id,
+      name,
+      description,
+      level,
+      rule,
+      threshold: rule === 'manual' ? 0 : threshold,
+      repeatable: REPEATABLE_RULES.includes(rule) && repeatable,
+      enabled,
+    }).catch((err) => {
+      setErrorMsg(err?.msg || '');
+    });
+  };
+
+  return (
+    <Form noValidate onSubmit={handleSubmit} className="mb-4">
+      <Form.Group controlId="badge_name" className="mb-3">
+        <Form.Label>{t('name.label')}</Form.Label>
+        <Form.Control
+          required
+          maxLength={100}
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+        />
+      </Form.Group>
+      <Form.Group controlId="badge_description" className="mb-3">
+        <Form.Label>{t('description.label')}</Form.Label>
+        <Form.Control
+          maxLength={500}
+          value={description}
+          onChange={(e) => setDescription(e.target.value)}
+        />
+      </Form.Group>
+      <Form.Group controlId="badge_level" className="mb-3">
+        <Form.Label>{t('level.label')}</Form.Label>
+        <Form.Select
+          value={level}
+          onChange={(e) => setLevel(Number(e.target.value))}>
+          {Object.keys(BADGE_LEVELS).map((key) => (
+            <option key={key} value={key}>
+              {t(`badge_level.${BADGE_LEVELS[key]}`, {
+                keyPrefix: 'personal',
+              })}
+            </option>
+          ))}
+        </Form.Select>
+      </Form.Group>
+      <Form.Group controlId="badge_rule" className="mb-3">
+        <Form.Label>{t('rule.label')}</Form.Label>
+        <Form.Select value={rule} onChange={(e) => setRule(e.target.value)}>
+          {RULES.map((key) => (
+            <option key={key} value={key}>
+              {t(`rules.${key}`, { keyPrefix: 'admin.badges' })}
+            </option>
+          ))}
+        </Form.Select>
+      </Form.Group>
+      {rule !== 'manual' && (
+        <Form.Group controlId="badge_threshold" className="mb-3">
+          <Form.Label>{t('threshold.label')}</Form.Label>
+          <Form.Control
+            type="number"
+            min={0}
+            value={threshold}
+            onChange={(e) => setThreshold(Number(e.target.value))}
+          />
+          <Form.Text>{t('threshold.text')}</Form.Text>
+        </Form.Group>
+      )}
+      {REPEATABLE_RULES.includes(rule) && (
+        <Form.Group className="mb-3">
+          <Form.Check
+            id="badge_repeatable"
+            type="checkbox"
+            label={t('repeatable.label')}
+            checked={repeatable}
+            onChange={(e) => setRepeatable(e.target.checked)}
+          />
+          <Form.Text>{t('repeatable.text')}</Form.Text>
+        </Form.Group>
+      )}
+      <Form.Group className="mb-3">
+        <Form.Check
+          id="badge_enabled"
+          type="switch"
+          label={t('enabled.label')}
+          checked={enabled}
+          onChange={(e) => setEnabled(e.target.checked)}
+        />
+      </Form.Group>
+      {errorMsg && <p className="text-danger">{errorMsg}</p>}
+      <Button type="submit" variant="primary" className="me-2">
+        {t('save', { keyPrefix: 'btns' })}
+      </Button>
+      <Button variant="link" onClick={onCancel}>
+        {t('cancel', { keyPrefix: 'btns' })}
+      </Button>
+    </Form>
+  );
+};
+
+export default Index;
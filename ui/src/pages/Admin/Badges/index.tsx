@@ -0,0 +1,110 @@
+import { FC, useState } from 'react';
+import { Table, Button, Badge } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+
+import type * as Type from '@/common/interface';
+import { BADGE_LEVELS, BADGE_LEVEL_COLORS } from '@/common/constants';
+import { useToast } from '@/hooks';
+import { useQueryBadges, addBadge, updateBadge } from '@/services';
+
+import BadgeForm from './components/BadgeForm';
+import Awards from './components/Awards';
+
+const Badges: FC = () => {
+  const { t } = useTranslation('translation', { keyPrefix: 'admin.badges' });
+  const Toast = useToast();
+  const { data, mutate } = useQueryBadges();
+  // the badge being edited, an empty object when adding a new one
+  const [editing, setEditing] = useState<Type.BadgeItem | {} | null>(null);
+
+  const handleSubmit = (params: Type.BadgeReq) => {
+    const req = params.id ? updateBadge(params) : addBadge(params);
+    return req.then(() => {
+      Toast.onShow({
+        msg: t('update', { keyPrefix: 'toast' }),
+        variant: 'success',
+      });
+      setEditing(null);
+      mutate();
+    });
+  };
+
+  return (
+    <>
+      <h3 className="mb-4">{t('title')}</h3>
+      <p className="text-secondary">{t('text')}</p>
+      {editing ? (
+        <BadgeForm
+          data={'id' in editing ? editing : undefined}
+          onSubmit={handleSubmit}
+          onCancel={() => setEditing(null)}
+        />
+      ) : (
+        <Button
+          variant="outline-secondary"
+          className="mb-4"
+          onClick={() => setEditing({})}>
+          {t('add_btn')}
+        </Button>
+      )}
+      {data && data.length > 0 && (
+        <Table responsive className="mb-5">
+          <thead>
+            <tr>
+              <th>{t('name')}</th>
+              <th>{t('rule')}</th>
+              <th>{t('award_count')}</th>
+              <th>{t('status')}</th>
+              <th />
+            </tr>
+          </thead>
+          <tbody>
+            {data.map((item) => (
+              <tr key={item.id}>
+                <td>
+                  <i
+                    className="br bi-award-fill me-1"
+                    style={{ color: BADGE_LEVEL_COLORS[item.level] }}
+                  />
+                  {item.name}
+                  <span className="small text-secondary ms-2">
+                    {t(`badge_level.${BADGE_LEVELS[item.level]}`, {
+                      keyPrefix: 'personal',
+                    })}
+                  </span>
+                  <div className="small text-secondary">
+                    {item.description}
+                  </div>
+                </td>
+                <td className="small">
+                  {t(`rules.${item.rule}`)}
+                  {item.rule !== 'manual' && ` (${item.threshold})`}
+                  {item.repeatable && (
+                    <div className="text-secondary">{t('repeatable')}</div>
+                  )}
+                </td>
+                <td>{item.award_count}</td>
+                <td>
+                  <Badge bg={item.enabled ? 'success' : 'secondary'}>
+                    {item.enabled ? t('enabled') : t('disabled')}
+                  </Badge>
+                </td>
+                <td className="text-end">
+                  <Button
+                    variant="link"
+                    className="p-0"
+                    onClick={() => setEditing(item)}>
+                    {t('edit_btn')}
+                  </Button>
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      )}
+      {data && data.length > 0 && <Awards badges={data} />}
+    </>
+  );
+};
+
+export default Badges;
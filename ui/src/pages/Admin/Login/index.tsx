@@ -30,6 +30,18 @@ const Index: FC = () => {
         description: t('private.text'),
         default: false,
       },
+      require_two_factor_admin: {
+        type: 'boolean',
+        title: t('two_factor.title'),
+        label: t('two_factor.admin_label'),
+        default: false,
+      },
+      require_two_factor_moderator: {
+        type: 'boolean',
+        label: t('two_factor.moderator_label'),
+        description: t('two_factor.text'),
+        default: false,
+      },
     },
   };
   const uiSchema: UISchema = {
@@ -39,8 +51,16 @@ const Index: FC = () => {
     login_required: {
       'ui:widget': 'switch',
     },
+    require_two_factor_admin: {
+      'ui:widget': 'switch',
+    },
+    require_two_factor_moderator: {
+      'ui:widget': 'switch',
+    },
   };
   const [formData, setFormData] = useState(initFormData(schema));
+  // the settings which are not in the form are kept when saving
+  const [setting, setSetting] = useState<Type.AdminSettingsLogin>();
   const { update: updateLoginSetting } = loginSettingStore((_) => _);
 
   const onSubmit = (evt) => {
@@ -48,8 +68,11 @@ const Index: FC = () => {
     evt.stopPropagation();
 
     const reqParams: Type.AdminSettingsLogin = {
+      ...setting,
       allow_new_registrations: formData.allow_new_registrations.value,
       login_required: formData.login_required.value,
+      require_two_factor_admin: formData.require_two_factor_admin.value,
+      require_two_factor_moderator: formData.require_two_factor_moderator.value,
     };
 
     putLoginSetting(reqParams)
@@ -69,12 +92,15 @@ const Index: FC = () => {
   };
 
   useEffect(() => {
-    getLoginSetting().then((setting) => {
-      if (setting) {
+    getLoginSetting().then((res) => {
+      if (res) {
+        setSetting(res);
         const formMeta = { ...formData };
-        formMeta.allow_new_registrations.value =
-          setting.allow_new_registrations;
-        formMeta.login_required.value = setting.login_required;
+        formMeta.allow_new_registrations.value = res.allow_new_registrations;
+        formMeta.login_required.value = res.login_required;
+        formMeta.require_two_factor_admin.value = res.require_two_factor_admin;
+        formMeta.require_two_factor_moderator.value =
+          res.require_two_factor_moderator;
         setFormData({ ...formMeta });
       }
     });
@@ -0,0 +1,127 @@
+import { FC, FormEvent, useEffect, useState } from 'react';
+import { Table, Form, Button } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+
+import type * as Type from '@/common/interface';
+import { useToast } from '@/hooks';
+import { usePrivilegeSetting, updatePrivilegeSetting } from '@/services';
+
+type ValueMap = Record<string, string>;
+
+const toValueMap = (items: Type.RankConfigItem[] = []) => {
+  const values: ValueMap = {};
+  items.forEach((item) => {
+    values[item.key] = String(item.value);
+  });
+  return values;
+};
+
+const toItems = (values: ValueMap) => {
+  return Object.keys(values).map((key) => ({
+    key,
+    value: Number(values[key]),
+  }));
+};
+
+const Privileges: FC = () => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'admin.privileges',
+  });
+  const Toast = useToast();
+  const { data, mutate } = usePrivilegeSetting();
+  const [privileges, setPrivileges] = useState<ValueMap>({});
+  const [reputations, setReputations] = useState<ValueMap>({});
+  const [limits, setLimits] = useState<ValueMap>({});
+  const [errorMsg, setErrorMsg] = useState('');
+
+  useEffect(() => {
+    if (!data) {
+      return;
+    }
+    setPrivileges(toValueMap(data.privileges));
+    setReputations(toValueMap(data.reputations));
+    setLimits(toValueMap(data.limits));
+  }, [data]);
+
+  const handleSubmit = (evt: FormEvent) => {
+    evt.preventDefault();
+    const values = { ...privileges, ...reputations, ...limits };
+    if (Object.values(values).some((v) => !/^-?\d+$/.test(v.trim()))) {
+      setErrorMsg(t('invalid'));
+      return;
+    }
+    setErrorMsg('');
+    updatePrivilegeSetting({
+      privileges: toItems(privileges),
+      reputations: toItems(reputations),
+      limits: toItems(limits),
+    })
+      .then(() => {
+        Toast.onShow({
+          msg: t('update', { keyPrefix: 'toast' }),
+          variant: 'success',
+        });
+        mutate();
+      })
+      .catch((err) => {
+        setErrorMsg(err?.msg || '');
+      });
+  };
+
+  const renderTable = (
+    values: ValueMap,
+    setValues: (fn: (pre: ValueMap) => ValueMap) => void,
+    keyTitle: string,
+  ) => (
+    <Table responsive size="sm" className="mb-4">
+      <thead>
+        <tr>
+          <th>{keyTitle}</th>
+          <th style={{ width: '10rem' }}>{t('value')}</th>
+        </tr>
+      </thead>
+      <tbody>
+        {Object.keys(values).map((key) => (
+          <tr key={key}>
+            <td className="align-middle">
+              <code>{key}</code>
+            </td>
+            <td>
+              <Form.Control
+                size="sm"
+                value={values[key]}
+                onChange={(e) =>
+                  setValues((pre) => ({ ...pre, [key]: e.target.value }))
+                }
+              />
+            </td>
+          </tr>
+        ))}
+      </tbody>
+    </Table>
+  );
+
+  if (!data) {
+    return null;
+  }
+  return (
+    <Form noValidate onSubmit={handleSubmit}>
+      <h3 className="mb-4">{t('page_title')}</h3>
+      <h5>{t('privileges.title')}</h5>
+      <p className="text-secondary small">{t('privileges.text')}</p>
+      {renderTable(privileges, setPrivileges, t('privileges.key'))}
+      <h5>{t('reputations.title')}</h5>
+      <p className="text-secondary small">{t('reputations.text')}</p>
+      {renderTable(reputations, setReputations, t('reputations.key'))}
+      <h5>{t('limits.title')}</h5>
+      <p className="text-secondary small">{t('limits.text')}</p>
+      {renderTable(limits, setLimits, t('limits.key'))}
+      {errorMsg && <p className="text-danger">{errorMsg}</p>}
+      <Button type="submit" variant="primary">
+        {t('save', { keyPrefix: 'btns' })}
+      </Button>
+    </Form>
+  );
+};
+
+export default Privileges;
@@ -0,0 +1,141 @@
+import { FC, useEffect, useState } from 'react';
+import { Table, Form, Button } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+
+import { useToast } from '@/hooks';
+import { useQueryRolePowers, updateRolePowers } from '@/services';
+
+interface IProps {
+  roleID: number;
+  roleName: string;
+}
+
+interface PowerSetting {
+  granted: boolean;
+  tags: string;
+}
+
+const Index: FC<IProps> = ({ roleID, roleName }) => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'admin.roles.powers',
+  });
+  const Toast = useToast();
+  const { data, mutate } = useQueryRolePowers(roleID);
+  const [settings, setSettings] = useState<Record<string, PowerSetting>>({});
+  const [errorMsg, setErrorMsg] = useState('');
+
+  useEffect(() => {
+    if (!data) {
+      return;
+    }
+    const initial: Record<string, PowerSetting> = {};
+    data.forEach((item) => {
+      initial[item.power_type] = {
+        granted: item.granted,
+        tags: item.tags.map((tag) => tag.slug_name).join(', '),
+      };
+    });
+    setSettings(initial);
+    setErrorMsg('');
+  }, [data]);
+
+  const handleChange = (powerType: string, value: Partial<PowerSetting>) => {
+    setSettings((pre) => ({
+      ...pre,
+      [powerType]: { ...pre[powerType], ...value },
+    }));
+  };
+
+  const handleSave = () => {
+    const powers: { power_type: string; tags: string[] }[] = [];
+    Object.keys(settings).forEach((powerType) => {
+      const { granted, tags } = settings[powerType];
+      if (granted) {
+        powers.push({ power_type: powerType, tags: [] });
+        return;
+      }
+      const slugs = tags
+        .split(',')
+        .map((v) => v.trim())
+        .filter((v) => v);
+      if (slugs.length > 0) {
+        powers.push({ power_type: powerType, tags: slugs });
+      }
+    });
+    updateRolePowers({ role_id: roleID, powers })
+      .then(() => {
+        Toast.onShow({
+          msg: t('update', { keyPrefix: 'toast' }),
+          variant: 'success',
+        });
+        mutate();
+      })
+      .catch((err) => {
+        setErrorMsg(err?.msg || '');
+      });
+  };
+
+  if (!data) {
+    return null;
+  }
+  return (
+    <>
+      <h5 className="mt-4 mb-2">{t('title', { name: roleName })}</h5>
+      <p className="text-secondary small">{t('text')}</p>
+      <Table responsive>
+        <thead>
+          <tr>
+            <th>{t('power')}</th>
+            <th>{t('everywhere')}</th>
+            <th>{t('tags')}</th>
+          </tr>
+        </thead>
+        <tbody>
+          {data.map((item) => {
+            const setting = settings[item.power_type] || {
+              granted: false,
+              tags: '',
+            };
+            return (
+              <tr key={item.power_type}>
+                <td>
+                  {item.name}
+                  <div className="small text-secondary">{item.power_type}</div>
+                </td>
+                <td className="align-middle">
+                  <Form.Check
+                    id={`power_${item.power_type}`}
+                    type="checkbox"
+                    checked={setting.granted}
+                    onChange={(e) =>
+                      handleChange(item.power_type, {
+                        granted: e.target.checked,
+                      })
+                    }
+                  />
+                </td>
+                <td className="align-middle">
+                  <Form.Control
+                    size="sm"
+                    disabled={setting.granted}
+                    placeholder={t('tags_placeholder')}
+                    value={setting.tags}
+                    onChange={(e) =>
+                      handleChange(item.power_type, { tags: e.target.value })
+                    }
+                  />
+                </td>
+              </tr>
+            );
+          })}
+        </tbody>
+      </Table>
+      {errorMsg && <p className="text-danger">{errorMsg}</p>}
+      <Button variant="primary" onClick={handleSave}>
+        {t('save', { keyPrefix: 'btns' })}
+      </Button>
+    </>
+  );
+};
+
+export default Index;
@@ -0,0 +1,68 @@
+import { FC, FormEvent, useState } from 'react';
+import { Form, Button } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+
+import type * as Type from '@/common/interface';
+
+interface IProps {
+  data?: Type.RoleItem;
+  onSubmit: (data: {
+    id?: number;
+    name: string;
+    description: string;
+  }) => Promise<any>;
+  onCancel: () => void;
+}
+
+const Index: FC<IProps> = ({ data, onSubmit, onCancel }) => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'admin.roles.form',
+  });
+  const [name, setName] = useState(data?.name || '');
+  const [description, setDescription] = useState(data?.description || '');
+  const [errorMsg, setErrorMsg] = useState('');
+
+  const handleSubmit = (evt: FormEvent) => {
+    evt.preventDefault();
+    if (!name.trim()) {
+      setErrorMsg(t('required'));
+      return;
+    }
+    onSubmit({ id: data?.id, name: name.trim(), description }).catch(
+      (err) => {
+        setErrorMsg(err?.msg || '');
+      },
+    );
+  };
+
+  return (
+    <Form noValidate onSubmit={handleSubmit} className="mb-4">
+      <Form.Group controlId="role_name" className="mb-3">
+        <Form.Label>{t('name.label')}</Form.Label>
+        <Form.Control
+          required
+          maxLength={50}
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+        />
+      </Form.Group>
+      <Form.Group controlId="role_description" className="mb-3">
+        <Form.Label>{t('description.label')}</Form.Label>
+        <Form.Control
+          maxLength={200}
+          value={description}
+          onChange={(e) => setDescription(e.target.value)}
+        />
+      </Form.Group>
+      {errorMsg && <p className="text-danger">{errorMsg}</p>}
+      <Button type="submit" variant="primary" className="me-2">
+        {t('save', { keyPrefix: 'btns' })}
+      </Button>
+      <Button variant="link" onClick={onCancel}>
+        {t('cancel', { keyPrefix: 'btns' })}
+      </Button>
+    </Form>
+  );
+};
+
+export default Index;
@@ -0,0 +1,130 @@
+import { FC, useState } from 'react';
+import { Table, Button } from 'react-bootstrap';
+import { useSearchParams } from 'react-router-dom';
+import { useTranslation } from 'react-i18next';
+
+import { Modal } from '@/components';
+import type * as Type from '@/common/interface';
+import { useToast } from '@/hooks';
+import { useQueryRoles, addRole, updateRole, deleteRole } from '@/services';
+
+import RoleForm from './components/RoleForm';
+import Powers from './components/Powers';
+
+// the built-in roles: user, admin and moderator
+const BUILT_IN_ROLE_IDS = [1, 2, 3];
+
+const Roles: FC = () => {
+  const { t } = useTranslation('translation', { keyPrefix: 'admin.roles' });
+  const [urlSearchParams, setUrlSearchParams] = useSearchParams();
+  const curRoleID = Number(urlSearchParams.get('role_id') || '0');
+  const Toast = useToast();
+  const { data, mutate } = useQueryRoles();
+  // the role being edited, an empty object when adding a new one
+  const [editing, setEditing] = useState<Type.RoleItem | {} | null>(null);
+  const curRole = data?.find((item) => item.id === curRoleID);
+
+  const handleSubmit = (params: {
+    id?: number;
+    name: string;
+    description: string;
+  }) => {
+    const req = params.id
+      ? updateRole(params as Type.RoleItem)
+      : addRole(params);
+    return req.then(() => {
+      Toast.onShow({
+        msg: t('update', { keyPrefix: 'toast' }),
+        variant: 'success',
+      });
+      setEditing(null);
+      mutate();
+    });
+  };
+
+  const handleDelete = (id: number) => {
+    Modal.confirm({
+      title: t('title'),
+      content: t('delete_confirm'),
+      cancelBtnVariant: 'link',
+      confirmBtnVariant: 'danger',
+      confirmText: t('delete', { keyPrefix: 'btns' }),
+      onConfirm: () => {
+        deleteRole(id).then(() => {
+          if (curRoleID === id) {
+            setUrlSearchParams({});
+          }
+          mutate();
+        });
+      },
+    });
+  };
+
+  return (
+    <>
+      <h3 className="mb-4">{t('title')}</h3>
+      <p className="text-secondary">{t('text')}</p>
+      {editing ? (
+        <RoleForm
+          key={'id' in editing ? editing.id : 0}
+          data={'id' in editing ? editing : undefined}
+          onSubmit={handleSubmit}
+          onCancel={() => setEditing(null)}
+        />
+      ) : (
+        <Button
+          variant="outline-secondary"
+          className="mb-4"
+          onClick={() => setEditing({})}>
+          {t('add_btn')}
+        </Button>
+      )}
+      {data && data.length > 0 && (
+        <Table responsive>
+          <thead>
+            <tr>
+              <th>{t('name')}</th>
+              <th>{t('description')}</th>
+              <th />
+            </tr>
+          </thead>
+          <tbody>
+            {data.map((item) => (
+              <tr key={item.id}>
+                <td>{item.name}</td>
+                <td className="small text-secondary">{item.description}</td>
+                <td className="text-end text-nowrap">
+                  <Button
+                    variant="link"
+                    className="p-0 me-3"
+                    onClick={() =>
+                      setUrlSearchParams({ role_id: String(item.id) })
+                    }>
+                    {t('powers_btn')}
+                  </Button>
+                  <Button
+                    variant="link"
+                    className="p-0 me-3"
+                    onClick={() => setEditing(item)}>
+                    {t('edit_btn')}
+                  </Button>
+                  {!BUILT_IN_ROLE_IDS.includes(item.id) && (
+                    <Button
+                      variant="link"
+                      className="p-0 text-danger"
+                      onClick={() => handleDelete(item.id)}>
+                      {t('delete', { keyPrefix: 'btns' })}
+                    </Button>
+                  )}
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      )}
+      {curRole && <Powers roleID={curRole.id} roleName={curRole.name} />}
+    </>
+  );
+};
+
+export default Roles;
@@ -64,6 +64,11 @@ const Smtp: FC = () => {
         title: t('test_email_recipient.label'),
         description: t('test_email_recipient.text'),
       },
+      reply_address: {
+        type: 'string',
+        title: t('reply_address.label'),
+        description: t('reply_address.text'),
+      },
     },
   };
   const uiSchema: UISchema = {
@@ -125,6 +130,17 @@ const Smtp: FC = () => {
         },
       },
     },
+    reply_address: {
+      'ui:options': {
+        type: 'email',
+        validator: (value) => {
+          if (value && !pattern.email.test(value)) {
+            return t('reply_address.msg');
+          }
+          return true;
+        },
+      },
+    },
   };
   const [formData, setFormData] = useState<Type.FormDataType>(
     initFormData(schema),
@@ -148,6 +164,7 @@ const Smtp: FC = () => {
         ? { smtp_password: formData.smtp_password.value }
         : {}),
       test_email_recipient: formData.test_email_recipient.value,
+      reply_address: formData.reply_address.value,
     };
 
     updateSmtpSetting(reqParams)
@@ -12,6 +12,7 @@ import {
   Empty,
   QueryGroup,
   Icon,
+  Modal,
 } from '@/components';
 import * as Type from '@/common/interface';
 import {
@@ -21,7 +22,12 @@ import {
   useChangePasswordModal,
   useToast,
 } from '@/hooks';
-import { useQueryUsers, addUser, updateUserPassword } from '@/services';
+import {
+  useQueryUsers,
+  addUser,
+  updateUserPassword,
+  resetUserTwoFactor,
+} from '@/services';
 import { loggedUserInfoStore } from '@/stores';
 import { formatCount } from '@/utils';
 
@@ -131,6 +137,22 @@ const Users: FC = () => {
     if (type === 'password') {
       changePasswordModal.onShow(user_id);
     }
+    if (type === 'two_factor') {
+      Modal.confirm({
+        title: t('reset_two_factor'),
+        content: t('reset_two_factor_confirm'),
+        cancelBtnVariant: 'link',
+        confirmBtnVariant: 'danger',
+        onConfirm: () => {
+          resetUserTwoFactor({ user_id }).then(() => {
+            Toast.onShow({
+              msg: t('reset_two_factor', { keyPrefix: 'toast' }),
+              variant: 'success',
+            });
+          });
+        },
+      });
+    }
   };
 
   const handleFilter = (e) => {
@@ -243,6 +265,10 @@ const Users: FC = () => {
                           onClick={() => handleAction('password', user)}>
                           {t('set_new_password')}
                         </Dropdown.Item>
+                        <Dropdown.Item
+                          onClick={() => handleAction('two_factor', user)}>
+                          {t('reset_two_factor')}
+                        </Dropdown.Item>
                         <Dropdown.Item
                           onClick={() => handleAction('status', user)}>
                           {t('change_status')}
@@ -0,0 +1,134 @@
+import { FC, Fragment, useState } from 'react';
+import { Table, Button, Badge } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+
+import { FormatTime, Pagination, Empty } from '@/components';
+import { useToast } from '@/hooks';
+import { useQueryWebhookDeliveries, redeliverWebhook } from '@/services';
+
+const statusMap = {
+  1: { key: 'pending', bg: 'secondary' },
+  2: { key: 'succeeded', bg: 'success' },
+  3: { key: 'failed', bg: 'danger' },
+};
+
+const PAGE_SIZE = 20;
+
+interface IProps {
+  webhookID: string;
+  page: number;
+}
+
+const Index: FC<IProps> = ({ webhookID, page }) => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'admin.webhooks.deliveries',
+  });
+  const Toast = useToast();
+  const [expanded, setExpanded] = useState('');
+  const { data, mutate } = useQueryWebhookDeliveries({
+    webhook_id: webhookID,
+    page,
+    page_size: PAGE_SIZE,
+  });
+
+  const handleRedeliver = (id: string) => {
+    redeliverWebhook(id).then(() => {
+      Toast.onShow({
+        msg: t('redelivered'),
+        variant: 'success',
+      });
+      mutate();
+    });
+  };
+
+  return (
+    <>
+      <h5 className="mb-3">{t('title')}</h5>
+      <Table responsive>
+        <thead>
+          <tr>
+            <th>{t('event')}</th>
+            <th>{t('status')}</th>
+            <th>{t('response')}</th>
+            <th>{t('created_at')}</th>
+            <th />
+          </tr>
+        </thead>
+        <tbody>
+          {data?.list.map((item) => (
+            <Fragment key={item.id}>
+              <tr>
+                <td>
+                  <Button
+                    variant="link"
+                    className="p-0"
+                    onClick={() =>
+                      setExpanded(expanded === item.id ? '' : item.id)
+                    }>
+                    {item.event}
+                  </Button>
+                </td>
+                <td>
+                  <Badge bg={statusMap[item.status]?.bg}>
+                    {t(statusMap[item.status]?.key)}
+                  </Badge>
+                  <div className="small text-secondary">
+                    {t('attempts', { count: item.attempts })}
+                  </div>
+                </td>
+                <td>
+                  {item.response_status || '-'}
+                  {item.delivered_at > 0 && (
+                    <div className="small text-secondary">
+                      {item.duration} ms
+                    </div>
+                  )}
+                </td>
+                <td>
+                  <FormatTime time={item.created_at} />
+                </td>
+                <td className="text-end">
+                  <Button
+                    variant="link"
+                    className="p-0"
+                    onClick={() => handleRedeliver(item.id)}>
+                    {t('redeliver_btn')}
+                  </Button>
+                </td>
+              </tr>
+              {expanded === item.id && (
+                <tr>
+                  <td colSpan={5}>
+                    {item.error && (
+                      <p className="text-danger small">{item.error}</p>
+                    )}
+                    <p className="mb-1 small">{t('payload')}</p>
+                    <pre className="bg-light p-2 small">{item.payload}</pre>
+                    {item.response_body && (
+                      <>
+                        <p className="mb-1 small">{t('response_body')}</p>
+                        <pre className="bg-light p-2 small">
+                          {item.response_body}
+                        </pre>
+                      </>
+                    )}
+                  </td>
+                </tr>
+              )}
+            </Fragment>
+          ))}
+        </tbody>
+      </Table>
+      {Number(data?.count) <= 0 && <Empty />}
+      <div className="mt-4 mb-2 d-flex justify-content-center">
+        <Pagination
+          currentPage={page}
+          totalSize={data?.count || 0}
+          pageSize={PAGE_SIZE}
+        />
+      </div>
+    </>
+  );
+};
+
+export default Index;
@@ -0,0 +1,124 @@
+import { FC, FormEvent, useState } from 'react';
+import { Form, Button } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+
+import type * as Type from '@/common/interface';
+
+interface IProps {
+  data?: Type.WebhookItem;
+  events: string[];
+  onSubmit: (data: Type.WebhookReq) => Promise<any>;
+  onCancel: () => void;
+}
+
+const Index: FC<IProps> = ({ data, events, onSubmit, onCancel }) => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'admin.webhooks.form',
+  });
+  const [name, setName] = useState(data?.name || '');
+  const [url, setUrl] = useState(data?.url || '');
+  // the secret is never returned, it is kept if it is empty when updating
+  const [secret, setSecret] = useState('');
+  const [selectedEvents, setEvents] = useState<string[]>(data?.events || []);
+  const [tags, setTags] = useState((data?.tags || []).join(', '));
+  const [enabled, setEnabled] = useState(data ? data.enabled : true);
+  const [errorMsg, setErrorMsg] = useState('');
+
+  const handleEvent = (event: string, checked: boolean) => {
+    setEvents((pre) =>
+      checked ? [...pre, event] : pre.filter((v) => v !== event),
+    );
+  };
+
+  const handleSubmit = (evt: FormEvent) => {
+    evt.preventDefault();
+    if (!name || !url || selectedEvents.length === 0) {
+      setErrorMsg(t('required'));
+      return;
+    }
+    onSubmit({
+      id: data?.id,
+      name,
+      url,
+      secret,
+      events: selectedEvents,
+      tags: tags
+        .split(',')
+        .map((v) => v.trim())
+        .filter((v) => v),
+      enabled,
+    }).catch((err) => {
+      setErrorMsg(err?.msg || '');
+    });
+  };
+
+  return (
+    <Form noValidate onSubmit={handleSubmit} className="mb-4">
+      <Form.Group controlId="webhook_name" className="mb-3">
+        <Form.Label>{t('name.label')}</Form.Label>
+        <Form.Control
+          required
+          maxLength={100}
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+        />
+      </Form.Group>
+      <Form.Group controlId="webhook_url" className="mb-3">
+        <Form.Label>{t('url.label')}</Form.Label>
+        <Form.Control
+          required
+          type="url"
+          maxLength={512}
+          value={url}
+          onChange={(e) => setUrl(e.target.value)}
+        />
+      </Form.Group>
+      <Form.Group controlId="webhook_secret" className="mb-3">
+        <Form.Label>{t('secret.label')}</Form.Label>
+        <Form.Control
+          maxLength={128}
+          value={secret}
+          placeholder={data?.secret_hint}
+          onChange={(e) => setSecret(e.target.value)}
+        />
+        <Form.Text>{data ? t('secret.keep_text') : t('secret.text')}</Form.Text>
+      </Form.Group>
+      <Form.Group className="mb-3">
+        <Form.Label>{t('events.label')}</Form.Label>
+        {events.map((event) => (
+          <Form.Check
+            key={event}
+            id={`webhook_event_${event}`}
+            type="checkbox"
+            label={event}
+            checked={selectedEvents.includes(event)}
+            onChange={(e) => handleEvent(event, e.target.checked)}
+          />
+        ))}
+      </Form.Group>
+      <Form.Group controlId="webhook_tags" className="mb-3">
+        <Form.Label>{t('tags.label')}</Form.Label>
+        <Form.Control value={tags} onChange={(e) => setTags(e.target.value)} />
+        <Form.Text>{t('tags.text')}</Form.Text>
+      </Form.Group>
+      <Form.Group className="mb-3">
+        <Form.Check
+          id="webhook_enabled"
+          type="switch"
+          label={t('enabled.label')}
+          checked={enabled}
+          onChange={(e) => setEnabled(e.target.checked)}
+        />
+      </Form.Group>
+      {errorMsg && <p className="text-danger">{errorMsg}</p>}
+      <Button type="submit" variant="primary" className="me-2">
+        {t('save', { keyPrefix: 'btns' })}
+      </Button>
+      <Button variant="link" onClick={onCancel}>
+        {t('cancel', { keyPrefix: 'btns' })}
+      </Button>
+    </Form>
+  );
+};
+
+export default Index;
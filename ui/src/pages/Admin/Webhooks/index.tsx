@@ -0,0 +1,155 @@
+import { FC, useEffect, useState } from 'react';
+import { Table, Button, Badge } from 'react-bootstrap';
+import { useSearchParams } from 'react-router-dom';
+import { useTranslation } from 'react-i18next';
+
+import { Modal } from '@/components';
+import type * as Type from '@/common/interface';
+import { useToast } from '@/hooks';
+import {
+  useQueryWebhooks,
+  getWebhookEvents,
+  addWebhook,
+  updateWebhook,
+  deleteWebhook,
+} from '@/services';
+
+import WebhookForm from './components/WebhookForm';
+import Deliveries from './components/Deliveries';
+
+const Webhooks: FC = () => {
+  const { t } = useTranslation('translation', { keyPrefix: 'admin.webhooks' });
+  const [urlSearchParams, setUrlSearchParams] = useSearchParams();
+  const curWebhookID = urlSearchParams.get('webhook_id') || '';
+  const curPage = Number(urlSearchParams.get('page') || '1');
+  const Toast = useToast();
+  const { data, mutate } = useQueryWebhooks();
+  const [events, setEvents] = useState<string[]>([]);
+  // the webhook being edited, an empty object when adding a new one
+  const [editing, setEditing] = useState<Type.WebhookItem | {} | null>(null);
+
+  useEffect(() => {
+    getWebhookEvents().then((res) => {
+      setEvents(res || []);
+    });
+  }, []);
+
+  const handleSubmit = (params: Type.WebhookReq) => {
+    const req = params.id ? updateWebhook(params) : addWebhook(params);
+    return req.then((res) => {
+      Toast.onShow({
+        msg: t('update', { keyPrefix: 'toast' }),
+        variant: 'success',
+      });
+      setEditing(null);
+      mutate();
+      // the generated secret is only returned once
+      if (!params.id && !params.secret && res?.secret) {
+        Modal.confirm({
+          title: t('title'),
+          content: `${t('secret_generated')}<br /><code>${res.secret}</code>`,
+          showCancel: false,
+        });
+      }
+    });
+  };
+
+  const handleDelete = (id: string) => {
+    Modal.confirm({
+      title: t('title'),
+      content: t('delete_confirm'),
+      cancelBtnVariant: 'link',
+      confirmBtnVariant: 'danger',
+      confirmText: t('delete', { keyPrefix: 'btns' }),
+      onConfirm: () => {
+        deleteWebhook(id).then(() => {
+          if (curWebhookID === id) {
+            setUrlSearchParams({});
+          }
+          mutate();
+        });
+      },
+    });
+  };
+
+  return (
+    <>
+      <h3 className="mb-4">{t('title')}</h3>
+      <p className="text-secondary">{t('text')}</p>
+      {editing ? (
+        <WebhookForm
+          data={'id' in editing ? editing : undefined}
+          events={events}
+          onSubmit={handleSubmit}
+          onCancel={() => setEditing(null)}
+        />
+      ) : (
+        <Button
+          variant="outline-secondary"
+          className="mb-4"
+          onClick={() => setEditing({})}>
+          {t('add_btn')}
+        </Button>
+      )}
+      {data && data.length > 0 && (
+        <Table responsive>
+          <thead>
+            <tr>
+              <th>{t('name')}</th>
+              <th>{t('events')}</th>
+              <th>{t('tags')}</th>
+              <th>{t('status')}</th>
+              <th />
+            </tr>
+          </thead>
+          <tbody>
+            {data.map((item) => (
+              <tr key={item.id}>
+                <td>
+                  {item.name}
+                  <div className="small text-secondary text-break">
+                    {item.url}
+                  </div>
+                </td>
+                <td className="small">{item.events.join(', ')}</td>
+                <td className="small">
+                  {item.tags.length > 0 ? item.tags.join(', ') : t('all_tags')}
+                </td>
+                <td>
+                  <Badge bg={item.enabled ? 'success' : 'secondary'}>
+                    {item.enabled ? t('enabled') : t('disabled')}
+                  </Badge>
+                </td>
+                <td className="text-end text-nowrap">
+                  <Button
+                    variant="link"
+                    className="p-0 me-3"
+                    onClick={() =>
+                      setUrlSearchParams({ webhook_id: item.id })
+                    }>
+                    {t('deliveries_btn')}
+                  </Button>
+                  <Button
+                    variant="link"
+                    className="p-0 me-3"
+                    onClick={() => setEditing(item)}>
+                    {t('edit_btn')}
+                  </Button>
+                  <Button
+                    variant="link"
+                    className="p-0 text-danger"
+                    onClick={() => handleDelete(item.id)}>
+                    {t('delete', { keyPrefix: 'btns' })}
+                  </Button>
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      )}
+      {curWebhookID && <Deliveries webhookID={curWebhookID} page={curPage} />}
+    </>
+  );
+};
+
+export default Webhooks;
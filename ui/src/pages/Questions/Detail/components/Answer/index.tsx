@@ -11,10 +11,12 @@ import {
   Comment,
   FormatTime,
   htmlRender,
+  Modal,
 } from '@/components';
 import { scrollTop } from '@/utils';
+import { useToast } from '@/hooks';
 import { AnswerItem } from '@/common/interface';
-import { acceptanceAnswer } from '@/services';
+import { acceptanceAnswer, awardBounty } from '@/services';
 
 interface Props {
   data: AnswerItem;
@@ -26,6 +28,9 @@ interface Props {
   slugTitle: string;
   isLogged: boolean;
   callback: (type: string) => void;
+  /** the open bounty amount the current user can award to this answer */
+  bountyAmount?: number;
+  bountyCallback?: () => void;
 }
 const Index: FC<Props> = ({
   aid,
@@ -35,12 +40,15 @@ const Index: FC<Props> = ({
   questionTitle = '',
   slugTitle,
   callback,
+  bountyAmount = 0,
+  bountyCallback,
 }) => {
   const { t } = useTranslation('translation', {
     keyPrefix: 'question_detail',
   });
   const [searchParams] = useSearchParams();
   const answerRef = useRef<HTMLDivElement>(null);
+  const toast = useToast();
   const acceptAnswer = () => {
     acceptanceAnswer({
       question_id: data.question_id,
@@ -50,6 +58,26 @@ const Index: FC<Props> = ({
     });
   };
 
+  const handleAwardBounty = () => {
+    Modal.confirm({
+      title: t('bounty.award_title'),
+      content: t('bounty.award_confirm', {
+        amount: bountyAmount,
+        user: data.user_info?.display_name,
+      }),
+      cancelBtnVariant: 'link',
+      onConfirm: () => {
+        awardBounty(data.id).then(() => {
+          toast.onShow({
+            msg: t('bounty.award_success'),
+            variant: 'success',
+          });
+          bountyCallback?.();
+        });
+      },
+    });
+  };
+
   useEffect(() => {
     if (!answerRef?.current) {
       return;
@@ -105,6 +133,15 @@ const Index: FC<Props> = ({
             <span>{t('answers.btn_accept')}</span>
           </Button>
         )}
+
+        {bountyAmount > 0 && (
+          <Button
+            variant="outline-primary"
+            className="ms-3"
+            onClick={handleAwardBounty}>
+            {t('bounty.award_btn', { amount: bountyAmount })}
+          </Button>
+        )}
       </div>
 
       <Row className="mt-4 mb-3">
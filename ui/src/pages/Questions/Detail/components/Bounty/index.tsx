@@ -0,0 +1,107 @@
+import { memo, FC, useState } from 'react';
+import { Alert, Button, Form, Stack } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+import { Link } from 'react-router-dom';
+
+import dayjs from 'dayjs';
+
+import type { QuestionBounty } from '@/common/interface';
+import { useToast } from '@/hooks';
+import { startBounty } from '@/services';
+
+const BOUNTY_AMOUNTS = [50, 100, 200, 300, 400, 500];
+
+interface Props {
+  questionId: string;
+  data?: QuestionBounty | null;
+  /** the question is open and has no accepted answer */
+  canStart: boolean;
+  callback: () => void;
+}
+const Index: FC<Props> = ({ questionId, data, canStart, callback }) => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'question_detail.bounty',
+  });
+  const toast = useToast();
+  const [showForm, setShowForm] = useState(false);
+  const [amount, setAmount] = useState(BOUNTY_AMOUNTS[0]);
+
+  const handleStart = () => {
+    startBounty({ question_id: questionId, amount }).then(() => {
+      toast.onShow({
+        msg: t('success'),
+        variant: 'success',
+      });
+      setShowForm(false);
+      callback();
+    });
+  };
+
+  if (data?.id) {
+    return (
+      <Alert className="mb-4" variant="info">
+        <p className="mb-1">
+          {t('open', { amount: data.amount })}{' '}
+          <Link to={`/users/${data.user_info?.username}`}>
+            {data.user_info?.display_name}
+          </Link>
+        </p>
+        <div className="fs-14">
+          {t('ends_at')}{' '}
+          <time
+            dateTime={dayjs.unix(data.expired_at).tz().toISOString()}
+            title={dayjs
+              .unix(data.expired_at)
+              .tz()
+              .format(t('long_date_with_time', { keyPrefix: 'dates' }))}>
+            {dayjs
+              .unix(data.expired_at)
+              .tz()
+              .format(t('long_date_with_year', { keyPrefix: 'dates' }))}
+          </time>
+          .
+        </div>
+      </Alert>
+    );
+  }
+
+  if (!canStart) {
+    return null;
+  }
+  if (!showForm) {
+    return (
+      <Button
+        variant="link"
+        className="p-0 mb-4 fs-14 btn-no-border"
+        onClick={() => setShowForm(true)}>
+        {t('start_btn')}
+      </Button>
+    );
+  }
+  return (
+    <Alert className="mb-4" variant="light">
+      <p className="fs-14">{t('start_desc')}</p>
+      <Stack direction="horizontal" gap={2}>
+        <Form.Select
+          size="sm"
+          className="w-auto"
+          value={amount}
+          onChange={(e) => setAmount(Number(e.target.value))}>
+          {BOUNTY_AMOUNTS.map((item) => (
+            <option key={item} value={item}>
+              +{item}
+            </option>
+          ))}
+        </Form.Select>
+        <Button size="sm" variant="primary" onClick={handleStart}>
+          {t('start_submit')}
+        </Button>
+        <Button size="sm" variant="link" onClick={() => setShowForm(false)}>
+          {t('cancel', { keyPrefix: 'btns' })}
+        </Button>
+      </Stack>
+    </Alert>
+  );
+};
+
+export default memo(Index);
@@ -4,5 +4,14 @@ import AnswerHead from './AnswerHead';
 import RelatedQuestions from './RelatedQuestions';
 import WriteAnswer from './WriteAnswer';
 import Alert from './Alert';
+import Bounty from './Bounty';
 
-export { Question, Answer, AnswerHead, RelatedQuestions, WriteAnswer, Alert };
+export {
+  Question,
+  Answer,
+  AnswerHead,
+  RelatedQuestions,
+  WriteAnswer,
+  Alert,
+  Bounty,
+};
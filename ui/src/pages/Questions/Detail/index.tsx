@@ -18,7 +18,12 @@ import type {
   QuestionDetailRes,
   AnswerItem,
 } from '@/common/interface';
-import { questionDetail, getAnswers } from '@/services';
+import {
+  questionDetail,
+  getAnswers,
+  subscribeNotificationStream,
+  useQuestionBounty,
+} from '@/services';
 
 import {
   Question,
@@ -27,6 +32,7 @@ import {
   RelatedQuestions,
   WriteAnswer,
   Alert,
+  Bounty,
 } from './components';
 
 import './index.scss';
@@ -54,6 +60,14 @@ const Index = () => {
   const isAuthor = userInfo?.username === question?.user_info?.username;
   const isLogged = Boolean(userInfo?.access_token);
   const { state: locationState } = useLocation();
+  const { data: bounty, mutate: refreshBounty } = useQuestionBounty(qid);
+  const isBountyOwner =
+    Boolean(bounty?.id) && bounty?.user_info?.username === userInfo?.username;
+  const canStartBounty =
+    isLogged &&
+    question?.status === 1 &&
+    !question?.answer_count &&
+    (!question?.accepted_answer_id || question?.accepted_answer_id === '0');
 
   useEffect(() => {
     if (locationState?.isReview) {
@@ -150,6 +164,31 @@ const Index = () => {
       requestAnswers();
     }
   }, [page, order]);
+
+  useEffect(() => {
+    if (!qid || !isLogged || typeof EventSource === 'undefined') {
+      return undefined;
+    }
+    return subscribeNotificationStream(
+      { question_id: qid },
+      {
+        vote: ({ object_id, votes }) => {
+          setQuestion((pre) =>
+            pre && pre.id === object_id ? { ...pre, vote_count: votes } : pre,
+          );
+          setAnswers((pre) => ({
+            ...pre,
+            list: pre.list.map((item) =>
+              item.id === object_id ? { ...item, vote_count: votes } : item,
+            ),
+          }));
+        },
+        answer_count: () => {
+          requestAnswers();
+        },
+      },
+    );
+  }, [qid, isLogged]);
   usePageTags({
     title: question?.title,
     description: question?.description,
@@ -168,6 +207,12 @@ const Index = () => {
             hasAnswer={answers.count > 0}
             isLogged={isLogged}
           />
+          <Bounty
+            questionId={qid}
+            data={bounty}
+            canStart={canStartBounty}
+            callback={refreshBounty}
+          />
           {answers.count > 0 && (
             <>
               <AnswerHead count={answers.count} order={order} />
@@ -182,6 +227,13 @@ const Index = () => {
                     isAuthor={isAuthor}
                     callback={initPage}
                     isLogged={isLogged}
+                    bountyAmount={
+                      isBountyOwner &&
+                      item.user_info?.username !== userInfo?.username
+                        ? bounty?.amount
+                        : 0
+                    }
+                    bountyCallback={refreshBounty}
                   />
                 );
               })}
@@ -69,7 +69,8 @@ const Index: FC<Props> = ({ data, isAdmin, objectInfo, revisionList }) => {
               {t(data.activity_type)}
             </Button>
           )}
-          {data.activity_type === 'accept' && (
+          {(data.activity_type === 'accept' ||
+            data.activity_type === 'bounty_awarded') && (
             <Link
               to={`/questions/${objectInfo.question_id}/${data?.object_id}`}>
               {t(data.activity_type)}
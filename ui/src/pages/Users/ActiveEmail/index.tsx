@@ -16,6 +16,16 @@ const Index: FC = () => {
 
     if (code) {
       activateAccount(encodeURIComponent(code)).then((res) => {
+        // the two-factor code is verified on the login page before the user is logged in
+        if (res.two_factor_token) {
+          navigate(
+            `/users/login?two_factor_token=${
+              res.two_factor_token
+            }&two_factor_enroll_required=${!!res.two_factor_enroll_required}`,
+            { replace: true },
+          );
+          return;
+        }
         updateUser(res);
         setTimeout(() => {
           navigate('/users/account-activation/success', { replace: true });
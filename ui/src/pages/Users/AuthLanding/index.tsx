@@ -0,0 +1,36 @@
+import { FC, memo, useEffect } from 'react';
+import { useSearchParams, useNavigate } from 'react-router-dom';
+
+import { loggedUserInfoStore } from '@/stores';
+import { exchangeExternalLoginCode, getLoggedUserInfo } from '@/services';
+import { LOGGED_TOKEN_STORAGE_KEY } from '@/common/constants';
+import Storage from '@/utils/storage';
+
+// the external login connector redirects here with the one-time code of the access token
+const Index: FC = () => {
+  const [searchParams] = useSearchParams();
+  const updateUser = loggedUserInfoStore((state) => state.update);
+  const navigate = useNavigate();
+  useEffect(() => {
+    const code = searchParams.get('code');
+    if (!code) {
+      navigate('/users/login', { replace: true });
+      return;
+    }
+    exchangeExternalLoginCode({ code })
+      .then((res) => {
+        Storage.set(LOGGED_TOKEN_STORAGE_KEY, res.access_token);
+        return getLoggedUserInfo();
+      })
+      .then((res) => {
+        updateUser(res);
+        navigate('/', { replace: true });
+      })
+      .catch(() => {
+        navigate('/users/login', { replace: true });
+      });
+  }, []);
+  return null;
+};
+
+export default memo(Index);
@@ -0,0 +1,103 @@
+import { FC, FormEvent, memo, useEffect, useState } from 'react';
+import { Col, Form, Button } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+
+import type {
+  TwoFactorEnrollRes,
+  TwoFactorLoginRes,
+} from '@/common/interface';
+import { twoFactorLogin, twoFactorLoginEnroll } from '@/services';
+
+interface Props {
+  token: string;
+  enrollRequired: boolean;
+  onLogin: (res: TwoFactorLoginRes) => void;
+}
+
+// the password is verified, the access token is returned after the two-factor code is verified
+const Index: FC<Props> = ({ token, enrollRequired, onLogin }) => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'login.two_factor',
+  });
+  const [code, setCode] = useState('');
+  const [enrollInfo, setEnrollInfo] = useState<TwoFactorEnrollRes>();
+  const [loginRes, setLoginRes] = useState<TwoFactorLoginRes>();
+
+  useEffect(() => {
+    if (enrollRequired) {
+      twoFactorLoginEnroll({ two_factor_token: token }).then((res) => {
+        setEnrollInfo(res);
+      });
+    }
+  }, [token, enrollRequired]);
+
+  const handleSubmit = (event: FormEvent) => {
+    event.preventDefault();
+    if (!code) {
+      return;
+    }
+    twoFactorLogin({ two_factor_token: token, code }).then((res) => {
+      if (res.recovery_codes?.length) {
+        // the recovery codes are only shown once after the enrollment
+        setLoginRes(res);
+        return;
+      }
+      onLogin(res);
+    });
+  };
+
+  if (loginRes) {
+    return (
+      <Col className="mx-auto" md={3}>
+        <p>{t('recovery_codes')}</p>
+        <pre className="bg-light p-3">
+          {loginRes.recovery_codes?.join('\n')}
+        </pre>
+        <div className="d-grid">
+          <Button variant="primary" onClick={() => onLogin(loginRes)}>
+            {t('continue')}
+          </Button>
+        </div>
+      </Col>
+    );
+  }
+
+  return (
+    <Col className="mx-auto" md={3}>
+      <Form noValidate onSubmit={handleSubmit}>
+        {enrollInfo && (
+          <div className="mb-3">
+            <p>{t('enroll')}</p>
+            <img
+              className="d-block mx-auto mb-2"
+              src={enrollInfo.qr_code}
+              alt={enrollInfo.provisioning_uri}
+            />
+            <p className="text-center">
+              <code>{enrollInfo.secret}</code>
+            </p>
+          </div>
+        )}
+        <Form.Group controlId="two_factor_code" className="mb-3">
+          <Form.Label>{t('code.label')}</Form.Label>
+          <Form.Control
+            required
+            autoFocus
+            autoComplete="one-time-code"
+            maxLength={32}
+            value={code}
+            onChange={(e) => setCode(e.target.value)}
+          />
+          <Form.Text>{t('code.text')}</Form.Text>
+        </Form.Group>
+        <div className="d-grid">
+          <Button variant="primary" type="submit">
+            {t('verify', { keyPrefix: 'btns' })}
+          </Button>
+        </div>
+      </Form>
+    </Col>
+  );
+};
+
+export default memo(Index);
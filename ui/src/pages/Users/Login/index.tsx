@@ -6,6 +6,7 @@ import { Trans, useTranslation } from 'react-i18next';
 import { usePageTags } from '@/hooks';
 import type {
   LoginReqParams,
+  UserInfoRes,
   ImgCodeRes,
   FormDataType,
 } from '@/common/interface';
@@ -18,6 +19,8 @@ import { RouteAlias } from '@/router/alias';
 import { PicAuthCodeModal } from '@/components/Modal';
 import Storage from '@/utils/storage';
 
+import TwoFactor from './components/TwoFactor';
+
 const Index: React.FC = () => {
   const { t } = useTranslation('translation', { keyPrefix: 'login' });
   const navigate = useNavigate();
@@ -50,6 +53,10 @@ const Index: React.FC = () => {
   });
   const [showModal, setModalState] = useState(false);
   const [step, setStep] = useState(1);
+  const [twoFactor, setTwoFactor] = useState({
+    token: '',
+    enrollRequired: false,
+  });
 
   const handleChange = (params: FormDataType) => {
     setFormData({ ...formData, ...params });
@@ -91,6 +98,22 @@ const Index: React.FC = () => {
     return bol;
   };
 
+  const handleLoggedIn = (res: UserInfoRes) => {
+    updateUser(res);
+    const userStat = guard.deriveLoginState();
+    if (userStat.isNotActivated) {
+      // inactive
+      setStep(2);
+      setRefresh((pre) => pre + 1);
+    } else {
+      const path = Storage.get(REDIRECT_PATH_STORAGE_KEY) || RouteAlias.home;
+      Storage.remove(REDIRECT_PATH_STORAGE_KEY);
+      floppyNavigation.navigate(path, () => {
+        navigate(path, { replace: true });
+      });
+    }
+  };
+
   const handleLogin = (event?: any) => {
     if (event) {
       event.preventDefault();
@@ -106,22 +129,16 @@ const Index: React.FC = () => {
 
     login(params)
       .then((res) => {
-        updateUser(res);
-        const userStat = guard.deriveLoginState();
-        if (userStat.isNotActivated) {
-          // inactive
-          setStep(2);
-          setRefresh((pre) => pre + 1);
-        } else {
-          const path =
-            Storage.get(REDIRECT_PATH_STORAGE_KEY) || RouteAlias.home;
-          Storage.remove(REDIRECT_PATH_STORAGE_KEY);
-          floppyNavigation.navigate(path, () => {
-            navigate(path, { replace: true });
+        setModalState(false);
+        if (res.two_factor_token) {
+          setTwoFactor({
+            token: res.two_factor_token,
+            enrollRequired: !!res.two_factor_enroll_required,
           });
+          setStep(3);
+          return;
         }
-
-        setModalState(false);
+        handleLoggedIn(res);
       })
       .catch((err) => {
         // if (err.isError && err.key) {
@@ -168,6 +185,16 @@ const Index: React.FC = () => {
     if ((storeUser.id && storeUser.mail_status === 2) || isInactive) {
       setStep(2);
     }
+    // the external login connector redirects here if the two-factor code should be verified
+    const twoFactorToken = searchParams.get('two_factor_token');
+    if (twoFactorToken) {
+      setTwoFactor({
+        token: twoFactorToken,
+        enrollRequired:
+          searchParams.get('two_factor_enroll_required') === 'true',
+      });
+      setStep(3);
+    }
   }, []);
   usePageTags({
     title: t('login', { keyPrefix: 'page_title' }),
@@ -252,6 +279,14 @@ const Index: React.FC = () => {
 
       {step === 2 && <Unactivate visible={step === 2} />}
 
+      {step === 3 && (
+        <TwoFactor
+          token={twoFactor.token}
+          enrollRequired={twoFactor.enrollRequired}
+          onLogin={handleLoggedIn}
+        />
+      )}
+
       <PicAuthCodeModal
         visible={showModal}
         data={{
@@ -20,6 +20,7 @@ const Achievements = ({ data, handleReadNotification }) => {
       {data.map((item) => {
         const { comment, question, answer } =
           item?.object_info?.object_map || {};
+        const isBadge = item.object_info.object_type === 'badge';
         let url = '';
         switch (item.object_info.object_type) {
           case 'question':
@@ -31,6 +32,9 @@ const Achievements = ({ data, handleReadNotification }) => {
           case 'comment':
             url = `/questions/${question}/${answer}?commentId=${comment}`;
             break;
+          case 'badge':
+            url = `/users/${item.user_info?.username}`;
+            break;
           default:
             url = '';
         }
@@ -41,11 +45,18 @@ const Achievements = ({ data, handleReadNotification }) => {
               'd-flex border-start-0 border-end-0',
               !item.is_read && 'warning',
             )}>
-            {item.rank > 0 && (
+            {isBadge && (
+              <div className="num text-end">
+                <i className="br bi-award-fill text-warning" />
+              </div>
+            )}
+            {!isBadge && item.rank > 0 && (
               <div className="text-success num text-end">{`+${item.rank}`}</div>
             )}
-            {item.rank === 0 && <div className="num text-end">{item.rank}</div>}
-            {item.rank < 0 && (
+            {!isBadge && item.rank === 0 && (
+              <div className="num text-end">{item.rank}</div>
+            )}
+            {!isBadge && item.rank < 0 && (
               <div className="text-danger num text-end">{`${item.rank}`}</div>
             )}
             <div className="d-flex flex-column ms-3 flex-fill">
@@ -53,7 +64,9 @@ const Achievements = ({ data, handleReadNotification }) => {
                 {item.object_info.title}
               </Link>
               <span className="text-secondary">
-                {item.object_info.object_type}
+                {isBadge
+                  ? item.notification_action
+                  : item.object_info.object_type}
               </span>
             </div>
           </ListGroup.Item>
@@ -5,6 +5,7 @@ import { Link } from 'react-router-dom';
 
 import { Avatar, Icon } from '@/components';
 import type { UserInfoRes } from '@/common/interface';
+import { BADGE_LEVELS, BADGE_LEVEL_COLORS } from '@/common/constants';
 
 interface Props {
   data: UserInfoRes;
@@ -64,6 +65,37 @@ const Index: FC<Props> = ({ data }) => {
           </div>
         </div>
 
+        {data.badges && data.badges.length > 0 && (
+          <div className="d-flex flex-wrap align-items-center mb-3">
+            <span className="text-secondary me-2">{t('badges')}</span>
+            {data.badges.map((badge) => (
+              <OverlayTrigger
+                key={badge.id}
+                placement="top"
+                overlay={
+                  <Tooltip>
+                    {`${t(`badge_level.${BADGE_LEVELS[badge.level]}`)} · ${
+                      badge.description
+                    }`}
+                  </Tooltip>
+                }>
+                <span className="badge text-bg-light me-2 mb-1">
+                  <i
+                    className="br bi-award-fill me-1"
+                    style={{ color: BADGE_LEVEL_COLORS[badge.level] }}
+                  />
+                  {badge.name}
+                  {badge.count > 1 && (
+                    <span className="text-secondary ms-1">
+                      {`× ${badge.count}`}
+                    </span>
+                  )}
+                </span>
+              </OverlayTrigger>
+            ))}
+          </div>
+        )}
+
         <div className="d-flex text-secondary">
           {data.location && (
             <div className="d-flex align-items-center me-3">
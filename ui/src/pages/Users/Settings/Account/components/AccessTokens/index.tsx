@@ -0,0 +1,187 @@
+import React, { FC, FormEvent, useEffect, useState } from 'react';
+import { Form, Button, Table } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+
+import dayjs from 'dayjs';
+
+import { FormatTime } from '@/components';
+import type { AccessTokenItem } from '@/common/interface';
+import { loggedUserInfoStore } from '@/stores';
+import {
+  getAccessTokens,
+  addAccessToken,
+  removeAccessToken,
+} from '@/services';
+
+const scopeOptions = ['read', 'write:question', 'write:answer', 'admin'];
+
+const Index: FC = () => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'settings.account.access_token',
+  });
+  const { user } = loggedUserInfoStore();
+  const [tokens, setTokens] = useState<AccessTokenItem[]>([]);
+  const [showForm, setFormState] = useState(false);
+  const [name, setName] = useState('');
+  const [scopes, setScopes] = useState<string[]>(['read']);
+  const [expiresInDays, setExpiresInDays] = useState(30);
+  const [newToken, setNewToken] = useState('');
+
+  const refreshTokens = () => {
+    getAccessTokens().then((res) => {
+      setTokens(res || []);
+    });
+  };
+
+  useEffect(() => {
+    refreshTokens();
+  }, []);
+
+  const handleScope = (scope: string, checked: boolean) => {
+    setScopes((pre) =>
+      checked ? [...pre, scope] : pre.filter((v) => v !== scope),
+    );
+  };
+
+  const handleSubmit = (event: FormEvent) => {
+    event.preventDefault();
+    if (!name || scopes.length === 0) {
+      return;
+    }
+    addAccessToken({
+      name,
+      scopes,
+      expires_in_days: expiresInDays,
+    }).then((res) => {
+      // the token is only shown once
+      setNewToken(res.token);
+      setFormState(false);
+      setName('');
+      refreshTokens();
+    });
+  };
+
+  const handleRemove = (id: string) => {
+    removeAccessToken({ id }).then(() => {
+      refreshTokens();
+    });
+  };
+
+  return (
+    <div className="mt-5">
+      <Form.Label>{t('title')}</Form.Label>
+      <p className="text-secondary">{t('text')}</p>
+      {newToken && (
+        <div className="mb-3">
+          <p>{t('new_token')}</p>
+          <pre className="bg-light p-3">{newToken}</pre>
+        </div>
+      )}
+      {tokens.length > 0 && (
+        <Table responsive>
+          <thead>
+            <tr>
+              <th>{t('name.label')}</th>
+              <th>{t('scopes.label')}</th>
+              <th>{t('last_used')}</th>
+              <th>{t('expires')}</th>
+              <th />
+            </tr>
+          </thead>
+          <tbody>
+            {tokens.map((item) => (
+              <tr key={item.id}>
+                <td>
+                  {item.name}
+                  <br />
+                  <code>{item.token_prefix}…</code>
+                </td>
+                <td>{item.scopes.join(', ')}</td>
+                <td>
+                  {item.last_used_at ? (
+                    <FormatTime time={item.last_used_at} />
+                  ) : (
+                    t('never')
+                  )}
+                </td>
+                <td>
+                  {item.expired_at
+                    ? dayjs.unix(item.expired_at).format('YYYY-MM-DD')
+                    : t('never')}
+                </td>
+                <td className="text-end">
+                  <Button
+                    variant="link"
+                    className="text-danger p-0"
+                    onClick={() => handleRemove(item.id)}>
+                    {t('revoke_btn')}
+                  </Button>
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      )}
+      {showForm ? (
+        <Form noValidate onSubmit={handleSubmit}>
+          <Form.Group controlId="access_token_name" className="mb-3">
+            <Form.Label>{t('name.label')}</Form.Label>
+            <Form.Control
+              required
+              maxLength={100}
+              value={name}
+              onChange={(e) => setName(e.target.value)}
+            />
+          </Form.Group>
+          <Form.Group className="mb-3">
+            <Form.Label>{t('scopes.label')}</Form.Label>
+            {scopeOptions
+              .filter((scope) => scope !== 'admin' || user.is_admin)
+              .map((scope) => (
+                <Form.Check
+                  key={scope}
+                  id={`access_token_scope_${scope}`}
+                  type="checkbox"
+                  label={t(`scopes.${scope.replace(':', '_')}`)}
+                  checked={scopes.includes(scope)}
+                  onChange={(e) => handleScope(scope, e.target.checked)}
+                />
+              ))}
+          </Form.Group>
+          <Form.Group controlId="access_token_expires" className="mb-3">
+            <Form.Label>{t('expires')}</Form.Label>
+            <Form.Select
+              value={expiresInDays}
+              onChange={(e) => setExpiresInDays(Number(e.target.value))}>
+              {[7, 30, 90, 365].map((days) => (
+                <option key={days} value={days}>
+                  {t('days', { count: days })}
+                </option>
+              ))}
+              <option value={0}>{t('never')}</option>
+            </Form.Select>
+          </Form.Group>
+          <div>
+            <Button type="submit" variant="primary" className="me-2">
+              {t('save', { keyPrefix: 'btns' })}
+            </Button>
+            <Button variant="link" onClick={() => setFormState(false)}>
+              {t('cancel', { keyPrefix: 'btns' })}
+            </Button>
+          </div>
+        </Form>
+      ) : (
+        <Button
+          variant="outline-secondary"
+          onClick={() => {
+            setNewToken('');
+            setFormState(true);
+          }}>
+          {t('add_btn')}
+        </Button>
+      )}
+    </div>
+  );
+};
+
+export default React.memo(Index);
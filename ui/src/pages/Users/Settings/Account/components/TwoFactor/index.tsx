@@ -0,0 +1,169 @@
+import React, { FC, FormEvent, useEffect, useState } from 'react';
+import { Form, Button } from 'react-bootstrap';
+import { useTranslation } from 'react-i18next';
+
+import { useToast } from '@/hooks';
+import type {
+  TwoFactorStatusRes,
+  TwoFactorEnrollRes,
+} from '@/common/interface';
+import {
+  getTwoFactorStatus,
+  twoFactorEnroll,
+  twoFactorEnable,
+  twoFactorDisable,
+  regenerateRecoveryCodes,
+} from '@/services';
+
+type Action = '' | 'enable' | 'disable' | 'recovery_codes';
+
+const Index: FC = () => {
+  const { t } = useTranslation('translation', {
+    keyPrefix: 'settings.account.two_factor',
+  });
+  const toast = useToast();
+  const [status, setStatus] = useState<TwoFactorStatusRes>();
+  const [action, setAction] = useState<Action>('');
+  const [enrollInfo, setEnrollInfo] = useState<TwoFactorEnrollRes>();
+  const [code, setCode] = useState('');
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>([]);
+
+  const refreshStatus = () => {
+    getTwoFactorStatus().then((res) => {
+      setStatus(res);
+    });
+  };
+
+  useEffect(() => {
+    refreshStatus();
+  }, []);
+
+  const handleAction = (val: Action) => {
+    setCode('');
+    setRecoveryCodes([]);
+    setAction(val);
+    if (val === 'enable') {
+      twoFactorEnroll().then((res) => {
+        setEnrollInfo(res);
+      });
+    }
+  };
+
+  const handleDone = () => {
+    setAction('');
+    setEnrollInfo(undefined);
+    refreshStatus();
+  };
+
+  const handleSubmit = (event: FormEvent) => {
+    event.preventDefault();
+    if (!code) {
+      return;
+    }
+    if (action === 'disable') {
+      twoFactorDisable({ code }).then(() => {
+        toast.onShow({
+          msg: t('disabled'),
+          variant: 'success',
+        });
+        handleDone();
+      });
+      return;
+    }
+    const req = action === 'enable' ? twoFactorEnable : regenerateRecoveryCodes;
+    req({ code }).then((res) => {
+      // the recovery codes are only shown once
+      setRecoveryCodes(res.recovery_codes);
+      setCode('');
+    });
+  };
+
+  if (!status) {
+    return null;
+  }
+
+  if (recoveryCodes.length > 0) {
+    return (
+      <div className="mt-5">
+        <Form.Label>{t('title')}</Form.Label>
+        <p>{t('recovery_codes')}</p>
+        <pre className="bg-light p-3">{recoveryCodes.join('\n')}</pre>
+        <Button variant="primary" onClick={handleDone}>
+          {t('done')}
+        </Button>
+      </div>
+    );
+  }
+
+  return (
+    <div className="mt-5">
+      <Form.Label>{t('title')}</Form.Label>
+      {action ? (
+        <Form noValidate onSubmit={handleSubmit}>
+          {action === 'enable' && enrollInfo && (
+            <div className="mb-3">
+              <p>{t('enroll')}</p>
+              <img
+                className="d-block mb-2"
+                src={enrollInfo.qr_code}
+                alt={enrollInfo.provisioning_uri}
+              />
+              <code>{enrollInfo.secret}</code>
+            </div>
+          )}
+          <Form.Group controlId="two_factor_code" className="mb-3">
+            <Form.Label>{t('code.label')}</Form.Label>
+            <Form.Control
+              required
+              autoComplete="one-time-code"
+              maxLength={32}
+              value={code}
+              onChange={(e) => setCode(e.target.value)}
+            />
+          </Form.Group>
+          <div>
+            <Button type="submit" variant="primary" className="me-2">
+              {t('verify', { keyPrefix: 'btns' })}
+            </Button>
+            <Button variant="link" onClick={() => handleAction('')}>
+              {t('cancel', { keyPrefix: 'btns' })}
+            </Button>
+          </div>
+        </Form>
+      ) : (
+        <>
+          <p className="text-secondary">
+            {status.enabled
+              ? t('enabled', { count: status.recovery_codes_remaining })
+              : t('not_enabled')}
+          </p>
+          {status.enabled ? (
+            <>
+              <Button
+                variant="outline-secondary"
+                className="me-2"
+                onClick={() => handleAction('recovery_codes')}>
+                {t('regenerate_btn')}
+              </Button>
+              {!status.required && (
+                <Button
+                  variant="outline-danger"
+                  onClick={() => handleAction('disable')}>
+                  {t('disable_btn')}
+                </Button>
+              )}
+            </>
+          ) : (
+            <Button
+              variant="outline-secondary"
+              onClick={() => handleAction('enable')}>
+              {t('enable_btn')}
+            </Button>
+          )}
+        </>
+      )}
+    </div>
+  );
+};
+
+export default React.memo(Index);
@@ -3,6 +3,8 @@ import { useTranslation } from 'react-i18next';
 
 import ModifyEmail from './components/ModifyEmail';
 import ModifyPassword from './components/ModifyPass';
+import TwoFactor from './components/TwoFactor';
+import AccessTokens from './components/AccessTokens';
 
 const Index = () => {
   const { t } = useTranslation('translation', {
@@ -13,6 +15,8 @@ const Index = () => {
       <h3 className="mb-4">{t('heading')}</h3>
       <ModifyEmail />
       <ModifyPassword />
+      <TwoFactor />
+      <AccessTokens />
     </>
   );
 };
@@ -3,7 +3,12 @@ import { useTranslation } from 'react-i18next';
 
 import type { FormDataType } from '@/common/interface';
 import { useToast } from '@/hooks';
-import { setNotice, getLoggedUserInfo } from '@/services';
+import {
+  setNotice,
+  getLoggedUserInfo,
+  getNoticeConfig,
+  updateNoticeConfig,
+} from '@/services';
 import { SchemaForm, JSONSchema, UISchema, initFormData } from '@/components';
 
 const Index = () => {
@@ -11,6 +16,8 @@ const Index = () => {
   const { t } = useTranslation('translation', {
     keyPrefix: 'settings.notification',
   });
+  const frequencies = ['immediate', 'daily', 'weekly', 'off'];
+  const digestFrequencies = ['daily', 'weekly', 'off'];
   const schema: JSONSchema = {
     title: t('heading'),
     properties: {
@@ -20,35 +27,76 @@ const Index = () => {
         label: t('email.radio'),
         default: false,
       },
+      new_answer: {
+        type: 'string',
+        title: t('new_answer.label'),
+        enum: frequencies,
+        enumNames: frequencies.map((f) => t(`frequency.${f}`)),
+        default: 'immediate',
+      },
+      new_comment: {
+        type: 'string',
+        title: t('new_comment.label'),
+        enum: frequencies,
+        enumNames: frequencies.map((f) => t(`frequency.${f}`)),
+        default: 'immediate',
+      },
+      followed_tags: {
+        type: 'string',
+        title: t('followed_tags.label'),
+        description: t('followed_tags.text'),
+        enum: digestFrequencies,
+        enumNames: digestFrequencies.map((f) => t(`frequency.${f}`)),
+        default: 'off',
+      },
     },
   };
   const uiSchema: UISchema = {
     notice_switch: {
       'ui:widget': 'switch',
     },
+    new_answer: {
+      'ui:widget': 'select',
+    },
+    new_comment: {
+      'ui:widget': 'select',
+    },
+    followed_tags: {
+      'ui:widget': 'select',
+    },
   };
   const [formData, setFormData] = useState<FormDataType>(initFormData(schema));
 
   const getProfile = () => {
-    getLoggedUserInfo().then((res) => {
-      if (res) {
+    Promise.all([getLoggedUserInfo(), getNoticeConfig()]).then(
+      ([userInfo, noticeConfig]) => {
+        if (!userInfo || !noticeConfig) {
+          return;
+        }
+        const field = (value) => ({ value, isInvalid: false, errorMsg: '' });
         setFormData({
-          notice_switch: {
-            value: res.notice_status === 1,
-            isInvalid: false,
-            errorMsg: '',
-          },
+          notice_switch: field(userInfo.notice_status === 1),
+          new_answer: field(noticeConfig.new_answer),
+          new_comment: field(noticeConfig.new_comment),
+          followed_tags: field(noticeConfig.followed_tags),
         });
-      }
-    });
+      },
+    );
   };
 
   const handleSubmit = (event: FormEvent) => {
     event.preventDefault();
     event.stopPropagation();
-    setNotice({
-      notice_switch: formData.notice_switch.value,
-    }).then(() => {
+    Promise.all([
+      setNotice({
+        notice_switch: formData.notice_switch.value,
+      }),
+      updateNoticeConfig({
+        new_answer: formData.new_answer.value,
+        new_comment: formData.new_comment.value,
+        followed_tags: formData.followed_tags.value,
+      }),
+    ]).then(() => {
       toast.onShow({
         msg: t('update', { keyPrefix: 'toast' }),
         variant: 'success',
@@ -178,6 +178,10 @@ const routes: RouteNode[] = [
         path: 'users/account-activation',
         page: 'pages/Users/ActiveEmail',
       },
+      {
+        path: 'users/auth-landing',
+        page: 'pages/Users/AuthLanding',
+      },
       {
         path: 'users/account-activation/success',
         page: 'pages/Users/ActivationResult',
@@ -279,6 +283,14 @@ const routes: RouteNode[] = [
             path: 'users/:user_id',
             page: 'pages/Admin/UserOverview',
           },
+          {
+            path: 'roles',
+            page: 'pages/Admin/Roles',
+          },
+          {
+            path: 'badges',
+            page: 'pages/Admin/Badges',
+          },
           {
             path: 'smtp',
             page: 'pages/Admin/Smtp',
@@ -303,6 +315,14 @@ const routes: RouteNode[] = [
             path: 'login',
             page: 'pages/Admin/Login',
           },
+          {
+            path: 'webhooks',
+            page: 'pages/Admin/Webhooks',
+          },
+          {
+            path: 'privileges',
+            page: 'pages/Admin/Privileges',
+          },
         ],
       },
       // for review
@@ -0,0 +1,55 @@
+import useSWR from 'swr';
+import qs from 'qs';
+
+import request from '@/utils/request';
+import type * as Type from '@/common/interface';
+
+export const useQueryBadges = () => {
+  const { data, error, mutate } = useSWR<Type.BadgeItem[], Error>(
+    '/answer/admin/api/badges',
+    request.instance.get,
+  );
+  return {
+    data,
+    isLoading: !data && !error,
+    error,
+    mutate,
+  };
+};
+
+export const addBadge = (params: Type.BadgeReq) => {
+  return request.post('/answer/admin/api/badge', params);
+};
+
+export const updateBadge = (params: Type.BadgeReq) => {
+  return request.put('/answer/admin/api/badge', params);
+};
+
+export const useQueryUserBadges = (params: {
+  badge_id?: string;
+  username?: string;
+  page: number;
+  page_size: number;
+}) => {
+  const apiUrl = `/answer/admin/api/user-badges/page?${qs.stringify(params, {
+    skipNulls: true,
+  })}`;
+  const { data, error, mutate } = useSWR<
+    Type.ListResult<Type.UserBadgeItem>,
+    Error
+  >(apiUrl, request.instance.get);
+  return {
+    data,
+    isLoading: !data && !error,
+    error,
+    mutate,
+  };
+};
+
+export const awardBadge = (params: { badge_id: string; username: string }) => {
+  return request.post('/answer/admin/api/user-badge', params);
+};
+
+export const revokeBadge = (id: string) => {
+  return request.delete('/answer/admin/api/user-badge', { id });
+};
@@ -4,3 +4,6 @@ export * from './question';
 export * from './settings';
 export * from './users';
 export * from './dashboard';
+export * from './webhook';
+export * from './role';
+export * from './badge';
@@ -0,0 +1,49 @@
+import useSWR from 'swr';
+
+import request from '@/utils/request';
+import type * as Type from '@/common/interface';
+
+export const useQueryRoles = () => {
+  const { data, error, mutate } = useSWR<Type.RoleItem[], Error>(
+    '/answer/admin/api/roles',
+    request.instance.get,
+  );
+  return {
+    data,
+    isLoading: !data && !error,
+    error,
+    mutate,
+  };
+};
+
+export const addRole = (params: { name: string; description: string }) => {
+  return request.post<Type.RoleItem>('/answer/admin/api/role', params);
+};
+
+export const updateRole = (params: Type.RoleItem) => {
+  return request.put('/answer/admin/api/role', params);
+};
+
+export const deleteRole = (id: number) => {
+  return request.delete('/answer/admin/api/role', { id });
+};
+
+export const useQueryRolePowers = (roleID: number) => {
+  const apiUrl = roleID
+    ? `/answer/admin/api/role/powers?role_id=${roleID}`
+    : null;
+  const { data, error, mutate } = useSWR<Type.RolePowerItem[], Error>(
+    apiUrl,
+    request.instance.get,
+  );
+  return {
+    data,
+    isLoading: !data && !error,
+    error,
+    mutate,
+  };
+};
+
+export const updateRolePowers = (params: Type.RolePowerReq) => {
+  return request.put('/answer/admin/api/role/powers', params);
+};
@@ -126,3 +126,22 @@ export const getLoginSetting = () => {
 export const putLoginSetting = (params: Type.AdminSettingsLogin) => {
   return request.put('/answer/admin/api/siteinfo/login', params);
 };
+
+export const usePrivilegeSetting = () => {
+  const apiUrl = `/answer/admin/api/setting/privileges`;
+  const { data, error, mutate } = useSWR<Type.AdminSettingsPrivilege, Error>(
+    [apiUrl],
+    request.instance.get,
+  );
+  return {
+    data,
+    isLoading: !data && !error,
+    error,
+    mutate,
+  };
+};
+
+export const updatePrivilegeSetting = (params: Type.AdminSettingsPrivilege) => {
+  const apiUrl = `/answer/admin/api/setting/privileges`;
+  return request.put(apiUrl, params);
+};
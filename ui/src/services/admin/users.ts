@@ -44,3 +44,7 @@ export const updateUserPassword = (params: {
 }) => {
   return request.put('/answer/admin/api/user/password', params);
 };
+
+export const resetUserTwoFactor = (params: { user_id: string }) => {
+  return request.put('/answer/admin/api/user/2fa/reset', params);
+};
@@ -0,0 +1,58 @@
+import useSWR from 'swr';
+import qs from 'qs';
+
+import request from '@/utils/request';
+import type * as Type from '@/common/interface';
+
+export const useQueryWebhooks = () => {
+  const { data, error, mutate } = useSWR<Type.WebhookItem[], Error>(
+    '/answer/admin/api/webhooks',
+    request.instance.get,
+  );
+  return {
+    data,
+    isLoading: !data && !error,
+    error,
+    mutate,
+  };
+};
+
+export const getWebhookEvents = () => {
+  return request.get<string[]>('/answer/admin/api/webhook/events');
+};
+
+export const addWebhook = (params: Type.WebhookReq) => {
+  return request.post<Type.AddWebhookRes>('/answer/admin/api/webhook', params);
+};
+
+export const updateWebhook = (params: Type.WebhookReq) => {
+  return request.put('/answer/admin/api/webhook', params);
+};
+
+export const deleteWebhook = (id: string) => {
+  return request.delete('/answer/admin/api/webhook', { id });
+};
+
+export const useQueryWebhookDeliveries = (params: {
+  webhook_id: string;
+  page: number;
+  page_size: number;
+}) => {
+  const apiUrl = params.webhook_id
+    ? `/answer/admin/api/webhook/deliveries/page?${qs.stringify(params)}`
+    : null;
+  const { data, error, mutate } = useSWR<
+    Type.ListResult<Type.WebhookDeliveryItem>,
+    Error
+  >(apiUrl, request.instance.get);
+  return {
+    data,
+    isLoading: !data && !error,
+    error,
+    mutate,
+  };
+};
+
+export const redeliverWebhook = (id: string) => {
+  return request.post('/answer/admin/api/webhook/delivery/redeliver', { id });
+};
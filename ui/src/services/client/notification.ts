@@ -1,8 +1,12 @@
+import { useEffect } from 'react';
+
 import useSWR from 'swr';
 import qs from 'qs';
 
 import request from '@/utils/request';
+import Storage from '@/utils/storage';
 import type * as Type from '@/common/interface';
+import { LOGGED_TOKEN_STORAGE_KEY } from '@/common/constants';
 import { tryLoggedAndActivated } from '@/utils/guard';
 
 export const useQueryNotifications = (params) => {
@@ -29,16 +33,63 @@ export const readNotification = (id) => {
   });
 };
 
+/**
+ * Subscribe the notification stream, the handler of each event is called with the parsed data.
+ * The EventSource reconnects automatically, the returned function closes it.
+ */
+export const subscribeNotificationStream = (
+  params: Type.NotificationStreamReq,
+  handlers: Type.NotificationStreamHandlers,
+) => {
+  const token = Storage.get(LOGGED_TOKEN_STORAGE_KEY) || '';
+  const query = qs.stringify(
+    { ...params, Authorization: token },
+    { skipNulls: true },
+  );
+  const source = new EventSource(
+    `${
+      process.env.REACT_APP_API_URL || ''
+    }/answer/api/v1/notification/stream?${query}`,
+  );
+  Object.keys(handlers).forEach((event) => {
+    source.addEventListener(event, (evt) => {
+      handlers[event](JSON.parse((evt as MessageEvent).data));
+    });
+  });
+  return () => {
+    source.close();
+  };
+};
+
 export const useQueryNotificationStatus = () => {
   const apiUrl = '/answer/api/v1/notification/status';
+  const logged = tryLoggedAndActivated().ok;
+  // the status is pushed by the stream, polling is only the fallback
+  const streamSupported = typeof EventSource !== 'undefined';
 
-  return useSWR<Type.NotificationStatus>(
-    tryLoggedAndActivated().ok ? apiUrl : null,
+  const resp = useSWR<Type.NotificationStatus>(
+    logged ? apiUrl : null,
     request.instance.get,
     {
-      refreshInterval: 3000,
+      refreshInterval: streamSupported ? 0 : 3000,
     },
   );
+
+  useEffect(() => {
+    if (!logged || !streamSupported) {
+      return undefined;
+    }
+    return subscribeNotificationStream(
+      {},
+      {
+        red_dot: (data) => {
+          resp.mutate(data, false);
+        },
+      },
+    );
+  }, [logged]);
+
+  return resp;
 };
 
 export const clearNotificationStatus = (type) => {
@@ -52,3 +52,27 @@ export const useSimilarQuestion = (params: {
     error,
   };
 };
+
+export const useQuestionBounty = (questionId: string) => {
+  const apiUrl = `/answer/api/v1/question/bounty?question_id=${questionId}`;
+  const { data, error, mutate } = useSWR<Type.QuestionBounty | null, Error>(
+    questionId ? apiUrl : null,
+    request.instance.get,
+  );
+  return {
+    data,
+    isLoading: !data && !error,
+    error,
+    mutate,
+  };
+};
+
+export const startBounty = (params: Type.StartBountyReq) => {
+  return request.post('/answer/api/v1/question/bounty', params);
+};
+
+export const awardBounty = (answerId: string) => {
+  return request.post('/answer/api/v1/question/bounty/award', {
+    answer_id: answerId,
+  });
+};
@@ -92,6 +92,20 @@ export const login = (params: Type.LoginReqParams) => {
   );
 };
 
+export const twoFactorLogin = (params: Type.TwoFactorLoginReq) => {
+  return request.post<Type.TwoFactorLoginRes>(
+    '/answer/api/v1/user/login/2fa',
+    params,
+  );
+};
+
+export const twoFactorLoginEnroll = (params: { two_factor_token: string }) => {
+  return request.post<Type.TwoFactorEnrollRes>(
+    '/answer/api/v1/user/login/2fa/enroll',
+    params,
+  );
+};
+
 export const register = (params: Type.RegisterReqParams) => {
   return request.post<any>('/answer/api/v1/user/register/email', params);
 };
@@ -128,10 +142,62 @@ export const getLoggedUserInfo = () => {
   return request.get<Type.UserInfoRes>('/answer/api/v1/user/info');
 };
 
+export const exchangeExternalLoginCode = (params: { code: string }) => {
+  return request.post<{ access_token: string }>(
+    '/answer/api/v1/connector/token',
+    params,
+  );
+};
+
 export const modifyPassword = (params: Type.ModifyPasswordReq) => {
   return request.put('/answer/api/v1/user/password', params);
 };
 
+export const getTwoFactorStatus = () => {
+  return request.get<Type.TwoFactorStatusRes>('/answer/api/v1/user/2fa');
+};
+
+export const twoFactorEnroll = () => {
+  return request.post<Type.TwoFactorEnrollRes>(
+    '/answer/api/v1/user/2fa/enroll',
+  );
+};
+
+export const twoFactorEnable = (params: { code: string }) => {
+  return request.post<Type.TwoFactorRecoveryCodesRes>(
+    '/answer/api/v1/user/2fa/enable',
+    params,
+  );
+};
+
+export const twoFactorDisable = (params: { code: string }) => {
+  return request.delete('/answer/api/v1/user/2fa', params);
+};
+
+export const regenerateRecoveryCodes = (params: { code: string }) => {
+  return request.post<Type.TwoFactorRecoveryCodesRes>(
+    '/answer/api/v1/user/2fa/recovery-codes',
+    params,
+  );
+};
+
+export const getAccessTokens = () => {
+  return request.get<Type.AccessTokenItem[]>(
+    '/answer/api/v1/user/access-tokens',
+  );
+};
+
+export const addAccessToken = (params: Type.AddAccessTokenReq) => {
+  return request.post<{ id: string; token: string }>(
+    '/answer/api/v1/user/access-token',
+    params,
+  );
+};
+
+export const removeAccessToken = (params: { id: string }) => {
+  return request.delete('/answer/api/v1/user/access-token', params);
+};
+
 export const modifyUserInfo = (params: Type.ModifyUserReq) => {
   return request.put('/answer/api/v1/user/info', params);
 };
@@ -158,6 +224,14 @@ export const setNotice = (params: Type.SetNoticeReq) => {
   return request.post('/answer/api/v1/user/notice/set', params);
 };
 
+export const getNoticeConfig = () => {
+  return request.get<Type.NoticeConfig>('/answer/api/v1/user/notice/config');
+};
+
+export const updateNoticeConfig = (params: Type.NoticeConfig) => {
+  return request.put('/answer/api/v1/user/notice/config', params);
+};
+
 export const saveQuestion = (params: Type.QuestionParams) => {
   return request.post('/answer/api/v1/question', params);
 };
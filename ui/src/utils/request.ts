@@ -130,6 +130,16 @@ class Request {
           }
           return Promise.reject(false);
         }
+        if (status === 429) {
+          // too many requests, the rate limit is exceeded
+          if (msg) {
+            toastStore.getState().show({
+              msg,
+              variant: 'danger',
+            });
+          }
+          return Promise.reject(false);
+        }
         if (status >= 500) {
           console.error(
             `Request failed with status code ${status}, ${msg || ''}`,